@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var updateAPI = flag.Bool("update", false, "update API golden files")
+
+// stableAPIPackages are the packages covered by the v1 compatibility promise
+var stableAPIPackages = []string{"servicenow", "mcp"}
+
+// TestPublicAPIGolden asserts the exported API of the stable packages against golden
+// files, so an incompatible change can't ship in a v1 release unnoticed. Run
+// `make api` (go test . -run TestPublicAPIGolden -update) after an intentional addition.
+func TestPublicAPIGolden(t *testing.T) {
+	for _, name := range stableAPIPackages {
+		t.Run(name, func(t *testing.T) {
+			got, err := exportedAPI(filepath.Join("pkg", name))
+			if err != nil {
+				t.Fatalf("Failed to read the API of pkg/%s: %v", name, err)
+			}
+
+			path := filepath.Join("testdata", "api", name+".golden.txt")
+			if *updateAPI {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatalf("Failed to create golden directory: %v", err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run `make api` to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Exported API of pkg/%s differs from %s. Removing or changing an exported name breaks v1 users; "+
+					"if the change only adds to the API, run `make api`", name, path)
+			}
+		})
+	}
+}
+
+// exportedAPI lists the exported declarations of the package in dir, one per line
+// group in source-independent order, without bodies or comments. Files excluded by
+// build tags in a default build are left out.
+func exportedAPI(dir string) ([]byte, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	docs, err := doc.NewFromFiles(fset, files, "github.com/JeremyProffitt/go-mcp-servicenow/"+filepath.ToSlash(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	add := func(node interface{}) {
+		var buf bytes.Buffer
+		_ = (&printer.Config{Mode: printer.TabIndent | printer.UseSpaces, Tabwidth: 8}).Fprint(&buf, token.NewFileSet(), node)
+		lines = append(lines, buf.String())
+	}
+	values := func(values []*doc.Value) {
+		for _, v := range values {
+			for _, spec := range v.Decl.Specs {
+				if s, ok := spec.(*ast.ValueSpec); ok && v.Decl.Tok == token.VAR {
+					// A variable's initial value is not part of its API
+					s.Values = nil
+				}
+			}
+			add(v.Decl)
+		}
+	}
+	funcs := func(funcs []*doc.Func) {
+		for _, f := range funcs {
+			f.Decl.Body = nil
+			add(f.Decl)
+		}
+	}
+
+	values(docs.Consts)
+	values(docs.Vars)
+	funcs(docs.Funcs)
+	for _, typ := range docs.Types {
+		ast.Inspect(typ.Decl, func(node ast.Node) bool {
+			if st, ok := node.(*ast.StructType); ok {
+				// Unexported fields are not part of the API
+				st.Fields.Opening, st.Fields.Closing, st.Incomplete = token.NoPos, token.NoPos, false
+			}
+			return true
+		})
+		add(typ.Decl)
+		values(typ.Consts)
+		values(typ.Vars)
+		funcs(typ.Funcs)
+		funcs(typ.Methods)
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "\n\n") + "\n"), nil
+}
@@ -1,3 +1,3 @@
-module github.com/elastiflow/go-mcp-servicenow
+module github.com/JeremyProffitt/go-mcp-servicenow
 
 go 1.21
@@ -6,17 +6,21 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/logging"
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
-	"github.com/elastiflow/go-mcp-servicenow/pkg/servicenow"
-	"github.com/elastiflow/go-mcp-servicenow/pkg/tools"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/logging"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/tools"
 )
 
-var Version = "1.0.0"
+// Version is set by release builds (-ldflags "-X main.Version=v1.2.3"). Binaries
+// installed with go install report the module version instead.
+var Version = "dev"
 
 const AppName = "go-mcp-servicenow"
 
@@ -31,8 +35,10 @@ func main() {
 	port := flag.Int("port", 3000, "HTTP port (only used with -http)")
 	host := flag.String("host", "127.0.0.1", "HTTP host (only used with -http)")
 	readOnlyMode := flag.Bool("read-only", false, "Enable read-only mode (disables write operations)")
+	toolPackage := flag.String("tool-package", "", "Tool package to expose (full, requester, service_desk, ...)")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
+	Version = resolveVersion(Version)
 
 	// Handle version flag
 	if *showVersion {
@@ -45,11 +51,19 @@ func main() {
 	actualLogLevel, logLevelSource := resolveLogLevel(*logLevel)
 	actualReadOnly := resolveReadOnlyMode(*readOnlyMode)
 
+	rotation, err := resolveLogRotation()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid log rotation settings: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Initialize logger
 	logger, err := logging.NewLogger(logging.Config{
 		LogDir:          actualLogDir,
 		AppName:         AppName,
 		Level:           logging.ParseLevel(actualLogLevel),
+		Format:          logging.ParseFormat(os.Getenv("MCP_LOG_FORMAT")),
+		Rotation:        rotation,
 		AddAppSubfolder: os.Getenv("MCP_LOG_DIR") != "",
 	})
 	if err != nil {
@@ -85,9 +99,29 @@ func main() {
 	}
 	logger.Info("ServiceNow instance: %s", maskedInstance)
 	logger.Info("Authentication type: %s", snConfig.Auth.Type)
+	if snConfig.Impersonation {
+		logger.Info("Impersonation enabled: writes run as the user in the %s header (default user: %q)",
+			servicenow.HeaderImpersonateUser, snConfig.ImpersonateUser)
+	}
+
+	// Fault injection is only available in test builds (-tags faultinject)
+	clientOpts := []servicenow.ClientOption{servicenow.WithLogger(logger)}
+	faults, err := servicenow.LoadFaultConfigFromEnv()
+	if err != nil {
+		logger.Error("Invalid fault injection settings: %v", err)
+		os.Exit(1)
+	}
+	if faults.Enabled() {
+		if servicenow.FaultInjectionBuild {
+			logger.Warn("Fault injection enabled, requests will fail at random: %s", faults)
+			clientOpts = append(clientOpts, servicenow.WithFaultInjection(faults))
+		} else {
+			logger.Warn("Ignoring SERVICENOW_FAULT_* settings: this build does not support fault injection (build with -tags faultinject)")
+		}
+	}
 
 	// Create ServiceNow client
-	client, err := servicenow.NewClient(snConfig)
+	client, err := servicenow.NewClient(snConfig, clientOpts...)
 	if err != nil {
 		logger.Error("Failed to create ServiceNow client: %v", err)
 		os.Exit(1)
@@ -95,19 +129,192 @@ func main() {
 
 	// Create MCP server
 	server := mcp.NewServer(AppName, Version)
+	if prefix := os.Getenv("MCP_TOOL_PREFIX"); prefix != "" {
+		server.SetToolPrefix(prefix)
+		logger.Info("Tool name prefix: %s", prefix)
+	}
+
+	if resolveBoolEnv("MCP_STRICT_LIFECYCLE") {
+		server.SetStrictLifecycle(true)
+		logger.Info("Strict MCP lifecycle enforcement enabled")
+	}
+	if resolveBoolEnv("MCP_DEBUG_ENDPOINTS") {
+		server.SetDebugEndpoints(true)
+		logger.Info("Debug endpoints enabled: /debug/pprof/ and /debug/vars (loopback or admin token only)")
+	}
+	if idle := os.Getenv("MCP_SESSION_IDLE_TIMEOUT"); idle != "" {
+		timeout, err := time.ParseDuration(idle)
+		if err != nil {
+			logger.Error("Invalid MCP_SESSION_IDLE_TIMEOUT %q: %v", idle, err)
+			os.Exit(1)
+		}
+		server.SetSessionIdleTimeout(timeout)
+	}
+
+	if spec := os.Getenv("MCP_WRITE_QUOTAS"); spec != "" {
+		quotas, err := mcp.ParseQuotaRules(spec)
+		if err != nil {
+			logger.Error("Invalid MCP_WRITE_QUOTAS: %v", err)
+			os.Exit(1)
+		}
+		server.SetQuotas(quotas)
+		logger.Info("Write quotas: %v", quotas)
+	}
 
 	// Set up telemetry callbacks
-	server.SetToolCallCallback(func(name string, args map[string]interface{}, duration time.Duration, success bool) {
-		logger.ToolCall(name, args, duration, success)
+	server.SetToolCallCallback(func(call mcp.ToolCallInfo) {
+		logger.ToolCall(logging.ToolCallEntry{
+			Name:      call.Name,
+			Duration:  call.Duration,
+			Success:   call.Success,
+			RequestID: call.RequestID,
+			SysIDs:    tools.TouchedSysIDs(call.Arguments, call.Result),
+		})
 	})
 	server.SetErrorCallback(func(err error, context string) {
 		logger.Error("Error in %s: %v", context, err)
 	})
+	server.SetAliasCallback(func(alias, target string) {
+		logger.Warn("Deprecated tool alias called: %s (use %s)", alias, target)
+	})
+	server.SetReadOnlyCallback(func(readOnly bool, source string) {
+		logger.Warn("Runtime read-only mode set to %v by %s", readOnly, source)
+	})
 
 	// Register tools
 	registry := tools.NewRegistry(client, logger, actualReadOnly)
+	registry.SetHTTPMode(*httpMode)
+	if resolveBoolEnv("MCP_DIAGNOSTIC_TOOLS") {
+		registry.EnableDiagnosticTools()
+		logger.Info("Diagnostic tools enabled (echo, sleep, error_test)")
+	}
+	if resolveBoolEnv("ALLOW_SCRIPT_EXECUTION") {
+		if err := registry.EnableScriptExecution(os.Getenv("SCRIPT_EXECUTION_API")); err != nil {
+			logger.Error("Invalid SCRIPT_EXECUTION_API: %v", err)
+			os.Exit(1)
+		}
+		if actualReadOnly {
+			logger.Warn("ALLOW_SCRIPT_EXECUTION is ignored in read-only mode")
+		} else {
+			logger.Warn("Background script execution enabled via %s", os.Getenv("SCRIPT_EXECUTION_API"))
+		}
+	}
+	if resolveBoolEnv("ALLOW_DESTRUCTIVE") {
+		registry.EnableDestructiveTools()
+		if actualReadOnly {
+			logger.Warn("ALLOW_DESTRUCTIVE is ignored in read-only mode")
+		} else {
+			logger.Warn("Destructive tools enabled: records can be closed, canceled, and deleted")
+		}
+	}
+	if pkg, source := resolveToolPackage(*toolPackage); pkg != "" {
+		if err := registry.SetToolPackage(pkg); err != nil {
+			logger.Warn("Ignoring tool package from %s: %v", source, err)
+		}
+	}
+	if enable, disable := os.Getenv("TOOLS_ENABLE"), os.Getenv("TOOLS_DISABLE"); enable != "" || disable != "" {
+		registry.SetToolSelection(strings.Split(enable, ","), strings.Split(disable, ","))
+		logger.Info("Tool selection: enable=%q disable=%q", enable, disable)
+	}
+	if tables, ok := os.LookupEnv("MCP_DELETE_PROTECTED_TABLES"); ok {
+		if err := registry.SetDeleteProtectedTables(strings.Split(tables, ",")); err != nil {
+			logger.Warn("Ignoring MCP_DELETE_PROTECTED_TABLES: %v", err)
+		}
+	}
+	if path := os.Getenv("MCP_SESSION_INDEX"); path != "" {
+		if err := registry.SetSessionIndex(path); err != nil {
+			logger.Error("Failed to open session index %s: %v", path, err)
+			os.Exit(1)
+		}
+		logger.Info("Session change index: %s", path)
+	}
+	if table := os.Getenv("MCP_ACTIVITY_TABLE"); table != "" {
+		if err := registry.SetActivityTable(table); err != nil {
+			logger.Error("Invalid MCP_ACTIVITY_TABLE: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Write tool activity recorded in %s", table)
+	}
+	if timeout, overrides := os.Getenv("MCP_TOOL_TIMEOUT"), os.Getenv("MCP_TOOL_TIMEOUTS"); timeout != "" || overrides != "" {
+		defaultTimeout := time.Duration(0)
+		if timeout != "" {
+			if defaultTimeout, err = time.ParseDuration(timeout); err != nil {
+				logger.Error("Invalid MCP_TOOL_TIMEOUT %q: %v", timeout, err)
+				os.Exit(1)
+			}
+		}
+		if err := registry.SetToolTimeouts(defaultTimeout, strings.Split(overrides, ",")); err != nil {
+			logger.Error("Invalid tool timeouts: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Tool timeouts: default=%s overrides=%q", defaultTimeout, overrides)
+	}
+	if max, overrides := os.Getenv("MCP_MAX_RESPONSE_BYTES"), os.Getenv("MCP_MAX_RESPONSE_SIZES"); max != "" || overrides != "" {
+		defaultMax := tools.DefaultMaxResponseBytes
+		if max != "" {
+			if defaultMax, err = strconv.Atoi(max); err != nil {
+				logger.Error("Invalid MCP_MAX_RESPONSE_BYTES %q: %v", max, err)
+				os.Exit(1)
+			}
+		}
+		if err := registry.SetResponseLimits(defaultMax, strings.Split(overrides, ",")); err != nil {
+			logger.Error("Invalid response limits: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Response limits: default=%d bytes overrides=%q", defaultMax, overrides)
+	}
+	if max, defaults, maximums := os.Getenv("MCP_MAX_LIMIT"), os.Getenv("MCP_DEFAULT_LIMITS"), os.Getenv("MCP_MAX_LIMITS"); max != "" || defaults != "" || maximums != "" {
+		maxLimit := 0
+		if max != "" {
+			if maxLimit, err = strconv.Atoi(max); err != nil {
+				logger.Error("Invalid MCP_MAX_LIMIT %q: %v", max, err)
+				os.Exit(1)
+			}
+		}
+		if err := registry.SetRecordLimits(maxLimit, strings.Split(defaults, ","), strings.Split(maximums, ",")); err != nil {
+			logger.Error("Invalid record limits: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Record limits: cap=%d defaults=%q maximums=%q", maxLimit, defaults, maximums)
+	}
+	if max := os.Getenv("MCP_AUTO_PAGINATE_MAX"); max != "" {
+		n, err := strconv.Atoi(max)
+		if err == nil {
+			err = registry.SetAutoPaginateMax(n)
+		}
+		if err != nil {
+			logger.Warn("Ignoring MCP_AUTO_PAGINATE_MAX %q: %v", max, err)
+		}
+	}
+	if mode := os.Getenv("MCP_UNKNOWN_ARGUMENTS"); mode != "" {
+		if err := registry.SetUnknownArgumentMode(strings.ToLower(strings.TrimSpace(mode))); err != nil {
+			logger.Warn("Ignoring MCP_UNKNOWN_ARGUMENTS: %v", err)
+		}
+	}
+	if labels := os.Getenv("MCP_STATE_LABELS"); labels != "" {
+		if err := registry.SetStateLabels(strings.Split(labels, ",")); err != nil {
+			logger.Warn("Ignoring MCP_STATE_LABELS: %v", err)
+		}
+	}
 	toolCount := registry.RegisterAll(server)
-	logger.Info("Registered %d tools (read-only mode: %v)", toolCount, actualReadOnly)
+	logger.Info("Registered %d tools (tool package: %s, read-only mode: %v)", toolCount, registry.ToolPackage(), actualReadOnly)
+
+	// Register resources
+	if tables := os.Getenv("MCP_DIGEST_TABLES"); tables != "" {
+		if err := registry.SetDigestTables(strings.Split(tables, ",")); err != nil {
+			logger.Warn("Ignoring MCP_DIGEST_TABLES: %v", err)
+		}
+	}
+	if pinned := os.Getenv("MCP_PINNED_RESOURCES"); pinned != "" {
+		if err := registry.SetPinnedResources(strings.Split(pinned, ",")); err != nil {
+			logger.Warn("Ignoring MCP_PINNED_RESOURCES: %v", err)
+		}
+	}
+	if groups := os.Getenv("MCP_QUEUE_RESOURCES"); groups != "" {
+		registry.SetQueueResources(strings.Split(groups, ","))
+	}
+	registry.RegisterResources(server)
+	registry.RegisterPrompts(server)
 
 	// Set up graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -122,6 +329,7 @@ func main() {
 			runErr = server.RunHTTP(addr)
 		} else {
 			logger.Info("Starting stdio server")
+			watchSnapshotSignal(logger)
 			runErr = server.Run()
 		}
 		if runErr != nil {
@@ -135,6 +343,41 @@ func main() {
 	logger.LogShutdown(fmt.Sprintf("received signal: %v", sig))
 }
 
+// watchSnapshotSignal writes a goroutine and heap snapshot on each SIGUSR1, to
+// MCP_SNAPSHOT_DIR or the temporary directory, for profiling stdio sessions
+func watchSnapshotSignal(logger *logging.Logger) {
+	snapshots := make(chan os.Signal, 1)
+	if !mcp.NotifySnapshotSignal(snapshots) {
+		return
+	}
+	dir := os.Getenv("MCP_SNAPSHOT_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	go func() {
+		for range snapshots {
+			paths, err := mcp.WriteRuntimeSnapshot(dir)
+			if err != nil {
+				logger.Error("Failed to write runtime snapshot: %v", err)
+				continue
+			}
+			logger.Info("Wrote runtime snapshot: %s", strings.Join(paths, ", "))
+		}
+	}()
+}
+
+// resolveVersion returns the version set at build time, or the module version of a
+// binary built by go install from a tagged release
+func resolveVersion(version string) string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
 func resolveLogDir(flagValue string) (string, logging.ConfigSource) {
 	if flagValue != "" {
 		return flagValue, logging.SourceFlag
@@ -155,10 +398,49 @@ func resolveLogLevel(flagValue string) (string, logging.ConfigSource) {
 	return "info", logging.SourceDefault
 }
 
+func resolveToolPackage(flagValue string) (string, logging.ConfigSource) {
+	if flagValue != "" {
+		return flagValue, logging.SourceFlag
+	}
+	if envValue := os.Getenv("MCP_TOOL_PACKAGE"); envValue != "" {
+		return envValue, logging.SourceEnvironment
+	}
+	return "", logging.SourceDefault
+}
+
+// resolveLogRotation applies the MCP_LOG_MAX_SIZE_MB, MCP_LOG_MAX_BACKUPS,
+// MCP_LOG_MAX_AGE_DAYS, and MCP_LOG_COMPRESS overrides to the default rotation
+func resolveLogRotation() (logging.RotationConfig, error) {
+	rotation := logging.DefaultRotation
+	for name, target := range map[string]*int{
+		"MCP_LOG_MAX_SIZE_MB":  &rotation.MaxSizeMB,
+		"MCP_LOG_MAX_BACKUPS":  &rotation.MaxBackups,
+		"MCP_LOG_MAX_AGE_DAYS": &rotation.MaxAgeDays,
+	} {
+		envValue := os.Getenv(name)
+		if envValue == "" {
+			continue
+		}
+		n, err := strconv.Atoi(envValue)
+		if err != nil || n < 0 {
+			return rotation, fmt.Errorf("%s must be a non-negative integer, got %q", name, envValue)
+		}
+		*target = n
+	}
+	if os.Getenv("MCP_LOG_COMPRESS") != "" {
+		rotation.Compress = resolveBoolEnv("MCP_LOG_COMPRESS")
+	}
+	return rotation, nil
+}
+
 func resolveReadOnlyMode(flagValue bool) bool {
 	if flagValue {
 		return true
 	}
-	envValue := strings.ToLower(os.Getenv("READ_ONLY_MODE"))
+	return resolveBoolEnv("READ_ONLY_MODE")
+}
+
+func resolveBoolEnv(name string) bool {
+	envValue := strings.ToLower(os.Getenv(name))
 	return envValue == "true" || envValue == "1"
 }
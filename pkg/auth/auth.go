@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"os"
 	"strings"
 )
@@ -9,6 +10,9 @@ import (
 // AuthHeaderName is the HTTP header used for MCP authentication
 const AuthHeaderName = "X-MCP-Auth-Token"
 
+// AdminHeaderName is the HTTP header used for admin endpoint authentication
+const AdminHeaderName = "X-MCP-Admin-Token"
+
 // ValidateToken validates the provided authentication token.
 func ValidateToken(token string) bool {
 	return token != ""
@@ -24,6 +28,26 @@ func IsAuthEnabled() bool {
 	return GetExpectedToken() != ""
 }
 
+// GetAdminToken returns the admin token from environment variable.
+func GetAdminToken() string {
+	return os.Getenv("MCP_ADMIN_TOKEN")
+}
+
+// IsAdminEnabled returns true if admin endpoints are enabled (admin token is configured)
+func IsAdminEnabled() bool {
+	return GetAdminToken() != ""
+}
+
+// ValidateAdminToken validates the provided token against the admin token.
+// Always false when no admin token is configured.
+func ValidateAdminToken(providedToken string) bool {
+	adminToken := GetAdminToken()
+	if adminToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(providedToken), []byte(adminToken)) == 1
+}
+
 // ValidateAgainstExpected validates the provided token against the expected token.
 func ValidateAgainstExpected(providedToken string) bool {
 	expectedToken := GetExpectedToken()
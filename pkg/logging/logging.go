@@ -2,8 +2,10 @@ package logging
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -53,6 +55,28 @@ func ParseLevel(s string) Level {
 	}
 }
 
+// Format is the log line format
+type Format string
+
+const (
+	// FormatText writes "[timestamp] [LEVEL] message" lines
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per line in the log/slog JSON layout
+	// (time, level, msg, and fields), for ingestion by Splunk, ELK, and similar
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a string into a Format, defaulting to text
+func ParseFormat(s string) Format {
+	if strings.EqualFold(strings.TrimSpace(s), string(FormatJSON)) {
+		return FormatJSON
+	}
+	return FormatText
+}
+
+// slogLevelAudit is the slog level of audit entries, above ERROR
+const slogLevelAudit = slog.Level(12)
+
 // ConfigSource indicates where a configuration value came from
 type ConfigSource string
 
@@ -73,6 +97,8 @@ type Config struct {
 	LogDir          string
 	AppName         string
 	Level           Level
+	Format          Format
+	Rotation        RotationConfig
 	AddAppSubfolder bool
 }
 
@@ -90,12 +116,22 @@ type StartupInfo struct {
 	StartTime   time.Time
 }
 
+// ToolCallEntry describes a completed tool call
+type ToolCallEntry struct {
+	Name      string
+	Duration  time.Duration
+	Success   bool
+	RequestID interface{}
+	SysIDs    []string
+}
+
 // Logger provides structured logging
 type Logger struct {
 	config    Config
-	file      *os.File
+	file      *rotatingFile
 	mu        sync.Mutex
 	startTime time.Time
+	json      *slog.Logger
 }
 
 // NewLogger creates a new logger
@@ -116,13 +152,25 @@ func NewLogger(config Config) (*Logger, error) {
 		}
 
 		logFile := filepath.Join(logDir, fmt.Sprintf("%s.log", config.AppName))
-		file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		file, err := openRotatingFile(logFile, config.Rotation)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open log file: %w", err)
 		}
 		logger.file = file
 	}
 
+	if config.Format == FormatJSON {
+		logger.json = slog.New(slog.NewJSONHandler(lineWriter{logger}, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.LevelKey && a.Value.Any() == slogLevelAudit {
+					a.Value = slog.StringValue("AUDIT")
+				}
+				return a
+			},
+		}))
+	}
+
 	return logger, nil
 }
 
@@ -142,20 +190,51 @@ func (l *Logger) log(level Level, format string, args ...interface{}) {
 	if level < l.config.Level {
 		return
 	}
+	l.write(level.slogLevel(), level.String(), fmt.Sprintf(format, args...))
+}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
+// slogLevel returns the slog level corresponding to l
+func (l Level) slogLevel() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// write writes a log entry with the given level label. In JSON format the fields
+// are written as attributes; in text format they are left out.
+func (l *Logger) write(level slog.Level, label, msg string, fields ...slog.Attr) {
+	if l.json != nil {
+		l.json.LogAttrs(context.Background(), level, msg, fields...)
+		return
+	}
 
-	msg := fmt.Sprintf(format, args...)
 	timestamp := time.Now().Format("2006-01-02T15:04:05.000Z07:00")
-	logLine := fmt.Sprintf("[%s] [%s] %s\n", timestamp, level.String(), msg)
+	_, _ = lineWriter{l}.Write([]byte(fmt.Sprintf("[%s] [%s] %s\n", timestamp, label, msg)))
+}
 
-	if l.file != nil {
-		_, _ = l.file.WriteString(logLine)
+// lineWriter writes complete log lines to the log file and stderr
+type lineWriter struct {
+	logger *Logger
+}
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w.logger.mu.Lock()
+	defer w.logger.mu.Unlock()
+
+	if w.logger.file != nil {
+		_, _ = w.logger.file.Write(p)
 	}
 
 	// Also write to stderr for debugging
-	fmt.Fprint(os.Stderr, logLine)
+	_, _ = os.Stderr.Write(p)
+	return len(p), nil
 }
 
 // Debug logs a debug message
@@ -178,13 +257,38 @@ func (l *Logger) Error(format string, args ...interface{}) {
 	l.log(LevelError, format, args...)
 }
 
-// ToolCall logs a tool call
-func (l *Logger) ToolCall(name string, args map[string]interface{}, duration time.Duration, success bool) {
+// Audit logs an audit trail entry. Audit entries are written regardless of the log level.
+func (l *Logger) Audit(format string, args ...interface{}) {
+	l.write(slogLevelAudit, "AUDIT", fmt.Sprintf(format, args...))
+}
+
+// ToolCall logs a tool call. In JSON format the tool name, duration, status, request
+// ID, and sys_ids touched are written as fields.
+func (l *Logger) ToolCall(call ToolCallEntry) {
+	if LevelInfo < l.config.Level {
+		return
+	}
 	status := "success"
-	if !success {
+	if !call.Success {
 		status = "failure"
 	}
-	l.Info("Tool call: %s (duration: %v, status: %s)", name, duration, status)
+	if l.json == nil {
+		l.Info("Tool call: %s (duration: %v, status: %s)", call.Name, call.Duration, status)
+		return
+	}
+
+	fields := []slog.Attr{
+		slog.String("tool", call.Name),
+		slog.Float64("duration_ms", float64(call.Duration.Microseconds())/1000),
+		slog.String("status", status),
+	}
+	if call.RequestID != nil {
+		fields = append(fields, slog.Any("request_id", call.RequestID))
+	}
+	if len(call.SysIDs) > 0 {
+		fields = append(fields, slog.Any("sys_ids", call.SysIDs))
+	}
+	l.write(slog.LevelInfo, LevelInfo.String(), "Tool call", fields...)
 }
 
 // LogStartup logs startup information
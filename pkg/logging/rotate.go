@@ -0,0 +1,212 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeLayout is the timestamp in rotated log file names
+// (e.g., go-mcp-servicenow-2024-12-15T14-30-00.000.log)
+const backupTimeLayout = "2006-01-02T15-04-05.000"
+
+// RotationConfig controls log file rotation. Zero values disable the corresponding limit.
+type RotationConfig struct {
+	// MaxSizeMB is the size in megabytes at which the log file is rotated
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int
+	// MaxAgeDays is the number of days rotated files are kept
+	MaxAgeDays int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// DefaultRotation is the rotation used when none is configured
+var DefaultRotation = RotationConfig{MaxSizeMB: 100, MaxBackups: 5, MaxAgeDays: 30, Compress: true}
+
+// rotatingFile is a log file that is rotated when it reaches the maximum size.
+// Rotated files are compressed and pruned in the background.
+type rotatingFile struct {
+	path     string
+	config   RotationConfig
+	file     *os.File
+	size     int64
+	pruning  sync.Mutex
+	inFlight sync.WaitGroup
+}
+
+// openRotatingFile opens (appending to) the log file at path and prunes old backups
+func openRotatingFile(path string, config RotationConfig) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, config: config}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	f.inFlight.Add(1)
+	go f.compressAndPrune()
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write writes p, rotating first if p would take the file past the maximum size.
+// Callers serialize writes.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	maxSize := int64(f.config.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > maxSize {
+		if err := f.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to rotate log file: %v\n", err)
+		}
+	}
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate renames the current file to a timestamped backup and starts a new one
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	renameErr := os.Rename(f.path, f.backupName(time.Now()))
+	if err := f.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		// Keep appending to the current file rather than losing lines
+		return renameErr
+	}
+	f.inFlight.Add(1)
+	go f.compressAndPrune()
+	return nil
+}
+
+// backupName returns the path of a backup rotated at t
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(f.path, ext), t.Format(backupTimeLayout), ext)
+}
+
+// backup is a rotated log file
+type backup struct {
+	path    string
+	rotated time.Time
+}
+
+// backups returns the rotated log files, newest first
+func (f *rotatingFile) backups() ([]backup, error) {
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+		rotated, err := time.ParseInLocation(backupTimeLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		result = append(result, backup{path: filepath.Join(filepath.Dir(f.path), name), rotated: rotated})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].rotated.After(result[j].rotated) })
+	return result, nil
+}
+
+// compressAndPrune removes backups beyond the maximum count or age and compresses the rest
+func (f *rotatingFile) compressAndPrune() {
+	defer f.inFlight.Done()
+	f.pruning.Lock()
+	defer f.pruning.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list rotated log files: %v\n", err)
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -f.config.MaxAgeDays)
+	for i, b := range backups {
+		if (f.config.MaxBackups > 0 && i >= f.config.MaxBackups) || (f.config.MaxAgeDays > 0 && b.rotated.Before(cutoff)) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Failed to remove rotated log file: %v\n", err)
+			}
+			continue
+		}
+		if f.config.Compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := compressFile(b.path); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to compress rotated log file: %v\n", err)
+			}
+		}
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
+
+// Close closes the file after background compression and pruning finish
+func (f *rotatingFile) Close() error {
+	f.inFlight.Wait()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
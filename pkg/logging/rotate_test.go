@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRotatingFile tests that the log file is rotated at the maximum size and old backups are pruned and compressed
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	// An expired backup and a recent one left by a previous run
+	old := filepath.Join(dir, "app-"+time.Now().AddDate(0, 0, -10).Format(backupTimeLayout)+".log")
+	recent := filepath.Join(dir, "app-"+time.Now().Add(-time.Hour).Format(backupTimeLayout)+".log")
+	for _, backup := range []string{old, recent} {
+		if err := os.WriteFile(backup, []byte("previous run\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := openRotatingFile(path, RotationConfig{MaxSizeMB: 1, MaxBackups: 2, MaxAgeDays: 7, Compress: true})
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	line := []byte(strings.Repeat("x", 1023) + "\n")
+	for i := 0; i < 1500; i++ {
+		if _, err := f.Write(line); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != 476*1024 {
+		t.Errorf("Expected the current file to hold the lines written after rotation, got %v (%v)", info, err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("Expected the expired backup to be removed, got %v", err)
+	}
+	if _, err := os.Stat(recent + ".gz"); err != nil {
+		t.Errorf("Expected the recent backup to be compressed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	var rotated []string
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			rotated = append(rotated, entry.Name())
+		}
+	}
+	if len(rotated) != 2 || !strings.HasSuffix(rotated[0], ".log.gz") || !strings.HasSuffix(rotated[1], ".log.gz") {
+		t.Errorf("Expected two compressed backups, got %v", rotated)
+	}
+}
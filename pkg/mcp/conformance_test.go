@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// conformanceFixture is a recorded exchange sequence from testdata/conformance. Each
+// exchange sends "request" (any JSON value) or "raw" (text that may not be valid JSON)
+// and compares the response against "response" (null when no response is expected).
+type conformanceFixture struct {
+	Description string `json:"description"`
+	Exchanges   []struct {
+		Request  json.RawMessage `json:"request"`
+		Raw      string          `json:"raw"`
+		Response json.RawMessage `json:"response"`
+	} `json:"exchanges"`
+}
+
+// conformanceResources serves a single static text resource
+type conformanceResources struct{}
+
+func (conformanceResources) ListResources() []Resource {
+	return []Resource{{URI: "test://hello", Name: "Hello", MimeType: "text/plain"}}
+}
+
+func (conformanceResources) ReadResource(uri string) (*ReadResourceResult, error) {
+	if uri != "test://hello" {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+	return &ReadResourceResult{Contents: []ResourceContent{{URI: uri, MimeType: "text/plain", Text: "Hello"}}}, nil
+}
+
+// conformancePrompts serves a single prompt with one required argument
+type conformancePrompts struct{}
+
+func (conformancePrompts) ListPrompts() []Prompt {
+	return []Prompt{{Name: "greet", Arguments: []PromptArgument{{Name: "name", Required: true}}}}
+}
+
+func (conformancePrompts) GetPrompt(name string, arguments map[string]interface{}) (*GetPromptResult, error) {
+	if name != "greet" {
+		return nil, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	who, _ := arguments["name"].(string)
+	return &GetPromptResult{Messages: []PromptMessage{{Role: "user", Content: ContentItem{Type: "text", Text: "Say hello to " + who}}}}, nil
+}
+
+// TestConformance replays the JSON fixtures in testdata/conformance against a server
+// with a tool, a resource, and a prompt, checking responses follow the MCP and
+// JSON-RPC 2.0 specifications. Expected responses are matched as subsets: objects
+// may have extra keys, "<any>" matches any present value, and "<absent>" requires
+// the key to be missing.
+func TestConformance(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "conformance", "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("No conformance fixtures found: %v", err)
+	}
+
+	for _, path := range paths {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".json"), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+			var fixture conformanceFixture
+			if err := json.Unmarshal(data, &fixture); err != nil {
+				t.Fatalf("Invalid fixture: %v", err)
+			}
+
+			s := newEchoServer(t, "")
+			s.RegisterResourceProvider(conformanceResources{})
+			s.RegisterPromptProvider(conformancePrompts{})
+
+			for i, exchange := range fixture.Exchanges {
+				payload := []byte(exchange.Raw)
+				if exchange.Raw == "" {
+					payload = exchange.Request
+				}
+
+				var actual interface{}
+				if response := s.handlePayloadWithContext(context.Background(), payload); response != nil {
+					encoded, _ := json.Marshal(response)
+					_ = json.Unmarshal(encoded, &actual)
+				}
+
+				var expected interface{}
+				_ = json.Unmarshal(exchange.Response, &expected)
+				if problems := conformanceDiff("response", expected, actual); len(problems) > 0 {
+					encoded, _ := json.Marshal(actual)
+					t.Errorf("%s, exchange %d (%s):\n  %s\n  got %s", fixture.Description, i+1, payload,
+						strings.Join(problems, "\n  "), encoded)
+				}
+			}
+		})
+	}
+}
+
+// conformanceDiff lists the ways actual fails to match the expected subset
+func conformanceDiff(path string, expected, actual interface{}) []string {
+	if expected == "<any>" {
+		return nil
+	}
+
+	switch want := expected.(type) {
+	case map[string]interface{}:
+		got, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %v", path, actual)}
+		}
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var problems []string
+		for _, key := range keys {
+			value, present := got[key]
+			switch {
+			case want[key] == "<absent>":
+				if present {
+					problems = append(problems, fmt.Sprintf("%s.%s: expected no value, got %v", path, key, value))
+				}
+			case !present:
+				problems = append(problems, fmt.Sprintf("%s.%s: missing", path, key))
+			default:
+				problems = append(problems, conformanceDiff(path+"."+key, want[key], value)...)
+			}
+		}
+		return problems
+	case []interface{}:
+		got, ok := actual.([]interface{})
+		if !ok || len(got) != len(want) {
+			return []string{fmt.Sprintf("%s: expected %d elements, got %v", path, len(want), actual)}
+		}
+		var problems []string
+		for i := range want {
+			problems = append(problems, conformanceDiff(fmt.Sprintf("%s[%d]", path, i), want[i], got[i])...)
+		}
+		return problems
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			return []string{fmt.Sprintf("%s: expected %v, got %v", path, expected, actual)}
+		}
+		return nil
+	}
+}
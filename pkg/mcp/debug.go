@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/auth"
+)
+
+// publishDebugVars publishes the runtime variables served by /debug/vars once per process
+var publishDebugVars sync.Once
+
+// SetDebugEndpoints serves the pprof profiles (/debug/pprof/) and expvar variables
+// (/debug/vars) in HTTP mode. They answer requests from loopback addresses, and from
+// elsewhere only with the admin token (MCP_ADMIN_TOKEN).
+func (s *Server) SetDebugEndpoints(enabled bool) {
+	s.debugEndpoints = enabled
+}
+
+// debugHandler serves the pprof and expvar endpoints to allowed callers
+func (s *Server) debugHandler() http.Handler {
+	publishDebugVars.Do(func() {
+		expvar.Publish("goroutines", expvar.Func(func() interface{} { return runtime.NumGoroutine() }))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !debugAllowed(r) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": "Unauthorized: debug endpoints answer loopback requests or the admin token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// debugAllowed reports whether a request may read the debug endpoints: it comes from a
+// loopback address, or carries the admin token
+func debugAllowed(r *http.Request) bool {
+	if auth.IsAdminEnabled() && auth.ValidateAdminToken(r.Header.Get(auth.AdminHeaderName)) {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// WriteRuntimeSnapshot writes the stacks of all goroutines and a heap profile to dir,
+// named with the current time, and returns the paths written. Read the heap profile
+// with `go tool pprof`.
+func WriteRuntimeSnapshot(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	stamp := time.Now().Format("20060102-150405")
+
+	var paths []string
+	write := func(name string, profile func(f *os.File) error) error {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer f.Close()
+		if err := profile(f); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+		return nil
+	}
+
+	if err := write(fmt.Sprintf("goroutines-%s.txt", stamp), func(f *os.File) error {
+		return runtimepprof.Lookup("goroutine").WriteTo(f, 2)
+	}); err != nil {
+		return paths, err
+	}
+	err := write(fmt.Sprintf("heap-%s.pprof", stamp), func(f *os.File) error {
+		runtime.GC()
+		return runtimepprof.WriteHeapProfile(f)
+	})
+	return paths, err
+}
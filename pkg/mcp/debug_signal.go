@@ -0,0 +1,16 @@
+//go:build !windows
+
+package mcp
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifySnapshotSignal relays SIGUSR1, which asks for a runtime snapshot, to c. It
+// reports whether the platform has the signal.
+func NotifySnapshotSignal(c chan<- os.Signal) bool {
+	signal.Notify(c, syscall.SIGUSR1)
+	return true
+}
@@ -0,0 +1,8 @@
+package mcp
+
+import "os"
+
+// NotifySnapshotSignal does nothing on Windows, which has no SIGUSR1
+func NotifySnapshotSignal(c chan<- os.Signal) bool {
+	return false
+}
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+// FuzzTrimLine checks trimLine matches strings.Trim over the JSON-RPC whitespace set
+func FuzzTrimLine(f *testing.F) {
+	f.Add(`{"jsonrpc":"2.0"}` + "\r\n")
+	f.Add(" \t\n")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if got, want := trimLine(s), strings.Trim(s, " \t\n\r"); got != want {
+			t.Errorf("trimLine(%q) = %q, want %q", s, got, want)
+		}
+	})
+}
+
+// FuzzHandleMessage checks arbitrary input never panics and requests always get a JSON-RPC 2.0 response
+func FuzzHandleMessage(f *testing.F) {
+	f.Add(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	f.Add(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":[]}}`)
+	f.Add(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":"x"}`)
+	f.Add(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	f.Add(`not json`)
+
+	s := newEchoServer(f, "")
+	f.Fuzz(func(t *testing.T, data string) {
+		response := s.handleMessage([]byte(data))
+		if response != nil && response.JSONRPC != "2.0" {
+			t.Errorf("Expected jsonrpc 2.0 response, got %+v", response)
+		}
+	})
+}
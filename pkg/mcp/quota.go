@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// Quota operation kinds. Write tools are charged for each ServiceNow request they send,
+// by its HTTP method (see ChargeQuota), and are refused up front when a quota their
+// tool falls under is exhausted. A request or tool can fall into several kinds; every
+// matching quota applies.
+const (
+	// QuotaCreate matches POST requests, and tools named create_*
+	QuotaCreate = "create"
+	// QuotaDelete matches DELETE requests, and tools named delete_* or remove_* or
+	// annotated with DestructiveHint
+	QuotaDelete = "delete"
+	// QuotaWrite matches every write request, and every tool not annotated with ReadOnlyHint
+	QuotaWrite = "write"
+)
+
+// QuotaRule limits how many calls of an operation kind one identity may make within a window
+type QuotaRule struct {
+	Operation string
+	Limit     int
+	Window    time.Duration
+}
+
+func (q QuotaRule) String() string {
+	return fmt.Sprintf("%s=%d/%s", q.Operation, q.Limit, formatWindow(q.Window))
+}
+
+// ParseQuotaRules parses a comma-separated quota spec such as "create=50/24h,delete=5/1h"
+func ParseQuotaRules(spec string) ([]QuotaRule, error) {
+	var rules []QuotaRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		operation, limitWindow, ok := strings.Cut(part, "=")
+		limitStr, windowStr, ok2 := strings.Cut(limitWindow, "/")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid quota %q: expected operation=limit/window (e.g., create=50/24h)", part)
+		}
+
+		operation = strings.TrimSpace(operation)
+		switch operation {
+		case QuotaCreate, QuotaDelete, QuotaWrite:
+		default:
+			return nil, fmt.Errorf("invalid quota %q: operation must be %s, %s, or %s", part, QuotaCreate, QuotaDelete, QuotaWrite)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid quota %q: limit must be a positive integer", part)
+		}
+		window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid quota %q: window must be a positive duration (e.g., 1h, 24h)", part)
+		}
+
+		rules = append(rules, QuotaRule{Operation: operation, Limit: limit, Window: window})
+	}
+	return rules, nil
+}
+
+// formatWindow renders a quota window in the largest whole unit (e.g., "24h", "5m")
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
+
+// ErrQuotaExceeded is returned by ChargeQuota when a write quota is exhausted
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// quotaPruneInterval is how often the counters of every identity are swept, so those
+// of identities that stopped calling are dropped once their windows pass
+const quotaPruneInterval = time.Minute
+
+// quotaTracker records charged operations per rule and identity
+type quotaTracker struct {
+	mu     sync.Mutex
+	rules  []QuotaRule
+	calls  map[string][]time.Time
+	pruned time.Time
+}
+
+func newQuotaTracker() *quotaTracker {
+	return &quotaTracker{calls: make(map[string][]time.Time)}
+}
+
+// setRules replaces the quota rules and resets all counters
+func (qt *quotaTracker) setRules(rules []QuotaRule) {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	qt.rules = rules
+	qt.calls = make(map[string][]time.Time)
+}
+
+// active reports whether any quota is set
+func (qt *quotaTracker) active() bool {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	return len(qt.rules) > 0
+}
+
+// check reports whether every rule matching the given operations has quota left for
+// the identity, recording nothing. It returns "" if so, and a message describing the
+// exhausted quota otherwise.
+func (qt *quotaTracker) check(identity string, operations map[string]bool) string {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	_, message := qt.available(identity, operations, time.Now())
+	return message
+}
+
+// charge checks the rules like check and, if all have quota left, records one
+// operation against each of them
+func (qt *quotaTracker) charge(identity string, operations map[string]bool) string {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	now := time.Now()
+	keys, message := qt.available(identity, operations, now)
+	if message != "" {
+		return message
+	}
+	for _, key := range keys {
+		qt.calls[key] = append(qt.calls[key], now)
+	}
+	return ""
+}
+
+// refund removes the latest operation charged against each rule matching operations
+func (qt *quotaTracker) refund(identity string, operations map[string]bool) {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+	for i, rule := range qt.rules {
+		key := quotaKey(i, identity)
+		if calls := qt.calls[key]; operations[rule.Operation] && len(calls) > 0 {
+			qt.calls[key] = calls[:len(calls)-1]
+		}
+	}
+}
+
+// available drops the identity's operations that left the window of each rule matching
+// operations, and returns the keys of those rules, or a message naming the first one
+// with no quota left. The caller holds qt.mu.
+func (qt *quotaTracker) available(identity string, operations map[string]bool, now time.Time) ([]string, string) {
+	if now.Sub(qt.pruned) >= quotaPruneInterval {
+		qt.prune(now)
+	}
+	var keys []string
+	for i, rule := range qt.rules {
+		if !operations[rule.Operation] {
+			continue
+		}
+		key := quotaKey(i, identity)
+		recent := qt.recent(key, rule, now)
+		if len(recent) >= rule.Limit {
+			retryAt := recent[0].Add(rule.Window)
+			return nil, fmt.Sprintf("Quota exceeded: max %d %s operations per %s for %s. Try again after %s.",
+				rule.Limit, rule.Operation, formatWindow(rule.Window), identity, retryAt.UTC().Format(time.RFC3339))
+		}
+		keys = append(keys, key)
+	}
+	return keys, ""
+}
+
+// recent keeps only the operations of key within the rule's window, deleting the key
+// when none are left. The caller holds qt.mu.
+func (qt *quotaTracker) recent(key string, rule QuotaRule, now time.Time) []time.Time {
+	cutoff := now.Add(-rule.Window)
+	recent := make([]time.Time, 0, len(qt.calls[key]))
+	for _, ts := range qt.calls[key] {
+		if ts.After(cutoff) {
+			recent = append(recent, ts)
+		}
+	}
+	if len(recent) == 0 {
+		delete(qt.calls, key)
+	} else {
+		qt.calls[key] = recent
+	}
+	return recent
+}
+
+// prune drops the operations of every identity that left their rule's window. The caller holds qt.mu.
+func (qt *quotaTracker) prune(now time.Time) {
+	qt.pruned = now
+	for key := range qt.calls {
+		index, _, _ := strings.Cut(key, "|")
+		if i, err := strconv.Atoi(index); err == nil && i < len(qt.rules) {
+			qt.recent(key, qt.rules[i], now)
+		} else {
+			delete(qt.calls, key)
+		}
+	}
+}
+
+// quotaKey is the key of the operations of an identity under the rule at index i
+func quotaKey(i int, identity string) string {
+	return fmt.Sprintf("%d|%s", i, identity)
+}
+
+// quotaMeterKey is the context key of the quota meter of a tool call
+type quotaMeterKey struct{}
+
+// quotaMeter charges the ServiceNow writes of one call of a write tool to the caller's quotas
+type quotaMeter struct {
+	tracker  *quotaTracker
+	identity string
+	charged  atomic.Int64
+}
+
+// settle charges a call that sent no metered request (e.g., one writing through an
+// API ChargeQuota doesn't see) once, as the operations its tool is classified under
+func (m *quotaMeter) settle(operations map[string]bool) {
+	if m.charged.Load() == 0 {
+		m.tracker.charge(m.identity, operations)
+	}
+}
+
+// methodOperations classifies a ServiceNow request by its HTTP method: every write is a
+// write, a POST also a create, and a DELETE also a delete. Reads are nothing.
+func methodOperations(method string) map[string]bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPost:
+		return map[string]bool{QuotaWrite: true, QuotaCreate: true}
+	case http.MethodPut, http.MethodPatch:
+		return map[string]bool{QuotaWrite: true}
+	case http.MethodDelete:
+		return map[string]bool{QuotaWrite: true, QuotaDelete: true}
+	}
+	return nil
+}
+
+// ChargeQuota charges one ServiceNow request of the tool call in ctx to the caller's
+// write quotas, classified by its HTTP method, so a tool writing many records (e.g., a
+// batch) is charged for each. It returns an error wrapping ErrQuotaExceeded, charging
+// nothing, when a quota is exhausted. Reads, and requests of read-only tools or of
+// servers without quotas, are not charged.
+func ChargeQuota(ctx context.Context, method string) error {
+	meter, ok := ctx.Value(quotaMeterKey{}).(*quotaMeter)
+	operations := methodOperations(method)
+	if !ok || operations == nil {
+		return nil
+	}
+	if message := meter.tracker.charge(meter.identity, operations); message != "" {
+		return fmt.Errorf("%w: %s", ErrQuotaExceeded, strings.TrimPrefix(message, "Quota exceeded: "))
+	}
+	meter.charged.Add(1)
+	return nil
+}
+
+// RefundQuota returns the charge of a request passed to ChargeQuota that was not sent
+func RefundQuota(ctx context.Context, method string) {
+	meter, ok := ctx.Value(quotaMeterKey{}).(*quotaMeter)
+	operations := methodOperations(method)
+	if !ok || operations == nil {
+		return
+	}
+	meter.tracker.refund(meter.identity, operations)
+	meter.charged.Add(-1)
+}
+
+// quotaOperations classifies a tool into the quota operation kinds it counts against
+func quotaOperations(tool Tool, baseName string) map[string]bool {
+	operations := map[string]bool{}
+	if tool.Annotations == nil || !tool.Annotations.ReadOnlyHint {
+		operations[QuotaWrite] = true
+	}
+	if strings.HasPrefix(baseName, "create_") {
+		operations[QuotaCreate] = true
+	}
+	if strings.HasPrefix(baseName, "delete_") || strings.HasPrefix(baseName, "remove_") ||
+		(tool.Annotations != nil && tool.Annotations.DestructiveHint) {
+		operations[QuotaDelete] = true
+	}
+	return operations
+}
+
+// CallerIdentity identifies who a tool call is attributed to (for quotas and whoami): the
+// ServiceNow user or API key passed in HTTP headers, then the MCP auth token,
+// and finally the server's configured identity (stdio mode)
+func CallerIdentity(ctx context.Context) string {
+	if creds := servicenow.CredentialsFromContext(ctx); creds != nil {
+		if creds.Username != "" {
+			return "user " + creds.Username
+		}
+		if creds.APIKey != "" {
+			return "api key " + hashToken(creds.APIKey)[:12]
+		}
+	}
+	if binding := sessionBindingFromContext(ctx); binding != nil && binding.token != "" {
+		return "token " + hashToken(binding.token)[:12]
+	}
+	return "default identity"
+}
@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// TestParseQuotaRules tests parsing of the quota spec format
+func TestParseQuotaRules(t *testing.T) {
+	rules, err := ParseQuotaRules("create=50/24h, delete=5/1h")
+	if err != nil {
+		t.Fatalf("ParseQuotaRules failed: %v", err)
+	}
+	if len(rules) != 2 || rules[0] != (QuotaRule{Operation: QuotaCreate, Limit: 50, Window: 24 * time.Hour}) ||
+		rules[1].String() != "delete=5/1h" {
+		t.Errorf("Unexpected rules: %v", rules)
+	}
+
+	for _, spec := range []string{"create=50", "update=5/1h", "create=0/1h", "create=5/soon"} {
+		if _, err := ParseQuotaRules(spec); err == nil {
+			t.Errorf("Expected error parsing %q", spec)
+		}
+	}
+}
+
+// TestQuotaEnforcement tests that quotas are tracked per identity and operation kind
+func TestQuotaEnforcement(t *testing.T) {
+	s := newEchoServer(t, "snow")
+	s.RegisterTool(Tool{
+		Name:        "create_thing",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(args map[string]interface{}) (*CallToolResult, error) {
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "created"}}}, nil
+	})
+	s.SetQuotas([]QuotaRule{{Operation: QuotaCreate, Limit: 2, Window: time.Hour}})
+
+	alice := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: "alice", Password: "x"})
+	bob := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: "bob", Password: "x"})
+	params := map[string]interface{}{"name": "snow_create_thing"}
+
+	for i := 0; i < 2; i++ {
+		if result, _ := s.handleCallToolWithContext(alice, params); result.IsError {
+			t.Fatalf("Expected call %d to succeed, got %+v", i+1, result)
+		}
+	}
+
+	result, _ := s.handleCallToolWithContext(alice, params)
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "Quota exceeded: max 2 create operations per 1h for user alice") {
+		t.Errorf("Expected quota exceeded, got %+v", result)
+	}
+
+	if result, _ := s.handleCallToolWithContext(bob, params); result.IsError {
+		t.Errorf("Expected another identity to have its own quota, got %+v", result)
+	}
+	if result, _ := s.handleCallToolWithContext(alice, map[string]interface{}{"name": "snow_echo"}); result.IsError {
+		t.Errorf("Expected non-create tool to be unaffected, got %+v", result)
+	}
+}
+
+// TestQuotaCharges tests that write tools are charged for each request they send, by its method
+func TestQuotaCharges(t *testing.T) {
+	s := newEchoServer(t, "")
+	var errs []error
+	s.RegisterToolWithContext(Tool{
+		Name:        "update_things",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(ctx context.Context, args map[string]interface{}) (*CallToolResult, error) {
+		errs = nil
+		for _, method := range []string{"GET", "POST", "PUT", "POST", "POST"} {
+			errs = append(errs, ChargeQuota(ctx, method))
+		}
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "updated"}}}, nil
+	})
+	s.SetQuotas([]QuotaRule{{Operation: QuotaCreate, Limit: 2, Window: time.Hour}, {Operation: QuotaWrite, Limit: 4, Window: time.Hour}})
+	params := map[string]interface{}{"name": "update_things"}
+	call := func(wantRefused ...bool) {
+		t.Helper()
+		if result, _ := s.handleCallToolWithContext(context.Background(), params); result.IsError {
+			t.Fatalf("Expected the call to run, got %+v", result)
+		}
+		for i, want := range wantRefused {
+			if got := errors.Is(errs[i], ErrQuotaExceeded); got != want {
+				t.Errorf("Request %d: expected refused %v, got %v", i, want, errs[i])
+			}
+		}
+	}
+
+	// Three writes, two of them creates, are charged; the third POST is over the create quota
+	call(false, false, false, false, true)
+	// Only the PUT fits, using up the write quota
+	call(false, true, false, true, true)
+	if result, _ := s.handleCallToolWithContext(context.Background(), params); !result.IsError || !strings.Contains(result.Content[0].Text, "max 4 write operations") {
+		t.Errorf("Expected the call to be refused once the write quota is used up, got %+v", result)
+	}
+
+	s.quotas.refund(CallerIdentity(context.Background()), map[string]bool{QuotaWrite: true})
+	call(false, true, false, true, true)
+}
+
+// TestQuotaPrune tests that the counters of identities whose windows passed are dropped
+func TestQuotaPrune(t *testing.T) {
+	qt := newQuotaTracker()
+	qt.setRules([]QuotaRule{{Operation: QuotaWrite, Limit: 5, Window: time.Millisecond}})
+	write := map[string]bool{QuotaWrite: true}
+	qt.charge("alice", write)
+	time.Sleep(2 * time.Millisecond)
+	qt.pruned = time.Time{}
+	qt.charge("bob", write)
+	if _, ok := qt.calls[quotaKey(0, "alice")]; ok || len(qt.calls) != 1 {
+		t.Errorf("Expected only bob's counter to be kept, got %v", qt.calls)
+	}
+}
+
+// TestQuotaOperations tests classification of tools into quota operation kinds
+func TestQuotaOperations(t *testing.T) {
+	readOnly := &ToolAnnotation{ReadOnlyHint: true}
+	destructive := &ToolAnnotation{DestructiveHint: true}
+
+	tests := []struct {
+		name string
+		tool Tool
+		want []string
+	}{
+		{name: "list_incidents", tool: Tool{Annotations: readOnly}, want: nil},
+		{name: "create_incident", tool: Tool{}, want: []string{QuotaWrite, QuotaCreate}},
+		{name: "remove_group_members", tool: Tool{}, want: []string{QuotaWrite, QuotaDelete}},
+		{name: "purge_records", tool: Tool{Annotations: destructive}, want: []string{QuotaWrite, QuotaDelete}},
+	}
+
+	for _, tt := range tests {
+		got := quotaOperations(tt.tool, tt.name)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+		for _, op := range tt.want {
+			if !got[op] {
+				t.Errorf("%s: expected %s in %v", tt.name, op, got)
+			}
+		}
+	}
+}
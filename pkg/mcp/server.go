@@ -2,17 +2,21 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/auth"
-	"github.com/elastiflow/go-mcp-servicenow/pkg/servicenow"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/auth"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
 )
 
 // ToolHandler is a function that handles a tool call
@@ -27,23 +31,57 @@ type ResourceProvider interface {
 	ReadResource(uri string) (*ReadResourceResult, error)
 }
 
+// ContextResourceProvider is implemented by resource providers whose content
+// depends on the request (e.g., the caller's ServiceNow identity). When the
+// registered provider implements it, resources/read uses ReadResourceWithContext.
+type ContextResourceProvider interface {
+	ResourceProvider
+	ReadResourceWithContext(ctx context.Context, uri string) (*ReadResourceResult, error)
+}
+
 // PromptProvider provides prompts for the MCP server
 type PromptProvider interface {
 	ListPrompts() []Prompt
 	GetPrompt(name string, arguments map[string]interface{}) (*GetPromptResult, error)
 }
 
+// ContextPromptProvider is implemented by prompt providers that fill prompts with
+// data read for the caller. When the registered provider implements it, prompts/get
+// uses GetPromptWithContext.
+type ContextPromptProvider interface {
+	PromptProvider
+	GetPromptWithContext(ctx context.Context, name string, arguments map[string]interface{}) (*GetPromptResult, error)
+}
+
+var (
+	// ErrResourceNotFound is wrapped by resource providers for URIs they don't serve;
+	// resources/read reports it with the ResourceNotFound error code
+	ErrResourceNotFound = errors.New("resource not found")
+	// ErrPromptNotFound is wrapped by prompt providers for names they don't serve;
+	// prompts/get reports it as invalid params
+	ErrPromptNotFound = errors.New("prompt not found")
+	// ErrInvalidPromptArguments is wrapped by prompt providers for missing or invalid
+	// prompt arguments; prompts/get reports it as invalid params
+	ErrInvalidPromptArguments = errors.New("invalid prompt arguments")
+
+	// errInvalidParams marks malformed request params
+	errInvalidParams = errors.New("invalid params")
+)
+
 // Server represents an MCP server
 type Server struct {
-	name     string
-	version  string
-	tools    []Tool
-	handlers map[string]ToolHandler
+	name        string
+	version     string
+	prefix      string
+	tools       []Tool
+	handlers    map[string]ToolHandler
 	ctxHandlers map[string]ToolHandlerWithContext
-	mu       sync.RWMutex
-	stdin    io.Reader
-	stdout   io.Writer
-	stderr   io.Writer
+	aliases     map[string]string
+	toolFilter  func(name string) bool
+	mu          sync.RWMutex
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
 
 	// Optional providers
 	resourceProvider ResourceProvider
@@ -53,9 +91,28 @@ type Server struct {
 	toolCallTimestamps []time.Time
 	rateLimitMu        sync.Mutex
 
+	// Alias usage counters
+	aliasUsage   map[string]int
+	aliasUsageMu sync.Mutex
+
+	// HTTP session lifecycle
+	sessions        *sessionStore
+	strictLifecycle bool
+
+	// Runtime read-only switch (blocks tools without ReadOnlyHint)
+	readOnly atomic.Bool
+
+	// Per-identity write quotas
+	quotas *quotaTracker
+
+	// pprof and expvar endpoints (MCP_DEBUG_ENDPOINTS)
+	debugEndpoints bool
+
 	// Callbacks
-	onToolCall func(name string, args map[string]interface{}, duration time.Duration, success bool)
-	onError    func(err error, context string)
+	onToolCall  func(call ToolCallInfo)
+	onError     func(err error, context string)
+	onAliasCall func(alias, target string)
+	onReadOnly  func(readOnly bool, source string)
 }
 
 // NewServer creates a new MCP server
@@ -66,6 +123,10 @@ func NewServer(name, version string) *Server {
 		tools:              make([]Tool, 0),
 		handlers:           make(map[string]ToolHandler),
 		ctxHandlers:        make(map[string]ToolHandlerWithContext),
+		aliases:            make(map[string]string),
+		aliasUsage:         make(map[string]int),
+		sessions:           newSessionStore(DefaultSessionIdleTimeout),
+		quotas:             newQuotaTracker(),
 		stdin:              os.Stdin,
 		stdout:             os.Stdout,
 		stderr:             os.Stderr,
@@ -73,16 +134,96 @@ func NewServer(name, version string) *Server {
 	}
 }
 
+// ToolCallInfo describes a completed tool call, for telemetry
+type ToolCallInfo struct {
+	Name      string
+	Arguments map[string]interface{}
+	Result    *CallToolResult
+	Duration  time.Duration
+	Success   bool
+	// RequestID is the JSON-RPC ID of the tools/call request the call was made for, if any
+	RequestID interface{}
+}
+
 // SetToolCallCallback sets a callback for tool calls (for telemetry)
-func (s *Server) SetToolCallCallback(cb func(name string, args map[string]interface{}, duration time.Duration, success bool)) {
+func (s *Server) SetToolCallCallback(cb func(call ToolCallInfo)) {
 	s.onToolCall = cb
 }
 
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the JSON-RPC ID of the request being handled, or nil
+func RequestIDFromContext(ctx context.Context) interface{} {
+	return ctx.Value(requestIDKey{})
+}
+
 // SetErrorCallback sets a callback for errors
 func (s *Server) SetErrorCallback(cb func(err error, context string)) {
 	s.onError = cb
 }
 
+// SetAliasCallback sets a callback invoked whenever a deprecated alias is called (for telemetry)
+func (s *Server) SetAliasCallback(cb func(alias, target string)) {
+	s.onAliasCall = cb
+}
+
+// SetStrictLifecycle enables strict MCP lifecycle enforcement in HTTP mode.
+// When enabled, requests other than initialize and ping are rejected until the
+// session has completed initialize, and unknown or expired session IDs get a 404.
+func (s *Server) SetStrictLifecycle(strict bool) {
+	s.strictLifecycle = strict
+}
+
+// SetSessionIdleTimeout sets how long an HTTP session may stay idle before it
+// expires. A zero or negative value disables expiry.
+func (s *Server) SetSessionIdleTimeout(d time.Duration) {
+	s.sessions.setIdleTimeout(d)
+}
+
+// SetReadOnlyCallback sets a callback invoked whenever the runtime read-only switch changes
+func (s *Server) SetReadOnlyCallback(cb func(readOnly bool, source string)) {
+	s.onReadOnly = cb
+}
+
+// SetReadOnly flips the runtime read-only switch. While enabled, calls to any
+// tool not annotated with ReadOnlyHint are rejected, without a restart.
+func (s *Server) SetReadOnly(readOnly bool, source string) {
+	if s.readOnly.Swap(readOnly) == readOnly {
+		return
+	}
+	if s.onReadOnly != nil {
+		s.onReadOnly(readOnly, source)
+	}
+}
+
+// IsReadOnly reports whether the runtime read-only switch is enabled
+func (s *Server) IsReadOnly() bool {
+	return s.readOnly.Load()
+}
+
+// SetQuotas sets the per-identity quotas enforced on tool calls, resetting all counters
+func (s *Server) SetQuotas(rules []QuotaRule) {
+	s.quotas.setRules(rules)
+}
+
+// lookupTool returns the registered tool with the given name
+func (s *Server) lookupTool(name string) (Tool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, tool := range s.tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// isWriteTool reports whether a tool may modify data, i.e. it is not annotated with ReadOnlyHint
+func (s *Server) isWriteTool(name string) bool {
+	tool, ok := s.lookupTool(name)
+	return ok && (tool.Annotations == nil || !tool.Annotations.ReadOnlyHint)
+}
+
 // RegisterResourceProvider registers a resource provider
 func (s *Server) RegisterResourceProvider(provider ResourceProvider) {
 	s.resourceProvider = provider
@@ -93,10 +234,50 @@ func (s *Server) RegisterPromptProvider(provider PromptProvider) {
 	s.promptProvider = provider
 }
 
+// SetToolPrefix sets a prefix applied to the names of all subsequently
+// registered tools (e.g., "snow" turns list_incidents into snow_list_incidents)
+func (s *Server) SetToolPrefix(prefix string) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prefix = prefix
+}
+
+// ToolName returns the externally visible name for a tool, including any configured prefix
+func (s *Server) ToolName(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.prefix + name
+}
+
+// SetToolFilter limits the tools listed and callable to those for which filter
+// returns true. The filter receives tool names without the configured prefix and
+// is consulted on every request, so it may change its answer at runtime. Aliases
+// are visible when their target is. A nil filter exposes every registered tool.
+func (s *Server) SetToolFilter(filter func(name string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolFilter = filter
+}
+
+// toolVisibleLocked reports whether a tool passes the tool filter. s.mu must be held.
+func (s *Server) toolVisibleLocked(name string) bool {
+	if s.toolFilter == nil {
+		return true
+	}
+	if target, ok := s.aliases[name]; ok {
+		name = target
+	}
+	return s.toolFilter(strings.TrimPrefix(name, s.prefix))
+}
+
 // RegisterTool registers a tool with its handler
 func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	tool.Name = s.prefix + tool.Name
 	s.tools = append(s.tools, tool)
 	s.handlers[tool.Name] = handler
 }
@@ -105,10 +286,78 @@ func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
 func (s *Server) RegisterToolWithContext(tool Tool, handler ToolHandlerWithContext) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	tool.Name = s.prefix + tool.Name
 	s.tools = append(s.tools, tool)
 	s.ctxHandlers[tool.Name] = handler
 }
 
+// RegisterAlias registers a deprecated alias for an already registered tool.
+// Calls to the alias are routed to the target tool, and the alias is listed
+// with the target's schema and a deprecation notice in its description.
+func (s *Server) RegisterAlias(alias, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alias = s.prefix + alias
+	target = s.prefix + target
+
+	var targetTool *Tool
+	for i := range s.tools {
+		if s.tools[i].Name == target {
+			targetTool = &s.tools[i]
+			break
+		}
+	}
+	if targetTool == nil {
+		return fmt.Errorf("alias target not registered: %s", target)
+	}
+	if _, exists := s.aliases[alias]; exists {
+		return fmt.Errorf("alias already registered: %s", alias)
+	}
+	for _, tool := range s.tools {
+		if tool.Name == alias {
+			return fmt.Errorf("alias conflicts with registered tool: %s", alias)
+		}
+	}
+
+	aliasTool := *targetTool
+	aliasTool.Name = alias
+	aliasTool.Description = fmt.Sprintf("[DEPRECATED: use %s] %s", target, targetTool.Description)
+	s.tools = append(s.tools, aliasTool)
+	s.aliases[alias] = target
+	return nil
+}
+
+// AliasUsage returns the number of calls made through each deprecated alias
+func (s *Server) AliasUsage() map[string]int {
+	s.aliasUsageMu.Lock()
+	defer s.aliasUsageMu.Unlock()
+	usage := make(map[string]int, len(s.aliasUsage))
+	for alias, count := range s.aliasUsage {
+		usage[alias] = count
+	}
+	return usage
+}
+
+// resolveAlias returns the target tool name for an alias, recording its usage
+func (s *Server) resolveAlias(name string) string {
+	s.mu.RLock()
+	target, isAlias := s.aliases[name]
+	s.mu.RUnlock()
+	if !isAlias {
+		return name
+	}
+
+	s.aliasUsageMu.Lock()
+	s.aliasUsage[name]++
+	s.aliasUsageMu.Unlock()
+
+	if s.onAliasCall != nil {
+		s.onAliasCall(name, target)
+	}
+	return target
+}
+
 // checkRateLimit returns true if the request should be rate limited
 func (s *Server) checkRateLimit() bool {
 	s.rateLimitMu.Lock()
@@ -172,7 +421,7 @@ func (s *Server) Run() error {
 				continue
 			}
 
-			response := s.handleMessage([]byte(line))
+			response := s.handlePayloadWithContext(context.Background(), []byte(line))
 			if response != nil {
 				s.sendResponse(response)
 			}
@@ -201,6 +450,16 @@ func (s *Server) RunHTTP(addr string) error {
 
 // RunHTTPWithAuthorizer starts the server in HTTP mode with a custom authorizer
 func (s *Server) RunHTTPWithAuthorizer(addr string, authorizer auth.Authorizer) error {
+	if auth.IsAuthEnabled() {
+		fmt.Fprintf(s.stderr, "MCP Server running on HTTP at %s (authentication enabled)\n", addr)
+	} else {
+		fmt.Fprintf(s.stderr, "MCP Server running on HTTP at %s (authentication disabled)\n", addr)
+	}
+	return http.ListenAndServe(addr, s.httpHandler(authorizer))
+}
+
+// httpHandler builds the HTTP routes served in HTTP mode
+func (s *Server) httpHandler(authorizer auth.Authorizer) http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint (no auth required)
@@ -213,17 +472,31 @@ func (s *Server) RunHTTPWithAuthorizer(addr string, authorizer auth.Authorizer)
 		})
 	})
 
+	// Admin endpoint for the runtime read-only switch (only when MCP_ADMIN_TOKEN is set)
+	mux.HandleFunc("/admin/read-only", s.handleAdminReadOnly)
+
+	// Profiling endpoints (only when MCP_DEBUG_ENDPOINTS is set)
+	if s.debugEndpoints {
+		debug := s.debugHandler()
+		mux.Handle("/debug/pprof/", debug)
+		mux.Handle("/debug/vars", debug)
+	}
+
 	// MCP endpoint with authentication
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
+		// Check Authorization header first, then fall back to X-MCP-Auth-Token
+		token := r.Header.Get("Authorization")
+		if token == "" {
+			token = r.Header.Get(auth.AuthHeaderName)
+		}
+
 		// Check authentication if enabled
 		if auth.IsAuthEnabled() {
-			// Check Authorization header first, then fall back to X-MCP-Auth-Token
-			token := r.Header.Get("Authorization")
 			if token == "" {
 				token = r.Header.Get(auth.AuthHeaderName)
 			}
@@ -262,6 +535,31 @@ func (s *Server) RunHTTPWithAuthorizer(addr string, authorizer auth.Authorizer)
 			}
 		}
 
+		// Resolve the session from Mcp-Session-Id, falling back to the auth token
+		sessionID := r.Header.Get(HeaderSessionID)
+		binding := &sessionBinding{token: token, sessionID: sessionID, sess: s.sessions.lookup(sessionID, token)}
+		if binding.sess == nil && sessionID != "" && (s.strictLifecycle || r.Method == http.MethodDelete) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      nil,
+				"error":   map[string]interface{}{"code": InvalidRequest, "message": "Session not found or expired: send initialize to start a new session"},
+			})
+			return
+		}
+
+		// DELETE terminates the session
+		if r.Method == http.MethodDelete {
+			if binding.sess == nil {
+				http.Error(w, "Missing "+HeaderSessionID+" header", http.StatusBadRequest)
+				return
+			}
+			s.sessions.remove(binding.sess.id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			w.Header().Set("Content-Type", "application/json")
@@ -287,20 +585,57 @@ func (s *Server) RunHTTPWithAuthorizer(addr string, authorizer auth.Authorizer)
 			}
 			ctx = servicenow.ContextWithCredentials(ctx, creds)
 		}
+		if user := r.Header.Get(servicenow.HeaderImpersonateUser); user != "" {
+			ctx = servicenow.ContextWithImpersonation(ctx, user)
+		}
+		ctx = contextWithSessionBinding(ctx, binding)
 
-		response := s.handleMessageWithContext(ctx, body)
+		response := s.handlePayloadWithContext(ctx, body)
+		if binding.sess != nil {
+			w.Header().Set(HeaderSessionID, binding.sess.id)
+		}
 		if response != nil {
 			w.Header().Set("Content-Type", "application/json")
 			_ = json.NewEncoder(w).Encode(response)
 		}
 	})
 
-	if auth.IsAuthEnabled() {
-		fmt.Fprintf(s.stderr, "MCP Server running on HTTP at %s (authentication enabled)\n", addr)
-	} else {
-		fmt.Fprintf(s.stderr, "MCP Server running on HTTP at %s (authentication disabled)\n", addr)
+	return mux
+}
+
+// handleAdminReadOnly reports (GET) or flips (POST {"read_only": bool}) the runtime read-only switch
+func (s *Server) handleAdminReadOnly(w http.ResponseWriter, r *http.Request) {
+	if !auth.IsAdminEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+	if !auth.ValidateAdminToken(r.Header.Get(auth.AdminHeaderName)) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": "Unauthorized: invalid admin token"})
+		return
 	}
-	return http.ListenAndServe(addr, mux)
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var body struct {
+			ReadOnly *bool `json:"read_only"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ReadOnly == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": `Request body must be {"read_only": true|false}`})
+			return
+		}
+		s.SetReadOnly(*body.ReadOnly, "admin endpoint "+r.RemoteAddr)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"read_only": s.IsReadOnly()})
 }
 
 func trimLine(s string) string {
@@ -315,6 +650,64 @@ func trimLine(s string) string {
 	return s[start:end]
 }
 
+// handlePayloadWithContext handles a raw JSON-RPC payload, which is either a
+// single message or a batch array. It returns a *JSONRPCResponse for a single
+// message, a []*JSONRPCResponse for a batch, or nil when nothing should be sent.
+func (s *Server) handlePayloadWithContext(ctx context.Context, data []byte) interface{} {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		if response := s.handleMessageWithContext(ctx, data); response != nil {
+			return response
+		}
+		return nil
+	}
+
+	var messages []json.RawMessage
+	if err := json.Unmarshal(trimmed, &messages); err != nil {
+		return &JSONRPCResponse{
+			JSONRPC: "2.0",
+			Error: &JSONRPCError{
+				Code:    ParseError,
+				Message: "Parse error",
+				Data:    err.Error(),
+			},
+		}
+	}
+	if len(messages) == 0 {
+		return &JSONRPCResponse{
+			JSONRPC: "2.0",
+			Error: &JSONRPCError{
+				Code:    InvalidRequest,
+				Message: "Invalid Request: empty batch",
+			},
+		}
+	}
+
+	// Batch entries are handled in order; notifications produce no response
+	responses := make([]*JSONRPCResponse, 0, len(messages))
+	for _, message := range messages {
+		message = bytes.TrimLeft(message, " \t\r\n")
+		if len(message) == 0 || message[0] != '{' {
+			responses = append(responses, &JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error: &JSONRPCError{
+					Code:    InvalidRequest,
+					Message: "Invalid Request: batch entries must be objects",
+				},
+			})
+			continue
+		}
+		if response := s.handleMessageWithContext(ctx, message); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
 func (s *Server) handleMessage(data []byte) *JSONRPCResponse {
 	return s.handleMessageWithContext(context.Background(), data)
 }
@@ -360,42 +753,47 @@ func (s *Server) handleRequestWithContext(ctx context.Context, request *JSONRPCR
 		ID:      request.ID,
 	}
 
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		response.Error = &JSONRPCError{
+			Code:    InvalidRequest,
+			Message: "Invalid Request: jsonrpc must be \"2.0\" and method is required",
+		}
+		return response
+	}
+
+	if err := s.checkLifecycle(ctx, request.Method); err != nil {
+		response.Error = err
+		return response
+	}
+
 	switch request.Method {
 	case "initialize":
 		response.Result = s.handleInitialize(request.Params)
+		s.startSession(ctx, request.Params)
 	case "tools/list":
 		response.Result = s.handleListTools()
 	case "tools/call":
-		result, err := s.handleCallToolWithContext(ctx, request.Params)
+		result, err := s.handleCallToolWithContext(context.WithValue(ctx, requestIDKey{}, request.ID), request.Params)
 		if err != nil {
-			response.Error = &JSONRPCError{
-				Code:    InternalError,
-				Message: err.Error(),
-			}
+			response.Error = rpcError(err)
 		} else {
 			response.Result = result
 		}
 	case "resources/list":
 		response.Result = s.handleListResources()
 	case "resources/read":
-		result, err := s.handleReadResource(request.Params)
+		result, err := s.handleReadResource(ctx, request.Params)
 		if err != nil {
-			response.Error = &JSONRPCError{
-				Code:    InternalError,
-				Message: err.Error(),
-			}
+			response.Error = rpcError(err)
 		} else {
 			response.Result = result
 		}
 	case "prompts/list":
 		response.Result = s.handleListPrompts()
 	case "prompts/get":
-		result, err := s.handleGetPrompt(request.Params)
+		result, err := s.handleGetPrompt(ctx, request.Params)
 		if err != nil {
-			response.Error = &JSONRPCError{
-				Code:    InternalError,
-				Message: err.Error(),
-			}
+			response.Error = rpcError(err)
 		} else {
 			response.Result = result
 		}
@@ -411,6 +809,61 @@ func (s *Server) handleRequestWithContext(ctx context.Context, request *JSONRPCR
 	return response
 }
 
+// rpcError converts a method handler error to a JSON-RPC error
+func rpcError(err error) *JSONRPCError {
+	code := InternalError
+	switch {
+	case errors.Is(err, errInvalidParams), errors.Is(err, ErrPromptNotFound), errors.Is(err, ErrInvalidPromptArguments):
+		code = InvalidParams
+	case errors.Is(err, ErrResourceNotFound):
+		code = ResourceNotFound
+	}
+	return &JSONRPCError{Code: code, Message: err.Error()}
+}
+
+// checkLifecycle rejects requests sent on an HTTP session before initialize when
+// strict lifecycle enforcement is enabled. Stdio requests are never rejected.
+func (s *Server) checkLifecycle(ctx context.Context, method string) *JSONRPCError {
+	if !s.strictLifecycle || method == "initialize" || method == "ping" {
+		return nil
+	}
+	binding := sessionBindingFromContext(ctx)
+	if binding == nil {
+		return nil
+	}
+	if binding.sess == nil || !s.sessions.isInitialized(binding.sess) {
+		return &JSONRPCError{
+			Code:    InvalidRequest,
+			Message: fmt.Sprintf("Session not initialized: send initialize before %s", method),
+		}
+	}
+	return nil
+}
+
+// startSession begins a new HTTP session for an initialize request
+func (s *Server) startSession(ctx context.Context, params interface{}) {
+	binding := sessionBindingFromContext(ctx)
+	if binding == nil {
+		return
+	}
+
+	var info ClientInfo
+	if paramsMap, ok := params.(map[string]interface{}); ok {
+		if clientInfo, ok := paramsMap["clientInfo"].(map[string]interface{}); ok {
+			info.Name, _ = clientInfo["name"].(string)
+			info.Version, _ = clientInfo["version"].(string)
+		}
+	}
+
+	// A client re-initializing its own session replaces it. A session found only by
+	// the token may belong to another client sharing it, so it is left alone.
+	if binding.sess != nil && binding.sess.id == binding.sessionID {
+		s.sessions.remove(binding.sess.id)
+	}
+	binding.sess = s.sessions.create(binding.token)
+	s.sessions.markInitialized(binding.sess, info)
+}
+
 func (s *Server) handleInitialize(params interface{}) *InitializeResult {
 	caps := ServerCapabilities{
 		Tools: &ToolsCapability{ListChanged: false},
@@ -434,9 +887,42 @@ func (s *Server) handleInitialize(params interface{}) *InitializeResult {
 }
 
 func (s *Server) handleListTools() *ListToolsResult {
+	return &ListToolsResult{Tools: s.ListTools()}
+}
+
+// ListTools returns a snapshot of the registered tools that pass the tool filter, as returned by tools/list
+func (s *Server) ListTools() []Tool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return &ListToolsResult{Tools: s.tools}
+	tools := make([]Tool, 0, len(s.tools))
+	for _, tool := range s.tools {
+		if s.toolVisibleLocked(tool.Name) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// Handler returns the handler registered for a tool, bypassing rate limits,
+// quotas, and callbacks. Plain handlers are adapted to take a context.
+func (s *Server) Handler(name string) (ToolHandlerWithContext, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if handler, ok := s.ctxHandlers[name]; ok {
+		return handler, true
+	}
+	if handler, ok := s.handlers[name]; ok {
+		return func(_ context.Context, args map[string]interface{}) (*CallToolResult, error) {
+			return handler(args)
+		}, true
+	}
+	return nil, false
+}
+
+// CallTool calls a tool the way a tools/call request does, applying aliases, the
+// tool filter, rate limits, quotas, read-only mode, and callbacks
+func (s *Server) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*CallToolResult, error) {
+	return s.handleCallToolWithContext(ctx, map[string]interface{}{"name": name, "arguments": arguments})
 }
 
 func (s *Server) handleCallTool(params interface{}) (*CallToolResult, error) {
@@ -446,34 +932,53 @@ func (s *Server) handleCallTool(params interface{}) (*CallToolResult, error) {
 func (s *Server) handleCallToolWithContext(ctx context.Context, params interface{}) (*CallToolResult, error) {
 	paramsMap, ok := params.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("invalid params type")
+		return nil, fmt.Errorf("%w: params must be an object", errInvalidParams)
 	}
 
 	name, ok := paramsMap["name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing tool name")
+		return nil, fmt.Errorf("%w: missing tool name", errInvalidParams)
 	}
 
 	arguments, _ := paramsMap["arguments"].(map[string]interface{})
 
 	// Check rate limit
 	if s.checkRateLimit() {
-		return &CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: "Rate limit exceeded: Maximum 5 tool calls per 20 seconds. Please try again later."}},
-			IsError: true,
-		}, nil
+		return ToolError(ErrorRateLimited, "Rate limit exceeded: Maximum 5 tool calls per 20 seconds. Please try again later."), nil
+	}
+
+	name = s.resolveAlias(name)
+
+	if s.IsReadOnly() && s.isWriteTool(name) {
+		return ToolError(ErrorPermissionDenied, "This operation is blocked: the server was switched to read-only mode by an administrator. Write operations are disabled until it is switched back."), nil
 	}
 
 	s.mu.RLock()
 	handler, handlerExists := s.handlers[name]
 	ctxHandler, ctxHandlerExists := s.ctxHandlers[name]
+	visible := s.toolVisibleLocked(name)
+	prefix := s.prefix
 	s.mu.RUnlock()
 
 	if !handlerExists && !ctxHandlerExists {
-		return &CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Unknown tool: %s", name)}},
-			IsError: true,
-		}, nil
+		return ToolError(ErrorNotFound, fmt.Sprintf("Unknown tool: %s", name)), nil
+	}
+	if !visible {
+		return ToolError(ErrorPermissionDenied, fmt.Sprintf("Tool %s is not available in the current tool package", name)), nil
+	}
+
+	// Check per-identity quotas, then charge the requests of write tools as they are sent
+	if tool, ok := s.lookupTool(name); ok && s.quotas.active() {
+		operations := quotaOperations(tool, strings.TrimPrefix(name, prefix))
+		identity := CallerIdentity(ctx)
+		if message := s.quotas.check(identity, operations); message != "" {
+			return ToolError(ErrorRateLimited, message), nil
+		}
+		if operations[QuotaWrite] {
+			meter := &quotaMeter{tracker: s.quotas, identity: identity}
+			ctx = context.WithValue(ctx, quotaMeterKey{}, meter)
+			defer meter.settle(operations)
+		}
 	}
 
 	startTime := time.Now()
@@ -492,17 +997,21 @@ func (s *Server) handleCallToolWithContext(ctx context.Context, params interface
 
 	// Call telemetry callback
 	if s.onToolCall != nil {
-		s.onToolCall(name, arguments, duration, success)
+		s.onToolCall(ToolCallInfo{
+			Name:      name,
+			Arguments: arguments,
+			Result:    result,
+			Duration:  duration,
+			Success:   success,
+			RequestID: RequestIDFromContext(ctx),
+		})
 	}
 
 	if err != nil {
 		if s.onError != nil {
 			s.onError(err, fmt.Sprintf("tool_%s", name))
 		}
-		return &CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("Error: %s", err.Error())}},
-			IsError: true,
-		}, nil
+		return ToolError(ErrorUpstreamError, fmt.Sprintf("Error: %s", err.Error())), nil
 	}
 
 	return result, nil
@@ -515,21 +1024,24 @@ func (s *Server) handleListResources() *ListResourcesResult {
 	return &ListResourcesResult{Resources: s.resourceProvider.ListResources()}
 }
 
-func (s *Server) handleReadResource(params interface{}) (*ReadResourceResult, error) {
+func (s *Server) handleReadResource(ctx context.Context, params interface{}) (*ReadResourceResult, error) {
 	if s.resourceProvider == nil {
 		return nil, fmt.Errorf("resources not supported")
 	}
 
 	paramsMap, ok := params.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("invalid params type")
+		return nil, fmt.Errorf("%w: params must be an object", errInvalidParams)
 	}
 
 	uri, ok := paramsMap["uri"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing resource uri")
+		return nil, fmt.Errorf("%w: missing resource uri", errInvalidParams)
 	}
 
+	if provider, ok := s.resourceProvider.(ContextResourceProvider); ok {
+		return provider.ReadResourceWithContext(ctx, uri)
+	}
 	return s.resourceProvider.ReadResource(uri)
 }
 
@@ -540,26 +1052,29 @@ func (s *Server) handleListPrompts() *ListPromptsResult {
 	return &ListPromptsResult{Prompts: s.promptProvider.ListPrompts()}
 }
 
-func (s *Server) handleGetPrompt(params interface{}) (*GetPromptResult, error) {
+func (s *Server) handleGetPrompt(ctx context.Context, params interface{}) (*GetPromptResult, error) {
 	if s.promptProvider == nil {
 		return nil, fmt.Errorf("prompts not supported")
 	}
 
 	paramsMap, ok := params.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("invalid params type")
+		return nil, fmt.Errorf("%w: params must be an object", errInvalidParams)
 	}
 
 	name, ok := paramsMap["name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("missing prompt name")
+		return nil, fmt.Errorf("%w: missing prompt name", errInvalidParams)
 	}
 
 	arguments, _ := paramsMap["arguments"].(map[string]interface{})
+	if provider, ok := s.promptProvider.(ContextPromptProvider); ok {
+		return provider.GetPromptWithContext(ctx, name, arguments)
+	}
 	return s.promptProvider.GetPrompt(name, arguments)
 }
 
-func (s *Server) sendResponse(response *JSONRPCResponse) {
+func (s *Server) sendResponse(response interface{}) {
 	data, err := json.Marshal(response)
 	if err != nil {
 		fmt.Fprintf(s.stderr, "Error marshaling response: %v\n", err)
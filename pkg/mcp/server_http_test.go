@@ -7,9 +7,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/auth"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/auth"
 )
 
 // createTestServer creates an MCP server wrapped with HTTP handlers for testing.
@@ -53,85 +54,7 @@ func createTestServer(t *testing.T, authorizer auth.Authorizer, enableAuth bool)
 		}, nil
 	})
 
-	// Create HTTP mux with the same routes as RunHTTPWithAuthorizer
-	mux := http.NewServeMux()
-
-	// Health check endpoint (no auth required)
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":  "ok",
-			"version": mcpServer.version,
-		})
-	})
-
-	// MCP endpoint with authentication
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		// Check authentication if enabled
-		if auth.IsAuthEnabled() {
-			token := r.Header.Get("Authorization")
-			if token == "" {
-				token = r.Header.Get(auth.AuthHeaderName)
-			}
-
-			if token == "" {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				_ = json.NewEncoder(w).Encode(map[string]interface{}{
-					"jsonrpc": "2.0",
-					"id":      nil,
-					"error":   map[string]interface{}{"code": -32001, "message": "Unauthorized: missing Authorization header"},
-				})
-				return
-			}
-
-			var authorized bool
-			var authErr error
-			if authorizer != nil {
-				authorized, authErr = authorizer.Authorize(r.Context(), token)
-			} else {
-				defaultAuth := auth.NewTokenAuthorizer()
-				authorized, authErr = defaultAuth.Authorize(r.Context(), token)
-			}
-
-			if authErr != nil || !authorized {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusUnauthorized)
-				_ = json.NewEncoder(w).Encode(map[string]interface{}{
-					"jsonrpc": "2.0",
-					"id":      nil,
-					"error":   map[string]interface{}{"code": -32001, "message": "Unauthorized: invalid authentication token"},
-				})
-				return
-			}
-		}
-
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0",
-				"id":      nil,
-				"error":   map[string]interface{}{"code": -32700, "message": "Parse error"},
-			})
-			return
-		}
-
-		response := mcpServer.handleMessage(body)
-		if response != nil {
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(response)
-		}
-	})
-
-	ts := httptest.NewServer(mux)
+	ts := httptest.NewServer(mcpServer.httpHandler(authorizer))
 
 	cleanup := func() {
 		ts.Close()
@@ -598,3 +521,132 @@ func TestHTTPUnknownMethod(t *testing.T) {
 		t.Errorf("Expected error code %d (MethodNotFound), got %d", MethodNotFound, result.Error.Code)
 	}
 }
+
+// TestHTTPBatchRequest tests that POST / with a batch array returns a batch response
+func TestHTTPBatchRequest(t *testing.T) {
+	ts, cleanup := createTestServer(t, nil, false)
+	defer cleanup()
+
+	batch := `[{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}, {"jsonrpc": "2.0", "id": 2, "method": "ping"}]`
+	resp, err := http.Post(ts.URL+"/", "application/json", bytes.NewReader([]byte(batch)))
+	if err != nil {
+		t.Fatalf("Failed to send POST request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var results []JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		t.Fatalf("Failed to decode batch response: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			t.Errorf("Expected no error for response %d, got %v", i, result.Error)
+		}
+		if result.ID != float64(i+1) {
+			t.Errorf("Expected response %d to have id %d, got %v", i, i+1, result.ID)
+		}
+	}
+}
+
+// TestHTTPAdminReadOnly tests that the admin endpoint requires the admin token and flips the switch
+func TestHTTPAdminReadOnly(t *testing.T) {
+	t.Setenv("MCP_ADMIN_TOKEN", "admin-secret")
+
+	s := NewServer("test-servicenow-mcp", "1.0.0-test")
+	ts := httptest.NewServer(s.httpHandler(nil))
+	defer ts.Close()
+
+	post := func(token, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/admin/read-only", bytes.NewReader([]byte(body)))
+		req.Header.Set(auth.AdminHeaderName, token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to send POST request: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := post("wrong", `{"read_only": true}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for wrong admin token, got %d", resp.StatusCode)
+	}
+	if s.IsReadOnly() {
+		t.Fatal("Expected read-only mode to be unchanged")
+	}
+
+	if resp := post("admin-secret", `{}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing read_only, got %d", resp.StatusCode)
+	}
+
+	if resp := post("admin-secret", `{"read_only": true}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200, got %d", resp.StatusCode)
+	}
+	if !s.IsReadOnly() {
+		t.Error("Expected read-only mode to be enabled")
+	}
+
+	os.Unsetenv("MCP_ADMIN_TOKEN")
+	resp, err := http.Get(ts.URL + "/admin/read-only")
+	if err != nil {
+		t.Fatalf("Failed to send GET request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 when no admin token is configured, got %d", resp.StatusCode)
+	}
+}
+
+// TestHTTPDebugEndpoints tests that the debug endpoints are served only when enabled, to
+// loopback callers or with the admin token
+func TestHTTPDebugEndpoints(t *testing.T) {
+	t.Setenv("MCP_ADMIN_TOKEN", "admin-secret")
+
+	s := NewServer("test-servicenow-mcp", "1.0.0-test")
+	get := func(path, remoteAddr, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set(auth.AdminHeaderName, token)
+		}
+		rec := httptest.NewRecorder()
+		s.httpHandler(nil).ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/debug/vars", "127.0.0.1:5000", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected the debug endpoints to be off by default, got %d", rec.Code)
+	}
+
+	s.SetDebugEndpoints(true)
+	if rec := get("/debug/vars", "127.0.0.1:5000", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"goroutines"`) {
+		t.Errorf("Expected expvar variables for a loopback caller, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := get("/debug/pprof/", "[::1]:5000", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected the pprof index for a loopback caller, got %d", rec.Code)
+	}
+	if rec := get("/debug/pprof/heap", "10.0.0.5:5000", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a remote caller without the admin token, got %d", rec.Code)
+	}
+	if rec := get("/debug/pprof/heap", "10.0.0.5:5000", "admin-secret"); rec.Code != http.StatusOK {
+		t.Errorf("Expected the heap profile with the admin token, got %d", rec.Code)
+	}
+}
+
+// TestWriteRuntimeSnapshot tests that a snapshot writes the goroutine stacks and a heap profile
+func TestWriteRuntimeSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := WriteRuntimeSnapshot(dir)
+	if err != nil || len(paths) != 2 {
+		t.Fatalf("Expected two snapshot files, got %v (%v)", paths, err)
+	}
+	stacks, err := os.ReadFile(paths[0])
+	if err != nil || !strings.Contains(string(stacks), "goroutine") {
+		t.Errorf("Expected goroutine stacks in %s, got %v", paths[0], err)
+	}
+	if info, err := os.Stat(paths[1]); err != nil || info.Size() == 0 {
+		t.Errorf("Expected a heap profile in %s, got %v", paths[1], err)
+	}
+}
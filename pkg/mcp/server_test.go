@@ -0,0 +1,241 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// newEchoServer creates a server with a single echo tool registered
+func newEchoServer(t testing.TB, prefix string) *Server {
+	t.Helper()
+
+	s := NewServer("test-servicenow-mcp", "1.0.0-test")
+	s.SetToolPrefix(prefix)
+	s.RegisterTool(Tool{
+		Name:        "echo",
+		Description: "Echo a message",
+		InputSchema: JSONSchema{Type: "object"},
+	}, func(args map[string]interface{}) (*CallToolResult, error) {
+		msg, _ := args["message"].(string)
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "Echo: " + msg}}}, nil
+	})
+	return s
+}
+
+// callTool sends a tools/call request through the message handler and returns the result
+func callTool(t *testing.T, s *Server, name string, args map[string]interface{}) *CallToolResult {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": args},
+	})
+	response := s.handleMessage(body)
+	if response == nil || response.Error != nil {
+		t.Fatalf("Expected successful response, got %+v", response)
+	}
+	result, ok := response.Result.(*CallToolResult)
+	if !ok {
+		t.Fatalf("Expected *CallToolResult, got %T", response.Result)
+	}
+	return result
+}
+
+// TestToolPrefix tests that a configured prefix is applied to registered tool names
+func TestToolPrefix(t *testing.T) {
+	s := newEchoServer(t, "snow")
+
+	tools := s.handleListTools().Tools
+	if len(tools) != 1 || tools[0].Name != "snow_echo" {
+		t.Fatalf("Expected single tool 'snow_echo', got %+v", tools)
+	}
+
+	if name := s.ToolName("echo"); name != "snow_echo" {
+		t.Errorf("Expected ToolName 'snow_echo', got '%s'", name)
+	}
+
+	result := callTool(t, s, "snow_echo", map[string]interface{}{"message": "hi"})
+	if result.IsError || result.Content[0].Text != "Echo: hi" {
+		t.Errorf("Expected echo result, got %+v", result)
+	}
+
+	result = callTool(t, s, "echo", nil)
+	if !result.IsError {
+		t.Errorf("Expected unprefixed name to be unknown, got %+v", result)
+	}
+}
+
+// TestToolCallCallback tests that the telemetry callback receives the call, its result, and the request ID
+func TestToolCallCallback(t *testing.T) {
+	s := newEchoServer(t, "")
+	var calls []ToolCallInfo
+	s.SetToolCallCallback(func(call ToolCallInfo) {
+		calls = append(calls, call)
+	})
+
+	callTool(t, s, "echo", map[string]interface{}{"message": "hi"})
+	if len(calls) != 1 {
+		t.Fatalf("Expected one callback, got %d", len(calls))
+	}
+	call := calls[0]
+	if call.Name != "echo" || !call.Success || call.RequestID != float64(1) || call.Result.Content[0].Text != "Echo: hi" || call.Arguments["message"] != "hi" {
+		t.Errorf("Unexpected tool call info %+v", call)
+	}
+
+	if _, err := s.CallTool(context.Background(), "echo", nil); err != nil || len(calls) != 2 || calls[1].RequestID != nil {
+		t.Errorf("Expected no request ID outside a tools/call request, got %+v (%v)", calls, err)
+	}
+}
+
+// TestRegisterAlias tests that deprecated aliases route to their target and are tracked
+func TestRegisterAlias(t *testing.T) {
+	s := newEchoServer(t, "")
+
+	var aliasCalls []string
+	s.SetAliasCallback(func(alias, target string) {
+		aliasCalls = append(aliasCalls, alias+"->"+target)
+	})
+
+	if err := s.RegisterAlias("old_echo", "echo"); err != nil {
+		t.Fatalf("RegisterAlias failed: %v", err)
+	}
+	if err := s.RegisterAlias("bad_alias", "missing"); err == nil {
+		t.Error("Expected error registering alias for missing tool")
+	}
+	if err := s.RegisterAlias("echo", "echo"); err == nil {
+		t.Error("Expected error registering alias that shadows a tool")
+	}
+
+	tools := s.handleListTools().Tools
+	if len(tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(tools))
+	}
+	if tools[1].Name != "old_echo" || !strings.HasPrefix(tools[1].Description, "[DEPRECATED: use echo]") {
+		t.Errorf("Expected deprecated alias listing, got %+v", tools[1])
+	}
+
+	result := callTool(t, s, "old_echo", map[string]interface{}{"message": "legacy"})
+	if result.IsError || result.Content[0].Text != "Echo: legacy" {
+		t.Errorf("Expected alias to route to echo, got %+v", result)
+	}
+
+	if usage := s.AliasUsage(); usage["old_echo"] != 1 {
+		t.Errorf("Expected alias usage 1, got %v", usage)
+	}
+	if len(aliasCalls) != 1 || aliasCalls[0] != "old_echo->echo" {
+		t.Errorf("Expected alias callback, got %v", aliasCalls)
+	}
+}
+
+// TestBatchRequests tests that batch arrays return a response per request, skipping notifications
+func TestBatchRequests(t *testing.T) {
+	s := newEchoServer(t, "")
+
+	batch := `[
+		{"jsonrpc": "2.0", "id": 1, "method": "ping"},
+		{"jsonrpc": "2.0", "method": "notifications/initialized"},
+		{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "echo", "arguments": {"message": "batched"}}},
+		42
+	]`
+	responses, ok := s.handlePayloadWithContext(context.Background(), []byte(batch)).([]*JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected batch response")
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(responses))
+	}
+	if responses[0].ID != float64(1) || responses[0].Error != nil {
+		t.Errorf("Expected ping response, got %+v", responses[0])
+	}
+	result, ok := responses[1].Result.(*CallToolResult)
+	if !ok || result.Content[0].Text != "Echo: batched" {
+		t.Errorf("Expected echo result, got %+v", responses[1])
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != InvalidRequest {
+		t.Errorf("Expected InvalidRequest for non-object entry, got %+v", responses[2])
+	}
+
+	if response := s.handlePayloadWithContext(context.Background(), []byte(`[{"jsonrpc": "2.0", "method": "notifications/initialized"}]`)); response != nil {
+		t.Errorf("Expected no response for notification-only batch, got %+v", response)
+	}
+
+	single, ok := s.handlePayloadWithContext(context.Background(), []byte("[]")).(*JSONRPCResponse)
+	if !ok || single.Error == nil || single.Error.Code != InvalidRequest {
+		t.Errorf("Expected InvalidRequest for empty batch, got %+v", single)
+	}
+}
+
+// TestRuntimeReadOnly tests that the runtime read-only switch blocks only tools without ReadOnlyHint
+func TestRuntimeReadOnly(t *testing.T) {
+	s := newEchoServer(t, "")
+	s.RegisterTool(Tool{
+		Name:        "lookup",
+		InputSchema: JSONSchema{Type: "object"},
+		Annotations: &ToolAnnotation{ReadOnlyHint: true},
+	}, func(args map[string]interface{}) (*CallToolResult, error) {
+		return &CallToolResult{Content: []ContentItem{{Type: "text", Text: "found"}}}, nil
+	})
+
+	var changes []bool
+	s.SetReadOnlyCallback(func(readOnly bool, source string) {
+		changes = append(changes, readOnly)
+	})
+
+	s.SetReadOnly(true, "test")
+	s.SetReadOnly(true, "test")
+	if !s.IsReadOnly() {
+		t.Fatal("Expected read-only mode to be enabled")
+	}
+
+	if result := callTool(t, s, "echo", nil); !result.IsError || !strings.Contains(result.Content[0].Text, "read-only") {
+		t.Errorf("Expected write tool to be blocked, got %+v", result)
+	}
+	if result := callTool(t, s, "lookup", nil); result.IsError {
+		t.Errorf("Expected read-only tool to run, got %+v", result)
+	}
+
+	s.SetReadOnly(false, "test")
+	if result := callTool(t, s, "echo", nil); result.IsError {
+		t.Errorf("Expected write tool to run after re-enabling writes, got %+v", result)
+	}
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("Expected callback for each change, got %v", changes)
+	}
+}
+
+// TestToolFilter tests that filtered tools are neither listed nor callable, and that the filter is consulted per request
+func TestToolFilter(t *testing.T) {
+	s := newEchoServer(t, "snow")
+	if err := s.RegisterAlias("old_echo", "echo"); err != nil {
+		t.Fatalf("RegisterAlias failed: %v", err)
+	}
+
+	allowed := false
+	s.SetToolFilter(func(name string) bool {
+		if name != "echo" {
+			t.Errorf("Expected the filter to see the unprefixed target name, got %s", name)
+		}
+		return allowed
+	})
+
+	if tools := s.handleListTools().Tools; len(tools) != 0 {
+		t.Errorf("Expected no tools to be listed, got %+v", tools)
+	}
+	for _, name := range []string{"snow_echo", "snow_old_echo"} {
+		if result := callTool(t, s, name, nil); !result.IsError || !strings.Contains(result.Content[0].Text, "not available in the current tool package") {
+			t.Errorf("Expected %s to be blocked, got %+v", name, result)
+		}
+	}
+
+	allowed = true
+	if tools := s.handleListTools().Tools; len(tools) != 2 {
+		t.Errorf("Expected tool and alias to be listed, got %+v", tools)
+	}
+	if result := callTool(t, s, "snow_old_echo", map[string]interface{}{"message": "hi"}); result.IsError {
+		t.Errorf("Expected alias to run once allowed, got %+v", result)
+	}
+}
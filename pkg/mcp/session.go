@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// HeaderSessionID is the HTTP header carrying the MCP session ID
+const HeaderSessionID = "Mcp-Session-Id"
+
+// DefaultSessionIdleTimeout is how long an HTTP session may stay idle before it expires
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// sessionContextKey is the context key for the HTTP session binding of the current request
+type sessionContextKey struct{}
+
+// session tracks the lifecycle state of an HTTP client
+type session struct {
+	id          string
+	tokenKey    string
+	initialized bool
+	clientInfo  ClientInfo
+	lastSeen    time.Time
+}
+
+// sessionStore tracks HTTP sessions by session ID, and by auth token for
+// clients that don't echo the Mcp-Session-Id header. Clients often share one
+// token (MCP_AUTH_TOKEN), so the token only names its latest session.
+type sessionStore struct {
+	mu          sync.Mutex
+	sessions    map[string]*session
+	tokens      map[string]string
+	idleTimeout time.Duration
+}
+
+func newSessionStore(idleTimeout time.Duration) *sessionStore {
+	return &sessionStore{
+		sessions:    make(map[string]*session),
+		tokens:      make(map[string]string),
+		idleTimeout: idleTimeout,
+	}
+}
+
+// setIdleTimeout changes the idle timeout applied to all sessions
+func (st *sessionStore) setIdleTimeout(d time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.idleTimeout = d
+}
+
+// lookup returns the live session for the given session ID, or for the token
+// when no session ID is provided. It returns nil if no such session exists.
+func (st *sessionStore) lookup(id, token string) *session {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.expireLocked(time.Now())
+
+	if id == "" && token != "" {
+		id = st.tokens[hashToken(token)]
+	}
+	sess, ok := st.sessions[id]
+	if !ok {
+		return nil
+	}
+	sess.lastSeen = time.Now()
+	return sess
+}
+
+// create starts a new session, bound to the token when one is provided. Earlier
+// sessions of the token stay live for the clients that send their IDs.
+func (st *sessionStore) create(token string) *session {
+	sess := &session{id: newSessionID(), lastSeen: time.Now()}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if token != "" {
+		sess.tokenKey = hashToken(token)
+		st.tokens[sess.tokenKey] = sess.id
+	}
+	st.sessions[sess.id] = sess
+	return sess
+}
+
+// remove terminates a session, returning false if it did not exist
+func (st *sessionStore) remove(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	sess, ok := st.sessions[id]
+	if !ok {
+		return false
+	}
+	st.removeLocked(sess)
+	return true
+}
+
+// markInitialized records that the session completed the initialize handshake
+func (st *sessionStore) markInitialized(sess *session, info ClientInfo) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	sess.initialized = true
+	sess.clientInfo = info
+}
+
+// isInitialized reports whether the session completed the initialize handshake
+func (st *sessionStore) isInitialized(sess *session) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return sess.initialized
+}
+
+// count returns the number of live sessions
+func (st *sessionStore) count() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.expireLocked(time.Now())
+	return len(st.sessions)
+}
+
+// expireLocked drops sessions idle for longer than the timeout
+func (st *sessionStore) expireLocked(now time.Time) {
+	if st.idleTimeout <= 0 {
+		return
+	}
+	cutoff := now.Add(-st.idleTimeout)
+	for _, sess := range st.sessions {
+		if sess.lastSeen.Before(cutoff) {
+			st.removeLocked(sess)
+		}
+	}
+}
+
+func (st *sessionStore) removeLocked(sess *session) {
+	delete(st.sessions, sess.id)
+	if sess.tokenKey != "" && st.tokens[sess.tokenKey] == sess.id {
+		delete(st.tokens, sess.tokenKey)
+	}
+}
+
+// hashToken derives a map key from an auth token so raw tokens aren't retained
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSessionID generates a random, globally unique session ID
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// sessionBinding carries the HTTP session state of a single request. The
+// session is nil until the request resolves or starts one. sessionID is the
+// Mcp-Session-Id the request sent, if any.
+type sessionBinding struct {
+	token     string
+	sessionID string
+	sess      *session
+}
+
+// contextWithSessionBinding adds the HTTP session binding to the request context
+func contextWithSessionBinding(ctx context.Context, binding *sessionBinding) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, binding)
+}
+
+// sessionBindingFromContext retrieves the HTTP session binding from the request context
+func sessionBindingFromContext(ctx context.Context) *sessionBinding {
+	if binding, ok := ctx.Value(sessionContextKey{}).(*sessionBinding); ok {
+		return binding
+	}
+	return nil
+}
+
+// SessionIDFromContext returns the HTTP session ID of the request being handled,
+// or "" outside an HTTP session (e.g., in stdio mode)
+func SessionIDFromContext(ctx context.Context) string {
+	if binding := sessionBindingFromContext(ctx); binding != nil && binding.sess != nil {
+		return binding.sess.id
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// postRPCWithToken sends a JSON-RPC request to the test server with an optional auth token and session ID
+func postRPCWithToken(t *testing.T, url, token, sessionID, method string, params map[string]interface{}) (*http.Response, JSONRPCResponse) {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if sessionID != "" {
+		req.Header.Set(HeaderSessionID, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send POST request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result JSONRPCResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return resp, result
+}
+
+// postRPC sends a JSON-RPC request to the test server with an optional session ID
+func postRPC(t *testing.T, url, sessionID, method string, params map[string]interface{}) (*http.Response, JSONRPCResponse) {
+	t.Helper()
+	return postRPCWithToken(t, url, "", sessionID, method, params)
+}
+
+// TestHTTPSessionStrictLifecycle tests that strict mode rejects tools/call before initialize
+func TestHTTPSessionStrictLifecycle(t *testing.T) {
+	s := newEchoServer(t, "")
+	s.SetStrictLifecycle(true)
+	ts := httptest.NewServer(s.httpHandler(nil))
+	defer ts.Close()
+
+	callParams := map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": "hi"}}
+
+	_, result := postRPC(t, ts.URL, "", "tools/call", callParams)
+	if result.Error == nil || result.Error.Code != InvalidRequest {
+		t.Fatalf("Expected InvalidRequest before initialize, got %+v", result)
+	}
+
+	_, result = postRPC(t, ts.URL, "", "ping", nil)
+	if result.Error != nil {
+		t.Errorf("Expected ping to be allowed before initialize, got %+v", result.Error)
+	}
+
+	resp, result := postRPC(t, ts.URL, "", "initialize", map[string]interface{}{
+		"clientInfo": map[string]interface{}{"name": "test-client"},
+	})
+	sessionID := resp.Header.Get(HeaderSessionID)
+	if result.Error != nil || sessionID == "" {
+		t.Fatalf("Expected initialize to return a session ID, got %q (%+v)", sessionID, result.Error)
+	}
+
+	_, result = postRPC(t, ts.URL, sessionID, "tools/call", callParams)
+	if result.Error != nil {
+		t.Errorf("Expected tools/call to succeed after initialize, got %+v", result.Error)
+	}
+
+	resp, _ = postRPC(t, ts.URL, "unknown-session", "tools/call", callParams)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown session, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(HeaderSessionID, sessionID)
+	delResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send DELETE request: %v", err)
+	}
+	delResp.Body.Close()
+	if delResp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204 terminating session, got %d", delResp.StatusCode)
+	}
+
+	resp, _ = postRPC(t, ts.URL, sessionID, "tools/call", callParams)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for terminated session, got %d", resp.StatusCode)
+	}
+}
+
+// TestHTTPSessionLenientLifecycle tests that tools/call works without initialize by default
+func TestHTTPSessionLenientLifecycle(t *testing.T) {
+	s := newEchoServer(t, "")
+	ts := httptest.NewServer(s.httpHandler(nil))
+	defer ts.Close()
+
+	resp, result := postRPC(t, ts.URL, "stale-session", "tools/call", map[string]interface{}{"name": "echo"})
+	if resp.StatusCode != http.StatusOK || result.Error != nil {
+		t.Errorf("Expected tools/call to succeed without a session, got %d (%+v)", resp.StatusCode, result.Error)
+	}
+	if s.sessions.count() != 0 {
+		t.Errorf("Expected no sessions to be created, got %d", s.sessions.count())
+	}
+}
+
+// TestSessionStoreTokenAffinity tests that sessions are found by token when no ID is sent
+func TestSessionStoreTokenAffinity(t *testing.T) {
+	store := newSessionStore(time.Minute)
+
+	first := store.create("Bearer abc")
+	if got := store.lookup("", "Bearer abc"); got != first {
+		t.Errorf("Expected token lookup to return the session, got %+v", got)
+	}
+
+	second := store.create("Bearer abc")
+	if store.lookup(first.id, "") != first {
+		t.Error("Expected another session with the same token to leave the first one live")
+	}
+	if got := store.lookup("", "Bearer abc"); got != second {
+		t.Errorf("Expected token lookup to return the new session, got %+v", got)
+	}
+	if store.lookup("", "Bearer other") != nil {
+		t.Error("Expected no session for an unknown token")
+	}
+}
+
+// TestSessionStoreExpiry tests that idle sessions expire
+func TestSessionStoreExpiry(t *testing.T) {
+	store := newSessionStore(time.Minute)
+
+	sess := store.create("Bearer abc")
+	sess.lastSeen = time.Now().Add(-2 * time.Minute)
+
+	if store.lookup(sess.id, "") != nil {
+		t.Error("Expected idle session to expire")
+	}
+	if store.lookup("", "Bearer abc") != nil {
+		t.Error("Expected expired session to be unbound from its token")
+	}
+	if store.count() != 0 {
+		t.Errorf("Expected no live sessions, got %d", store.count())
+	}
+}
+
+// TestHTTPSessionSharedToken tests that clients sharing an auth token keep their own sessions
+func TestHTTPSessionSharedToken(t *testing.T) {
+	t.Setenv("MCP_AUTH_TOKEN", "shared-token")
+	s := newEchoServer(t, "")
+	s.SetStrictLifecycle(true)
+	ts := httptest.NewServer(s.httpHandler(nil))
+	defer ts.Close()
+
+	initParams := map[string]interface{}{"clientInfo": map[string]interface{}{"name": "test-client"}}
+	callParams := map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": "hi"}}
+
+	resp, _ := postRPCWithToken(t, ts.URL, "shared-token", "", "initialize", initParams)
+	first := resp.Header.Get(HeaderSessionID)
+	resp, _ = postRPCWithToken(t, ts.URL, "shared-token", "", "initialize", initParams)
+	second := resp.Header.Get(HeaderSessionID)
+	if first == "" || second == "" || first == second {
+		t.Fatalf("Expected two distinct sessions, got %q and %q", first, second)
+	}
+
+	for _, id := range []string{first, second} {
+		resp, result := postRPCWithToken(t, ts.URL, "shared-token", id, "tools/call", callParams)
+		if resp.StatusCode != http.StatusOK || result.Error != nil {
+			t.Errorf("Expected session %s to stay usable, got %d (%+v)", id, resp.StatusCode, result.Error)
+		}
+	}
+	if s.sessions.count() != 2 {
+		t.Errorf("Expected two live sessions, got %d", s.sessions.count())
+	}
+
+	// A client without a session ID falls back to the token's latest session
+	resp, _ = postRPCWithToken(t, ts.URL, "shared-token", "", "tools/call", callParams)
+	if got := resp.Header.Get(HeaderSessionID); got != second {
+		t.Errorf("Expected the token fallback to use the latest session %s, got %s", second, got)
+	}
+
+	// Re-initializing a session by its ID replaces only that session
+	resp, _ = postRPCWithToken(t, ts.URL, "shared-token", first, "initialize", initParams)
+	if resp.Header.Get(HeaderSessionID) == first || s.sessions.lookup(first, "") != nil || s.sessions.lookup(second, "") == nil {
+		t.Errorf("Expected only the re-initialized session to be replaced")
+	}
+}
@@ -10,7 +10,7 @@ type JSONRPCRequest struct {
 
 type JSONRPCResponse struct {
 	JSONRPC string        `json:"jsonrpc"`
-	ID      interface{}   `json:"id,omitempty"`
+	ID      interface{}   `json:"id"`
 	Result  interface{}   `json:"result,omitempty"`
 	Error   *JSONRPCError `json:"error,omitempty"`
 }
@@ -91,11 +91,12 @@ type ToolAnnotation struct {
 }
 
 type JSONSchema struct {
-	Type        string              `json:"type"`
-	Properties  map[string]Property `json:"properties,omitempty"`
-	Required    []string            `json:"required,omitempty"`
-	Description string              `json:"description,omitempty"`
-	Items       *Property           `json:"items,omitempty"`
+	Type        string                   `json:"type"`
+	Properties  map[string]Property      `json:"properties,omitempty"`
+	Required    []string                 `json:"required,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Items       *Property                `json:"items,omitempty"`
+	Examples    []map[string]interface{} `json:"examples,omitempty"` // example argument payloads
 }
 
 type Property struct {
@@ -107,6 +108,9 @@ type Property struct {
 	Properties  map[string]Property `json:"properties,omitempty"`
 	Minimum     *float64            `json:"minimum,omitempty"`
 	Maximum     *float64            `json:"maximum,omitempty"`
+	MaxLength   int                 `json:"maxLength,omitempty"`
+	Pattern     string              `json:"pattern,omitempty"`
+	Format      string              `json:"format,omitempty"` // "date" (YYYY-MM-DD) or "date-time" (YYYY-MM-DD HH:MM:SS)
 }
 
 type ListToolsResult struct {
@@ -119,8 +123,9 @@ type CallToolParams struct {
 }
 
 type CallToolResult struct {
-	Content []ContentItem `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content []ContentItem          `json:"content"`
+	IsError bool                   `json:"isError,omitempty"`
+	Meta    map[string]interface{} `json:"_meta,omitempty"`
 }
 
 type ContentItem struct {
@@ -147,10 +152,11 @@ type ReadResourceResult struct {
 }
 
 type ResourceContent struct {
-	URI      string `json:"uri"`
-	MimeType string `json:"mimeType,omitempty"`
-	Text     string `json:"text,omitempty"`
-	Blob     string `json:"blob,omitempty"`
+	URI      string                 `json:"uri"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Text     string                 `json:"text,omitempty"`
+	Blob     string                 `json:"blob,omitempty"`
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
 }
 
 // Prompt types
@@ -187,4 +193,37 @@ const (
 	MethodNotFound = -32601
 	InvalidParams  = -32602
 	InternalError  = -32603
+
+	// ResourceNotFound is the MCP error code for resources/read of an unknown URI
+	ResourceNotFound = -32002
 )
+
+// ToolErrorCode classifies a failed tool call, so clients can branch on the kind of
+// failure instead of matching messages. JSON error results carry it as "code"; text
+// error results as _meta.code.
+type ToolErrorCode string
+
+// Tool error codes
+const (
+	// ErrorNotFound is a record, tool, or other object that doesn't exist
+	ErrorNotFound ToolErrorCode = "NOT_FOUND"
+	// ErrorAmbiguousID is a name or number matching more than one record
+	ErrorAmbiguousID ToolErrorCode = "AMBIGUOUS_ID"
+	// ErrorPermissionDenied is a call the caller, or the server's mode, doesn't allow
+	ErrorPermissionDenied ToolErrorCode = "PERMISSION_DENIED"
+	// ErrorValidationFailed is an argument or record the tool or instance rejected
+	ErrorValidationFailed ToolErrorCode = "VALIDATION_FAILED"
+	// ErrorRateLimited is a call refused by a rate limit or quota
+	ErrorRateLimited ToolErrorCode = "RATE_LIMITED"
+	// ErrorUpstreamError is a failure of the instance or the connection to it
+	ErrorUpstreamError ToolErrorCode = "UPSTREAM_ERROR"
+)
+
+// ToolError returns an error result with message and code
+func ToolError(code ToolErrorCode, message string) *CallToolResult {
+	return &CallToolResult{
+		Content: []ContentItem{{Type: "text", Text: message}},
+		IsError: true,
+		Meta:    map[string]interface{}{"code": code},
+	}
+}
@@ -0,0 +1,151 @@
+package servicenow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrAttachmentRejected is returned for an upload the attachment policy does not allow
+// (too large, or of a MIME type outside SERVICENOW_ATTACHMENT_TYPES); nothing was sent
+var ErrAttachmentRejected = errors.New("attachment rejected")
+
+// checkAttachment applies the configured attachment policy to an upload
+func (c *Config) checkAttachment(fileName, contentType string, size int) error {
+	if c.MaxAttachmentBytes > 0 && int64(size) > c.MaxAttachmentBytes {
+		return fmt.Errorf("%w: %s is %d bytes, more than the limit of %d bytes", ErrAttachmentRejected, fileName, size, c.MaxAttachmentBytes)
+	}
+	if len(c.AllowedAttachmentTypes) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+	for _, allowed := range c.AllowedAttachmentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s has MIME type %s; allowed types are %s", ErrAttachmentRejected, fileName, mediaType, strings.Join(c.AllowedAttachmentTypes, ", "))
+}
+
+// UploadAttachment uploads a file and attaches it to a record
+func (c *Client) UploadAttachment(tableName, tableSysID, fileName, contentType string, data []byte) (map[string]interface{}, error) {
+	return c.UploadAttachmentWithContext(context.Background(), tableName, tableSysID, fileName, contentType, data)
+}
+
+// UploadAttachmentWithContext uploads a file and attaches it to a record with context support.
+// Uploads the attachment policy does not allow return ErrAttachmentRejected.
+func (c *Client) UploadAttachmentWithContext(ctx context.Context, tableName, tableSysID, fileName, contentType string, data []byte) (map[string]interface{}, error) {
+	if err := c.config.checkAttachment(fileName, contentType, len(data)); err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Set("table_name", tableName)
+	values.Set("table_sys_id", tableSysID)
+	values.Set("file_name", fileName)
+	apiURL := fmt.Sprintf("%s/attachment/file?%s", c.config.APIURL(), values.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	headers, err := c.GetHeadersWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headers: %w", err)
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	httpClient, err := c.writeClient(ctx, "POST", "/attachment/file")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	c.recordUsage(ctx, resp.Header)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result map[string]interface{}
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// DownloadAttachment downloads the content of an attachment, returning at most maxBytes
+// bytes along with the content type reported by the instance
+func (c *Client) DownloadAttachment(attachmentSysID string, maxBytes int64) ([]byte, string, error) {
+	return c.DownloadAttachmentWithContext(context.Background(), attachmentSysID, maxBytes)
+}
+
+// DownloadAttachmentWithContext downloads the content of an attachment with context support.
+// Attachments larger than maxBytes return an error rather than a truncated file.
+func (c *Client) DownloadAttachmentWithContext(ctx context.Context, attachmentSysID string, maxBytes int64) ([]byte, string, error) {
+	apiURL := fmt.Sprintf("%s/attachment/%s/file", c.config.APIURL(), url.PathEscape(attachmentSysID))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	headers, err := c.GetHeadersWithContext(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get headers: %w", err)
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	c.recordUsage(ctx, resp.Header)
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, "", &APIError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, "", fmt.Errorf("attachment exceeds %d bytes", maxBytes)
+	}
+
+	return data, resp.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,61 @@
+package servicenow
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestUploadAttachmentPolicy tests that uploads too large or of a type outside the allowed list are rejected before they are sent
+func TestUploadAttachmentPolicy(t *testing.T) {
+	var uploads []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/now/attachment/file" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		uploads = append(uploads, r.URL.Query().Get("file_name"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"sys_id": "att1"}}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		InstanceURL:            ts.URL,
+		Timeout:                5,
+		Auth:                   AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "integration", Password: "secret"}},
+		MaxAttachmentBytes:     10,
+		AllowedAttachmentTypes: []string{"text/plain", "image/*"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for _, upload := range []struct {
+		fileName, contentType, data string
+		rejected                    string
+	}{
+		{"notes.txt", "text/plain; charset=utf-8", "hello", ""},
+		{"photo.png", "image/png", "png", ""},
+		{"big.txt", "text/plain", "more than ten bytes", "more than the limit of 10 bytes"},
+		{"setup.exe", "application/x-msdownload", "MZ", "allowed types are text/plain, image/*"},
+		{"unknown", "", "?", "MIME type application/octet-stream"},
+	} {
+		_, err := client.UploadAttachment("incident", "inc1", upload.fileName, upload.contentType, []byte(upload.data))
+		if upload.rejected == "" {
+			if err != nil {
+				t.Errorf("Expected %s to be uploaded, got %v", upload.fileName, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrAttachmentRejected) || !strings.Contains(err.Error(), upload.rejected) {
+			t.Errorf("Expected %s to be rejected with %q, got %v", upload.fileName, upload.rejected, err)
+		}
+	}
+	if strings.Join(uploads, ",") != "notes.txt,photo.png" {
+		t.Errorf("Expected only the allowed files to be sent, got %v", uploads)
+	}
+}
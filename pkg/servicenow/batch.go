@@ -0,0 +1,148 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// MaxBatchRequests is the number of requests sent per Batch API call; larger
+// batches are split into several calls
+const MaxBatchRequests = 50
+
+// BatchRequest is one request of a Batch API call
+type BatchRequest struct {
+	Method string
+	// Endpoint is relative to the API URL, as for the other client methods (e.g., /table/incident/<sys_id>)
+	Endpoint string
+	Body     interface{}
+}
+
+// BatchResponse is the outcome of one BatchRequest. Err is set when the request
+// failed or was not serviced; Result holds the parsed response body otherwise.
+type BatchResponse struct {
+	StatusCode int
+	Result     map[string]interface{}
+	Err        error
+}
+
+// ErrBatchUnavailable is returned when the instance rejects the Batch API itself (e.g.,
+// it is disabled or the account may not call it), so none of the requests were run
+var ErrBatchUnavailable = errors.New("batch API unavailable")
+
+// batchHeaders are sent with every request of a batch
+var batchHeaders = []map[string]string{
+	{"name": "Content-Type", "value": "application/json"},
+	{"name": "Accept", "value": "application/json"},
+}
+
+// Batch runs several requests through the Batch API (/api/now/v1/batch), one round
+// trip per MaxBatchRequests requests. Responses are returned in request order.
+func (c *Client) Batch(requests []BatchRequest) ([]BatchResponse, error) {
+	return c.BatchWithContext(context.Background(), requests)
+}
+
+// BatchWithContext runs several requests through the Batch API with context support.
+// An error is returned only when a batch call itself fails; the responses of earlier
+// calls are returned with it.
+func (c *Client) BatchWithContext(ctx context.Context, requests []BatchRequest) ([]BatchResponse, error) {
+	apiURL, err := url.Parse(c.config.APIURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance URL: %w", err)
+	}
+
+	responses := make([]BatchResponse, 0, len(requests))
+	for start := 0; start < len(requests); start += MaxBatchRequests {
+		end := start + MaxBatchRequests
+		if end > len(requests) {
+			end = len(requests)
+		}
+		chunk, err := c.batch(ctx, apiURL.Path, requests[start:end])
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, chunk...)
+	}
+	return responses, nil
+}
+
+// batch sends one Batch API call. Request IDs are their index in requests.
+func (c *Client) batch(ctx context.Context, apiPath string, requests []BatchRequest) ([]BatchResponse, error) {
+	restRequests := make([]map[string]interface{}, 0, len(requests))
+	for i, request := range requests {
+		restRequest := map[string]interface{}{
+			"id":                       strconv.Itoa(i),
+			"method":                   request.Method,
+			"url":                      apiPath + request.Endpoint,
+			"headers":                  batchHeaders,
+			"exclude_response_headers": true,
+		}
+		if request.Body != nil {
+			body, err := json.Marshal(request.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			}
+			restRequest["body"] = base64.StdEncoding.EncodeToString(body)
+		}
+		restRequests = append(restRequests, restRequest)
+	}
+
+	result, err := c.RequestWithContext(ctx, "POST", "/v1/batch", map[string]interface{}{
+		"batch_request_id": "1",
+		"rest_requests":    restRequests,
+	})
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusForbidden || apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed) {
+		return nil, fmt.Errorf("%w: %v", ErrBatchUnavailable, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]BatchResponse, len(requests))
+	for i := range responses {
+		responses[i].Err = fmt.Errorf("request not serviced by the batch")
+	}
+	serviced, _ := result["serviced_requests"].([]interface{})
+	for _, item := range serviced {
+		served, _ := item.(map[string]interface{})
+		i, err := strconv.Atoi(fmt.Sprint(served["id"]))
+		if err != nil || i < 0 || i >= len(responses) {
+			continue
+		}
+		responses[i] = parseBatchResponse(served)
+	}
+	return responses, nil
+}
+
+// parseBatchResponse decodes a serviced request of a Batch API response
+func parseBatchResponse(served map[string]interface{}) BatchResponse {
+	status, _ := served["status_code"].(float64)
+	response := BatchResponse{StatusCode: int(status)}
+
+	var body []byte
+	if encoded, _ := served["body"].(string); encoded != "" {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			response.Err = fmt.Errorf("failed to decode response body: %w", err)
+			return response
+		}
+		body = decoded
+	}
+
+	if response.StatusCode >= 400 {
+		response.Err = &APIError{StatusCode: response.StatusCode, Body: string(body)}
+		return response
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &response.Result); err != nil {
+			response.Err = fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return response
+}
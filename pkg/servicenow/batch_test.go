@@ -0,0 +1,113 @@
+package servicenow
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestBatch tests that requests are split into Batch API calls and responses matched to requests
+func TestBatch(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/now/v1/batch" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls++
+
+		var batch struct {
+			RestRequests []struct {
+				ID     string `json:"id"`
+				Method string `json:"method"`
+				URL    string `json:"url"`
+				Body   string `json:"body"`
+			} `json:"rest_requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Fatalf("Failed to decode batch: %v", err)
+		}
+
+		serviced := []interface{}{}
+		unserviced := []interface{}{}
+		// Answer in reverse order; responses are matched by ID
+		for i := len(batch.RestRequests) - 1; i >= 0; i-- {
+			request := batch.RestRequests[i]
+			if request.Method != http.MethodPatch || !strings.HasPrefix(request.URL, "/api/now/table/incident/") {
+				t.Errorf("Unexpected batched request %s %s", request.Method, request.URL)
+			}
+			sysID := strings.TrimPrefix(request.URL, "/api/now/table/incident/")
+			switch sysID {
+			case "missing":
+				serviced = append(serviced, map[string]interface{}{
+					"id": request.ID, "status_code": 404,
+					"body": base64.StdEncoding.EncodeToString([]byte(`{"error":{"message":"No Record found"}}`)),
+				})
+			case "skipped":
+				unserviced = append(unserviced, request.ID)
+			default:
+				body, _ := base64.StdEncoding.DecodeString(request.Body)
+				if string(body) != `{"state":"2"}` {
+					t.Errorf("Expected the update body, got %s", body)
+				}
+				serviced = append(serviced, map[string]interface{}{
+					"id": request.ID, "status_code": 200,
+					"body": base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"result":{"sys_id":%q}}`, sysID))),
+				})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"batch_request_id":    "1",
+			"serviced_requests":   serviced,
+			"unserviced_requests": unserviced,
+		})
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		InstanceURL: ts.URL,
+		Timeout:     5,
+		Auth:        AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "u", Password: "p"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var requests []BatchRequest
+	for i := 0; i < MaxBatchRequests+2; i++ {
+		sysID := fmt.Sprintf("inc%d", i)
+		switch i {
+		case 1:
+			sysID = "missing"
+		case MaxBatchRequests + 1:
+			sysID = "skipped"
+		}
+		requests = append(requests, BatchRequest{Method: http.MethodPatch, Endpoint: "/table/incident/" + sysID, Body: map[string]string{"state": "2"}})
+	}
+
+	responses, err := client.Batch(requests)
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if calls != 2 || len(responses) != len(requests) {
+		t.Fatalf("Expected %d responses from 2 calls, got %d from %d", len(requests), len(responses), calls)
+	}
+	if responses[0].Err != nil || responses[0].Result["result"].(map[string]interface{})["sys_id"] != "inc0" {
+		t.Errorf("Expected the first update to succeed, got %+v", responses[0])
+	}
+	if responses[1].StatusCode != 404 || responses[1].Err == nil {
+		t.Errorf("Expected the missing record to fail with 404, got %+v", responses[1])
+	}
+	if responses[MaxBatchRequests].Err != nil {
+		t.Errorf("Expected the first request of the second call to succeed, got %+v", responses[MaxBatchRequests])
+	}
+	if responses[MaxBatchRequests+1].Err == nil {
+		t.Errorf("Expected the unserviced request to fail, got %+v", responses[MaxBatchRequests+1])
+	}
+}
@@ -0,0 +1,105 @@
+package servicenow
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached lookups are reused unless SERVICENOW_CACHE_TTL sets otherwise
+const DefaultCacheTTL = 10 * time.Minute
+
+// maxCacheEntries bounds the cache; when it is full, expired entries are dropped, and then all of them
+const maxCacheEntries = 10000
+
+// Cache keeps the results of frequently repeated lookups (users and groups resolved from
+// names, record numbers resolved to sys_ids, choice lists) in memory for a TTL. Keys are
+// prefixed with the kind of lookup (e.g., "sys_user:|abel.tuter"), so one kind can be
+// cleared on its own. Callers scope keys to the credentials a lookup ran with. A cache
+// with a TTL of 0 or less keeps nothing. It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	hits    int
+	misses  int
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// CacheStats describes the contents and use of a cache
+type CacheStats struct {
+	Entries int    `json:"entries"`
+	Hits    int    `json:"hits"`
+	Misses  int    `json:"misses"`
+	TTL     string `json:"ttl"`
+}
+
+// NewCache creates a cache whose entries expire after ttl
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+// Get returns the value cached under key, if it has not expired
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	return entry.value, true
+}
+
+// Set caches value under key for the cache's TTL
+func (c *Cache) Set(key string, value interface{}) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= maxCacheEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			c.entries = map[string]cacheEntry{}
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+// Clear removes the entries whose keys start with prefix ("" removes every entry) and
+// returns how many were removed
+func (c *Cache) Clear(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if prefix == "" {
+		removed := len(c.entries)
+		c.entries = map[string]cacheEntry{}
+		return removed
+	}
+	removed := 0
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Stats returns the number of cached entries and the hits and misses since the cache was created
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Entries: len(c.entries), Hits: c.hits, Misses: c.misses, TTL: c.ttl.String()}
+}
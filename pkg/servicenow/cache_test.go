@@ -0,0 +1,37 @@
+package servicenow
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCache tests that entries expire after the TTL, clear by prefix, and are not kept when the cache is disabled
+func TestCache(t *testing.T) {
+	cache := NewCache(50 * time.Millisecond)
+	cache.Set("sys_user:abel.tuter", "u1")
+	cache.Set("sys_user_group:network", "g1")
+
+	if value, ok := cache.Get("sys_user:abel.tuter"); !ok || value != "u1" {
+		t.Errorf("Expected the cached user, got %v %v", value, ok)
+	}
+	if removed := cache.Clear("sys_user:"); removed != 1 {
+		t.Errorf("Expected one entry cleared, got %d", removed)
+	}
+	if _, ok := cache.Get("sys_user:abel.tuter"); ok {
+		t.Error("Expected the cleared entry to be gone")
+	}
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 entry, 1 hit, and 1 miss, got %+v", stats)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := cache.Get("sys_user_group:network"); ok {
+		t.Error("Expected the entry to expire after the TTL")
+	}
+
+	disabled := NewCache(-1)
+	disabled.Set("sys_user:abel.tuter", "u1")
+	if _, ok := disabled.Get("sys_user:abel.tuter"); ok {
+		t.Error("Expected a disabled cache to keep nothing")
+	}
+}
@@ -13,7 +13,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/logging"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/logging"
 )
 
 // Client represents a ServiceNow API client
@@ -26,6 +26,18 @@ type Client struct {
 	token     string
 	tokenType string
 	tokenMu   sync.RWMutex
+
+	// Impersonation sessions reused across writes, by caller and user
+	sessions        map[string]*impersonationSession
+	retiredSessions []*impersonationSession
+	sessionsMu      sync.Mutex
+
+	// Usage headers from the most recent response
+	lastUsage Usage
+	usageMu   sync.Mutex
+
+	// Repeated lookups shared by the tools (SERVICENOW_CACHE_TTL)
+	cache *Cache
 }
 
 // ClientOption is a functional option for the Client
@@ -51,6 +63,12 @@ func NewClient(config *Config, opts ...ClientOption) (*Client, error) {
 		},
 	}
 
+	ttl := config.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+	client.cache = NewCache(ttl)
+
 	for _, opt := range opts {
 		opt(client)
 	}
@@ -256,8 +274,18 @@ func (c *Client) Request(method, endpoint string, body interface{}) (map[string]
 
 // RequestWithContext makes an HTTP request to the ServiceNow API with context support
 func (c *Client) RequestWithContext(ctx context.Context, method, endpoint string, body interface{}) (map[string]interface{}, error) {
-	apiURL := fmt.Sprintf("%s%s", c.config.APIURL(), endpoint)
+	return c.request(ctx, method, fmt.Sprintf("%s%s", c.config.APIURL(), endpoint), endpoint, body)
+}
 
+// InstanceRequestWithContext makes an HTTP request to a path of the instance outside
+// the /api/now namespace, such as a scripted REST API (e.g., /api/acme/mcp_script/run)
+func (c *Client) InstanceRequestWithContext(ctx context.Context, method, path string, body interface{}) (map[string]interface{}, error) {
+	return c.request(ctx, method, strings.TrimSuffix(c.config.InstanceURL, "/")+path, path, body)
+}
+
+// request sends a JSON request to apiURL and parses the JSON response. endpoint
+// names the request in impersonation audit logs.
+func (c *Client) request(ctx context.Context, method, apiURL, endpoint string, body interface{}) (map[string]interface{}, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		bodyBytes, err := json.Marshal(body)
@@ -281,11 +309,19 @@ func (c *Client) RequestWithContext(ctx context.Context, method, endpoint string
 		req.Header.Set(k, v)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	httpClient := c.httpClient
+	if method != http.MethodGet {
+		if httpClient, err = c.writeClient(ctx, method, endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	c.recordUsage(ctx, resp.Header)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -293,7 +329,7 @@ func (c *Client) RequestWithContext(ctx context.Context, method, endpoint string
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result map[string]interface{}
@@ -306,6 +342,16 @@ func (c *Client) RequestWithContext(ctx context.Context, method, endpoint string
 	return result, nil
 }
 
+// APIError is an error response from the ServiceNow API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+}
+
 // Get makes a GET request to the ServiceNow API
 func (c *Client) Get(endpoint string, params map[string]string) (map[string]interface{}, error) {
 	return c.GetWithContext(context.Background(), endpoint, params)
@@ -313,6 +359,18 @@ func (c *Client) Get(endpoint string, params map[string]string) (map[string]inte
 
 // GetWithContext makes a GET request to the ServiceNow API with context support
 func (c *Client) GetWithContext(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, error) {
+	result, _, err := c.get(ctx, endpoint, c.withQueryHints(endpoint, params, false))
+	return result, err
+}
+
+// GetWithHeaders makes a GET request and returns the parsed body along with the
+// response headers (e.g., X-Total-Count and Link for paging)
+func (c *Client) GetWithHeaders(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, http.Header, error) {
+	return c.get(ctx, endpoint, c.withQueryHints(endpoint, params, true))
+}
+
+// get sends a GET request with params as given
+func (c *Client) get(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, http.Header, error) {
 	apiURL := fmt.Sprintf("%s%s", c.config.APIURL(), endpoint)
 
 	if len(params) > 0 {
@@ -325,12 +383,12 @@ func (c *Client) GetWithContext(ctx context.Context, endpoint string, params map
 
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	headers, err := c.GetHeadersWithContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get headers: %w", err)
+		return nil, nil, fmt.Errorf("failed to get headers: %w", err)
 	}
 
 	for k, v := range headers {
@@ -339,27 +397,28 @@ func (c *Client) GetWithContext(ctx context.Context, endpoint string, params map
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	c.recordUsage(ctx, resp.Header)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result map[string]interface{}
 	if len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, &result); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse response: %w", err)
 		}
 	}
 
-	return result, nil
+	return result, resp.Header, nil
 }
 
 // Post makes a POST request to the ServiceNow API
@@ -406,3 +465,8 @@ func (c *Client) DeleteWithContext(ctx context.Context, endpoint string) (map[st
 func (c *Client) Config() *Config {
 	return c.config
 }
+
+// Cache returns the client's lookup cache
+func (c *Client) Cache() *Cache {
+	return c.cache
+}
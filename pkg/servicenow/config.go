@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // AuthType represents the authentication type for ServiceNow
@@ -51,6 +52,24 @@ type Config struct {
 	Auth        AuthConfig
 	Debug       bool
 	Timeout     int
+
+	// Impersonation runs writes as the conversing user (from the request, or
+	// ImpersonateUser) instead of the integration account. The integration
+	// user needs the impersonator role.
+	Impersonation   bool
+	ImpersonateUser string
+
+	// Attachment upload policy: the largest upload in bytes (0 for no limit), and the
+	// MIME types uploads may have (e.g., "application/pdf" or "image/*"; empty allows any)
+	MaxAttachmentBytes     int64
+	AllowedAttachmentTypes []string
+
+	// CacheTTL is how long repeated lookups are cached: 0 uses DefaultCacheTTL, and a
+	// negative TTL disables the cache
+	CacheTTL time.Duration
+
+	// QueryHints are Table API flags added to reads on large or busy tables
+	QueryHints QueryHints
 }
 
 // APIURL returns the base API URL for ServiceNow
@@ -86,6 +105,41 @@ func LoadConfigFromEnv() (*Config, error) {
 		Auth: AuthConfig{
 			Type: authType,
 		},
+		Impersonation:   strings.ToLower(os.Getenv("SERVICENOW_IMPERSONATION")) == "true",
+		ImpersonateUser: os.Getenv("SERVICENOW_IMPERSONATE_USER"),
+	}
+
+	if max := os.Getenv("SERVICENOW_ATTACHMENT_MAX_BYTES"); max != "" {
+		parsed, err := strconv.ParseInt(strings.TrimSpace(max), 10, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid SERVICENOW_ATTACHMENT_MAX_BYTES %q", max)
+		}
+		config.MaxAttachmentBytes = parsed
+	}
+	if ttl := os.Getenv("SERVICENOW_CACHE_TTL"); ttl != "" {
+		parsed, err := time.ParseDuration(strings.TrimSpace(ttl))
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid SERVICENOW_CACHE_TTL %q (use a duration, e.g., 5m, or 0 to disable caching)", ttl)
+		}
+		if parsed == 0 {
+			parsed = -1
+		}
+		config.CacheTTL = parsed
+	}
+	config.QueryHints = QueryHints{
+		NoCount:                  strings.ToLower(os.Getenv("SERVICENOW_NO_COUNT")) != "false",
+		SuppressPaginationHeader: strings.ToLower(os.Getenv("SERVICENOW_SUPPRESS_PAGINATION_HEADER")) == "true",
+		QueryNoDomain:            strings.ToLower(os.Getenv("SERVICENOW_QUERY_NO_DOMAIN")) == "true",
+	}
+	for _, table := range strings.Split(os.Getenv("SERVICENOW_NO_COUNT_TABLES"), ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			config.QueryHints.NoCountTables = append(config.QueryHints.NoCountTables, table)
+		}
+	}
+	for _, mediaType := range strings.Split(os.Getenv("SERVICENOW_ATTACHMENT_TYPES"), ",") {
+		if mediaType = strings.ToLower(strings.TrimSpace(mediaType)); mediaType != "" {
+			config.AllowedAttachmentTypes = append(config.AllowedAttachmentTypes, mediaType)
+		}
 	}
 
 	switch authType {
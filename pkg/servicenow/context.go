@@ -10,6 +10,8 @@ type contextKey string
 const (
 	// CredentialsContextKey is the context key for ServiceNow credentials
 	CredentialsContextKey contextKey = "servicenow_credentials"
+	// ImpersonationContextKey is the context key for the user writes are performed on behalf of
+	ImpersonationContextKey contextKey = "servicenow_impersonation"
 )
 
 // ContextCredentials holds ServiceNow credentials from request headers
@@ -31,3 +33,14 @@ func CredentialsFromContext(ctx context.Context) *ContextCredentials {
 func ContextWithCredentials(ctx context.Context, creds *ContextCredentials) context.Context {
 	return context.WithValue(ctx, CredentialsContextKey, creds)
 }
+
+// ImpersonationFromContext retrieves the user name writes should be performed on behalf of
+func ImpersonationFromContext(ctx context.Context) string {
+	userName, _ := ctx.Value(ImpersonationContextKey).(string)
+	return userName
+}
+
+// ContextWithImpersonation sets the user name writes should be performed on behalf of
+func ContextWithImpersonation(ctx context.Context, userName string) context.Context {
+	return context.WithValue(ctx, ImpersonationContextKey, userName)
+}
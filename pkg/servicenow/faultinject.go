@@ -0,0 +1,85 @@
+//go:build faultinject
+
+package servicenow
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultInjectionBuild reports whether this build can inject faults
+const FaultInjectionBuild = true
+
+// WithFaultInjection makes the client fail a share of its requests with simulated
+// 429 and 500 responses and timeouts, without sending them to the instance
+func WithFaultInjection(config FaultConfig) ClientOption {
+	return func(c *Client) {
+		if !config.Enabled() {
+			return
+		}
+		seed := config.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		next := c.httpClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		c.httpClient.Transport = &faultTransport{next: next, config: config, rand: rand.New(rand.NewSource(seed))}
+	}
+}
+
+// faultTransport is a RoundTripper that replaces some requests with simulated failures
+type faultTransport struct {
+	next   http.RoundTripper
+	config FaultConfig
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	roll := t.rand.Float64()
+	t.mu.Unlock()
+
+	switch {
+	case roll < t.config.RateLimit:
+		resp := faultResponse(req, http.StatusTooManyRequests, "Rate limit exceeded (simulated)")
+		resp.Header.Set("Retry-After", "1")
+		resp.Header.Set(HeaderRateLimitLimit, "1000")
+		resp.Header.Set(HeaderRateLimitRemaining, "0")
+		resp.Header.Set(HeaderRateLimitReset, strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10))
+		return resp, nil
+	case roll < t.config.RateLimit+t.config.ServerError:
+		return faultResponse(req, http.StatusInternalServerError, "Internal server error (simulated)"), nil
+	case roll < t.config.RateLimit+t.config.ServerError+t.config.Timeout:
+		// Hang until the client timeout (or the caller) cancels the request
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	return t.next.RoundTrip(req)
+}
+
+// faultResponse builds a response with a ServiceNow-style error body
+func faultResponse(req *http.Request, status int, message string) *http.Response {
+	body := `{"error":{"message":"` + message + `","detail":"Injected by SERVICENOW_FAULT_* settings"},"status":"failure"}`
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
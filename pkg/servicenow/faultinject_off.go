@@ -0,0 +1,12 @@
+//go:build !faultinject
+
+package servicenow
+
+// FaultInjectionBuild reports whether this build can inject faults
+const FaultInjectionBuild = false
+
+// WithFaultInjection does nothing: faults are only injected by builds with the
+// faultinject tag, so production binaries never simulate failures
+func WithFaultInjection(config FaultConfig) ClientOption {
+	return func(c *Client) {}
+}
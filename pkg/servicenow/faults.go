@@ -0,0 +1,65 @@
+package servicenow
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// FaultConfig sets the probability (0 to 1) of each simulated failure per request.
+// Faults are only injected by builds with the faultinject tag; see WithFaultInjection.
+type FaultConfig struct {
+	// RateLimit is the probability of a 429 Too Many Requests response
+	RateLimit float64
+	// ServerError is the probability of a 500 Internal Server Error response
+	ServerError float64
+	// Timeout is the probability of a request hanging until the client timeout
+	Timeout float64
+	// Seed makes the sequence of faults reproducible (0 seeds from the clock)
+	Seed int64
+}
+
+// Enabled reports whether any fault has a non-zero probability
+func (f FaultConfig) Enabled() bool {
+	return f.RateLimit > 0 || f.ServerError > 0 || f.Timeout > 0
+}
+
+// String summarizes the fault probabilities for logging
+func (f FaultConfig) String() string {
+	return fmt.Sprintf("rate_limit=%g server_error=%g timeout=%g", f.RateLimit, f.ServerError, f.Timeout)
+}
+
+// LoadFaultConfigFromEnv reads SERVICENOW_FAULT_RATE_LIMIT, SERVICENOW_FAULT_SERVER_ERROR,
+// SERVICENOW_FAULT_TIMEOUT, and SERVICENOW_FAULT_SEED
+func LoadFaultConfigFromEnv() (FaultConfig, error) {
+	var config FaultConfig
+	total := 0.0
+	for name, target := range map[string]*float64{
+		"SERVICENOW_FAULT_RATE_LIMIT":   &config.RateLimit,
+		"SERVICENOW_FAULT_SERVER_ERROR": &config.ServerError,
+		"SERVICENOW_FAULT_TIMEOUT":      &config.Timeout,
+	} {
+		envValue := os.Getenv(name)
+		if envValue == "" {
+			continue
+		}
+		p, err := strconv.ParseFloat(envValue, 64)
+		if err != nil || p < 0 || p > 1 {
+			return FaultConfig{}, fmt.Errorf("%s must be a probability between 0 and 1, got %q", name, envValue)
+		}
+		*target = p
+		total += p
+	}
+	if total > 1 {
+		return FaultConfig{}, fmt.Errorf("fault probabilities add up to %g, more than 1", total)
+	}
+
+	if envValue := os.Getenv("SERVICENOW_FAULT_SEED"); envValue != "" {
+		seed, err := strconv.ParseInt(envValue, 10, 64)
+		if err != nil {
+			return FaultConfig{}, fmt.Errorf("SERVICENOW_FAULT_SEED must be an integer, got %q", envValue)
+		}
+		config.Seed = seed
+	}
+	return config, nil
+}
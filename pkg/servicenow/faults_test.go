@@ -0,0 +1,91 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLoadFaultConfigFromEnv tests that fault probabilities are read and validated
+func TestLoadFaultConfigFromEnv(t *testing.T) {
+	t.Setenv("SERVICENOW_FAULT_RATE_LIMIT", "0.2")
+	t.Setenv("SERVICENOW_FAULT_TIMEOUT", "0.05")
+	t.Setenv("SERVICENOW_FAULT_SEED", "42")
+	config, err := LoadFaultConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Expected valid fault config, got %v", err)
+	}
+	if config.RateLimit != 0.2 || config.ServerError != 0 || config.Timeout != 0.05 || config.Seed != 42 || !config.Enabled() {
+		t.Errorf("Unexpected fault config %+v", config)
+	}
+
+	t.Setenv("SERVICENOW_FAULT_SERVER_ERROR", "0.9")
+	if _, err := LoadFaultConfigFromEnv(); err == nil {
+		t.Error("Expected probabilities adding up to more than 1 to be rejected")
+	}
+	t.Setenv("SERVICENOW_FAULT_SERVER_ERROR", "often")
+	if _, err := LoadFaultConfigFromEnv(); err == nil {
+		t.Error("Expected a non-numeric probability to be rejected")
+	}
+}
+
+// TestFaultInjection tests that simulated failures replace requests in faultinject builds
+// (go test -tags faultinject) and that other builds never inject them
+func TestFaultInjection(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+	}))
+	defer ts.Close()
+
+	newClient := func(faults FaultConfig) *Client {
+		client, err := NewClient(&Config{
+			InstanceURL: ts.URL,
+			Timeout:     1,
+			Auth:        AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "test", Password: "test"}},
+		}, WithFaultInjection(faults))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		return client
+	}
+
+	client := newClient(FaultConfig{RateLimit: 1, Seed: 1})
+	ctx := ContextWithUsage(context.Background())
+	_, err := client.GetWithContext(ctx, "/table/incident", nil)
+	if !FaultInjectionBuild {
+		if err != nil || requests != 1 {
+			t.Fatalf("Expected faults to be ignored without the faultinject tag, got %v after %d requests", err, requests)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), "status 429") || requests != 0 {
+		t.Fatalf("Expected a simulated 429 without reaching the instance, got %v after %d requests", err, requests)
+	}
+	if usage, _ := UsageFromContext(ctx); usage.RateLimit == nil || usage.RateLimit.Remaining != 0 {
+		t.Errorf("Expected the simulated 429 to report an exhausted rate limit, got %+v", usage)
+	}
+
+	if _, err := newClient(FaultConfig{ServerError: 1}).Post("/table/incident", map[string]interface{}{}); err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("Expected a simulated 500, got %v", err)
+	}
+	if _, err := newClient(FaultConfig{Timeout: 1}).Get("/table/incident", nil); err == nil || !strings.Contains(err.Error(), "Client.Timeout") {
+		t.Errorf("Expected a simulated timeout, got %v", err)
+	}
+
+	client = newClient(FaultConfig{ServerError: 0.5, Seed: 7})
+	failures := 0
+	for i := 0; i < 200; i++ {
+		if _, err := client.Get("/table/incident", nil); err != nil {
+			failures++
+		}
+	}
+	if failures < 60 || failures > 140 || requests != 200-failures {
+		t.Errorf("Expected about half of 200 requests to fail, got %d failures and %d requests", failures, requests)
+	}
+}
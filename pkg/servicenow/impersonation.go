@@ -0,0 +1,207 @@
+package servicenow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ImpersonatedUser returns the user writes in ctx should be performed on behalf of,
+// or "" when impersonation is disabled or no user is known
+func (c *Client) ImpersonatedUser(ctx context.Context) string {
+	if !c.config.Impersonation {
+		return ""
+	}
+	if user := ImpersonationFromContext(ctx); user != "" {
+		return user
+	}
+	return c.config.ImpersonateUser
+}
+
+// impersonationSessionTTL is how long an impersonation session is reused before a
+// new one is started, well within ServiceNow's default 30 minute session timeout
+const impersonationSessionTTL = 10 * time.Minute
+
+// impersonationSession is a ServiceNow session impersonating a user
+type impersonationSession struct {
+	client    *http.Client
+	userSysID string
+	expires   time.Time
+}
+
+// writeClient returns the HTTP client for a write request. With impersonation it is a
+// session impersonating the conversing user, so the write is recorded as theirs.
+// Sessions are reused per caller and user for impersonationSessionTTL. If
+// impersonation fails the write fails too, rather than falling back to the
+// integration account.
+func (c *Client) writeClient(ctx context.Context, method, endpoint string) (*http.Client, error) {
+	user := c.ImpersonatedUser(ctx)
+	if user == "" {
+		return c.httpClient, nil
+	}
+
+	key := sessionKey(ctx, user)
+	session := c.reuseSession(key)
+	if session == nil {
+		var err error
+		if session, err = c.impersonate(ctx, user); err != nil {
+			return nil, err
+		}
+		c.storeSession(key, session)
+	}
+
+	if c.logger != nil {
+		c.logger.Audit("Impersonated write: %s %s as %s (sys_id %s) by integration account", method, endpoint, user, session.userSysID)
+	}
+	return session.client, nil
+}
+
+// impersonate starts a new session impersonating user
+func (c *Client) impersonate(ctx context.Context, user string) (*impersonationSession, error) {
+	userSysID, err := c.lookupUserSysID(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("impersonation of %s failed: %w", user, err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	session := &http.Client{
+		Transport: c.httpClient.Transport,
+		Timeout:   c.httpClient.Timeout,
+		Jar:       jar,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/ui/impersonate/%s", c.config.APIURL(), url.PathEscape(userSysID)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	headers, err := c.GetHeadersWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headers: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := session.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("impersonation of %s failed: %w", user, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("impersonation of %s failed (status %d): %s", user, resp.StatusCode, string(body))
+	}
+
+	return &impersonationSession{client: session, userSysID: userSysID}, nil
+}
+
+// sessionKey identifies the impersonation sessions of a user started with the
+// credentials in ctx, so callers never share a session started by someone else
+func sessionKey(ctx context.Context, user string) string {
+	creds := CredentialsFromContext(ctx)
+	if creds == nil {
+		return "|" + user
+	}
+	sum := sha256.Sum256([]byte(creds.APIKey + "\x00" + creds.Username + "\x00" + creds.Password))
+	return hex.EncodeToString(sum[:12]) + "|" + user
+}
+
+// reuseSession returns the live session for key, or nil. Expired sessions are
+// retired, and retired sessions are ended once writes started in them have timed out.
+func (c *Client) reuseSession(key string) *impersonationSession {
+	now := time.Now()
+	var ended []*impersonationSession
+
+	c.sessionsMu.Lock()
+	for k, session := range c.sessions {
+		if now.After(session.expires) {
+			c.retiredSessions = append(c.retiredSessions, session)
+			delete(c.sessions, k)
+		}
+	}
+	retired := c.retiredSessions[:0]
+	for _, session := range c.retiredSessions {
+		if now.After(session.expires.Add(c.httpClient.Timeout)) {
+			ended = append(ended, session)
+		} else {
+			retired = append(retired, session)
+		}
+	}
+	c.retiredSessions = retired
+	live := c.sessions[key]
+	c.sessionsMu.Unlock()
+
+	for _, session := range ended {
+		c.endSession(session)
+	}
+	return live
+}
+
+// storeSession keeps a new session for reuse. When a concurrent write already
+// stored one for key, the new session serves only its own write and is retired.
+func (c *Client) storeSession(key string, session *impersonationSession) {
+	session.expires = time.Now().Add(impersonationSessionTTL)
+
+	c.sessionsMu.Lock()
+	defer c.sessionsMu.Unlock()
+	if c.sessions == nil {
+		c.sessions = map[string]*impersonationSession{}
+	}
+	if _, ok := c.sessions[key]; ok {
+		session.expires = time.Now()
+		c.retiredSessions = append(c.retiredSessions, session)
+		return
+	}
+	c.sessions[key] = session
+}
+
+// endSession logs out of an impersonation session. Failures are only logged, as
+// the instance expires abandoned sessions on its own.
+func (c *Client) endSession(session *impersonationSession) {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(c.config.InstanceURL, "/")+"/logout.do", nil)
+	if err != nil {
+		return
+	}
+	resp, err := session.client.Do(req)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("Failed to end impersonation session: %v", err)
+		}
+		return
+	}
+	resp.Body.Close()
+}
+
+// lookupUserSysID resolves a user_name to its sys_id
+func (c *Client) lookupUserSysID(ctx context.Context, userName string) (string, error) {
+	if strings.ContainsAny(userName, "^\r\n") {
+		return "", fmt.Errorf("invalid user name: %q", userName)
+	}
+
+	result, err := c.GetWithContext(ctx, "/table/sys_user", map[string]string{
+		"sysparm_query":  "user_name=" + userName,
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return "", err
+	}
+	if records, ok := result["result"].([]interface{}); ok && len(records) > 0 {
+		if record, ok := records[0].(map[string]interface{}); ok {
+			if sysID, ok := record["sys_id"].(string); ok && sysID != "" {
+				return sysID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("user not found: %s", userName)
+}
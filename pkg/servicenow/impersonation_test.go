@@ -0,0 +1,132 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newImpersonationTestClient creates a client against a fake instance that grants
+// impersonation of jane.doe and records whether writes arrived in her session
+func newImpersonationTestClient(t *testing.T, impersonation bool, impersonateStatus int) (*Client, *[]string) {
+	t.Helper()
+
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": "jane"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/ui/impersonate/jane":
+			calls = append(calls, "impersonate")
+			if impersonateStatus != http.StatusOK {
+				w.WriteHeader(impersonateStatus)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "jane-session", Path: "/"})
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{}})
+		case r.Method == http.MethodGet && r.URL.Path == "/logout.do":
+			calls = append(calls, "logout")
+		case r.Method == http.MethodPut:
+			session := "integration"
+			if cookie, err := r.Cookie("JSESSIONID"); err == nil {
+				session = cookie.Value
+			}
+			calls = append(calls, "write:"+session)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "inc1"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := NewClient(&Config{
+		InstanceURL:   ts.URL,
+		Timeout:       5,
+		Auth:          AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "integration", Password: "secret"}},
+		Impersonation: impersonation,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client, &calls
+}
+
+// TestImpersonatedWrite tests that writes run in a session impersonating the user from the context
+func TestImpersonatedWrite(t *testing.T) {
+	ctx := ContextWithImpersonation(context.Background(), "jane.doe")
+
+	client, calls := newImpersonationTestClient(t, true, http.StatusOK)
+	if _, err := client.PutWithContext(ctx, "/table/incident/inc1", map[string]interface{}{"comments": "hi"}); err != nil {
+		t.Fatalf("Expected write to succeed, got %v", err)
+	}
+	if len(*calls) != 2 || (*calls)[0] != "impersonate" || (*calls)[1] != "write:jane-session" {
+		t.Errorf("Expected write in jane's session, got %v", *calls)
+	}
+
+	// Impersonation disabled: the header is ignored
+	client, calls = newImpersonationTestClient(t, false, http.StatusOK)
+	if _, err := client.PutWithContext(ctx, "/table/incident/inc1", map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected write to succeed, got %v", err)
+	}
+	if len(*calls) != 1 || (*calls)[0] != "write:integration" {
+		t.Errorf("Expected write as the integration account, got %v", *calls)
+	}
+
+	// Impersonation refused: the write must not fall back to the integration account
+	client, calls = newImpersonationTestClient(t, true, http.StatusForbidden)
+	if _, err := client.PutWithContext(ctx, "/table/incident/inc1", map[string]interface{}{}); err == nil {
+		t.Fatal("Expected write to fail when impersonation is refused")
+	}
+	if len(*calls) != 1 {
+		t.Errorf("Expected no write after refused impersonation, got %v", *calls)
+	}
+
+	if _, err := client.lookupUserSysID(context.Background(), "jane^NQuser_name=admin"); err == nil {
+		t.Error("Expected user names with query separators to be rejected")
+	}
+}
+
+// TestImpersonationSessionReuse tests that a user's impersonation session is reused until it expires, then ended and replaced
+func TestImpersonationSessionReuse(t *testing.T) {
+	ctx := ContextWithImpersonation(context.Background(), "jane.doe")
+	client, calls := newImpersonationTestClient(t, true, http.StatusOK)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.PutWithContext(ctx, "/table/incident/inc1", map[string]interface{}{}); err != nil {
+			t.Fatalf("Expected write to succeed, got %v", err)
+		}
+	}
+	if want := []string{"impersonate", "write:jane-session", "write:jane-session"}; strings.Join(*calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected the session to be reused, got %v", *calls)
+	}
+
+	// Another caller's credentials get their own session
+	other := ContextWithCredentials(ctx, &ContextCredentials{Username: "other", Password: "secret"})
+	*calls = nil
+	if _, err := client.PutWithContext(other, "/table/incident/inc1", map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected write to succeed, got %v", err)
+	}
+	if len(*calls) != 2 || (*calls)[0] != "impersonate" {
+		t.Errorf("Expected a new session for another caller, got %v", *calls)
+	}
+
+	client.sessionsMu.Lock()
+	for _, session := range client.sessions {
+		session.expires = time.Now().Add(-time.Hour)
+	}
+	client.sessionsMu.Unlock()
+
+	*calls = nil
+	if _, err := client.PutWithContext(ctx, "/table/incident/inc1", map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected write to succeed, got %v", err)
+	}
+	if want := []string{"logout", "logout", "impersonate", "write:jane-session"}; strings.Join(*calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected expired sessions to be ended and replaced, got %v", *calls)
+	}
+}
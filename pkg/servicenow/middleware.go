@@ -9,6 +9,10 @@ const (
 	HeaderUsername = "X-ServiceNow-Username"
 	HeaderPassword = "X-ServiceNow-Password"
 	HeaderAPIKey   = "X-ServiceNow-API-Key"
+
+	// HeaderImpersonateUser names the user (user_name) writes are performed on behalf of
+	// when impersonation is enabled
+	HeaderImpersonateUser = "X-ServiceNow-Impersonate-User"
 )
 
 // CredentialsMiddleware extracts ServiceNow credentials from request headers
@@ -37,6 +41,9 @@ func (m *CredentialsMiddleware) Wrap(next http.Handler) http.Handler {
 			}
 			r = r.WithContext(ContextWithCredentials(r.Context(), creds))
 		}
+		if user := r.Header.Get(HeaderImpersonateUser); user != "" {
+			r = r.WithContext(ContextWithImpersonation(r.Context(), user))
+		}
 
 		next.ServeHTTP(w, r)
 	})
@@ -0,0 +1,142 @@
+package servicenow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrStopPaging can be returned from a PageFunc to stop iteration early without an error
+var ErrStopPaging = errors.New("stop paging")
+
+// PageFunc is called with each page of records fetched by GetAllPages
+type PageFunc func(page []map[string]interface{}) error
+
+// PagingResult summarizes a GetAllPages iteration
+type PagingResult struct {
+	Pages      int  `json:"pages"`
+	Records    int  `json:"records"`
+	TotalCount int  `json:"total_count"` // -1 when the instance did not report X-Total-Count
+	NextOffset int  `json:"next_offset"`
+	Complete   bool `json:"complete"`
+}
+
+// GetWithTotalCount makes a GET request and also returns the X-Total-Count reported
+// for the query, or -1 when the instance did not report it
+func (c *Client) GetWithTotalCount(endpoint string, params map[string]string) (map[string]interface{}, int, error) {
+	return c.GetWithTotalCountContext(context.Background(), endpoint, params)
+}
+
+// GetWithTotalCountContext makes a GET request with context support and also returns
+// the X-Total-Count reported for the query, or -1 when the instance did not report it
+func (c *Client) GetWithTotalCountContext(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, int, error) {
+	result, header, err := c.GetWithHeaders(ctx, endpoint, params)
+	if err != nil {
+		return nil, -1, err
+	}
+	total, err := strconv.Atoi(header.Get(HeaderTotalCount))
+	if err != nil {
+		total = -1
+	}
+	return result, total, nil
+}
+
+// GetPages iterates over all records matching a Table API query, as GetAllPages
+// without a context
+func (c *Client) GetPages(endpoint string, params map[string]string, perPage int, fn PageFunc) (*PagingResult, error) {
+	return c.GetAllPages(context.Background(), endpoint, params, perPage, fn)
+}
+
+// GetAllPages iterates over all records matching a Table API query, calling fn
+// once per page of up to perPage records. Iteration starts at params["sysparm_offset"]
+// (default 0) and ends when a short page is returned, the reported total count is
+// reached, a Link header has no next page, the context is cancelled, or fn returns an error. Returning ErrStopPaging
+// from fn stops iteration without an error.
+func (c *Client) GetAllPages(ctx context.Context, endpoint string, params map[string]string, perPage int, fn PageFunc) (*PagingResult, error) {
+	if perPage <= 0 {
+		return nil, fmt.Errorf("perPage must be positive")
+	}
+
+	pageParams := make(map[string]string, len(params)+2)
+	for k, v := range params {
+		pageParams[k] = v
+	}
+
+	offset := 0
+	if v, ok := params["sysparm_offset"]; ok {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sysparm_offset: %s", v)
+		}
+		offset = parsed
+	}
+
+	paging := &PagingResult{TotalCount: -1, NextOffset: offset}
+	for {
+		if err := ctx.Err(); err != nil {
+			return paging, err
+		}
+
+		pageParams["sysparm_limit"] = strconv.Itoa(perPage)
+		pageParams["sysparm_offset"] = strconv.Itoa(offset)
+
+		result, header, err := c.GetWithHeaders(ctx, endpoint, pageParams)
+		if err != nil {
+			return paging, err
+		}
+		if total, err := strconv.Atoi(header.Get(HeaderTotalCount)); err == nil {
+			paging.TotalCount = total
+		}
+
+		page := []map[string]interface{}{}
+		if resultList, ok := result["result"].([]interface{}); ok {
+			for _, item := range resultList {
+				if record, ok := item.(map[string]interface{}); ok {
+					page = append(page, record)
+				}
+			}
+		}
+
+		offset += len(page)
+		paging.NextOffset = offset
+		last := len(page) < perPage || (paging.TotalCount >= 0 && offset >= paging.TotalCount) || !hasNextLink(header)
+		if len(page) > 0 {
+			paging.Pages++
+			paging.Records += len(page)
+			if err := fn(page); err != nil {
+				if errors.Is(err, ErrStopPaging) {
+					paging.Complete = last
+					return paging, nil
+				}
+				return paging, err
+			}
+		}
+
+		if last {
+			paging.Complete = true
+			return paging, nil
+		}
+	}
+}
+
+// hasNextLink reports whether a response may have a next page: its Link header has a
+// rel="next" entry, or there is no Link header to tell
+func hasNextLink(header http.Header) bool {
+	links := header.Values(HeaderLink)
+	if len(links) == 0 {
+		return true
+	}
+	for _, link := range links {
+		for _, entry := range strings.Split(link, ",") {
+			for _, param := range strings.Split(entry, ";")[1:] {
+				if rel, ok := strings.CutPrefix(strings.TrimSpace(param), "rel="); ok && strings.Trim(rel, `"`) == "next" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newPagingTestClient creates a client against a fake Table API serving total records
+func newPagingTestClient(t *testing.T, total int) (*Client, *int) {
+	t.Helper()
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("sysparm_limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+
+		records := []map[string]interface{}{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			records = append(records, map[string]interface{}{"sys_id": strconv.Itoa(i)})
+		}
+
+		w.Header().Set(HeaderTotalCount, strconv.Itoa(total))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := NewClient(&Config{
+		InstanceURL: ts.URL,
+		Timeout:     5,
+		Auth:        AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "u", Password: "p"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client, &requests
+}
+
+// TestGetAllPages tests that all pages are fetched and the total count is tracked
+func TestGetAllPages(t *testing.T) {
+	client, requests := newPagingTestClient(t, 25)
+
+	seen := 0
+	paging, err := client.GetAllPages(context.Background(), "/table/incident", nil, 10, func(page []map[string]interface{}) error {
+		seen += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GetAllPages failed: %v", err)
+	}
+
+	if seen != 25 || paging.Records != 25 {
+		t.Errorf("Expected 25 records, got seen=%d records=%d", seen, paging.Records)
+	}
+	if paging.Pages != 3 || *requests != 3 {
+		t.Errorf("Expected 3 pages and requests, got pages=%d requests=%d", paging.Pages, *requests)
+	}
+	if paging.TotalCount != 25 || !paging.Complete || paging.NextOffset != 25 {
+		t.Errorf("Unexpected paging result: %+v", paging)
+	}
+}
+
+// TestGetAllPagesStopEarly tests early termination via ErrStopPaging
+func TestGetAllPagesStopEarly(t *testing.T) {
+	client, requests := newPagingTestClient(t, 100)
+
+	paging, err := client.GetAllPages(context.Background(), "/table/incident", map[string]string{"sysparm_offset": "20"}, 10, func(page []map[string]interface{}) error {
+		if page[0]["sys_id"] != "20" {
+			t.Errorf("Expected first record at offset 20, got %v", page[0]["sys_id"])
+		}
+		return ErrStopPaging
+	})
+	if err != nil {
+		t.Fatalf("GetAllPages failed: %v", err)
+	}
+
+	if *requests != 1 || paging.Records != 10 || paging.Complete || paging.NextOffset != 30 {
+		t.Errorf("Unexpected paging result after early stop: %+v (requests=%d)", paging, *requests)
+	}
+}
+
+// TestGetAllPagesLinkHeader tests that paging ends when the Link header has no next page
+func TestGetAllPagesLinkHeader(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+		records := []map[string]interface{}{}
+		for i := offset; i < offset+10; i++ {
+			records = append(records, map[string]interface{}{"sys_id": strconv.Itoa(i)})
+		}
+
+		w.Header().Set(HeaderLink, `<https://example.service-now.com/api/now/table/incident?sysparm_offset=0>;rel="first"`)
+		if offset == 0 {
+			w.Header().Add(HeaderLink, `<https://example.service-now.com/api/now/table/incident?sysparm_offset=10>;rel="next"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		InstanceURL: ts.URL,
+		Timeout:     5,
+		Auth:        AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "u", Password: "p"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	paging, err := client.GetPages("/table/incident", nil, 10, func(page []map[string]interface{}) error { return nil })
+	if err != nil {
+		t.Fatalf("GetPages failed: %v", err)
+	}
+	if requests != 2 || paging.Records != 20 || !paging.Complete || paging.TotalCount != -1 {
+		t.Errorf("Unexpected paging result: %+v (requests=%d)", paging, requests)
+	}
+}
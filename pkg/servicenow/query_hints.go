@@ -0,0 +1,79 @@
+package servicenow
+
+import "strings"
+
+// Table API parameters set by query hints
+const (
+	ParamNoCount                  = "sysparm_no_count"
+	ParamSuppressPaginationHeader = "sysparm_suppress_pagination_header"
+	ParamQueryNoDomain            = "sysparm_query_no_domain"
+)
+
+// QueryHints are Table API flags added to reads to lower the cost of queries on large
+// or busy tables. Parameters a caller sets itself are left as given.
+type QueryHints struct {
+	// NoCount skips the row count (sysparm_no_count) on reads that don't use it, such as
+	// lookups and single-record reads
+	NoCount bool
+	// NoCountTables also skips it on the list reads of these tables ("*" for every table),
+	// which then report no total and guess whether more pages follow from the page size
+	NoCountTables []string
+	// SuppressPaginationHeader leaves the Link header out of responses (sysparm_suppress_pagination_header)
+	SuppressPaginationHeader bool
+	// QueryNoDomain queries across all domains on domain-separated instances (sysparm_query_no_domain)
+	QueryNoDomain bool
+}
+
+// tableName returns the table of a Table API endpoint (e.g., "incident" for
+// "/table/incident/<sys_id>"), or "" for other APIs
+func tableName(endpoint string) string {
+	rest, ok := strings.CutPrefix(endpoint, "/table/")
+	if !ok {
+		return ""
+	}
+	table, _, _ := strings.Cut(rest, "/")
+	return table
+}
+
+// noCount reports whether the row count is skipped on reads of table that use it
+func (h QueryHints) noCount(table string) bool {
+	for _, t := range h.NoCountTables {
+		if t == "*" || strings.EqualFold(t, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// withQueryHints returns params with the configured query hints added for a read of
+// endpoint. countUsed says whether the caller reads X-Total-Count. params is not modified.
+func (c *Client) withQueryHints(endpoint string, params map[string]string, countUsed bool) map[string]string {
+	table := tableName(endpoint)
+	if table == "" {
+		return params
+	}
+	hints := c.config.QueryHints
+
+	added := map[string]string{}
+	if (hints.NoCount && !countUsed) || hints.noCount(table) {
+		added[ParamNoCount] = "true"
+	}
+	if hints.SuppressPaginationHeader {
+		added[ParamSuppressPaginationHeader] = "true"
+	}
+	if hints.QueryNoDomain {
+		added[ParamQueryNoDomain] = "true"
+	}
+	if len(added) == 0 {
+		return params
+	}
+
+	hinted := make(map[string]string, len(params)+len(added))
+	for k, v := range added {
+		hinted[k] = v
+	}
+	for k, v := range params {
+		hinted[k] = v
+	}
+	return hinted
+}
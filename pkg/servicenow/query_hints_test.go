@@ -0,0 +1,64 @@
+package servicenow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestQueryHints tests that the count is skipped on reads that don't use it and on the configured tables, and that other hints are added to Table API reads only
+func TestQueryHints(t *testing.T) {
+	queries := map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path] = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": []}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		InstanceURL: ts.URL,
+		Timeout:     5,
+		Auth:        AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "integration", Password: "secret"}},
+		QueryHints:  QueryHints{NoCount: true, NoCountTables: []string{"sys_audit"}, QueryNoDomain: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for _, read := range []struct {
+		name  string
+		read  func() error
+		path  string
+		query string
+	}{
+		{"lookup", func() error {
+			_, err := client.Get("/table/sys_user", map[string]string{"sysparm_limit": "1"})
+			return err
+		}, "/api/now/table/sys_user", "sysparm_limit=1&sysparm_no_count=true&sysparm_query_no_domain=true"},
+		{"counted list", func() error {
+			_, _, err := client.GetWithTotalCount("/table/incident", map[string]string{"sysparm_limit": "10"})
+			return err
+		}, "/api/now/table/incident", "sysparm_limit=10&sysparm_query_no_domain=true"},
+		{"uncounted table", func() error {
+			_, _, err := client.GetWithTotalCount("/table/sys_audit", nil)
+			return err
+		}, "/api/now/table/sys_audit", "sysparm_no_count=true&sysparm_query_no_domain=true"},
+		{"caller's own flag", func() error {
+			_, err := client.Get("/table/problem", map[string]string{ParamNoCount: "false"})
+			return err
+		}, "/api/now/table/problem", "sysparm_no_count=false&sysparm_query_no_domain=true"},
+		{"other API", func() error {
+			_, err := client.GetWithContext(context.Background(), "/stats/incident", map[string]string{"sysparm_count": "true"})
+			return err
+		}, "/api/now/stats/incident", "sysparm_count=true"},
+	} {
+		if err := read.read(); err != nil {
+			t.Fatalf("%s: %v", read.name, err)
+		}
+		if queries[read.path] != read.query {
+			t.Errorf("%s: expected %s, got %s", read.name, read.query, queries[read.path])
+		}
+	}
+}
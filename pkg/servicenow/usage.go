@@ -0,0 +1,131 @@
+package servicenow
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Response headers carrying API usage information
+	HeaderTotalCount         = "X-Total-Count"
+	HeaderLink               = "Link"
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimit holds the rate limit state reported by the instance (when rate limiting is enabled)
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset,omitempty"`
+}
+
+// IsLow reports whether the remaining quota is at or below the given fraction of the limit
+func (r *RateLimit) IsLow(ratio float64) bool {
+	if r == nil || r.Limit <= 0 {
+		return false
+	}
+	return float64(r.Remaining) <= float64(r.Limit)*ratio
+}
+
+// Usage holds API usage information captured from a ServiceNow response
+type Usage struct {
+	TotalCount *int       `json:"total_count,omitempty"`
+	RateLimit  *RateLimit `json:"rate_limit,omitempty"`
+	ObservedAt time.Time  `json:"observed_at"`
+}
+
+// parseUsage extracts usage information from response headers
+func parseUsage(header http.Header) Usage {
+	usage := Usage{ObservedAt: time.Now()}
+
+	if v := header.Get(HeaderTotalCount); v != "" {
+		if total, err := strconv.Atoi(v); err == nil {
+			usage.TotalCount = &total
+		}
+	}
+
+	limit, limitErr := strconv.Atoi(header.Get(HeaderRateLimitLimit))
+	remaining, remainingErr := strconv.Atoi(header.Get(HeaderRateLimitRemaining))
+	if limitErr == nil && remainingErr == nil {
+		rl := &RateLimit{Limit: limit, Remaining: remaining}
+		if reset, err := strconv.ParseInt(header.Get(HeaderRateLimitReset), 10, 64); err == nil {
+			rl.Reset = time.Unix(reset, 0).UTC()
+		}
+		usage.RateLimit = rl
+	}
+
+	return usage
+}
+
+// usageKey is the context key for the usageRecorder of a request
+type usageKey struct{}
+
+// usageRecorder holds the usage observed on the responses to requests made with a
+// context. Recorders nest, so a tool call made within another also reports to the outer one.
+type usageRecorder struct {
+	mu     sync.Mutex
+	usage  Usage
+	parent *usageRecorder
+}
+
+// ContextWithUsage returns a context whose requests record the usage headers of
+// their responses, for UsageFromContext to read. Usage is kept per context, so
+// concurrent callers of a shared Client each see their own.
+func ContextWithUsage(ctx context.Context) context.Context {
+	parent, _ := ctx.Value(usageKey{}).(*usageRecorder)
+	return context.WithValue(ctx, usageKey{}, &usageRecorder{parent: parent})
+}
+
+// UsageFromContext returns the usage information observed on the most recent
+// response to a request made with ctx. It reports false when ctx was not made by
+// ContextWithUsage or no response has been received yet.
+func UsageFromContext(ctx context.Context) (Usage, bool) {
+	recorder, ok := ctx.Value(usageKey{}).(*usageRecorder)
+	if !ok {
+		return Usage{}, false
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return recorder.usage, !recorder.usage.ObservedAt.IsZero()
+}
+
+// recordUsage stores the usage information from a response in the recorders of ctx
+// and as the client's most recent usage
+func (c *Client) recordUsage(ctx context.Context, header http.Header) {
+	usage := parseUsage(header)
+	recorder, _ := ctx.Value(usageKey{}).(*usageRecorder)
+	for ; recorder != nil; recorder = recorder.parent {
+		recorder.mu.Lock()
+		recorded := usage
+		// Keep the last known rate limit when a response omits the headers
+		if recorded.RateLimit == nil {
+			recorded.RateLimit = recorder.usage.RateLimit
+		}
+		recorder.usage = recorded
+		recorder.mu.Unlock()
+	}
+
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	if usage.RateLimit == nil {
+		usage.RateLimit = c.lastUsage.RateLimit
+	}
+	c.lastUsage = usage
+}
+
+// LastUsage returns the usage information observed on the most recent response.
+// In HTTP mode concurrent requests share this snapshot, so it reflects the
+// latest response from any caller.
+//
+// Deprecated: Use ContextWithUsage and UsageFromContext, which report the usage
+// of a single caller's requests.
+func (c *Client) LastUsage() Usage {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	return c.lastUsage
+}
@@ -0,0 +1,66 @@
+package servicenow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUsageFromContext tests that usage is recorded for the context that made the request, and reported to enclosing contexts
+func TestUsageFromContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sysparm_limit") == "1" {
+			w.Header().Set(HeaderRateLimitLimit, "100")
+			w.Header().Set(HeaderRateLimitRemaining, "5")
+		}
+		w.Header().Set(HeaderTotalCount, r.URL.Query().Get("sysparm_limit"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": []}`))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		InstanceURL: ts.URL,
+		Timeout:     5,
+		Auth:        AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuthConfig{Username: "integration", Password: "secret"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, ok := UsageFromContext(context.Background()); ok {
+		t.Error("Expected no usage for a context without a recorder")
+	}
+
+	outer := ContextWithUsage(context.Background())
+	first := ContextWithUsage(outer)
+	second := ContextWithUsage(context.Background())
+	if _, ok := UsageFromContext(first); ok {
+		t.Error("Expected no usage before a response is received")
+	}
+
+	if _, err := client.GetWithContext(first, "/table/incident", map[string]string{"sysparm_limit": "1"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if _, err := client.GetWithContext(first, "/table/incident", map[string]string{"sysparm_limit": "2"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if _, err := client.GetWithContext(second, "/table/incident", map[string]string{"sysparm_limit": "3"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	usage, ok := UsageFromContext(first)
+	if !ok || usage.TotalCount == nil || *usage.TotalCount != 2 {
+		t.Errorf("Expected the first context's latest total count, got %+v", usage)
+	}
+	if usage.RateLimit == nil || usage.RateLimit.Remaining != 5 {
+		t.Errorf("Expected the last known rate limit to be kept, got %+v", usage.RateLimit)
+	}
+	if usage, _ := UsageFromContext(outer); usage.TotalCount == nil || *usage.TotalCount != 2 {
+		t.Errorf("Expected the enclosing context to see the nested request, got %+v", usage)
+	}
+	if usage, _ := UsageFromContext(second); usage.TotalCount == nil || *usage.TotalCount != 3 || usage.RateLimit != nil {
+		t.Errorf("Expected the second context to see only its own request, got %+v", usage)
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// maxActivityText bounds the arguments, records, and error text of an activity record
+const maxActivityText = 4000
+
+// secretArgumentWords mark arguments whose values are not copied to activity records
+var secretArgumentWords = []string{"password", "secret", "token"}
+
+// SetActivityTable enables activity records: every write tool call inserts a record into
+// table (e.g., u_ai_agent_activity) with the server's credentials, so admins can report
+// on AI-driven changes in the platform. Columns the table lacks are ignored by ServiceNow.
+// An empty table disables activity records.
+func (r *Registry) SetActivityTable(table string) error {
+	if table != "" && !tableNamePattern.MatchString(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+	r.activityTable = table
+	return nil
+}
+
+// logActivity inserts the activity record of a write tool call: who called which tool
+// with which arguments, whether it succeeded, and the records it created, updated, or
+// deleted. Failures are logged rather than failing the call.
+func (r *Registry) logActivity(ctx context.Context, call *ToolCall, result *mcp.CallToolResult, callErr error, changes *changeRecorder) {
+	success := callErr == nil && result != nil && !result.IsError
+	record := map[string]interface{}{
+		"u_tool":        call.Tool.Name,
+		"u_caller":      mcp.CallerIdentity(ctx),
+		"u_success":     success,
+		"u_duration_ms": time.Since(call.Start).Milliseconds(),
+		"u_arguments":   truncateText(activityArguments(call.Args), maxActivityText),
+	}
+	if sessionID := mcp.SessionIDFromContext(ctx); sessionID != "" {
+		record["u_session_id"] = sessionID
+	}
+	if requestID := mcp.RequestIDFromContext(ctx); requestID != nil {
+		record["u_request_id"] = fmt.Sprint(requestID)
+	}
+	if user := r.base.ImpersonatedUser(ctx); user != "" {
+		record["u_on_behalf_of"] = user
+	}
+
+	if changes != nil {
+		changes.mu.Lock()
+		lines := make([]string, 0, len(changes.changes))
+		for _, change := range changes.changes {
+			line := fmt.Sprintf("%s %s %s", change.Action, change.Table, change.SysID)
+			if change.Number != "" {
+				line += " (" + change.Number + ")"
+			}
+			lines = append(lines, line)
+		}
+		changes.mu.Unlock()
+		record["u_records"] = truncateText(strings.Join(lines, "\n"), maxActivityText)
+	}
+
+	switch {
+	case callErr != nil:
+		record["u_error"] = truncateText(callErr.Error(), maxActivityText)
+	case result != nil && result.IsError && len(result.Content) > 0:
+		record["u_error"] = truncateText(result.Content[0].Text, maxActivityText)
+	case result != nil && len(result.Content) > 0:
+		var body map[string]interface{}
+		if json.Unmarshal([]byte(result.Content[0].Text), &body) == nil && body["success"] == false {
+			record["u_success"] = false
+			record["u_error"] = truncateText(fmt.Sprint(body["message"]), maxActivityText)
+		}
+	}
+
+	// The server's own credentials, so callers need no access to the activity table
+	if _, err := r.base.Post("/table/"+r.activityTable, record); err != nil && r.logger != nil {
+		r.logger.Warn("Failed to write the activity record of %s to %s: %v", call.Tool.Name, r.activityTable, err)
+	}
+}
+
+// activityArguments encodes tool arguments for an activity record, masking secrets
+// (e.g., a new user's password)
+func activityArguments(args map[string]interface{}) string {
+	masked := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		masked[name] = value
+		lower := strings.ToLower(name)
+		for _, word := range secretArgumentWords {
+			if strings.Contains(lower, word) {
+				masked[name] = "(masked)"
+				break
+			}
+		}
+	}
+	encoded, _ := json.Marshal(masked)
+	return string(encoded)
+}
+
+// truncateText shortens text to at most limit bytes, on a character boundary
+func truncateText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestActivityRecords tests that write tool calls insert an activity record naming the records they changed
+func TestActivityRecords(t *testing.T) {
+	var activities []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/u_ai_agent_activity":
+			var activity map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&activity)
+			activities = append(activities, activity)
+			_, _ = w.Write([]byte(`{"result": {"sys_id": "act1"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/sys_user_group":
+			_, _ = w.Write([]byte(`{"result": {"sys_id": "g1"}}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "u1", "user_name": "abel.tuter"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	if err := registry.SetActivityTable("u_ai_agent_activity"); err != nil {
+		t.Fatalf("SetActivityTable failed: %v", err)
+	}
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	createGroup, _ := server.Handler("create_group")
+	result, _ := createGroup(context.Background(), map[string]interface{}{"name": "Network", "description": "Network team"})
+	if result.IsError {
+		t.Fatalf("Expected the group to be created, got %+v", result)
+	}
+	if len(activities) != 1 {
+		t.Fatalf("Expected one activity record, got %+v", activities)
+	}
+	activity := activities[0]
+	if activity["u_tool"] != "create_group" || activity["u_success"] != true || activity["u_records"] != "created sys_user_group g1" {
+		t.Errorf("Unexpected activity record %+v", activity)
+	}
+	if !strings.Contains(activity["u_arguments"].(string), `"name":"Network"`) {
+		t.Errorf("Expected the arguments in the activity record, got %v", activity["u_arguments"])
+	}
+
+	// Read-only tools leave no activity record
+	getUser, _ := server.Handler("get_user")
+	_, _ = getUser(context.Background(), map[string]interface{}{"user_id": "abel.tuter"})
+	if len(activities) != 1 {
+		t.Errorf("Expected no activity record for a read-only tool, got %d records", len(activities))
+	}
+
+	if got := activityArguments(map[string]interface{}{"client_secret": "s3cr3t", "name": "x"}); strings.Contains(got, "s3cr3t") {
+		t.Errorf("Expected secret arguments to be masked, got %s", got)
+	}
+	if err := registry.SetActivityTable("u_activity; drop"); err == nil {
+		t.Error("Expected an invalid table name to be rejected")
+	}
+}
@@ -4,24 +4,25 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
 )
 
 // registerAgileTools registers all agile management tools (stories, epics, scrum tasks, projects)
 func (r *Registry) registerAgileTools(server *mcp.Server) int {
 	count := 0
 
-	// Helper for limit constraints
+	// Helper for limit/offset constraints
 	limitMin := float64(1)
 	limitMax := float64(1000)
+	offsetMin := float64(0)
 
 	// === Stories ===
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_stories",
 		Description: "List user stories with optional filtering by state, sprint, or assignee. Stories represent work items in Agile development.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of stories to return (default: 50)",
@@ -29,6 +30,12 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"state": {
 					Type:        "string",
 					Description: "Filter by state (e.g., 'Draft', 'Ready', 'In Progress', 'Complete')",
@@ -41,24 +48,22 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Filter by assigned user (sys_id, username, or email)",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Stories",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listStories(args)
-	})
+	}, (*Registry).listStories)
 	count++
 
 	// === Epics ===
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_epics",
 		Description: "List epics with optional filtering. Epics are large bodies of work that contain multiple stories.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of epics to return (default: 50)",
@@ -66,6 +71,12 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"state": {
 					Type:        "string",
 					Description: "Filter by state (e.g., 'Draft', 'Analysis', 'Development', 'Complete')",
@@ -74,24 +85,22 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Filter by product sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Epics",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listEpics(args)
-	})
+	}, (*Registry).listEpics)
 	count++
 
 	// === Scrum Tasks ===
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_scrum_tasks",
 		Description: "List scrum tasks with optional filtering. Tasks are work items that implement a story.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of tasks to return (default: 50)",
@@ -99,6 +108,12 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"story": {
 					Type:        "string",
 					Description: "Filter by parent story sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
@@ -111,24 +126,22 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Filter by assigned user (sys_id, username, or email)",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Scrum Tasks",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listScrumTasks(args)
-	})
+	}, (*Registry).listScrumTasks)
 	count++
 
 	// === Projects ===
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_projects",
 		Description: "List projects with optional filtering by state or active status.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of projects to return (default: 50)",
@@ -136,6 +149,12 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"state": {
 					Type:        "string",
 					Description: "Filter by state (e.g., 'Draft', 'Pending', 'Open', 'Work in progress', 'Closed')",
@@ -144,21 +163,19 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					Type:        "boolean",
 					Description: "Filter by active status (true = only active, false = only inactive)",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Projects",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listProjects(args)
-	})
+	}, (*Registry).listProjects)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Story
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_story",
 			Description: "Create a new user story. Stories represent work items in Agile development.",
 			InputSchema: mcp.JSONSchema{
@@ -167,6 +184,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Story title/summary",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"description": {
 						Type:        "string",
@@ -198,25 +216,44 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Story",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createStory(args)
-		})
+		}, (*Registry).createStory)
 		count++
 
 		// Update Story
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_story",
-			Description: "Update an existing user story. At least one field besides story_id must be provided.",
+			Description: "Update an existing user story, including grooming fields (acceptance criteria, assignee, sprint, epic). At least one field besides story_id must be provided.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
 					"story_id": {
 						Type:        "string",
-						Description: "Story sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
+						Description: "Story number (e.g., 'STRY0010001') or sys_id",
 					},
 					"short_description": {
 						Type:        "string",
 						Description: "Story title/summary",
+						MaxLength:   shortDescriptionMaxLength,
+					},
+					"description": {
+						Type:        "string",
+						Description: "Story description",
+					},
+					"acceptance_criteria": {
+						Type:        "string",
+						Description: "Acceptance criteria the story must meet to be complete",
+					},
+					"assigned_to": {
+						Type:        "string",
+						Description: "Assigned user (sys_id, username, or email)",
+					},
+					"sprint": {
+						Type:        "string",
+						Description: "Sprint number (e.g., 'SPNT0010001') or sys_id, or 'backlog' to take the story out of its sprint",
+					},
+					"epic": {
+						Type:        "string",
+						Description: "Parent epic number (e.g., 'EPIC0010001') or sys_id",
 					},
 					"state": {
 						Type:        "string",
@@ -230,19 +267,21 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 						Type:        "boolean",
 						Description: "Whether the story is blocked",
 					},
+					"work_notes": {
+						Type:        "string",
+						Description: "Internal work notes to add. Mention users with @user_name or @[Full Name] to notify them.",
+					},
 				},
 				Required: []string{"story_id"},
 			},
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Story",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateStory(args)
-		})
+		}, (*Registry).updateStory)
 		count++
 
 		// Create Epic
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_epic",
 			Description: "Create a new epic. Epics are large bodies of work that contain multiple stories.",
 			InputSchema: mcp.JSONSchema{
@@ -251,6 +290,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Epic title/summary",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"description": {
 						Type:        "string",
@@ -266,13 +306,11 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Epic",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createEpic(args)
-		})
+		}, (*Registry).createEpic)
 		count++
 
 		// Update Epic
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_epic",
 			Description: "Update an existing epic. At least one field besides epic_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -285,6 +323,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Epic title/summary",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"state": {
 						Type:        "string",
@@ -296,13 +335,11 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Epic",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateEpic(args)
-		})
+		}, (*Registry).updateEpic)
 		count++
 
 		// Create Scrum Task
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_scrum_task",
 			Description: "Create a new scrum task. Tasks are work items that implement a story.",
 			InputSchema: mcp.JSONSchema{
@@ -311,6 +348,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Task title/summary",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"story": {
 						Type:        "string",
@@ -334,13 +372,11 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Scrum Task",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createScrumTask(args)
-		})
+		}, (*Registry).createScrumTask)
 		count++
 
 		// Update Scrum Task
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_scrum_task",
 			Description: "Update an existing scrum task. At least one field besides task_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -364,13 +400,11 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Scrum Task",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateScrumTask(args)
-		})
+		}, (*Registry).updateScrumTask)
 		count++
 
 		// Create Project
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_project",
 			Description: "Create a new project. Projects are used for tracking larger initiatives.",
 			InputSchema: mcp.JSONSchema{
@@ -379,6 +413,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Project title/summary",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"description": {
 						Type:        "string",
@@ -387,10 +422,12 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"start_date": {
 						Type:        "string",
 						Description: "Project start date (format: YYYY-MM-DD)",
+						Format:      "date",
 					},
 					"end_date": {
 						Type:        "string",
 						Description: "Project end date (format: YYYY-MM-DD)",
+						Format:      "date",
 					},
 				},
 				Required: []string{"short_description"},
@@ -398,13 +435,11 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Project",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createProject(args)
-		})
+		}, (*Registry).createProject)
 		count++
 
 		// Update Project
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_project",
 			Description: "Update an existing project. At least one field besides project_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -417,6 +452,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Project title/summary",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"state": {
 						Type:        "string",
@@ -428,9 +464,7 @@ func (r *Registry) registerAgileTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Project",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateProject(args)
-		})
+		}, (*Registry).updateProject)
 		count++
 	}
 
@@ -445,26 +479,32 @@ func (r *Registry) listStories(args map[string]interface{}) (*mcp.CallToolResult
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,state,story_points,sprint,epic,blocked"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
 	if sprint != "" {
-		filters = append(filters, fmt.Sprintf("sprint=%s", sprint))
+		filters = append(filters, fmt.Sprintf("sprint=%s", SanitizeQueryValue(sprint)))
 	}
 	if assignedTo != "" {
-		filters = append(filters, fmt.Sprintf("assigned_to=%s", assignedTo))
+		userID, err := r.resolveUser(assignedTo)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assigned_to", err)), nil
+		}
+		filters = append(filters, fmt.Sprintf("assigned_to=%s", userID))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/rm_story", params)
+	result, page, err := r.listRecords("/table/rm_story", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list stories", err)), nil
 	}
@@ -473,7 +513,7 @@ func (r *Registry) listStories(args map[string]interface{}) (*mcp.CallToolResult
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				stories = append(stories, map[string]interface{}{
+				stories = append(stories, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"number":            data["number"],
 					"short_description": data["short_description"],
@@ -482,16 +522,16 @@ func (r *Registry) listStories(args map[string]interface{}) (*mcp.CallToolResult
 					"sprint":            data["sprint"],
 					"epic":              data["epic"],
 					"blocked":           data["blocked"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Found %d stories", len(stories)),
 		"stories": stories,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) listEpics(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -501,23 +541,25 @@ func (r *Registry) listEpics(args map[string]interface{}) (*mcp.CallToolResult,
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,state,product"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
 	if product != "" {
-		filters = append(filters, fmt.Sprintf("product=%s", product))
+		filters = append(filters, fmt.Sprintf("product=%s", SanitizeQueryValue(product)))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/rm_epic", params)
+	result, page, err := r.listRecords("/table/rm_epic", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list epics", err)), nil
 	}
@@ -526,22 +568,22 @@ func (r *Registry) listEpics(args map[string]interface{}) (*mcp.CallToolResult,
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				epics = append(epics, map[string]interface{}{
+				epics = append(epics, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"number":            data["number"],
 					"short_description": data["short_description"],
 					"state":             data["state"],
 					"product":           data["product"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Found %d epics", len(epics)),
 		"epics":   epics,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) listScrumTasks(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -552,26 +594,32 @@ func (r *Registry) listScrumTasks(args map[string]interface{}) (*mcp.CallToolRes
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,state,story,type,time_remaining"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if story != "" {
-		filters = append(filters, fmt.Sprintf("story=%s", story))
+		filters = append(filters, fmt.Sprintf("story=%s", SanitizeQueryValue(story)))
 	}
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
 	if assignedTo != "" {
-		filters = append(filters, fmt.Sprintf("assigned_to=%s", assignedTo))
+		userID, err := r.resolveUser(assignedTo)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assigned_to", err)), nil
+		}
+		filters = append(filters, fmt.Sprintf("assigned_to=%s", userID))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/rm_scrum_task", params)
+	result, page, err := r.listRecords("/table/rm_scrum_task", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list scrum tasks", err)), nil
 	}
@@ -580,7 +628,7 @@ func (r *Registry) listScrumTasks(args map[string]interface{}) (*mcp.CallToolRes
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				tasks = append(tasks, map[string]interface{}{
+				tasks = append(tasks, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"number":            data["number"],
 					"short_description": data["short_description"],
@@ -588,16 +636,16 @@ func (r *Registry) listScrumTasks(args map[string]interface{}) (*mcp.CallToolRes
 					"story":             data["story"],
 					"type":              data["type"],
 					"time_remaining":    data["time_remaining"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":     true,
 		"message":     fmt.Sprintf("Found %d scrum tasks", len(tasks)),
 		"scrum_tasks": tasks,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) listProjects(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -606,27 +654,25 @@ func (r *Registry) listProjects(args map[string]interface{}) (*mcp.CallToolResul
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,state,start_date,end_date,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
-	if active, exists := args["active"]; exists {
-		if active.(bool) {
-			filters = append(filters, "active=true")
-		} else {
-			filters = append(filters, "active=false")
-		}
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/pm_project", params)
+	result, page, err := r.listRecords("/table/pm_project", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list projects", err)), nil
 	}
@@ -635,7 +681,7 @@ func (r *Registry) listProjects(args map[string]interface{}) (*mcp.CallToolResul
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				projects = append(projects, map[string]interface{}{
+				projects = append(projects, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"number":            data["number"],
 					"short_description": data["short_description"],
@@ -643,16 +689,16 @@ func (r *Registry) listProjects(args map[string]interface{}) (*mcp.CallToolResul
 					"start_date":        data["start_date"],
 					"end_date":          data["end_date"],
 					"active":            data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":  true,
 		"message":  fmt.Sprintf("Found %d projects", len(projects)),
 		"projects": projects,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) createStory(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -684,8 +730,8 @@ func (r *Registry) createStory(args map[string]interface{}) (*mcp.CallToolResult
 	if v := GetIntArg(args, "story_points", 0); v > 0 {
 		data["story_points"] = v
 	}
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
 	}
 
 	result, err := r.client.Post("/table/rm_story", data)
@@ -715,13 +761,17 @@ func (r *Registry) updateStory(args map[string]interface{}) (*mcp.CallToolResult
 		return JSONResult(NewErrorResponse("story_id is required", nil)), nil
 	}
 
+	sysID, err := r.resolveStoryID(storyID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find story", err)), nil
+	}
+
 	data := map[string]interface{}{}
 
-	if v := GetStringArg(args, "short_description", ""); v != "" {
-		data["short_description"] = v
-	}
-	if v := GetStringArg(args, "state", ""); v != "" {
-		data["state"] = v
+	for _, field := range []string{"short_description", "description", "acceptance_criteria", "state", "work_notes"} {
+		if v := GetStringArg(args, field, ""); v != "" {
+			data[field] = v
+		}
 	}
 	if v := GetIntArg(args, "story_points", 0); v > 0 {
 		data["story_points"] = v
@@ -729,18 +779,48 @@ func (r *Registry) updateStory(args map[string]interface{}) (*mcp.CallToolResult
 	if v, exists := args["blocked"]; exists {
 		data["blocked"] = v
 	}
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
+	}
+	if v := strings.TrimSpace(GetStringArg(args, "sprint", "")); v != "" {
+		sprintID := ""
+		if !strings.EqualFold(v, backlogSprint) {
+			if sprintID, err = r.resolveRecordNumber("rm_sprint", v, "sprint"); err != nil {
+				return JSONResult(NewErrorResponse("Failed to resolve sprint", err)), nil
+			}
+		}
+		data["sprint"] = sprintID
+	}
+	if v := GetStringArg(args, "epic", ""); v != "" {
+		epicID, err := r.resolveRecordNumber("rm_epic", v, "epic")
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve epic", err)), nil
+		}
+		data["epic"] = epicID
+	}
+
+	if len(data) == 0 {
+		return JSONResult(NewErrorResponse("At least one field to update is required", nil)), nil
+	}
+
+	unresolved := r.expandJournalMentions(data)
 
-	result, err := r.client.Put(fmt.Sprintf("/table/rm_story/%s", storyID), data)
+	result, err := r.client.Put(fmt.Sprintf("/table/rm_story/%s", sysID), data)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to update story", err)), nil
 	}
 
 	if resultData, ok := result["result"].(map[string]interface{}); ok {
-		return JSONResult(map[string]interface{}{
-			"success":  true,
-			"message":  "Story updated successfully",
-			"story_id": resultData["sys_id"],
-		}), nil
+		response := map[string]interface{}{
+			"success":      true,
+			"message":      "Story updated successfully",
+			"story_id":     resultData["sys_id"],
+			"story_number": resultData["number"],
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
@@ -839,8 +919,8 @@ func (r *Registry) createScrumTask(args map[string]interface{}) (*mcp.CallToolRe
 	if v := GetStringArg(args, "type", ""); v != "" {
 		data["type"] = v
 	}
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
 	}
 	if v := GetIntArg(args, "time_remaining", 0); v > 0 {
 		data["time_remaining"] = v
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestUpdateStory tests that update_story resolves the story, sprint, and epic from their numbers and sets the grooming fields
+func TestUpdateStory(t *testing.T) {
+	const (
+		storyID  = "11111111111111111111111111111111"
+		sprintID = "22222222222222222222222222222222"
+		epicID   = "33333333333333333333333333333333"
+		userID   = "44444444444444444444444444444444"
+	)
+
+	var path string
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		query := r.URL.Query().Get("sysparm_query")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_story" && query == "number=STRY0010001":
+			result = []interface{}{map[string]interface{}{"sys_id": storyID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_story":
+			result = []interface{}{}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_sprint":
+			result = []interface{}{map[string]interface{}{"sys_id": sprintID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_epic":
+			result = []interface{}{map[string]interface{}{"sys_id": epicID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			result = []interface{}{map[string]interface{}{"sys_id": userID}}
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/now/table/rm_story/"):
+			path = r.URL.Path
+			updated = nil
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			result = map[string]interface{}{"sys_id": storyID, "number": "STRY0010001"}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.updateStory(map[string]interface{}{
+		"story_id": "STRY0010001", "acceptance_criteria": "Given a user, when they log in, then they see the dashboard",
+		"assigned_to": "abel.tuter", "sprint": "SPNT0010001", "epic": "EPIC0010001", "work_notes": "Groomed",
+	})
+	if !strings.Contains(result.Content[0].Text, `"story_number": "STRY0010001"`) {
+		t.Fatalf("Expected the story to be updated, got %s", result.Content[0].Text)
+	}
+	if path != "/api/now/table/rm_story/"+storyID {
+		t.Errorf("Expected the story number to be resolved, got %s", path)
+	}
+	if updated["acceptance_criteria"] != "Given a user, when they log in, then they see the dashboard" || updated["assigned_to"] != userID ||
+		updated["sprint"] != sprintID || updated["epic"] != epicID || updated["work_notes"] != "Groomed" {
+		t.Errorf("Unexpected story payload: %+v", updated)
+	}
+
+	registry.updateStory(map[string]interface{}{"story_id": storyID, "sprint": "backlog"})
+	if sprint, ok := updated["sprint"]; !ok || sprint != "" {
+		t.Errorf("Expected the sprint to be cleared, got %+v", updated)
+	}
+
+	result, _ = registry.updateStory(map[string]interface{}{"story_id": "STRY0099999", "state": "2"})
+	if !strings.Contains(result.Content[0].Text, "NOT_FOUND") {
+		t.Errorf("Expected an unknown story to be reported, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.updateStory(map[string]interface{}{"story_id": storyID})
+	if !strings.Contains(result.Content[0].Text, "At least one field") {
+		t.Errorf("Expected an update without fields to be refused, got %s", result.Content[0].Text)
+	}
+}
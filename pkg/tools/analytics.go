@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// registerAnalyticsTools registers Performance Analytics tools (indicators, breakdowns, scores)
+func (r *Registry) registerAnalyticsTools(server *mcp.Server) int {
+	count := 0
+
+	// Helper for limit/offset constraints
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// List PA Indicators
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_pa_indicators",
+		Description: "List Performance Analytics indicators (governed KPIs). Use with get_pa_scores for trends instead of ad-hoc record counts.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+				"query": {
+					Type:        "string",
+					Description: "Search indicator name or description (e.g., 'backlog', 'MTTR')",
+				},
+				"active": {
+					Type:        "boolean",
+					Description: "Filter by active status",
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List PA Indicators",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listPAIndicators)
+	count++
+
+	// List PA Breakdowns for an indicator
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_pa_breakdowns",
+		Description: "List the breakdowns (e.g., by priority, assignment group) available for a Performance Analytics indicator.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"indicator_id": {
+					Type:        "string",
+					Description: "Indicator sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+			Required: []string{"indicator_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List PA Breakdowns",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listPABreakdowns)
+	count++
+
+	// Get PA Scores
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_pa_scores",
+		Description: "Get Performance Analytics scores over time for an indicator, optionally for a breakdown element (e.g., backlog trend for the quarter by priority). With chart, also returns a PNG line chart of the trend, or a bar chart of the elements' current scores.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withChart(map[string]mcp.Property{
+				"indicator_id": {
+					Type:        "string",
+					Description: "Indicator sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
+				},
+				"breakdown_id": {
+					Type:        "string",
+					Description: "Breakdown sys_id from list_pa_breakdowns. Without element_id, returns scores for every element",
+				},
+				"element_id": {
+					Type:        "string",
+					Description: "Breakdown element sys_id (e.g., a specific priority or group)",
+				},
+				"from": {
+					Type:        "string",
+					Description: "Earliest score date (format: YYYY-MM-DD)",
+					Format:      "date",
+				},
+				"to": {
+					Type:        "string",
+					Description: "Latest score date (format: YYYY-MM-DD)",
+					Format:      "date",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max scorecards",
+					Default:     10,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+			}),
+			Required: []string{"indicator_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get PA Scores",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getPAScores)
+	count++
+
+	return count
+}
+
+func (r *Registry) listPAIndicators(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	limit := GetIntArg(args, "limit", 20)
+	offset := GetIntArg(args, "offset", 0)
+	query := GetStringArg(args, "query", "")
+
+	params := map[string]string{
+		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_fields":                 readFields(args, "sys_id,name,description,frequency,unit,direction,type,active"),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+	}
+
+	var filters []string
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
+	}
+	if query != "" {
+		filters = append(filters, LikeFilter(query, "name", "description"))
+	}
+
+	if len(filters) > 0 {
+		params["sysparm_query"] = strings.Join(filters, "^")
+	}
+
+	result, page, err := r.listRecords("/table/pa_indicators", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list PA indicators (is Performance Analytics enabled?)", err)), nil
+	}
+
+	indicators := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		indicators = append(indicators, selectFields(map[string]interface{}{
+			"sys_id":      data["sys_id"],
+			"name":        data["name"],
+			"description": data["description"],
+			"frequency":   data["frequency"],
+			"unit":        data["unit"],
+			"direction":   data["direction"],
+			"type":        data["type"],
+			"active":      data["active"],
+		}, data, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Found %d indicators", len(indicators)),
+		"indicators": indicators,
+	}, page)), nil
+}
+
+func (r *Registry) listPABreakdowns(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	indicatorID := GetStringArg(args, "indicator_id", "")
+	if indicatorID == "" {
+		return JSONResult(NewErrorResponse("indicator_id is required", nil)), nil
+	}
+
+	params := map[string]string{
+		"sysparm_query":         fmt.Sprintf("indicator=%s", SanitizeQueryValue(indicatorID)),
+		"sysparm_fields":        "breakdown",
+		"sysparm_display_value": "all",
+		"sysparm_limit":         "100",
+		"sysparm_offset":        fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	result, page, err := r.listRecords("/table/pa_indicator_breakdowns", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list PA breakdowns", err)), nil
+	}
+
+	breakdowns := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		breakdowns = append(breakdowns, map[string]interface{}{
+			"breakdown_id": FieldValue(data["breakdown"]),
+			"name":         FieldDisplay(data["breakdown"]),
+		})
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Found %d breakdowns", len(breakdowns)),
+		"breakdowns": breakdowns,
+	}, page)), nil
+}
+
+func (r *Registry) getPAScores(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	indicatorID := GetStringArg(args, "indicator_id", "")
+	breakdownID := GetStringArg(args, "breakdown_id", "")
+	elementID := GetStringArg(args, "element_id", "")
+	limit := GetIntArg(args, "limit", 10)
+
+	if indicatorID == "" {
+		return JSONResult(NewErrorResponse("indicator_id is required", nil)), nil
+	}
+	if elementID != "" && breakdownID == "" {
+		return JSONResult(NewErrorResponse("breakdown_id is required when element_id is provided", nil)), nil
+	}
+
+	// The scorecard UUID is indicator[:breakdown[:element]]
+	uuid := indicatorID
+	if breakdownID != "" && elementID != "" {
+		uuid = fmt.Sprintf("%s:%s:%s", indicatorID, breakdownID, elementID)
+	}
+
+	params := map[string]string{
+		"sysparm_uuid":           uuid,
+		"sysparm_include_scores": "true",
+		"sysparm_display_value":  "true",
+		"sysparm_per_page":       fmt.Sprintf("%d", limit),
+	}
+	if breakdownID != "" && elementID == "" {
+		params["sysparm_breakdown"] = breakdownID
+	}
+	if v := GetStringArg(args, "from", ""); v != "" {
+		params["sysparm_from"] = v
+	}
+	if v := GetStringArg(args, "to", ""); v != "" {
+		params["sysparm_to"] = v
+	}
+
+	result, err := r.client.Get("/pa/scorecards", params)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get PA scores (is Performance Analytics enabled?)", err)), nil
+	}
+
+	scorecards := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		scorecards = append(scorecards, map[string]interface{}{
+			"indicator": data["indicator"],
+			"breakdown": data["breakdown"],
+			"element":   data["element"],
+			"value":     data["value"],
+			"change":    data["change"],
+			"target":    data["target"],
+			"frequency": data["frequency"],
+			"scores":    data["scores"],
+		})
+	}
+
+	response := JSONResult(map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Found %d scorecards", len(scorecards)),
+		"scorecards": scorecards,
+	})
+	if len(scorecards) == 1 {
+		title, points := paScoreTrend(scorecards[0])
+		return withChartImage(response, args, lineChart, title, points), nil
+	}
+	title, points := paBreakdownScores(scorecards)
+	return withChartImage(response, args, barChart, title, points), nil
+}
+
+// paScoreTrend returns the scores of one scorecard over time, oldest first, for a line chart
+func paScoreTrend(scorecard map[string]interface{}) (string, []chartPoint) {
+	title := FieldDisplay(scorecard["indicator"])
+	if element := FieldDisplay(scorecard["element"]); element != "" {
+		title += " - " + element
+	}
+	scores, _ := scorecard["scores"].([]interface{})
+	points := []chartPoint{}
+	for _, s := range scores {
+		score, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := chartNumber(score["value"]); ok {
+			label, _ := score["start_at"].(string)
+			points = append(points, chartPoint{label: label, value: value})
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].label < points[j].label })
+	// Keep the latest scores when there are more than the chart draws
+	if len(points) > maxChartPoints {
+		points = points[len(points)-maxChartPoints:]
+	}
+	return title, points
+}
+
+// paBreakdownScores returns the current score of each breakdown element, for a bar chart
+func paBreakdownScores(scorecards []map[string]interface{}) (string, []chartPoint) {
+	var title string
+	points := []chartPoint{}
+	for _, scorecard := range scorecards {
+		if value, ok := chartNumber(scorecard["value"]); ok {
+			title = FieldDisplay(scorecard["indicator"])
+			if breakdown := FieldDisplay(scorecard["breakdown"]); breakdown != "" {
+				title += " by " + breakdown
+			}
+			points = append(points, chartPoint{label: FieldDisplay(scorecard["element"]), value: value})
+		}
+	}
+	return title, points
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetPAScores tests that a scorecard is requested for the indicator, breakdown, and element, and its scores are returned and charted
+func TestGetPAScores(t *testing.T) {
+	var params map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/now/pa/scorecards" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		params = map[string]string{}
+		for key := range r.URL.Query() {
+			params[key] = r.URL.Query().Get(key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{
+			"indicator": map[string]interface{}{"value": "ind1", "display_value": "Open incidents"},
+			"element":   map[string]interface{}{"value": "p1", "display_value": "1 - Critical"},
+			"value":     "1,204",
+			"change":    "-12",
+			"frequency": "daily",
+			"scores": []interface{}{
+				map[string]interface{}{"start_at": "2024-12-02", "value": "1,216"},
+				map[string]interface{}{"start_at": "2024-12-01", "value": "1,190"},
+				map[string]interface{}{"start_at": "2024-12-03", "value": "1,204"},
+			},
+		}}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.getPAScores(map[string]interface{}{
+		"indicator_id": "ind1",
+		"breakdown_id": "prio",
+		"element_id":   "p1",
+		"from":         "2024-12-01",
+		"limit":        5,
+		"chart":        true,
+	})
+
+	if params["sysparm_uuid"] != "ind1:prio:p1" || params["sysparm_from"] != "2024-12-01" || params["sysparm_per_page"] != "5" || params["sysparm_include_scores"] != "true" {
+		t.Errorf("Unexpected scorecard request %v", params)
+	}
+	var response struct {
+		Success    bool                     `json:"success"`
+		Message    string                   `json:"message"`
+		Scorecards []map[string]interface{} `json:"scorecards"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || response.Message != "Found 1 scorecards" || len(response.Scorecards) != 1 {
+		t.Fatalf("Expected one scorecard, got %s", result.Content[0].Text)
+	}
+	scorecard := response.Scorecards[0]
+	if scorecard["value"] != "1,204" || scorecard["change"] != "-12" || FieldDisplay(scorecard["element"]) != "1 - Critical" {
+		t.Errorf("Unexpected scorecard %v", scorecard)
+	}
+	if scores, _ := scorecard["scores"].([]interface{}); len(scores) != 3 {
+		t.Errorf("Expected the three scores, got %v", scorecard["scores"])
+	}
+	if len(result.Content) != 2 || result.Content[1].Type != "image" {
+		t.Errorf("Expected a chart image after the scorecards, got %+v", result.Content)
+	}
+
+	title, points := paScoreTrend(scorecard)
+	if title != "Open incidents - 1 - Critical" || len(points) != 3 || points[0].label != "2024-12-01" || points[2].value != 1204 {
+		t.Errorf("Expected the trend oldest first, got %q %+v", title, points)
+	}
+}
+
+// TestGetPAScoresFailures tests the responses when scores are missing, unavailable, or asked for inconsistently
+func TestGetPAScoresFailures(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"message": "Requested URI does not represent any resource"}})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	tests := []struct {
+		name    string
+		status  int
+		args    map[string]interface{}
+		isError bool
+		message string
+	}{
+		{"no scorecards", http.StatusOK, map[string]interface{}{"indicator_id": "ind1", "chart": true}, false, "Found 0 scorecards"},
+		{"Performance Analytics unavailable", http.StatusNotFound, map[string]interface{}{"indicator_id": "ind1"}, true, "is Performance Analytics enabled?"},
+		{"element without breakdown", http.StatusOK, map[string]interface{}{"indicator_id": "ind1", "element_id": "p1"}, true, "breakdown_id is required"},
+		{"missing indicator", http.StatusOK, map[string]interface{}{}, true, "indicator_id is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			result, _ := registry.getPAScores(tt.args)
+			text := result.Content[0].Text
+			if strings.Contains(text, `"success": false`) != tt.isError || !strings.Contains(text, tt.message) {
+				t.Errorf("Expected error=%v with %q, got %s", tt.isError, tt.message, text)
+			}
+			if len(result.Content) != 1 {
+				t.Errorf("Expected no chart, got %d content items", len(result.Content))
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// appFileTypes maps the artifact types search_application_files takes to the tables
+// (sys_class_name) of their application files
+var appFileTypes = map[string]string{
+	"business_rule":  "sys_script",
+	"client_script":  "sys_script_client",
+	"script_include": "sys_script_include",
+	"ui_action":      "sys_ui_action",
+	"ui_policy":      "sys_ui_policy",
+	"ui_page":        "sys_ui_page",
+	"widget":         "sp_widget",
+	"flow":           "sys_hub_flow",
+	"action":         "sys_hub_action_type_definition",
+	"acl":            "sys_security_acl",
+	"scheduled_job":  "sysauto_script",
+	"rest_api":       "sys_ws_operation",
+	"table":          "sys_db_object",
+}
+
+// registerAppFileTools registers the application file (sys_metadata) search tools
+func (r *Registry) registerAppFileTools(server *mcp.Server) int {
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	types := make([]string, 0, len(appFileTypes))
+	for name := range appFileTypes {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	// Search Application Files (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "search_application_files",
+		Description: "Search the application files (sys_metadata) of a scoped app by name and type, returning each artifact's type, name, and sys_id. Use it to find the widget, business rule, flow, or other artifact behind behavior being debugged.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"scope": {
+					Type:        "string",
+					Description: "Application to search: scope namespace (e.g., 'x_acme_hr'), application name, or sys_id. Use 'global' for the global scope. Omit to search every application.",
+				},
+				"query": {
+					Type:        "string",
+					Description: "Search text in the file name",
+				},
+				"type": {
+					Type:        "string",
+					Description: fmt.Sprintf("Only files of this type: one of %s, or a table name (e.g., 'sys_ui_script')", strings.Join(types, ", ")),
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Search Application Files",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).searchApplicationFiles)
+
+	return 1
+}
+
+func (r *Registry) searchApplicationFiles(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	var filters []string
+	var application map[string]interface{}
+	if scope := GetStringArg(args, "scope", ""); scope != "" {
+		sysID, err := r.resolveApplication(scope)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to find application", err)), nil
+		}
+		filters = append(filters, "sys_scope="+sysID)
+		application = map[string]interface{}{"scope": scope, "sys_id": sysID}
+	}
+	if fileType := GetStringArg(args, "type", ""); fileType != "" {
+		table, ok := appFileTypes[fileType]
+		if !ok {
+			if !tableNamePattern.MatchString(fileType) {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid type: %s", fileType), nil)), nil
+			}
+			table = fileType
+		}
+		filters = append(filters, "sys_class_name="+table)
+	}
+	if query := GetStringArg(args, "query", ""); query != "" {
+		filters = append(filters, LikeFilter(query, "sys_name"))
+	}
+
+	params := map[string]string{
+		"sysparm_query":                  strings.Join(append(filters, "ORDERBYsys_class_name^ORDERBYsys_name"), "^"),
+		"sysparm_fields":                 "sys_id,sys_name,sys_class_name,sys_scope,sys_updated_on,sys_updated_by",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 50)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	result, page, err := r.listRecords("/table/sys_metadata", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to search application files", err)), nil
+	}
+
+	files := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		files = append(files, map[string]interface{}{
+			"sys_id":     FieldValue(data["sys_id"]),
+			"name":       FieldValue(data["sys_name"]),
+			"type":       FieldValue(data["sys_class_name"]),
+			"type_label": FieldDisplay(data["sys_class_name"]),
+			"scope":      FieldDisplay(data["sys_scope"]),
+			"updated_on": FieldDisplay(data["sys_updated_on"]),
+			"updated_by": FieldValue(data["sys_updated_by"]),
+		})
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d application files", len(files)),
+		"files":   files,
+	}
+	if application != nil {
+		response["application"] = application
+	}
+	return JSONResult(withPaging(response, page)), nil
+}
+
+// resolveApplication resolves an application given as a sys_id, scope namespace, or name to
+// its sys_scope sys_id. The global scope's sys_id is "global".
+func (r *Registry) resolveApplication(scope string) (string, error) {
+	return r.resolveReference(cacheNumbers+"sys_scope:", "sys_scope", scope, "scope=%[1]s^ORname=%[1]s", "application")
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSearchApplicationFiles tests that files are searched within the resolved application, by artifact type and name
+func TestSearchApplicationFiles(t *testing.T) {
+	const scopeID = "a0000000000000000000000000000001"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		switch r.URL.Path {
+		case "/api/now/table/sys_scope":
+			if query != "scope=x_acme_hr^ORname=x_acme_hr" {
+				t.Errorf("Unexpected application query %s", query)
+			}
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + scopeID + `"}]}`))
+		case "/api/now/table/sys_metadata":
+			want := "sys_scope=" + scopeID + "^sys_class_name=sys_script^sys_nameLIKEonboard^ORDERBYsys_class_name^ORDERBYsys_name"
+			if query != want {
+				t.Errorf("Expected query %s, got %s", want, query)
+			}
+			w.Header().Set("X-Total-Count", "1")
+			_, _ = w.Write([]byte(`{"result": [{
+				"sys_id": {"value": "b0000000000000000000000000000001", "display_value": "b0000000000000000000000000000001"},
+				"sys_name": {"value": "Set onboarding tasks", "display_value": "Set onboarding tasks"},
+				"sys_class_name": {"value": "sys_script", "display_value": "Business Rule"},
+				"sys_scope": {"value": "` + scopeID + `", "display_value": "Acme HR"}
+			}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.searchApplicationFiles(map[string]interface{}{"scope": "x_acme_hr", "type": "business_rule", "query": "onboard"})
+	var response struct {
+		Success bool `json:"success"`
+		Files   []struct {
+			SysID     string `json:"sys_id"`
+			Name      string `json:"name"`
+			Type      string `json:"type"`
+			TypeLabel string `json:"type_label"`
+			Scope     string `json:"scope"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Files) != 1 {
+		t.Fatalf("Expected one file, got %s", result.Content[0].Text)
+	}
+	file := response.Files[0]
+	if file.Name != "Set onboarding tasks" || file.Type != "sys_script" || file.TypeLabel != "Business Rule" || file.Scope != "Acme HR" {
+		t.Errorf("Expected the business rule with its type and scope, got %+v", file)
+	}
+
+	result, _ = registry.searchApplicationFiles(map[string]interface{}{"type": "sys_script; drop"})
+	if !strings.Contains(result.Content[0].Text, "Invalid type") {
+		t.Errorf("Expected an invalid type to be rejected, got %s", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// approvalStates are the approval states list_my_approvals filters by
+var approvalStates = []string{"requested", "approved", "rejected", "cancelled", "not_required", "all"}
+
+// approvalActions maps the actions of respond_to_approval to approval states
+var approvalActions = map[string]string{
+	"approve": "approved",
+	"reject":  "rejected",
+}
+
+// registerApprovalTools registers the approval inbox tools, which work on approvals
+// (sysapproval_approver) of any task: requested items, changes, stories, ...
+func (r *Registry) registerApprovalTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+
+	// List My Approvals (read-only)
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "list_my_approvals",
+		Description: "List approvals waiting on you (or another approver) for any kind of record: requested items, change requests, stories, and so on. Includes approvals of approvers who delegated theirs to you. Respond with respond_to_approval.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"state": {
+					Type:        "string",
+					Description: "Approval state",
+					Enum:        approvalStates,
+					Default:     "requested",
+				},
+				"approver": {
+					Type:        "string",
+					Description: "Approver user name, email, or sys_id (default: you, with your delegators)",
+				},
+				"table": {
+					Type:        "string",
+					Description: "Only approvals of records of this class (e.g., 'sc_req_item', 'change_request', 'rm_story')",
+				},
+				"include_delegated": {
+					Type:        "boolean",
+					Description: "Include approvals of approvers who delegated theirs to you",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List My Approvals",
+			ReadOnlyHint: true,
+		},
+	}, r.listMyApprovals)
+	count++
+
+	if !r.readOnlyMode {
+		// Respond to Approval
+		r.registerToolWithContext(server, mcp.Tool{
+			Name:        "respond_to_approval",
+			Description: "Approve or reject a pending approval of any kind of record. Give the approval_id from list_my_approvals, or the record and your pending approval on it is used (yours first, then one delegated to you). Comments are required when rejecting.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"approval_id": {
+						Type:        "string",
+						Description: "Approval sys_id from list_my_approvals",
+					},
+					"record": {
+						Type:        "string",
+						Description: "Number (e.g., 'RITM0010001', 'CHG0010001', 'STRY0010001') or sys_id of the record to respond for, instead of approval_id",
+					},
+					"action": {
+						Type:        "string",
+						Description: "Response to the approval",
+						Enum:        []string{"approve", "reject"},
+					},
+					"comments": {
+						Type:        "string",
+						Description: "Comments for the approval (required when rejecting)",
+					},
+				},
+				Required: []string{"action"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Respond to Approval",
+			},
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).respondToApproval(ctx, args)
+		})
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listMyApprovals(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	state := GetStringArg(args, "state", "requested")
+	if !slices.Contains(approvalStates, state) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid state: %s (use one of %s)", state, strings.Join(approvalStates, ", ")), nil)), nil
+	}
+	table := GetStringArg(args, "table", "")
+	if table != "" && !tableNamePattern.MatchString(table) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+	}
+
+	var userID string
+	var approvers []string
+	if approver := GetStringArg(args, "approver", ""); approver != "" {
+		r = r.forContext(ctx)
+		id, err := r.resolveUser(approver)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve approver", err)), nil
+		}
+		userID, approvers = id, []string{id}
+	} else {
+		id, _, err := r.requesterIdentity(ctx)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to identify caller (pass approver)", err)), nil
+		}
+		r = r.forContext(ctx)
+		userID, approvers = id, []string{id}
+		if GetBoolArg(args, "include_delegated", true) {
+			if approvers, err = r.actionableApprovers(id); err != nil {
+				return JSONResult(NewErrorResponse("Failed to get approval delegations", err)), nil
+			}
+		}
+	}
+
+	filters := []string{"approverIN" + strings.Join(approvers, ",")}
+	if state != "all" {
+		filters = append(filters, "state="+state)
+	}
+	if table != "" {
+		filters = append(filters, "sysapproval.sys_class_name="+table)
+	}
+
+	params := map[string]string{
+		"sysparm_query":                  strings.Join(append(filters, "ORDERBYDESCsys_created_on"), "^"),
+		"sysparm_fields":                 "sys_id,state,approver,sysapproval,sysapproval.sys_class_name,sysapproval.short_description,due_date,sys_created_on,sys_updated_on",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+	result, page, err := r.listRecords("/table/sysapproval_approver", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list approvals", err)), nil
+	}
+
+	approvals := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		approval := map[string]interface{}{
+			"approval_id":  FieldValue(data["sys_id"]),
+			"state":        FieldValue(data["state"]),
+			"approver":     FieldDisplay(data["approver"]),
+			"requested_on": FieldDisplay(data["sys_created_on"]),
+			"record": map[string]interface{}{
+				"sys_id":            FieldValue(data["sysapproval"]),
+				"number":            FieldDisplay(data["sysapproval"]),
+				"class":             FieldValue(data["sysapproval.sys_class_name"]),
+				"class_label":       FieldDisplay(data["sysapproval.sys_class_name"]),
+				"short_description": FieldDisplay(data["sysapproval.short_description"]),
+			},
+		}
+		if due := FieldDisplay(data["due_date"]); due != "" {
+			approval["due_date"] = due
+		}
+		if approvalDecided(FieldValue(data["state"])) {
+			approval["decided_on"] = FieldDisplay(data["sys_updated_on"])
+		}
+		if FieldValue(data["approver"]) != userID {
+			approval["delegated"] = true
+		}
+		approvals = append(approvals, approval)
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":   true,
+		"message":   fmt.Sprintf("Found %d approvals", len(approvals)),
+		"approvals": approvals,
+	}, page)), nil
+}
+
+func (r *Registry) respondToApproval(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	approvalID := GetStringArg(args, "approval_id", "")
+	record := GetStringArg(args, "record", "")
+	action := GetStringArg(args, "action", "")
+	comments := GetStringArg(args, "comments", "")
+
+	state, ok := approvalActions[action]
+	if !ok {
+		return JSONResult(NewErrorResponse("action must be approve or reject", nil)), nil
+	}
+	if (approvalID == "") == (record == "") {
+		return JSONResult(NewErrorResponse("Provide either approval_id or record", nil)), nil
+	}
+	if action == "reject" && comments == "" {
+		return JSONResult(NewErrorResponse("comments are required when rejecting", nil)), nil
+	}
+
+	delegator := ""
+	if record != "" {
+		sysID, err := r.resolveRecordNumber("task", record, "record")
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to find record", err)), nil
+		}
+		approvalID, delegator, err = r.findPendingApproval(ctx, sysID)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to find approval record", err)), nil
+		}
+		if approvalID == "" {
+			return JSONResult(NewNotFoundResponse(fmt.Sprintf("No pending approval found for %s", record))), nil
+		}
+	} else {
+		if !IsSysID(approvalID) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid approval_id: %s", approvalID), nil)), nil
+		}
+		result, err := r.client.Get("/table/sysapproval_approver/"+approvalID, map[string]string{
+			"sysparm_fields":                 "sys_id,state,approver",
+			"sysparm_display_value":          "all",
+			"sysparm_exclude_reference_link": "true",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to get approval", err)), nil
+		}
+		approval, _ := result["result"].(map[string]interface{})
+		if current := FieldValue(approval["state"]); current != "requested" {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Approval is %s, not requested", FieldDisplay(approval["state"])), nil)), nil
+		}
+		// When the caller is known, the approval must be theirs or delegated to them
+		if userID, _, err := r.requesterIdentity(ctx); err == nil {
+			approvers, err := r.actionableApprovers(userID)
+			if err != nil {
+				return JSONResult(NewErrorResponse("Failed to get approval delegations", err)), nil
+			}
+			approver := FieldValue(approval["approver"])
+			if !slices.Contains(approvers, approver) {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Approval is for %s, who has not delegated approvals to you", FieldDisplay(approval["approver"])), nil)), nil
+			}
+			if approver != userID {
+				delegator = FieldDisplay(approval["approver"])
+			}
+		}
+	}
+
+	data := map[string]interface{}{"state": state}
+	if comments != "" {
+		data["comments"] = comments
+	}
+	result, err := r.client.Put("/table/sysapproval_approver/"+approvalID, data)
+	if err != nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to %s approval", action), err)), nil
+	}
+	if result["result"] == nil {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+
+	message := fmt.Sprintf("Approval %s", state)
+	if delegator != "" {
+		message += fmt.Sprintf(" on behalf of %s", delegator)
+	}
+	return JSONResult(map[string]interface{}{
+		"success":     true,
+		"message":     message,
+		"approval_id": approvalID,
+		"state":       state,
+	}), nil
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestApprovalInbox tests that the caller's and delegated approvals of any record are listed, and that an approval can only be actioned by its approver or their delegate
+func TestApprovalInbox(t *testing.T) {
+	const (
+		userID      = "11111111111111111111111111111111"
+		delegatorID = "22222222222222222222222222222222"
+		otherID     = "44444444444444444444444444444444"
+		approvalID  = "33333333333333333333333333333333"
+		foreignID   = "55555555555555555555555555555555"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+
+	var listQuery string
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			result = []interface{}{map[string]interface{}{"sys_id": userID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user_delegate":
+			result = []interface{}{map[string]interface{}{"user": delegatorID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sysapproval_approver":
+			listQuery = r.URL.Query().Get("sysparm_query")
+			result = []interface{}{
+				map[string]interface{}{
+					"sys_id": field(approvalID, approvalID), "state": field("requested", "Requested"),
+					"approver": field(delegatorID, "Beth Anglin"), "sysapproval": field("r1", "RITM0010001"),
+					"sysapproval.sys_class_name":    field("sc_req_item", "Requested Item"),
+					"sysapproval.short_description": field("New laptop", "New laptop"),
+				},
+				map[string]interface{}{
+					"sys_id": field("66666666666666666666666666666666", ""), "state": field("requested", "Requested"),
+					"approver": field(userID, "Jane Doe"), "sysapproval": field("s1", "STRY0010001"),
+					"sysapproval.sys_class_name": field("rm_story", "Story"),
+				},
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sysapproval_approver/"+approvalID:
+			result = map[string]interface{}{"sys_id": field(approvalID, approvalID), "state": field("requested", "Requested"), "approver": field(delegatorID, "Beth Anglin")}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sysapproval_approver/"+foreignID:
+			result = map[string]interface{}{"sys_id": field(foreignID, foreignID), "state": field("requested", "Requested"), "approver": field(otherID, "Fred Luddy")}
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/sysapproval_approver/"+approvalID:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			result = map[string]interface{}{"sys_id": approvalID}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.listMyApprovals(context.Background(), map[string]interface{}{"table": "sc_req_item"})
+	if want := "approverIN" + userID + "," + delegatorID + "^state=requested^sysapproval.sys_class_name=sc_req_item^ORDERBYDESCsys_created_on"; listQuery != want {
+		t.Errorf("Expected approval query %s, got %s", want, listQuery)
+	}
+	var response struct {
+		Approvals []struct {
+			ApprovalID string                 `json:"approval_id"`
+			Delegated  bool                   `json:"delegated"`
+			Record     map[string]interface{} `json:"record"`
+		} `json:"approvals"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(response.Approvals) != 2 || !response.Approvals[0].Delegated || response.Approvals[1].Delegated ||
+		response.Approvals[0].Record["number"] != "RITM0010001" || response.Approvals[1].Record["class"] != "rm_story" {
+		t.Errorf("Expected a delegated requested item approval and the caller's story approval, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.respondToApproval(context.Background(), map[string]interface{}{"approval_id": approvalID, "action": "reject"})
+	if !strings.Contains(result.Content[0].Text, "comments are required") {
+		t.Errorf("Expected a rejection without comments to be refused, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.respondToApproval(context.Background(), map[string]interface{}{"approval_id": approvalID, "action": "reject", "comments": "Over budget"})
+	if !strings.Contains(result.Content[0].Text, "Approval rejected on behalf of Beth Anglin") {
+		t.Fatalf("Expected a delegated rejection, got %s", result.Content[0].Text)
+	}
+	if updated["state"] != "rejected" || updated["comments"] != "Over budget" {
+		t.Errorf("Unexpected update payload: %+v", updated)
+	}
+
+	result, _ = registry.respondToApproval(context.Background(), map[string]interface{}{"approval_id": foreignID, "action": "approve"})
+	if !strings.Contains(result.Content[0].Text, "Fred Luddy, who has not delegated approvals to you") {
+		t.Errorf("Expected another approver's approval to be refused, got %s", result.Content[0].Text)
+	}
+}
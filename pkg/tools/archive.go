@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// archivePrefix prefixes the table holding a table's archived records (e.g., ar_incident)
+const archivePrefix = "ar_"
+
+// withArchived adds the include_archived argument to the properties of a tool that
+// reads historical records
+func withArchived(properties map[string]mcp.Property) map[string]mcp.Property {
+	properties["include_archived"] = mcp.Property{
+		Type:        "boolean",
+		Description: "Also read records moved to the table's archive (ar_<table>) by data archiving, after the live ones; archived records are marked archived: true",
+		Default:     false,
+	}
+	return properties
+}
+
+// archiveTable returns the table holding the archived records of table
+func archiveTable(table string) string {
+	return archivePrefix + table
+}
+
+// archiveUnavailable reports whether err is the instance refusing an archive table that
+// doesn't exist (archiving not set up for the table) or the caller can't read
+func archiveUnavailable(err error) bool {
+	var apiErr *servicenow.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// archiveArgsError refuses include_archived with auto_paginate, which reads the live
+// table alone
+func archiveArgsError(args map[string]interface{}) *ErrorResponse {
+	if GetBoolArg(args, "include_archived", false) && GetBoolArg(args, "auto_paginate", false) {
+		return NewErrorResponse("include_archived can't be combined with auto_paginate; page with limit and offset", nil)
+	}
+	return nil
+}
+
+// markArchived marks the records of a Table API list result as archived
+func markArchived(result map[string]interface{}) []interface{} {
+	records, _ := result["result"].([]interface{})
+	for _, item := range records {
+		if record, ok := item.(map[string]interface{}); ok {
+			record["archived"] = true
+		}
+	}
+	return records
+}
+
+// listRecordsWithArchive reads a page of table like listRecords and, with the
+// include_archived argument, continues into its archive table once the live records
+// run out, so limit and offset page through the live records and then the archived
+// ones. The warning is set when the archive couldn't be read. Callers check the
+// arguments with archiveArgsError first.
+func (r *Registry) listRecordsWithArchive(table string, params map[string]string, args map[string]interface{}) (map[string]interface{}, *listPage, string, error) {
+	if !GetBoolArg(args, "include_archived", false) {
+		result, page, err := r.listRecords("/table/"+table, params, args)
+		return result, page, "", err
+	}
+
+	limit, _ := strconv.Atoi(params["sysparm_limit"])
+	offset, _ := strconv.Atoi(params["sysparm_offset"])
+	result, liveTotal, err := r.client.GetWithTotalCount("/table/"+table, params)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	records, _ := result["result"].([]interface{})
+	if liveTotal < 0 {
+		// Without the live count the archive can't be lined up after the live records
+		return result, newListPage(-1, limit, offset, len(records)), "The instance didn't report how many live records match, so archived records were not read", nil
+	}
+
+	// The archive continues where the live records end. A full page of live records
+	// still reads one archived record for the archive's count.
+	archiveParams := make(map[string]string, len(params))
+	for key, value := range params {
+		archiveParams[key] = value
+	}
+	archiveParams["sysparm_offset"] = strconv.Itoa(max(offset-liveTotal, 0))
+	archiveParams["sysparm_limit"] = strconv.Itoa(max(limit-len(records), 1))
+	archived, archiveTotal, err := r.client.GetWithTotalCount("/table/"+archiveTable(table), archiveParams)
+	if archiveUnavailable(err) {
+		return result, newListPage(liveTotal, limit, offset, len(records)), fmt.Sprintf("Archived records were not read: %v", err), nil
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if len(records) < limit {
+		records = append(records, markArchived(archived)...)
+	}
+
+	total := -1
+	if archiveTotal >= 0 {
+		total = liveTotal + archiveTotal
+	}
+	return map[string]interface{}{"result": records}, newListPage(total, limit, offset, len(records)), "", nil
+}
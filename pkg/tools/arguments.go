@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Handling of arguments a tool's input schema doesn't declare (MCP_UNKNOWN_ARGUMENTS)
+const (
+	// UnknownArgumentsIgnore drops unknown arguments silently
+	UnknownArgumentsIgnore = "ignore"
+	// UnknownArgumentsWarn runs the call and appends a warning naming the unknown arguments
+	UnknownArgumentsWarn = "warn"
+	// UnknownArgumentsStrict rejects calls with unknown arguments
+	UnknownArgumentsStrict = "strict"
+)
+
+// SetUnknownArgumentMode sets how calls with arguments missing from the tool's input
+// schema are handled: ignore, warn (the default), or strict. Warnings and errors list
+// the valid arguments and suggest the closest one (e.g., assigned_to for assignee).
+func (r *Registry) SetUnknownArgumentMode(mode string) error {
+	switch mode {
+	case UnknownArgumentsIgnore, UnknownArgumentsWarn, UnknownArgumentsStrict:
+		r.unknownArguments = mode
+		return nil
+	}
+	return fmt.Errorf("unknown argument mode %q (use %s, %s, or %s)", mode, UnknownArgumentsIgnore, UnknownArgumentsWarn, UnknownArgumentsStrict)
+}
+
+// unknownArgumentsValidator rejects or warns about arguments the tool's schema doesn't declare
+func (r *Registry) unknownArgumentsValidator(call *ToolCall) error {
+	if r.unknownArguments == UnknownArgumentsIgnore {
+		return nil
+	}
+	message := unknownArgumentsMessage(call)
+	if message == "" {
+		return nil
+	}
+	if r.unknownArguments == UnknownArgumentsStrict {
+		return fmt.Errorf("%s", message)
+	}
+	call.Warnings = append(call.Warnings, fmt.Sprintf("Warning: %s. They were ignored.", message))
+	return nil
+}
+
+// unknownArgumentsMessage describes the arguments of a call missing from its tool's
+// schema, with suggestions and the valid arguments, or returns "" when there are none
+func unknownArgumentsMessage(call *ToolCall) string {
+	properties := call.Tool.InputSchema.Properties
+	valid := make([]string, 0, len(properties))
+	for name := range properties {
+		valid = append(valid, name)
+	}
+	sort.Strings(valid)
+
+	var unknown []string
+	for name := range call.Args {
+		if _, ok := properties[name]; ok {
+			continue
+		}
+		if suggestion := closestArgument(name, valid); suggestion != "" {
+			unknown = append(unknown, fmt.Sprintf("%q (did you mean %q?)", name, suggestion))
+		} else {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(unknown) == 0 {
+		return ""
+	}
+	sort.Strings(unknown)
+
+	validList := "none"
+	if len(valid) > 0 {
+		validList = strings.Join(valid, ", ")
+	}
+	return fmt.Sprintf("unknown argument(s) for %s: %s; valid arguments: %s", call.Tool.Name, strings.Join(unknown, ", "), validList)
+}
+
+// closestArgument returns the valid argument name nearest to name: a small edit away
+// (typos, e.g., "limt") or sharing a stem with it (e.g., "assignee" for "assigned_to").
+// It returns "" when none is close.
+func closestArgument(name string, valid []string) string {
+	name = strings.ToLower(name)
+	best, bestScore := "", 0
+	for _, candidate := range valid {
+		score := 0
+		if distance := editDistance(name, candidate); distance <= 2 && distance < len(candidate) {
+			score = 100 - distance
+		} else if prefix := commonPrefix(name, candidate); prefix >= 4 {
+			score = prefix
+		}
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,235 @@
+package tools
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// maxBacklogStories bounds the stories moved or ranked by one call
+const maxBacklogStories = 200
+
+// backlogSprint is the sprint argument that moves stories out of their sprint
+const backlogSprint = "backlog"
+
+// registerBacklogTools registers the sprint planning tools that update many stories at once
+func (r *Registry) registerBacklogTools(server *mcp.Server) int {
+	count := 0
+
+	rankMin := float64(1)
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Move Stories to Sprint
+		r.registerTool(server, mcp.Tool{
+			Name:        "move_stories_to_sprint",
+			Description: fmt.Sprintf("Move stories into a sprint, or back to the backlog, in one batch of up to %d stories. Each story is reported separately.", maxBacklogStories),
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"story_ids": {
+						Type:        "array",
+						Description: "Story numbers (e.g., 'STRY0010001') or sys_ids",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"sprint": {
+						Type:        "string",
+						Description: "Target sprint number (e.g., 'SPNT0010001') or sys_id, or 'backlog' to take the stories out of their sprint",
+					},
+				},
+				Required: []string{"story_ids", "sprint"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:          "Move Stories to Sprint",
+				IdempotentHint: true,
+			},
+		}, (*Registry).moveStoriesToSprint)
+		count++
+
+		// Rank Backlog
+		r.registerTool(server, mcp.Tool{
+			Name:        "rank_backlog",
+			Description: fmt.Sprintf("Rank stories in the given order, first is highest: sets their rank to start_rank, start_rank + rank_step, and so on, in one batch of up to %d stories. Stories not listed keep their rank.", maxBacklogStories),
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"story_ids": {
+						Type:        "array",
+						Description: "Story numbers or sys_ids, highest priority first",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"rank_field": {
+						Type:        "string",
+						Description: "Rank field to set: 'rank' (sprint and backlog rank) or 'global_rank' (Agile 2.0 backlogs)",
+						Enum:        []string{"rank", "global_rank"},
+						Default:     "rank",
+					},
+					"start_rank": {
+						Type:        "integer",
+						Description: "Rank of the first story",
+						Default:     100,
+						Minimum:     &rankMin,
+					},
+					"rank_step": {
+						Type:        "integer",
+						Description: "Gap between consecutive ranks, leaving room to insert stories later",
+						Default:     100,
+						Minimum:     &rankMin,
+					},
+				},
+				Required: []string{"story_ids"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:          "Rank Backlog",
+				IdempotentHint: true,
+			},
+		}, (*Registry).rankBacklog)
+		count++
+	}
+
+	return count
+}
+
+// storyUpdates resolves stories given as numbers or sys_ids and builds one update per
+// story with the body returned by update. Stories that don't resolve are reported as
+// results without a request.
+func (r *Registry) storyUpdates(storyIDs []string, update func(i int) map[string]interface{}) ([]string, []servicenow.BatchRequest, []batchItemResult, error) {
+	sysIDs, err := r.resolveRecordNumbers("rm_story", storyIDs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var ids []string
+	var requests []servicenow.BatchRequest
+	var rejected []batchItemResult
+	for i, id := range storyIDs {
+		sysID, ok := sysIDs[id]
+		if !ok {
+			rejected = append(rejected, batchItemResult{ID: id, Error: "story not found"})
+			continue
+		}
+		ids = append(ids, id)
+		requests = append(requests, servicenow.BatchRequest{
+			Method:   http.MethodPut,
+			Endpoint: "/table/rm_story/" + sysID,
+			Body:     update(i),
+		})
+	}
+	return ids, requests, rejected, nil
+}
+
+// storyBatchResponse runs the story updates and reports each story, with message
+// formatting the number updated
+func (r *Registry) storyBatchResponse(storyIDs, ids []string, requests []servicenow.BatchRequest, rejected []batchItemResult, message func(updated int) string) map[string]interface{} {
+	results := []batchItemResult{}
+	updated := 0
+	if len(requests) > 0 {
+		results, updated = r.runBatch(ids, requests)
+	}
+	results = append(results, rejected...)
+
+	text := message(updated)
+	if updated < len(storyIDs) {
+		text = fmt.Sprintf("%s (%d of %d stories); see warnings for the failures", text, updated, len(storyIDs))
+	}
+	return withWarnings(map[string]interface{}{
+		"success": updated > 0,
+		"message": text,
+		"results": results,
+	}, batchWarnings(results)...)
+}
+
+// checkStoryIDs validates the story_ids argument
+func checkStoryIDs(storyIDs []string) *ErrorResponse {
+	if len(storyIDs) == 0 {
+		return NewErrorResponse("story_ids is required", nil)
+	}
+	if len(storyIDs) > maxBacklogStories {
+		return NewErrorResponse(fmt.Sprintf("At most %d stories can be updated per call", maxBacklogStories), nil)
+	}
+	seen := map[string]bool{}
+	for _, id := range storyIDs {
+		key := strings.ToUpper(strings.TrimSpace(id))
+		if seen[key] {
+			return NewErrorResponse(fmt.Sprintf("Story %s is listed more than once", id), nil)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func (r *Registry) moveStoriesToSprint(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	storyIDs := GetStringArrayArg(args, "story_ids")
+	if errResp := checkStoryIDs(storyIDs); errResp != nil {
+		return JSONResult(errResp), nil
+	}
+	sprint := strings.TrimSpace(GetStringArg(args, "sprint", ""))
+	if sprint == "" {
+		return JSONResult(NewErrorResponse("sprint is required (use 'backlog' to take stories out of their sprint)", nil)), nil
+	}
+
+	sprintID := ""
+	target := "the backlog"
+	if !strings.EqualFold(sprint, backlogSprint) {
+		var err error
+		if sprintID, err = r.resolveRecordNumber("rm_sprint", sprint, "sprint"); err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve sprint", err)), nil
+		}
+		target = "sprint " + sprint
+	}
+
+	ids, requests, rejected, err := r.storyUpdates(storyIDs, func(int) map[string]interface{} {
+		return map[string]interface{}{"sprint": sprintID}
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to look up stories", err)), nil
+	}
+	response := r.storyBatchResponse(storyIDs, ids, requests, rejected, func(updated int) string {
+		return fmt.Sprintf("Moved %d stories to %s", updated, target)
+	})
+	response["sprint_id"] = sprintID
+	return JSONResult(response), nil
+}
+
+func (r *Registry) rankBacklog(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	storyIDs := GetStringArrayArg(args, "story_ids")
+	if errResp := checkStoryIDs(storyIDs); errResp != nil {
+		return JSONResult(errResp), nil
+	}
+	field := GetStringArg(args, "rank_field", "rank")
+	if field != "rank" && field != "global_rank" {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid rank_field: %s (use rank or global_rank)", field), nil)), nil
+	}
+	start := GetIntArg(args, "start_rank", 100)
+	step := GetIntArg(args, "rank_step", 100)
+	if start < 1 || step < 1 {
+		return JSONResult(NewErrorResponse("start_rank and rank_step must be at least 1", nil)), nil
+	}
+
+	// Ranks follow the order given, so stories that don't resolve leave a gap
+	ids, requests, rejected, err := r.storyUpdates(storyIDs, func(i int) map[string]interface{} {
+		return map[string]interface{}{field: start + i*step}
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to look up stories", err)), nil
+	}
+	response := r.storyBatchResponse(storyIDs, ids, requests, rejected, func(updated int) string {
+		return fmt.Sprintf("Ranked %d stories", updated)
+	})
+	ranks := make([]map[string]interface{}, len(storyIDs))
+	for i, id := range storyIDs {
+		ranks[i] = map[string]interface{}{"id": id, field: start + i*step}
+	}
+	response["ranks"] = ranks
+	return JSONResult(response), nil
+}
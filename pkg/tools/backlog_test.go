@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestBacklogTools tests moving stories into a sprint and back to the backlog, and ranking stories in the given order, with unknown stories reported per story
+func TestBacklogTools(t *testing.T) {
+	const (
+		storyID  = "11111111111111111111111111111111"
+		otherID  = "22222222222222222222222222222222"
+		sprintID = "33333333333333333333333333333333"
+	)
+
+	var mu sync.Mutex
+	updates := map[string]map[string]interface{}{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch {
+		case r.URL.Path == "/api/now/v1/batch":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_story":
+			result = []interface{}{map[string]interface{}{"sys_id": storyID, "number": "STRY0010001"}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_sprint":
+			result = []interface{}{map[string]interface{}{"sys_id": sprintID}}
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/now/table/rm_story/"):
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			sysID := strings.TrimPrefix(r.URL.Path, "/api/now/table/rm_story/")
+			mu.Lock()
+			updates[sysID] = body
+			mu.Unlock()
+			result = map[string]interface{}{"sys_id": sysID}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.moveStoriesToSprint(map[string]interface{}{
+		"story_ids": []interface{}{"STRY0010001", "STRY0099999", otherID}, "sprint": "SPNT0010001",
+	})
+	var response struct {
+		Results []batchItemResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(response.Results) != 3 || response.Results[2].ID != "STRY0099999" || response.Results[2].Error != "story not found" {
+		t.Errorf("Expected two moved stories and one unknown, got %s", result.Content[0].Text)
+	}
+	if updates[storyID]["sprint"] != sprintID || updates[otherID]["sprint"] != sprintID {
+		t.Errorf("Expected both stories to be moved to the sprint, got %+v", updates)
+	}
+
+	registry.moveStoriesToSprint(map[string]interface{}{"story_ids": []interface{}{otherID}, "sprint": "backlog"})
+	if sprint, ok := updates[otherID]["sprint"]; !ok || sprint != "" {
+		t.Errorf("Expected the sprint to be cleared, got %+v", updates[otherID])
+	}
+
+	result, _ = registry.rankBacklog(map[string]interface{}{
+		"story_ids": []interface{}{otherID, "STRY0010001"}, "start_rank": float64(10), "rank_step": float64(5),
+	})
+	if !strings.Contains(result.Content[0].Text, "Ranked 2 stories") {
+		t.Errorf("Expected both stories to be ranked, got %s", result.Content[0].Text)
+	}
+	if updates[otherID]["rank"] != float64(10) || updates[storyID]["rank"] != float64(15) {
+		t.Errorf("Expected ranks 10 and 15 in the given order, got %+v", updates)
+	}
+
+	result, _ = registry.rankBacklog(map[string]interface{}{"story_ids": []interface{}{"STRY0010001", "stry0010001"}})
+	if !strings.Contains(result.Content[0].Text, "listed more than once") {
+		t.Errorf("Expected a duplicate story to be refused, got %s", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// maxBatchUpdateRecords bounds the records created or updated by one batch_update call
+const maxBatchUpdateRecords = 500
+
+// batchItemResult is the outcome of one record operation of a batch
+type batchItemResult struct {
+	ID      string `json:"id"`
+	SysID   string `json:"sys_id,omitempty"`
+	Number  string `json:"number,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// batchWarnings returns a warning for each failed item of a batch, naming its ID and error
+func batchWarnings(results []batchItemResult) []string {
+	var warnings []string
+	for _, result := range results {
+		if !result.Success {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", result.ID, result.Error))
+		}
+	}
+	return warnings
+}
+
+// maxParallelRequests bounds the requests in flight when items are sent one request each
+const maxParallelRequests = 8
+
+// forEachParallel calls fn for each index below n on at most maxParallelRequests
+// goroutines and returns when all calls have finished
+func forEachParallel(n int, fn func(i int)) {
+	workers := maxParallelRequests
+	if n < workers {
+		workers = n
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// runBatch sends requests through the Batch API and reports each one under the matching
+// entry of ids. It returns the per-item results and the number that succeeded. When the
+// instance rejects the Batch API, the requests not yet run are sent one each, in parallel.
+func (r *Registry) runBatch(ids []string, requests []servicenow.BatchRequest) ([]batchItemResult, int) {
+	responses, err := r.client.Batch(requests)
+	if errors.Is(err, servicenow.ErrBatchUnavailable) {
+		if r.logger != nil {
+			r.logger.Debug("Sending %d requests individually: %v", len(requests)-len(responses), err)
+		}
+		sent := len(responses)
+		responses = append(responses, make([]servicenow.BatchResponse, len(requests)-sent)...)
+		forEachParallel(len(requests)-sent, func(i int) {
+			responses[sent+i] = r.sendRequest(requests[sent+i])
+		})
+		err = nil
+	}
+
+	results := make([]batchItemResult, len(requests))
+	succeeded := 0
+	for i := range requests {
+		results[i].ID = ids[i]
+		if i >= len(responses) {
+			results[i].Error = "not sent"
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+			continue
+		}
+		if responses[i].Err != nil {
+			results[i].Error = responses[i].Err.Error()
+			continue
+		}
+		results[i].Success = true
+		succeeded++
+		if record, ok := responses[i].Result["result"].(map[string]interface{}); ok {
+			results[i].SysID = FieldValue(record["sys_id"])
+			results[i].Number = FieldValue(record["number"])
+		}
+	}
+	return results, succeeded
+}
+
+// sendRequest sends one request of a batch on its own
+func (r *Registry) sendRequest(request servicenow.BatchRequest) servicenow.BatchResponse {
+	var result map[string]interface{}
+	var err error
+	switch request.Method {
+	case http.MethodPost:
+		result, err = r.client.Post(request.Endpoint, request.Body)
+	case http.MethodPut:
+		result, err = r.client.Put(request.Endpoint, request.Body)
+	case http.MethodDelete:
+		result, err = r.client.Delete(request.Endpoint)
+	default:
+		err = fmt.Errorf("unsupported method %s", request.Method)
+	}
+	return servicenow.BatchResponse{Result: result, Err: err}
+}
+
+// registerBatchTools registers the batch record tool
+func (r *Registry) registerBatchTools(server *mcp.Server) int {
+	count := 0
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Batch Update
+		r.registerTool(server, mcp.Tool{
+			Name:        "batch_update",
+			Description: "Create or update many records of one table in a single round trip using the Batch API (e.g., reassign 40 incidents). Give 'sys_ids' and 'fields' to set the same fields on every record, or 'records' for per-record changes. Returns a result per record.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"table": {
+						Type:        "string",
+						Description: "Table name (e.g., 'incident', 'sc_task')",
+					},
+					"sys_ids": {
+						Type:        "array",
+						Description: "sys_ids of the records to update with 'fields'",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"fields": {
+						Type:        "object",
+						Description: "Field values set on every record in 'sys_ids' (e.g., {\"assignment_group\": \"<sys_id>\", \"work_notes\": \"Reassigned to Network\"})",
+					},
+					"records": {
+						Type:        "array",
+						Description: "Per-record changes; records without a sys_id are created",
+						Items: &mcp.Property{
+							Type: "object",
+							Properties: map[string]mcp.Property{
+								"sys_id": {
+									Type:        "string",
+									Description: "Record to update; omit to create a record",
+								},
+								"fields": {
+									Type:        "object",
+									Description: "Field values to set",
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"table"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Batch Update",
+			},
+		}, (*Registry).batchUpdate)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) batchUpdate(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	table := GetStringArg(args, "table", "")
+	if !tableNamePattern.MatchString(table) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+	}
+
+	var ids []string
+	var requests []servicenow.BatchRequest
+	if sysIDs := GetStringArrayArg(args, "sys_ids"); len(sysIDs) > 0 {
+		fields := GetMapArg(args, "fields")
+		if len(fields) == 0 {
+			return JSONResult(NewErrorResponse("fields is required with sys_ids", nil)), nil
+		}
+		for _, sysID := range sysIDs {
+			if !IsSysID(sysID) {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid sys_id: %s", sysID), nil)), nil
+			}
+			ids = append(ids, sysID)
+			requests = append(requests, servicenow.BatchRequest{Method: http.MethodPut, Endpoint: fmt.Sprintf("/table/%s/%s", table, sysID), Body: fields})
+		}
+	}
+	records, _ := args["records"].([]interface{})
+	for i, item := range records {
+		record, _ := item.(map[string]interface{})
+		fields := GetMapArg(record, "fields")
+		if len(fields) == 0 {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("records[%d] has no fields", i), nil)), nil
+		}
+		sysID := GetStringArg(record, "sys_id", "")
+		switch {
+		case sysID == "":
+			ids = append(ids, fmt.Sprintf("records[%d]", i))
+			requests = append(requests, servicenow.BatchRequest{Method: http.MethodPost, Endpoint: fmt.Sprintf("/table/%s", table), Body: fields})
+		case IsSysID(sysID):
+			ids = append(ids, sysID)
+			requests = append(requests, servicenow.BatchRequest{Method: http.MethodPut, Endpoint: fmt.Sprintf("/table/%s/%s", table, sysID), Body: fields})
+		default:
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid sys_id in records[%d]: %s", i, sysID), nil)), nil
+		}
+	}
+
+	if len(requests) == 0 {
+		return JSONResult(NewErrorResponse("sys_ids with fields, or records, is required", nil)), nil
+	}
+	if len(requests) > maxBatchUpdateRecords {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Too many records: %d (at most %d per call)", len(requests), maxBatchUpdateRecords), nil)), nil
+	}
+
+	results, succeeded := r.runBatch(ids, requests)
+	return JSONResult(withWarnings(map[string]interface{}{
+		"success":   succeeded > 0,
+		"message":   fmt.Sprintf("Wrote %d of %d %s records", succeeded, len(requests), table),
+		"succeeded": succeeded,
+		"failed":    len(requests) - succeeded,
+		"results":   results,
+	}, batchWarnings(results)...)), nil
+}
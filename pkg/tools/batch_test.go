@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestBatchUpdate tests that updates and creates go out in one Batch API call with a result per record
+func TestBatchUpdate(t *testing.T) {
+	const (
+		inc1 = "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"
+		inc2 = "b1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"
+	)
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/now/v1/batch" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls++
+
+		var batch struct {
+			RestRequests []struct {
+				ID     string `json:"id"`
+				Method string `json:"method"`
+				URL    string `json:"url"`
+			} `json:"rest_requests"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+
+		var got []string
+		serviced := []interface{}{}
+		for _, request := range batch.RestRequests {
+			got = append(got, request.Method+" "+request.URL)
+			status, body := 200, fmt.Sprintf(`{"result":{"sys_id":%q,"number":"INC001"}}`, strings.TrimPrefix(request.URL, "/api/now/table/incident/"))
+			switch {
+			case request.Method == http.MethodPost:
+				status, body = 201, `{"result":{"sys_id":"new1","number":"INC002"}}`
+			case strings.HasSuffix(request.URL, inc2):
+				status, body = 403, `{"error":{"message":"ACL"}}`
+			}
+			serviced = append(serviced, map[string]interface{}{
+				"id": request.ID, "status_code": status, "body": base64.StdEncoding.EncodeToString([]byte(body)),
+			})
+		}
+		want := "PUT /api/now/table/incident/" + inc1 + ",PUT /api/now/table/incident/" + inc2 + ",POST /api/now/table/incident"
+		if strings.Join(got, ",") != want {
+			t.Errorf("Expected batched requests %s, got %s", want, strings.Join(got, ","))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"serviced_requests": serviced, "unserviced_requests": []interface{}{}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.batchUpdate(map[string]interface{}{
+		"table":   "incident",
+		"sys_ids": []interface{}{inc1, inc2},
+		"fields":  map[string]interface{}{"assignment_group": "network"},
+		"records": []interface{}{map[string]interface{}{"fields": map[string]interface{}{"short_description": "New"}}},
+	})
+
+	var body struct {
+		Succeeded int               `json:"succeeded"`
+		Failed    int               `json:"failed"`
+		Results   []batchItemResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if calls != 1 || body.Succeeded != 2 || body.Failed != 1 {
+		t.Fatalf("Expected 2 of 3 writes in one call, got %s", result.Content[0].Text)
+	}
+	if body.Results[1].ID != inc2 || body.Results[1].Success || !strings.Contains(body.Results[1].Error, "status 403") {
+		t.Errorf("Expected the second update to report the ACL failure, got %+v", body.Results[1])
+	}
+	if body.Results[2].ID != "records[0]" || body.Results[2].SysID != "new1" {
+		t.Errorf("Expected the created record in the results, got %+v", body.Results[2])
+	}
+
+	result, _ = registry.batchUpdate(map[string]interface{}{"table": "incident", "sys_ids": []interface{}{"INC0010001"}, "fields": map[string]interface{}{"state": "2"}})
+	if !strings.Contains(result.Content[0].Text, "Invalid sys_id") {
+		t.Errorf("Expected numbers to be rejected as sys_ids, got %s", result.Content[0].Text)
+	}
+}
+
+// TestBatchQuota tests that each request of a batch is charged to the write quotas, refusing those over them
+func TestBatchQuota(t *testing.T) {
+	sent := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch struct {
+			RestRequests []struct {
+				ID string `json:"id"`
+			} `json:"rest_requests"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&batch)
+		serviced := []interface{}{}
+		for _, request := range batch.RestRequests {
+			sent++
+			serviced = append(serviced, map[string]interface{}{
+				"id": request.ID, "status_code": 201, "body": base64.StdEncoding.EncodeToString([]byte(`{"result":{"sys_id":"new1"}}`)),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"serviced_requests": serviced, "unserviced_requests": []interface{}{}})
+	}))
+	defer ts.Close()
+
+	_, server := newTestRegistry(t, ts.URL, false)
+	server.SetQuotas([]mcp.QuotaRule{{Operation: mcp.QuotaCreate, Limit: 2, Window: time.Hour}})
+	record := map[string]interface{}{"fields": map[string]interface{}{"short_description": "New"}}
+	result, _ := server.CallTool(context.Background(), "batch_update", map[string]interface{}{
+		"table":   "incident",
+		"records": []interface{}{record, record, record},
+	})
+
+	var body struct {
+		Succeeded int               `json:"succeeded"`
+		Results   []batchItemResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if sent != 2 || body.Succeeded != 2 || len(body.Results) != 3 || !strings.Contains(body.Results[2].Error, "quota exceeded: max 2 create operations") {
+		t.Errorf("Expected two creates sent and the third refused, got %d sent and %s", sent, result.Content[0].Text)
+	}
+}
+
+// TestBatchFallback tests that items are sent one request each, with bounded concurrency, when the Batch API is unavailable
+func TestBatchFallback(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight, added := 0, 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/now/v1/batch":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"message":"User Not Authorized"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/sys_user_grmember":
+			mu.Lock()
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			added++
+			mu.Unlock()
+
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.Header().Set("Content-Type", "application/json")
+			if body["user"] == "u7" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error":{"message":"ACL"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"result":{"sys_id":"m-%s"}}`, body["user"])))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	userIDs := make([]interface{}, 20)
+	for i := range userIDs {
+		userIDs[i] = fmt.Sprintf("u%d", i)
+	}
+	result, _ := registry.addGroupMembers(map[string]interface{}{"group_id": "g1", "user_ids": userIDs})
+
+	var response struct {
+		Results []batchItemResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if added != 20 || len(response.Results) != 20 {
+		t.Fatalf("Expected 20 individual requests with a result each, got %d requests: %s", added, result.Content[0].Text)
+	}
+	for i, item := range response.Results {
+		if item.ID != fmt.Sprintf("u%d", i) || item.Success != (i != 7) {
+			t.Errorf("Expected results in request order with only u7 failing, got %+v at %d", item, i)
+		}
+	}
+	if maxInFlight < 2 || maxInFlight > maxParallelRequests {
+		t.Errorf("Expected between 2 and %d requests in flight, got %d", maxParallelRequests, maxInFlight)
+	}
+}
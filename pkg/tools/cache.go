@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// Key prefixes of the lookups kept in the client's cache
+const (
+	cacheUsers     = "sys_user:"
+	cacheGroups    = "sys_user_group:"
+	cacheNumbers   = "number:"
+	cacheChoices   = "choices:"
+	cacheLocations = "cmn_location:"
+)
+
+// cacheScopes maps the scopes clear_cache takes to the key prefixes they clear
+var cacheScopes = map[string]string{
+	"all":       "",
+	"users":     cacheUsers,
+	"groups":    cacheGroups,
+	"records":   cacheNumbers,
+	"choices":   cacheChoices,
+	"locations": cacheLocations,
+}
+
+// cachedTables maps the tables read by cached lookups to the key prefixes of those lookups
+var cachedTables = map[string]string{
+	"sys_user":       cacheUsers,
+	"sys_user_group": cacheGroups,
+	"sys_choice":     cacheChoices,
+	"cmn_location":   cacheLocations,
+}
+
+// invalidateCache drops the lookups a successful write to a Table API endpoint may have
+// made stale, for every caller: all user, group, choice, or location lookups after a
+// write to their table, and the record number lookups of a table after one of its
+// records is deleted
+func invalidateCache(cache *servicenow.Cache, method, endpoint string) {
+	path, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/table/"), "?")
+	if path == endpoint {
+		return
+	}
+	table, _, _ := strings.Cut(path, "/")
+	if prefix, ok := cachedTables[table]; ok {
+		cache.Clear(prefix)
+	}
+	if method == http.MethodDelete {
+		cache.Clear(cacheNumbers + table + ":")
+	}
+}
+
+// cacheKey returns the key of a lookup of value under prefix. Lookups run under the
+// caller's ACLs, so when the request carries its own ServiceNow credentials (HTTP mode)
+// the key names a hash of them, and one caller's results are never served to another.
+// Requests with the configured credentials share their entries.
+func (r *Registry) cacheKey(prefix, value string) string {
+	caller := ""
+	if client, ok := r.client.(contextClient); ok {
+		if creds := servicenow.CredentialsFromContext(client.ctx); creds != nil && (creds.APIKey != "" || creds.Username != "") {
+			sum := sha256.Sum256([]byte(creds.APIKey + "\x00" + creds.Username + "\x00" + creds.Password))
+			caller = hex.EncodeToString(sum[:12])
+		}
+	}
+	return prefix + caller + "|" + value
+}
+
+// registerCacheTools registers the lookup cache tools
+func (r *Registry) registerCacheTools(server *mcp.Server) int {
+	r.registerTool(server, mcp.Tool{
+		Name:        "clear_cache",
+		Description: "Clear the server's cache of repeated lookups: users, groups, and locations resolved from names, record numbers resolved to sys_ids, and field choice lists. Use it after renaming users or groups or changing choices on the instance. Returns the entries removed and the cache statistics.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"scope": {
+					Type:        "string",
+					Description: "Lookups to clear (default: all)",
+					Enum:        []string{"all", "users", "groups", "locations", "records", "choices"},
+					Default:     "all",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:          "Clear Cache",
+			IdempotentHint: true,
+		},
+	}, (*Registry).clearCache)
+
+	return 1
+}
+
+func (r *Registry) clearCache(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	scope := GetStringArg(args, "scope", "all")
+	prefix, ok := cacheScopes[scope]
+	if !ok {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid scope: %s (use all, users, groups, locations, records, or choices)", scope), nil)), nil
+	}
+
+	removed := r.base.Cache().Clear(prefix)
+	if r.logger != nil {
+		r.logger.Info("Cleared %d cached %s lookups", removed, scope)
+	}
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Cleared %d cached lookups (%s)", removed, scope),
+		"removed": removed,
+		"cache":   r.base.Cache().Stats(),
+	}), nil
+}
@@ -1,10 +1,16 @@
 package tools
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
 )
 
 // registerCatalogTools registers all service catalog tools
@@ -17,12 +23,12 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 	offsetMin := float64(0)
 
 	// List Catalogs
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_catalogs",
 		Description: "List available service catalogs. Catalogs contain categories which contain orderable items.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of catalogs to return (default: 50)",
@@ -30,24 +36,28 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
-			},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Catalogs",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listCatalogs(args)
-	})
+	}, (*Registry).listCatalogs)
 	count++
 
 	// List Catalog Items
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_catalog_items",
 		Description: "List service catalog items (orderable products/services) with optional filtering by category or search query.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of items to return (default: 50)",
@@ -67,49 +77,55 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 				},
 				"query": {
 					Type:        "string",
-					Description: "Search query (searches name and short_description). For advanced filtering, use ServiceNow encoded query syntax (^ for AND, | for OR)",
+					Description: "Text search in name and short_description (e.g., 'laptop')",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Catalog Items",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listCatalogItems(args)
-	})
+	}, (*Registry).listCatalogItems)
 	count++
 
 	// Get Catalog Item
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_catalog_item",
-		Description: "Get detailed information about a specific catalog item including description, price, and configuration options.",
+		Description: "Get detailed information about a specific catalog item including description, pricing (one-time and recurring price), and configuration options. Optionally includes who the item is available to, and the item picture and icon as images.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"item_id": {
 					Type:        "string",
 					Description: "Catalog item sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
 				},
-			},
+				"include_images": {
+					Type:        "boolean",
+					Description: "If true, returns the item picture and icon as image content for rendering product tiles",
+					Default:     false,
+				},
+				"include_availability": {
+					Type:        "boolean",
+					Description: "If true, returns the user criteria the item is available and not available for, and a summary of its entitlement script",
+					Default:     false,
+				},
+			}),
 			Required: []string{"item_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Catalog Item",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getCatalogItem(args)
-	})
+	}, (*Registry).getCatalogItem)
 	count++
 
 	// List Catalog Categories
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_catalog_categories",
 		Description: "List service catalog categories. Categories organize catalog items and can be nested (parent/child hierarchy).",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"catalog_id": {
 					Type:        "string",
 					Description: "Filter by catalog sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
@@ -125,44 +141,89 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
-			},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Catalog Categories",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listCatalogCategories(args)
-	})
+	}, (*Registry).listCatalogCategories)
 	count++
 
 	// List Catalog Item Variables
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_catalog_item_variables",
-		Description: "List all form variables (input fields) for a catalog item. Variables define the questions/options shown when ordering.",
+		Description: "List all form variables (input fields) for a catalog item, including those of its variable sets, as needed to fill in its order form: each variable's type, default value, choices (for select boxes and multiple choice), reference table and qualifier (for reference and list collector variables), lookup table, and the UI policy rules that show, hide, or require it.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"item_id": {
 					Type:        "string",
 					Description: "Catalog item sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
 				},
-			},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     100,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
 			Required: []string{"item_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Catalog Item Variables",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listCatalogItemVariables(args)
-	})
+	}, (*Registry).listCatalogItemVariables)
+	count++
+
+	// Request Approval Report
+	daysMin := float64(1)
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_request_approval_report",
+		Description: "Summarize catalog request approvals pending longer than N days, grouped by approver with the count and oldest wait, to find stuck approvals and nudge approvers.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"older_than_days": {
+					Type:        "integer",
+					Description: "Only count approvals requested at least this many days ago (default: 3)",
+					Default:     3,
+					Minimum:     &daysMin,
+				},
+				"limit": {
+					Type:        "number",
+					Description: "Maximum number of approvers to return, most pending approvals first (default: 50)",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Request Approval Report",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getRequestApprovalReport)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Catalog Category
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_catalog_category",
 			Description: "Create a new service catalog category. Categories organize catalog items and can be nested.",
 			InputSchema: mcp.JSONSchema{
@@ -190,13 +251,11 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Catalog Category",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createCatalogCategory(args)
-		})
+		}, (*Registry).createCatalogCategory)
 		count++
 
 		// Update Catalog Category
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_catalog_category",
 			Description: "Update an existing catalog category. At least one field besides category_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -220,13 +279,11 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Catalog Category",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateCatalogCategory(args)
-		})
+		}, (*Registry).updateCatalogCategory)
 		count++
 
 		// Update Catalog Item
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_catalog_item",
 			Description: "Update a catalog item. At least one field besides item_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -256,19 +313,34 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 						Type:        "boolean",
 						Description: "Whether the item is active and orderable",
 					},
+					"price": {
+						Type:        "string",
+						Description: "One-time price in the instance currency (e.g., '1200.00'); '0' makes the item free",
+					},
+					"recurring_price": {
+						Type:        "string",
+						Description: "Recurring price in the instance currency (e.g., '25.00'); '0' removes the recurring charge",
+					},
+					"recurring_frequency": {
+						Type:        "string",
+						Description: "How often the recurring price is charged",
+						Enum:        catalogRecurringFrequencies,
+					},
+					"omit_price": {
+						Type:        "boolean",
+						Description: "Hide the price in the catalog and cart",
+					},
 				},
 				Required: []string{"item_id"},
 			},
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Catalog Item",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateCatalogItem(args)
-		})
+		}, (*Registry).updateCatalogItem)
 		count++
 
 		// Create Catalog Item Variable
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_catalog_item_variable",
 			Description: "Create a new form variable (input field) for a catalog item. Variables define questions shown when ordering.",
 			InputSchema: mcp.JSONSchema{
@@ -305,13 +377,11 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Catalog Item Variable",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createCatalogItemVariable(args)
-		})
+		}, (*Registry).createCatalogItemVariable)
 		count++
 
 		// Move Catalog Items
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "move_catalog_items",
 			Description: "Move one or more catalog items to a different category.",
 			InputSchema: mcp.JSONSchema{
@@ -332,9 +402,53 @@ func (r *Registry) registerCatalogTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Move Catalog Items",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.moveCatalogItems(args)
-		})
+		}, (*Registry).moveCatalogItems)
+		count++
+
+		// Create Request
+		r.registerTool(server, mcp.Tool{
+			Name:        "create_request",
+			Description: "Create a service request (sc_request). Set requested_for to the end user and opened_by to the agent when submitting on someone's behalf.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"short_description": {
+						Type:        "string",
+						Description: "Brief summary of the request (e.g., 'New laptop for onboarding')",
+						MaxLength:   shortDescriptionMaxLength,
+					},
+					"description": {
+						Type:        "string",
+						Description: "Detailed description of what is being requested",
+					},
+					"requested_for": {
+						Type:        "string",
+						Description: "User the request is for (sys_id, username, or email). Defaults to opened_by.",
+					},
+					"opened_by": {
+						Type:        "string",
+						Description: "User submitting the request, e.g., a service desk agent (sys_id, username, or email). Defaults to the integration user.",
+					},
+					"special_instructions": {
+						Type:        "string",
+						Description: "Delivery or fulfillment instructions (e.g., 'Deliver to building 2 reception')",
+					},
+					"delivery_address": {
+						Type:        "string",
+						Description: "Address to deliver the order to. Defaults to the address of requested_for's location.",
+					},
+					"location_defaults": {
+						Type:        "boolean",
+						Description: "Default delivery_address to requested_for's location, and route the request to the group of the assignment rule for that location or their department. Set false to leave them empty.",
+						Default:     true,
+					},
+				},
+				Required: []string{"short_description"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create Request",
+			},
+		}, (*Registry).createRequest)
 		count++
 	}
 
@@ -346,11 +460,13 @@ func (r *Registry) listCatalogs(args map[string]interface{}) (*mcp.CallToolResul
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,title,description,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
-	result, err := r.client.Get("/table/sc_catalog", params)
+	result, page, err := r.listRecords("/table/sc_catalog", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list catalogs", err)), nil
 	}
@@ -359,21 +475,21 @@ func (r *Registry) listCatalogs(args map[string]interface{}) (*mcp.CallToolResul
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				catalogs = append(catalogs, map[string]interface{}{
+				catalogs = append(catalogs, selectFields(map[string]interface{}{
 					"sys_id":      data["sys_id"],
 					"title":       data["title"],
 					"description": data["description"],
 					"active":      data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":  true,
 		"message":  fmt.Sprintf("Found %d catalogs", len(catalogs)),
 		"catalogs": catalogs,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) listCatalogItems(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -385,23 +501,24 @@ func (r *Registry) listCatalogItems(args map[string]interface{}) (*mcp.CallToolR
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
 		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_fields":                 readFields(args, "sys_id,name,short_description,category,active,price"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if category != "" {
-		filters = append(filters, fmt.Sprintf("category=%s", category))
+		filters = append(filters, fmt.Sprintf("category=%s", SanitizeQueryValue(category)))
 	}
 	if query != "" {
-		filters = append(filters, fmt.Sprintf("nameLIKE%s^ORshort_descriptionLIKE%s", query, query))
+		filters = append(filters, LikeFilter(query, "name", "short_description"))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/sc_cat_item", params)
+	result, page, err := r.listRecords("/table/sc_cat_item", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list catalog items", err)), nil
 	}
@@ -410,23 +527,23 @@ func (r *Registry) listCatalogItems(args map[string]interface{}) (*mcp.CallToolR
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				items = append(items, map[string]interface{}{
+				items = append(items, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"name":              data["name"],
 					"short_description": data["short_description"],
 					"category":          data["category"],
 					"active":            data["active"],
 					"price":             data["price"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Found %d catalog items", len(items)),
 		"items":   items,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) getCatalogItem(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -436,6 +553,7 @@ func (r *Registry) getCatalogItem(args map[string]interface{}) (*mcp.CallToolRes
 	}
 
 	params := map[string]string{
+		"sysparm_fields":                 readFields(args, "sys_id,name,short_description,description,category,sc_catalogs,active,price,recurring_price,recurring_frequency,omit_price,availability,entitlement_script,delivery_time,order,sys_updated_on"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
@@ -445,18 +563,204 @@ func (r *Registry) getCatalogItem(args map[string]interface{}) (*mcp.CallToolRes
 		return JSONResult(NewErrorResponse("Failed to get catalog item", err)), nil
 	}
 
-	if data, ok := result["result"].(map[string]interface{}); ok {
-		return JSONResult(map[string]interface{}{
-			"success": true,
-			"message": "Catalog item found",
-			"item":    data,
-		}), nil
+	data, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Catalog item not found: %s", itemID))), nil
 	}
 
-	return JSONResult(map[string]interface{}{
-		"success": false,
-		"message": fmt.Sprintf("Catalog item not found: %s", itemID),
-	}), nil
+	response := map[string]interface{}{
+		"success": true,
+		"message": "Catalog item found",
+		"item":    selectFields(data, data, args),
+		"pricing": catalogItemPricing(data),
+	}
+
+	sysID, _ := data["sys_id"].(string)
+	if sysID == "" {
+		sysID = itemID
+	}
+	if GetBoolArg(args, "include_availability", false) {
+		availability, err := r.getCatalogItemAvailability(sysID, data)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to get catalog item availability", err)), nil
+		}
+		response["availability"] = availability
+	}
+	if !GetBoolArg(args, "include_images", false) {
+		return JSONResult(response), nil
+	}
+
+	images, warnings := r.getCatalogItemImages(sysID)
+	imageNames := []string{}
+	for _, image := range images {
+		imageNames = append(imageNames, image.name)
+	}
+	response["images"] = imageNames
+
+	toolResult := JSONResult(withWarnings(response, warnings...))
+	for _, image := range images {
+		toolResult.Content = append(toolResult.Content, image.content)
+	}
+	return toolResult, nil
+}
+
+// catalogRecurringFrequencies are the choices of sc_cat_item.recurring_frequency
+var catalogRecurringFrequencies = []string{"daily", "weekly", "weekly2", "monthly", "monthly2", "quarterly", "semiannual", "yearly"}
+
+// catalogItemPricing summarizes the one-time and recurring price of a catalog item read
+// with display values. price_model is free, one_time, recurring, or one_time_and_recurring.
+func catalogItemPricing(item map[string]interface{}) map[string]interface{} {
+	oneTime := parsePrice(FieldDisplay(item["price"])) > 0
+	recurring := parsePrice(FieldDisplay(item["recurring_price"])) > 0
+
+	model := "free"
+	switch {
+	case oneTime && recurring:
+		model = "one_time_and_recurring"
+	case oneTime:
+		model = "one_time"
+	case recurring:
+		model = "recurring"
+	}
+
+	pricing := map[string]interface{}{
+		"price_model":  model,
+		"price":        FieldDisplay(item["price"]),
+		"price_hidden": FieldDisplay(item["omit_price"]) == "true",
+	}
+	if recurring {
+		pricing["recurring_price"] = FieldDisplay(item["recurring_price"])
+		pricing["recurring_frequency"] = FieldDisplay(item["recurring_frequency"])
+	}
+	return pricing
+}
+
+// parsePrice reads the amount of a price display value (e.g., "$1,200.00" is 1200)
+func parsePrice(display string) float64 {
+	amount := strings.Map(func(c rune) rune {
+		if (c >= '0' && c <= '9') || c == '.' {
+			return c
+		}
+		return -1
+	}, display)
+	value, _ := strconv.ParseFloat(amount, 64)
+	return value
+}
+
+// getCatalogItemAvailability returns who a catalog item is available to: the user
+// criteria it is and isn't available for, where it is shown, and a summary of its
+// legacy entitlement script
+func (r *Registry) getCatalogItemAvailability(itemSysID string, item map[string]interface{}) (map[string]interface{}, error) {
+	criteria := map[string][]string{}
+	for key, table := range map[string]string{
+		"available_for":     "sc_cat_item_user_criteria_mtom",
+		"not_available_for": "sc_cat_item_user_criteria_no_mtom",
+	} {
+		result, err := r.client.Get(fmt.Sprintf("/table/%s", table), map[string]string{
+			"sysparm_query":                  fmt.Sprintf("sc_cat_item=%s", itemSysID),
+			"sysparm_fields":                 "user_criteria",
+			"sysparm_display_value":          "true",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  "100",
+		})
+		if err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, record := range GetResultList(result) {
+			names = append(names, FieldDisplay(record["user_criteria"]))
+		}
+		criteria[key] = names
+	}
+
+	availability := map[string]interface{}{
+		"active":            FieldDisplay(item["active"]) == "true",
+		"shown_on":          FieldDisplay(item["availability"]),
+		"available_for":     criteria["available_for"],
+		"not_available_for": criteria["not_available_for"],
+	}
+	if len(criteria["available_for"]) == 0 {
+		availability["available_for_everyone"] = true
+	}
+	if script := strings.TrimSpace(FieldDisplay(item["entitlement_script"])); script != "" {
+		availability["entitlement_script"] = summarizeScript(script)
+	}
+	return availability, nil
+}
+
+// summarizeScript describes a script by its length and first statement, so it can be
+// mentioned without returning the whole script
+func summarizeScript(script string) map[string]interface{} {
+	lines := strings.Split(script, "\n")
+	first := ""
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "//") && !strings.HasPrefix(line, "/*") && !strings.HasPrefix(line, "*") {
+			first = line
+			break
+		}
+	}
+	if len(first) > 120 {
+		first = first[:117] + "..."
+	}
+	return map[string]interface{}{
+		"lines":           len(lines),
+		"first_statement": first,
+	}
+}
+
+// catalogImageTable is the attachment table holding image field values of sc_cat_item records
+const catalogImageTable = "ZZ_YYsc_cat_item"
+
+// maxCatalogImageBytes caps the size of each image returned by get_catalog_item
+const maxCatalogImageBytes = 1 << 20
+
+// catalogImage is a catalog item image field ready to return as MCP image content
+type catalogImage struct {
+	name    string
+	content mcp.ContentItem
+}
+
+// getCatalogItemImages downloads the picture and icon of a catalog item. Images that are
+// missing, too large, or not an image type are skipped with a warning.
+func (r *Registry) getCatalogItemImages(itemSysID string) ([]catalogImage, []string) {
+	result, err := r.client.Get("/attachment", map[string]string{
+		"sysparm_query":  fmt.Sprintf("table_name=%s^table_sys_id=%s^file_nameINpicture,icon", catalogImageTable, itemSysID),
+		"sysparm_fields": "sys_id,file_name,content_type",
+	})
+	if err != nil {
+		return nil, []string{fmt.Sprintf("Failed to list item images: %v", err)}
+	}
+
+	var images []catalogImage
+	var warnings []string
+	for _, attachment := range GetResultList(result) {
+		attachmentID, _ := attachment["sys_id"].(string)
+		name, _ := attachment["file_name"].(string)
+
+		content, contentType, err := r.client.DownloadAttachment(attachmentID, maxCatalogImageBytes)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Skipped %s: %v", name, err))
+			continue
+		}
+		if declared, _ := attachment["content_type"].(string); strings.HasPrefix(declared, "image/") {
+			contentType = declared
+		}
+		if !strings.HasPrefix(contentType, "image/") {
+			warnings = append(warnings, fmt.Sprintf("Skipped %s: not an image (%s)", name, contentType))
+			continue
+		}
+
+		images = append(images, catalogImage{
+			name: name,
+			content: mcp.ContentItem{
+				Type:     "image",
+				MimeType: contentType,
+				Data:     base64.StdEncoding.EncodeToString(content),
+			},
+		})
+	}
+	return images, warnings
 }
 
 func (r *Registry) listCatalogCategories(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -466,23 +770,25 @@ func (r *Registry) listCatalogCategories(args map[string]interface{}) (*mcp.Call
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,title,description,parent,sc_catalog,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if catalogID != "" {
-		filters = append(filters, fmt.Sprintf("sc_catalog=%s", catalogID))
+		filters = append(filters, fmt.Sprintf("sc_catalog=%s", SanitizeQueryValue(catalogID)))
 	}
 	if parentID != "" {
-		filters = append(filters, fmt.Sprintf("parent=%s", parentID))
+		filters = append(filters, fmt.Sprintf("parent=%s", SanitizeQueryValue(parentID)))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/sc_category", params)
+	result, page, err := r.listRecords("/table/sc_category", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list catalog categories", err)), nil
 	}
@@ -491,62 +797,394 @@ func (r *Registry) listCatalogCategories(args map[string]interface{}) (*mcp.Call
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				categories = append(categories, map[string]interface{}{
+				categories = append(categories, selectFields(map[string]interface{}{
 					"sys_id":      data["sys_id"],
 					"title":       data["title"],
 					"description": data["description"],
 					"parent":      data["parent"],
 					"sc_catalog":  data["sc_catalog"],
 					"active":      data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":    true,
 		"message":    fmt.Sprintf("Found %d categories", len(categories)),
 		"categories": categories,
-	}), nil
+	}, page)), nil
 }
 
+// catalogVariableFields are the item_option_new fields a variable's definition is built from
+const catalogVariableFields = "sys_id,name,question_text,type,mandatory,order,read_only,default_value,help_text,variable_set," +
+	"reference,use_reference_qualifier,reference_qual_condition,reference_qual,dynamic_ref_qual," +
+	"lookup_table,lookup_value,lookup_label,list_table,choice_table,choice_field"
+
+// Variable types (item_option_new.type) whose definitions carry choices or a table
+const (
+	variableTypeMultipleChoice = "3"
+	variableTypeSelectBox      = "5"
+	variableTypeReference      = "8"
+	variableTypeLookupSelect   = "18"
+	variableTypeListCollector  = "21"
+)
+
+// maxCatalogVariableDetails bounds the choices, UI policies, and UI policy actions read for a page of variables
+const maxCatalogVariableDetails = 1000
+
 func (r *Registry) listCatalogItemVariables(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	itemID := GetStringArg(args, "item_id", "")
 	if itemID == "" {
 		return JSONResult(NewErrorResponse("item_id is required", nil)), nil
 	}
+	if !IsSysID(itemID) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid item_id: %s (expected a catalog item sys_id)", itemID), nil)), nil
+	}
+
+	// Details failing to load are reported under warnings, so the variables are still returned
+	var warnings []string
+
+	// The variables of the item's variable sets are part of its order form
+	query := "cat_item=" + itemID
+	sets, err := r.catalogItemVariableSets(itemID)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Variable sets not read: %v", err))
+	}
+	if len(sets) > 0 {
+		query += "^ORvariable_setIN" + strings.Join(sets, ",")
+	}
 
 	params := map[string]string{
-		"sysparm_query":                  fmt.Sprintf("cat_item=%s", itemID),
-		"sysparm_display_value":          "true",
+		"sysparm_query":                  query + "^ORDERBYorder",
+		"sysparm_fields":                 readFields(args, catalogVariableFields),
+		"sysparm_display_value":          "all",
 		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 100)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
 	}
 
-	result, err := r.client.Get("/table/item_option_new", params)
+	result, page, err := r.listRecords("/table/item_option_new", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list catalog item variables", err)), nil
 	}
+	records := GetResultList(result)
+
+	ids := make([]string, 0, len(records))
+	names := map[string]string{}
+	for _, data := range records {
+		id := FieldValue(data["sys_id"])
+		ids = append(ids, id)
+		names[id] = FieldValue(data["name"])
+	}
+
+	choices, err := r.catalogVariableChoices(ids)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Choices not read: %v", err))
+	}
+	rules, err := r.catalogVariableRules(itemID, sets, names)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("UI policy rules not read: %v", err))
+	}
 
 	variables := []map[string]interface{}{}
-	if resultList, ok := result["result"].([]interface{}); ok {
-		for _, item := range resultList {
-			if data, ok := item.(map[string]interface{}); ok {
-				variables = append(variables, map[string]interface{}{
-					"sys_id":        data["sys_id"],
-					"name":          data["name"],
-					"question_text": data["question_text"],
-					"type":          data["type"],
-					"mandatory":     data["mandatory"],
-					"order":         data["order"],
-				})
-			}
+	for _, data := range records {
+		variable := catalogVariable(data)
+		id := FieldValue(data["sys_id"])
+		if list := choices[id]; len(list) > 0 {
+			variable["choices"] = list
+		}
+		if list := rules[id]; len(list) > 0 {
+			variable["rules"] = list
 		}
+		variables = append(variables, selectFields(variable, data, args))
 	}
 
-	return JSONResult(map[string]interface{}{
+	response := map[string]interface{}{
 		"success":   true,
 		"message":   fmt.Sprintf("Found %d variables", len(variables)),
 		"variables": variables,
+	}
+	withWarnings(response, warnings...)
+	return JSONResult(withPaging(response, page)), nil
+}
+
+// catalogVariable builds a variable's definition from its item_option_new record, read
+// with display values: its default value, and the table its value is chosen from for
+// reference, lookup, and list collector variables
+func catalogVariable(data map[string]interface{}) map[string]interface{} {
+	variable := map[string]interface{}{
+		"sys_id":        FieldValue(data["sys_id"]),
+		"name":          FieldValue(data["name"]),
+		"question_text": FieldDisplay(data["question_text"]),
+		"type":          FieldDisplay(data["type"]),
+		"mandatory":     FieldDisplay(data["mandatory"]),
+		"order":         FieldDisplay(data["order"]),
+	}
+	if FieldValue(data["read_only"]) == "true" {
+		variable["read_only"] = true
+	}
+	optional := map[string]string{
+		"default_value": FieldValue(data["default_value"]),
+		"help_text":     FieldDisplay(data["help_text"]),
+		"variable_set":  FieldDisplay(data["variable_set"]),
+	}
+	for key, value := range optional {
+		if value != "" {
+			variable[key] = value
+		}
+	}
+
+	switch FieldValue(data["type"]) {
+	case variableTypeReference:
+		reference := map[string]interface{}{"table": FieldValue(data["reference"])}
+		// The qualifier restricting the records offered, by kind
+		switch kind := FieldValue(data["use_reference_qualifier"]); kind {
+		case "simple":
+			reference["qualifier_type"], reference["qualifier"] = kind, FieldValue(data["reference_qual_condition"])
+		case "advanced":
+			reference["qualifier_type"], reference["qualifier"] = kind, FieldValue(data["reference_qual"])
+		case "dynamic":
+			reference["qualifier_type"], reference["qualifier"] = kind, FieldDisplay(data["dynamic_ref_qual"])
+		}
+		if reference["qualifier"] == "" {
+			delete(reference, "qualifier")
+		}
+		variable["reference"] = reference
+	case variableTypeLookupSelect:
+		variable["lookup"] = map[string]interface{}{
+			"table":       FieldValue(data["lookup_table"]),
+			"value_field": FieldValue(data["lookup_value"]),
+			"label_field": FieldValue(data["lookup_label"]),
+		}
+	case variableTypeListCollector:
+		variable["reference"] = map[string]interface{}{"table": FieldValue(data["list_table"])}
+	case variableTypeSelectBox, variableTypeMultipleChoice:
+		// Choices may come from a field's choice list instead of question_choice records
+		if table := FieldValue(data["choice_table"]); table != "" {
+			variable["choice_list"] = map[string]interface{}{
+				"table": table,
+				"field": FieldValue(data["choice_field"]),
+			}
+		}
+	}
+	return variable
+}
+
+// catalogItemVariableSets returns the sys_ids of the variable sets attached to a catalog item
+func (r *Registry) catalogItemVariableSets(itemID string) ([]string, error) {
+	result, err := r.client.Get("/table/io_set_item", map[string]string{
+		"sysparm_query":  "sc_cat_item=" + itemID,
+		"sysparm_fields": "variable_set",
+		"sysparm_limit":  "100",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var sets []string
+	for _, record := range GetResultList(result) {
+		if id := FieldValue(record["variable_set"]); IsSysID(id) {
+			sets = append(sets, id)
+		}
+	}
+	return sets, nil
+}
+
+// catalogVariableChoices returns the active question_choice records of variables, in
+// order, keyed by variable sys_id
+func (r *Registry) catalogVariableChoices(ids []string) (map[string][]map[string]interface{}, error) {
+	choices := map[string][]map[string]interface{}{}
+	if len(ids) == 0 {
+		return choices, nil
+	}
+	result, err := r.client.Get("/table/question_choice", map[string]string{
+		"sysparm_query":  fmt.Sprintf("questionIN%s^inactive!=true^ORDERBYorder", strings.Join(ids, ",")),
+		"sysparm_fields": "question,text,value,order",
+		"sysparm_limit":  fmt.Sprintf("%d", maxCatalogVariableDetails),
+	})
+	if err != nil {
+		return choices, err
+	}
+	for _, record := range GetResultList(result) {
+		question := FieldValue(record["question"])
+		choices[question] = append(choices[question], map[string]interface{}{
+			"value": FieldValue(record["value"]),
+			"label": FieldValue(record["text"]),
+		})
+	}
+	return choices, nil
+}
+
+// catalogVariableRules returns the show/hide, mandatory, and read-only rules the active
+// catalog UI policies of an item and its variable sets apply to its variables, keyed by
+// variable sys_id. Conditions name variables instead of their IO:<sys_id> references.
+func (r *Registry) catalogVariableRules(itemID string, sets []string, names map[string]string) (map[string][]map[string]interface{}, error) {
+	rules := map[string][]map[string]interface{}{}
+	if len(names) == 0 {
+		return rules, nil
+	}
+
+	query := "active=true^catalog_item=" + itemID
+	if len(sets) > 0 {
+		query += "^ORvariable_setIN" + strings.Join(sets, ",")
+	}
+	result, err := r.client.Get("/table/catalog_ui_policy", map[string]string{
+		"sysparm_query":  query + "^ORDERBYorder",
+		"sysparm_fields": "sys_id,short_description,catalog_conditions,reverse_if_false",
+		"sysparm_limit":  fmt.Sprintf("%d", maxCatalogVariableDetails),
+	})
+	if err != nil {
+		return rules, err
+	}
+	policies := map[string]map[string]interface{}{}
+	var policyIDs []string
+	for _, record := range GetResultList(result) {
+		id := FieldValue(record["sys_id"])
+		policies[id] = record
+		policyIDs = append(policyIDs, id)
+	}
+	if len(policyIDs) == 0 {
+		return rules, nil
+	}
+
+	result, err = r.client.Get("/table/catalog_ui_policy_action", map[string]string{
+		"sysparm_query":  fmt.Sprintf("ui_policyIN%s", strings.Join(policyIDs, ",")),
+		"sysparm_fields": "ui_policy,catalog_variable,visible,mandatory,disabled",
+		"sysparm_limit":  fmt.Sprintf("%d", maxCatalogVariableDetails),
+	})
+	if err != nil {
+		return rules, err
+	}
+
+	pairs := make([]string, 0, 2*len(names))
+	for id, name := range names {
+		pairs = append(pairs, "IO:"+id, name)
+	}
+	conditions := strings.NewReplacer(pairs...)
+
+	for _, action := range GetResultList(result) {
+		variableID := strings.TrimPrefix(FieldValue(action["catalog_variable"]), "IO:")
+		policy, ok := policies[FieldValue(action["ui_policy"])]
+		if _, onPage := names[variableID]; !ok || !onPage {
+			continue
+		}
+		rule := map[string]interface{}{
+			"policy":           FieldValue(policy["short_description"]),
+			"when":             conditions.Replace(FieldValue(policy["catalog_conditions"])),
+			"reverse_if_false": FieldValue(policy["reverse_if_false"]) == "true",
+		}
+		// "ignore" leaves the setting as it is
+		for field, key := range map[string]string{"visible": "visible", "mandatory": "mandatory", "disabled": "read_only"} {
+			switch FieldValue(action[field]) {
+			case "true":
+				rule[key] = true
+			case "false":
+				rule[key] = false
+			}
+		}
+		rules[variableID] = append(rules[variableID], rule)
+	}
+	return rules, nil
+}
+
+// pendingApprover is one approver's row in the request approval report
+type pendingApprover struct {
+	ApproverID  string `json:"approver_id,omitempty"`
+	Approver    string `json:"approver"`
+	Email       string `json:"email,omitempty"`
+	Pending     int    `json:"pending"`
+	OldestSince string `json:"oldest_requested_at,omitempty"`
+	OldestDays  int    `json:"oldest_days_waiting,omitempty"`
+}
+
+func (r *Registry) getRequestApprovalReport(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	days := GetIntArg(args, "older_than_days", 3)
+	if days < 1 {
+		return JSONResult(NewErrorResponse("older_than_days must be at least 1", nil)), nil
+	}
+	limit := GetIntArg(args, "limit", 50)
+
+	// Raw values keep the grouping on approver sys_ids and dates in UTC
+	query := fmt.Sprintf("state=requested^source_tableINsc_request,sc_req_item^sys_created_on<javascript:gs.daysAgoStart(%d)", days)
+	result, err := r.client.Get("/stats/sysapproval_approver", map[string]string{
+		"sysparm_query":         query,
+		"sysparm_count":         "true",
+		"sysparm_group_by":      "approver",
+		"sysparm_min_fields":    "sys_created_on",
+		"sysparm_display_value": "false",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to aggregate pending approvals", err)), nil
+	}
+
+	now := time.Now().UTC()
+	approvers := []pendingApprover{}
+	total := 0
+	for _, group := range GetResultList(result) {
+		count, _ := statsCount(map[string]interface{}{"result": group})
+		row := pendingApprover{Pending: count, Approver: "(no approver)"}
+		if fields, ok := group["groupby_fields"].([]interface{}); ok && len(fields) > 0 {
+			if field, ok := fields[0].(map[string]interface{}); ok {
+				row.ApproverID, _ = field["value"].(string)
+			}
+		}
+		stats, _ := group["stats"].(map[string]interface{})
+		minimums, _ := stats["min"].(map[string]interface{})
+		if oldest, _ := minimums["sys_created_on"].(string); oldest != "" {
+			row.OldestSince = oldest
+			if t, err := time.Parse(dateTimeLayout, oldest); err == nil {
+				row.OldestDays = int(now.Sub(t).Hours() / 24)
+			}
+		}
+		total += count
+		approvers = append(approvers, row)
+	}
+
+	sort.SliceStable(approvers, func(i, j int) bool {
+		if approvers[i].Pending != approvers[j].Pending {
+			return approvers[i].Pending > approvers[j].Pending
+		}
+		return approvers[i].OldestDays > approvers[j].OldestDays
+	})
+	if len(approvers) > limit {
+		approvers = approvers[:limit]
+	}
+
+	// Resolve approver names and emails for the listed approvers
+	var ids []string
+	for _, row := range approvers {
+		if row.ApproverID != "" {
+			ids = append(ids, row.ApproverID)
+		}
+	}
+	if len(ids) > 0 {
+		users, err := r.client.Get("/table/sys_user", map[string]string{
+			"sysparm_query":  "sys_idIN" + strings.Join(ids, ","),
+			"sysparm_fields": "sys_id,name,email",
+			"sysparm_limit":  strconv.Itoa(len(ids)),
+		})
+		if err == nil {
+			byID := map[string]map[string]interface{}{}
+			for _, user := range GetResultList(users) {
+				byID[FieldValue(user["sys_id"])] = user
+			}
+			for i := range approvers {
+				if user, ok := byID[approvers[i].ApproverID]; ok {
+					approvers[i].Approver = FieldValue(user["name"])
+					approvers[i].Email = FieldValue(user["email"])
+				} else if approvers[i].ApproverID != "" {
+					approvers[i].Approver = approvers[i].ApproverID
+				}
+			}
+		}
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":         true,
+		"message":         fmt.Sprintf("%d request approvals pending more than %d days across %d approvers", total, days, len(GetResultList(result))),
+		"older_than_days": days,
+		"total_pending":   total,
+		"approvers":       approvers,
 	}), nil
 }
 
@@ -652,6 +1290,20 @@ func (r *Registry) updateCatalogItem(args map[string]interface{}) (*mcp.CallTool
 	if v, exists := args["active"]; exists {
 		data["active"] = v
 	}
+	for _, field := range []string{"price", "recurring_price"} {
+		if v := GetStringArg(args, field, ""); v != "" {
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid %s: %s (use a plain amount, e.g., '25.00')", field, v), nil)), nil
+			}
+			data[field] = v
+		}
+	}
+	if v := GetStringArg(args, "recurring_frequency", ""); v != "" {
+		data["recurring_frequency"] = v
+	}
+	if v, exists := args["omit_price"]; exists {
+		data["omit_price"] = v
+	}
 
 	result, err := r.client.Put(fmt.Sprintf("/table/sc_cat_item/%s", itemID), data)
 	if err != nil {
@@ -725,31 +1377,96 @@ func (r *Registry) moveCatalogItems(args map[string]interface{}) (*mcp.CallToolR
 		return JSONResult(NewErrorResponse("item_ids and target_category_id are required", nil)), nil
 	}
 
-	movedCount := 0
-	var lastErr error
-
+	requests := make([]servicenow.BatchRequest, 0, len(itemIDs))
 	for _, itemID := range itemIDs {
-		data := map[string]interface{}{
-			"category": targetCategoryID,
-		}
-
-		_, err := r.client.Put(fmt.Sprintf("/table/sc_cat_item/%s", itemID), data)
-		if err != nil {
-			lastErr = err
-		} else {
-			movedCount++
-		}
+		requests = append(requests, servicenow.BatchRequest{
+			Method:   http.MethodPut,
+			Endpoint: fmt.Sprintf("/table/sc_cat_item/%s", itemID),
+			Body:     map[string]interface{}{"category": targetCategoryID},
+		})
 	}
+	results, movedCount := r.runBatch(itemIDs, requests)
 
 	if movedCount == len(itemIDs) {
 		return JSONResult(map[string]interface{}{
 			"success": true,
 			"message": fmt.Sprintf("Successfully moved %d catalog items", movedCount),
+			"results": results,
 		}), nil
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withWarnings(map[string]interface{}{
 		"success": movedCount > 0,
-		"message": fmt.Sprintf("Moved %d of %d items. Last error: %v", movedCount, len(itemIDs), lastErr),
-	}), nil
+		"message": fmt.Sprintf("Moved %d of %d items; see warnings for the failures", movedCount, len(itemIDs)),
+		"results": results,
+	}, batchWarnings(results)...)), nil
+}
+
+func (r *Registry) createRequest(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	shortDesc := GetStringArg(args, "short_description", "")
+	if shortDesc == "" {
+		return JSONResult(NewErrorResponse("short_description is required", nil)), nil
+	}
+
+	data := map[string]interface{}{
+		"short_description": shortDesc,
+	}
+
+	if v := GetStringArg(args, "description", ""); v != "" {
+		data["description"] = v
+	}
+	if v := GetStringArg(args, "special_instructions", ""); v != "" {
+		data["special_instructions"] = v
+	}
+
+	// Resolve requested_for and opened_by to sys_ids so on-behalf-of requests are attributed correctly
+	if err := r.setReferenceArgs(args, data, []string{"requested_for", "opened_by"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
+	}
+	if _, ok := data["requested_for"]; !ok {
+		if openedBy, ok := data["opened_by"]; ok {
+			data["requested_for"] = openedBy
+		}
+	}
+	if v := GetStringArg(args, "delivery_address", ""); v != "" {
+		data["delivery_address"] = v
+	}
+
+	// The order is delivered to, and routed by, where requested_for sits
+	var locationDefaults map[string]interface{}
+	var locationWarning string
+	if requestedFor, ok := data["requested_for"].(string); ok && GetBoolArg(args, "location_defaults", true) {
+		defaults, err := r.applyLocationDefaults("sc_request", requestedFor, data, "", "delivery_address")
+		if err != nil {
+			locationWarning = fmt.Sprintf("Location defaults were not applied: %v", err)
+		}
+		locationDefaults = defaults
+	}
+
+	result, err := r.client.Post("/table/sc_request", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create request", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		response := map[string]interface{}{
+			"success":        true,
+			"message":        "Request created successfully",
+			"request_id":     resultData["sys_id"],
+			"request_number": resultData["number"],
+		}
+		if locationDefaults != nil {
+			response["location_defaults"] = locationDefaults
+		}
+		if locationWarning != "" {
+			withWarnings(response, locationWarning)
+		}
+		return JSONResult(response), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
 }
@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// catalogTaskCloseStates maps close_catalog_task outcomes to sc_task states
+var catalogTaskCloseStates = map[string]string{
+	"complete":   "3",
+	"incomplete": "4",
+	"skipped":    "7",
+}
+
+// registerCatalogTaskTools registers catalog task (sc_task) fulfillment tools
+func (r *Registry) registerCatalogTaskTools(server *mcp.Server) int {
+	count := 0
+
+	// Helper for limit/offset constraints
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// List Catalog Tasks
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_catalog_tasks",
+		Description: "List catalog tasks (sc_task) used to fulfill requested items. Filter by requested item, state, assignee, or group.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(withAutoPaginate(map[string]mcp.Property{
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+				"request_item": {
+					Type:        "string",
+					Description: "Requested item number (e.g., 'RITM0010001') or sys_id",
+				},
+				"state": {
+					Type:        "string",
+					Description: "Task state: -5=Pending, 1=Open, 2=Work in Progress, 3=Closed Complete, 4=Closed Incomplete, 7=Closed Skipped",
+					Enum:        []string{"-5", "1", "2", "3", "4", "7"},
+				},
+				"assigned_to": {
+					Type:        "string",
+					Description: "Filter by assigned user (sys_id or username, e.g., 'beth.anglin')",
+				},
+				"assignment_group": {
+					Type:        "string",
+					Description: "Filter by assignment group (sys_id or name, e.g., 'Hardware')",
+				},
+				"active": {
+					Type:        "boolean",
+					Description: "Filter by active status (open tasks only when true)",
+				},
+				"query": {
+					Type:        "string",
+					Description: "Search short description (e.g., 'laptop')",
+				},
+			})),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Catalog Tasks",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listCatalogTasks)
+	count++
+
+	// Get Catalog Task
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_catalog_task",
+		Description: "Get a catalog task (sc_task) with its requested item, request, and fulfillment details.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"task_id": {
+					Type:        "string",
+					Description: "Catalog task number (e.g., 'SCTASK0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
+				},
+			}),
+			Required: []string{"task_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Catalog Task",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getCatalogTask)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Update Catalog Task
+		r.registerTool(server, mcp.Tool{
+			Name:        "update_catalog_task",
+			Description: "Progress a catalog task: set state, assignee, or group, or add work notes/comments. Use close_catalog_task to complete it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"task_id": {
+						Type:        "string",
+						Description: "Catalog task number (e.g., 'SCTASK0010001') or sys_id. Accepts both formats.",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Task state: -5=Pending, 1=Open, 2=Work in Progress",
+						Enum:        []string{"-5", "1", "2"},
+					},
+					"assigned_to": {
+						Type:        "string",
+						Description: "User to assign the task to (sys_id or username, e.g., 'beth.anglin')",
+					},
+					"assignment_group": {
+						Type:        "string",
+						Description: "Group to assign the task to (sys_id or name, e.g., 'Hardware')",
+					},
+					"work_notes": {
+						Type:        "string",
+						Description: "Internal work note (e.g., 'Laptop imaged, awaiting pickup')",
+					},
+					"comments": {
+						Type:        "string",
+						Description: "Comment visible to the requester",
+					},
+				},
+				Required: []string{"task_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Update Catalog Task",
+			},
+		}, (*Registry).updateCatalogTask)
+		count++
+
+		// Close Catalog Task
+		r.registerTool(server, mcp.Tool{
+			Name:        "close_catalog_task",
+			Description: "Close a catalog task as complete, incomplete, or skipped with closing notes. Closing the last task lets the requested item progress.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"task_id": {
+						Type:        "string",
+						Description: "Catalog task number (e.g., 'SCTASK0010001') or sys_id. Accepts both formats.",
+					},
+					"outcome": {
+						Type:        "string",
+						Description: "Closing outcome: 'complete', 'incomplete', or 'skipped'",
+						Default:     "complete",
+						Enum:        []string{"complete", "incomplete", "skipped"},
+					},
+					"close_notes": {
+						Type:        "string",
+						Description: "Notes describing how the task was fulfilled (e.g., 'Laptop delivered to desk 4.12')",
+					},
+				},
+				Required: []string{"task_id", "close_notes"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Close Catalog Task",
+			},
+		}, (*Registry).closeCatalogTask)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listCatalogTasks(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	limit := GetIntArg(args, "limit", 20)
+	offset := GetIntArg(args, "offset", 0)
+
+	params := map[string]string{
+		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,state,request_item,request,assigned_to,assignment_group,due_date,sys_updated_on"),
+	}
+
+	var filters []string
+	if v := GetStringArg(args, "request_item", ""); v != "" {
+		if IsSysID(v) {
+			filters = append(filters, fmt.Sprintf("request_item=%s", v))
+		} else {
+			filters = append(filters, fmt.Sprintf("request_item.number=%s", SanitizeQueryValue(v)))
+		}
+	}
+	if v := GetStringArg(args, "state", ""); v != "" {
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(v)))
+	}
+	if v := GetStringArg(args, "assigned_to", ""); v != "" {
+		userID, err := r.resolveUser(v)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assigned_to", err)), nil
+		}
+		filters = append(filters, fmt.Sprintf("assigned_to=%s", userID))
+	}
+	if v := GetStringArg(args, "assignment_group", ""); v != "" {
+		groupID, err := r.resolveGroup(v)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assignment_group", err)), nil
+		}
+		filters = append(filters, fmt.Sprintf("assignment_group=%s", groupID))
+	}
+	if _, ok := args["active"]; ok {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", true)))
+	}
+	if v := GetStringArg(args, "query", ""); v != "" {
+		filters = append(filters, LikeFilter(v, "short_description"))
+	}
+	filters = append(filters, "ORDERBYDESCsys_updated_on")
+	params["sysparm_query"] = strings.Join(filters, "^")
+
+	result, page, err := r.listRecords("/table/sc_task", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list catalog tasks", err)), nil
+	}
+
+	tasks := selectRecordFields(GetResultList(result), args)
+	r.labelStates("sc_task", tasks...)
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d catalog tasks", len(tasks)),
+		"tasks":   tasks,
+	}, page)), nil
+}
+
+func (r *Registry) getCatalogTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	taskID := GetStringArg(args, "task_id", "")
+	if taskID == "" {
+		return JSONResult(NewErrorResponse("task_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveCatalogTaskID(taskID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find catalog task", err)), nil
+	}
+
+	params := map[string]string{
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,description,state,active,request_item,request,assigned_to,assignment_group,due_date,close_notes,opened_at,closed_at,sys_created_on,sys_updated_on"),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+	}
+
+	result, err := r.client.Get(fmt.Sprintf("/table/sc_task/%s", sysID), params)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get catalog task", err)), nil
+	}
+
+	if data, ok := result["result"].(map[string]interface{}); ok {
+		data = selectFields(data, data, args)
+		r.labelStates("sc_task", data)
+		return JSONResult(map[string]interface{}{
+			"success": true,
+			"message": "Catalog task found",
+			"task":    data,
+		}), nil
+	}
+
+	return JSONResult(NewNotFoundResponse(fmt.Sprintf("Catalog task not found: %s", taskID))), nil
+}
+
+func (r *Registry) updateCatalogTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	taskID := GetStringArg(args, "task_id", "")
+	if taskID == "" {
+		return JSONResult(NewErrorResponse("task_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveCatalogTaskID(taskID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find catalog task", err)), nil
+	}
+
+	data := map[string]interface{}{}
+	for _, field := range []string{"state", "work_notes", "comments"} {
+		if v := GetStringArg(args, field, ""); v != "" {
+			data[field] = v
+		}
+	}
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, []string{"assignment_group"}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user or group", err)), nil
+	}
+
+	if len(data) == 0 {
+		return JSONResult(NewErrorResponse("At least one field to update is required", nil)), nil
+	}
+
+	unresolved := r.expandJournalMentions(data)
+
+	result, err := r.client.Put(fmt.Sprintf("/table/sc_task/%s", sysID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to update catalog task", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		response := map[string]interface{}{
+			"success":     true,
+			"message":     "Catalog task updated successfully",
+			"task_id":     resultData["sys_id"],
+			"task_number": resultData["number"],
+			"state":       resultData["state"],
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) closeCatalogTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	taskID := GetStringArg(args, "task_id", "")
+	closeNotes := GetStringArg(args, "close_notes", "")
+	outcome := GetStringArg(args, "outcome", "complete")
+
+	if taskID == "" || closeNotes == "" {
+		return JSONResult(NewErrorResponse("task_id and close_notes are required", nil)), nil
+	}
+
+	state, ok := catalogTaskCloseStates[outcome]
+	if !ok {
+		return JSONResult(NewErrorResponse("outcome must be 'complete', 'incomplete', or 'skipped'", nil)), nil
+	}
+
+	sysID, err := r.resolveCatalogTaskID(taskID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find catalog task", err)), nil
+	}
+
+	data := map[string]interface{}{
+		"state":       state,
+		"close_notes": closeNotes,
+	}
+
+	result, err := r.client.Put(fmt.Sprintf("/table/sc_task/%s", sysID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to close catalog task", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     fmt.Sprintf("Catalog task closed as %s", outcome),
+			"task_id":     resultData["sys_id"],
+			"task_number": resultData["number"],
+			"state":       resultData["state"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+// resolveCatalogTaskID resolves a catalog task number to sys_id
+func (r *Registry) resolveCatalogTaskID(taskID string) (string, error) {
+	return r.resolveRecordNumber("sc_task", taskID, "catalog task")
+}
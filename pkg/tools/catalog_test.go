@@ -0,0 +1,358 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestCreateRequestOnBehalf tests that requested_for and opened_by are resolved to user sys_ids
+func TestCreateRequestOnBehalf(t *testing.T) {
+	users := map[string]string{
+		"abel.tuter":        "11111111111111111111111111111111",
+		"agent@example.com": "22222222222222222222222222222222",
+	}
+
+	var created map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			records := []interface{}{}
+			for name, sysID := range users {
+				if strings.Contains(r.URL.Query().Get("sysparm_query"), "user_name="+name) {
+					records = append(records, map[string]interface{}{"sys_id": sysID})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/sc_request":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "req1", "number": "REQ0010001"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.createRequest(map[string]interface{}{
+		"short_description": "New laptop",
+		"requested_for":     "abel.tuter",
+		"opened_by":         "agent@example.com",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "REQ0010001") {
+		t.Fatalf("Expected request to be created, got %+v", result)
+	}
+	if created["requested_for"] != users["abel.tuter"] || created["opened_by"] != users["agent@example.com"] {
+		t.Errorf("Expected resolved requested_for and opened_by, got %+v", created)
+	}
+
+	created = nil
+	result, _ = registry.createRequest(map[string]interface{}{
+		"short_description": "New laptop",
+		"requested_for":     "nobody",
+	})
+	if created != nil || !strings.Contains(result.Content[0].Text, "user not found: nobody") {
+		t.Errorf("Expected unknown requested_for to be rejected, got %+v", result)
+	}
+}
+
+// TestCloseCatalogTask tests that close outcomes map to the sc_task closed states
+func TestCloseCatalogTask(t *testing.T) {
+	const sysID = "6816f79cc0a8016401c5a33be04be441"
+
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPut || r.URL.Path != "/api/now/table/sc_task/"+sysID {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&updated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": sysID, "state": updated["state"]}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	for outcome, state := range catalogTaskCloseStates {
+		result, _ := registry.closeCatalogTask(map[string]interface{}{"task_id": sysID, "outcome": outcome, "close_notes": "done"})
+		if result.IsError || updated["state"] != state {
+			t.Errorf("Expected outcome %s to set state %s, got %+v (%+v)", outcome, state, result, updated)
+		}
+	}
+
+	result, _ := registry.closeCatalogTask(map[string]interface{}{"task_id": sysID, "outcome": "finished", "close_notes": "done"})
+	if !strings.Contains(result.Content[0].Text, "outcome must be") {
+		t.Errorf("Expected invalid outcome to be rejected, got %+v", result)
+	}
+}
+
+// TestGetCatalogItemImages tests that the item picture is returned as image content and non-images are skipped
+func TestGetCatalogItemImages(t *testing.T) {
+	const itemID = "04b7e94b4f7b4200086eeed18110c7fd"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/now/table/sc_cat_item/" + itemID:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": itemID, "name": "Laptop"}})
+		case "/api/now/attachment":
+			if q := r.URL.Query().Get("sysparm_query"); !strings.Contains(q, "table_name=ZZ_YYsc_cat_item^table_sys_id="+itemID) {
+				t.Errorf("Unexpected attachment query %q", q)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"sys_id": "pic1", "file_name": "picture", "content_type": "image/png"},
+				map[string]interface{}{"sys_id": "ico1", "file_name": "icon", "content_type": "text/plain"},
+			}})
+		case "/api/now/attachment/pic1/file":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("png-bytes"))
+		case "/api/now/attachment/ico1/file":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("not an image"))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.getCatalogItem(map[string]interface{}{"item_id": itemID})
+	if len(result.Content) != 1 {
+		t.Fatalf("Expected no images unless requested, got %d content items", len(result.Content))
+	}
+
+	result, _ = registry.getCatalogItem(map[string]interface{}{"item_id": itemID, "include_images": true})
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected JSON plus one image, got %+v", result.Content)
+	}
+	image := result.Content[1]
+	if image.Type != "image" || image.MimeType != "image/png" || image.Data != base64.StdEncoding.EncodeToString([]byte("png-bytes")) {
+		t.Errorf("Unexpected image content %+v", image)
+	}
+	if !strings.Contains(result.Content[0].Text, "Skipped icon: not an image") {
+		t.Errorf("Expected warning for the non-image icon, got %s", result.Content[0].Text)
+	}
+}
+
+// TestGetCatalogItemPricing tests the price model and availability summary of a catalog item
+func TestGetCatalogItemPricing(t *testing.T) {
+	const itemID = "04b7e94b4f7b4200086eeed18110c7fd"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sc_cat_item/" + itemID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{
+				"sys_id":              itemID,
+				"name":                "Adobe Acrobat",
+				"price":               "$0.00",
+				"recurring_price":     "$1,200.50",
+				"recurring_frequency": "Yearly",
+				"active":              "true",
+				"entitlement_script":  "// Licensed departments only\ngs.getUser().isMemberOf('Design');",
+			}})
+		case "/api/now/table/sc_cat_item_user_criteria_mtom":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"user_criteria": "Design department"},
+			}})
+		case "/api/now/table/sc_cat_item_user_criteria_no_mtom":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.getCatalogItem(map[string]interface{}{"item_id": itemID, "include_availability": true})
+
+	var body struct {
+		Pricing      map[string]interface{} `json:"pricing"`
+		Availability struct {
+			AvailableFor      []string `json:"available_for"`
+			EntitlementScript struct {
+				Lines          int    `json:"lines"`
+				FirstStatement string `json:"first_statement"`
+			} `json:"entitlement_script"`
+		} `json:"availability"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if body.Pricing["price_model"] != "recurring" || body.Pricing["recurring_frequency"] != "Yearly" {
+		t.Errorf("Expected a yearly recurring price, got %+v", body.Pricing)
+	}
+	if len(body.Availability.AvailableFor) != 1 || body.Availability.AvailableFor[0] != "Design department" {
+		t.Errorf("Expected the item to be available for Design, got %+v", body.Availability.AvailableFor)
+	}
+	if script := body.Availability.EntitlementScript; script.Lines != 2 || script.FirstStatement != "gs.getUser().isMemberOf('Design');" {
+		t.Errorf("Unexpected entitlement script summary %+v", script)
+	}
+}
+
+// TestGetRequestApprovalReport tests that pending approvals are aggregated per approver, most pending first
+func TestGetRequestApprovalReport(t *testing.T) {
+	var statsParams url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/stats/sysapproval_approver":
+			statsParams = r.URL.Query()
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{
+					"stats":          map[string]interface{}{"count": "2", "min": map[string]interface{}{"sys_created_on": "2024-01-10 09:00:00"}},
+					"groupby_fields": []interface{}{map[string]interface{}{"field": "approver", "value": "mgr1"}},
+				},
+				map[string]interface{}{
+					"stats":          map[string]interface{}{"count": "5", "min": map[string]interface{}{"sys_created_on": "2024-01-01 09:00:00"}},
+					"groupby_fields": []interface{}{map[string]interface{}{"field": "approver", "value": "mgr2"}},
+				},
+			}})
+		case "/api/now/table/sys_user":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"sys_id": "mgr1", "name": "Beth Anglin", "email": "beth.anglin@example.com"},
+				map[string]interface{}{"sys_id": "mgr2", "name": "Fred Luddy", "email": "fred.luddy@example.com"},
+			}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.getRequestApprovalReport(map[string]interface{}{"older_than_days": float64(7)})
+
+	if got := statsParams.Get("sysparm_query"); got != "state=requested^source_tableINsc_request,sc_req_item^sys_created_on<javascript:gs.daysAgoStart(7)" {
+		t.Errorf("Unexpected aggregate query %q", got)
+	}
+	if statsParams.Get("sysparm_group_by") != "approver" || statsParams.Get("sysparm_count") != "true" {
+		t.Errorf("Expected a count grouped by approver, got %v", statsParams)
+	}
+
+	var body struct {
+		TotalPending int               `json:"total_pending"`
+		Approvers    []pendingApprover `json:"approvers"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Invalid response %s: %v", result.Content[0].Text, err)
+	}
+	if body.TotalPending != 7 || len(body.Approvers) != 2 {
+		t.Fatalf("Expected 7 approvals across 2 approvers, got %+v", body)
+	}
+	first := body.Approvers[0]
+	if first.Approver != "Fred Luddy" || first.Pending != 5 || first.Email != "fred.luddy@example.com" || first.OldestDays < 1 {
+		t.Errorf("Expected Fred Luddy first with 5 pending, got %+v", first)
+	}
+}
+
+// TestListCatalogItemVariables tests that variables carry their choices, reference
+// tables, default values, and UI policy rules, including those of variable sets
+func TestListCatalogItemVariables(t *testing.T) {
+	const (
+		itemID     = "a0000000000000000000000000000001"
+		setID      = "b0000000000000000000000000000001"
+		reasonID   = "c0000000000000000000000000000001"
+		laptopID   = "c0000000000000000000000000000002"
+		managerID  = "c0000000000000000000000000000003"
+		policyID   = "d0000000000000000000000000000001"
+		referrerID = "e0000000000000000000000000000001"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+
+	var variableQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var records []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/io_set_item":
+			records = []interface{}{map[string]interface{}{"variable_set": setID}}
+		case "/api/now/table/item_option_new":
+			variableQuery = r.URL.Query().Get("sysparm_query")
+			records = []interface{}{
+				map[string]interface{}{"sys_id": field(laptopID, laptopID), "name": field("laptop", "laptop"), "type": field("5", "Select Box"),
+					"mandatory": field("true", "true"), "default_value": field("standard", "standard")},
+				map[string]interface{}{"sys_id": field(reasonID, reasonID), "name": field("reason", "reason"), "type": field("2", "Multi Line Text")},
+				map[string]interface{}{"sys_id": field(managerID, managerID), "name": field("manager", "manager"), "type": field("8", "Reference"),
+					"variable_set": field(setID, "Requester details"), "reference": field("sys_user", "User"),
+					"use_reference_qualifier": field("simple", "Simple"), "reference_qual_condition": field("active=true", "active=true")},
+			}
+		case "/api/now/table/question_choice":
+			records = []interface{}{
+				map[string]interface{}{"question": laptopID, "text": "Standard", "value": "standard"},
+				map[string]interface{}{"question": laptopID, "text": "Developer", "value": "developer"},
+			}
+		case "/api/now/table/catalog_ui_policy":
+			records = []interface{}{map[string]interface{}{"sys_id": policyID, "short_description": "Developer needs reason",
+				"catalog_conditions": "IO:" + laptopID + "=developer^EQ", "reverse_if_false": "true"}}
+		case "/api/now/table/catalog_ui_policy_action":
+			records = []interface{}{
+				map[string]interface{}{"ui_policy": policyID, "catalog_variable": "IO:" + reasonID, "visible": "true", "mandatory": "true", "disabled": "ignore"},
+				map[string]interface{}{"ui_policy": policyID, "catalog_variable": "IO:" + referrerID, "visible": "false"},
+			}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.listCatalogItemVariables(map[string]interface{}{"item_id": itemID})
+	var response struct {
+		Variables []map[string]interface{} `json:"variables"`
+		Warnings  []string                 `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !strings.Contains(variableQuery, "variable_setIN"+setID) {
+		t.Errorf("Expected the variable set's variables to be read, got query %q", variableQuery)
+	}
+	if len(response.Variables) != 3 || len(response.Warnings) != 0 {
+		t.Fatalf("Expected 3 variables without warnings, got %+v", response)
+	}
+
+	laptop, reason, manager := response.Variables[0], response.Variables[1], response.Variables[2]
+	if laptop["type"] != "Select Box" || laptop["default_value"] != "standard" {
+		t.Errorf("Expected the select box's type and default value, got %+v", laptop)
+	}
+	if choices, _ := laptop["choices"].([]interface{}); len(choices) != 2 || choices[1].(map[string]interface{})["value"] != "developer" {
+		t.Errorf("Expected the select box's choices, got %+v", laptop["choices"])
+	}
+	reference, _ := manager["reference"].(map[string]interface{})
+	if reference["table"] != "sys_user" || reference["qualifier"] != "active=true" || manager["variable_set"] != "Requester details" {
+		t.Errorf("Expected the reference table and qualifier, got %+v", manager)
+	}
+	rules, _ := reason["rules"].([]interface{})
+	if len(rules) != 1 {
+		t.Fatalf("Expected one rule on reason, got %+v", reason)
+	}
+	rule := rules[0].(map[string]interface{})
+	if rule["when"] != "laptop=developer^EQ" || rule["visible"] != true || rule["mandatory"] != true || rule["read_only"] != nil {
+		t.Errorf("Expected the rule with a named condition, got %+v", rule)
+	}
+
+	result, _ = registry.listCatalogItemVariables(map[string]interface{}{"item_id": "laptop^ORsys_id!=x"})
+	if !strings.Contains(result.Content[0].Text, "Invalid item_id") {
+		t.Errorf("Expected an invalid item_id to be rejected, got %s", result.Content[0].Text)
+	}
+}
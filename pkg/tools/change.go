@@ -1,10 +1,19 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// changeRequestFields are the fields list_change_requests builds its records from, and
+// changeRequestDetailFields the compact record returned by get_change_request
+const (
+	changeRequestFields       = "sys_id,number,short_description,type,state,priority,risk,start_date,end_date"
+	changeRequestDetailFields = changeRequestFields + ",description,impact,category,cmdb_ci,assignment_group,assigned_to,requested_by,approval,justification,implementation_plan,backout_plan,test_plan,close_code,close_notes,sys_created_on,sys_updated_on"
 )
 
 // registerChangeTools registers all change management tools
@@ -17,12 +26,12 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 	offsetMin := float64(0)
 
 	// List Change Requests
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_change_requests",
 		Description: "List change requests with optional filtering by state, type, or assignee. Returns key details for each change request.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(withAutoPaginate(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of change requests to return (default: 10)",
@@ -50,44 +59,136 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Filter by assigned user (sys_id, username, or email)",
 				},
-			},
+			})),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Change Requests",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listChangeRequests(args)
-	})
+	}, (*Registry).listChangeRequests)
 	count++
 
 	// Get Change Request Details
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_change_request",
-		Description: "Get detailed information about a specific change request including all fields, tasks, and approval status.",
+		Description: "Get detailed information about a specific change request: schedule, risk, plans, assignment, and approval status. Use fields to read other fields.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"change_id": {
 					Type:        "string",
 					Description: "Change request number (e.g., 'CHG0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
 				},
-			},
+			}),
 			Required: []string{"change_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Change Request",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getChangeRequest(args)
-	})
+	}, (*Registry).getChangeRequest)
+	count++
+
+	// Get Change Approval Chain
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_change_approval_chain",
+		Description: "Get the ordered approval records for a change request (approvers, approval groups, states, and timestamps) and which approvals it is waiting on. Use to find where a change is stuck.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"change_id": {
+					Type:        "string",
+					Description: "Change request number (e.g., 'CHG0010001') or sys_id. Accepts both formats.",
+				},
+			},
+			Required: []string{"change_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Change Approval Chain",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getChangeApprovalChain)
+	count++
+
+	// Check Change Conflicts
+	r.registerTool(server, mcp.Tool{
+		Name:        "check_change_conflicts",
+		Description: "Check a planned change window for conflicts: other open change requests scheduled in an overlapping window on the same CIs, blackout schedules it overlaps, and CI maintenance schedules it falls outside. Give a change_id to use its planned dates and affected CIs, or a window and CIs to check before creating the change.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"change_id": {
+					Type:        "string",
+					Description: "Change request number (e.g., 'CHG0010001') or sys_id whose planned window, CI, and affected CIs to check",
+				},
+				"start_date": {
+					Type:        "string",
+					Description: "Planned start in UTC (format: YYYY-MM-DD HH:MM:SS); overrides the change's planned start",
+					Format:      "date-time",
+				},
+				"end_date": {
+					Type:        "string",
+					Description: "Planned end in UTC (format: YYYY-MM-DD HH:MM:SS); overrides the change's planned end",
+					Format:      "date-time",
+				},
+				"cis": {
+					Type:        "array",
+					Description: "Configuration items (names or sys_ids) affected by the change, in addition to those of change_id. Without any CIs every overlapping change is reported.",
+					Items:       &mcp.Property{Type: "string"},
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Check Change Conflicts",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).checkChangeConflicts)
+	count++
+
+	// List Change Schedule
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_change_schedule",
+		Description: "Show the change calendar: change requests whose planned window overlaps a date range, grouped by day and assignment group (e.g., what's going in this weekend). Days are in the instance's time zone; canceled changes are left out.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"start_date": {
+					Type:        "string",
+					Description: "First day of the range (format: YYYY-MM-DD, default: today)",
+					Format:      "date",
+				},
+				"end_date": {
+					Type:        "string",
+					Description: "Last day of the range, inclusive (format: YYYY-MM-DD, default: six days after start_date). At most 31 days.",
+					Format:      "date",
+				},
+				"type": {
+					Type:        "string",
+					Description: "Filter by change type",
+					Enum:        []string{"normal", "standard", "emergency"},
+				},
+				"risk": {
+					Type:        "string",
+					Description: "Filter by risk level (1=Very High, 2=High, 3=Moderate, 4=Low)",
+					Enum:        []string{"1", "2", "3", "4"},
+				},
+				"assignment_group": {
+					Type:        "string",
+					Description: "Filter by assignment group (sys_id or name)",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Change Schedule",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listChangeSchedule)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Change Request
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_change_request",
 			Description: "Create a new change request. Returns the new change number and sys_id upon successful creation.",
 			InputSchema: mcp.JSONSchema{
@@ -96,6 +197,7 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Brief summary of the change (required)",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"type": {
 						Type:        "string",
@@ -136,10 +238,12 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					"start_date": {
 						Type:        "string",
 						Description: "Planned start date/time (format: YYYY-MM-DD HH:MM:SS)",
+						Format:      "date-time",
 					},
 					"end_date": {
 						Type:        "string",
 						Description: "Planned end date/time (format: YYYY-MM-DD HH:MM:SS)",
+						Format:      "date-time",
 					},
 				},
 				Required: []string{"short_description", "type"},
@@ -147,13 +251,11 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Change Request",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createChangeRequest(args)
-		})
+		}, (*Registry).createChangeRequest)
 		count++
 
 		// Update Change Request
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_change_request",
 			Description: "Update an existing change request. At least one field besides change_id must be provided to make changes.",
 			InputSchema: mcp.JSONSchema{
@@ -166,6 +268,7 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Brief summary of the change",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"description": {
 						Type:        "string",
@@ -173,7 +276,7 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					},
 					"state": {
 						Type:        "string",
-						Description: "Change state (-5=New, -4=Assess, -3=Authorize, -2=Scheduled, -1=Implement, 0=Review, 3=Closed, 4=Canceled)",
+						Description: "Change state (-5=New, -4=Assess, -3=Authorize, -2=Scheduled, -1=Implement, 0=Review, 3=Closed, 4=Canceled). Checked against the change's model; invalid transitions are rejected with the valid next states.",
 						Enum:        []string{"-5", "-4", "-3", "-2", "-1", "0", "3", "4"},
 					},
 					"priority": {
@@ -195,13 +298,11 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Change Request",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateChangeRequest(args)
-		})
+		}, (*Registry).updateChangeRequest)
 		count++
 
 		// Add Change Task
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "add_change_task",
 			Description: "Add a task to a change request. Tasks represent individual work items within the change implementation.",
 			InputSchema: mcp.JSONSchema{
@@ -214,6 +315,7 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Brief description of the task",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"assigned_to": {
 						Type:        "string",
@@ -222,10 +324,12 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 					"planned_start_date": {
 						Type:        "string",
 						Description: "Planned start date/time (format: YYYY-MM-DD HH:MM:SS)",
+						Format:      "date-time",
 					},
 					"planned_end_date": {
 						Type:        "string",
 						Description: "Planned end date/time (format: YYYY-MM-DD HH:MM:SS)",
+						Format:      "date-time",
 					},
 				},
 				Required: []string{"change_id", "short_description"},
@@ -233,13 +337,94 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Add Change Task",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.addChangeTask(args)
-		})
+		}, (*Registry).addChangeTask)
+		count++
+
+		// Update Change Task
+		r.registerTool(server, mcp.Tool{
+			Name:        "update_change_task",
+			Description: "Progress a change task: set state, assignee, actual start/end dates, or add work notes. Use close_change_task to complete it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"task_id": {
+						Type:        "string",
+						Description: "Change task number (e.g., 'CTASK0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Task state: -5=Pending, 1=Open, 2=In Progress, 3=Closed, 4=Canceled",
+						Enum:        []string{"-5", "1", "2", "3", "4"},
+					},
+					"assigned_to": {
+						Type:        "string",
+						Description: "User to assign the task to (sys_id, username, or email)",
+					},
+					"work_notes": {
+						Type:        "string",
+						Description: "Work note to add (e.g., 'Firewall rule deployed to staging')",
+					},
+					"actual_start_date": {
+						Type:        "string",
+						Description: "Actual start date/time (format: YYYY-MM-DD HH:MM:SS)",
+						Format:      "date-time",
+					},
+					"actual_end_date": {
+						Type:        "string",
+						Description: "Actual end date/time (format: YYYY-MM-DD HH:MM:SS)",
+						Format:      "date-time",
+					},
+				},
+				Required: []string{"task_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Update Change Task",
+			},
+		}, (*Registry).updateChangeTask)
+		count++
+
+		// Close Change Task
+		r.registerTool(server, mcp.Tool{
+			Name:        "close_change_task",
+			Description: "Close a change task with a close code and notes, recording the actual end date. Use cancel to close a task that will not be done.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"task_id": {
+						Type:        "string",
+						Description: "Change task number (e.g., 'CTASK0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
+					},
+					"close_code": {
+						Type:        "string",
+						Description: "Outcome: 'successful', 'successful_issues', or 'unsuccessful'",
+						Default:     "successful",
+						Enum:        []string{"successful", "successful_issues", "unsuccessful"},
+					},
+					"close_notes": {
+						Type:        "string",
+						Description: "Notes describing the outcome (e.g., 'Patch applied to all 4 nodes, health checks green')",
+					},
+					"actual_end_date": {
+						Type:        "string",
+						Description: "Actual end date/time (format: YYYY-MM-DD HH:MM:SS). Defaults to now.",
+						Format:      "date-time",
+					},
+					"cancel": {
+						Type:        "boolean",
+						Description: "If true, sets the task to Canceled instead of Closed",
+						Default:     false,
+					},
+				},
+				Required: []string{"task_id", "close_notes"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Close Change Task",
+			},
+		}, (*Registry).closeChangeTask)
 		count++
 
 		// Submit Change for Approval
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "submit_change_for_approval",
 			Description: "Submit a change request for approval. Moves the change to the Assess state to trigger the approval workflow.",
 			InputSchema: mcp.JSONSchema{
@@ -255,15 +440,36 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Submit Change for Approval",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.submitChangeForApproval(args)
+		}, (*Registry).submitChangeForApproval)
+		count++
+
+		// Calculate Change Risk
+		r.registerToolWithContext(server, mcp.Tool{
+			Name:        "calculate_change_risk",
+			Description: "Calculate a change request's risk and impact with the instance's risk conditions and risk assessment answers (Change Management REST API), and store them on the change. Returns the new and previous risk and impact.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"change_id": {
+						Type:        "string",
+						Description: "Change request number (e.g., 'CHG0010001') or sys_id",
+					},
+				},
+				Required: []string{"change_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:          "Calculate Change Risk",
+				IdempotentHint: true,
+			},
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).calculateChangeRisk(ctx, args)
 		})
 		count++
 
 		// Approve Change
-		server.RegisterTool(mcp.Tool{
+		r.registerToolWithContext(server, mcp.Tool{
 			Name:        "approve_change",
-			Description: "Approve a pending change request. Only works if there is a pending approval for the current user.",
+			Description: "Approve a pending change request. Only works if there is a pending approval for the current user, directly or as a delegate of the approver.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -281,15 +487,15 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Approve Change",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.approveChange(args)
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).approveChange(ctx, args)
 		})
 		count++
 
 		// Reject Change
-		server.RegisterTool(mcp.Tool{
+		r.registerToolWithContext(server, mcp.Tool{
 			Name:        "reject_change",
-			Description: "Reject a pending change request. Only works if there is a pending approval for the current user.",
+			Description: "Reject a pending change request. Only works if there is a pending approval for the current user, directly or as a delegate of the approver.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
@@ -307,8 +513,8 @@ func (r *Registry) registerChangeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Reject Change",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.rejectChange(args)
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).rejectChange(ctx, args)
 		})
 		count++
 	}
@@ -326,26 +532,31 @@ func (r *Registry) listChangeRequests(args map[string]interface{}) (*mcp.CallToo
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
 		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_fields":                 readFields(args, changeRequestFields),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
 	if changeType != "" {
-		filters = append(filters, fmt.Sprintf("type=%s", changeType))
+		filters = append(filters, fmt.Sprintf("type=%s", SanitizeQueryValue(changeType)))
 	}
 	if assignedTo != "" {
-		filters = append(filters, fmt.Sprintf("assigned_to=%s", assignedTo))
+		userID, err := r.resolveUser(assignedTo)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assigned_to", err)), nil
+		}
+		filters = append(filters, fmt.Sprintf("assigned_to=%s", userID))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/change_request", params)
+	result, page, err := r.listRecords("/table/change_request", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list change requests", err)), nil
 	}
@@ -354,7 +565,7 @@ func (r *Registry) listChangeRequests(args map[string]interface{}) (*mcp.CallToo
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				changes = append(changes, map[string]interface{}{
+				changes = append(changes, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"number":            data["number"],
 					"short_description": data["short_description"],
@@ -364,16 +575,17 @@ func (r *Registry) listChangeRequests(args map[string]interface{}) (*mcp.CallToo
 					"risk":              data["risk"],
 					"start_date":        data["start_date"],
 					"end_date":          data["end_date"],
-				})
+				}, data, args))
 			}
 		}
 	}
+	r.labelStates("change_request", changes...)
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":         true,
 		"message":         fmt.Sprintf("Found %d change requests", len(changes)),
 		"change_requests": changes,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) getChangeRequest(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -388,6 +600,7 @@ func (r *Registry) getChangeRequest(args map[string]interface{}) (*mcp.CallToolR
 	}
 
 	params := map[string]string{
+		"sysparm_fields":                 readFields(args, changeRequestDetailFields),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
@@ -398,6 +611,8 @@ func (r *Registry) getChangeRequest(args map[string]interface{}) (*mcp.CallToolR
 	}
 
 	if data, ok := result["result"].(map[string]interface{}); ok {
+		data = selectFields(data, data, args)
+		r.labelStates("change_request", data)
 		return JSONResult(map[string]interface{}{
 			"success":        true,
 			"message":        "Change request found",
@@ -405,9 +620,122 @@ func (r *Registry) getChangeRequest(args map[string]interface{}) (*mcp.CallToolR
 		}), nil
 	}
 
+	return JSONResult(NewNotFoundResponse(fmt.Sprintf("Change request not found: %s", changeID))), nil
+}
+
+// changeApproval is one approval record in a change's approval chain
+type changeApproval struct {
+	Step        int    `json:"step"`
+	ApprovalID  string `json:"approval_id"`
+	Approver    string `json:"approver"`
+	Group       string `json:"group,omitempty"`
+	State       string `json:"state"`
+	RequestedAt string `json:"requested_at"`
+	DecidedAt   string `json:"decided_at,omitempty"`
+	Comments    string `json:"comments,omitempty"`
+}
+
+// approvalDecided reports whether an approval state is final
+func approvalDecided(state string) bool {
+	switch state {
+	case "approved", "rejected", "cancelled", "not_required", "no_longer_required":
+		return true
+	}
+	return false
+}
+
+func (r *Registry) getChangeApprovalChain(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	changeID := GetStringArg(args, "change_id", "")
+	if changeID == "" {
+		return JSONResult(NewErrorResponse("change_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveChangeID(changeID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find change request", err)), nil
+	}
+
+	changeResult, err := r.client.Get(fmt.Sprintf("/table/change_request/%s", sysID), map[string]string{
+		"sysparm_fields":                 "number,state,approval",
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get change request", err)), nil
+	}
+	change, _ := changeResult["result"].(map[string]interface{})
+
+	// Group approvals, keyed by sysapproval_group sys_id
+	groupResult, err := r.client.Get("/table/sysapproval_group", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("parent=%s^ORDERBYsys_created_on", sysID),
+		"sysparm_fields":                 "sys_id,assignment_group,approval,sys_created_on,sys_updated_on",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get group approvals", err)), nil
+	}
+	groups := []map[string]interface{}{}
+	groupNames := map[string]string{}
+	for _, record := range GetResultList(groupResult) {
+		groupNames[FieldValue(record["sys_id"])] = FieldDisplay(record["assignment_group"])
+		groups = append(groups, map[string]interface{}{
+			"group_approval_id": FieldValue(record["sys_id"]),
+			"group":             FieldDisplay(record["assignment_group"]),
+			"state":             FieldValue(record["approval"]),
+			"requested_at":      FieldDisplay(record["sys_created_on"]),
+		})
+	}
+
+	approvalResult, err := r.client.Get("/table/sysapproval_approver", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("sysapproval=%s^ORDERBYsys_created_on", sysID),
+		"sysparm_fields":                 "sys_id,approver,group,state,comments,sys_created_on,sys_updated_on",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get approvals", err)), nil
+	}
+
+	chain := []changeApproval{}
+	pending := []string{}
+	for i, record := range GetResultList(approvalResult) {
+		approval := changeApproval{
+			Step:        i + 1,
+			ApprovalID:  FieldValue(record["sys_id"]),
+			Approver:    FieldDisplay(record["approver"]),
+			Group:       groupNames[FieldValue(record["group"])],
+			State:       FieldValue(record["state"]),
+			RequestedAt: FieldDisplay(record["sys_created_on"]),
+			Comments:    FieldDisplay(record["comments"]),
+		}
+		if approvalDecided(approval.State) {
+			approval.DecidedAt = FieldDisplay(record["sys_updated_on"])
+		}
+		if approval.State == "requested" {
+			waitingOn := approval.Approver
+			if approval.Group != "" {
+				waitingOn = fmt.Sprintf("%s (%s)", approval.Approver, approval.Group)
+			}
+			pending = append(pending, waitingOn)
+		}
+		chain = append(chain, approval)
+	}
+
+	message := fmt.Sprintf("Found %d approvals", len(chain))
+	if len(pending) > 0 {
+		message = fmt.Sprintf("Waiting on %d of %d approvals: %s", len(pending), len(chain), strings.Join(pending, ", "))
+	}
+
 	return JSONResult(map[string]interface{}{
-		"success": false,
-		"message": fmt.Sprintf("Change request not found: %s", changeID),
+		"success":         true,
+		"message":         message,
+		"change_number":   change["number"],
+		"change_state":    change["state"],
+		"approval":        change["approval"],
+		"approvals":       chain,
+		"group_approvals": groups,
+		"waiting_on":      pending,
 	}), nil
 }
 
@@ -443,11 +771,8 @@ func (r *Registry) createChangeRequest(args map[string]interface{}) (*mcp.CallTo
 	if v := GetStringArg(args, "impact", ""); v != "" {
 		data["impact"] = v
 	}
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
-	}
-	if v := GetStringArg(args, "assignment_group", ""); v != "" {
-		data["assignment_group"] = v
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, []string{"assignment_group"}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user or group", err)), nil
 	}
 	if v := GetStringArg(args, "start_date", ""); v != "" {
 		data["start_date"] = v
@@ -496,31 +821,61 @@ func (r *Registry) updateChangeRequest(args map[string]interface{}) (*mcp.CallTo
 	if v := GetStringArg(args, "description", ""); v != "" {
 		data["description"] = v
 	}
+	var transition *changeTransition
 	if v := GetStringArg(args, "state", ""); v != "" {
+		// Business rules revert disallowed state changes without failing the update
+		var invalid string
+		transition, invalid, err = r.checkChangeTransition(sysID, v)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to read change request", err)), nil
+		}
+		if invalid != "" {
+			return JSONResult(NewErrorResponse(invalid, nil)), nil
+		}
 		data["state"] = v
 	}
 	if v := GetStringArg(args, "priority", ""); v != "" {
 		data["priority"] = v
 	}
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
 	}
 	if v := GetStringArg(args, "work_notes", ""); v != "" {
 		data["work_notes"] = v
 	}
 
+	unresolved := r.expandJournalMentions(data)
+
 	result, err := r.client.Put(fmt.Sprintf("/table/change_request/%s", sysID), data)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to update change request", err)), nil
 	}
 
 	if resultData, ok := result["result"].(map[string]interface{}); ok {
-		return JSONResult(map[string]interface{}{
+		if target, ok := data["state"].(string); ok {
+			if state := FieldValue(resultData["state"]); state != "" && state != target {
+				return JSONResult(map[string]interface{}{
+					"success":       false,
+					"message":       fmt.Sprintf("Change request updated, but its state is %s instead of %s: a business rule or the change model kept it from moving", r.changeStateList([]string{state}), r.changeStateList([]string{target})),
+					"change_id":     resultData["sys_id"],
+					"change_number": resultData["number"],
+					"state":         state,
+				}), nil
+			}
+		}
+		response := map[string]interface{}{
 			"success":       true,
 			"message":       "Change request updated successfully",
 			"change_id":     resultData["sys_id"],
 			"change_number": resultData["number"],
-		}), nil
+		}
+		if transition != nil && transition.warning != "" {
+			withWarnings(response, transition.warning)
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
@@ -548,8 +903,8 @@ func (r *Registry) addChangeTask(args map[string]interface{}) (*mcp.CallToolResu
 		"short_description": shortDesc,
 	}
 
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
 	}
 	if v := GetStringArg(args, "planned_start_date", ""); v != "" {
 		data["planned_start_date"] = v
@@ -575,6 +930,116 @@ func (r *Registry) addChangeTask(args map[string]interface{}) (*mcp.CallToolResu
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
 }
 
+func (r *Registry) updateChangeTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	taskID := GetStringArg(args, "task_id", "")
+	if taskID == "" {
+		return JSONResult(NewErrorResponse("task_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveChangeTaskID(taskID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find change task", err)), nil
+	}
+
+	data := map[string]interface{}{}
+
+	if v := GetStringArg(args, "state", ""); v != "" {
+		data["state"] = v
+	}
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, nil); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user", err)), nil
+	}
+	if v := GetStringArg(args, "work_notes", ""); v != "" {
+		data["work_notes"] = v
+	}
+	if v := GetStringArg(args, "actual_start_date", ""); v != "" {
+		data["actual_start_date"] = v
+	}
+	if v := GetStringArg(args, "actual_end_date", ""); v != "" {
+		data["actual_end_date"] = v
+	}
+
+	if len(data) == 0 {
+		return JSONResult(NewErrorResponse("At least one field to update is required", nil)), nil
+	}
+
+	unresolved := r.expandJournalMentions(data)
+
+	result, err := r.client.Put(fmt.Sprintf("/table/change_task/%s", sysID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to update change task", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		response := map[string]interface{}{
+			"success":     true,
+			"message":     "Change task updated successfully",
+			"task_id":     resultData["sys_id"],
+			"task_number": resultData["number"],
+			"state":       resultData["state"],
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) closeChangeTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	taskID := GetStringArg(args, "task_id", "")
+	closeNotes := GetStringArg(args, "close_notes", "")
+
+	if taskID == "" || closeNotes == "" {
+		return JSONResult(NewErrorResponse("task_id and close_notes are required", nil)), nil
+	}
+
+	sysID, err := r.resolveChangeTaskID(taskID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find change task", err)), nil
+	}
+
+	state := "3" // Closed
+	message := "Change task closed successfully"
+	if GetBoolArg(args, "cancel", false) {
+		state = "4" // Canceled
+		message = "Change task canceled successfully"
+	}
+
+	data := map[string]interface{}{
+		"state":           state,
+		"close_code":      GetStringArg(args, "close_code", "successful"),
+		"close_notes":     closeNotes,
+		"actual_end_date": GetStringArg(args, "actual_end_date", time.Now().UTC().Format(dateTimeLayout)),
+	}
+
+	result, err := r.client.Put(fmt.Sprintf("/table/change_task/%s", sysID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to close change task", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     message,
+			"task_id":     resultData["sys_id"],
+			"task_number": resultData["number"],
+			"state":       resultData["state"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
 func (r *Registry) submitChangeForApproval(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	if r.readOnlyMode {
 		return WriteBlockedResult(), nil
@@ -591,6 +1056,13 @@ func (r *Registry) submitChangeForApproval(args map[string]interface{}) (*mcp.Ca
 	}
 
 	// Update state to "Assess" (state -4) to trigger approval workflow
+	_, invalid, err := r.checkChangeTransition(sysID, "-4")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read change request", err)), nil
+	}
+	if invalid != "" {
+		return JSONResult(NewErrorResponse(invalid, nil)), nil
+	}
 	data := map[string]interface{}{
 		"state": "-4",
 	}
@@ -601,6 +1073,9 @@ func (r *Registry) submitChangeForApproval(args map[string]interface{}) (*mcp.Ca
 	}
 
 	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		if state := FieldValue(resultData["state"]); state != "" && state != "-4" {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Change request was not submitted: its state is %s instead of -4 (Assess)", r.changeStateList([]string{state})), nil)), nil
+		}
 		return JSONResult(map[string]interface{}{
 			"success":       true,
 			"message":       "Change request submitted for approval",
@@ -612,7 +1087,7 @@ func (r *Registry) submitChangeForApproval(args map[string]interface{}) (*mcp.Ca
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
 }
 
-func (r *Registry) approveChange(args map[string]interface{}) (*mcp.CallToolResult, error) {
+func (r *Registry) approveChange(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
 	if r.readOnlyMode {
 		return WriteBlockedResult(), nil
 	}
@@ -629,29 +1104,13 @@ func (r *Registry) approveChange(args map[string]interface{}) (*mcp.CallToolResu
 		return JSONResult(NewErrorResponse("Failed to find change request", err)), nil
 	}
 
-	// Find pending approval for this change
-	params := map[string]string{
-		"sysparm_query": fmt.Sprintf("sysapproval=%s^state=requested", sysID),
-		"sysparm_limit": "1",
-	}
-
-	approvalResult, err := r.client.Get("/table/sysapproval_approver", params)
+	approvalID, delegator, err := r.findPendingApproval(ctx, sysID)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to find approval record", err)), nil
 	}
 
-	var approvalID string
-	if resultList, ok := approvalResult["result"].([]interface{}); ok && len(resultList) > 0 {
-		if approvalData, ok := resultList[0].(map[string]interface{}); ok {
-			approvalID, _ = approvalData["sys_id"].(string)
-		}
-	}
-
 	if approvalID == "" {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": "No pending approval found for this change request",
-		}), nil
+		return JSONResult(NewNotFoundResponse("No pending approval found for this change request")), nil
 	}
 
 	data := map[string]interface{}{
@@ -667,16 +1126,59 @@ func (r *Registry) approveChange(args map[string]interface{}) (*mcp.CallToolResu
 	}
 
 	if result["result"] != nil {
+		message := "Change request approved"
+		if delegator != "" {
+			message += fmt.Sprintf(" on behalf of %s", delegator)
+		}
 		return JSONResult(map[string]interface{}{
 			"success": true,
-			"message": "Change request approved",
+			"message": message,
 		}), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
 }
 
-func (r *Registry) rejectChange(args map[string]interface{}) (*mcp.CallToolResult, error) {
+// findPendingApproval returns the requested approval of a task (e.g., a change request)
+// that the caller can action: their own, or one of an approver who delegated approvals
+// to them (sys_user_delegate). delegator names that approver when the approval is delegated.
+// When the caller cannot be identified (API key or OAuth), any requested approval is used.
+func (r *Registry) findPendingApproval(ctx context.Context, taskSysID string) (approvalID, delegator string, err error) {
+	query := fmt.Sprintf("sysapproval=%s^state=requested", taskSysID)
+	userID, _, identityErr := r.requesterIdentity(ctx)
+	if identityErr == nil {
+		approvers, err := r.actionableApprovers(userID)
+		if err != nil {
+			return "", "", err
+		}
+		query += fmt.Sprintf("^approverIN%s", strings.Join(approvers, ","))
+	}
+
+	result, err := r.client.Get("/table/sysapproval_approver", map[string]string{
+		"sysparm_query":                  query,
+		"sysparm_fields":                 "sys_id,approver",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "10",
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// The caller's own approval is preferred over delegated ones
+	for _, approval := range GetResultList(result) {
+		approver := FieldValue(approval["approver"])
+		if identityErr != nil || approver == userID {
+			return FieldValue(approval["sys_id"]), "", nil
+		}
+		if approvalID == "" {
+			approvalID, delegator = FieldValue(approval["sys_id"]), FieldDisplay(approval["approver"])
+		}
+	}
+	return approvalID, delegator, nil
+}
+
+func (r *Registry) rejectChange(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
 	if r.readOnlyMode {
 		return WriteBlockedResult(), nil
 	}
@@ -693,29 +1195,13 @@ func (r *Registry) rejectChange(args map[string]interface{}) (*mcp.CallToolResul
 		return JSONResult(NewErrorResponse("Failed to find change request", err)), nil
 	}
 
-	// Find pending approval for this change
-	params := map[string]string{
-		"sysparm_query": fmt.Sprintf("sysapproval=%s^state=requested", sysID),
-		"sysparm_limit": "1",
-	}
-
-	approvalResult, err := r.client.Get("/table/sysapproval_approver", params)
+	approvalID, delegator, err := r.findPendingApproval(ctx, sysID)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to find approval record", err)), nil
 	}
 
-	var approvalID string
-	if resultList, ok := approvalResult["result"].([]interface{}); ok && len(resultList) > 0 {
-		if approvalData, ok := resultList[0].(map[string]interface{}); ok {
-			approvalID, _ = approvalData["sys_id"].(string)
-		}
-	}
-
 	if approvalID == "" {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": "No pending approval found for this change request",
-		}), nil
+		return JSONResult(NewNotFoundResponse("No pending approval found for this change request")), nil
 	}
 
 	data := map[string]interface{}{
@@ -729,38 +1215,45 @@ func (r *Registry) rejectChange(args map[string]interface{}) (*mcp.CallToolResul
 	}
 
 	if result["result"] != nil {
+		message := "Change request rejected"
+		if delegator != "" {
+			message += fmt.Sprintf(" on behalf of %s", delegator)
+		}
 		return JSONResult(map[string]interface{}{
 			"success": true,
-			"message": "Change request rejected",
+			"message": message,
 		}), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
 }
 
-// resolveChangeID resolves a change number to sys_id
-func (r *Registry) resolveChangeID(changeID string) (string, error) {
-	if IsSysID(changeID) {
-		return changeID, nil
-	}
-
-	params := map[string]string{
-		"sysparm_query": fmt.Sprintf("number=%s", changeID),
-		"sysparm_limit": "1",
-	}
-
-	result, err := r.client.Get("/table/change_request", params)
+// actionableApprovers returns the approvers whose approvals a user can action: the user,
+// then each approver who currently delegates approvals to them (sys_user_delegate)
+func (r *Registry) actionableApprovers(userID string) ([]string, error) {
+	approvers := []string{userID}
+	delegations, err := r.client.Get("/table/sys_user_delegate", map[string]string{
+		"sysparm_query":  fmt.Sprintf("delegate=%s^approvals=true^starts<=javascript:gs.nowDateTime()^ORstartsISEMPTY^ends>=javascript:gs.nowDateTime()^ORendsISEMPTY", userID),
+		"sysparm_fields": "user",
+		"sysparm_limit":  "100",
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	if resultList, ok := result["result"].([]interface{}); ok && len(resultList) > 0 {
-		if data, ok := resultList[0].(map[string]interface{}); ok {
-			if sysID, ok := data["sys_id"].(string); ok {
-				return sysID, nil
-			}
+	for _, delegation := range GetResultList(delegations) {
+		if user := FieldValue(delegation["user"]); IsSysID(user) {
+			approvers = append(approvers, user)
 		}
 	}
+	return approvers, nil
+}
+
+// resolveChangeID resolves a change number to sys_id
+func (r *Registry) resolveChangeID(changeID string) (string, error) {
+	return r.resolveRecordNumber("change_request", changeID, "change request")
+}
 
-	return "", fmt.Errorf("change request not found: %s", changeID)
+// resolveChangeTaskID resolves a change task number to sys_id
+func (r *Registry) resolveChangeTaskID(taskID string) (string, error) {
+	return r.resolveRecordNumber("change_task", taskID, "change task")
 }
@@ -0,0 +1,374 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// maxConflictChanges bounds the overlapping change requests check_change_conflicts reads
+const maxConflictChanges = 100
+
+// maxBlackoutSchedules bounds the blackout schedules check_change_conflicts evaluates
+const maxBlackoutSchedules = 25
+
+// changeRiskPath is the Change Management REST API (sn_chg_rest) risk calculation of a change
+const changeRiskPath = "/api/sn_chg_rest/change/%s/risk"
+
+// changeConflictCI is a configuration item affected by a change, with its maintenance schedule
+type changeConflictCI struct {
+	SysID               string `json:"sys_id"`
+	Name                string `json:"name"`
+	maintenanceSchedule string
+}
+
+func (r *Registry) checkChangeConflicts(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	var changeSysID, changeNumber string
+	var start, end time.Time
+	ciIDs := []string{}
+
+	if changeID := GetStringArg(args, "change_id", ""); changeID != "" {
+		sysID, err := r.resolveChangeID(changeID)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to find change request", err)), nil
+		}
+		result, err := r.client.Get("/table/change_request/"+sysID, map[string]string{
+			"sysparm_fields": "sys_id,number,start_date,end_date,cmdb_ci",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to get change request", err)), nil
+		}
+		data, _ := result["result"].(map[string]interface{})
+		if data == nil {
+			return JSONResult(NewNotFoundResponse(fmt.Sprintf("Change request not found: %s", changeID))), nil
+		}
+		changeSysID, changeNumber = sysID, FieldValue(data["number"])
+		start, _ = time.Parse(dateTimeLayout, FieldValue(data["start_date"]))
+		end, _ = time.Parse(dateTimeLayout, FieldValue(data["end_date"]))
+		if ci := FieldValue(data["cmdb_ci"]); ci != "" {
+			ciIDs = append(ciIDs, ci)
+		}
+
+		// Affected CIs (task_ci) beyond the change's primary CI
+		affected, err := r.client.Get("/table/task_ci", map[string]string{
+			"sysparm_query":  "task=" + sysID,
+			"sysparm_fields": "ci_item",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to read affected CIs", err)), nil
+		}
+		for _, record := range GetResultList(affected) {
+			if ci := FieldValue(record["ci_item"]); ci != "" {
+				ciIDs = append(ciIDs, ci)
+			}
+		}
+	}
+
+	for name, target := range map[string]*time.Time{"start_date": &start, "end_date": &end} {
+		if v := GetStringArg(args, name, ""); v != "" {
+			parsed, err := time.Parse(dateTimeLayout, v)
+			if err != nil {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid %s: %s (use YYYY-MM-DD HH:MM:SS)", name, v), nil)), nil
+			}
+			*target = parsed
+		}
+	}
+	if start.IsZero() || end.IsZero() {
+		return JSONResult(NewErrorResponse("A planned window is required: give start_date and end_date, or a change_id with planned dates", nil)), nil
+	}
+	if !end.After(start) {
+		return JSONResult(NewErrorResponse("end_date must be after start_date", nil)), nil
+	}
+	if end.Sub(start) > maxScheduleRange {
+		return JSONResult(NewErrorResponse("start_date and end_date must be at most a year apart", nil)), nil
+	}
+
+	for _, ci := range GetStringArrayArg(args, "cis") {
+		sysID, err := r.resolveCIID(ci)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve cis", err)), nil
+		}
+		ciIDs = append(ciIDs, sysID)
+	}
+	cis, err := r.changeConflictCIs(ciIDs)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read configuration items", err)), nil
+	}
+
+	conflicts := []map[string]interface{}{}
+	warnings := []string{}
+
+	changes, err := r.overlappingChanges(changeSysID, start, end, cis)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read overlapping change requests", err)), nil
+	}
+	conflicts = append(conflicts, changes...)
+
+	window := interval{start, end}
+	blackouts, err := r.client.Get("/table/cmn_schedule", map[string]string{
+		"sysparm_query":  "type=blackout^ORDERBYname",
+		"sysparm_fields": "sys_id,name",
+		"sysparm_limit":  fmt.Sprintf("%d", maxBlackoutSchedules),
+	})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Blackout schedules were not checked: %v", err))
+	}
+	for _, record := range GetResultList(blackouts) {
+		schedule, err := r.loadSchedule(FieldValue(record["sys_id"]))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Blackout schedule %s was not checked: %v", FieldValue(record["name"]), err))
+			continue
+		}
+		if periods := schedule.workingIntervals(window.start, window.end); len(periods) > 0 {
+			conflicts = append(conflicts, map[string]interface{}{
+				"type":     "blackout",
+				"sys_id":   schedule.id,
+				"schedule": schedule.name,
+				"message":  fmt.Sprintf("The window overlaps blackout schedule %s", schedule.name),
+				"periods":  intervalList(periods),
+			})
+		}
+	}
+
+	// A change on a CI with a maintenance schedule belongs inside that schedule
+	for _, ci := range cis {
+		if ci.maintenanceSchedule == "" {
+			continue
+		}
+		schedule, err := r.loadSchedule(ci.maintenanceSchedule)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Maintenance schedule of %s was not checked: %v", ci.Name, err))
+			continue
+		}
+		if outside := subtractIntervals([]interval{window}, schedule.workingIntervals(window.start, window.end)); len(outside) > 0 {
+			conflicts = append(conflicts, map[string]interface{}{
+				"type":     "maintenance_window",
+				"sys_id":   schedule.id,
+				"schedule": schedule.name,
+				"ci":       ci.Name,
+				"message":  fmt.Sprintf("The window falls outside maintenance schedule %s of %s", schedule.name, ci.Name),
+				"periods":  intervalList(outside),
+			})
+		}
+	}
+
+	subject := "the window"
+	if changeNumber != "" {
+		subject = changeNumber
+	}
+	response := map[string]interface{}{
+		"success":        true,
+		"message":        fmt.Sprintf("Found %d conflicts for %s", len(conflicts), subject),
+		"window":         intervalList([]interval{window})[0],
+		"cis":            cis,
+		"conflicts":      conflicts,
+		"conflict_count": len(conflicts),
+	}
+	if changeSysID != "" {
+		response["change"] = map[string]interface{}{"sys_id": changeSysID, "number": changeNumber}
+	}
+	return JSONResult(withWarnings(response, warnings...)), nil
+}
+
+// changeConflictCIs reads the names and maintenance schedules of the CIs given by sys_id,
+// dropping duplicates
+func (r *Registry) changeConflictCIs(ids []string) ([]changeConflictCI, error) {
+	cis := []changeConflictCI{}
+	seen := map[string]bool{}
+	var unique []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return cis, nil
+	}
+
+	result, err := r.client.Get("/table/cmdb_ci", map[string]string{
+		"sysparm_query":  "sys_idIN" + strings.Join(unique, ","),
+		"sysparm_fields": "sys_id,name,maintenance_schedule",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range GetResultList(result) {
+		cis = append(cis, changeConflictCI{
+			SysID:               FieldValue(record["sys_id"]),
+			Name:                FieldValue(record["name"]),
+			maintenanceSchedule: FieldValue(record["maintenance_schedule"]),
+		})
+	}
+	return cis, nil
+}
+
+// overlappingChanges returns the open change requests other than exclude whose planned
+// window overlaps [start, end): those on any of cis, as primary or affected CI, or every
+// one when no CIs are given
+func (r *Registry) overlappingChanges(exclude string, start, end time.Time, cis []changeConflictCI) ([]map[string]interface{}, error) {
+	overlap := []string{"start_date<" + end.Format(dateTimeLayout), "end_date>" + start.Format(dateTimeLayout), "stateNOT IN3,4"}
+	if exclude != "" {
+		overlap = append(overlap, "sys_id!="+exclude)
+	}
+
+	names := map[string]string{}
+	var ciIDs []string
+	for _, ci := range cis {
+		names[ci.SysID] = ci.Name
+		ciIDs = append(ciIDs, ci.SysID)
+	}
+
+	// Shared CIs by change sys_id
+	shared := map[string][]string{}
+	query := strings.Join(overlap, "^")
+	if len(cis) > 0 {
+		// The same window conditions on the changes the CIs are affected by
+		filters := []string{"ci_itemIN" + strings.Join(ciIDs, ","), "task.sys_class_name=change_request"}
+		for _, condition := range overlap {
+			filters = append(filters, "task."+condition)
+		}
+		affected, err := r.client.Get("/table/task_ci", map[string]string{
+			"sysparm_query":  strings.Join(filters, "^"),
+			"sysparm_fields": "task,ci_item",
+			"sysparm_limit":  fmt.Sprintf("%d", maxConflictChanges),
+		})
+		if err != nil {
+			return nil, err
+		}
+		var taskIDs []string
+		for _, record := range GetResultList(affected) {
+			task := FieldValue(record["task"])
+			if _, ok := shared[task]; !ok {
+				taskIDs = append(taskIDs, task)
+			}
+			shared[task] = appendUnique(shared[task], names[FieldValue(record["ci_item"])])
+		}
+		query += "^cmdb_ciIN" + strings.Join(ciIDs, ",")
+		if len(taskIDs) > 0 {
+			query += "^ORsys_idIN" + strings.Join(taskIDs, ",")
+		}
+	}
+
+	result, err := r.client.Get("/table/change_request", map[string]string{
+		"sysparm_query":                  query + "^ORDERBYstart_date",
+		"sysparm_fields":                 "sys_id,number,short_description,state,start_date,end_date,cmdb_ci",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", maxConflictChanges),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		sysID := FieldValue(data["sys_id"])
+		cisInCommon := shared[sysID]
+		if name, ok := names[FieldValue(data["cmdb_ci"])]; ok {
+			cisInCommon = appendUnique(cisInCommon, name)
+		}
+		sort.Strings(cisInCommon)
+		message := fmt.Sprintf("%s is scheduled in an overlapping window", FieldValue(data["number"]))
+		if len(cisInCommon) > 0 {
+			message = fmt.Sprintf("%s is scheduled in an overlapping window on %s", FieldValue(data["number"]), strings.Join(cisInCommon, ", "))
+		}
+		conflicts = append(conflicts, map[string]interface{}{
+			"type":              "change",
+			"sys_id":            sysID,
+			"number":            FieldValue(data["number"]),
+			"short_description": FieldValue(data["short_description"]),
+			"state":             FieldDisplay(data["state"]),
+			"start_date":        FieldValue(data["start_date"]),
+			"end_date":          FieldValue(data["end_date"]),
+			"shared_cis":        append([]string{}, cisInCommon...),
+			"message":           message,
+		})
+	}
+	return conflicts, nil
+}
+
+// appendUnique appends value to values unless it is empty or already present
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// intervalList formats intervals as start and end times in UTC
+func intervalList(intervals []interval) []map[string]string {
+	list := make([]map[string]string, len(intervals))
+	for i, period := range intervals {
+		list[i] = map[string]string{
+			"start": period.start.UTC().Format(dateTimeLayout),
+			"end":   period.end.UTC().Format(dateTimeLayout),
+		}
+	}
+	return list
+}
+
+func (r *Registry) calculateChangeRisk(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	changeID := GetStringArg(args, "change_id", "")
+	if changeID == "" {
+		return JSONResult(NewErrorResponse("change_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveChangeID(changeID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find change request", err)), nil
+	}
+	riskFields := map[string]string{
+		"sysparm_fields":        "number,risk,impact",
+		"sysparm_display_value": "true",
+	}
+	before, err := r.client.GetWithContext(ctx, "/table/change_request/"+sysID, riskFields)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get change request", err)), nil
+	}
+	previous, _ := before["result"].(map[string]interface{})
+
+	if _, err := r.client.InstanceRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf(changeRiskPath, sysID), nil); err != nil {
+		var apiErr *servicenow.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return JSONResult(NewErrorResponse("Risk calculation is not available on this instance (it needs the Change Management REST API, sn_chg_rest)", err)), nil
+		}
+		return JSONResult(NewErrorResponse("Failed to calculate change risk", err)), nil
+	}
+
+	after, err := r.client.GetWithContext(ctx, "/table/change_request/"+sysID, riskFields)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Risk was calculated, but the change request could not be read back", err)), nil
+	}
+	data, _ := after["result"].(map[string]interface{})
+	if data == nil {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":         true,
+		"message":         fmt.Sprintf("Calculated risk of %s: %s", FieldValue(data["number"]), FieldValue(data["risk"])),
+		"change_id":       sysID,
+		"change_number":   FieldValue(data["number"]),
+		"risk":            FieldValue(data["risk"]),
+		"impact":          FieldValue(data["impact"]),
+		"previous_risk":   FieldValue(previous["risk"]),
+		"previous_impact": FieldValue(previous["impact"]),
+	}), nil
+}
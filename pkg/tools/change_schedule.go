@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// maxChangeScheduleDays bounds the date range of list_change_schedule
+const maxChangeScheduleDays = 31
+
+// maxChangeScheduleRecords bounds the change requests list_change_schedule reads
+const maxChangeScheduleRecords = 500
+
+// unassignedGroup names the schedule group of changes without an assignment group
+const unassignedGroup = "Unassigned"
+
+// scheduledChange is a change request on the change schedule
+type scheduledChange struct {
+	SysID            string `json:"sys_id"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	Type             string `json:"type"`
+	Risk             string `json:"risk"`
+	State            string `json:"state"`
+	StartDate        string `json:"start_date"`
+	EndDate          string `json:"end_date"`
+	start, end       time.Time
+	group            string
+}
+
+func (r *Registry) listChangeSchedule(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	zone, location := r.instanceLocation()
+
+	from := startOfDay(time.Now().In(location))
+	if v := GetStringArg(args, "start_date", ""); v != "" {
+		parsed, err := time.ParseInLocation(dateLayout, v, location)
+		if err != nil {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid start_date: %s (use YYYY-MM-DD)", v), nil)), nil
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 0, 6)
+	if v := GetStringArg(args, "end_date", ""); v != "" {
+		parsed, err := time.ParseInLocation(dateLayout, v, location)
+		if err != nil {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid end_date: %s (use YYYY-MM-DD)", v), nil)), nil
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return JSONResult(NewErrorResponse("end_date must not be before start_date", nil)), nil
+	}
+	if to.Sub(from) >= maxChangeScheduleDays*24*time.Hour {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("The date range can span at most %d days", maxChangeScheduleDays), nil)), nil
+	}
+	// end_date is inclusive
+	until := to.AddDate(0, 0, 1)
+
+	filters := []string{
+		"start_date<" + until.UTC().Format(dateTimeLayout),
+		"end_date>" + from.UTC().Format(dateTimeLayout),
+		"state!=4",
+	}
+	if changeType := GetStringArg(args, "type", ""); changeType != "" {
+		filters = append(filters, "type="+SanitizeQueryValue(changeType))
+	}
+	if risk := GetStringArg(args, "risk", ""); risk != "" {
+		filters = append(filters, "risk="+SanitizeQueryValue(risk))
+	}
+	if group := GetStringArg(args, "assignment_group", ""); group != "" {
+		groupID, err := r.resolveGroup(group)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assignment_group", err)), nil
+		}
+		filters = append(filters, "assignment_group="+groupID)
+	}
+
+	result, total, err := r.client.GetWithTotalCount("/table/change_request", map[string]string{
+		"sysparm_query":                  strings.Join(append(filters, "ORDERBYstart_date"), "^"),
+		"sysparm_fields":                 "sys_id,number,short_description,type,risk,state,start_date,end_date,assignment_group",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", maxChangeScheduleRecords),
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list scheduled changes", err)), nil
+	}
+
+	var changes []scheduledChange
+	for _, data := range GetResultList(result) {
+		change := scheduledChange{
+			SysID:            FieldValue(data["sys_id"]),
+			Number:           FieldValue(data["number"]),
+			ShortDescription: FieldValue(data["short_description"]),
+			Type:             FieldDisplay(data["type"]),
+			Risk:             FieldDisplay(data["risk"]),
+			State:            FieldDisplay(data["state"]),
+			group:            FieldDisplay(data["assignment_group"]),
+		}
+		var startErr, endErr error
+		change.start, startErr = time.Parse(dateTimeLayout, FieldValue(data["start_date"]))
+		change.end, endErr = time.Parse(dateTimeLayout, FieldValue(data["end_date"]))
+		if startErr != nil || endErr != nil {
+			continue
+		}
+		change.StartDate = change.start.In(location).Format(dateTimeLayout)
+		change.EndDate = change.end.In(location).Format(dateTimeLayout)
+		if change.group == "" {
+			change.group = unassignedGroup
+		}
+		changes = append(changes, change)
+	}
+
+	// A change is listed on every day of the range it is in progress
+	days := []map[string]interface{}{}
+	for day := from; day.Before(until); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		byGroup := map[string][]scheduledChange{}
+		count := 0
+		for _, change := range changes {
+			if change.start.Before(next) && change.end.After(day) {
+				byGroup[change.group] = append(byGroup[change.group], change)
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		names := make([]string, 0, len(byGroup))
+		for name := range byGroup {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		groups := make([]map[string]interface{}, len(names))
+		for i, name := range names {
+			groups[i] = map[string]interface{}{"assignment_group": name, "changes": byGroup[name]}
+		}
+		days = append(days, map[string]interface{}{
+			"date":    day.Format(dateLayout),
+			"weekday": day.Weekday().String(),
+			"count":   count,
+			"groups":  groups,
+		})
+	}
+
+	response := map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Found %d changes scheduled between %s and %s", len(changes), from.Format(dateLayout), to.Format(dateLayout)),
+		"time_zone":  zone,
+		"start_date": from.Format(dateLayout),
+		"end_date":   to.Format(dateLayout),
+		"total":      len(changes),
+		"days":       days,
+	}
+	if total > maxChangeScheduleRecords {
+		response["truncated"] = true
+		response["message"] = fmt.Sprintf("Showing the first %d of %d changes scheduled between %s and %s; narrow the range or filter by type, risk, or assignment_group", maxChangeScheduleRecords, total, from.Format(dateLayout), to.Format(dateLayout))
+	}
+	return JSONResult(response), nil
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// changeStateLabels are the labels of the out-of-the-box change request states
+var changeStateLabels = map[string]string{
+	"-5": "New", "-4": "Assess", "-3": "Authorize", "-2": "Scheduled",
+	"-1": "Implement", "0": "Review", "3": "Closed", "4": "Canceled",
+}
+
+// defaultChangeTransitions are the state transitions of the out-of-the-box change
+// state models (ChangeRequestStateModel_normal, _standard, _emergency), by change type.
+// They apply to changes without a change model (chg_model).
+var defaultChangeTransitions = map[string]map[string][]string{
+	"normal": {
+		"-5": {"-4", "4"},
+		"-4": {"-5", "-3", "4"},
+		"-3": {"-5", "-2", "4"},
+		"-2": {"-5", "-1", "4"},
+		"-1": {"-5", "0", "4"},
+		"0":  {"3", "4"},
+	},
+	"standard": {
+		"-5": {"-2", "4"},
+		"-2": {"-5", "-1", "4"},
+		"-1": {"-5", "0", "4"},
+		"0":  {"3", "4"},
+	},
+	"emergency": {
+		"-5": {"-3", "4"},
+		"-3": {"-5", "-2", "4"},
+		"-2": {"-5", "-1", "4"},
+		"-1": {"-5", "0", "4"},
+		"0":  {"3", "4"},
+	},
+}
+
+// changeTransition is the outcome of checking a change request state change
+type changeTransition struct {
+	// from is the state before the update, and allowed the states it may move to
+	from    string
+	allowed []string
+	// source names where the transitions came from; warning is set when they are unknown
+	source  string
+	warning string
+}
+
+// checkChangeTransition reads a change request and the transitions its change model
+// (or the default state model of its type) allows from its current state. It returns
+// an error message listing the valid next states when the change can't move to target.
+func (r *Registry) checkChangeTransition(sysID, target string) (*changeTransition, string, error) {
+	result, err := r.client.Get(fmt.Sprintf("/table/change_request/%s", sysID), map[string]string{
+		"sysparm_fields": "number,state,type,chg_model",
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	change, _ := result["result"].(map[string]interface{})
+	transition := &changeTransition{from: FieldValue(change["state"])}
+	if transition.from == target {
+		return transition, "", nil
+	}
+
+	if model := FieldValue(change["chg_model"]); model != "" {
+		transitions, err := r.client.Get("/table/chg_model_state_transition", map[string]string{
+			"sysparm_query":  fmt.Sprintf("model=%s^from_state.state=%s", model, transition.from),
+			"sysparm_fields": "to_state.state",
+			"sysparm_limit":  "100",
+		})
+		if err != nil {
+			transition.warning = fmt.Sprintf("State transition not checked: the change model's transitions could not be read: %v", err)
+			return transition, "", nil
+		}
+		for _, record := range GetResultList(transitions) {
+			if state := FieldValue(record["to_state.state"]); state != "" {
+				transition.allowed = append(transition.allowed, state)
+			}
+		}
+		transition.source = "change model"
+	} else {
+		changeType := strings.ToLower(FieldValue(change["type"]))
+		model, ok := defaultChangeTransitions[changeType]
+		if !ok {
+			transition.warning = fmt.Sprintf("State transition not checked: no default state model for %q changes", changeType)
+			return transition, "", nil
+		}
+		transition.allowed = model[transition.from]
+		transition.source = changeType + " change state model"
+	}
+
+	for _, state := range transition.allowed {
+		if state == target {
+			return transition, "", nil
+		}
+	}
+
+	next := "none; the change is in a final state"
+	if len(transition.allowed) > 0 {
+		next = r.changeStateList(transition.allowed)
+	}
+	return transition, fmt.Sprintf("%s can't move from %s to %s under its %s. Valid next states: %s",
+		FieldValue(change["number"]), r.changeStateList([]string{transition.from}), r.changeStateList([]string{target}), transition.source, next), nil
+}
+
+// changeStateList formats change states as "value (label)", using the instance's labels
+func (r *Registry) changeStateList(states []string) string {
+	choices := r.stateChoices("change_request")
+	sorted := append([]string{}, states...)
+	sort.SliceStable(sorted, func(i, j int) bool { return changeStateOrder(sorted[i]) < changeStateOrder(sorted[j]) })
+	parts := make([]string, 0, len(sorted))
+	for _, state := range sorted {
+		label, ok := choices.labels[state]
+		if !ok {
+			label = changeStateLabels[state]
+		}
+		if label == "" {
+			parts = append(parts, state)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", state, label))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changeStateOrder orders change states by lifecycle: New through Review, then Closed and Canceled
+func changeStateOrder(state string) int {
+	var n int
+	if _, err := fmt.Sscan(state, &n); err != nil {
+		return 1 << 20
+	}
+	return n
+}
@@ -0,0 +1,436 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestCloseChangeTask tests that closing a change task sets the closed state, close code, and end date
+func TestCloseChangeTask(t *testing.T) {
+	const sysID = "6816f79cc0a8016401c5a33be04be441"
+
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/change_task":
+			if r.URL.Query().Get("sysparm_query") != "number=CTASK0010001" {
+				t.Errorf("Unexpected query %s", r.URL.Query().Get("sysparm_query"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": sysID}}})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/change_task/"+sysID:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": sysID, "number": "CTASK0010001", "state": updated["state"]}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.closeChangeTask(map[string]interface{}{
+		"task_id":     "CTASK0010001",
+		"close_notes": "Patch applied",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "Change task closed successfully") {
+		t.Fatalf("Expected task to be closed, got %+v", result)
+	}
+	if updated["state"] != "3" || updated["close_code"] != "successful" || updated["close_notes"] != "Patch applied" {
+		t.Errorf("Unexpected update payload: %+v", updated)
+	}
+	if end, _ := updated["actual_end_date"].(string); len(end) != len("2006-01-02 15:04:05") {
+		t.Errorf("Expected actual_end_date to default to now, got %q", end)
+	}
+
+	result, _ = registry.closeChangeTask(map[string]interface{}{
+		"task_id":     sysID,
+		"close_notes": "Not needed",
+		"close_code":  "unsuccessful",
+		"cancel":      true,
+	})
+	if result.IsError || updated["state"] != "4" || updated["close_code"] != "unsuccessful" {
+		t.Errorf("Expected task to be canceled, got %+v (%+v)", result, updated)
+	}
+
+	result, _ = registry.updateChangeTask(map[string]interface{}{"task_id": sysID})
+	if !strings.Contains(result.Content[0].Text, "At least one field to update is required") {
+		t.Errorf("Expected validation error for empty update, got %+v", result)
+	}
+}
+
+// TestGetChangeApprovalChain tests that approvals are returned in order with their groups and pending approvers
+func TestGetChangeApprovalChain(t *testing.T) {
+	const sysID = "6816f79cc0a8016401c5a33be04be441"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		ref := func(value, display string) map[string]interface{} {
+			return map[string]interface{}{"value": value, "display_value": display}
+		}
+		switch r.URL.Path {
+		case "/api/now/table/change_request/" + sysID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"number": "CHG0010001", "state": "Authorize", "approval": "Requested"}})
+		case "/api/now/table/sysapproval_group":
+			if r.URL.Query().Get("sysparm_query") != "parent="+sysID+"^ORDERBYsys_created_on" {
+				t.Errorf("Unexpected group query %s", r.URL.Query().Get("sysparm_query"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"sys_id": ref("grp1", "grp1"), "assignment_group": ref("cab", "CAB Approval"), "approval": ref("requested", "Requested")},
+			}})
+		case "/api/now/table/sysapproval_approver":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"sys_id": ref("a1", "a1"), "approver": ref("u1", "Alice Manager"), "state": ref("approved", "Approved"),
+					"sys_created_on": ref("x", "2024-01-15 09:00:00"), "sys_updated_on": ref("y", "2024-01-15 10:00:00")},
+				map[string]interface{}{"sys_id": ref("a2", "a2"), "approver": ref("u2", "Bob Board"), "group": ref("grp1", "CAB Approval"), "state": ref("requested", "Requested"),
+					"sys_created_on": ref("x", "2024-01-15 10:00:00"), "sys_updated_on": ref("y", "2024-01-15 10:00:00")},
+			}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.getChangeApprovalChain(map[string]interface{}{"change_id": sysID})
+
+	var body struct {
+		Message   string           `json:"message"`
+		Approvals []changeApproval `json:"approvals"`
+		WaitingOn []string         `json:"waiting_on"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(body.Approvals) != 2 || body.Approvals[0].DecidedAt != "2024-01-15 10:00:00" || body.Approvals[1].DecidedAt != "" {
+		t.Fatalf("Unexpected approvals %+v", body.Approvals)
+	}
+	if body.Approvals[1].Step != 2 || body.Approvals[1].Group != "CAB Approval" {
+		t.Errorf("Expected second approval to belong to the CAB group, got %+v", body.Approvals[1])
+	}
+	if len(body.WaitingOn) != 1 || body.Message != "Waiting on 1 of 2 approvals: Bob Board (CAB Approval)" {
+		t.Errorf("Unexpected pending approvals %q: %v", body.Message, body.WaitingOn)
+	}
+}
+
+// TestApproveChangeAsDelegate tests that approvals of approvers who delegated to the caller can be actioned
+func TestApproveChangeAsDelegate(t *testing.T) {
+	const (
+		changeID    = "c286e1c0c0a8016401c5a33be04be441"
+		userID      = "11111111111111111111111111111111"
+		delegatorID = "22222222222222222222222222222222"
+		approvalID  = "33333333333333333333333333333333"
+	)
+
+	var approvalQuery string
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/change_request":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": changeID}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": userID}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user_delegate":
+			if !strings.HasPrefix(query, "delegate="+userID+"^approvals=true^") {
+				t.Errorf("Unexpected delegate query %s", query)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"user": delegatorID}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sysapproval_approver":
+			approvalQuery = query
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{
+				"sys_id":   map[string]interface{}{"value": approvalID, "display_value": approvalID},
+				"approver": map[string]interface{}{"value": delegatorID, "display_value": "Beth Anglin"},
+			}}})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/sysapproval_approver/"+approvalID:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": approvalID}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.approveChange(context.Background(), map[string]interface{}{"change_id": "CHG0030001", "comments": "Looks good"})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "Change request approved on behalf of Beth Anglin") {
+		t.Fatalf("Expected delegated approval, got %+v", result)
+	}
+	if want := "sysapproval=" + changeID + "^state=requested^approverIN" + userID + "," + delegatorID; approvalQuery != want {
+		t.Errorf("Expected approval query %s, got %s", want, approvalQuery)
+	}
+	if updated["state"] != "approved" || updated["comments"] != "Looks good" {
+		t.Errorf("Unexpected update payload: %+v", updated)
+	}
+}
+
+// TestUpdateChangeStateTransition tests that state changes are checked against the change model and reverts are reported
+func TestUpdateChangeStateTransition(t *testing.T) {
+	const sysID = "6816f79cc0a8016401c5a33be04be441"
+
+	change := map[string]interface{}{"number": "CHG0030001", "state": "-1", "type": "normal", "chg_model": ""}
+	puts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/change_request/"+sysID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": change})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/chg_model_state_transition":
+			if got := r.URL.Query().Get("sysparm_query"); got != "model=m1^from_state.state=-1" {
+				t.Errorf("Expected the model's transitions from Implement, got %s", got)
+			}
+			_, _ = w.Write([]byte(`{"result": [{"to_state.state": "0"}, {"to_state.state": "3"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_choice":
+			_, _ = w.Write([]byte(`{"result": []}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/change_request/"+sysID:
+			puts++
+			// A business rule keeps the change in Implement
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": sysID, "number": "CHG0030001", "state": "-1"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.updateChangeRequest(map[string]interface{}{"change_id": sysID, "state": "3"})
+	text := result.Content[0].Text
+	if puts != 0 || !strings.Contains(text, "can't move from -1 (Implement) to 3 (Closed)") || !strings.Contains(text, "Valid next states: -5 (New), 0 (Review), 4 (Canceled)") {
+		t.Errorf("Expected Implement to Closed to be rejected with the valid next states, got %s", text)
+	}
+
+	result, _ = registry.updateChangeRequest(map[string]interface{}{"change_id": sysID, "state": "0"})
+	if text := result.Content[0].Text; puts != 1 || !strings.Contains(text, `"success": false`) || !strings.Contains(text, "instead of 0 (Review)") {
+		t.Errorf("Expected the reverted state to be reported, got %s", text)
+	}
+
+	change["chg_model"] = "m1"
+	result, _ = registry.updateChangeRequest(map[string]interface{}{"change_id": sysID, "state": "3"})
+	if puts != 2 || strings.Contains(result.Content[0].Text, "can't move") {
+		t.Errorf("Expected the change model to allow Implement to Closed, got %s", result.Content[0].Text)
+	}
+}
+
+// TestCheckChangeConflicts tests that a change's window is checked against changes on its CIs, blackout schedules, and CI maintenance schedules
+func TestCheckChangeConflicts(t *testing.T) {
+	const (
+		changeID      = "c0000000000000000000000000000001"
+		otherChangeID = "c0000000000000000000000000000002"
+		dbID          = "d0000000000000000000000000000001"
+		appID         = "d0000000000000000000000000000002"
+		blackoutID    = "b0000000000000000000000000000001"
+		maintenanceID = "e0000000000000000000000000000001"
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		var result interface{}
+		switch {
+		case r.URL.Path == "/api/now/table/change_request" && query == "number=CHG0030001":
+			result = []interface{}{map[string]interface{}{"sys_id": changeID}}
+		case r.URL.Path == "/api/now/table/change_request/"+changeID:
+			result = map[string]interface{}{"sys_id": changeID, "number": "CHG0030001", "start_date": "2024-12-21 02:00:00", "end_date": "2024-12-21 06:00:00", "cmdb_ci": dbID}
+		case r.URL.Path == "/api/now/table/task_ci" && query == "task="+changeID:
+			result = []interface{}{map[string]interface{}{"ci_item": appID}}
+		case r.URL.Path == "/api/now/table/cmdb_ci":
+			result = []interface{}{
+				map[string]interface{}{"sys_id": dbID, "name": "db01", "maintenance_schedule": maintenanceID},
+				map[string]interface{}{"sys_id": appID, "name": "app01", "maintenance_schedule": ""},
+			}
+		case r.URL.Path == "/api/now/table/task_ci":
+			want := "ci_itemIN" + dbID + "," + appID + "^task.sys_class_name=change_request^task.start_date<2024-12-21 06:00:00^task.end_date>2024-12-21 02:00:00^task.stateNOT IN3,4^task.sys_id!=" + changeID
+			if query != want {
+				t.Errorf("Expected affected CI query %s, got %s", want, query)
+			}
+			result = []interface{}{map[string]interface{}{"task": otherChangeID, "ci_item": appID}}
+		case r.URL.Path == "/api/now/table/change_request":
+			if !strings.HasSuffix(query, "^cmdb_ciIN"+dbID+","+appID+"^ORsys_idIN"+otherChangeID+"^ORDERBYstart_date") {
+				t.Errorf("Expected changes on the CIs, got %s", query)
+			}
+			result = []interface{}{map[string]interface{}{
+				"sys_id": map[string]interface{}{"value": otherChangeID, "display_value": otherChangeID},
+				"number": map[string]interface{}{"value": "CHG0030002", "display_value": "CHG0030002"},
+				"state":  map[string]interface{}{"value": "-2", "display_value": "Scheduled"},
+			}}
+		case r.URL.Path == "/api/now/table/cmn_schedule":
+			result = []interface{}{map[string]interface{}{"sys_id": blackoutID, "name": "Year-end freeze"}}
+		case r.URL.Path == "/api/now/table/cmn_schedule/"+blackoutID:
+			result = map[string]interface{}{"sys_id": blackoutID, "name": "Year-end freeze", "time_zone": "UTC"}
+		case r.URL.Path == "/api/now/table/cmn_schedule/"+maintenanceID:
+			result = map[string]interface{}{"sys_id": maintenanceID, "name": "Nightly 03-05", "time_zone": "UTC"}
+		case r.URL.Path == "/api/now/table/cmn_other_schedule":
+			result = []interface{}{}
+		case r.URL.Path == "/api/now/table/cmn_schedule_span" && query == "scheduleIN"+blackoutID:
+			result = []interface{}{map[string]interface{}{"schedule": blackoutID, "start_date_time": "20241220T000000", "end_date_time": "20250102T000000"}}
+		case r.URL.Path == "/api/now/table/cmn_schedule_span" && query == "scheduleIN"+maintenanceID:
+			result = []interface{}{map[string]interface{}{"schedule": maintenanceID, "start_date_time": "20240101T030000", "end_date_time": "20240101T050000", "repeat_type": "daily"}}
+		default:
+			t.Errorf("Unexpected request %s %s?%s", r.Method, r.URL.Path, query)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.checkChangeConflicts(map[string]interface{}{"change_id": "CHG0030001"})
+	var response struct {
+		Success   bool `json:"success"`
+		Conflicts []struct {
+			Type      string              `json:"type"`
+			Number    string              `json:"number"`
+			Schedule  string              `json:"schedule"`
+			SharedCIs []string            `json:"shared_cis"`
+			Periods   []map[string]string `json:"periods"`
+		} `json:"conflicts"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Conflicts) != 3 {
+		t.Fatalf("Expected three conflicts, got %s", result.Content[0].Text)
+	}
+	change, blackout, maintenance := response.Conflicts[0], response.Conflicts[1], response.Conflicts[2]
+	if change.Type != "change" || change.Number != "CHG0030002" || strings.Join(change.SharedCIs, ",") != "app01" {
+		t.Errorf("Expected CHG0030002 on app01, got %+v", change)
+	}
+	if blackout.Type != "blackout" || blackout.Schedule != "Year-end freeze" || len(blackout.Periods) != 1 || blackout.Periods[0]["start"] != "2024-12-21 02:00:00" {
+		t.Errorf("Expected the whole window in the freeze, got %+v", blackout)
+	}
+	if maintenance.Type != "maintenance_window" || len(maintenance.Periods) != 2 || maintenance.Periods[0]["end"] != "2024-12-21 03:00:00" || maintenance.Periods[1]["start"] != "2024-12-21 05:00:00" {
+		t.Errorf("Expected the window outside db01's 03:00-05:00 maintenance, got %+v", maintenance)
+	}
+}
+
+// TestListChangeSchedule tests that changes are listed on each day of the range they are in progress, grouped by assignment group
+func TestListChangeSchedule(t *testing.T) {
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/sys_properties":
+			result = []interface{}{map[string]interface{}{"value": "America/New_York"}}
+		case "/api/now/table/change_request":
+			want := "start_date<2024-12-16 05:00:00^end_date>2024-12-14 05:00:00^state!=4^risk=2^ORDERBYstart_date"
+			if q := r.URL.Query().Get("sysparm_query"); q != want {
+				t.Errorf("Expected query %s, got %s", want, q)
+			}
+			result = []interface{}{
+				map[string]interface{}{
+					"number": field("CHG0030001", "CHG0030001"), "assignment_group": field("g1", "Network"),
+					"start_date": field("2024-12-15 03:00:00", "2024-12-14 22:00:00"), "end_date": field("2024-12-15 07:00:00", "2024-12-15 02:00:00"),
+				},
+				map[string]interface{}{
+					"number": field("CHG0030002", "CHG0030002"), "assignment_group": field("", ""),
+					"start_date": field("2024-12-15 15:00:00", "2024-12-15 10:00:00"), "end_date": field("2024-12-15 16:00:00", "2024-12-15 11:00:00"),
+				},
+			}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.listChangeSchedule(map[string]interface{}{"start_date": "2024-12-14", "end_date": "2024-12-15", "risk": "2"})
+	var response struct {
+		Success bool `json:"success"`
+		Days    []struct {
+			Date   string `json:"date"`
+			Groups []struct {
+				AssignmentGroup string `json:"assignment_group"`
+				Changes         []struct {
+					Number    string `json:"number"`
+					StartDate string `json:"start_date"`
+				} `json:"changes"`
+			} `json:"groups"`
+		} `json:"days"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	var calendar []string
+	for _, day := range response.Days {
+		for _, group := range day.Groups {
+			for _, change := range group.Changes {
+				calendar = append(calendar, day.Date+" "+group.AssignmentGroup+" "+change.Number)
+			}
+		}
+	}
+	want := "2024-12-14 Network CHG0030001, 2024-12-15 Network CHG0030001, 2024-12-15 Unassigned CHG0030002"
+	if !response.Success || strings.Join(calendar, ", ") != want {
+		t.Errorf("Expected %s, got %v", want, calendar)
+	}
+	if response.Days[0].Groups[0].Changes[0].StartDate != "2024-12-14 22:00:00" {
+		t.Errorf("Expected planned dates in the instance time zone, got %+v", response.Days[0].Groups[0].Changes[0])
+	}
+}
+
+// TestCalculateChangeRisk tests that the risk calculation is reported and recorded as an update of the change
+func TestCalculateChangeRisk(t *testing.T) {
+	const sysID = "6816f79cc0a8016401c5a33be04be441"
+
+	risk := "Moderate"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/change_request/"+sysID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"number": "CHG0010001", "risk": risk, "impact": "2 - Medium"}})
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/sn_chg_rest/change/"+sysID+"/risk":
+			risk = "High"
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	if err := registry.SetSessionIndex(filepath.Join(t.TempDir(), "index.jsonl")); err != nil {
+		t.Fatalf("Failed to enable the session index: %v", err)
+	}
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	calculate, _ := server.Handler("calculate_change_risk")
+	result, _ := calculate(context.Background(), map[string]interface{}{"change_id": sysID})
+	var body map[string]interface{}
+	_ = json.Unmarshal([]byte(result.Content[0].Text), &body)
+	if body["risk"] != "High" || body["previous_risk"] != "Moderate" {
+		t.Fatalf("Expected the risk to change from Moderate to High, got %s", result.Content[0].Text)
+	}
+
+	listChanges, _ := server.Handler("list_session_changes")
+	result, _ = listChanges(context.Background(), map[string]interface{}{})
+	var changes struct {
+		Changes []recordChange `json:"changes"`
+	}
+	_ = json.Unmarshal([]byte(result.Content[0].Text), &changes)
+	if len(changes.Changes) != 1 || changes.Changes[0].Table != "change_request" || changes.Changes[0].SysID != sysID || changes.Changes[0].Action != changeUpdated {
+		t.Errorf("Expected the change request to be recorded as updated, got %+v", changes.Changes)
+	}
+}
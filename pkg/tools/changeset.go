@@ -4,24 +4,28 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
 )
 
+// changesetDetailFields are the fields of the compact record returned by get_changeset
+const changesetDetailFields = "sys_id,name,description,state,application,parent,release_date,sys_created_by,sys_created_on,sys_updated_on"
+
 // registerChangesetTools registers all changeset/update set tools
 func (r *Registry) registerChangesetTools(server *mcp.Server) int {
 	count := 0
 
-	// Helper for limit constraints
+	// Helper for limit/offset constraints
 	limitMin := float64(1)
 	limitMax := float64(1000)
+	offsetMin := float64(0)
 
 	// List Changesets
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_changesets",
 		Description: "List changesets (update sets) with optional filtering. Update sets are containers for capturing configuration changes.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of changesets to return (default: 50)",
@@ -29,6 +33,12 @@ func (r *Registry) registerChangesetTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"state": {
 					Type:        "string",
 					Description: "Filter by state",
@@ -38,44 +48,40 @@ func (r *Registry) registerChangesetTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Filter by creator username",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Changesets",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listChangesets(args)
-	})
+	}, (*Registry).listChangesets)
 	count++
 
 	// Get Changeset Details
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_changeset",
 		Description: "Get detailed information about a changeset (update set) including contained changes.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"changeset_id": {
 					Type:        "string",
 					Description: "Changeset sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6') or name. Accepts both formats.",
 				},
-			},
+			}),
 			Required: []string{"changeset_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Changeset",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getChangeset(args)
-	})
+	}, (*Registry).getChangeset)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Changeset
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_changeset",
 			Description: "Create a new changeset (update set). Use update sets to capture and migrate configuration changes.",
 			InputSchema: mcp.JSONSchema{
@@ -99,13 +105,11 @@ func (r *Registry) registerChangesetTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Changeset",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createChangeset(args)
-		})
+		}, (*Registry).createChangeset)
 		count++
 
 		// Update Changeset
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_changeset",
 			Description: "Update an existing changeset. At least one field besides changeset_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -129,13 +133,11 @@ func (r *Registry) registerChangesetTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Changeset",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateChangeset(args)
-		})
+		}, (*Registry).updateChangeset)
 		count++
 
 		// Commit Changeset
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "commit_changeset",
 			Description: "Commit a changeset by marking it as complete. Completed changesets can be exported or deployed to other instances.",
 			InputSchema: mcp.JSONSchema{
@@ -151,9 +153,7 @@ func (r *Registry) registerChangesetTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Commit Changeset",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.commitChangeset(args)
-		})
+		}, (*Registry).commitChangeset)
 		count++
 	}
 
@@ -167,23 +167,25 @@ func (r *Registry) listChangesets(args map[string]interface{}) (*mcp.CallToolRes
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,name,description,state,application,sys_created_by,sys_created_on"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
 	if createdBy != "" {
-		filters = append(filters, fmt.Sprintf("sys_created_by=%s", createdBy))
+		filters = append(filters, fmt.Sprintf("sys_created_by=%s", SanitizeQueryValue(createdBy)))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/sys_update_set", params)
+	result, page, err := r.listRecords("/table/sys_update_set", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list changesets", err)), nil
 	}
@@ -192,7 +194,7 @@ func (r *Registry) listChangesets(args map[string]interface{}) (*mcp.CallToolRes
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				changesets = append(changesets, map[string]interface{}{
+				changesets = append(changesets, selectFields(map[string]interface{}{
 					"sys_id":         data["sys_id"],
 					"name":           data["name"],
 					"description":    data["description"],
@@ -200,16 +202,16 @@ func (r *Registry) listChangesets(args map[string]interface{}) (*mcp.CallToolRes
 					"application":    data["application"],
 					"sys_created_by": data["sys_created_by"],
 					"sys_created_on": data["sys_created_on"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":    true,
 		"message":    fmt.Sprintf("Found %d changesets", len(changesets)),
 		"changesets": changesets,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) getChangeset(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -224,14 +226,16 @@ func (r *Registry) getChangeset(args map[string]interface{}) (*mcp.CallToolResul
 	if IsSysID(changesetID) {
 		endpoint = fmt.Sprintf("/table/sys_update_set/%s", changesetID)
 		params = map[string]string{
+			"sysparm_fields":                 readFields(args, changesetDetailFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
 	} else {
 		endpoint = "/table/sys_update_set"
 		params = map[string]string{
-			"sysparm_query":                  fmt.Sprintf("name=%s", changesetID),
+			"sysparm_query":                  fmt.Sprintf("name=%s", SanitizeQueryValue(changesetID)),
 			"sysparm_limit":                  "1",
+			"sysparm_fields":                 readFields(args, changesetDetailFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
@@ -252,16 +256,13 @@ func (r *Registry) getChangeset(args map[string]interface{}) (*mcp.CallToolResul
 	}
 
 	if changesetData == nil {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": fmt.Sprintf("Changeset not found: %s", changesetID),
-		}), nil
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Changeset not found: %s", changesetID))), nil
 	}
 
 	return JSONResult(map[string]interface{}{
 		"success":   true,
 		"message":   "Changeset found",
-		"changeset": changesetData,
+		"changeset": selectFields(changesetData, changesetData, args),
 	}), nil
 }
 
@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// chartArg is the argument asking a KPI tool to return its result as a chart image too
+const chartArg = "chart"
+
+// maxChartPoints bounds the bars or points drawn on a chart; the rest are left out
+const maxChartPoints = 50
+
+// Chart size and plot area margins, in pixels
+const (
+	chartWidth        = 720
+	chartHeight       = 400
+	chartMarginLeft   = 64
+	chartMarginRight  = 24
+	chartMarginTop    = 44
+	chartMarginBottom = 56
+	chartGridLines    = 4
+)
+
+var (
+	chartBackground = color.RGBA{255, 255, 255, 255}
+	chartAxis       = color.RGBA{51, 51, 51, 255}
+	chartGrid       = color.RGBA{221, 221, 221, 255}
+	chartSeries     = color.RGBA{59, 130, 246, 255}
+)
+
+// chartKind is the shape of a chart: bar for categories (e.g., report groups), line
+// for values over time (e.g., indicator scores)
+type chartKind int
+
+const (
+	barChart chartKind = iota
+	lineChart
+)
+
+// chartPoint is one bar or point of a chart
+type chartPoint struct {
+	label string
+	value float64
+}
+
+// withChart adds the chart argument to the properties of a KPI tool
+func withChart(properties map[string]mcp.Property) map[string]mcp.Property {
+	properties[chartArg] = mcp.Property{
+		Type:        "boolean",
+		Description: "Also return the result as a PNG chart image, for answers shown to people",
+		Default:     false,
+	}
+	return properties
+}
+
+// withChartImage appends a chart of points to result as an image content item, when
+// the chart argument asks for one. Results without points to draw are returned as is.
+func withChartImage(result *mcp.CallToolResult, args map[string]interface{}, kind chartKind, title string, points []chartPoint) *mcp.CallToolResult {
+	if !GetBoolArg(args, chartArg, false) || len(points) == 0 || result.IsError {
+		return result
+	}
+	data, err := renderChart(kind, title, points)
+	if err != nil {
+		addWarnings(result, "Warning: the chart could not be drawn: "+err.Error())
+		return result
+	}
+	result.Content = append(result.Content, mcp.ContentItem{
+		Type:     "image",
+		MimeType: "image/png",
+		Data:     base64.StdEncoding.EncodeToString(data),
+	})
+	return result
+}
+
+// chartNumber returns the number of a KPI value, including display values with digit
+// grouping (e.g., "1,234")
+func chartNumber(value interface{}) (float64, bool) {
+	if s, ok := value.(string); ok {
+		value = strings.ReplaceAll(s, ",", "")
+	}
+	return CoerceNumber(value)
+}
+
+// renderChart draws a bar or line chart of points as a PNG, with a title, a value axis
+// with grid lines, and category labels
+func renderChart(kind chartKind, title string, points []chartPoint) ([]byte, error) {
+	if len(points) > maxChartPoints {
+		points = points[:maxChartPoints]
+	}
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	fillRect(img, img.Bounds(), chartBackground)
+
+	plot := image.Rect(chartMarginLeft, chartMarginTop, chartWidth-chartMarginRight, chartHeight-chartMarginBottom)
+	drawText(img, (chartWidth-textWidth(title, 2))/2, 12, fitText(title, chartWidth/12), 2, chartAxis)
+
+	// The value axis spans zero and every value, rounded out to a readable step
+	low, high := 0.0, 0.0
+	for _, point := range points {
+		low, high = math.Min(low, point.value), math.Max(high, point.value)
+	}
+	step := niceStep((high - low) / chartGridLines)
+	low, high = math.Floor(low/step)*step, math.Ceil(high/step)*step
+	if high == low {
+		high = low + step
+	}
+	y := func(value float64) int {
+		return plot.Max.Y - int(math.Round((value-low)/(high-low)*float64(plot.Dy())))
+	}
+
+	for value := low; value <= high+step/2; value += step {
+		fillRect(img, image.Rect(plot.Min.X, y(value), plot.Max.X, y(value)+1), chartGrid)
+		label := formatChartValue(value)
+		drawText(img, plot.Min.X-8-textWidth(label, 1), y(value)-3, label, 1, chartAxis)
+	}
+	fillRect(img, image.Rect(plot.Min.X, plot.Min.Y, plot.Min.X+1, plot.Max.Y+1), chartAxis)
+	fillRect(img, image.Rect(plot.Min.X, y(0), plot.Max.X, y(0)+1), chartAxis)
+
+	slot := float64(plot.Dx()) / float64(len(points))
+	labelEvery := int(math.Ceil(float64(6*12) / slot))
+	if labelEvery < 1 {
+		labelEvery = 1
+	}
+	var previous image.Point
+	for i, point := range points {
+		center := plot.Min.X + int(slot*float64(i)+slot/2)
+		switch kind {
+		case barChart:
+			half := int(math.Max(1, slot*0.35))
+			top, bottom := y(point.value), y(0)
+			if top > bottom {
+				top, bottom = bottom, top
+			}
+			fillRect(img, image.Rect(center-half, top, center+half, bottom), chartSeries)
+			if label := formatChartValue(point.value); textWidth(label, 1) < int(slot) {
+				drawText(img, center-textWidth(label, 1)/2, top-10, label, 1, chartAxis)
+			}
+		case lineChart:
+			current := image.Pt(center, y(point.value))
+			if i > 0 {
+				drawLine(img, previous, current, chartSeries)
+			}
+			fillRect(img, image.Rect(current.X-2, current.Y-2, current.X+3, current.Y+3), chartSeries)
+			previous = current
+		}
+		if i%labelEvery == 0 {
+			label := fitText(point.label, int(slot*float64(labelEvery))/6)
+			drawText(img, center-textWidth(label, 1)/2, plot.Max.Y+10, label, 1, chartAxis)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// niceStep rounds a grid step up to 1, 2, or 5 times a power of ten
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// formatChartValue formats an axis or bar value compactly (e.g., 1500 as 1.5K)
+func formatChartValue(value float64) string {
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e9, "B"}, {1e6, "M"}, {1e3, "K"}} {
+		if math.Abs(value) >= unit.size {
+			return strconv.FormatFloat(math.Round(value/unit.size*10)/10, 'f', -1, 64) + unit.suffix
+		}
+	}
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// fitText shortens text to at most max characters, marking the cut with ".."
+func fitText(text string, max int) string {
+	if max < 1 {
+		return ""
+	}
+	if runes := []rune(text); len(runes) > max {
+		if max <= 2 {
+			return string(runes[:max])
+		}
+		return string(runes[:max-2]) + ".."
+	}
+	return text
+}
+
+// fillRect fills a rectangle of img with c
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawLine draws a two-pixel line from a to b
+func drawLine(img *image.RGBA, a, b image.Point, c color.RGBA) {
+	steps := int(math.Max(math.Abs(float64(b.X-a.X)), math.Abs(float64(b.Y-a.Y))))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		x := a.X + int(math.Round(t*float64(b.X-a.X)))
+		y := a.Y + int(math.Round(t*float64(b.Y-a.Y)))
+		fillRect(img, image.Rect(x, y, x+2, y+2), c)
+	}
+}
+
+// textWidth returns the width in pixels of text drawn with drawText at scale
+func textWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (n*6 - 1) * scale
+}
+
+// drawText draws text in the chart font with its top left corner at (x, y), each font
+// pixel scale pixels wide. Letters are drawn in upper case; characters without a glyph
+// are drawn as "?".
+func drawText(img *image.RGBA, x, y int, text string, scale int, c color.RGBA) {
+	for _, ch := range strings.ToUpper(text) {
+		glyph, ok := chartFont[ch]
+		if !ok {
+			glyph = chartFont['?']
+		}
+		for row, bits := range glyph {
+			for col := 0; col < 5; col++ {
+				if bits&(1<<(4-col)) != 0 {
+					fillRect(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+				}
+			}
+		}
+		x += 6 * scale
+	}
+}
+
+// chartFont is a 5x7 pixel font: seven rows per glyph, the high bit of the low five the leftmost pixel
+var chartFont = map[rune][7]uint8{
+	' ': {},
+	'0': {0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	'1': {0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'2': {0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F},
+	'3': {0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E},
+	'4': {0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	'5': {0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	'6': {0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	'7': {0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8': {0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	'9': {0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+	'A': {0x0E, 0x11, 0x11, 0x11, 0x1F, 0x11, 0x11},
+	'B': {0x1E, 0x11, 0x11, 0x1E, 0x11, 0x11, 0x1E},
+	'C': {0x0E, 0x11, 0x10, 0x10, 0x10, 0x11, 0x0E},
+	'D': {0x1C, 0x12, 0x11, 0x11, 0x11, 0x12, 0x1C},
+	'E': {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x1F},
+	'F': {0x1F, 0x10, 0x10, 0x1E, 0x10, 0x10, 0x10},
+	'G': {0x0E, 0x11, 0x10, 0x17, 0x11, 0x11, 0x0F},
+	'H': {0x11, 0x11, 0x11, 0x1F, 0x11, 0x11, 0x11},
+	'I': {0x0E, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'J': {0x07, 0x02, 0x02, 0x02, 0x02, 0x12, 0x0C},
+	'K': {0x11, 0x12, 0x14, 0x18, 0x14, 0x12, 0x11},
+	'L': {0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F},
+	'M': {0x11, 0x1B, 0x15, 0x15, 0x11, 0x11, 0x11},
+	'N': {0x11, 0x11, 0x19, 0x15, 0x13, 0x11, 0x11},
+	'O': {0x0E, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'P': {0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10, 0x10},
+	'Q': {0x0E, 0x11, 0x11, 0x11, 0x15, 0x12, 0x0D},
+	'R': {0x1E, 0x11, 0x11, 0x1E, 0x14, 0x12, 0x11},
+	'S': {0x0F, 0x10, 0x10, 0x0E, 0x01, 0x01, 0x1E},
+	'T': {0x1F, 0x04, 0x04, 0x04, 0x04, 0x04, 0x04},
+	'U': {0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x0E},
+	'V': {0x11, 0x11, 0x11, 0x11, 0x11, 0x0A, 0x04},
+	'W': {0x11, 0x11, 0x11, 0x15, 0x15, 0x15, 0x0A},
+	'X': {0x11, 0x11, 0x0A, 0x04, 0x0A, 0x11, 0x11},
+	'Y': {0x11, 0x11, 0x11, 0x0A, 0x04, 0x04, 0x04},
+	'Z': {0x1F, 0x01, 0x02, 0x04, 0x08, 0x10, 0x1F},
+	'-': {0x00, 0x00, 0x00, 0x1F, 0x00, 0x00, 0x00},
+	'+': {0x00, 0x04, 0x04, 0x1F, 0x04, 0x04, 0x00},
+	'.': {0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C},
+	',': {0x00, 0x00, 0x00, 0x00, 0x0C, 0x04, 0x08},
+	':': {0x00, 0x0C, 0x0C, 0x00, 0x0C, 0x0C, 0x00},
+	'/': {0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00},
+	'%': {0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03},
+	'(': {0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02},
+	')': {0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08},
+	'_': {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1F},
+	'#': {0x0A, 0x0A, 0x1F, 0x0A, 0x1F, 0x0A, 0x0A},
+	'&': {0x0C, 0x12, 0x14, 0x08, 0x15, 0x12, 0x0D},
+	'?': {0x0E, 0x11, 0x01, 0x02, 0x04, 0x00, 0x04},
+}
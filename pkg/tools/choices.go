@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// stateChoices are the choices of a table field (originally only state fields)
+type stateChoices struct {
+	// labels maps values to labels, and values maps lower-case labels to values
+	labels map[string]string
+	values map[string]string
+	// order lists the values in the instance's sequence
+	order []string
+}
+
+// choiceCache holds the configured labels that replace state labels (MCP_STATE_LABELS).
+// The choices read from sys_choice are kept in the client's lookup cache, keyed by
+// cacheChoices and table.field.
+type choiceCache struct {
+	mu        sync.Mutex
+	overrides map[string]map[string]string
+}
+
+// SetStateLabels sets the labels shown for state values, as table.value=label entries
+// (e.g., incident.2=Being worked on). They replace the instance's choice labels in results.
+func (r *Registry) SetStateLabels(entries []string) error {
+	overrides := map[string]map[string]string{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, label, ok := strings.Cut(entry, "=")
+		table, value, dotted := strings.Cut(strings.TrimSpace(key), ".")
+		label = strings.TrimSpace(label)
+		if !ok || !dotted || !tableNamePattern.MatchString(table) || value == "" || label == "" {
+			return fmt.Errorf("invalid state label %q (use table.value=label)", entry)
+		}
+		if overrides[table] == nil {
+			overrides[table] = map[string]string{}
+		}
+		overrides[table][value] = label
+	}
+
+	r.choices.mu.Lock()
+	defer r.choices.mu.Unlock()
+	r.choices.overrides = overrides
+	return nil
+}
+
+// stateChoices returns the choices of a table's state field
+func (r *Registry) stateChoices(table string) stateChoices {
+	return r.fieldChoices(table, "state")
+}
+
+// fieldChoices returns the choices of a table field, reading sys_choice when they are
+// not cached. Tables without their own choices use those of task. A failed read
+// returns no choices and is retried on the next call.
+func (r *Registry) fieldChoices(table, field string) stateChoices {
+	key := r.cacheKey(cacheChoices, table+"."+field)
+	if cached, ok := r.base.Cache().Get(key); ok {
+		return cached.(stateChoices)
+	}
+
+	result, err := r.client.Get("/table/sys_choice", map[string]string{
+		"sysparm_query":  fmt.Sprintf("nameIN%s,task^element=%s^inactive=false^ORDERBYsequence", SanitizeQueryValue(table), SanitizeQueryValue(field)),
+		"sysparm_fields": "name,value,label,language",
+		"sysparm_limit":  "500",
+	})
+	if err != nil {
+		if r.logger != nil {
+			r.logger.Warn("Failed to read %s choices of %s: %v", field, table, err)
+		}
+		return stateChoices{}
+	}
+
+	records := GetResultList(result)
+	source := "task"
+	for _, record := range records {
+		if FieldValue(record["name"]) == table {
+			source = table
+			break
+		}
+	}
+
+	choices := stateChoices{labels: map[string]string{}, values: map[string]string{}}
+	for _, record := range records {
+		if FieldValue(record["name"]) != source {
+			continue
+		}
+		value, label := FieldValue(record["value"]), FieldValue(record["label"])
+		// Labels are matched in any language; English labels are shown when there is a choice
+		choices.values[strings.ToLower(label)] = value
+		if _, ok := choices.labels[value]; !ok {
+			choices.order = append(choices.order, value)
+		}
+		if _, ok := choices.labels[value]; !ok || FieldValue(record["language"]) == "en" {
+			choices.labels[value] = label
+		}
+	}
+	// Numbered labels (e.g., "1 - Critical") are also matched without their number
+	for _, record := range records {
+		_, name, numbered := strings.Cut(strings.ToLower(FieldValue(record["label"])), " - ")
+		if _, taken := choices.values[name]; FieldValue(record["name"]) == source && numbered && !taken {
+			choices.values[name] = FieldValue(record["value"])
+		}
+	}
+
+	r.base.Cache().Set(key, choices)
+	return choices
+}
+
+// labelStates replaces the state field of records read from table with its value and
+// label ({"value": "2", "label": "In Progress"}). State read as a display value is
+// mapped back to its value through the table's choices.
+func (r *Registry) labelStates(table string, records ...map[string]interface{}) {
+	var choices *stateChoices
+	for _, record := range records {
+		state, ok := record["state"]
+		if !ok || state == nil {
+			continue
+		}
+		if choices == nil {
+			loaded := r.stateChoices(table)
+			choices = &loaded
+		}
+
+		value, label := FieldValue(state), FieldDisplay(state)
+		if _, withDisplay := state.(map[string]interface{}); !withDisplay {
+			if v, ok := choices.values[strings.ToLower(label)]; ok {
+				value = v
+			} else if l, ok := choices.labels[value]; ok {
+				label = l
+			}
+		}
+
+		r.choices.mu.Lock()
+		if override, ok := r.choices.overrides[table][value]; ok {
+			label = override
+		}
+		r.choices.mu.Unlock()
+
+		record["state"] = map[string]interface{}{"value": value, "label": label}
+	}
+}
+
+// choiceArgTables maps tools to the table whose choices their choice arguments
+// (choiceArgFields) take, so labels can be given instead of values
+var choiceArgTables = map[string]string{
+	"list_incidents":        "incident",
+	"create_incident":       "incident",
+	"update_incident":       "incident",
+	"compute_priority":      "incident",
+	"list_sla_breaches":     "task",
+	"will_breach_soon":      "task",
+	"list_catalog_tasks":    "sc_task",
+	"update_catalog_task":   "sc_task",
+	"list_problems":         "problem",
+	"update_problem_rca":    "problem",
+	"list_change_requests":  "change_request",
+	"create_change_request": "change_request",
+	"update_change_request": "change_request",
+	"update_change_task":    "change_task",
+	"list_stories":          "rm_story",
+	"update_story":          "rm_story",
+	"list_epics":            "rm_epic",
+	"update_epic":           "rm_epic",
+	"list_scrum_tasks":      "rm_scrum_task",
+	"update_scrum_task":     "rm_scrum_task",
+	"list_projects":         "pm_project",
+	"update_project":        "pm_project",
+}
+
+// choiceArgFields are the choice arguments translated from labels to values
+var choiceArgFields = []string{"state", "priority", "impact", "urgency", "category"}
+
+// choiceArgsValidator translates choice labels (e.g., "In Progress", "Critical",
+// "Hardware") to their values using the instance's cached choices. Arguments
+// matching no choice are passed on unchanged, except where the schema lists the
+// values, which rejects them with the valid choices.
+func (r *Registry) choiceArgsValidator(call *ToolCall) error {
+	table, ok := choiceArgTables[call.Tool.Name]
+	if !ok {
+		return nil
+	}
+	for _, field := range choiceArgFields {
+		input, ok := call.Args[field].(string)
+		prop, declared := call.Tool.InputSchema.Properties[field]
+		if !ok || !declared || input == "" || slices.Contains(prop.Enum, input) {
+			continue
+		}
+		value, known := r.choiceValue(table, field, input)
+		if value != "" {
+			call.Args[field] = value
+			continue
+		}
+		if len(prop.Enum) > 0 && known {
+			return fmt.Errorf("unknown %s %q for %s; use one of: %s", field, input, table, r.choiceList(table, field, prop.Enum))
+		}
+	}
+	return nil
+}
+
+// choiceValue returns the value of a table field's choice given its value or label
+// (in any case, and for numbered labels like "1 - Critical" also without the number).
+// It returns "" when no choice matches, with known reporting whether choices were found.
+func (r *Registry) choiceValue(table, field, input string) (value string, known bool) {
+	choices := r.fieldChoices(table, field)
+	if _, ok := choices.labels[input]; ok {
+		return input, true
+	}
+	lower := strings.ToLower(strings.TrimSpace(input))
+	if value, ok := choices.values[lower]; ok {
+		return value, true
+	}
+	if field == "state" {
+		r.choices.mu.Lock()
+		defer r.choices.mu.Unlock()
+		for value, label := range r.choices.overrides[table] {
+			if strings.ToLower(label) == lower {
+				return value, true
+			}
+		}
+	}
+	return "", len(choices.labels) > 0
+}
+
+// choiceList formats values as "value (label)" with the labels of a table field's choices
+func (r *Registry) choiceList(table, field string, values []string) string {
+	choices := r.fieldChoices(table, field)
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if label, ok := choices.labels[value]; ok {
+			parts = append(parts, fmt.Sprintf("%s (%s)", value, label))
+		} else {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
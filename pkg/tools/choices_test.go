@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLabelStates tests that states are returned as value and label, with labels from the cached choices or configuration
+func TestLabelStates(t *testing.T) {
+	choiceReads := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_choice":
+			choiceReads++
+			if q := r.URL.Query().Get("sysparm_query"); q != "nameINincident,task^element=state^inactive=false^ORDERBYsequence" {
+				t.Errorf("Unexpected choice query %s", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"name": "task", "value": "2", "label": "Work in Progress", "language": "en"},
+				map[string]interface{}{"name": "incident", "value": "2", "label": "In Progress", "language": "en"},
+				map[string]interface{}{"name": "incident", "value": "2", "label": "En cours", "language": "fr"},
+				map[string]interface{}{"name": "incident", "value": "6", "label": "Resolved", "language": "en"},
+			}})
+		case "/api/now/table/incident":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"number": "INC0010001", "state": "In Progress"},
+				map[string]interface{}{"number": "INC0010002", "state": "Resolved"},
+				map[string]interface{}{"number": "INC0010003", "state": "En cours"},
+			}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	if err := registry.SetStateLabels([]string{"incident.6=Fixed", ""}); err != nil {
+		t.Fatalf("Expected state labels to be accepted, got %v", err)
+	}
+	if err := registry.SetStateLabels([]string{"incident=Fixed"}); err == nil {
+		t.Error("Expected an entry without a value to be rejected")
+	}
+	_ = registry.SetStateLabels([]string{"incident.6=Fixed"})
+
+	type state struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	}
+	for i := 0; i < 2; i++ {
+		result, _ := registry.listIncidents(map[string]interface{}{})
+		var body struct {
+			Incidents []struct {
+				State state `json:"state"`
+			} `json:"incidents"`
+		}
+		if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil || len(body.Incidents) != 3 {
+			t.Fatalf("Failed to parse incidents: %s", result.Content[0].Text)
+		}
+		want := []state{{"2", "In Progress"}, {"6", "Fixed"}, {"2", "En cours"}}
+		for j, incident := range body.Incidents {
+			if incident.State != want[j] {
+				t.Errorf("Expected state %+v, got %+v", want[j], incident.State)
+			}
+		}
+	}
+	if choiceReads != 1 {
+		t.Errorf("Expected the choices to be read once, got %d reads", choiceReads)
+	}
+}
+
+// TestChoiceArgs tests that choice labels are translated to values before the handler runs
+func TestChoiceArgs(t *testing.T) {
+	const sysID = "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/now/table/sys_choice":
+			var records []interface{}
+			switch r.URL.Query().Get("sysparm_query") {
+			case "nameINincident,task^element=state^inactive=false^ORDERBYsequence":
+				records = []interface{}{
+					map[string]interface{}{"name": "incident", "value": "1", "label": "New", "language": "en"},
+					map[string]interface{}{"name": "incident", "value": "2", "label": "In Progress", "language": "en"},
+				}
+			case "nameINincident,task^element=priority^inactive=false^ORDERBYsequence":
+				records = []interface{}{
+					map[string]interface{}{"name": "task", "value": "1", "label": "1 - Critical", "language": "en"},
+					map[string]interface{}{"name": "task", "value": "2", "label": "2 - High", "language": "en"},
+				}
+			case "nameINincident,task^element=category^inactive=false^ORDERBYsequence":
+				records = []interface{}{
+					map[string]interface{}{"name": "incident", "value": "hardware", "label": "Hardware", "language": "en"},
+				}
+			default:
+				t.Errorf("Unexpected choice query %s", r.URL.Query().Get("sysparm_query"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/incident/"+sysID:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": sysID}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	_, server := newTestRegistry(t, ts.URL, false)
+	updateIncident, _ := server.Handler("update_incident")
+
+	result, _ := updateIncident(context.Background(), map[string]interface{}{
+		"incident_id": sysID, "state": "in progress", "priority": "Critical", "category": "Hardware", "urgency": "2",
+	})
+	if result.IsError {
+		t.Fatalf("Expected the labels to be accepted, got %s", result.Content[0].Text)
+	}
+	if updated["state"] != "2" || updated["priority"] != "1" || updated["category"] != "hardware" || updated["urgency"] != "2" {
+		t.Errorf("Expected labels to be sent as values, got %+v", updated)
+	}
+
+	result, _ = updateIncident(context.Background(), map[string]interface{}{"incident_id": sysID, "state": "Parked"})
+	if text := result.Content[0].Text; !strings.Contains(text, "Invalid arguments") || !strings.Contains(text, "2 (In Progress)") {
+		t.Errorf("Expected an unknown state to be rejected with the valid states, got %s", text)
+	}
+}
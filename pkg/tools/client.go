@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// serviceNowClient is the part of the ServiceNow client used by tool handlers
+type serviceNowClient interface {
+	Get(endpoint string, params map[string]string) (map[string]interface{}, error)
+	GetWithContext(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, error)
+	Post(endpoint string, body interface{}) (map[string]interface{}, error)
+	PostWithContext(ctx context.Context, endpoint string, body interface{}) (map[string]interface{}, error)
+	Put(endpoint string, body interface{}) (map[string]interface{}, error)
+	PutWithContext(ctx context.Context, endpoint string, body interface{}) (map[string]interface{}, error)
+	Delete(endpoint string) (map[string]interface{}, error)
+	UploadAttachment(tableName, tableSysID, fileName, contentType string, data []byte) (map[string]interface{}, error)
+	DownloadAttachment(attachmentSysID string, maxBytes int64) ([]byte, string, error)
+	GetWithTotalCount(endpoint string, params map[string]string) (map[string]interface{}, int, error)
+	GetPages(endpoint string, params map[string]string, perPage int, fn servicenow.PageFunc) (*servicenow.PagingResult, error)
+	Batch(requests []servicenow.BatchRequest) ([]servicenow.BatchResponse, error)
+	InstanceRequestWithContext(ctx context.Context, method, path string, body interface{}) (map[string]interface{}, error)
+	Config() *servicenow.Config
+}
+
+// contextClient routes the context-free client methods through a request context,
+// so handlers calling r.client.Get and friends act with the caller's credentials
+type contextClient struct {
+	*servicenow.Client
+	ctx context.Context
+}
+
+func (c contextClient) Get(endpoint string, params map[string]string) (map[string]interface{}, error) {
+	return c.Client.GetWithContext(c.ctx, endpoint, params)
+}
+
+func (c contextClient) GetWithTotalCount(endpoint string, params map[string]string) (map[string]interface{}, int, error) {
+	return c.Client.GetWithTotalCountContext(c.ctx, endpoint, params)
+}
+
+func (c contextClient) GetPages(endpoint string, params map[string]string, perPage int, fn servicenow.PageFunc) (*servicenow.PagingResult, error) {
+	return c.Client.GetAllPages(c.ctx, endpoint, params, perPage, fn)
+}
+
+func (c contextClient) Post(endpoint string, body interface{}) (map[string]interface{}, error) {
+	return c.PostWithContext(c.ctx, endpoint, body)
+}
+
+func (c contextClient) Put(endpoint string, body interface{}) (map[string]interface{}, error) {
+	return c.PutWithContext(c.ctx, endpoint, body)
+}
+
+func (c contextClient) Delete(endpoint string) (map[string]interface{}, error) {
+	return c.DeleteWithContext(c.ctx, endpoint)
+}
+
+// The write methods charge each request to the caller's write quotas, note the records
+// they change for the session index, and drop the cached lookups the change may have
+// made stale
+
+func (c contextClient) PostWithContext(ctx context.Context, endpoint string, body interface{}) (map[string]interface{}, error) {
+	if err := mcp.ChargeQuota(ctx, http.MethodPost); err != nil {
+		return nil, err
+	}
+	result, err := c.Client.PostWithContext(ctx, endpoint, body)
+	if err == nil {
+		invalidateCache(c.Client.Cache(), http.MethodPost, endpoint)
+		recordWrite(ctx, http.MethodPost, endpoint, body, result)
+	}
+	return result, err
+}
+
+func (c contextClient) PutWithContext(ctx context.Context, endpoint string, body interface{}) (map[string]interface{}, error) {
+	if err := mcp.ChargeQuota(ctx, http.MethodPut); err != nil {
+		return nil, err
+	}
+	result, err := c.Client.PutWithContext(ctx, endpoint, body)
+	if err == nil {
+		invalidateCache(c.Client.Cache(), http.MethodPut, endpoint)
+		recordWrite(ctx, http.MethodPut, endpoint, body, result)
+	}
+	return result, err
+}
+
+func (c contextClient) DeleteWithContext(ctx context.Context, endpoint string) (map[string]interface{}, error) {
+	if err := mcp.ChargeQuota(ctx, http.MethodDelete); err != nil {
+		return nil, err
+	}
+	result, err := c.Client.DeleteWithContext(ctx, endpoint)
+	if err == nil {
+		invalidateCache(c.Client.Cache(), http.MethodDelete, endpoint)
+		recordWrite(ctx, http.MethodDelete, endpoint, nil, result)
+	}
+	return result, err
+}
+
+// instanceRecordAPIs maps the instance APIs outside /api/now that tools write through
+// to the table of the record whose sys_id follows in the path
+var instanceRecordAPIs = map[string]string{
+	"/api/sn_chg_rest/change/": "change_request",
+}
+
+// instanceTableEndpoint returns the Table API endpoint of the record an instance API
+// path acts on, e.g. /table/change_request/<sys_id> for /api/sn_chg_rest/change/<sys_id>/risk
+func instanceTableEndpoint(path string) (string, bool) {
+	for prefix, table := range instanceRecordAPIs {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			sysID, _, _ := strings.Cut(rest, "/")
+			if IsSysID(sysID) {
+				return "/table/" + table + "/" + sysID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// InstanceRequestWithContext sends a request to an instance API outside /api/now. Writes
+// are charged like the others, and recorded against the record the path names, if any.
+func (c contextClient) InstanceRequestWithContext(ctx context.Context, method, path string, body interface{}) (map[string]interface{}, error) {
+	if method == http.MethodGet {
+		return c.Client.InstanceRequestWithContext(ctx, method, path, body)
+	}
+	if err := mcp.ChargeQuota(ctx, method); err != nil {
+		return nil, err
+	}
+	result, err := c.Client.InstanceRequestWithContext(ctx, method, path, body)
+	if endpoint, ok := instanceTableEndpoint(path); ok && err == nil {
+		invalidateCache(c.Client.Cache(), method, endpoint)
+		recordWrite(ctx, method, endpoint, body, result)
+	}
+	return result, err
+}
+
+func (c contextClient) UploadAttachment(tableName, tableSysID, fileName, contentType string, data []byte) (map[string]interface{}, error) {
+	if err := mcp.ChargeQuota(c.ctx, http.MethodPost); err != nil {
+		return nil, err
+	}
+	result, err := c.Client.UploadAttachmentWithContext(c.ctx, tableName, tableSysID, fileName, contentType, data)
+	if err == nil {
+		recordUpload(c.ctx, result)
+	}
+	return result, err
+}
+
+// Batch charges each request to the caller's quotas and sends those within them; the
+// others fail on their own. Requests the batch didn't send are refunded, as the caller
+// may send them individually.
+func (c contextClient) Batch(requests []servicenow.BatchRequest) ([]servicenow.BatchResponse, error) {
+	refused := make([]error, len(requests))
+	allowed := make([]servicenow.BatchRequest, 0, len(requests))
+	for i, request := range requests {
+		if refused[i] = mcp.ChargeQuota(c.ctx, request.Method); refused[i] == nil {
+			allowed = append(allowed, request)
+		}
+	}
+	sent, err := c.Client.BatchWithContext(c.ctx, allowed)
+	for _, request := range allowed[len(sent):] {
+		mcp.RefundQuota(c.ctx, request.Method)
+	}
+
+	responses := make([]servicenow.BatchResponse, 0, len(requests))
+	for i, request := range requests {
+		if refused[i] != nil {
+			responses = append(responses, servicenow.BatchResponse{Err: refused[i]})
+			continue
+		}
+		if len(sent) == 0 {
+			break
+		}
+		response := sent[0]
+		sent = sent[1:]
+		if response.Err == nil {
+			invalidateCache(c.Client.Cache(), request.Method, request.Endpoint)
+			recordWrite(c.ctx, request.Method, request.Endpoint, request.Body, response.Result)
+		}
+		responses = append(responses, response)
+	}
+	return responses, err
+}
+
+func (c contextClient) DownloadAttachment(attachmentSysID string, maxBytes int64) ([]byte, string, error) {
+	return c.Client.DownloadAttachmentWithContext(c.ctx, attachmentSysID, maxBytes)
+}
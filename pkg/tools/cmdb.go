@@ -0,0 +1,338 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+const (
+	// defaultCIRelationshipType is the most common CMDB relationship: the parent depends on the child
+	defaultCIRelationshipType = "Depends on::Used by"
+	// maxCIRelationshipDepth bounds relationship traversal
+	maxCIRelationshipDepth = 5
+	// maxCIRelationshipEdges bounds the number of relationships returned by one traversal
+	maxCIRelationshipEdges = 500
+)
+
+// registerCMDBTools registers configuration item relationship tools
+func (r *Registry) registerCMDBTools(server *mcp.Server) int {
+	count := 0
+
+	depthMin := float64(1)
+	depthMax := float64(maxCIRelationshipDepth)
+
+	// Get CI Relationships
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_ci_relationships",
+		Description: "Traverse configuration item relationships (cmdb_rel_ci) for impact analysis. 'upstream' returns CIs that depend on this CI (what breaks if it fails); 'downstream' returns CIs this CI depends on.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"ci_id": {
+					Type:        "string",
+					Description: "Configuration item name (e.g., 'lnux100') or sys_id",
+				},
+				"direction": {
+					Type:        "string",
+					Description: "Traversal direction: 'upstream' (dependents), 'downstream' (dependencies), or 'both'",
+					Default:     "upstream",
+					Enum:        []string{"upstream", "downstream", "both"},
+				},
+				"depth": {
+					Type:        "integer",
+					Description: "Number of relationship hops to follow",
+					Default:     1,
+					Minimum:     &depthMin,
+					Maximum:     &depthMax,
+				},
+			},
+			Required: []string{"ci_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get CI Relationships",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getCIRelationships)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Add CI Relationship
+		r.registerTool(server, mcp.Tool{
+			Name:        "add_ci_relationship",
+			Description: "Create a relationship between two configuration items. With the default type, the parent depends on the child (e.g., an application depends on its server).",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"parent_ci": {
+						Type:        "string",
+						Description: "Parent configuration item name or sys_id (e.g., 'SAP Enterprise Services')",
+					},
+					"child_ci": {
+						Type:        "string",
+						Description: "Child configuration item name or sys_id (e.g., 'lnux100')",
+					},
+					"relationship_type": {
+						Type:        "string",
+						Description: "Relationship type name as 'parent descriptor::child descriptor' (e.g., 'Runs on::Runs', 'Contains::Contained by')",
+						Default:     defaultCIRelationshipType,
+					},
+				},
+				Required: []string{"parent_ci", "child_ci"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Add CI Relationship",
+			},
+		}, (*Registry).addCIRelationship)
+		count++
+	}
+
+	return count
+}
+
+// ciRelationship is one cmdb_rel_ci record found during traversal
+type ciRelationship struct {
+	ID        string `json:"relationship_id"`
+	Depth     int    `json:"depth"`
+	Direction string `json:"direction"`
+	Type      string `json:"type"`
+	Parent    ciRef  `json:"parent"`
+	Child     ciRef  `json:"child"`
+}
+
+// ciRef identifies a configuration item in a relationship
+type ciRef struct {
+	SysID string `json:"sys_id"`
+	Name  string `json:"name"`
+	Class string `json:"class"`
+}
+
+func (r *Registry) getCIRelationships(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	ciID := GetStringArg(args, "ci_id", "")
+	if ciID == "" {
+		return JSONResult(NewErrorResponse("ci_id is required", nil)), nil
+	}
+
+	depth := GetIntArg(args, "depth", 1)
+	if depth < 1 {
+		depth = 1
+	}
+	if depth > maxCIRelationshipDepth {
+		depth = maxCIRelationshipDepth
+	}
+
+	root, err := r.resolveCIID(ciID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find configuration item", err)), nil
+	}
+
+	direction := GetStringArg(args, "direction", "upstream")
+	var directions []string
+	switch direction {
+	case "upstream", "downstream":
+		directions = []string{direction}
+	case "both":
+		directions = []string{"upstream", "downstream"}
+	default:
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid direction: %s", direction), nil)), nil
+	}
+
+	relationships := []ciRelationship{}
+	cycles := 0
+	truncated := false
+	for _, dir := range directions {
+		edges, cyclesFound, limited, err := r.traverseCIRelationships(root, dir, depth, maxCIRelationshipEdges-len(relationships))
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to get CI relationships", err)), nil
+		}
+		relationships = append(relationships, edges...)
+		cycles += cyclesFound
+		truncated = truncated || limited
+	}
+
+	response := map[string]interface{}{
+		"success":       true,
+		"message":       fmt.Sprintf("Found %d relationships within %d hops", len(relationships), depth),
+		"ci_id":         root,
+		"relationships": relationships,
+	}
+	if cycles > 0 {
+		response["cycles_detected"] = cycles
+	}
+	if truncated {
+		response["truncated"] = true
+		response["message"] = fmt.Sprintf("Stopped after %d relationships; reduce depth for a complete result", len(relationships))
+	}
+	return JSONResult(response), nil
+}
+
+// traverseCIRelationships walks relationships breadth-first from root, one query per hop.
+// A CI reached again is not expanded a second time; such edges are counted as cycles.
+func (r *Registry) traverseCIRelationships(root, direction string, depth, maxEdges int) ([]ciRelationship, int, bool, error) {
+	// Upstream: the current CIs are children, the next hop is their parents
+	matchField, nextField := "child", "parent"
+	if direction == "downstream" {
+		matchField, nextField = "parent", "child"
+	}
+
+	if maxEdges <= 0 {
+		return nil, 0, true, nil
+	}
+
+	visited := map[string]bool{root: true}
+	frontier := []string{root}
+	edges := []ciRelationship{}
+	cycles := 0
+
+	for hop := 1; hop <= depth && len(frontier) > 0; hop++ {
+		result, err := r.client.Get("/table/cmdb_rel_ci", map[string]string{
+			"sysparm_query":                  fmt.Sprintf("%sIN%s", matchField, strings.Join(frontier, ",")),
+			"sysparm_fields":                 "sys_id,type.name,parent,parent.name,parent.sys_class_name,child,child.name,child.sys_class_name",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  fmt.Sprintf("%d", maxEdges-len(edges)+1),
+		})
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		next := []string{}
+		for _, record := range GetResultList(result) {
+			if len(edges) >= maxEdges {
+				return edges, cycles, true, nil
+			}
+
+			edge := ciRelationship{
+				ID:        FieldValue(record["sys_id"]),
+				Depth:     hop,
+				Direction: direction,
+				Type:      FieldValue(record["type.name"]),
+				Parent: ciRef{
+					SysID: FieldValue(record["parent"]),
+					Name:  FieldValue(record["parent.name"]),
+					Class: FieldValue(record["parent.sys_class_name"]),
+				},
+				Child: ciRef{
+					SysID: FieldValue(record["child"]),
+					Name:  FieldValue(record["child.name"]),
+					Class: FieldValue(record["child.sys_class_name"]),
+				},
+			}
+			edges = append(edges, edge)
+
+			target := edge.Parent.SysID
+			if nextField == "child" {
+				target = edge.Child.SysID
+			}
+			if visited[target] {
+				cycles++
+				continue
+			}
+			visited[target] = true
+			next = append(next, target)
+		}
+		frontier = next
+	}
+
+	return edges, cycles, false, nil
+}
+
+func (r *Registry) addCIRelationship(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	parentCI := GetStringArg(args, "parent_ci", "")
+	childCI := GetStringArg(args, "child_ci", "")
+	if parentCI == "" || childCI == "" {
+		return JSONResult(NewErrorResponse("parent_ci and child_ci are required", nil)), nil
+	}
+
+	parentSysID, err := r.resolveCIID(parentCI)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find parent configuration item", err)), nil
+	}
+	childSysID, err := r.resolveCIID(childCI)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find child configuration item", err)), nil
+	}
+	if parentSysID == childSysID {
+		return JSONResult(NewErrorResponse("A configuration item cannot be related to itself", nil)), nil
+	}
+
+	typeName := GetStringArg(args, "relationship_type", defaultCIRelationshipType)
+	typeResult, err := r.client.Get("/table/cmdb_rel_type", map[string]string{
+		"sysparm_query":  fmt.Sprintf("name=%s", SanitizeQueryValue(typeName)),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to look up relationship type", err)), nil
+	}
+	types := GetResultList(typeResult)
+	if len(types) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Relationship type not found: %s", typeName))), nil
+	}
+	typeSysID := FieldValue(types[0]["sys_id"])
+
+	existing, err := r.client.Get("/table/cmdb_rel_ci", map[string]string{
+		"sysparm_query":  fmt.Sprintf("parent=%s^child=%s^type=%s", parentSysID, childSysID, typeSysID),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to check existing relationships", err)), nil
+	}
+	if records := GetResultList(existing); len(records) > 0 {
+		return JSONResult(map[string]interface{}{
+			"success":         true,
+			"message":         "Relationship already exists",
+			"relationship_id": records[0]["sys_id"],
+		}), nil
+	}
+
+	result, err := r.client.Post("/table/cmdb_rel_ci", map[string]interface{}{
+		"parent": parentSysID,
+		"child":  childSysID,
+		"type":   typeSysID,
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to add CI relationship", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":         true,
+			"message":         fmt.Sprintf("Relationship created: %s %s %s", parentCI, strings.Split(typeName, "::")[0], childCI),
+			"relationship_id": resultData["sys_id"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+// resolveCIID resolves a configuration item name to sys_id
+func (r *Registry) resolveCIID(ciID string) (string, error) {
+	if IsSysID(ciID) {
+		return ciID, nil
+	}
+
+	params := map[string]string{
+		"sysparm_query": fmt.Sprintf("name=%s", SanitizeQueryValue(ciID)),
+		"sysparm_limit": "1",
+	}
+
+	result, err := r.client.Get("/table/cmdb_ci", params)
+	if err != nil {
+		return "", err
+	}
+
+	if records := GetResultList(result); len(records) > 0 {
+		if sysID, ok := records[0]["sys_id"].(string); ok {
+			return sysID, nil
+		}
+	}
+
+	return "", fmt.Errorf("configuration item %w: %s", ErrNotFound, ciID)
+}
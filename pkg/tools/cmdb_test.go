@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetCIRelationships tests depth-limited upstream traversal with cycle detection
+func TestGetCIRelationships(t *testing.T) {
+	// app -> db -> server, and server -> app closes a cycle (parent depends on child)
+	rels := []map[string]interface{}{
+		{"sys_id": "r1", "type.name": "Depends on::Used by", "parent": "app", "parent.name": "App", "child": "db", "child.name": "DB"},
+		{"sys_id": "r2", "type.name": "Depends on::Used by", "parent": "db", "parent.name": "DB", "child": "server", "child.name": "Server"},
+		{"sys_id": "r3", "type.name": "Depends on::Used by", "parent": "server", "parent.name": "Server", "child": "app", "child.name": "App"},
+	}
+
+	queries := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/cmdb_ci":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": "server"}}})
+		case "/api/now/table/cmdb_rel_ci":
+			queries++
+			children := strings.Split(strings.TrimPrefix(r.URL.Query().Get("sysparm_query"), "childIN"), ",")
+			matches := []interface{}{}
+			for _, rel := range rels {
+				for _, child := range children {
+					if rel["child"] == child {
+						matches = append(matches, rel)
+					}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": matches})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.getCIRelationships(map[string]interface{}{"ci_id": "Server", "depth": float64(5)})
+
+	var body struct {
+		Relationships []ciRelationship `json:"relationships"`
+		Cycles        int              `json:"cycles_detected"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(body.Relationships) != 3 || body.Cycles != 1 {
+		t.Fatalf("Expected 3 relationships and 1 cycle, got %+v", body)
+	}
+	if body.Relationships[0].Parent.Name != "DB" || body.Relationships[2].Depth != 3 {
+		t.Errorf("Unexpected traversal order %+v", body.Relationships)
+	}
+	if queries != 3 {
+		t.Errorf("Expected traversal to stop once the cycle closed, got %d queries", queries)
+	}
+}
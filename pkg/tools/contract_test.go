@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// contractSysID is the sys_id of every fixture record served by the fake instance
+const contractSysID = "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"
+
+// contractRecord is a representative Table API record carrying the fields read tools commonly inspect
+func contractRecord() map[string]interface{} {
+	return map[string]interface{}{
+		"sys_id":            contractSysID,
+		"number":            "TEST0010001",
+		"name":              "Test Record",
+		"user_name":         "test.user",
+		"email":             "test.user@example.com",
+		"short_description": "Test record",
+		"description":       "Test description",
+		"state":             "1",
+		"priority":          "3",
+		"active":            "true",
+		"assigned_to":       map[string]interface{}{"value": contractSysID, "display_value": "Test User"},
+		"assignment_group":  map[string]interface{}{"value": contractSysID, "display_value": "Test Group"},
+		"sys_created_on":    "2024-01-01 00:00:00",
+		"sys_updated_on":    "2024-01-02 00:00:00",
+	}
+}
+
+// newContractServer fakes a ServiceNow instance that answers every GET with fixture records:
+// a single record for /table/<name>/<sys_id> paths and a one-record list otherwise
+func newContractServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			t.Errorf("Read tool issued %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var result interface{} = []interface{}{contractRecord()}
+		if parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/now/"), "/"); len(parts) == 3 && parts[0] == "table" {
+			result = contractRecord()
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+}
+
+// contractValue builds a representative argument value for a schema property
+func contractValue(name string, prop mcp.Property) interface{} {
+	if len(prop.Enum) > 0 {
+		return prop.Enum[0]
+	}
+	switch prop.Type {
+	case "integer", "number":
+		if prop.Minimum != nil && *prop.Minimum > 1 {
+			return *prop.Minimum
+		}
+		return float64(1)
+	case "boolean":
+		return true
+	case "array":
+		if prop.Items != nil {
+			return []interface{}{contractValue(name, *prop.Items)}
+		}
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	}
+	if strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_ids") {
+		return contractSysID
+	}
+	return "test"
+}
+
+// contractArgs builds arguments for a tool: its required properties, or every property when all is set.
+// With stringly set, booleans and numbers are sent as strings the way LLMs often produce them.
+func contractArgs(tool mcp.Tool, all, stringly bool) map[string]interface{} {
+	names := tool.InputSchema.Required
+	if all {
+		names = nil
+		for name := range tool.InputSchema.Properties {
+			names = append(names, name)
+		}
+	}
+
+	args := map[string]interface{}{}
+	for _, name := range names {
+		value := contractValue(name, tool.InputSchema.Properties[name])
+		if stringly {
+			switch v := value.(type) {
+			case bool, float64:
+				value = fmt.Sprint(v)
+			}
+		}
+		args[name] = value
+	}
+	return args
+}
+
+// callContract invokes a handler, converting a panic into a test failure
+func callContract(t *testing.T, handler mcp.ToolHandlerWithContext, args map[string]interface{}) (result *mcp.CallToolResult, err error) {
+	t.Helper()
+	defer func() {
+		if p := recover(); p != nil {
+			t.Fatalf("Handler panicked with args %v: %v", args, p)
+		}
+	}()
+	return handler(context.Background(), args)
+}
+
+// assertEnvelope checks the response envelope every tool result must follow: success,
+// message, and warnings when present
+func assertEnvelope(t *testing.T, result *mcp.CallToolResult, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Expected errors to be reported in the result, got handler error: %v", err)
+	}
+	if result == nil || len(result.Content) == 0 {
+		t.Fatalf("Expected at least one content item, got %+v", result)
+	}
+	if result.Content[0].Type != "text" {
+		t.Errorf("Expected text content, got %q", result.Content[0].Type)
+	}
+	if result.IsError {
+		return
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Expected a JSON object, got %q: %v", result.Content[0].Text, err)
+	}
+	if success, ok := body["success"]; ok {
+		if _, isBool := success.(bool); !isBool {
+			t.Errorf("Expected success to be a boolean, got %T", success)
+		}
+		if _, ok := body["message"].(string); !ok {
+			t.Errorf("Expected a message alongside success, got %v", body["message"])
+		}
+	}
+	if warnings, ok := body["warnings"]; ok {
+		list, isList := warnings.([]interface{})
+		if !isList {
+			t.Errorf("Expected warnings to be an array, got %T", warnings)
+		}
+		for _, warning := range list {
+			if _, isString := warning.(string); !isString {
+				t.Errorf("Expected each warning to be a string, got %T", warning)
+			}
+		}
+	}
+}
+
+// TestReadToolContracts runs every registered read tool against a fake instance and
+// checks that it returns a well-formed envelope without panicking, including when
+// booleans and numbers arrive as strings
+func TestReadToolContracts(t *testing.T) {
+	ts := newContractServer(t)
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	registry.EnableDiagnosticTools()
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	tools := server.ListTools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	tested := 0
+	for _, tool := range tools {
+		if tool.Annotations == nil || !tool.Annotations.ReadOnlyHint || tool.Name == "sleep" {
+			continue
+		}
+		handler, ok := server.Handler(tool.Name)
+		if !ok {
+			continue
+		}
+		tested++
+
+		for _, variant := range []struct {
+			name     string
+			all      bool
+			stringly bool
+		}{{"required", false, false}, {"all", true, false}, {"all_as_strings", true, true}} {
+			t.Run(fmt.Sprintf("%s/%s", tool.Name, variant.name), func(t *testing.T) {
+				args := contractArgs(tool, variant.all, variant.stringly)
+				result, err := callContract(t, handler, args)
+				assertEnvelope(t, result, err)
+			})
+		}
+	}
+
+	if tested == 0 {
+		t.Fatal("Expected read tools to be registered")
+	}
+}
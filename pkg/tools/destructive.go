@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// Incident and change request states the destructive tools set or refuse
+const (
+	incidentStateResolved = "6"
+	incidentStateClosed   = "7"
+	incidentStateCanceled = "8"
+	changeStateClosed     = "3"
+	changeStateCanceled   = "4"
+)
+
+// EnableDestructiveTools registers the close, cancel, and delete tools on the next
+// RegisterAll call (never in read-only mode)
+func (r *Registry) EnableDestructiveTools() {
+	r.destructive = true
+}
+
+// registerDestructiveTools registers the tools that close, cancel, or delete records
+// for good. They are only registered when destructive operations are allowed
+// (ALLOW_DESTRUCTIVE=true) outside read-only mode.
+func (r *Registry) registerDestructiveTools(server *mcp.Server) int {
+	count := 0
+
+	if !r.readOnlyMode {
+		// Close Incident
+		r.registerTool(server, mcp.Tool{
+			Name:        "close_incident",
+			Description: "Close an incident, or cancel it with cancel=true. Closed and canceled incidents can't be reopened; use resolve_incident when the caller may still reopen it. close_code and close_notes default to the incident's resolution.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"incident_id": {
+						Type:        "string",
+						Description: "Incident number (e.g., 'INC0010001') or sys_id",
+					},
+					"close_code": {
+						Type:        "string",
+						Description: "Close code (e.g., 'Solved (Permanently)'); required unless the incident is resolved or canceled",
+					},
+					"close_notes": {
+						Type:        "string",
+						Description: "Close notes; required unless the incident is resolved or canceled",
+					},
+					"cancel": {
+						Type:        "boolean",
+						Description: "Cancel the incident instead of closing it (e.g., raised in error or a duplicate)",
+						Default:     false,
+					},
+				},
+				Required: []string{"incident_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Close Incident",
+				DestructiveHint: true,
+			},
+		}, (*Registry).closeIncident)
+		count++
+
+		// Cancel Change
+		r.registerTool(server, mcp.Tool{
+			Name:        "cancel_change",
+			Description: "Cancel a change request that is not closed. Canceled changes can't be resumed; raise a new change instead.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"change_id": {
+						Type:        "string",
+						Description: "Change request number (e.g., 'CHG0010001') or sys_id",
+					},
+					"reason": {
+						Type:        "string",
+						Description: "Why the change is canceled, added as a work note",
+					},
+				},
+				Required: []string{"change_id", "reason"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Cancel Change",
+				DestructiveHint: true,
+			},
+		}, (*Registry).cancelChange)
+		count++
+
+		// Delete KB Article
+		r.registerTool(server, mcp.Tool{
+			Name:        "delete_kb_article",
+			Description: "Permanently delete a knowledge article. The deletion can only be undone with restore_deleted_record when the instance keeps deleted records.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"article_id": {
+						Type:        "string",
+						Description: "Article number (e.g., 'KB0010001') or sys_id",
+					},
+				},
+				Required: []string{"article_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Delete Knowledge Article",
+				DestructiveHint: true,
+			},
+		}, (*Registry).deleteKBArticle)
+		count++
+
+		// Delete Catalog Variable
+		r.registerTool(server, mcp.Tool{
+			Name:        "delete_catalog_variable",
+			Description: "Permanently delete a catalog item variable. Requests already submitted keep their answers, but the item no longer asks the question.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"variable_id": {
+						Type:        "string",
+						Description: "Variable sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
+					},
+				},
+				Required: []string{"variable_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Delete Catalog Variable",
+				DestructiveHint: true,
+			},
+		}, (*Registry).deleteCatalogVariable)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) closeIncident(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	incidentID := GetStringArg(args, "incident_id", "")
+	if incidentID == "" {
+		return JSONResult(NewErrorResponse("incident_id is required", nil)), nil
+	}
+
+	incident, err := r.getIncidentRecord(incidentID, "sys_id,number,state,close_code,close_notes")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find incident", err)), nil
+	}
+	number := FieldValue(incident["number"])
+	state := FieldValue(incident["state"])
+	if state == incidentStateClosed || state == incidentStateCanceled {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Incident %s is already %s", number, FieldDisplay(incident["state"])), nil)), nil
+	}
+
+	cancel := GetBoolArg(args, "cancel", false)
+	data := map[string]interface{}{"state": incidentStateClosed}
+	message := fmt.Sprintf("Incident %s closed", number)
+	if cancel {
+		data["state"] = incidentStateCanceled
+		message = fmt.Sprintf("Incident %s canceled", number)
+	}
+
+	// A resolved incident closes with its resolution
+	for _, field := range []string{"close_code", "close_notes"} {
+		value := GetStringArg(args, field, "")
+		if value == "" && state == incidentStateResolved {
+			value = FieldValue(incident[field])
+		}
+		if value == "" && !cancel {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("%s is required to close incident %s, which is not resolved", field, number), nil)), nil
+		}
+		if value != "" {
+			data[field] = value
+		}
+	}
+
+	result, err := r.client.Put("/table/incident/"+FieldValue(incident["sys_id"]), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to close incident", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":         true,
+			"message":         message,
+			"incident_id":     resultData["sys_id"],
+			"incident_number": number,
+			"state":           resultData["state"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) cancelChange(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	changeID := GetStringArg(args, "change_id", "")
+	reason := GetStringArg(args, "reason", "")
+	if changeID == "" || reason == "" {
+		return JSONResult(NewErrorResponse("change_id and reason are required", nil)), nil
+	}
+
+	sysID, err := r.resolveChangeID(changeID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find change request", err)), nil
+	}
+	current, err := r.client.Get("/table/change_request/"+sysID, map[string]string{
+		"sysparm_fields":        "number,state",
+		"sysparm_display_value": "all",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read change request", err)), nil
+	}
+	change, _ := current["result"].(map[string]interface{})
+	number := FieldValue(change["number"])
+	if state := FieldValue(change["state"]); state == changeStateClosed || state == changeStateCanceled {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Change %s is already %s", number, FieldDisplay(change["state"])), nil)), nil
+	}
+
+	result, err := r.client.Put("/table/change_request/"+sysID, map[string]interface{}{
+		"state":      changeStateCanceled,
+		"work_notes": "Canceled: " + reason,
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to cancel change request", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":       true,
+			"message":       fmt.Sprintf("Change %s canceled", number),
+			"change_id":     resultData["sys_id"],
+			"change_number": number,
+			"state":         resultData["state"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) deleteKBArticle(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	articleID := GetStringArg(args, "article_id", "")
+	if articleID == "" {
+		return JSONResult(NewErrorResponse("article_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveRecordNumber("kb_knowledge", articleID, "knowledge article")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find knowledge article", err)), nil
+	}
+
+	recovery, err := r.deleteRecord("kb_knowledge", sysID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to delete knowledge article", err)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":    true,
+		"message":    "Knowledge article deleted successfully",
+		"article_id": sysID,
+		"recovery":   recovery,
+	}), nil
+}
+
+func (r *Registry) deleteCatalogVariable(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	variableID := GetStringArg(args, "variable_id", "")
+	if variableID == "" {
+		return JSONResult(NewErrorResponse("variable_id is required", nil)), nil
+	}
+
+	recovery, err := r.deleteRecord("item_option_new", variableID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to delete catalog variable", err)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":     true,
+		"message":     "Catalog variable deleted successfully",
+		"variable_id": variableID,
+		"recovery":    recovery,
+	}), nil
+}
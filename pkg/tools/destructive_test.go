@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestDestructiveTools tests that the close, cancel, and delete tools are opt-in and never registered read-only, that closing a resolved incident keeps its resolution, and that closed changes and delete-protected articles are refused
+func TestDestructiveTools(t *testing.T) {
+	const (
+		incidentID = "11111111111111111111111111111111"
+		changeID   = "22222222222222222222222222222222"
+		articleID  = "33333333333333333333333333333333"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+
+	incidentState, changeState := "6", "-2"
+	recycleBin := true
+	var updated map[string]interface{}
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident/"+incidentID:
+			result = map[string]interface{}{
+				"sys_id": field(incidentID, incidentID), "number": field("INC0010001", "INC0010001"), "state": field(incidentState, "Resolved"),
+				"close_code": field("Solved (Permanently)", "Solved (Permanently)"), "close_notes": field("Rebooted", "Rebooted"),
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/change_request":
+			result = []interface{}{map[string]interface{}{"sys_id": changeID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/change_request/"+changeID:
+			result = map[string]interface{}{"number": field("CHG0010001", "CHG0010001"), "state": field(changeState, "Scheduled")}
+		case r.Method == http.MethodPut:
+			updated = nil
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			result = map[string]interface{}{"sys_id": strings.TrimPrefix(r.URL.Path, "/api/now/table/"), "state": updated["state"]}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/kb_knowledge":
+			result = []interface{}{map[string]interface{}{"sys_id": articleID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_audit_delete":
+			if !recycleBin {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error": {"message": "Forbidden"}}`))
+				return
+			}
+			result = []interface{}{map[string]interface{}{"sys_id": "d1"}}
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registered := func(registry *Registry) bool {
+		server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+		registry.RegisterAll(server)
+		for _, tool := range server.ListTools() {
+			if tool.Name == "close_incident" {
+				return tool.Annotations != nil && tool.Annotations.DestructiveHint
+			}
+		}
+		return false
+	}
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	if registered(registry) {
+		t.Error("Expected close_incident to be unregistered unless enabled")
+	}
+	registry.EnableDestructiveTools()
+	if !registered(registry) {
+		t.Error("Expected close_incident to be registered with a destructive hint once enabled")
+	}
+	readOnly, _ := newTestRegistry(t, ts.URL, true)
+	readOnly.EnableDestructiveTools()
+	if registered(readOnly) {
+		t.Error("Expected close_incident to be unregistered in read-only mode")
+	}
+
+	result, _ := registry.closeIncident(map[string]interface{}{"incident_id": incidentID})
+	if updated["state"] != "7" || updated["close_code"] != "Solved (Permanently)" || updated["close_notes"] != "Rebooted" {
+		t.Errorf("Expected the resolved incident to close with its resolution, got %+v (%s)", updated, result.Content[0].Text)
+	}
+
+	incidentState = "2"
+	result, _ = registry.closeIncident(map[string]interface{}{"incident_id": incidentID})
+	if !strings.Contains(result.Content[0].Text, "close_code is required") {
+		t.Errorf("Expected an unresolved incident to need a close code, got %s", result.Content[0].Text)
+	}
+	registry.closeIncident(map[string]interface{}{"incident_id": incidentID, "cancel": true})
+	if updated["state"] != "8" {
+		t.Errorf("Expected the incident to be canceled, got %+v", updated)
+	}
+
+	registry.cancelChange(map[string]interface{}{"change_id": "CHG0010001", "reason": "Superseded by CHG0010002"})
+	if updated["state"] != "4" || updated["work_notes"] != "Canceled: Superseded by CHG0010002" {
+		t.Errorf("Unexpected cancel payload: %+v", updated)
+	}
+	changeState = "3"
+	result, _ = registry.cancelChange(map[string]interface{}{"change_id": "CHG0010001", "reason": "Again"})
+	if !strings.Contains(result.Content[0].Text, "already") {
+		t.Errorf("Expected a closed change to be refused, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.deleteKBArticle(map[string]interface{}{"article_id": "KB0010001"})
+	if len(deleted) != 1 || deleted[0] != "/api/now/table/kb_knowledge/"+articleID || !strings.Contains(result.Content[0].Text, `"restorable": true`) {
+		t.Errorf("Expected the article to be deleted restorably, got %v (%s)", deleted, result.Content[0].Text)
+	}
+
+	recycleBin = false
+	noRecycleBin, _ := newTestRegistry(t, ts.URL, false)
+	result, _ = noRecycleBin.deleteKBArticle(map[string]interface{}{"article_id": articleID})
+	if len(deleted) != 1 || !strings.Contains(result.Content[0].Text, "delete-protected") {
+		t.Errorf("Expected the article delete to be refused without deleted records, got %s", result.Content[0].Text)
+	}
+	noRecycleBin.deleteCatalogVariable(map[string]interface{}{"variable_id": "44444444444444444444444444444444"})
+	if len(deleted) != 2 || deleted[1] != "/api/now/table/item_option_new/44444444444444444444444444444444" {
+		t.Errorf("Expected the catalog variable to be deleted, got %v", deleted)
+	}
+}
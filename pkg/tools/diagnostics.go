@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// maxDiagnosticSleepSeconds caps how long the sleep diagnostic tool may block
+const maxDiagnosticSleepSeconds = 300
+
+// registerDiagnosticTools registers transport diagnostic tools that never call ServiceNow.
+// They are only registered when diagnostics are enabled (MCP_DIAGNOSTIC_TOOLS=true).
+func (r *Registry) registerDiagnosticTools(server *mcp.Server) int {
+	count := 0
+
+	sleepMin := float64(0)
+	sleepMax := float64(maxDiagnosticSleepSeconds)
+
+	// Echo
+	r.registerTool(server, mcp.Tool{
+		Name:        "echo",
+		Description: "Echo back the message and arguments received. Diagnostic tool for testing client connectivity; does not call ServiceNow.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"message": {
+					Type:        "string",
+					Description: "Text to echo back (e.g., 'hello')",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:          "Echo",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, (*Registry).diagnosticEcho)
+	count++
+
+	// Sleep
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "sleep",
+		Description: "Wait for the given number of seconds before responding. Diagnostic tool for testing client timeouts; does not call ServiceNow.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"seconds": {
+					Type:        "integer",
+					Description: "Seconds to wait",
+					Default:     1,
+					Minimum:     &sleepMin,
+					Maximum:     &sleepMax,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:          "Sleep",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, r.diagnosticSleep)
+	count++
+
+	// Error test
+	r.registerTool(server, mcp.Tool{
+		Name:        "error_test",
+		Description: "Return an error on purpose. Diagnostic tool for testing how clients render tool errors; does not call ServiceNow.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"mode": {
+					Type:        "string",
+					Description: "Error kind: 'result' returns an error result, 'handler' fails inside the tool handler",
+					Default:     "result",
+					Enum:        []string{"result", "handler"},
+				},
+				"message": {
+					Type:        "string",
+					Description: "Error message to return (e.g., 'simulated failure')",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:          "Error Test",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, (*Registry).diagnosticError)
+	count++
+
+	return count
+}
+
+func (r *Registry) diagnosticEcho(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	return JSONResult(map[string]interface{}{
+		"message":   GetStringArg(args, "message", ""),
+		"arguments": args,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}), nil
+}
+
+// diagnosticSleep waits for the requested time, returning early when the call is cancelled or times out
+func (r *Registry) diagnosticSleep(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	seconds := GetIntArg(args, "seconds", 1)
+	if seconds < 0 || seconds > maxDiagnosticSleepSeconds {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("seconds must be between 0 and %d", maxDiagnosticSleepSeconds), nil)), nil
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(time.Duration(seconds) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return JSONResult(NewErrorResponse("Sleep interrupted", ctx.Err())), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"requested_seconds": seconds,
+		"elapsed_ms":        time.Since(start).Milliseconds(),
+	}), nil
+}
+
+func (r *Registry) diagnosticError(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	message := GetStringArg(args, "message", "simulated failure")
+
+	switch GetStringArg(args, "mode", "result") {
+	case "result":
+		return ErrorResult(message), nil
+	case "handler":
+		return nil, fmt.Errorf("%s", message)
+	default:
+		return JSONResult(NewErrorResponse("mode must be 'result' or 'handler'", nil)), nil
+	}
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// DailyDigestURI is the resource URI of the daily record digest
+const DailyDigestURI = "servicenow://digest/daily"
+
+// defaultDigestTables are the task tables summarized by the daily digest
+var defaultDigestTables = []string{"incident", "change_request", "problem", "sc_req_item"}
+
+// maxDigestRecordsPerTable bounds the number of records listed per table
+const maxDigestRecordsPerTable = 25
+
+// SetDigestTables sets the tables summarized by the daily digest resource. Tables
+// must be task-based (they need assigned_to, opened_by, and watch_list fields).
+func (r *Registry) SetDigestTables(tables []string) error {
+	selected := make([]string, 0, len(tables))
+	for _, table := range tables {
+		table = strings.TrimSpace(table)
+		if table == "" {
+			continue
+		}
+		if !tableNamePattern.MatchString(table) {
+			return fmt.Errorf("invalid table name %q", table)
+		}
+		selected = append(selected, table)
+	}
+	r.digestTables = selected
+	return nil
+}
+
+// digestProvider serves the daily digest, generated for the caller on every read
+type digestProvider struct {
+	registry *Registry
+}
+
+// ListResources lists the digest resource
+func (p digestProvider) ListResources() []mcp.Resource {
+	return []mcp.Resource{{
+		URI:         DailyDigestURI,
+		Name:        "Daily digest",
+		Description: "Records updated today that you are assigned to, opened, or watch",
+		MimeType:    "text/markdown",
+	}}
+}
+
+// ReadResource generates the digest for the configured user
+func (p digestProvider) ReadResource(uri string) (*mcp.ReadResourceResult, error) {
+	return p.ReadResourceWithContext(context.Background(), uri)
+}
+
+// ReadResourceWithContext generates the digest for the user whose credentials came with the request
+func (p digestProvider) ReadResourceWithContext(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if uri != DailyDigestURI {
+		return nil, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+
+	text, err := p.registry.forContext(ctx).dailyDigest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContent{{URI: uri, MimeType: "text/markdown", Text: text}},
+	}, nil
+}
+
+// dailyDigest renders the records updated today that the caller is assigned to, opened, or watches
+func (r *Registry) dailyDigest(ctx context.Context) (string, error) {
+	userID, userName, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tables := r.digestTables
+	if len(tables) == 0 {
+		tables = defaultDigestTables
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Daily digest for %s\n", userName)
+
+	// Tables may overlap (e.g., task and incident), so records are collected once each
+	var records taskRecordSet
+	var failed []string
+	for _, table := range tables {
+		result, err := r.client.Get(fmt.Sprintf("/table/%s", table), map[string]string{
+			"sysparm_query": fmt.Sprintf("sys_updated_on>=javascript:gs.beginningOfToday()^assigned_to=%[1]s^ORopened_by=%[1]s^ORwatch_listLIKE%[1]s^ORDERBYDESCsys_updated_on",
+				userID),
+			"sysparm_fields":                 "sys_id,sys_class_name,number,short_description,state,priority,assigned_to,opened_by,watch_list,sys_updated_on,sys_updated_by",
+			"sysparm_display_value":          "all",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  fmt.Sprintf("%d", maxDigestRecordsPerTable),
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("\n## %s\n\nFailed to load: %v\n", table, err))
+			continue
+		}
+		for _, record := range GetResultList(result) {
+			records.add(table, record)
+		}
+	}
+
+	classes, groups := records.byClass()
+	for _, class := range classes {
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", class, len(groups[class]))
+		for _, record := range groups[class] {
+			fmt.Fprintf(&b, "- **%s** %s — %s, priority %s. Updated %s by %s (%s)\n",
+				FieldDisplay(record.fields["number"]), FieldDisplay(record.fields["short_description"]),
+				FieldDisplay(record.fields["state"]), FieldDisplay(record.fields["priority"]),
+				FieldDisplay(record.fields["sys_updated_on"]), FieldDisplay(record.fields["sys_updated_by"]),
+				digestReason(record.fields, userID))
+		}
+	}
+	for _, failure := range failed {
+		b.WriteString(failure)
+	}
+
+	if len(records.records) == 0 {
+		b.WriteString("\nNothing you are assigned to, opened, or watch has changed today.\n")
+	}
+	return b.String(), nil
+}
+
+// digestReason explains why a record is in the caller's digest
+func digestReason(record map[string]interface{}, userID string) string {
+	var reasons []string
+	if FieldValue(record["assigned_to"]) == userID {
+		reasons = append(reasons, "assigned to you")
+	}
+	if FieldValue(record["opened_by"]) == userID {
+		reasons = append(reasons, "opened by you")
+	}
+	if strings.Contains(FieldValue(record["watch_list"]), userID) {
+		reasons = append(reasons, "watching")
+	}
+	if len(reasons) == 0 {
+		return "related to you"
+	}
+	return strings.Join(reasons, ", ")
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// TestDailyDigest tests that the digest resource is generated for the calling user
+func TestDailyDigest(t *testing.T) {
+	var queried []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_user":
+			if r.URL.Query().Get("sysparm_query") != "user_name=jane.doe" {
+				t.Errorf("Expected the digest to be built for the caller, got %s", r.URL.Query().Get("sysparm_query"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": "jane"}}})
+		case "/api/now/table/incident", "/api/now/table/problem":
+			queried = append(queried, r.URL.Path)
+			if !strings.Contains(r.URL.Query().Get("sysparm_query"), "assigned_to=jane^ORopened_by=jane^ORwatch_listLIKEjane") {
+				t.Errorf("Expected a caller-scoped query, got %s", r.URL.Query().Get("sysparm_query"))
+			}
+			result := []interface{}{}
+			if strings.HasSuffix(r.URL.Path, "incident") {
+				result = append(result, map[string]interface{}{
+					"number":            map[string]interface{}{"value": "INC0010001", "display_value": "INC0010001"},
+					"short_description": map[string]interface{}{"value": "Email down", "display_value": "Email down"},
+					"assigned_to":       map[string]interface{}{"value": "bob", "display_value": "Bob"},
+					"watch_list":        map[string]interface{}{"value": "bob,jane", "display_value": "Bob, Jane"},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	if err := registry.SetDigestTables([]string{"incident", " problem", ""}); err != nil {
+		t.Fatalf("Expected tables to be accepted, got %v", err)
+	}
+	if err := registry.SetDigestTables([]string{"incident^ORactive=true"}); err == nil {
+		t.Error("Expected invalid table name to be rejected")
+	}
+
+	provider := digestProvider{registry: registry}
+	ctx := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: "jane.doe", Password: "secret"})
+	if _, err := provider.ReadResourceWithContext(ctx, "servicenow://digest/weekly"); err == nil {
+		t.Error("Expected unknown resource to be rejected")
+	}
+
+	result, err := provider.ReadResourceWithContext(ctx, DailyDigestURI)
+	if err != nil {
+		t.Fatalf("Failed to read digest: %v", err)
+	}
+	text := result.Contents[0].Text
+	if !strings.Contains(text, "## incident (1)") || !strings.Contains(text, "**INC0010001** Email down") || !strings.Contains(text, "(watching)") {
+		t.Errorf("Unexpected digest:\n%s", text)
+	}
+	if strings.Contains(text, "## problem") || len(queried) != 2 {
+		t.Errorf("Expected empty tables to be queried but omitted, queried %v:\n%s", queried, text)
+	}
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// Errors wrapped by lookups (e.g., "user not found: jdoe"), classified by errorCode
+var (
+	// ErrNotFound is a record or other object that doesn't exist or the caller can't read
+	ErrNotFound = errors.New("not found")
+	// ErrAmbiguousID is a name or number matching more than one record
+	ErrAmbiguousID = errors.New("matches more than one record")
+	// ErrUnknownCaller is a call that acts as the calling user without knowing who that is
+	ErrUnknownCaller = errors.New("caller's ServiceNow user is unknown")
+)
+
+// errorCode classifies the error behind an error response. Responses without an error
+// are the tool rejecting its arguments.
+func errorCode(err error) mcp.ToolErrorCode {
+	if err == nil {
+		return mcp.ErrorValidationFailed
+	}
+	if errors.Is(err, ErrNotFound) {
+		return mcp.ErrorNotFound
+	}
+	if errors.Is(err, ErrAmbiguousID) {
+		return mcp.ErrorAmbiguousID
+	}
+	if errors.Is(err, ErrUnknownCaller) {
+		return mcp.ErrorPermissionDenied
+	}
+	if errors.Is(err, mcp.ErrQuotaExceeded) {
+		return mcp.ErrorRateLimited
+	}
+
+	var apiErr *servicenow.APIError
+	if !errors.As(err, &apiErr) {
+		return mcp.ErrorUpstreamError
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		return mcp.ErrorNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return mcp.ErrorPermissionDenied
+	case http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity:
+		return mcp.ErrorValidationFailed
+	case http.StatusTooManyRequests:
+		return mcp.ErrorRateLimited
+	}
+	return mcp.ErrorUpstreamError
+}
@@ -0,0 +1,129 @@
+package tools
+
+import "github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+
+// toolExamples holds example argument payloads for tools whose arguments are easy to
+// get wrong (structured filters, nested arguments, date/times, reference lookups).
+// They are published as the JSON Schema "examples" of each tool's input schema.
+var toolExamples = map[string][]map[string]interface{}{
+	"query_table": {
+		{
+			"table": "cmdb_ci_server",
+			"filters": []interface{}{
+				map[string]interface{}{"field": "os", "operator": "starts_with", "value": "Linux"},
+				map[string]interface{}{"field": "install_status", "operator": "in", "value": "1,3"},
+			},
+			"fields":   []interface{}{"name", "ip_address", "os", "install_status"},
+			"order_by": "name", "order_direction": "asc", "limit": 50,
+		},
+		{
+			"table":    "sys_user_role",
+			"query":    "nameSTARTSWITHitil",
+			"fields":   []interface{}{"name", "description"},
+			"order_by": "name", "order_direction": "asc",
+		},
+	},
+	"batch_update": {
+		{
+			"table":   "incident",
+			"sys_ids": []interface{}{"a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "b1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"},
+			"fields":  map[string]interface{}{"assignment_group": "c1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "work_notes": "Reassigned to Network"},
+		},
+		{
+			"table": "sc_task",
+			"records": []interface{}{
+				map[string]interface{}{"sys_id": "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "fields": map[string]interface{}{"state": "3"}},
+				map[string]interface{}{"fields": map[string]interface{}{"short_description": "Ship laptop", "request_item": "d1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"}},
+			},
+		},
+	},
+	"start_job": {
+		{
+			"type":  jobTypeExportTable,
+			"table": "incident",
+			"filters": []interface{}{
+				map[string]interface{}{"field": "active", "operator": "equals", "value": "true"},
+				map[string]interface{}{"field": "priority", "operator": "less_or_equal", "value": "2"},
+			},
+			"fields":      []interface{}{"number", "short_description", "priority", "assignment_group"},
+			"max_records": 5000,
+		},
+		{
+			"type": jobTypeToolCall,
+			"tool": "move_catalog_items",
+			"arguments": map[string]interface{}{
+				"item_ids":           []interface{}{"a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "b1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"},
+				"target_category_id": "c1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6",
+			},
+		},
+	},
+	"list_incidents": {
+		{"state": "2", "category": "Network", "limit": 20},
+		{"assigned_to": "beth.anglin", "query": "VPN"},
+	},
+	"create_incident": {
+		{
+			"short_description": "Email not syncing on mobile devices",
+			"description":       "Since 09:00 Outlook on iOS shows 'Cannot get mail'. Webmail works.",
+			"caller_id":         "abel.tuter",
+			"category":          "Software",
+			"impact":            "2",
+			"urgency":           "2",
+			"assignment_group":  "Service Desk",
+		},
+	},
+	"update_incident": {
+		{"incident_id": "INC0010001", "state": "2", "assigned_to": "beth.anglin", "work_notes": "Investigating with @david.loo"},
+		{"incident_id": "INC0010001", "state": "3", "work_notes": "Waiting for the vendor to ship a replacement disk"},
+	},
+	"create_change_request": {
+		{
+			"type":              "normal",
+			"short_description": "Upgrade database cluster to 15.4",
+			"description":       "Rolling upgrade of the three replicas; rollback restores the snapshot taken before the change.",
+			"risk":              "3",
+			"impact":            "2",
+			"assignment_group":  "Database",
+			"start_date":        "2024-12-15 22:00:00",
+			"end_date":          "2024-12-16 02:00:00",
+		},
+	},
+	"list_change_schedule": {
+		{"start_date": "2024-12-14", "end_date": "2024-12-15"},
+		{"start_date": "2024-12-01", "end_date": "2024-12-31", "type": "normal", "risk": "2"},
+	},
+	"compute_business_duration": {
+		{"schedule_id": "8-5 weekdays excluding holidays", "start": "2024-12-13 16:00:00", "end": "2024-12-17 10:00:00"},
+		{"schedule_id": "8-5 weekdays excluding holidays", "start": "2024-12-13 16:00:00", "business_hours": 4},
+	},
+	"will_breach_soon": {
+		{"remaining_percent": 20, "task_table": "incident", "priority": "1"},
+	},
+	"get_pa_scores": {
+		{"indicator_id": "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "from": "2024-11-01", "to": "2024-11-30"},
+	},
+	"add_ci_relationship": {
+		{"parent_ci": "SAP Enterprise Services", "child_ci": "lnux100", "relationship_type": "Runs on::Runs"},
+	},
+	"create_catalog_item_variable": {
+		{
+			"item_id":       "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6",
+			"name":          "laptop_model",
+			"question_text": "Which laptop model do you need?",
+			"type":          "select_box",
+			"mandatory":     true,
+			"order":         100,
+		},
+	},
+	"add_group_members": {
+		{"group_id": "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "user_ids": []interface{}{"b1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6", "c1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6"}},
+	},
+}
+
+// withExamples returns tool with its registered example payloads in the input schema
+func withExamples(tool mcp.Tool) mcp.Tool {
+	if examples, ok := toolExamples[tool.Name]; ok {
+		tool.InputSchema.Examples = examples
+	}
+	return tool
+}
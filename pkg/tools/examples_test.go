@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestToolExamples tests that every example names a registered tool and is valid against its input schema
+func TestToolExamples(t *testing.T) {
+	_, server := newTestRegistry(t, "https://example.service-now.com", false)
+	tools := map[string]mcp.Tool{}
+	for _, tool := range server.ListTools() {
+		tools[tool.Name] = tool
+	}
+
+	for name, examples := range toolExamples {
+		tool, ok := tools[name]
+		if !ok {
+			t.Errorf("Examples registered for unknown tool %s", name)
+			continue
+		}
+		if len(tool.InputSchema.Examples) != len(examples) {
+			t.Errorf("Expected %s to publish %d examples, got %d", name, len(examples), len(tool.InputSchema.Examples))
+		}
+		for i, example := range examples {
+			args := map[string]interface{}{}
+			for key, value := range example {
+				prop, ok := tool.InputSchema.Properties[key]
+				if !ok {
+					t.Errorf("%s example %d: unknown argument %q", name, i+1, key)
+				}
+				if s, isString := value.(string); isString && len(prop.Enum) > 0 && !slices.Contains(prop.Enum, s) {
+					t.Errorf("%s example %d: %s must be one of %v, got %q", name, i+1, key, prop.Enum, s)
+				}
+				args[key] = value
+			}
+			for _, required := range tool.InputSchema.Required {
+				if _, ok := args[required]; !ok {
+					t.Errorf("%s example %d: missing required argument %q", name, i+1, required)
+				}
+			}
+			if err := coerceArgs(tool.InputSchema, args); err != nil {
+				t.Errorf("%s example %d: %v", name, i+1, err)
+			} else if err := validateArgs(tool.InputSchema, args); err != nil {
+				t.Errorf("%s example %d: %v", name, i+1, err)
+			}
+		}
+	}
+}
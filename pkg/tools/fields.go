@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// DefaultMaxResponseBytes bounds the size of a tool result before its records are truncated
+// (MCP_MAX_RESPONSE_BYTES)
+const DefaultMaxResponseBytes = 100000
+
+// withFieldSelection adds the fields argument to the properties of a list or get tool
+// whose handler reads through readFields and builds its records with selectFields
+func withFieldSelection(properties map[string]mcp.Property) map[string]mcp.Property {
+	properties["fields"] = mcp.Property{
+		Type:        "array",
+		Description: "Fields to return for each record (e.g., [\"number\", \"short_description\", \"state\"]). Fields not returned by default are read from the record. Omit for the compact default set.",
+		Items:       &mcp.Property{Type: "string"},
+	}
+	return properties
+}
+
+// readFields returns the sysparm_fields of a read: the compact default fields the tool
+// builds its records from, and the fields requested with the fields argument
+func readFields(args map[string]interface{}, defaults string) string {
+	fields := GetStringArrayArg(args, "fields")
+	if len(fields) == 0 {
+		return defaults
+	}
+	return defaults + "," + strings.Join(fields, ",")
+}
+
+// selectFields narrows a record built from raw to the fields requested with the fields
+// argument, copying requested fields it lacks from raw. sys_id is always kept. Without
+// the fields argument the record is returned as built.
+func selectFields(record, raw map[string]interface{}, args map[string]interface{}) map[string]interface{} {
+	fields := GetStringArrayArg(args, "fields")
+	if len(fields) == 0 {
+		return record
+	}
+	selected := map[string]interface{}{}
+	if sysID, ok := record["sys_id"]; ok {
+		selected["sys_id"] = sysID
+	}
+	for _, field := range fields {
+		if value, ok := record[field]; ok {
+			selected[field] = value
+		} else if value, ok := raw[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected
+}
+
+// selectRecordFields narrows records returned as read from the Table API to the fields
+// requested with the fields argument
+func selectRecordFields(records []map[string]interface{}, args map[string]interface{}) []map[string]interface{} {
+	for i, record := range records {
+		records[i] = selectFields(record, record, args)
+	}
+	return records
+}
+
+// fieldsValidator checks the field names given to the fields argument of list and get tools
+func fieldsValidator(call *ToolCall) error {
+	if property, ok := call.Tool.InputSchema.Properties["fields"]; !ok || property.Type != "array" {
+		return nil
+	}
+	for _, field := range GetStringArrayArg(call.Args, "fields") {
+		if !fieldNamePattern.MatchString(field) {
+			return fmt.Errorf("invalid field name: %s", field)
+		}
+	}
+	return nil
+}
+
+// SetResponseLimits bounds the size in bytes of tool results. defaultMax applies to every
+// tool (0 means no limit); overrides are "tool=bytes" entries (e.g., "query_table=500000")
+// for individual tools. Larger results have their records truncated, with a warning.
+func (r *Registry) SetResponseLimits(defaultMax int, overrides []string) error {
+	if defaultMax < 0 {
+		return fmt.Errorf("response limit must not be negative, got %d", defaultMax)
+	}
+	perTool := map[string]int{}
+	for _, entry := range overrides {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid response limit %q (use tool=bytes, e.g., query_table=500000)", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			return fmt.Errorf("invalid byte count for %s: %q", strings.TrimSpace(name), value)
+		}
+		perTool[strings.TrimSpace(name)] = limit
+	}
+	r.maxResponseBytes, r.maxResponseBytesPerTool = defaultMax, perTool
+	return nil
+}
+
+// responseLimitFor returns the most bytes a result of the named tool may hold (0 means no limit)
+func (r *Registry) responseLimitFor(name string) int {
+	if limit, ok := r.maxResponseBytesPerTool[name]; ok {
+		return limit
+	}
+	return r.maxResponseBytes
+}
+
+// responseSizeTransformer truncates results larger than the tool's response limit and
+// appends a warning saying what was left out
+func (r *Registry) responseSizeTransformer(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult {
+	limit := r.responseLimitFor(call.Tool.Name)
+	if limit <= 0 || len(result.Content) == 0 || len(result.Content[0].Text) <= limit {
+		return result
+	}
+
+	text, warning := truncateResponse(result.Content[0].Text, limit)
+	result.Content[0].Text = text
+	result.Content = append(result.Content, mcp.ContentItem{Type: "text", Text: warning})
+	if r.logger != nil {
+		r.logger.Warn("Truncated %s result to %d bytes", call.Tool.Name, limit)
+	}
+	return result
+}
+
+// truncateResponse shortens a JSON result to at most limit bytes by dropping records from
+// the end of its longest list, marking it "truncated". Results without a list to shorten
+// are cut at limit bytes.
+func truncateResponse(text string, limit int) (string, string) {
+	var response map[string]interface{}
+	var key string
+	var records []interface{}
+	if err := json.Unmarshal([]byte(text), &response); err == nil {
+		for k, v := range response {
+			if list, ok := v.([]interface{}); ok && len(list) > len(records) {
+				key, records = k, list
+			}
+		}
+	}
+
+	if len(records) > 0 {
+		response["truncated"] = true
+		encode := func(n int) string {
+			response[key] = records[:n]
+			encoded, _ := json.MarshalIndent(response, "", "  ")
+			return string(encoded)
+		}
+		// Keep the most records that fit
+		low, high := 0, len(records)
+		for low < high {
+			mid := (low + high + 1) / 2
+			if len(encode(mid)) <= limit {
+				low = mid
+			} else {
+				high = mid - 1
+			}
+		}
+		if truncated := encode(low); len(truncated) <= limit {
+			return truncated, fmt.Sprintf("Warning: result exceeded %d bytes; returned %d of %d %s. Request fewer fields or a smaller limit, or continue with offset.", limit, low, len(records), key)
+		}
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut], fmt.Sprintf("Warning: result exceeded %d bytes and was cut off. Request fewer fields or a smaller limit.", limit)
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestListIncidentsFields tests that fields reads the requested fields and narrows the records to them
+func TestListIncidentsFields(t *testing.T) {
+	var readFields string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/now/table/incident" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		readFields = r.URL.Query().Get("sysparm_fields")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": [{"sys_id": "a1", "number": "INC0010001", "short_description": "VPN down", "priority": "2 - High", "impact": "1 - High"}]}`))
+	}))
+	defer ts.Close()
+
+	_, server := newTestRegistry(t, ts.URL, true)
+	listIncidents, _ := server.Handler("list_incidents")
+
+	result, _ := listIncidents(context.Background(), map[string]interface{}{"fields": []interface{}{"number", "impact"}})
+	var response struct {
+		Incidents []map[string]interface{} `json:"incidents"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if readFields != incidentFields+",number,impact" {
+		t.Errorf("Expected the default fields and the requested fields to be read, got %q", readFields)
+	}
+	if len(response.Incidents) != 1 || len(response.Incidents[0]) != 3 || response.Incidents[0]["impact"] != "1 - High" || response.Incidents[0]["number"] != "INC0010001" {
+		t.Errorf("Expected sys_id, number, and impact only, got %+v", response.Incidents)
+	}
+
+	result, _ = listIncidents(context.Background(), map[string]interface{}{"fields": []interface{}{"number,work_notes"}})
+	if !strings.Contains(result.Content[0].Text, "invalid field name") {
+		t.Errorf("Expected an invalid field name to be rejected, got %s", result.Content[0].Text)
+	}
+
+	_, _ = listIncidents(context.Background(), map[string]interface{}{})
+	if readFields != incidentFields {
+		t.Errorf("Expected the compact default fields without fields, got %q", readFields)
+	}
+}
+
+// TestResponseSizeLimit tests that results over the response limit drop records with a warning
+func TestResponseSizeLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		records := []map[string]interface{}{}
+		for i := 0; i < 50; i++ {
+			records = append(records, map[string]interface{}{"sys_id": fmt.Sprintf("%032d", i), "short_description": strings.Repeat("x", 100)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, server := newTestRegistry(t, ts.URL, true)
+	if err := registry.SetResponseLimits(0, []string{"query_table=2000"}); err != nil {
+		t.Fatalf("SetResponseLimits failed: %v", err)
+	}
+	queryTable, _ := server.Handler("query_table")
+
+	result, _ := queryTable(context.Background(), map[string]interface{}{"table": "cmdb_ci"})
+	var response struct {
+		Records   []map[string]interface{} `json:"records"`
+		Truncated bool                     `json:"truncated"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Expected truncated JSON, got %v", err)
+	}
+	if len(result.Content[0].Text) > 2000 || !response.Truncated || len(response.Records) == 0 || len(response.Records) >= 50 {
+		t.Errorf("Expected a truncated result within 2000 bytes, got %d bytes and %d records", len(result.Content[0].Text), len(response.Records))
+	}
+	if len(result.Content) < 2 || !strings.Contains(result.Content[1].Text, fmt.Sprintf("returned %d of 50 records", len(response.Records))) {
+		t.Errorf("Expected a truncation warning, got %+v", result.Content[1:])
+	}
+
+	if err := registry.SetResponseLimits(0, []string{"query_table"}); err == nil {
+		t.Error("Expected an entry without a byte count to be rejected")
+	}
+}
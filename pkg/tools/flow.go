@@ -0,0 +1,425 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// flowExecutionStates maps the list_flow_executions state filter to sys_flow_context states
+var flowExecutionStates = map[string]string{
+	"queued":      "QUEUED",
+	"in_progress": "IN_PROGRESS",
+	"waiting":     "WAITING",
+	"complete":    "COMPLETE",
+	"cancelled":   "CANCELLED",
+	"error":       "ERROR",
+}
+
+// flowExecutionStateNames lists the execution state filters in schema order
+var flowExecutionStateNames = []string{"queued", "in_progress", "waiting", "complete", "cancelled", "error"}
+
+// flowFields are the fields of the record returned by get_flow
+const flowFields = "sys_id,name,internal_name,description,type,status,active,run_as,access,sys_scope,sys_created_by,sys_updated_on"
+
+// flowExecutionFields are the fields of the executions returned by list_flow_executions
+const flowExecutionFields = "sys_id,name,flow,state,source_table,source_record,error_message,run_time,sys_created_on,sys_updated_on"
+
+// flowStepTables are the tables holding the steps of a flow: actions, flow logic, and subflow calls
+var flowStepTables = []struct{ kind, table, field string }{
+	{"action", "sys_hub_action_instance", "action_type"},
+	{"flow_logic", "sys_hub_flow_logic", "logic_definition"},
+	{"subflow", "sys_hub_sub_flow_instance", "subflow"},
+}
+
+// registerFlowTools registers the Flow Designer inspection tools
+func (r *Registry) registerFlowTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+	daysMin := float64(1)
+	daysMax := float64(90)
+
+	// List Flows (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_flows",
+		Description: "List Flow Designer flows and subflows (sys_hub_flow) with their status and active state. Most modern automation is built in Flow Designer rather than classic workflows (list_workflows).",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"query": {
+					Type:        "string",
+					Description: "Search text in the flow name or description",
+				},
+				"type": {
+					Type:        "string",
+					Description: "Only flows or only subflows",
+					Enum:        []string{"flow", "subflow"},
+				},
+				"active": {
+					Type:        "boolean",
+					Description: "Filter by active status (true = only active flows, false = only inactive)",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Flows",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listFlows)
+	count++
+
+	// Get Flow (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_flow",
+		Description: "Get a Flow Designer flow: its trigger, its steps in order (actions, flow logic, and subflow calls), and how its executions of the last N days ended, by state.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"flow_id": {
+					Type:        "string",
+					Description: "Flow sys_id, name, or internal name",
+				},
+				"days": {
+					Type:        "integer",
+					Description: "Days of executions to summarize",
+					Default:     7,
+					Minimum:     &daysMin,
+					Maximum:     &daysMax,
+				},
+			}),
+			Required: []string{"flow_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Flow",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getFlow)
+	count++
+
+	// List Flow Executions (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_flow_executions",
+		Description: "List Flow Designer executions (sys_flow_context), newest first, with their state, the record that triggered them, run time, and error message. Filter by flow, state (e.g., 'error' to find failures), or triggering record.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"flow_id": {
+					Type:        "string",
+					Description: "Only executions of this flow (sys_id, name, or internal name)",
+				},
+				"state": {
+					Type:        "string",
+					Description: "Only executions in this state",
+					Enum:        flowExecutionStateNames,
+				},
+				"source_record": {
+					Type:        "string",
+					Description: "Only executions triggered by this record (sys_id)",
+				},
+				"days": {
+					Type:        "integer",
+					Description: "Only executions started in the last N days",
+					Default:     7,
+					Minimum:     &daysMin,
+					Maximum:     &daysMax,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Flow Executions",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listFlowExecutions)
+	count++
+
+	return count
+}
+
+func (r *Registry) listFlows(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := map[string]string{
+		"sysparm_fields":                 readFields(args, "sys_id,name,internal_name,description,type,status,active"),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 50)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	var filters []string
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
+	}
+	if flowType := GetStringArg(args, "type", ""); flowType != "" {
+		filters = append(filters, "type="+SanitizeQueryValue(flowType))
+	}
+	if query := GetStringArg(args, "query", ""); query != "" {
+		filters = append(filters, LikeFilter(query, "name", "description"))
+	}
+	params["sysparm_query"] = strings.Join(append(filters, "ORDERBYname"), "^")
+
+	result, page, err := r.listRecords("/table/sys_hub_flow", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list flows", err)), nil
+	}
+
+	flows := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		flows = append(flows, selectFields(map[string]interface{}{
+			"sys_id":        data["sys_id"],
+			"name":          data["name"],
+			"internal_name": data["internal_name"],
+			"description":   data["description"],
+			"type":          data["type"],
+			"status":        data["status"],
+			"active":        data["active"],
+		}, data, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d flows", len(flows)),
+		"flows":   flows,
+	}, page)), nil
+}
+
+func (r *Registry) getFlow(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	flowID := GetStringArg(args, "flow_id", "")
+	if flowID == "" {
+		return JSONResult(NewErrorResponse("flow_id is required", nil)), nil
+	}
+	days := GetIntArg(args, "days", 7)
+
+	result, err := r.client.Get("/table/sys_hub_flow", map[string]string{
+		"sysparm_query":                  flowIDQuery(flowID),
+		"sysparm_fields":                 readFields(args, flowFields),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get flow", err)), nil
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Flow not found: %s", flowID))), nil
+	}
+	flow := records[0]
+	sysID := FieldValue(flow["sys_id"])
+
+	// Details failing to load are reported under warnings, so the flow is still returned
+	var warnings []string
+	read := func(name, table, query, fields string) []map[string]interface{} {
+		result, err := r.client.Get("/table/"+table, map[string]string{
+			"sysparm_query":                  query,
+			"sysparm_fields":                 fields,
+			"sysparm_display_value":          "true",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  "500",
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s not read: %v", name, err))
+			return nil
+		}
+		return GetResultList(result)
+	}
+
+	var trigger map[string]interface{}
+	if triggers := read("Trigger", "sys_hub_trigger_instance", "flow="+sysID, "trigger_definition,trigger_type"); len(triggers) > 0 {
+		trigger = map[string]interface{}{
+			"name": FieldDisplay(triggers[0]["trigger_definition"]),
+			"type": FieldDisplay(triggers[0]["trigger_type"]),
+		}
+	}
+
+	steps := []map[string]interface{}{}
+	for _, source := range flowStepTables {
+		for _, record := range read(source.kind, source.table, "flow="+sysID, "sys_id,order,comment,"+source.field) {
+			step := map[string]interface{}{
+				"order": FieldDisplay(record["order"]),
+				"kind":  source.kind,
+				"name":  FieldDisplay(record[source.field]),
+			}
+			if comment := FieldDisplay(record["comment"]); comment != "" {
+				step["comment"] = comment
+			}
+			steps = append(steps, step)
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return flowStepOrderLess(steps[i]["order"].(string), steps[j]["order"].(string))
+	})
+
+	// Executions in the last days, counted by state
+	executions := map[string]int{}
+	stats, err := r.client.Get("/stats/sys_flow_context", map[string]string{
+		"sysparm_query":    fmt.Sprintf("flow=%s^sys_created_on>=javascript:gs.daysAgoStart(%d)", sysID, days),
+		"sysparm_count":    "true",
+		"sysparm_group_by": "state",
+	})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Executions not counted: %v", err))
+	}
+	for _, group := range GetResultList(stats) {
+		count, _ := statsCount(map[string]interface{}{"result": group})
+		if fields, ok := group["groupby_fields"].([]interface{}); ok && len(fields) > 0 {
+			if field, ok := fields[0].(map[string]interface{}); ok {
+				executions[strings.ToLower(fmt.Sprint(field["value"]))] += count
+			}
+		}
+	}
+
+	response := map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Flow %s has %d steps", FieldDisplay(flow["name"]), len(steps)),
+		"flow":       selectFields(flow, flow, args),
+		"trigger":    trigger,
+		"steps":      steps,
+		"executions": map[string]interface{}{"days": days, "by_state": executions},
+	}
+	withWarnings(response, warnings...)
+	return JSONResult(response), nil
+}
+
+func (r *Registry) listFlowExecutions(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	days := GetIntArg(args, "days", 7)
+	filters := []string{fmt.Sprintf("sys_created_on>=javascript:gs.daysAgoStart(%d)", days)}
+
+	if flowID := GetStringArg(args, "flow_id", ""); flowID != "" {
+		sysID, err := r.resolveFlowID(flowID)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to find flow", err)), nil
+		}
+		filters = append(filters, "flow="+sysID)
+	}
+	if state := GetStringArg(args, "state", ""); state != "" {
+		value, ok := flowExecutionStates[state]
+		if !ok {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid state: %s (use one of %s)", state, strings.Join(flowExecutionStateNames, ", ")), nil)), nil
+		}
+		filters = append(filters, "state="+value)
+	}
+	if source := GetStringArg(args, "source_record", ""); source != "" {
+		if !IsSysID(source) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid source_record: %s (expected a sys_id)", source), nil)), nil
+		}
+		filters = append(filters, "source_record="+source)
+	}
+
+	params := map[string]string{
+		"sysparm_query":                  strings.Join(append(filters, "ORDERBYDESCsys_created_on"), "^"),
+		"sysparm_fields":                 readFields(args, flowExecutionFields),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	result, page, err := r.listRecords("/table/sys_flow_context", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list flow executions", err)), nil
+	}
+
+	executions := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		execution := map[string]interface{}{
+			"sys_id":        data["sys_id"],
+			"name":          data["name"],
+			"flow":          data["flow"],
+			"state":         data["state"],
+			"source_table":  data["source_table"],
+			"source_record": data["source_record"],
+			"run_time":      data["run_time"],
+			"started_on":    data["sys_created_on"],
+			"updated_on":    data["sys_updated_on"],
+		}
+		if message := FieldDisplay(data["error_message"]); message != "" {
+			execution["error_message"] = message
+		}
+		executions = append(executions, selectFields(execution, data, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Found %d flow executions", len(executions)),
+		"executions": executions,
+	}, page)), nil
+}
+
+// flowIDQuery returns the encoded query matching a flow by sys_id, or by name or internal name
+func flowIDQuery(flowID string) string {
+	if IsSysID(flowID) {
+		return "sys_id=" + flowID
+	}
+	value := SanitizeQueryValue(flowID)
+	return fmt.Sprintf("name=%s^ORinternal_name=%s", value, value)
+}
+
+// resolveFlowID resolves a flow name or internal name to sys_id
+func (r *Registry) resolveFlowID(flowID string) (string, error) {
+	if IsSysID(flowID) {
+		return flowID, nil
+	}
+	result, err := r.client.Get("/table/sys_hub_flow", map[string]string{
+		"sysparm_query":  flowIDQuery(flowID),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return "", err
+	}
+	if records := GetResultList(result); len(records) > 0 {
+		return FieldValue(records[0]["sys_id"]), nil
+	}
+	return "", fmt.Errorf("flow %w: %s", ErrNotFound, flowID)
+}
+
+// flowStepOrderLess orders flow steps by their order, numerically per dotted
+// component so nested steps (e.g., "2.1") follow their parent and "10" follows "9"
+func flowStepOrderLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr != nil || bErr != nil {
+			if as[i] != bs[i] {
+				return as[i] < bs[i]
+			}
+			continue
+		}
+		if an != bn {
+			return an < bn
+		}
+	}
+	return len(as) < len(bs)
+}
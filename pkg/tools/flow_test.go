@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetFlow tests that a flow's steps are merged across step tables in run order and its executions counted by state
+func TestGetFlow(t *testing.T) {
+	const flowID = "f0000000000000000000000000000001"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_hub_flow":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + flowID + `", "name": "Notify on P1", "internal_name": "notify_on_p1", "active": "true"}]}`))
+		case "/api/now/table/sys_hub_trigger_instance":
+			_, _ = w.Write([]byte(`{"result": [{"trigger_definition": "Created", "trigger_type": "record_create"}]}`))
+		case "/api/now/table/sys_hub_action_instance":
+			_, _ = w.Write([]byte(`{"result": [{"order": "10", "action_type": "Send Email"}, {"order": "2.1", "action_type": "Update Record"}]}`))
+		case "/api/now/table/sys_hub_flow_logic":
+			_, _ = w.Write([]byte(`{"result": [{"order": "2", "logic_definition": "If"}]}`))
+		case "/api/now/table/sys_hub_sub_flow_instance":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"message": "Insufficient rights"}}`))
+		case "/api/now/stats/sys_flow_context":
+			if r.URL.Query().Get("sysparm_group_by") != "state" {
+				t.Errorf("Expected executions grouped by state, got %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"result": [
+				{"stats": {"count": "5"}, "groupby_fields": [{"field": "state", "value": "COMPLETE"}]},
+				{"stats": {"count": "2"}, "groupby_fields": [{"field": "state", "value": "ERROR"}]}
+			]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.getFlow(map[string]interface{}{"flow_id": "notify_on_p1"})
+	var response struct {
+		Success bool `json:"success"`
+		Trigger struct {
+			Name string `json:"name"`
+		} `json:"trigger"`
+		Steps []struct {
+			Order string `json:"order"`
+			Kind  string `json:"kind"`
+			Name  string `json:"name"`
+		} `json:"steps"`
+		Executions struct {
+			ByState map[string]int `json:"by_state"`
+		} `json:"executions"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || response.Trigger.Name != "Created" {
+		t.Fatalf("Expected the flow with its trigger, got %s", result.Content[0].Text)
+	}
+	var steps []string
+	for _, step := range response.Steps {
+		steps = append(steps, step.Order+" "+step.Name)
+	}
+	if strings.Join(steps, ", ") != "2 If, 2.1 Update Record, 10 Send Email" {
+		t.Errorf("Expected steps in run order, got %v", steps)
+	}
+	if response.Executions.ByState["complete"] != 5 || response.Executions.ByState["error"] != 2 {
+		t.Errorf("Expected executions counted by state, got %v", response.Executions.ByState)
+	}
+	if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "subflow") {
+		t.Errorf("Expected a warning for the unreadable subflow calls, got %v", response.Warnings)
+	}
+}
+
+// TestListFlowExecutions tests that executions are filtered by the resolved flow and state
+func TestListFlowExecutions(t *testing.T) {
+	const flowID = "f0000000000000000000000000000001"
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_hub_flow":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + flowID + `"}]}`))
+		case "/api/now/table/sys_flow_context":
+			query = r.URL.Query().Get("sysparm_query")
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "c1", "name": "Notify on P1", "state": "ERROR", "error_message": "Email recipient not found"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.listFlowExecutions(map[string]interface{}{"flow_id": "Notify on P1", "state": "error"})
+	if !strings.Contains(query, "flow="+flowID) || !strings.Contains(query, "state=ERROR") || !strings.HasSuffix(query, "ORDERBYDESCsys_created_on") {
+		t.Errorf("Expected executions of the flow in error state, newest first, got %s", query)
+	}
+	if !strings.Contains(result.Content[0].Text, "Email recipient not found") {
+		t.Errorf("Expected the error message, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.listFlowExecutions(map[string]interface{}{"state": "failed"})
+	if !strings.Contains(result.Content[0].Text, "Invalid state") {
+		t.Errorf("Expected an unknown state to be rejected, got %s", result.Content[0].Text)
+	}
+}
@@ -2,10 +2,27 @@ package tools
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
 )
 
+// ServiceNow date and date/time formats (date/times are UTC when read as raw values)
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// shortDescriptionMaxLength is the length of the task short_description field
+const shortDescriptionMaxLength = 160
+
 // TextResult creates a successful text result
 func TextResult(content string) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
@@ -26,17 +43,14 @@ func JSONResult(data interface{}) *mcp.CallToolResult {
 	}
 }
 
-// ErrorResult creates an error result
+// ErrorResult creates an error result for a failure outside the caller's control
 func ErrorResult(message string) *mcp.CallToolResult {
-	return &mcp.CallToolResult{
-		Content: []mcp.ContentItem{{Type: "text", Text: message}},
-		IsError: true,
-	}
+	return mcp.ToolError(mcp.ErrorUpstreamError, message)
 }
 
 // WriteBlockedResult returns a result for blocked write operations
 func WriteBlockedResult() *mcp.CallToolResult {
-	return ErrorResult("This operation is blocked in read-only mode. Set READ_ONLY_MODE=false to enable write operations.")
+	return mcp.ToolError(mcp.ErrorPermissionDenied, "This operation is blocked in read-only mode. Set READ_ONLY_MODE=false to enable write operations.")
 }
 
 // GetStringArg extracts a string argument with default value
@@ -47,25 +61,165 @@ func GetStringArg(args map[string]interface{}, key, defaultValue string) string
 	return defaultValue
 }
 
-// GetIntArg extracts an integer argument with default value
+// GetIntArg extracts an integer argument with default value. Numeric strings
+// (e.g., "20") are accepted; values that are not numbers return the default.
 func GetIntArg(args map[string]interface{}, key string, defaultValue int) int {
-	if val, ok := args[key].(float64); ok {
+	if val, ok := CoerceNumber(args[key]); ok {
 		return int(val)
 	}
-	if val, ok := args[key].(int); ok {
-		return val
-	}
 	return defaultValue
 }
 
-// GetBoolArg extracts a boolean argument with default value
+// GetBoolArg extracts a boolean argument with default value. The strings
+// "true"/"false" (and yes/no, 1/0) and the numbers 1/0 are accepted; other
+// values return the default.
 func GetBoolArg(args map[string]interface{}, key string, defaultValue bool) bool {
-	if val, ok := args[key].(bool); ok {
+	if val, ok := CoerceBool(args[key]); ok {
 		return val
 	}
 	return defaultValue
 }
 
+// CoerceBool converts the boolean forms LLMs commonly produce to a bool
+func CoerceBool(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "y", "1":
+			return true, true
+		case "false", "no", "n", "0":
+			return false, true
+		}
+	case float64, int, int64, json.Number:
+		if n, ok := CoerceNumber(v); ok && (n == 0 || n == 1) {
+			return n == 1, true
+		}
+	}
+	return false, false
+}
+
+// CoerceNumber converts a JSON number, Go integer, or numeric string to a float64
+func CoerceNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil && !math.IsNaN(n) && !math.IsInf(n, 0)
+	}
+	return 0, false
+}
+
+// coerceArgs normalizes arguments in place to the types declared in the tool's
+// input schema: booleans and numbers sent as strings become bool/float64, and
+// numbers or booleans sent for string properties become strings. Values that
+// cannot be converted are reported as a validation error.
+func coerceArgs(schema mcp.JSONSchema, args map[string]interface{}) error {
+	for name, value := range args {
+		prop, ok := schema.Properties[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		switch prop.Type {
+		case "boolean":
+			b, ok := CoerceBool(value)
+			if !ok {
+				return fmt.Errorf("argument %q must be a boolean (true or false), got %v", name, value)
+			}
+			args[name] = b
+		case "integer", "number":
+			n, ok := CoerceNumber(value)
+			if !ok {
+				return fmt.Errorf("argument %q must be a number, got %v", name, value)
+			}
+			if prop.Type == "integer" && n != math.Trunc(n) {
+				return fmt.Errorf("argument %q must be a whole number, got %v", name, value)
+			}
+			args[name] = n
+		case "string":
+			switch v := value.(type) {
+			case float64:
+				args[name] = strconv.FormatFloat(v, 'f', -1, 64)
+			case int:
+				args[name] = strconv.Itoa(v)
+			case bool:
+				args[name] = strconv.FormatBool(v)
+			case json.Number:
+				args[name] = v.String()
+			}
+		}
+	}
+	return nil
+}
+
+// validateArgs checks coerced arguments against the constraints declared in the tool's
+// input schema: minimum/maximum for numbers, and maxLength, pattern, and format for
+// strings. RFC 3339 date-times are accepted for "date-time" properties and normalized
+// in place to the ServiceNow format in UTC.
+func validateArgs(schema mcp.JSONSchema, args map[string]interface{}) error {
+	for name, value := range args {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			continue
+		}
+
+		switch v := value.(type) {
+		case float64:
+			if prop.Minimum != nil && v < *prop.Minimum {
+				return fmt.Errorf("argument %q must be at least %v, got %v", name, *prop.Minimum, v)
+			}
+			if prop.Maximum != nil && v > *prop.Maximum {
+				return fmt.Errorf("argument %q must be at most %v, got %v", name, *prop.Maximum, v)
+			}
+		case string:
+			if prop.MaxLength > 0 && utf8.RuneCountInString(v) > prop.MaxLength {
+				return fmt.Errorf("argument %q must be at most %d characters, got %d", name, prop.MaxLength, utf8.RuneCountInString(v))
+			}
+			if prop.Pattern != "" {
+				matched, err := regexp.MatchString(prop.Pattern, v)
+				if err != nil {
+					return fmt.Errorf("argument %q has an invalid pattern in the tool schema: %w", name, err)
+				}
+				if !matched {
+					return fmt.Errorf("argument %q must match %s, got %q", name, prop.Pattern, v)
+				}
+			}
+			// Empty strings leave optional date fields unset
+			if v == "" {
+				continue
+			}
+			switch prop.Format {
+			case "date":
+				if _, err := time.Parse(dateLayout, v); err != nil {
+					return fmt.Errorf("argument %q must be a date (YYYY-MM-DD), got %q", name, v)
+				}
+			case "date-time":
+				if _, err := time.Parse(dateTimeLayout, v); err == nil {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					return fmt.Errorf("argument %q must be a date/time (YYYY-MM-DD HH:MM:SS), got %q", name, v)
+				}
+				args[name] = t.UTC().Format(dateTimeLayout)
+			}
+		}
+	}
+	return nil
+}
+
 // GetStringArrayArg extracts a string array argument
 func GetStringArrayArg(args map[string]interface{}, key string) []string {
 	if val, ok := args[key].([]interface{}); ok {
@@ -88,6 +242,63 @@ func GetMapArg(args map[string]interface{}, key string) map[string]interface{} {
 	return nil
 }
 
+// GetResultList extracts the list of records from a Table API response
+func GetResultList(result map[string]interface{}) []map[string]interface{} {
+	records := []map[string]interface{}{}
+	if resultList, ok := result["result"].([]interface{}); ok {
+		for _, item := range resultList {
+			if data, ok := item.(map[string]interface{}); ok {
+				records = append(records, data)
+			}
+		}
+	}
+	return records
+}
+
+// FieldValue returns the raw value of a field returned with sysparm_display_value=all
+// (a {"value","display_value"} object), or the field itself when it is a plain string
+func FieldValue(field interface{}) string {
+	if m, ok := field.(map[string]interface{}); ok {
+		v, _ := m["value"].(string)
+		return v
+	}
+	v, _ := field.(string)
+	return v
+}
+
+// FieldDisplay returns the display value of a field returned with sysparm_display_value=all,
+// or the field itself when it is a plain string
+func FieldDisplay(field interface{}) string {
+	if m, ok := field.(map[string]interface{}); ok {
+		v, _ := m["display_value"].(string)
+		return v
+	}
+	v, _ := field.(string)
+	return v
+}
+
+// SanitizeQueryValue removes encoded query separators from a user-supplied value so it
+// cannot terminate the current condition and inject additional terms (e.g., ^ORDERBY or ^NQ)
+func SanitizeQueryValue(value string) string {
+	return strings.Map(func(c rune) rune {
+		switch c {
+		case '^', '\n', '\r':
+			return -1
+		}
+		return c
+	}, value)
+}
+
+// LikeFilter builds an encoded query matching value as a substring of any of the given fields
+func LikeFilter(value string, fields ...string) string {
+	value = SanitizeQueryValue(value)
+	terms := make([]string, len(fields))
+	for i, field := range fields {
+		terms[i] = field + "LIKE" + value
+	}
+	return strings.Join(terms, "^OR")
+}
+
 // IsSysID checks if a string looks like a ServiceNow sys_id
 func IsSysID(s string) bool {
 	if len(s) != 32 {
@@ -101,17 +312,69 @@ func IsSysID(s string) bool {
 	return true
 }
 
+// TouchedSysIDs returns the sys_ids a tool call referred to, for logging: sys_id
+// arguments, and the sys_id and *_id fields at the top level of a JSON result (e.g.,
+// the incident_id of a created incident)
+func TouchedSysIDs(args map[string]interface{}, result *mcp.CallToolResult) []string {
+	seen := map[string]bool{}
+	var sysIDs []string
+	add := func(value interface{}) {
+		if s, ok := value.(string); ok && IsSysID(s) && !seen[s] {
+			seen[s] = true
+			sysIDs = append(sysIDs, s)
+		}
+	}
+
+	for _, value := range args {
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				add(item)
+			}
+			continue
+		}
+		add(value)
+	}
+
+	if result != nil && !result.IsError && len(result.Content) > 0 {
+		var body map[string]interface{}
+		if json.Unmarshal([]byte(result.Content[0].Text), &body) == nil {
+			for key, value := range body {
+				if key == "sys_id" || strings.HasSuffix(key, "_id") {
+					add(value)
+				}
+			}
+		}
+	}
+	sort.Strings(sysIDs)
+	return sysIDs
+}
+
 // SuccessResponse creates a standard success response
 type SuccessResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success  bool     `json:"success"`
+	Message  string   `json:"message"`
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ErrorResponse creates a standard error response
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
+	Success  bool              `json:"success"`
+	Code     mcp.ToolErrorCode `json:"code"`
+	Message  string            `json:"message"`
+	Error    string            `json:"error,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// withWarnings adds warnings to the warnings field of a response envelope, after any
+// already there. Calls that partly fail list one warning per failure, naming the ID
+// and the reason, so no failure hides behind another.
+func withWarnings(response map[string]interface{}, warnings ...string) map[string]interface{} {
+	if len(warnings) == 0 {
+		return response
+	}
+	existing, _ := response["warnings"].([]string)
+	response["warnings"] = append(existing, warnings...)
+	return response
 }
 
 // NewSuccessResponse creates a new success response
@@ -122,10 +385,11 @@ func NewSuccessResponse(message string) *SuccessResponse {
 	}
 }
 
-// NewErrorResponse creates a new error response
+// NewErrorResponse creates a new error response, with its code classified from err
 func NewErrorResponse(message string, err error) *ErrorResponse {
 	resp := &ErrorResponse{
 		Success: false,
+		Code:    errorCode(err),
 		Message: message,
 	}
 	if err != nil {
@@ -133,3 +397,19 @@ func NewErrorResponse(message string, err error) *ErrorResponse {
 	}
 	return resp
 }
+
+// NewValidationErrorResponse creates an error response for arguments rejected with err
+func NewValidationErrorResponse(message string, err error) *ErrorResponse {
+	resp := NewErrorResponse(message, err)
+	resp.Code = mcp.ErrorValidationFailed
+	return resp
+}
+
+// NewNotFoundResponse creates an error response for a record that doesn't exist
+func NewNotFoundResponse(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Success: false,
+		Code:    mcp.ErrorNotFound,
+		Message: message,
+	}
+}
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// isHex reports whether every character of s is a hexadecimal digit
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// FuzzIsSysID checks IsSysID accepts exactly the 32-character hex strings
+func FuzzIsSysID(f *testing.F) {
+	f.Add("6816f79cc0a8016401c5a33be04be441")
+	f.Add("INC0010001")
+	f.Add("6816f79cc0a8016401c5a33be04be44g")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		want := len(s) == 32 && isHex(s)
+		if got := IsSysID(s); got != want {
+			t.Errorf("IsSysID(%q) = %v, want %v", s, got, want)
+		}
+	})
+}
+
+// FuzzLikeFilter checks user-supplied search text cannot add encoded query terms
+func FuzzLikeFilter(f *testing.F) {
+	f.Add("network")
+	f.Add("x^ORDERBYDESCsys_created_on")
+	f.Add("a^NQactive=false")
+	f.Add("line\nbreak^")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		sanitized := SanitizeQueryValue(value)
+		if strings.ContainsAny(sanitized, "^\n\r") {
+			t.Fatalf("SanitizeQueryValue(%q) = %q still contains separators", value, sanitized)
+		}
+		if SanitizeQueryValue(sanitized) != sanitized {
+			t.Fatalf("SanitizeQueryValue is not idempotent for %q", value)
+		}
+
+		query := LikeFilter(value, "short_description", "description")
+		if got := strings.Count(query, "^"); got != 1 {
+			t.Fatalf("LikeFilter(%q) = %q has %d separators, want 1", value, query, got)
+		}
+		terms := strings.Split(query, "^OR")
+		if len(terms) != 2 || terms[0] != "short_descriptionLIKE"+sanitized || terms[1] != "descriptionLIKE"+sanitized {
+			t.Fatalf("LikeFilter(%q) = %q, unexpected terms", value, query)
+		}
+	})
+}
+
+// FuzzArgHelpers checks argument extraction never panics on arbitrary JSON arguments
+func FuzzArgHelpers(f *testing.F) {
+	f.Add(`{"limit": 10, "active": true, "query": "x"}`)
+	f.Add(`{"limit": "10", "active": "true", "user_ids": ["a", 1, null]}`)
+	f.Add(`{"limit": null, "fields": {"a": 1}, "user_ids": "a,b"}`)
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &args); err != nil {
+			return
+		}
+		for key := range args {
+			_ = GetStringArg(args, key, "")
+			_ = GetIntArg(args, key, 0)
+			_ = GetBoolArg(args, key, false)
+			_ = GetMapArg(args, key)
+			_ = GetStringArrayArg(args, key)
+		}
+	})
+}
+
+// TestCoerceArgs tests that LLM-produced argument variants are normalized to the schema types
+func TestCoerceArgs(t *testing.T) {
+	schema := mcp.JSONSchema{
+		Type: "object",
+		Properties: map[string]mcp.Property{
+			"active": {Type: "boolean"},
+			"limit":  {Type: "integer"},
+			"score":  {Type: "number"},
+			"state":  {Type: "string"},
+		},
+	}
+
+	args := map[string]interface{}{"active": "True", "limit": "20", "score": 3, "state": float64(2), "extra": "kept"}
+	if err := coerceArgs(schema, args); err != nil {
+		t.Fatalf("Expected coercion to succeed, got %v", err)
+	}
+	want := map[string]interface{}{"active": true, "limit": float64(20), "score": float64(3), "state": "2", "extra": "kept"}
+	for key, value := range want {
+		if args[key] != value {
+			t.Errorf("Expected %s = %#v, got %#v", key, value, args[key])
+		}
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"active": "maybe"},
+		{"limit": "twenty"},
+		{"limit": 2.5},
+		{"active": float64(2)},
+	} {
+		if err := coerceArgs(schema, bad); err == nil {
+			t.Errorf("Expected validation error for %v", bad)
+		}
+	}
+}
+
+// TestValidateArgs tests that schema constraints are enforced and RFC 3339 date-times are normalized
+func TestValidateArgs(t *testing.T) {
+	limitMin := float64(1)
+	limitMax := float64(100)
+	schema := mcp.JSONSchema{
+		Type: "object",
+		Properties: map[string]mcp.Property{
+			"limit":             {Type: "integer", Minimum: &limitMin, Maximum: &limitMax},
+			"short_description": {Type: "string", MaxLength: 10},
+			"number":            {Type: "string", Pattern: `^INC\d{7}$`},
+			"due":               {Type: "string", Format: "date"},
+			"start":             {Type: "string", Format: "date-time"},
+		},
+	}
+
+	args := map[string]interface{}{
+		"limit": float64(100), "short_description": "Disk full", "number": "INC0010001",
+		"due": "2024-12-15", "start": "2024-12-15T14:30:00-05:00", "extra": float64(1000),
+	}
+	if err := validateArgs(schema, args); err != nil {
+		t.Fatalf("Expected valid arguments, got %v", err)
+	}
+	if args["start"] != "2024-12-15 19:30:00" {
+		t.Errorf("Expected the date-time in ServiceNow format in UTC, got %v", args["start"])
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"limit": float64(0)},
+		{"limit": float64(101)},
+		{"short_description": "Disk full on /var"},
+		{"number": "CHG0010001"},
+		{"due": "12/15/2024"},
+		{"start": "2024-12-15"},
+	} {
+		if err := validateArgs(schema, bad); err == nil {
+			t.Errorf("Expected validation error for %v", bad)
+		}
+	}
+}
+
+// TestTouchedSysIDs tests that sys_ids are collected from arguments and top-level result IDs
+func TestTouchedSysIDs(t *testing.T) {
+	const (
+		argID     = "11111111111111111111111111111111"
+		listID    = "22222222222222222222222222222222"
+		createdID = "33333333333333333333333333333333"
+	)
+	args := map[string]interface{}{"incident_id": argID, "ci_ids": []interface{}{listID, argID}, "state": "2"}
+	result := JSONResult(map[string]interface{}{
+		"success":     true,
+		"incident_id": createdID,
+		"records":     []interface{}{map[string]interface{}{"sys_id": "44444444444444444444444444444444"}},
+	})
+
+	got := strings.Join(TouchedSysIDs(args, result), ",")
+	if want := strings.Join([]string{argID, listID, createdID}, ","); got != want {
+		t.Errorf("Expected sys_ids %s, got %s", want, got)
+	}
+}
+
+// TestGetArgCoercion tests that the argument helpers accept string and integer variants
+func TestGetArgCoercion(t *testing.T) {
+	args := map[string]interface{}{"a": "false", "b": "yes", "n": "15", "i": 7, "bad": "x"}
+	if GetBoolArg(args, "a", true) || !GetBoolArg(args, "b", false) || !GetBoolArg(args, "bad", true) {
+		t.Error("Expected string booleans to be coerced and invalid values to fall back to the default")
+	}
+	if GetIntArg(args, "n", 0) != 15 || GetIntArg(args, "i", 0) != 7 || GetIntArg(args, "bad", 3) != 3 {
+		t.Error("Expected numeric strings and ints to be coerced and invalid values to fall back to the default")
+	}
+}
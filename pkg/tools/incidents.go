@@ -3,8 +3,16 @@ package tools
 import (
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// incidentFields are the fields list_incidents builds its records from, and
+// incidentDetailFields those of get_incident
+const (
+	incidentFields       = "sys_id,number,short_description,description,state,priority,category,subcategory,assigned_to,sys_created_on,sys_updated_on"
+	incidentDetailFields = incidentFields + ",impact,urgency"
 )
 
 // registerIncidentTools registers all incident management tools
@@ -17,12 +25,12 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 	offsetMin := float64(0)
 
 	// List Incidents (read-only)
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_incidents",
-		Description: "List incidents with optional filtering by state, assignee, category, or search query. Use the query parameter for advanced filtering with ServiceNow encoded query syntax.",
+		Description: "List incidents with optional filtering by state, assignee, category, or search query. Use the query parameter for free-text search.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withArchived(withFieldSelection(withAutoPaginate(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of incidents to return (default: 10)",
@@ -51,54 +59,79 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 				},
 				"query": {
 					Type:        "string",
-					Description: "Search query for incidents (searches short_description and description). For advanced filtering, use ServiceNow encoded query syntax (^ for AND, | for OR, e.g., 'priority=1^state=2')",
+					Description: "Text search in short_description and description (e.g., 'network outage')",
 				},
-			},
+			}))),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Incidents",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listIncidents(args)
-	})
+	}, (*Registry).listIncidents)
 	count++
 
 	// Get Incident by Number (read-only)
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_incident",
-		Description: "Get detailed information about a specific incident including all fields, timestamps, and related records.",
+		Description: "Get detailed information about a specific incident: description, state, priority, impact, urgency, category, assignee, and timestamps. Use fields to read other fields.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withArchived(withFieldSelection(map[string]mcp.Property{
 				"incident_id": {
 					Type:        "string",
 					Description: "Incident number (e.g., 'INC0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
 				},
-			},
+			})),
 			Required: []string{"incident_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Incident",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getIncident(args)
-	})
+	}, (*Registry).getIncident)
+	count++
+
+	// Compute Priority (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "compute_priority",
+		Description: "Compute incident priority from impact and urgency using the instance's priority lookup table (falls back to the default ServiceNow matrix).",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"impact": {
+					Type:        "string",
+					Description: "Business impact level (1=High, 2=Medium, 3=Low)",
+					Enum:        []string{"1", "2", "3"},
+				},
+				"urgency": {
+					Type:        "string",
+					Description: "Urgency level (1=High, 2=Medium, 3=Low)",
+					Enum:        []string{"1", "2", "3"},
+				},
+			},
+			Required: []string{"impact", "urgency"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:          "Compute Priority",
+			ReadOnlyHint:   true,
+			IdempotentHint: true,
+		},
+	}, (*Registry).computePriorityTool)
 	count++
 
 	// Write operations (only if not read-only mode)
 	if !r.readOnlyMode {
 		// Create Incident
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_incident",
-			Description: "Create a new incident. Returns the new incident number and sys_id upon successful creation.",
+			Description: "Create a new incident. When acting for an end user, set caller_id to the affected user and opened_by to the agent. Returns the new incident number and sys_id.",
 			InputSchema: mcp.JSONSchema{
 				Type: "object",
 				Properties: map[string]mcp.Property{
 					"short_description": {
 						Type:        "string",
-						Description: "Brief summary of the incident (required, max 160 characters recommended)",
+						Description: "Brief summary of the incident (required, max 160 characters)",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"description": {
 						Type:        "string",
@@ -106,7 +139,11 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 					},
 					"caller_id": {
 						Type:        "string",
-						Description: "User who reported the incident (sys_id, username, or email)",
+						Description: "User who reported or is affected by the incident (sys_id, username, or email)",
+					},
+					"opened_by": {
+						Type:        "string",
+						Description: "User logging the incident on the caller's behalf, e.g., a service desk agent (sys_id, username, or email). Defaults to the integration user.",
 					},
 					"category": {
 						Type:        "string",
@@ -139,6 +176,15 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 						Type:        "string",
 						Description: "Group to assign the incident to (sys_id or group name)",
 					},
+					"location": {
+						Type:        "string",
+						Description: "Location of the issue (cmn_location name or sys_id). Defaults to the caller's location.",
+					},
+					"location_defaults": {
+						Type:        "boolean",
+						Description: "Default location to the caller's, and assignment_group (when not given) to the group of the assignment rule for the location or the caller's department. Set false to leave them empty.",
+						Default:     true,
+					},
 				},
 				Required: []string{"short_description"},
 			},
@@ -146,13 +192,11 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 				Title:           "Create Incident",
 				DestructiveHint: false,
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createIncident(args)
-		})
+		}, (*Registry).createIncident)
 		count++
 
 		// Update Incident
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_incident",
 			Description: "Update an existing incident. At least one field besides incident_id must be provided to make changes.",
 			InputSchema: mcp.JSONSchema{
@@ -165,6 +209,7 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 					"short_description": {
 						Type:        "string",
 						Description: "Brief summary of the incident",
+						MaxLength:   shortDescriptionMaxLength,
 					},
 					"description": {
 						Type:        "string",
@@ -204,7 +249,7 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 					},
 					"work_notes": {
 						Type:        "string",
-						Description: "Internal work notes to add (visible only to support staff)",
+						Description: "Internal work notes to add (visible only to support staff). Mention users with @user_name or @[Full Name] to notify them.",
 					},
 				},
 				Required: []string{"incident_id"},
@@ -212,13 +257,11 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Incident",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateIncident(args)
-		})
+		}, (*Registry).updateIncident)
 		count++
 
 		// Add Comment
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "add_incident_comment",
 			Description: "Add a comment or work note to an incident. Comments are visible to the caller, work notes are internal only.",
 			InputSchema: mcp.JSONSchema{
@@ -230,7 +273,7 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 					},
 					"comment": {
 						Type:        "string",
-						Description: "Comment text to add to the incident",
+						Description: "Comment text to add to the incident. Mention users with @user_name or @[Full Name] to notify them.",
 					},
 					"is_work_note": {
 						Type:        "boolean",
@@ -243,13 +286,11 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Add Incident Comment",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.addIncidentComment(args)
-		})
+		}, (*Registry).addIncidentComment)
 		count++
 
 		// Resolve Incident
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "resolve_incident",
 			Description: "Resolve an incident by setting state to Resolved and providing resolution details. The incident can later be closed or reopened.",
 			InputSchema: mcp.JSONSchema{
@@ -273,9 +314,37 @@ func (r *Registry) registerIncidentTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Resolve Incident",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.resolveIncident(args)
-		})
+		}, (*Registry).resolveIncident)
+		count++
+
+		// Attach Transcript
+		r.registerTool(server, mcp.Tool{
+			Name:        "attach_transcript",
+			Description: "Save the agent conversation transcript on an incident as a .txt/.md attachment or as work notes, so the human agent picking up the ticket sees what was already tried.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"incident_id": {
+						Type:        "string",
+						Description: "Incident number (e.g., 'INC0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
+					},
+					"transcript_text": {
+						Type:        "string",
+						Description: "Conversation transcript (e.g., 'User: VPN drops every hour\nAgent: Checked client version...')",
+					},
+					"format": {
+						Type:        "string",
+						Description: "How to save: 'md' or 'txt' attachment, or 'work_notes' (split into numbered work notes)",
+						Default:     "md",
+						Enum:        []string{"md", "txt", "work_notes"},
+					},
+				},
+				Required: []string{"incident_id", "transcript_text"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Attach Transcript",
+			},
+		}, (*Registry).attachTranscript)
 		count++
 	}
 
@@ -293,29 +362,37 @@ func (r *Registry) listIncidents(args map[string]interface{}) (*mcp.CallToolResu
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
 		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_fields":                 readFields(args, incidentFields),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if state != "" {
-		filters = append(filters, fmt.Sprintf("state=%s", state))
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(state)))
 	}
 	if assignedTo != "" {
-		filters = append(filters, fmt.Sprintf("assigned_to=%s", assignedTo))
+		userID, err := r.resolveUser(assignedTo)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve assigned_to", err)), nil
+		}
+		filters = append(filters, fmt.Sprintf("assigned_to=%s", userID))
 	}
 	if category != "" {
-		filters = append(filters, fmt.Sprintf("category=%s", category))
+		filters = append(filters, fmt.Sprintf("category=%s", SanitizeQueryValue(category)))
 	}
 	if query != "" {
-		filters = append(filters, fmt.Sprintf("short_descriptionLIKE%s^ORdescriptionLIKE%s", query, query))
+		filters = append(filters, LikeFilter(query, "short_description", "description"))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
+	if errResp := archiveArgsError(args); errResp != nil {
+		return JSONResult(errResp), nil
+	}
 
-	result, err := r.client.Get("/table/incident", params)
+	result, page, warning, err := r.listRecordsWithArchive("incident", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list incidents", err)), nil
 	}
@@ -344,16 +421,25 @@ func (r *Registry) listIncidents(args map[string]interface{}) (*mcp.CallToolResu
 					incident["assigned_to"] = incidentData["assigned_to"]
 				}
 
+				incident = selectFields(incident, incidentData, args)
+				if incidentData["archived"] == true {
+					incident["archived"] = true
+				}
 				incidents = append(incidents, incident)
 			}
 		}
 	}
+	r.labelStates("incident", incidents...)
 
-	return JSONResult(map[string]interface{}{
+	response := map[string]interface{}{
 		"success":   true,
 		"message":   fmt.Sprintf("Found %d incidents", len(incidents)),
 		"incidents": incidents,
-	}), nil
+	}
+	if warning != "" {
+		withWarnings(response, warning)
+	}
+	return JSONResult(withPaging(response, page)), nil
 }
 
 func (r *Registry) getIncident(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -362,44 +448,20 @@ func (r *Registry) getIncident(args map[string]interface{}) (*mcp.CallToolResult
 		return JSONResult(NewErrorResponse("incident_id is required", nil)), nil
 	}
 
-	var params map[string]string
-	var endpoint string
-
-	if IsSysID(incidentID) {
-		endpoint = fmt.Sprintf("/table/incident/%s", incidentID)
-		params = map[string]string{
-			"sysparm_display_value":          "true",
-			"sysparm_exclude_reference_link": "true",
-		}
-	} else {
-		endpoint = "/table/incident"
-		params = map[string]string{
-			"sysparm_query":                  fmt.Sprintf("number=%s", incidentID),
-			"sysparm_limit":                  "1",
-			"sysparm_display_value":          "true",
-			"sysparm_exclude_reference_link": "true",
+	incidentData, err := r.readIncident("incident", incidentID, args)
+	archived := false
+	// An incident missing from the live table may have been archived
+	if incidentData == nil && (err == nil || errorCode(err) == mcp.ErrorNotFound) && GetBoolArg(args, "include_archived", false) {
+		if data, archiveErr := r.readIncident(archiveTable("incident"), incidentID, args); archiveErr == nil && data != nil {
+			incidentData, err, archived = data, nil, true
 		}
 	}
-
-	result, err := r.client.Get(endpoint, params)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to get incident", err)), nil
 	}
 
-	var incidentData map[string]interface{}
-	if IsSysID(incidentID) {
-		incidentData, _ = result["result"].(map[string]interface{})
-	} else {
-		if resultList, ok := result["result"].([]interface{}); ok && len(resultList) > 0 {
-			incidentData, _ = resultList[0].(map[string]interface{})
-		}
-	}
-
 	if incidentData == nil {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": fmt.Sprintf("Incident not found: %s", incidentID),
-		}), nil
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Incident not found: %s", incidentID))), nil
 	}
 
 	incident := map[string]interface{}{
@@ -422,6 +484,11 @@ func (r *Registry) getIncident(args map[string]interface{}) (*mcp.CallToolResult
 	} else {
 		incident["assigned_to"] = incidentData["assigned_to"]
 	}
+	incident = selectFields(incident, incidentData, args)
+	if archived {
+		incident["archived"] = true
+	}
+	r.labelStates("incident", incident)
 
 	return JSONResult(map[string]interface{}{
 		"success":  true,
@@ -430,6 +497,35 @@ func (r *Registry) getIncident(args map[string]interface{}) (*mcp.CallToolResult
 	}), nil
 }
 
+// readIncident reads an incident by number or sys_id from table (incident or its
+// archive), or nil when no incident has that number
+func (r *Registry) readIncident(table, incidentID string, args map[string]interface{}) (map[string]interface{}, error) {
+	params := map[string]string{
+		"sysparm_fields":                 readFields(args, incidentDetailFields),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+	}
+	if IsSysID(incidentID) {
+		result, err := r.client.Get(fmt.Sprintf("/table/%s/%s", table, incidentID), params)
+		if err != nil {
+			return nil, err
+		}
+		incidentData, _ := result["result"].(map[string]interface{})
+		return incidentData, nil
+	}
+
+	params["sysparm_query"] = fmt.Sprintf("number=%s", SanitizeQueryValue(incidentID))
+	params["sysparm_limit"] = "1"
+	result, err := r.client.Get("/table/"+table, params)
+	if err != nil {
+		return nil, err
+	}
+	if records := GetResultList(result); len(records) > 0 {
+		return records[0], nil
+	}
+	return nil, nil
+}
+
 func (r *Registry) createIncident(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	if r.readOnlyMode {
 		return WriteBlockedResult(), nil
@@ -447,8 +543,9 @@ func (r *Registry) createIncident(args map[string]interface{}) (*mcp.CallToolRes
 	if v := GetStringArg(args, "description", ""); v != "" {
 		data["description"] = v
 	}
-	if v := GetStringArg(args, "caller_id", ""); v != "" {
-		data["caller_id"] = v
+	// Resolve users and groups to sys_ids so on-behalf-of records are attributed correctly
+	if err := r.setReferenceArgs(args, data, []string{"caller_id", "opened_by", "assigned_to"}, []string{"assignment_group"}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user or group", err)), nil
 	}
 	if v := GetStringArg(args, "category", ""); v != "" {
 		data["category"] = v
@@ -465,11 +562,34 @@ func (r *Registry) createIncident(args map[string]interface{}) (*mcp.CallToolRes
 	if v := GetStringArg(args, "urgency", ""); v != "" {
 		data["urgency"] = v
 	}
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
+	if v := GetStringArg(args, "location", ""); v != "" {
+		locationID, err := r.resolveLocation(v)
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve location", err)), nil
+		}
+		data["location"] = locationID
+	}
+
+	// Location-dependent fields default from where the caller sits
+	var locationDefaults map[string]interface{}
+	var locationWarning string
+	if callerID, ok := data["caller_id"].(string); ok && GetBoolArg(args, "location_defaults", true) {
+		defaults, err := r.applyLocationDefaults("incident", callerID, data, "location", "")
+		if err != nil {
+			locationWarning = fmt.Sprintf("Location defaults were not applied: %v", err)
+		}
+		locationDefaults = defaults
 	}
-	if v := GetStringArg(args, "assignment_group", ""); v != "" {
-		data["assignment_group"] = v
+
+	// Derive priority from impact/urgency when not provided explicitly
+	if _, hasPriority := data["priority"]; !hasPriority {
+		impact, _ := data["impact"].(string)
+		urgency, _ := data["urgency"].(string)
+		if impact != "" && urgency != "" {
+			if priority, _, err := r.computePriority(impact, urgency); err == nil {
+				data["priority"] = priority
+			}
+		}
 	}
 
 	result, err := r.client.Post("/table/incident", data)
@@ -478,12 +598,19 @@ func (r *Registry) createIncident(args map[string]interface{}) (*mcp.CallToolRes
 	}
 
 	if resultData, ok := result["result"].(map[string]interface{}); ok {
-		return JSONResult(map[string]interface{}{
+		response := map[string]interface{}{
 			"success":         true,
 			"message":         "Incident created successfully",
 			"incident_id":     resultData["sys_id"],
 			"incident_number": resultData["number"],
-		}), nil
+		}
+		if locationDefaults != nil {
+			response["location_defaults"] = locationDefaults
+		}
+		if locationWarning != "" {
+			withWarnings(response, locationWarning)
+		}
+		return JSONResult(response), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
@@ -503,7 +630,7 @@ func (r *Registry) updateIncident(args map[string]interface{}) (*mcp.CallToolRes
 	sysID := incidentID
 	if !IsSysID(incidentID) {
 		params := map[string]string{
-			"sysparm_query": fmt.Sprintf("number=%s", incidentID),
+			"sysparm_query": fmt.Sprintf("number=%s", SanitizeQueryValue(incidentID)),
 			"sysparm_limit": "1",
 		}
 		result, err := r.client.Get("/table/incident", params)
@@ -518,10 +645,7 @@ func (r *Registry) updateIncident(args map[string]interface{}) (*mcp.CallToolRes
 		}
 
 		if sysID == "" || sysID == incidentID {
-			return JSONResult(map[string]interface{}{
-				"success": false,
-				"message": fmt.Sprintf("Incident not found: %s", incidentID),
-			}), nil
+			return JSONResult(NewNotFoundResponse(fmt.Sprintf("Incident not found: %s", incidentID))), nil
 		}
 	}
 
@@ -548,28 +672,31 @@ func (r *Registry) updateIncident(args map[string]interface{}) (*mcp.CallToolRes
 	if v := GetStringArg(args, "urgency", ""); v != "" {
 		data["urgency"] = v
 	}
-	if v := GetStringArg(args, "assigned_to", ""); v != "" {
-		data["assigned_to"] = v
-	}
-	if v := GetStringArg(args, "assignment_group", ""); v != "" {
-		data["assignment_group"] = v
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, []string{"assignment_group"}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve user or group", err)), nil
 	}
 	if v := GetStringArg(args, "work_notes", ""); v != "" {
 		data["work_notes"] = v
 	}
 
+	unresolved := r.expandJournalMentions(data)
+
 	result, err := r.client.Put(fmt.Sprintf("/table/incident/%s", sysID), data)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to update incident", err)), nil
 	}
 
 	if resultData, ok := result["result"].(map[string]interface{}); ok {
-		return JSONResult(map[string]interface{}{
+		response := map[string]interface{}{
 			"success":         true,
 			"message":         "Incident updated successfully",
 			"incident_id":     resultData["sys_id"],
 			"incident_number": resultData["number"],
-		}), nil
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
@@ -592,7 +719,7 @@ func (r *Registry) addIncidentComment(args map[string]interface{}) (*mcp.CallToo
 	sysID := incidentID
 	if !IsSysID(incidentID) {
 		params := map[string]string{
-			"sysparm_query": fmt.Sprintf("number=%s", incidentID),
+			"sysparm_query": fmt.Sprintf("number=%s", SanitizeQueryValue(incidentID)),
 			"sysparm_limit": "1",
 		}
 		result, err := r.client.Get("/table/incident", params)
@@ -607,10 +734,7 @@ func (r *Registry) addIncidentComment(args map[string]interface{}) (*mcp.CallToo
 		}
 
 		if sysID == "" || sysID == incidentID {
-			return JSONResult(map[string]interface{}{
-				"success": false,
-				"message": fmt.Sprintf("Incident not found: %s", incidentID),
-			}), nil
+			return JSONResult(NewNotFoundResponse(fmt.Sprintf("Incident not found: %s", incidentID))), nil
 		}
 	}
 
@@ -621,18 +745,24 @@ func (r *Registry) addIncidentComment(args map[string]interface{}) (*mcp.CallToo
 		data["comments"] = comment
 	}
 
+	unresolved := r.expandJournalMentions(data)
+
 	result, err := r.client.Put(fmt.Sprintf("/table/incident/%s", sysID), data)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to add comment", err)), nil
 	}
 
 	if resultData, ok := result["result"].(map[string]interface{}); ok {
-		return JSONResult(map[string]interface{}{
+		response := map[string]interface{}{
 			"success":         true,
 			"message":         "Comment added successfully",
 			"incident_id":     resultData["sys_id"],
 			"incident_number": resultData["number"],
-		}), nil
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
 	}
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
@@ -655,7 +785,7 @@ func (r *Registry) resolveIncident(args map[string]interface{}) (*mcp.CallToolRe
 	sysID := incidentID
 	if !IsSysID(incidentID) {
 		params := map[string]string{
-			"sysparm_query": fmt.Sprintf("number=%s", incidentID),
+			"sysparm_query": fmt.Sprintf("number=%s", SanitizeQueryValue(incidentID)),
 			"sysparm_limit": "1",
 		}
 		result, err := r.client.Get("/table/incident", params)
@@ -670,10 +800,7 @@ func (r *Registry) resolveIncident(args map[string]interface{}) (*mcp.CallToolRe
 		}
 
 		if sysID == "" || sysID == incidentID {
-			return JSONResult(map[string]interface{}{
-				"success": false,
-				"message": fmt.Sprintf("Incident not found: %s", incidentID),
-			}), nil
+			return JSONResult(NewNotFoundResponse(fmt.Sprintf("Incident not found: %s", incidentID))), nil
 		}
 	}
 
@@ -700,3 +827,174 @@ func (r *Registry) resolveIncident(args map[string]interface{}) (*mcp.CallToolRe
 
 	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
 }
+
+// transcriptWorkNoteSize is the maximum length of each work note when a transcript is saved as work notes
+const transcriptWorkNoteSize = 4000
+
+// transcriptContentTypes maps attachment formats to their MIME types
+var transcriptContentTypes = map[string]string{
+	"md":  "text/markdown",
+	"txt": "text/plain",
+}
+
+func (r *Registry) attachTranscript(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	incidentID := GetStringArg(args, "incident_id", "")
+	transcript := GetStringArg(args, "transcript_text", "")
+	format := GetStringArg(args, "format", "md")
+
+	if incidentID == "" || strings.TrimSpace(transcript) == "" {
+		return JSONResult(NewErrorResponse("incident_id and transcript_text are required", nil)), nil
+	}
+	if _, ok := transcriptContentTypes[format]; !ok && format != "work_notes" {
+		return JSONResult(NewErrorResponse("format must be 'md', 'txt', or 'work_notes'", nil)), nil
+	}
+
+	sysID, err := r.resolveIncidentID(incidentID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find incident", err)), nil
+	}
+
+	if format == "work_notes" {
+		parts := chunkTranscript(transcript, transcriptWorkNoteSize)
+		for i, part := range parts {
+			note := fmt.Sprintf("[AI agent transcript, part %d of %d]\n%s", i+1, len(parts), part)
+			if _, err := r.client.Put(fmt.Sprintf("/table/incident/%s", sysID), map[string]interface{}{"work_notes": note}); err != nil {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to add transcript work note %d of %d", i+1, len(parts)), err)), nil
+			}
+		}
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     fmt.Sprintf("Transcript added as %d work note(s)", len(parts)),
+			"incident_id": sysID,
+			"work_notes":  len(parts),
+		}), nil
+	}
+
+	fileName := fmt.Sprintf("ai-transcript-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	result, err := r.client.UploadAttachment("incident", sysID, fileName, transcriptContentTypes[format], []byte(transcript))
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to attach transcript", err)), nil
+	}
+
+	resultData, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+
+	// Point the human agent at the attachment from the activity stream
+	note := fmt.Sprintf("AI agent conversation transcript attached: %s", fileName)
+	_, noteErr := r.client.Put(fmt.Sprintf("/table/incident/%s", sysID), map[string]interface{}{"work_notes": note})
+
+	response := map[string]interface{}{
+		"success":       true,
+		"message":       "Transcript attached successfully",
+		"incident_id":   sysID,
+		"attachment_id": resultData["sys_id"],
+		"file_name":     fileName,
+	}
+	if noteErr != nil {
+		withWarnings(response, "Transcript attached, but the work note referencing it could not be added: "+noteErr.Error())
+	}
+	return JSONResult(response), nil
+}
+
+// chunkTranscript splits a transcript into parts of at most size bytes, breaking on line boundaries where possible
+func chunkTranscript(transcript string, size int) []string {
+	var parts []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(transcript, "\n") {
+		for len(line) > size {
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+			parts = append(parts, line[:size])
+			line = line[size:]
+		}
+		if current.Len()+len(line) > size {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
+
+// resolveIncidentID resolves an incident number to sys_id
+func (r *Registry) resolveIncidentID(incidentID string) (string, error) {
+	return r.resolveRecordNumber("incident", incidentID, "incident")
+}
+
+// defaultPriorityMatrix is the out-of-box ServiceNow priority lookup, keyed by impact then urgency
+var defaultPriorityMatrix = map[string]map[string]string{
+	"1": {"1": "1", "2": "2", "3": "3"},
+	"2": {"1": "2", "2": "3", "3": "4"},
+	"3": {"1": "3", "2": "4", "3": "5"},
+}
+
+// priorityLabels maps priority values to their default labels
+var priorityLabels = map[string]string{
+	"1": "Critical",
+	"2": "High",
+	"3": "Moderate",
+	"4": "Low",
+	"5": "Planning",
+}
+
+// computePriority derives priority from impact and urgency. It consults the
+// instance's priority data lookup table (dl_u_priority) and falls back to the
+// default matrix when the table is unavailable or has no matching rule.
+// Returns the priority value and the source used ("instance" or "default").
+func (r *Registry) computePriority(impact, urgency string) (string, string, error) {
+	if _, ok := defaultPriorityMatrix[impact][urgency]; !ok {
+		return "", "", fmt.Errorf("invalid impact/urgency combination: impact=%s, urgency=%s", impact, urgency)
+	}
+
+	params := map[string]string{
+		"sysparm_query":  fmt.Sprintf("impact=%s^urgency=%s^active=true", impact, urgency),
+		"sysparm_fields": "priority",
+		"sysparm_limit":  "1",
+	}
+	result, err := r.client.Get("/table/dl_u_priority", params)
+	if err == nil {
+		if resultList, ok := result["result"].([]interface{}); ok && len(resultList) > 0 {
+			if data, ok := resultList[0].(map[string]interface{}); ok {
+				if priority, ok := data["priority"].(string); ok && priority != "" {
+					return priority, "instance", nil
+				}
+			}
+		}
+	}
+
+	return defaultPriorityMatrix[impact][urgency], "default", nil
+}
+
+func (r *Registry) computePriorityTool(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	impact := GetStringArg(args, "impact", "")
+	urgency := GetStringArg(args, "urgency", "")
+	if impact == "" || urgency == "" {
+		return JSONResult(NewErrorResponse("impact and urgency are required", nil)), nil
+	}
+
+	priority, source, err := r.computePriority(impact, urgency)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to compute priority", err)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":        true,
+		"message":        fmt.Sprintf("Priority %s (%s)", priority, priorityLabels[priority]),
+		"impact":         impact,
+		"urgency":        urgency,
+		"priority":       priority,
+		"priority_label": priorityLabels[priority],
+		"source":         source,
+	}), nil
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestChunkTranscript tests that transcripts split on line boundaries within the size limit
+func TestChunkTranscript(t *testing.T) {
+	transcript := "User: hello\nAgent: hi\nUser: " + strings.Repeat("x", 25) + "\n"
+
+	parts := chunkTranscript(transcript, 12)
+	if strings.Join(parts, "") != transcript {
+		t.Fatalf("Expected parts to reassemble the transcript, got %q", parts)
+	}
+	for _, part := range parts {
+		if len(part) > 12 {
+			t.Errorf("Expected part of at most 12 bytes, got %d (%q)", len(part), part)
+		}
+	}
+	if parts[0] != "User: hello\n" {
+		t.Errorf("Expected first part to end on a line boundary, got %q", parts[0])
+	}
+
+	if parts := chunkTranscript("short", 100); len(parts) != 1 || parts[0] != "short" {
+		t.Errorf("Expected a single part, got %q", parts)
+	}
+}
+
+// TestAttachTranscript tests that a transcript is uploaded as an attachment and referenced in a work note
+func TestAttachTranscript(t *testing.T) {
+	const sysID = "6816f79cc0a8016401c5a33be04be441"
+
+	var uploaded, contentType, workNote string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": sysID}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/attachment/file":
+			if r.URL.Query().Get("table_sys_id") != sysID {
+				t.Errorf("Expected upload to incident %s, got %s", sysID, r.URL.RawQuery)
+			}
+			body, _ := io.ReadAll(r.Body)
+			uploaded = string(body)
+			contentType = r.Header.Get("Content-Type")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "att1"}})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/incident/"+sysID:
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			workNote, _ = body["work_notes"].(string)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": sysID}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.attachTranscript(map[string]interface{}{
+		"incident_id":     "INC0010001",
+		"transcript_text": "User: VPN drops\nAgent: Restarted client",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"attachment_id": "att1"`) {
+		t.Fatalf("Expected attachment result, got %+v", result)
+	}
+	if uploaded != "User: VPN drops\nAgent: Restarted client" || contentType != "text/markdown" {
+		t.Errorf("Expected markdown transcript upload, got %q (%s)", uploaded, contentType)
+	}
+	if !strings.HasPrefix(workNote, "AI agent conversation transcript attached: ai-transcript-") {
+		t.Errorf("Expected work note referencing the attachment, got %q", workNote)
+	}
+}
+
+// TestListIncidentsSanitizesFilters tests that filter values cannot add terms to the encoded query
+func TestListIncidentsSanitizesFilters(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("sysparm_query")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.listIncidents(map[string]interface{}{
+		"state":    "1^ORstate!=1",
+		"category": "network^ORDERBYDESCsys_created_on",
+	})
+	if result.IsError {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if want := "state=1ORstate!=1^category=networkORDERBYDESCsys_created_on"; query != want {
+		t.Errorf("Expected query %q, got %q", want, query)
+	}
+}
@@ -0,0 +1,531 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+const (
+	// Job types accepted by start_job
+	jobTypeExportTable = "export_table"
+	jobTypeToolCall    = "tool_call"
+
+	// Job states reported by get_job_status
+	jobRunning   = "running"
+	jobCompleted = "completed"
+	jobFailed    = "failed"
+
+	// maxActiveJobs bounds the number of jobs running at once across all callers
+	maxActiveJobs = 10
+	// jobRetention is how long finished jobs and their results are kept
+	jobRetention = time.Hour
+	// exportPageSize is the number of records fetched per Table API request by exports
+	exportPageSize = 500
+	// defaultExportRecords and maxExportRecords bound the records an export collects
+	defaultExportRecords = 10000
+	maxExportRecords     = 100000
+)
+
+// jobTools are the job management tools, which start_job cannot run as a tool_call
+var jobTools = map[string]bool{"start_job": true, "get_job_status": true, "fetch_job_result": true}
+
+// job is a long-running export or tool call started by start_job
+type job struct {
+	id          string
+	jobType     string
+	description string
+	owner       string
+
+	// Guarded by jobStore.mu
+	status    string
+	processed int
+	total     int // -1 when unknown
+	started   time.Time
+	finished  time.Time
+	records   []map[string]interface{}
+	result    *mcp.CallToolResult
+	err       string
+}
+
+// jobStore holds the jobs started on this server. Jobs live in memory for the life
+// of the process; finished jobs are dropped after jobRetention.
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: map[string]*job{}}
+}
+
+// start registers a new running job, or returns an error when too many jobs are running
+func (s *jobStore) start(jobType, description, owner string) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := 0
+	for id, j := range s.jobs {
+		if j.status != jobRunning && time.Since(j.finished) > jobRetention {
+			delete(s.jobs, id)
+			continue
+		}
+		if j.status == jobRunning {
+			active++
+		}
+	}
+	if active >= maxActiveJobs {
+		return nil, fmt.Errorf("%d jobs are already running; wait for one to finish", active)
+	}
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	j := &job{
+		id:          "job_" + hex.EncodeToString(b),
+		jobType:     jobType,
+		description: description,
+		owner:       owner,
+		status:      jobRunning,
+		total:       -1,
+		started:     time.Now(),
+	}
+	s.jobs[j.id] = j
+	return j, nil
+}
+
+// get returns a job started by owner
+func (s *jobStore) get(id, owner string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok || j.owner != owner {
+		return nil, false
+	}
+	return j, true
+}
+
+// update applies fn to a job under the store lock
+func (s *jobStore) update(j *job, fn func(j *job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(j)
+}
+
+// finish marks a job completed, or failed when err is non-nil
+func (s *jobStore) finish(j *job, err error) {
+	s.update(j, func(j *job) {
+		j.finished = time.Now()
+		if err != nil {
+			j.status = jobFailed
+			j.err = err.Error()
+			return
+		}
+		j.status = jobCompleted
+	})
+}
+
+// status summarizes a job for get_job_status
+func (s *jobStore) status(j *job) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := map[string]interface{}{
+		"job_id":      j.id,
+		"type":        j.jobType,
+		"description": j.description,
+		"status":      j.status,
+		"processed":   j.processed,
+		"started_at":  j.started.UTC().Format(time.RFC3339),
+	}
+	if j.total >= 0 {
+		status["total"] = j.total
+		if j.total > 0 {
+			status["percent"] = min(100, j.processed*100/j.total)
+		}
+	}
+	end := time.Now()
+	if j.status != jobRunning {
+		end = j.finished
+		status["finished_at"] = j.finished.UTC().Format(time.RFC3339)
+	}
+	status["elapsed_seconds"] = int(end.Sub(j.started).Seconds())
+	if j.err != "" {
+		status["error"] = j.err
+	}
+	return status
+}
+
+// jobOwner identifies the caller a job belongs to, so callers with their own
+// credentials only see their own jobs. Stdio and shared-credential callers share "".
+func jobOwner(ctx context.Context) string {
+	if creds := servicenow.CredentialsFromContext(ctx); creds != nil {
+		if creds.Username != "" {
+			return "user " + creds.Username
+		}
+		if creds.APIKey != "" {
+			sum := sha256.Sum256([]byte(creds.APIKey))
+			return "api key " + hex.EncodeToString(sum[:6])
+		}
+	}
+	return ""
+}
+
+// registerJobTools registers the long-running job tools
+func (r *Registry) registerJobTools(server *mcp.Server) int {
+	count := 0
+
+	offsetMin := float64(0)
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	recordsMin := float64(1)
+	recordsMax := float64(maxExportRecords)
+
+	// Start Job
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "start_job",
+		Description: "Start a long-running job in the background and return its job_id. Use it for exports and bulk operations that would exceed a call timeout, then poll get_job_status and collect the output with fetch_job_result. 'export_table' pages through every record matching a table query; 'tool_call' runs another tool (e.g., move_catalog_items) with the given arguments.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"type": {
+					Type:        "string",
+					Description: "Job type",
+					Enum:        []string{jobTypeExportTable, jobTypeToolCall},
+				},
+				"table": {
+					Type:        "string",
+					Description: "export_table: table name (e.g., 'incident', 'cmdb_ci_server')",
+				},
+				"filters": {
+					Type:        "array",
+					Description: "export_table: conditions ANDed together, as in query_table",
+					Items:       &mcp.Property{Type: "object"},
+				},
+				"query": {
+					Type:        "string",
+					Description: "export_table: raw encoded query (e.g., 'active=true^priority=1')",
+				},
+				"fields": {
+					Type:        "array",
+					Description: "export_table: fields to return (default: all fields)",
+					Items:       &mcp.Property{Type: "string"},
+				},
+				"order_by": {
+					Type:        "string",
+					Description: "export_table: field to sort by (e.g., 'sys_created_on')",
+				},
+				"order_direction": {
+					Type:        "string",
+					Description: "export_table: sort direction",
+					Default:     "desc",
+					Enum:        []string{"asc", "desc"},
+				},
+				"display_value": {
+					Type:        "string",
+					Description: "export_table: return raw values ('false'), display values ('true'), or both ('all')",
+					Default:     "true",
+					Enum:        []string{"true", "false", "all"},
+				},
+				"max_records": {
+					Type:        "integer",
+					Description: "export_table: stop after this many records",
+					Default:     defaultExportRecords,
+					Minimum:     &recordsMin,
+					Maximum:     &recordsMax,
+				},
+				"tool": {
+					Type:        "string",
+					Description: "tool_call: name of the tool to run (e.g., 'add_group_members')",
+				},
+				"arguments": {
+					Type:        "object",
+					Description: "tool_call: arguments for the tool",
+				},
+			},
+			Required: []string{"type"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title: "Start Job",
+		},
+	}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.startJob(ctx, server, args)
+	})
+	count++
+
+	// Get Job Status
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "get_job_status",
+		Description: "Report the status and progress of a job started with start_job.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"job_id": {
+					Type:        "string",
+					Description: "Job ID returned by start_job",
+				},
+			},
+			Required: []string{"job_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Job Status",
+			ReadOnlyHint: true,
+		},
+	}, r.getJobStatus)
+	count++
+
+	// Fetch Job Result
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "fetch_job_result",
+		Description: "Return the output of a finished job. Export records are returned a page at a time; pass next_offset back as offset to continue.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"job_id": {
+					Type:        "string",
+					Description: "Job ID returned by start_job",
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "export_table: index of the first record to return",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "export_table: max records to return",
+					Default:     exportPageSize,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+			},
+			Required: []string{"job_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Fetch Job Result",
+			ReadOnlyHint: true,
+		},
+	}, r.fetchJobResult)
+	count++
+
+	return count
+}
+
+func (r *Registry) startJob(ctx context.Context, server *mcp.Server, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	// The job outlives the request (and the per-tool timeout) but keeps its
+	// credentials and impersonation
+	jobCtx := withoutToolTimeout(context.WithoutCancel(ctx))
+
+	jobType := GetStringArg(args, "type", "")
+	var description string
+	var run func(j *job) error
+	switch jobType {
+	case jobTypeExportTable:
+		table, params, errResp := tableQueryParams(args)
+		if errResp != nil {
+			return JSONResult(errResp), nil
+		}
+		maxRecords := GetIntArg(args, "max_records", defaultExportRecords)
+		if maxRecords < 1 || maxRecords > maxExportRecords {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("max_records must be between 1 and %d", maxExportRecords), nil)), nil
+		}
+		description = fmt.Sprintf("Export of %s", table)
+		run = func(j *job) error {
+			return r.runExport(jobCtx, j, table, params, maxRecords)
+		}
+	case jobTypeToolCall:
+		name := GetStringArg(args, "tool", "")
+		if name == "" {
+			return JSONResult(NewErrorResponse("tool is required for tool_call jobs", nil)), nil
+		}
+		// Callers use the externally visible name, which carries any configured prefix
+		if jobTools[strings.TrimPrefix(name, server.ToolName(""))] {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("%s cannot be run as a job", name), nil)), nil
+		}
+		arguments := GetMapArg(args, "arguments")
+		description = fmt.Sprintf("Call of %s", name)
+		run = func(j *job) error {
+			result, err := server.CallTool(jobCtx, name, arguments)
+			if err != nil {
+				return err
+			}
+			r.jobs.update(j, func(j *job) {
+				j.result = result
+				j.processed = 1
+				j.total = 1
+			})
+			return nil
+		}
+	case "":
+		return JSONResult(NewErrorResponse("type is required", nil)), nil
+	default:
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Unsupported job type: %s", jobType), nil)), nil
+	}
+
+	j, err := r.jobs.start(jobType, description, jobOwner(ctx))
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to start job", err)), nil
+	}
+	go func() {
+		err := run(j)
+		r.jobs.finish(j, err)
+		if err != nil && r.logger != nil {
+			r.logger.Warn("Job %s (%s) failed: %v", j.id, j.description, err)
+		}
+	}()
+
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Started job %s (%s). Poll get_job_status, then call fetch_job_result when it completes.", j.id, description),
+		"job_id":  j.id,
+		"status":  jobRunning,
+	}), nil
+}
+
+// runExport pages through the records matching a table query into the job,
+// counting the matching records first so progress can be reported
+func (r *Registry) runExport(ctx context.Context, j *job, table string, params map[string]string, maxRecords int) error {
+	endpoint := fmt.Sprintf("/table/%s", table)
+
+	statsParams := map[string]string{"sysparm_count": "true"}
+	if query, ok := params["sysparm_query"]; ok {
+		statsParams["sysparm_query"] = query
+	}
+	if stats, err := r.base.GetWithContext(ctx, fmt.Sprintf("/stats/%s", table), statsParams); err == nil {
+		if count, ok := statsCount(stats); ok {
+			r.jobs.update(j, func(j *job) { j.total = min(count, maxRecords) })
+		}
+	}
+
+	_, err := r.base.GetAllPages(ctx, endpoint, params, min(exportPageSize, maxRecords), func(page []map[string]interface{}) error {
+		stop := false
+		r.jobs.update(j, func(j *job) {
+			if room := maxRecords - len(j.records); len(page) >= room {
+				page = page[:room]
+				stop = true
+			}
+			j.records = append(j.records, page...)
+			j.processed = len(j.records)
+		})
+		if stop {
+			return servicenow.ErrStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", table, err)
+	}
+
+	// The count is an estimate when records change during the export
+	r.jobs.update(j, func(j *job) { j.total = j.processed })
+	return nil
+}
+
+// statsCount extracts the record count from an Aggregate API response
+func statsCount(result map[string]interface{}) (int, bool) {
+	body, _ := result["result"].(map[string]interface{})
+	stats, _ := body["stats"].(map[string]interface{})
+	switch count := stats["count"].(type) {
+	case string:
+		n, err := strconv.Atoi(count)
+		return n, err == nil
+	case float64:
+		return int(count), true
+	}
+	return 0, false
+}
+
+func (r *Registry) getJobStatus(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	id := GetStringArg(args, "job_id", "")
+	if id == "" {
+		return JSONResult(NewErrorResponse("job_id is required", nil)), nil
+	}
+	j, ok := r.jobs.get(id, jobOwner(ctx))
+	if !ok {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Job not found: %s (finished jobs are kept for %s)", id, jobRetention))), nil
+	}
+
+	status := r.jobs.status(j)
+	status["success"] = true
+	switch status["status"] {
+	case jobRunning:
+		status["message"] = fmt.Sprintf("Job %s is running (%d processed)", id, status["processed"])
+	case jobCompleted:
+		status["message"] = fmt.Sprintf("Job %s completed; call fetch_job_result for its output", id)
+	default:
+		status["message"] = fmt.Sprintf("Job %s failed: %s", id, status["error"])
+	}
+	return JSONResult(status), nil
+}
+
+func (r *Registry) fetchJobResult(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	id := GetStringArg(args, "job_id", "")
+	if id == "" {
+		return JSONResult(NewErrorResponse("job_id is required", nil)), nil
+	}
+	j, ok := r.jobs.get(id, jobOwner(ctx))
+	if !ok {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Job not found: %s (finished jobs are kept for %s)", id, jobRetention))), nil
+	}
+
+	r.jobs.mu.Lock()
+	defer r.jobs.mu.Unlock()
+
+	switch j.status {
+	case jobRunning:
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Job %s is still running (%d processed); check get_job_status", id, j.processed), nil)), nil
+	case jobFailed:
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Job %s failed", id), fmt.Errorf("%s", j.err))), nil
+	}
+
+	if j.jobType == jobTypeToolCall {
+		// The called tool's result as a direct call would have returned it, copied
+		// so usage metadata attached to this call doesn't accumulate on the stored result
+		result := &mcp.CallToolResult{
+			Content: append([]mcp.ContentItem(nil), j.result.Content...),
+			IsError: j.result.IsError,
+		}
+		if len(j.result.Meta) > 0 {
+			result.Meta = make(map[string]interface{}, len(j.result.Meta))
+			for k, v := range j.result.Meta {
+				result.Meta[k] = v
+			}
+		}
+		return result, nil
+	}
+
+	offset := GetIntArg(args, "offset", 0)
+	limit := GetIntArg(args, "limit", exportPageSize)
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 1 {
+		limit = exportPageSize
+	}
+	start := min(offset, len(j.records))
+	end := min(start+limit, len(j.records))
+
+	response := map[string]interface{}{
+		"success":       true,
+		"message":       fmt.Sprintf("Records %d-%d of %d from job %s", start+1, end, len(j.records), id),
+		"job_id":        id,
+		"total_records": len(j.records),
+		"records":       j.records[start:end],
+		"has_more":      end < len(j.records),
+	}
+	if end < len(j.records) {
+		response["next_offset"] = end
+	}
+	if start == end {
+		response["message"] = fmt.Sprintf("No records at offset %d (job %s has %d records)", offset, id, len(j.records))
+	}
+	return JSONResult(response), nil
+}
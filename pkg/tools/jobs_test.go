@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// waitForJob polls get_job_status until the job leaves the running state
+func waitForJob(t *testing.T, status mcp.ToolHandlerWithContext, id string) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		result, _ := status(context.Background(), map[string]interface{}{"job_id": id})
+		var body map[string]interface{}
+		_ = json.Unmarshal([]byte(result.Content[0].Text), &body)
+		if body["status"] != jobRunning {
+			return body
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job %s still running: %v", id, body)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestExportJob tests that an export job pages through every record and serves them a page at a time
+func TestExportJob(t *testing.T) {
+	const total = 1200
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/stats/incident":
+			if r.URL.Query().Get("sysparm_query") != "active=true" {
+				t.Errorf("Unexpected stats query %q", r.URL.Query().Get("sysparm_query"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"stats": map[string]interface{}{"count": strconv.Itoa(total)}}})
+		case "/api/now/table/incident":
+			offset, _ := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("sysparm_limit"))
+			records := []interface{}{}
+			for i := offset; i < total && i < offset+limit; i++ {
+				records = append(records, map[string]interface{}{"number": fmt.Sprintf("INC%07d", i+1)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	_, server := newTestRegistry(t, ts.URL, true)
+	start, _ := server.Handler("start_job")
+	status, _ := server.Handler("get_job_status")
+	fetch, _ := server.Handler("fetch_job_result")
+
+	result, _ := start(context.Background(), map[string]interface{}{
+		"type":        "export_table",
+		"table":       "incident",
+		"query":       "active=true",
+		"max_records": float64(1100),
+	})
+	var started map[string]interface{}
+	_ = json.Unmarshal([]byte(result.Content[0].Text), &started)
+	id, _ := started["job_id"].(string)
+	if id == "" {
+		t.Fatalf("Expected a job ID, got %s", result.Content[0].Text)
+	}
+
+	done := waitForJob(t, status, id)
+	if done["status"] != jobCompleted || done["processed"] != float64(1100) || done["total"] != float64(1100) {
+		t.Fatalf("Expected 1100 records exported (capped by max_records), got %v", done)
+	}
+
+	result, _ = fetch(context.Background(), map[string]interface{}{"job_id": id, "offset": float64(1000), "limit": float64(500)})
+	var page map[string]interface{}
+	_ = json.Unmarshal([]byte(result.Content[0].Text), &page)
+	records, _ := page["records"].([]interface{})
+	if len(records) != 100 || page["has_more"] != false || page["total_records"] != float64(1100) {
+		t.Fatalf("Expected the last 100 records, got %d records: %v", len(records), page["message"])
+	}
+	if first, _ := records[0].(map[string]interface{}); first["number"] != "INC0001001" {
+		t.Errorf("Expected records in fetch order, got %v", first)
+	}
+
+	result, _ = fetch(context.Background(), map[string]interface{}{"job_id": "job_missing"})
+	if !strings.Contains(result.Content[0].Text, "Job not found") {
+		t.Errorf("Expected unknown job error, got %s", result.Content[0].Text)
+	}
+}
+
+// TestToolCallJob tests that a tool_call job runs a tool through the server and returns its result
+func TestToolCallJob(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", true)
+	registry.EnableDiagnosticTools()
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+	start, _ := server.Handler("start_job")
+	status, _ := server.Handler("get_job_status")
+	fetch, _ := server.Handler("fetch_job_result")
+
+	result, _ := start(context.Background(), map[string]interface{}{"type": "tool_call", "tool": "start_job"})
+	if !strings.Contains(result.Content[0].Text, "cannot be run as a job") {
+		t.Errorf("Expected job tools to be rejected, got %s", result.Content[0].Text)
+	}
+
+	result, _ = start(context.Background(), map[string]interface{}{
+		"type":      "tool_call",
+		"tool":      "echo",
+		"arguments": map[string]interface{}{"message": "from a job"},
+	})
+	var started map[string]interface{}
+	_ = json.Unmarshal([]byte(result.Content[0].Text), &started)
+	id, _ := started["job_id"].(string)
+
+	if done := waitForJob(t, status, id); done["status"] != jobCompleted {
+		t.Fatalf("Expected the job to complete, got %v", done)
+	}
+	result, _ = fetch(context.Background(), map[string]interface{}{"job_id": id})
+	if !strings.Contains(result.Content[0].Text, "from a job") {
+		t.Errorf("Expected the echo result, got %s", result.Content[0].Text)
+	}
+}
+
+// TestToolCallJobPrefixed tests that job tools are rejected under their prefixed names
+func TestToolCallJobPrefixed(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", true)
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	server.SetToolPrefix("snow")
+	registry.RegisterAll(server)
+	start, _ := server.Handler("snow_start_job")
+
+	for _, name := range []string{"snow_start_job", "snow_get_job_status", "snow_fetch_job_result"} {
+		result, _ := start(context.Background(), map[string]interface{}{"type": "tool_call", "tool": name})
+		if !strings.Contains(result.Content[0].Text, "cannot be run as a job") {
+			t.Errorf("Expected %s to be rejected, got %s", name, result.Content[0].Text)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// journalTypes maps the type filter of the journal tools to journal fields (sys_journal_field element)
+var journalTypes = map[string]string{
+	"all":        "comments,work_notes",
+	"comments":   "comments",
+	"work_notes": "work_notes",
+}
+
+// journalEntryTypes names the entries of each journal field in results
+var journalEntryTypes = map[string]string{
+	"comments":   "comment",
+	"work_notes": "work_note",
+}
+
+// registerJournalTools registers the journal (comments and work notes) history tools
+func (r *Registry) registerJournalTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(500)
+	offsetMin := float64(0)
+
+	// journalProperties are the filters shared by the journal tools
+	journalProperties := func(properties map[string]mcp.Property) map[string]mcp.Property {
+		properties["type"] = mcp.Property{
+			Type:        "string",
+			Description: "Entries to return: customer-visible comments, internal work notes, or both",
+			Enum:        []string{"all", "comments", "work_notes"},
+			Default:     "all",
+		}
+		properties["since"] = mcp.Property{
+			Type:        "string",
+			Description: "Only entries written at or after this date/time (format: YYYY-MM-DD HH:MM:SS)",
+			Format:      "date-time",
+		}
+		properties["limit"] = mcp.Property{
+			Type:        "integer",
+			Description: "Most entries to return, the newest first selected (default: 50)",
+			Default:     50,
+			Minimum:     &limitMin,
+			Maximum:     &limitMax,
+		}
+		properties["offset"] = mcp.Property{
+			Type:        "integer",
+			Description: "Newer entries to skip, to page back through a long history",
+			Default:     0,
+			Minimum:     &offsetMin,
+		}
+		return properties
+	}
+
+	// Get Incident Journal (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_incident_journal",
+		Description: "Get the conversation on an incident: its comments and work notes in the order they were written, with author and timestamp. get_incident returns only the current field values.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: journalProperties(map[string]mcp.Property{
+				"incident_id": {
+					Type:        "string",
+					Description: "Incident number (e.g., 'INC0010001') or sys_id",
+				},
+			}),
+			Required: []string{"incident_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Incident Journal",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getIncidentJournal)
+	count++
+
+	// Get Record Journal (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_record_journal",
+		Description: "Get the comments and work notes of any record with a journal (e.g., a change, problem, or catalog task) in the order they were written, with author and timestamp.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: journalProperties(map[string]mcp.Property{
+				"table": {
+					Type:        "string",
+					Description: "Table of the record (e.g., 'change_request', 'problem', 'sc_task')",
+				},
+				"record_id": {
+					Type:        "string",
+					Description: "Record number (e.g., 'CHG0030001') or sys_id",
+				},
+			}),
+			Required: []string{"table", "record_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Record Journal",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getRecordJournal)
+	count++
+
+	return count
+}
+
+func (r *Registry) getIncidentJournal(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	incidentID := GetStringArg(args, "incident_id", "")
+	if incidentID == "" {
+		return JSONResult(NewErrorResponse("incident_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveIncidentID(incidentID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find incident", err)), nil
+	}
+	return r.recordJournal("incident", sysID, incidentID, args)
+}
+
+func (r *Registry) getRecordJournal(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	table := GetStringArg(args, "table", "")
+	recordID := GetStringArg(args, "record_id", "")
+	if table == "" || recordID == "" {
+		return JSONResult(NewErrorResponse("table and record_id are required", nil)), nil
+	}
+	if !tableNamePattern.MatchString(table) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+	}
+
+	sysID, err := r.resolveRecordNumber(table, recordID, table+" record")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find record", err)), nil
+	}
+	return r.recordJournal(table, sysID, recordID, args)
+}
+
+// recordJournal reads the journal entries of a record from sys_journal_field. The newest
+// entries matching the filters are selected, then returned oldest first.
+func (r *Registry) recordJournal(table, sysID, recordID string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	journalType := GetStringArg(args, "type", "all")
+	elements, ok := journalTypes[journalType]
+	if !ok {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid type: %s (use all, comments, or work_notes)", journalType), nil)), nil
+	}
+
+	filters := []string{"element_id=" + sysID, "elementIN" + elements}
+	if since := GetStringArg(args, "since", ""); since != "" {
+		filters = append(filters, fmt.Sprintf("sys_created_on>=%s", SanitizeQueryValue(since)))
+	}
+	params := map[string]string{
+		"sysparm_query":  strings.Join(append(filters, "ORDERBYDESCsys_created_on"), "^"),
+		"sysparm_fields": "sys_id,element,value,sys_created_by,sys_created_on",
+		"sysparm_limit":  fmt.Sprintf("%d", GetIntArg(args, "limit", 50)),
+		"sysparm_offset": fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	result, total, err := r.client.GetWithTotalCount("/table/sys_journal_field", params)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read journal", err)), nil
+	}
+
+	records := GetResultList(result)
+	entries := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		// Read newest first; returned in the order written
+		entries[len(records)-1-i] = map[string]interface{}{
+			"sys_id":     FieldValue(record["sys_id"]),
+			"type":       journalEntryTypes[FieldValue(record["element"])],
+			"author":     FieldValue(record["sys_created_by"]),
+			"created_on": FieldValue(record["sys_created_on"]),
+			"value":      FieldValue(record["value"]),
+		}
+	}
+
+	page := newListPage(total, GetIntArg(args, "limit", 50), GetIntArg(args, "offset", 0), len(records))
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d journal entries on %s", len(entries), recordID),
+		"record":  map[string]interface{}{"table": table, "sys_id": sysID},
+		"entries": entries,
+	}, page)), nil
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGetIncidentJournal tests that the newest journal entries are read and returned in the order written
+func TestGetIncidentJournal(t *testing.T) {
+	const incidentID = "a0000000000000000000000000000001"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/incident":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + incidentID + `"}]}`))
+		case "/api/now/table/sys_journal_field":
+			want := "element_id=" + incidentID + "^elementINwork_notes^sys_created_on>=2026-10-01 00:00:00^ORDERBYDESCsys_created_on"
+			if got := r.URL.Query().Get("sysparm_query"); got != want {
+				t.Errorf("Expected query %s, got %s", want, got)
+			}
+			w.Header().Set("X-Total-Count", "3")
+			_, _ = w.Write([]byte(`{"result": [
+				{"sys_id": "j2", "element": "work_notes", "value": "Replaced the toner", "sys_created_by": "beth.anglin", "sys_created_on": "2026-10-02 09:00:00"},
+				{"sys_id": "j1", "element": "work_notes", "value": "Checked the printer", "sys_created_by": "beth.anglin", "sys_created_on": "2026-10-01 09:00:00"}
+			]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.getIncidentJournal(map[string]interface{}{
+		"incident_id": "INC0010001", "type": "work_notes", "since": "2026-10-01 00:00:00", "limit": float64(2),
+	})
+	var response struct {
+		Success bool                `json:"success"`
+		Entries []map[string]string `json:"entries"`
+		HasMore bool                `json:"has_more"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Entries) != 2 || !response.HasMore {
+		t.Fatalf("Expected two of three entries, got %s", result.Content[0].Text)
+	}
+	first := response.Entries[0]
+	if first["sys_id"] != "j1" || first["type"] != "work_note" || first["author"] != "beth.anglin" || first["created_on"] != "2026-10-01 09:00:00" {
+		t.Errorf("Expected the oldest entry first with its author and time, got %+v", first)
+	}
+}
@@ -0,0 +1,591 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// kbAccessTables are the many-to-many tables relating knowledge bases to user criteria, by access type
+var kbAccessTables = map[string]string{
+	"can_read":          "kb_uc_can_read_mtom",
+	"can_contribute":    "kb_uc_can_contribute_mtom",
+	"cannot_read":       "kb_uc_cannot_read_mtom",
+	"cannot_contribute": "kb_uc_cannot_contribute_mtom",
+}
+
+// kbAccessTypes lists the access types in schema order
+var kbAccessTypes = []string{"can_read", "can_contribute", "cannot_read", "cannot_contribute"}
+
+// userCriteriaFields are the fields of user_criteria records returned by the access tools
+const userCriteriaFields = "sys_id,name,active,users,groups,roles,departments,locations,companies,match_all,advanced"
+
+// userCriteriaLists are the user_criteria fields listing who a criteria record matches
+var userCriteriaLists = []string{"users", "groups", "roles", "departments", "locations", "companies"}
+
+// registerKBAccessTools registers tools for the user criteria controlling who can read and contribute to knowledge bases
+func (r *Registry) registerKBAccessTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// Get KB Access (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_kb_access",
+		Description: "Show who can read and contribute to a knowledge base: its can read, can contribute, cannot read, and cannot contribute user criteria with the users, groups, and roles each matches. Give article_id to also include the article's own read criteria, e.g., to find out why users can't see an article.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"knowledge_base": {
+					Type:        "string",
+					Description: "Knowledge base sys_id or title (e.g., 'IT'). Optional with article_id.",
+				},
+				"article_id": {
+					Type:        "string",
+					Description: "Article number (e.g., 'KB0010001') or sys_id",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get KB Access",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getKBAccess)
+	count++
+
+	// List User Criteria (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_user_criteria",
+		Description: "List user criteria records (user_criteria), which grant or deny access to knowledge bases and catalog items by users, groups, roles, departments, locations, and companies.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"query": {
+					Type:        "string",
+					Description: "Search text in the criteria name",
+				},
+				"active": {
+					Type:        "boolean",
+					Description: "Filter by active status",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List User Criteria",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listUserCriteria)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Create User Criteria
+		r.registerTool(server, mcp.Tool{
+			Name:        "create_user_criteria",
+			Description: "Create a user criteria record matching users, groups, roles, departments, locations, or companies. Assign it to a knowledge base with add_kb_user_criteria.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"name": {
+						Type:        "string",
+						Description: "Criteria name (e.g., 'Network team')",
+					},
+					"users": {
+						Type:        "array",
+						Description: "sys_ids of users matched",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"groups": {
+						Type:        "array",
+						Description: "sys_ids of groups whose members are matched",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"roles": {
+						Type:        "array",
+						Description: "sys_ids of roles whose holders are matched",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"departments": {
+						Type:        "array",
+						Description: "sys_ids of departments whose users are matched",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"locations": {
+						Type:        "array",
+						Description: "sys_ids of locations whose users are matched",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"companies": {
+						Type:        "array",
+						Description: "sys_ids of companies whose users are matched",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"match_all": {
+						Type:        "boolean",
+						Description: "Match only users meeting every condition, rather than any one",
+						Default:     false,
+					},
+				},
+				Required: []string{"name"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create User Criteria",
+			},
+		}, (*Registry).createUserCriteria)
+		count++
+
+		// Add KB User Criteria
+		r.registerTool(server, mcp.Tool{
+			Name:        "add_kb_user_criteria",
+			Description: "Grant or deny access to a knowledge base by adding user criteria to its can read, can contribute, cannot read, or cannot contribute list.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"knowledge_base": {
+						Type:        "string",
+						Description: "Knowledge base sys_id or title",
+					},
+					"access": {
+						Type:        "string",
+						Description: "List to add the criteria to",
+						Enum:        kbAccessTypes,
+					},
+					"criteria_id": {
+						Type:        "string",
+						Description: "User criteria sys_id or name",
+					},
+				},
+				Required: []string{"knowledge_base", "access", "criteria_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:          "Add KB User Criteria",
+				IdempotentHint: true,
+			},
+		}, (*Registry).addKBUserCriteria)
+		count++
+
+		// Remove KB User Criteria
+		r.registerTool(server, mcp.Tool{
+			Name:        "remove_kb_user_criteria",
+			Description: "Remove user criteria from a knowledge base's can read, can contribute, cannot read, or cannot contribute list. The criteria record itself is kept.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"knowledge_base": {
+						Type:        "string",
+						Description: "Knowledge base sys_id or title",
+					},
+					"access": {
+						Type:        "string",
+						Description: "List to remove the criteria from",
+						Enum:        kbAccessTypes,
+					},
+					"criteria_id": {
+						Type:        "string",
+						Description: "User criteria sys_id or name",
+					},
+				},
+				Required: []string{"knowledge_base", "access", "criteria_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Remove KB User Criteria",
+				DestructiveHint: true,
+			},
+		}, (*Registry).removeKBUserCriteria)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) getKBAccess(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	kbID := GetStringArg(args, "knowledge_base", "")
+	articleID := GetStringArg(args, "article_id", "")
+	if kbID == "" && articleID == "" {
+		return JSONResult(NewErrorResponse("knowledge_base or article_id is required", nil)), nil
+	}
+
+	var article map[string]interface{}
+	if articleID != "" {
+		var err error
+		article, err = r.getOriginalArticle(articleID, "sys_id,number,short_description,parent,kb_knowledge_base,can_read_user_criteria,cannot_read_user_criteria")
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to find article", err)), nil
+		}
+		if kbID == "" {
+			kbID = FieldValue(article["kb_knowledge_base"])
+		}
+	}
+
+	kb, err := r.findKnowledgeBase(kbID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find knowledge base", err)), nil
+	}
+	kbSysID := FieldValue(kb["sys_id"])
+
+	// Criteria sys_ids by access type, then their records in one request
+	var warnings []string
+	criteriaIDs := map[string][]string{}
+	var allIDs []string
+	for _, access := range kbAccessTypes {
+		result, err := r.client.Get("/table/"+kbAccessTables[access], map[string]string{
+			"sysparm_query":  "kb_knowledge_base=" + kbSysID,
+			"sysparm_fields": "user_criteria",
+			"sysparm_limit":  "500",
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s criteria not read: %v", access, err))
+			continue
+		}
+		for _, record := range GetResultList(result) {
+			if id := FieldValue(record["user_criteria"]); id != "" {
+				criteriaIDs[access] = append(criteriaIDs[access], id)
+				allIDs = append(allIDs, id)
+			}
+		}
+	}
+	// Articles may restrict reading further with their own criteria lists
+	accessTypes := kbAccessTypes
+	if article != nil {
+		accessTypes = append(append([]string{}, kbAccessTypes...), "article_can_read", "article_cannot_read")
+		for accessType, field := range map[string]string{"article_can_read": "can_read_user_criteria", "article_cannot_read": "cannot_read_user_criteria"} {
+			for _, id := range strings.Split(FieldValue(article[field]), ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					criteriaIDs[accessType] = append(criteriaIDs[accessType], id)
+					allIDs = append(allIDs, id)
+				}
+			}
+		}
+	}
+
+	criteria := map[string]map[string]interface{}{}
+	if len(allIDs) > 0 {
+		result, err := r.client.Get("/table/user_criteria", map[string]string{
+			"sysparm_query":                  "sys_idIN" + strings.Join(allIDs, ","),
+			"sysparm_fields":                 userCriteriaFields,
+			"sysparm_display_value":          "all",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  "1000",
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Criteria details not read: %v", err))
+		}
+		for _, record := range GetResultList(result) {
+			criteria[FieldValue(record["sys_id"])] = userCriteriaSummary(record)
+		}
+	}
+
+	access := map[string]interface{}{}
+	var notes []string
+	for _, accessType := range accessTypes {
+		entries := []map[string]interface{}{}
+		for _, id := range criteriaIDs[accessType] {
+			entry, ok := criteria[id]
+			if !ok {
+				entry = map[string]interface{}{"sys_id": id, "name": "(not readable)"}
+			} else if entry["active"] == "false" {
+				notes = append(notes, fmt.Sprintf("%s criteria %q is inactive and matches nobody", accessType, entry["name"]))
+			}
+			entries = append(entries, entry)
+		}
+		access[accessType] = entries
+	}
+	if len(criteriaIDs["can_read"]) == 0 {
+		notes = append(notes, "No can read criteria: all users can read the knowledge base unless a cannot read criteria matches them")
+	}
+	if len(criteriaIDs["cannot_read"]) > 0 {
+		notes = append(notes, "Cannot read criteria override can read: users matching both can't read the knowledge base")
+	}
+	if len(criteriaIDs["article_can_read"]) > 0 {
+		notes = append(notes, "The article's can read criteria apply on top of the knowledge base's: readers must match both")
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Knowledge base %s has %d can read and %d can contribute criteria", FieldDisplay(kb["title"]), len(criteriaIDs["can_read"]), len(criteriaIDs["can_contribute"])),
+		"knowledge_base": map[string]interface{}{
+			"sys_id": kbSysID,
+			"title":  FieldDisplay(kb["title"]),
+			"owner":  FieldDisplay(kb["owner"]),
+			"active": FieldValue(kb["active"]),
+		},
+		"access": access,
+	}
+	if article != nil {
+		response["article"] = map[string]interface{}{
+			"sys_id":            article["sys_id"],
+			"number":            article["number"],
+			"short_description": article["short_description"],
+		}
+		if FieldValue(article["kb_knowledge_base"]) != kbSysID {
+			notes = append(notes, fmt.Sprintf("%s belongs to another knowledge base", FieldValue(article["number"])))
+		}
+	}
+	if len(notes) > 0 {
+		response["notes"] = notes
+	}
+	return JSONResult(withWarnings(response, warnings...)), nil
+}
+
+func (r *Registry) listUserCriteria(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := map[string]string{
+		"sysparm_fields":                 readFields(args, userCriteriaFields),
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 50)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	var filters []string
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
+	}
+	if query := GetStringArg(args, "query", ""); query != "" {
+		filters = append(filters, LikeFilter(query, "name"))
+	}
+	params["sysparm_query"] = strings.Join(append(filters, "ORDERBYname"), "^")
+
+	result, page, err := r.listRecords("/table/user_criteria", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list user criteria", err)), nil
+	}
+
+	criteria := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		criteria = append(criteria, selectFields(userCriteriaSummary(data), data, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":       true,
+		"message":       fmt.Sprintf("Found %d user criteria", len(criteria)),
+		"user_criteria": criteria,
+	}, page)), nil
+}
+
+func (r *Registry) createUserCriteria(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	name := GetStringArg(args, "name", "")
+	if name == "" {
+		return JSONResult(NewErrorResponse("name is required", nil)), nil
+	}
+
+	data := map[string]interface{}{
+		"name":      name,
+		"active":    true,
+		"match_all": GetBoolArg(args, "match_all", false),
+	}
+	matched := 0
+	for _, field := range userCriteriaLists {
+		ids := GetStringArrayArg(args, field)
+		for _, id := range ids {
+			if !IsSysID(id) {
+				return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid %s entry: %s (expected a sys_id)", field, id), nil)), nil
+			}
+		}
+		if len(ids) > 0 {
+			data[field] = strings.Join(ids, ",")
+			matched += len(ids)
+		}
+	}
+	if matched == 0 {
+		return JSONResult(NewErrorResponse("At least one of users, groups, roles, departments, locations, or companies is required", nil)), nil
+	}
+
+	result, err := r.client.Post("/table/user_criteria", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create user criteria", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     fmt.Sprintf("User criteria %s created", name),
+			"criteria_id": resultData["sys_id"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) addKBUserCriteria(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	kb, criteria, table, errResult := r.kbCriteriaArgs(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	kbSysID, criteriaSysID := FieldValue(kb["sys_id"]), FieldValue(criteria["sys_id"])
+	access := GetStringArg(args, "access", "")
+
+	existing, err := r.client.Get("/table/"+table, map[string]string{
+		"sysparm_query":  fmt.Sprintf("kb_knowledge_base=%s^user_criteria=%s", kbSysID, criteriaSysID),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to check existing criteria", err)), nil
+	}
+	if records := GetResultList(existing); len(records) > 0 {
+		return JSONResult(map[string]interface{}{
+			"success": true,
+			"message": fmt.Sprintf("%s is already in the %s criteria of %s", FieldDisplay(criteria["name"]), access, FieldDisplay(kb["title"])),
+		}), nil
+	}
+
+	result, err := r.client.Post("/table/"+table, map[string]interface{}{
+		"kb_knowledge_base": kbSysID,
+		"user_criteria":     criteriaSysID,
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to add user criteria", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     fmt.Sprintf("Added %s to the %s criteria of %s", FieldDisplay(criteria["name"]), access, FieldDisplay(kb["title"])),
+			"relation_id": resultData["sys_id"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) removeKBUserCriteria(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	kb, criteria, table, errResult := r.kbCriteriaArgs(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+	access := GetStringArg(args, "access", "")
+
+	existing, err := r.client.Get("/table/"+table, map[string]string{
+		"sysparm_query":  fmt.Sprintf("kb_knowledge_base=%s^user_criteria=%s", FieldValue(kb["sys_id"]), FieldValue(criteria["sys_id"])),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find criteria relation", err)), nil
+	}
+	records := GetResultList(existing)
+	if len(records) == 0 {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s is not in the %s criteria of %s", FieldDisplay(criteria["name"]), access, FieldDisplay(kb["title"])), nil)), nil
+	}
+
+	if _, err := r.client.Delete(fmt.Sprintf("/table/%s/%s", table, FieldValue(records[0]["sys_id"]))); err != nil {
+		return JSONResult(NewErrorResponse("Failed to remove user criteria", err)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Removed %s from the %s criteria of %s", FieldDisplay(criteria["name"]), access, FieldDisplay(kb["title"])),
+	}), nil
+}
+
+// kbCriteriaArgs resolves the knowledge base, user criteria, and relation table of the
+// add and remove tools, or returns the error result to respond with
+func (r *Registry) kbCriteriaArgs(args map[string]interface{}) (map[string]interface{}, map[string]interface{}, string, *mcp.CallToolResult) {
+	kbID := GetStringArg(args, "knowledge_base", "")
+	access := GetStringArg(args, "access", "")
+	criteriaID := GetStringArg(args, "criteria_id", "")
+	if kbID == "" || access == "" || criteriaID == "" {
+		return nil, nil, "", JSONResult(NewErrorResponse("knowledge_base, access, and criteria_id are required", nil))
+	}
+	table, ok := kbAccessTables[access]
+	if !ok {
+		return nil, nil, "", JSONResult(NewErrorResponse(fmt.Sprintf("Invalid access: %s (use one of %s)", access, strings.Join(kbAccessTypes, ", ")), nil))
+	}
+
+	kb, err := r.findKnowledgeBase(kbID)
+	if err != nil {
+		return nil, nil, "", JSONResult(NewErrorResponse("Failed to find knowledge base", err))
+	}
+
+	query := "name=" + SanitizeQueryValue(criteriaID)
+	if IsSysID(criteriaID) {
+		query = "sys_id=" + criteriaID
+	}
+	result, err := r.client.Get("/table/user_criteria", map[string]string{
+		"sysparm_query":  query,
+		"sysparm_fields": "sys_id,name",
+		"sysparm_limit":  "2",
+	})
+	if err != nil {
+		return nil, nil, "", JSONResult(NewErrorResponse("Failed to find user criteria", err))
+	}
+	records := GetResultList(result)
+	switch {
+	case len(records) == 0:
+		return nil, nil, "", JSONResult(NewNotFoundResponse(fmt.Sprintf("User criteria not found: %s", criteriaID)))
+	case len(records) > 1:
+		return nil, nil, "", JSONResult(NewErrorResponse(fmt.Sprintf("Several user criteria are named %s; give the sys_id", criteriaID), nil))
+	}
+	return kb, records[0], table, nil
+}
+
+// findKnowledgeBase returns a knowledge base by sys_id or title
+func (r *Registry) findKnowledgeBase(kbID string) (map[string]interface{}, error) {
+	query := "title=" + SanitizeQueryValue(kbID)
+	if IsSysID(kbID) {
+		query = "sys_id=" + kbID
+	}
+	result, err := r.client.Get("/table/kb_knowledge_base", map[string]string{
+		"sysparm_query":                  query,
+		"sysparm_fields":                 "sys_id,title,owner,active",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return nil, fmt.Errorf("knowledge base %w: %s", ErrNotFound, kbID)
+	}
+	return records[0], nil
+}
+
+// userCriteriaSummary returns a user_criteria record read with display values "all",
+// listing who it matches by name
+func userCriteriaSummary(record map[string]interface{}) map[string]interface{} {
+	summary := map[string]interface{}{
+		"sys_id":    FieldValue(record["sys_id"]),
+		"name":      FieldDisplay(record["name"]),
+		"active":    FieldValue(record["active"]),
+		"match_all": FieldValue(record["match_all"]),
+	}
+	for _, field := range userCriteriaLists {
+		if names := FieldDisplay(record[field]); names != "" {
+			summary[field] = names
+		}
+	}
+	if FieldValue(record["advanced"]) == "true" {
+		summary["advanced"] = "true (matched by a script; the lists above may not apply)"
+	}
+	return summary
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetKBAccess tests that a knowledge base's criteria are listed by access type with the article's own read criteria
+func TestGetKBAccess(t *testing.T) {
+	const (
+		kbID       = "b0000000000000000000000000000001"
+		itStaff    = "c0000000000000000000000000000001"
+		contractor = "c0000000000000000000000000000002"
+		legacy     = "c0000000000000000000000000000003"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var records []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/kb_knowledge":
+			records = []interface{}{map[string]interface{}{
+				"sys_id": "a1", "number": "KB0010001", "kb_knowledge_base": kbID, "can_read_user_criteria": legacy,
+			}}
+		case "/api/now/table/kb_knowledge_base":
+			records = []interface{}{map[string]interface{}{"sys_id": field(kbID, kbID), "title": field("IT", "IT"), "active": field("true", "true")}}
+		case "/api/now/table/kb_uc_can_read_mtom":
+			records = []interface{}{map[string]interface{}{"user_criteria": itStaff}}
+		case "/api/now/table/kb_uc_cannot_read_mtom":
+			records = []interface{}{map[string]interface{}{"user_criteria": contractor}}
+		case "/api/now/table/kb_uc_can_contribute_mtom", "/api/now/table/kb_uc_cannot_contribute_mtom":
+		case "/api/now/table/user_criteria":
+			if got := r.URL.Query().Get("sysparm_query"); got != "sys_idIN"+itStaff+","+contractor+","+legacy {
+				t.Errorf("Expected the criteria to be read in one request, got %s", got)
+			}
+			records = []interface{}{
+				map[string]interface{}{"sys_id": field(itStaff, itStaff), "name": field("IT staff", "IT staff"), "active": field("true", "true"), "groups": field("g1", "Service Desk")},
+				map[string]interface{}{"sys_id": field(contractor, contractor), "name": field("Contractors", "Contractors"), "active": field("true", "true"), "roles": field("r1", "contractor")},
+				map[string]interface{}{"sys_id": field(legacy, legacy), "name": field("Legacy team", "Legacy team"), "active": field("false", "false")},
+			}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.getKBAccess(map[string]interface{}{"article_id": "KB0010001"})
+	var response struct {
+		Success bool                                `json:"success"`
+		Access  map[string][]map[string]interface{} `json:"access"`
+		Notes   []string                            `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Access["can_read"]) != 1 || response.Access["can_read"][0]["groups"] != "Service Desk" {
+		t.Fatalf("Expected the can read criteria with its groups, got %s", result.Content[0].Text)
+	}
+	if len(response.Access["cannot_read"]) != 1 || len(response.Access["can_contribute"]) != 0 || len(response.Access["article_can_read"]) != 1 {
+		t.Errorf("Expected criteria by access type, including the article's, got %+v", response.Access)
+	}
+	notes := strings.Join(response.Notes, "\n")
+	if !strings.Contains(notes, "inactive") || !strings.Contains(notes, "override") {
+		t.Errorf("Expected notes on the inactive criteria and cannot read precedence, got %v", response.Notes)
+	}
+}
+
+// TestAddKBUserCriteria tests that criteria are related to a knowledge base once
+func TestAddKBUserCriteria(t *testing.T) {
+	const (
+		kbID       = "b0000000000000000000000000000001"
+		criteriaID = "c0000000000000000000000000000001"
+	)
+	var created []map[string]interface{}
+	related := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/now/table/kb_knowledge_base":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + kbID + `", "title": "IT"}]}`))
+		case r.URL.Path == "/api/now/table/user_criteria":
+			if got := r.URL.Query().Get("sysparm_query"); got != "name=IT staff" {
+				t.Errorf("Expected the criteria to be found by name, got %s", got)
+			}
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + criteriaID + `", "name": "IT staff"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/kb_uc_can_contribute_mtom":
+			if related {
+				_, _ = w.Write([]byte(`{"result": [{"sys_id": "m1"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result": []}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/kb_uc_can_contribute_mtom":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			created = append(created, body)
+			related = true
+			_, _ = w.Write([]byte(`{"result": {"sys_id": "m1"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	args := map[string]interface{}{"knowledge_base": "IT", "access": "can_contribute", "criteria_id": "IT staff"}
+	for i := 0; i < 2; i++ {
+		result, _ := registry.addKBUserCriteria(args)
+		if !strings.Contains(result.Content[0].Text, `"success": true`) {
+			t.Fatalf("Expected the criteria to be added, got %s", result.Content[0].Text)
+		}
+	}
+	if len(created) != 1 || created[0]["kb_knowledge_base"] != kbID || created[0]["user_criteria"] != criteriaID {
+		t.Errorf("Expected one relation of the knowledge base and criteria, got %+v", created)
+	}
+
+	result, _ := registry.addKBUserCriteria(map[string]interface{}{"knowledge_base": "IT", "access": "can_write", "criteria_id": "IT staff"})
+	if !strings.Contains(result.Content[0].Text, "Invalid access") {
+		t.Errorf("Expected an unknown access type to be rejected, got %s", result.Content[0].Text)
+	}
+}
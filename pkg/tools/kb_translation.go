@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// Translated knowledge articles are kb_knowledge records with their own language
+// whose parent field references the original article
+const (
+	kbTranslationFields = "sys_id,number,language,parent,workflow_state,short_description,kb_knowledge_base,kb_category"
+	languagePattern     = `^[a-z]{2}(-[a-z]{2})?$`
+)
+
+// registerKBTranslationTools registers tools for translated versions of knowledge articles
+func (r *Registry) registerKBTranslationTools(server *mcp.Server) int {
+	count := 0
+
+	offsetMin := float64(0)
+
+	// List Article Translations
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_article_translations",
+		Description: "List the languages a knowledge article is available in: the original article and its translated versions.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"article_id": {
+					Type:        "string",
+					Description: "Article number (e.g., 'KB0010001') or sys_id of the original or any translation. Accepts both formats.",
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+			Required: []string{"article_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Article Translations",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listArticleTranslations)
+	count++
+
+	// Get Article Translation
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_article_translation",
+		Description: "Get a knowledge article in a specific language, including full content. Published versions are preferred over drafts.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"article_id": {
+					Type:        "string",
+					Description: "Article number (e.g., 'KB0010001') or sys_id of the original or any translation. Accepts both formats.",
+				},
+				"language": {
+					Type:        "string",
+					Description: "Language code (e.g., 'en', 'fr', 'de', 'ja')",
+					Pattern:     languagePattern,
+				},
+			}),
+			Required: []string{"article_id", "language"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Article Translation",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getArticleTranslation)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Create Article Translation
+		r.registerTool(server, mcp.Tool{
+			Name:        "create_article_translation",
+			Description: "Create a draft translation of a knowledge article in another language. The draft is placed in the same knowledge base and category; title and text default to the original's for the translator to replace.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"article_id": {
+						Type:        "string",
+						Description: "Original article number (e.g., 'KB0010001') or sys_id",
+					},
+					"language": {
+						Type:        "string",
+						Description: "Language code of the translation (e.g., 'fr', 'de', 'ja')",
+						Pattern:     languagePattern,
+					},
+					"short_description": {
+						Type:        "string",
+						Description: "Translated title (defaults to the original title)",
+					},
+					"text": {
+						Type:        "string",
+						Description: "Translated article body in HTML (defaults to the original text)",
+					},
+				},
+				Required: []string{"article_id", "language"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create Article Translation",
+			},
+		}, (*Registry).createArticleTranslation)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listArticleTranslations(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	articleID := GetStringArg(args, "article_id", "")
+	if articleID == "" {
+		return JSONResult(NewErrorResponse("article_id is required", nil)), nil
+	}
+
+	original, err := r.getOriginalArticle(articleID, kbTranslationFields)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find article", err)), nil
+	}
+
+	result, page, err := r.listRecords("/table/kb_knowledge", map[string]string{
+		"sysparm_query":  fmt.Sprintf("parent=%s^ORDERBYlanguage", original["sys_id"]),
+		"sysparm_fields": readFields(args, kbTranslationFields),
+		"sysparm_limit":  "100",
+		"sysparm_offset": fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list translations", err)), nil
+	}
+
+	languages := []string{FieldValue(original["language"])}
+	translations := []map[string]interface{}{}
+	for _, record := range GetResultList(result) {
+		translations = append(translations, selectFields(kbTranslationSummary(record), record, args))
+		languages = append(languages, FieldValue(record["language"]))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":      true,
+		"message":      fmt.Sprintf("Found %d translations", len(translations)),
+		"original":     kbTranslationSummary(original),
+		"translations": translations,
+		"languages":    languages,
+	}, page)), nil
+}
+
+func (r *Registry) getArticleTranslation(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	articleID := GetStringArg(args, "article_id", "")
+	language := GetStringArg(args, "language", "")
+	if articleID == "" || language == "" {
+		return JSONResult(NewErrorResponse("article_id and language are required", nil)), nil
+	}
+
+	original, err := r.getOriginalArticle(articleID, "sys_id,number,parent")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find article", err)), nil
+	}
+
+	language = SanitizeQueryValue(language)
+	result, err := r.client.Get("/table/kb_knowledge", map[string]string{
+		"sysparm_query": fmt.Sprintf("sys_id=%[1]s^language=%[2]s^NQparent=%[1]s^language=%[2]s",
+			original["sys_id"], language),
+		"sysparm_limit":                  "10",
+		"sysparm_fields":                 readFields(args, kbTranslationFields+",text,author,sys_updated_on"),
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get article translation", err)), nil
+	}
+
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("%s has no %s translation", original["number"], language))), nil
+	}
+
+	article := records[0]
+	for _, record := range records {
+		if FieldValue(record["workflow_state"]) == "published" {
+			article = record
+			break
+		}
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %s version of %s", language, original["number"]),
+		"article": selectFields(article, article, args),
+	}), nil
+}
+
+func (r *Registry) createArticleTranslation(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	articleID := GetStringArg(args, "article_id", "")
+	language := GetStringArg(args, "language", "")
+	if articleID == "" || language == "" {
+		return JSONResult(NewErrorResponse("article_id and language are required", nil)), nil
+	}
+
+	original, err := r.getOriginalArticle(articleID, kbTranslationFields+",text")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find article", err)), nil
+	}
+	if FieldValue(original["language"]) == language {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s is already written in %s", original["number"], language), nil)), nil
+	}
+
+	language = SanitizeQueryValue(language)
+	existing, err := r.client.Get("/table/kb_knowledge", map[string]string{
+		"sysparm_query":  fmt.Sprintf("parent=%s^language=%s", original["sys_id"], language),
+		"sysparm_fields": "sys_id,number",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to check existing translations", err)), nil
+	}
+	if records := GetResultList(existing); len(records) > 0 {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s already has a %s translation: %s", original["number"], language, records[0]["number"]), nil)), nil
+	}
+
+	data := map[string]interface{}{
+		"parent":            original["sys_id"],
+		"language":          language,
+		"kb_knowledge_base": FieldValue(original["kb_knowledge_base"]),
+		"short_description": GetStringArg(args, "short_description", FieldValue(original["short_description"])),
+		"text":              GetStringArg(args, "text", FieldValue(original["text"])),
+		"workflow_state":    "draft",
+	}
+	if category := FieldValue(original["kb_category"]); category != "" {
+		data["kb_category"] = category
+	}
+
+	result, err := r.client.Post("/table/kb_knowledge", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create article translation", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":        true,
+			"message":        fmt.Sprintf("Draft %s translation of %s created", language, original["number"]),
+			"article_id":     resultData["sys_id"],
+			"article_number": resultData["number"],
+			"original_id":    original["sys_id"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+// getOriginalArticle returns the given fields of the original article for an article
+// number or sys_id, following the parent reference when the article is a translation
+func (r *Registry) getOriginalArticle(articleID, fields string) (map[string]interface{}, error) {
+	query := fmt.Sprintf("number=%s", SanitizeQueryValue(articleID))
+	if IsSysID(articleID) {
+		query = fmt.Sprintf("sys_id=%s", articleID)
+	}
+
+	// Translations reference the original directly, so at most one parent is followed
+	for followed := false; ; followed = true {
+		result, err := r.client.Get("/table/kb_knowledge", map[string]string{
+			"sysparm_query":  query,
+			"sysparm_fields": fields,
+			"sysparm_limit":  "1",
+		})
+		if err != nil {
+			return nil, err
+		}
+		records := GetResultList(result)
+		if len(records) == 0 {
+			return nil, fmt.Errorf("article %w: %s", ErrNotFound, articleID)
+		}
+		parent := FieldValue(records[0]["parent"])
+		if parent == "" || followed {
+			return records[0], nil
+		}
+		query = fmt.Sprintf("sys_id=%s", parent)
+	}
+}
+
+// kbTranslationSummary returns the fields identifying one language version of an article
+func kbTranslationSummary(record map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"sys_id":            record["sys_id"],
+		"number":            record["number"],
+		"language":          FieldValue(record["language"]),
+		"workflow_state":    record["workflow_state"],
+		"short_description": record["short_description"],
+	}
+}
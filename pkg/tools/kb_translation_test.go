@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCreateArticleTranslation tests that a draft translation is created from the original article
+// when called with the number of an existing translation
+func TestCreateArticleTranslation(t *testing.T) {
+	const originalID = "11111111111111111111111111111111"
+	var created map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		switch {
+		case r.Method == http.MethodGet && query == "number=KB0010002":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"sys_id": "fr1", "number": "KB0010002", "language": "fr", "parent": originalID},
+			}})
+		case r.Method == http.MethodGet && query == "sys_id="+originalID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{
+					"sys_id": originalID, "number": "KB0010001", "language": "en", "parent": "",
+					"kb_knowledge_base": "kb1", "kb_category": "cat1",
+					"short_description": "Reset your password", "text": "<p>Steps</p>",
+				},
+			}})
+		case r.Method == http.MethodGet && query == "parent="+originalID+"^language=de":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/kb_knowledge":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "de1", "number": "KB0010003"}})
+		default:
+			t.Errorf("Unexpected request %s %s?%s", r.Method, r.URL.Path, query)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.createArticleTranslation(map[string]interface{}{
+		"article_id":        "KB0010002",
+		"language":          "de",
+		"short_description": "Passwort zurücksetzen",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"article_number": "KB0010003"`) {
+		t.Fatalf("Expected translation result, got %+v", result)
+	}
+	want := map[string]interface{}{
+		"parent": originalID, "language": "de", "kb_knowledge_base": "kb1", "kb_category": "cat1",
+		"short_description": "Passwort zurücksetzen", "text": "<p>Steps</p>", "workflow_state": "draft",
+	}
+	for key, value := range want {
+		if created[key] != value {
+			t.Errorf("Expected %s = %v, got %v", key, value, created[key])
+		}
+	}
+
+	result, _ = registry.createArticleTranslation(map[string]interface{}{"article_id": "KB0010002", "language": "en"})
+	if !strings.Contains(result.Content[0].Text, "already written in en") {
+		t.Errorf("Expected a translation in the original language to be rejected, got %s", result.Content[0].Text)
+	}
+}
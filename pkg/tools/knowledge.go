@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
 )
 
 // registerKnowledgeBaseTools registers all knowledge base tools
@@ -17,12 +17,12 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 	offsetMin := float64(0)
 
 	// List Knowledge Bases
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_knowledge_bases",
 		Description: "List knowledge bases. Knowledge bases are containers for organizing articles by topic or department.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of knowledge bases to return (default: 50)",
@@ -30,28 +30,32 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"active": {
 					Type:        "boolean",
 					Description: "Filter by active status (true = only active, false = only inactive)",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Knowledge Bases",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listKnowledgeBases(args)
-	})
+	}, (*Registry).listKnowledgeBases)
 	count++
 
 	// List Articles
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_knowledge_articles",
 		Description: "List knowledge articles with optional filtering by knowledge base, category, or search query.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of articles to return (default: 20)",
@@ -75,49 +79,45 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 				},
 				"query": {
 					Type:        "string",
-					Description: "Search query (searches title and body text). For advanced filtering, use ServiceNow encoded query syntax (^ for AND, | for OR)",
+					Description: "Text search in title and body text (e.g., 'VPN setup')",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Knowledge Articles",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listKnowledgeArticles(args)
-	})
+	}, (*Registry).listKnowledgeArticles)
 	count++
 
 	// Get Article
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_knowledge_article",
 		Description: "Get detailed information about a specific knowledge article including full content.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"article_id": {
 					Type:        "string",
 					Description: "Article number (e.g., 'KB0010001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
 				},
-			},
+			}),
 			Required: []string{"article_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Knowledge Article",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getKnowledgeArticle(args)
-	})
+	}, (*Registry).getKnowledgeArticle)
 	count++
 
 	// List KB Categories
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_kb_categories",
 		Description: "List knowledge base categories. Categories organize articles within a knowledge base and can be nested.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"knowledge_base": {
 					Type:        "string",
 					Description: "Filter by knowledge base sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6')",
@@ -133,21 +133,25 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
-			},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List KB Categories",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listKBCategories(args)
-	})
+	}, (*Registry).listKBCategories)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Knowledge Base
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_knowledge_base",
 			Description: "Create a new knowledge base. Knowledge bases are containers for organizing articles by topic or department.",
 			InputSchema: mcp.JSONSchema{
@@ -171,13 +175,11 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Knowledge Base",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createKnowledgeBase(args)
-		})
+		}, (*Registry).createKnowledgeBase)
 		count++
 
 		// Create KB Category
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_kb_category",
 			Description: "Create a new category within a knowledge base. Categories can be nested to create a hierarchy.",
 			InputSchema: mcp.JSONSchema{
@@ -201,13 +203,11 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create KB Category",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createKBCategory(args)
-		})
+		}, (*Registry).createKBCategory)
 		count++
 
 		// Create Article
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_knowledge_article",
 			Description: "Create a new knowledge article. Articles are created in draft state and must be published separately.",
 			InputSchema: mcp.JSONSchema{
@@ -235,13 +235,11 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Knowledge Article",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createKnowledgeArticle(args)
-		})
+		}, (*Registry).createKnowledgeArticle)
 		count++
 
 		// Update Article
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_knowledge_article",
 			Description: "Update an existing knowledge article. At least one field besides article_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -269,13 +267,11 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Knowledge Article",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateKnowledgeArticle(args)
-		})
+		}, (*Registry).updateKnowledgeArticle)
 		count++
 
 		// Publish Article
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "publish_knowledge_article",
 			Description: "Publish a knowledge article to make it visible to users. Article must be in draft state.",
 			InputSchema: mcp.JSONSchema{
@@ -291,9 +287,7 @@ func (r *Registry) registerKnowledgeBaseTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Publish Knowledge Article",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.publishKnowledgeArticle(args)
-		})
+		}, (*Registry).publishKnowledgeArticle)
 		count++
 	}
 
@@ -305,19 +299,17 @@ func (r *Registry) listKnowledgeBases(args map[string]interface{}) (*mcp.CallToo
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,title,description,owner,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
-	if active, exists := args["active"]; exists {
-		if active.(bool) {
-			params["sysparm_query"] = "active=true"
-		} else {
-			params["sysparm_query"] = "active=false"
-		}
+	if _, exists := args["active"]; exists {
+		params["sysparm_query"] = fmt.Sprintf("active=%t", GetBoolArg(args, "active", false))
 	}
 
-	result, err := r.client.Get("/table/kb_knowledge_base", params)
+	result, page, err := r.listRecords("/table/kb_knowledge_base", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list knowledge bases", err)), nil
 	}
@@ -326,22 +318,22 @@ func (r *Registry) listKnowledgeBases(args map[string]interface{}) (*mcp.CallToo
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				kbs = append(kbs, map[string]interface{}{
+				kbs = append(kbs, selectFields(map[string]interface{}{
 					"sys_id":      data["sys_id"],
 					"title":       data["title"],
 					"description": data["description"],
 					"owner":       data["owner"],
 					"active":      data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":         true,
 		"message":         fmt.Sprintf("Found %d knowledge bases", len(kbs)),
 		"knowledge_bases": kbs,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) listKnowledgeArticles(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -354,26 +346,27 @@ func (r *Registry) listKnowledgeArticles(args map[string]interface{}) (*mcp.Call
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
 		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_fields":                 readFields(args, "sys_id,number,short_description,kb_knowledge_base,kb_category,workflow_state,sys_view_count,sys_created_on"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if kb != "" {
-		filters = append(filters, fmt.Sprintf("kb_knowledge_base=%s", kb))
+		filters = append(filters, fmt.Sprintf("kb_knowledge_base=%s", SanitizeQueryValue(kb)))
 	}
 	if category != "" {
-		filters = append(filters, fmt.Sprintf("kb_category=%s", category))
+		filters = append(filters, fmt.Sprintf("kb_category=%s", SanitizeQueryValue(category)))
 	}
 	if query != "" {
-		filters = append(filters, fmt.Sprintf("short_descriptionLIKE%s^ORtextLIKE%s", query, query))
+		filters = append(filters, LikeFilter(query, "short_description", "text"))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/kb_knowledge", params)
+	result, page, err := r.listRecords("/table/kb_knowledge", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list knowledge articles", err)), nil
 	}
@@ -382,7 +375,7 @@ func (r *Registry) listKnowledgeArticles(args map[string]interface{}) (*mcp.Call
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				articles = append(articles, map[string]interface{}{
+				articles = append(articles, selectFields(map[string]interface{}{
 					"sys_id":             data["sys_id"],
 					"number":             data["number"],
 					"short_description":  data["short_description"],
@@ -391,18 +384,21 @@ func (r *Registry) listKnowledgeArticles(args map[string]interface{}) (*mcp.Call
 					"workflow_state":     data["workflow_state"],
 					"sys_view_count":     data["sys_view_count"],
 					"sys_created_on":     data["sys_created_on"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":  true,
 		"message":  fmt.Sprintf("Found %d articles", len(articles)),
 		"articles": articles,
-	}), nil
+	}, page)), nil
 }
 
+// knowledgeArticleFields are the fields of the record returned by get_knowledge_article
+const knowledgeArticleFields = "sys_id,number,short_description,text,kb_knowledge_base,kb_category,workflow_state,author,valid_to,sys_view_count,rating,sys_created_on,sys_updated_on"
+
 func (r *Registry) getKnowledgeArticle(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	articleID := GetStringArg(args, "article_id", "")
 	if articleID == "" {
@@ -415,14 +411,16 @@ func (r *Registry) getKnowledgeArticle(args map[string]interface{}) (*mcp.CallTo
 	if IsSysID(articleID) {
 		endpoint = fmt.Sprintf("/table/kb_knowledge/%s", articleID)
 		params = map[string]string{
+			"sysparm_fields":                 readFields(args, knowledgeArticleFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
 	} else {
 		endpoint = "/table/kb_knowledge"
 		params = map[string]string{
-			"sysparm_query":                  fmt.Sprintf("number=%s", articleID),
+			"sysparm_query":                  fmt.Sprintf("number=%s", SanitizeQueryValue(articleID)),
 			"sysparm_limit":                  "1",
+			"sysparm_fields":                 readFields(args, knowledgeArticleFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
@@ -443,16 +441,13 @@ func (r *Registry) getKnowledgeArticle(args map[string]interface{}) (*mcp.CallTo
 	}
 
 	if articleData == nil {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": fmt.Sprintf("Article not found: %s", articleID),
-		}), nil
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Article not found: %s", articleID))), nil
 	}
 
 	return JSONResult(map[string]interface{}{
 		"success": true,
 		"message": "Article found",
-		"article": articleData,
+		"article": selectFields(articleData, articleData, args),
 	}), nil
 }
 
@@ -463,23 +458,25 @@ func (r *Registry) listKBCategories(args map[string]interface{}) (*mcp.CallToolR
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,label,kb_knowledge_base,parent_id,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
 	if kb != "" {
-		filters = append(filters, fmt.Sprintf("kb_knowledge_base=%s", kb))
+		filters = append(filters, fmt.Sprintf("kb_knowledge_base=%s", SanitizeQueryValue(kb)))
 	}
 	if parent != "" {
-		filters = append(filters, fmt.Sprintf("parent_id=%s", parent))
+		filters = append(filters, fmt.Sprintf("parent_id=%s", SanitizeQueryValue(parent)))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/kb_category", params)
+	result, page, err := r.listRecords("/table/kb_category", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list KB categories", err)), nil
 	}
@@ -488,22 +485,22 @@ func (r *Registry) listKBCategories(args map[string]interface{}) (*mcp.CallToolR
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				categories = append(categories, map[string]interface{}{
+				categories = append(categories, selectFields(map[string]interface{}{
 					"sys_id":            data["sys_id"],
 					"label":             data["label"],
 					"kb_knowledge_base": data["kb_knowledge_base"],
 					"parent_id":         data["parent_id"],
 					"active":            data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":    true,
 		"message":    fmt.Sprintf("Found %d categories", len(categories)),
 		"categories": categories,
-	}), nil
+	}, page)), nil
 }
 
 func (r *Registry) createKnowledgeBase(args map[string]interface{}) (*mcp.CallToolResult, error) {
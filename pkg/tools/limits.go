@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetRecordLimits configures the limit argument of list tools. maxLimit caps the records
+// any call reads per request, whatever the tool schema allows (0 means no cap); defaults
+// and maximums are "tool=records" entries (e.g., "list_incidents=10") setting the limit
+// used when a call gives none and the largest limit a call may ask for, per tool.
+func (r *Registry) SetRecordLimits(maxLimit int, defaults, maximums []string) error {
+	if maxLimit < 0 {
+		return fmt.Errorf("limit cap must not be negative, got %d", maxLimit)
+	}
+	perToolDefault, err := parseToolLimits(defaults)
+	if err != nil {
+		return err
+	}
+	perToolMax, err := parseToolLimits(maximums)
+	if err != nil {
+		return err
+	}
+	r.maxLimit, r.defaultLimits, r.maxLimits = maxLimit, perToolDefault, perToolMax
+	return nil
+}
+
+// parseToolLimits parses "tool=records" entries
+func parseToolLimits(entries []string) (map[string]int, error) {
+	limits := map[string]int{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tool limit %q (use tool=records, e.g., list_incidents=10)", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid record count for %s: %q", strings.TrimSpace(name), value)
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+	return limits, nil
+}
+
+// limitCapFor returns the largest limit a call of the named tool may use (0 means no cap)
+func (r *Registry) limitCapFor(name string) int {
+	limit, ok := r.maxLimits[name]
+	if !ok || (r.maxLimit > 0 && limit > r.maxLimit) {
+		return r.maxLimit
+	}
+	return limit
+}
+
+// limitsValidator fills in the configured default limit of a list tool, and lowers a
+// limit above the tool's cap to the cap with a warning
+func (r *Registry) limitsValidator(call *ToolCall) error {
+	property, ok := call.Tool.InputSchema.Properties["limit"]
+	if !ok {
+		return nil
+	}
+	limitCap := r.limitCapFor(call.Tool.Name)
+
+	if _, given := call.Args["limit"]; !given {
+		limit, ok := r.defaultLimits[call.Tool.Name]
+		if !ok {
+			// The schema default is what the handler falls back to
+			schemaDefault, isNumber := CoerceNumber(property.Default)
+			if !isNumber || limitCap <= 0 || int(schemaDefault) <= limitCap {
+				return nil
+			}
+			limit = int(schemaDefault)
+		}
+		if limitCap > 0 && limit > limitCap {
+			limit = limitCap
+		}
+		call.Args["limit"] = float64(limit)
+		return nil
+	}
+
+	if requested := GetIntArg(call.Args, "limit", 0); limitCap > 0 && requested > limitCap {
+		call.Args["limit"] = float64(limitCap)
+		call.Warnings = append(call.Warnings, fmt.Sprintf("Warning: limit lowered from %d to %d, the most records this server returns per request for %s", requested, limitCap, call.Tool.Name))
+	}
+	return nil
+}
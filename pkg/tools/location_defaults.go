@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// requesterLocation is where the user a record is raised for sits, from their sys_user record
+type requesterLocation struct {
+	LocationID   string `json:"location_id,omitempty"`
+	Location     string `json:"location,omitempty"`
+	DepartmentID string `json:"department_id,omitempty"`
+	Department   string `json:"department,omitempty"`
+	Address      string `json:"address,omitempty"`
+}
+
+// locationConditionFields and departmentConditionFields are the fields an assignment
+// rule condition routes by location or department through
+var (
+	locationConditionFields   = []string{"location", "caller_id.location", "requested_for.location", "opened_by.location"}
+	departmentConditionFields = []string{"caller_id.department", "requested_for.department", "opened_by.department"}
+)
+
+// resolveLocation resolves a location given as a sys_id or name to a cmn_location sys_id
+func (r *Registry) resolveLocation(location string) (string, error) {
+	return r.resolveReference(cacheLocations, "cmn_location", location, "name=%s", "location")
+}
+
+// userLocation reads the location, department, and location address of a user. It
+// returns nil when the user is not found.
+func (r *Registry) userLocation(userID string) (*requesterLocation, error) {
+	result, err := r.client.Get("/table/sys_user", map[string]string{
+		"sysparm_query":                  "sys_id=" + SanitizeQueryValue(userID),
+		"sysparm_fields":                 "location,department,location.street,location.city,location.state,location.zip,location.country",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return nil, nil
+	}
+	user := records[0]
+
+	var address []string
+	for _, field := range []string{"street", "city", "state", "zip", "country"} {
+		if part := strings.TrimSpace(FieldDisplay(user["location."+field])); part != "" {
+			address = append(address, part)
+		}
+	}
+	return &requesterLocation{
+		LocationID:   FieldValue(user["location"]),
+		Location:     FieldDisplay(user["location"]),
+		DepartmentID: FieldValue(user["department"]),
+		Department:   FieldDisplay(user["department"]),
+		Address:      strings.Join(address, ", "),
+	}, nil
+}
+
+// locationGroup returns the group of the first active assignment rule of table (in
+// rule order) whose condition routes by the location or department, and allows the
+// category when it names categories. It returns nil when no rule matches.
+func (r *Registry) locationGroup(table, category string, location *requesterLocation) (*routingCandidate, error) {
+	result, err := r.client.Get("/table/sysrule_assignment", map[string]string{
+		"sysparm_query":         "active=true^table=" + table + "^ORDERBYorder",
+		"sysparm_fields":        "sys_id,name,condition,group,user",
+		"sysparm_display_value": "all",
+		"sysparm_limit":         fmt.Sprintf("%d", conditionScanLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := func(condition string, fields []string, value string) bool {
+		if value == "" {
+			return false
+		}
+		for _, field := range fields {
+			if values, ok := conditionValues(condition, field); ok && slices.Contains(values, value) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, rule := range GetResultList(result) {
+		condition := FieldValue(rule["condition"])
+		if FieldValue(rule["group"]) == "" {
+			continue
+		}
+		if !conditionAllows(condition, "category", category) {
+			continue
+		}
+		reason := ""
+		switch {
+		case matches(condition, locationConditionFields, location.LocationID):
+			reason = fmt.Sprintf("Assignment rule for location %s", location.Location)
+		case matches(condition, departmentConditionFields, location.DepartmentID):
+			reason = fmt.Sprintf("Assignment rule for department %s", location.Department)
+		default:
+			continue
+		}
+		return &routingCandidate{
+			GroupID:    FieldValue(rule["group"]),
+			GroupName:  FieldDisplay(rule["group"]),
+			AssignedTo: FieldDisplay(rule["user"]),
+			Source:     "location_rule",
+			Rule:       FieldDisplay(rule["name"]),
+			Reason:     reason,
+		}, nil
+	}
+	return nil, nil
+}
+
+// applyLocationDefaults fills the fields of a new record of table that depend on where
+// userID sits: locationField (the user's location) and addressField (its address), and
+// assignment_group from location routing. Fields already in data are kept, and an
+// explicit location is routed instead of the user's. Empty field names are skipped. It
+// returns what was looked up and filled, for the response.
+func (r *Registry) applyLocationDefaults(table, userID string, data map[string]interface{}, locationField, addressField string) (map[string]interface{}, error) {
+	location, err := r.userLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, nil
+	}
+	applied := map[string]interface{}{"requester": location}
+	routed := location
+	if given, ok := data[locationField].(string); ok && locationField != "" && given != location.LocationID {
+		// A location given for the record is routed instead of the user's
+		routed = &requesterLocation{LocationID: given, Location: given, DepartmentID: location.DepartmentID, Department: location.Department}
+	}
+	if _, ok := data[locationField]; !ok && locationField != "" && location.LocationID != "" {
+		data[locationField] = location.LocationID
+		applied[locationField] = location.Location
+	}
+	if _, ok := data[addressField]; !ok && addressField != "" && location.Address != "" {
+		data[addressField] = location.Address
+		applied[addressField] = location.Address
+	}
+	if _, ok := data["assignment_group"]; !ok && (routed.LocationID != "" || routed.DepartmentID != "") {
+		category, _ := data["category"].(string)
+		group, err := r.locationGroup(table, category, routed)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			data["assignment_group"] = group.GroupID
+			applied["assignment_group"] = group
+		}
+	}
+	return applied, nil
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestLocationDefaults tests that new incidents and requests take the location, delivery address, and location-routed group of the user they are for, unless given or turned off
+func TestLocationDefaults(t *testing.T) {
+	const (
+		userID     = "11111111111111111111111111111111"
+		locationID = "22222222222222222222222222222222"
+		groupID    = "33333333333333333333333333333333"
+		deptID     = "44444444444444444444444444444444"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+
+	var created map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		query := r.URL.Query().Get("sysparm_query")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user" && query == "sys_id="+userID:
+			result = []interface{}{map[string]interface{}{
+				"location": field(locationID, "London"), "department": field(deptID, "Finance"),
+				"location.street": field("1 Main St", "1 Main St"), "location.city": field("London", "London"),
+				"location.zip": field("EC1A 1AA", "EC1A 1AA"), "location.country": field("UK", "UK"),
+			}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			result = []interface{}{map[string]interface{}{"sys_id": userID}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sysrule_assignment":
+			result = []interface{}{
+				map[string]interface{}{"name": field("Network London", "Network London"), "condition": field("category=network^location="+locationID, ""), "group": field("n1", "Network EMEA")},
+				map[string]interface{}{"name": field("Paris", "Paris"), "condition": field("caller_id.location=55555555555555555555555555555555", ""), "group": field("p1", "Paris Desk")},
+				map[string]interface{}{"name": field("London", "London"), "condition": field("caller_id.location="+locationID, ""), "group": field(groupID, "London Desk")},
+			}
+		case r.Method == http.MethodPost && (r.URL.Path == "/api/now/table/incident" || r.URL.Path == "/api/now/table/sc_request"):
+			created = nil
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			result = map[string]interface{}{"sys_id": "r1", "number": "INC0010001"}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.createIncident(map[string]interface{}{"short_description": "Printer jammed", "caller_id": "abel.tuter", "category": "hardware"})
+	if created["location"] != locationID || created["assignment_group"] != groupID {
+		t.Errorf("Expected the caller's location and the London group, got %+v", created)
+	}
+	if !strings.Contains(result.Content[0].Text, `"source": "location_rule"`) || !strings.Contains(result.Content[0].Text, "Assignment rule for location London") {
+		t.Errorf("Expected the applied defaults to be reported, got %s", result.Content[0].Text)
+	}
+
+	registry.createIncident(map[string]interface{}{"short_description": "VPN down", "caller_id": "abel.tuter", "category": "network"})
+	if created["assignment_group"] != "n1" {
+		t.Errorf("Expected the category-specific rule to win, got %+v", created)
+	}
+
+	registry.createIncident(map[string]interface{}{"short_description": "Printer jammed", "caller_id": "abel.tuter", "location_defaults": false})
+	if _, ok := created["location"]; ok {
+		t.Errorf("Expected no location with location_defaults off, got %+v", created)
+	}
+
+	registry.createRequest(map[string]interface{}{"short_description": "New laptop", "requested_for": "abel.tuter"})
+	if created["delivery_address"] != "1 Main St, London, EC1A 1AA, UK" || created["assignment_group"] != groupID {
+		t.Errorf("Expected the delivery address and group of requested_for's location, got %+v", created)
+	}
+
+	registry.createRequest(map[string]interface{}{"short_description": "New laptop", "requested_for": "abel.tuter", "delivery_address": "Home"})
+	if created["delivery_address"] != "Home" {
+		t.Errorf("Expected the given delivery address to be kept, got %+v", created)
+	}
+}
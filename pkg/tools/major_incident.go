@@ -0,0 +1,641 @@
+package tools
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// majorIncidentStates are the major incident states (major_incident_state) list_major_incidents filters by
+var majorIncidentStates = []string{"proposed", "accepted", "rejected", "canceled"}
+
+// majorIncidentFields are the incident fields returned for major incidents
+const majorIncidentFields = "sys_id,number,short_description,state,priority,impact,urgency,major_incident_state,assignment_group,assigned_to,cmdb_ci,opened_at,promoted_by,promoted_on"
+
+// escalationImpactUrgency are the impact and urgency escalate_incident sets for each
+// priority under the default priority matrix
+var escalationImpactUrgency = map[string][2]string{
+	"1": {"1", "1"},
+	"2": {"2", "1"},
+	"3": {"2", "2"},
+	"4": {"3", "2"},
+}
+
+// maxChildIncidents bounds the child incidents linked or resolved by one call
+const maxChildIncidents = 100
+
+// registerMajorIncidentTools registers the major incident, escalation, and child incident tools
+func (r *Registry) registerMajorIncidentTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+
+	// List Major Incidents (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_major_incidents",
+		Description: "List major incidents (Major Incident Management), most recently promoted first, with the number of child incidents attached to each. Defaults to active accepted major incidents; use state=proposed for candidates awaiting a major incident manager.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withAutoPaginate(map[string]mcp.Property{
+				"state": {
+					Type:        "string",
+					Description: "Major incident state",
+					Enum:        majorIncidentStates,
+					Default:     "accepted",
+				},
+				"active_only": {
+					Type:        "boolean",
+					Description: "Only return incidents that are still open",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Major Incidents",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listMajorIncidents)
+	count++
+
+	if !r.readOnlyMode {
+		// Promote to Major Incident
+		r.registerTool(server, mcp.Tool{
+			Name:        "promote_to_major_incident",
+			Description: "Promote an open incident to a major incident (or propose it for a major incident manager to accept), record why in a work note, and optionally create communication tasks (e.g., status page, executive updates) on it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"incident_id": {
+						Type:        "string",
+						Description: "Incident number (e.g., 'INC0010001') or sys_id",
+					},
+					"reason": {
+						Type:        "string",
+						Description: "Why the incident is a major incident, added as a work note",
+					},
+					"state": {
+						Type:        "string",
+						Description: "'accepted' to promote it, or 'proposed' to propose it for acceptance",
+						Enum:        []string{"accepted", "proposed"},
+						Default:     "accepted",
+					},
+					"communication_tasks": {
+						Type:        "array",
+						Description: "Communication tasks to create on the incident, one per audience or channel (e.g., 'Status page update', 'Executive briefing')",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"communication_group": {
+						Type:        "string",
+						Description: "Assignment group (name or sys_id) of the communication tasks",
+					},
+				},
+				Required: []string{"incident_id", "reason"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Promote to Major Incident",
+			},
+		}, (*Registry).promoteToMajorIncident)
+		count++
+
+		// Escalate Incident
+		r.registerTool(server, mcp.Tool{
+			Name:        "escalate_incident",
+			Description: "Escalate an incident: raise its priority (one level, or to the given priority) through impact and urgency, optionally reassign it, and add a work note with the reason. The priority is read back, since the instance's priority lookup decides it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"incident_id": {
+						Type:        "string",
+						Description: "Incident number (e.g., 'INC0010001') or sys_id",
+					},
+					"reason": {
+						Type:        "string",
+						Description: "Why the incident is escalated, added as a work note",
+					},
+					"priority": {
+						Type:        "string",
+						Description: "Priority to escalate to (default: one level above the current one)",
+						Enum:        []string{"1", "2", "3", "4"},
+					},
+					"assignment_group": {
+						Type:        "string",
+						Description: "Group to reassign the incident to (name or sys_id)",
+					},
+					"assigned_to": {
+						Type:        "string",
+						Description: "User to reassign the incident to (user name, email, or sys_id)",
+					},
+				},
+				Required: []string{"incident_id", "reason"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Escalate Incident",
+			},
+		}, (*Registry).escalateIncident)
+		count++
+
+		// Link Child Incidents
+		r.registerTool(server, mcp.Tool{
+			Name:        "link_child_incidents",
+			Description: fmt.Sprintf("Make incidents children of a parent incident (e.g., duplicates of a major incident) by setting their parent_incident, in one batch of up to %d incidents. Each child is reported separately.", maxChildIncidents),
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"parent_id": {
+						Type:        "string",
+						Description: "Parent incident number (e.g., 'INC0010001') or sys_id",
+					},
+					"child_ids": {
+						Type:        "array",
+						Description: "Child incident numbers or sys_ids",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"work_note": {
+						Type:        "string",
+						Description: "Work note to add to each child (default: a note naming the parent)",
+					},
+				},
+				Required: []string{"parent_id", "child_ids"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Link Child Incidents",
+			},
+		}, (*Registry).linkChildIncidents)
+		count++
+
+		// Resolve Child Incidents
+		r.registerTool(server, mcp.Tool{
+			Name:        "resolve_child_incidents",
+			Description: fmt.Sprintf("Resolve the open child incidents of a parent incident in one batch (up to %d per call), with the given resolution code and notes or, by default, those of the resolved parent.", maxChildIncidents),
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"parent_id": {
+						Type:        "string",
+						Description: "Parent incident number (e.g., 'INC0010001') or sys_id",
+					},
+					"resolution_code": {
+						Type:        "string",
+						Description: "Resolution code (default: the parent's)",
+					},
+					"resolution_notes": {
+						Type:        "string",
+						Description: "Resolution notes (default: the parent's)",
+					},
+				},
+				Required: []string{"parent_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Resolve Child Incidents",
+			},
+		}, (*Registry).resolveChildIncidents)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listMajorIncidents(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	state := GetStringArg(args, "state", "accepted")
+	if !slices.Contains(majorIncidentStates, state) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid state: %s (use one of %s)", state, strings.Join(majorIncidentStates, ", ")), nil)), nil
+	}
+
+	query := "major_incident_state=" + state
+	if GetBoolArg(args, "active_only", true) {
+		query += "^active=true"
+	}
+	params := map[string]string{
+		"sysparm_query":                  query + "^ORDERBYDESCpromoted_on^ORDERBYDESCopened_at",
+		"sysparm_fields":                 majorIncidentFields,
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+	result, page, err := r.listRecords("/table/incident", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list major incidents", err)), nil
+	}
+	records := GetResultList(result)
+
+	// Child incidents are counted per parent in one aggregate call
+	children := map[string]int{}
+	if len(records) > 0 {
+		ids := make([]string, len(records))
+		for i, record := range records {
+			ids[i] = FieldValue(record["sys_id"])
+		}
+		stats, err := r.client.Get("/stats/incident", map[string]string{
+			"sysparm_query":    "parent_incidentIN" + strings.Join(ids, ","),
+			"sysparm_group_by": "parent_incident",
+			"sysparm_count":    "true",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to count child incidents", err)), nil
+		}
+		for _, group := range GetResultList(stats) {
+			fields, _ := group["groupby_fields"].([]interface{})
+			if len(fields) == 0 {
+				continue
+			}
+			field, _ := fields[0].(map[string]interface{})
+			parent, _ := field["value"].(string)
+			stat, _ := group["stats"].(map[string]interface{})
+			if n, ok := CoerceNumber(stat["count"]); ok {
+				children[parent] = int(n)
+			}
+		}
+	}
+
+	incidents := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		incidents[i] = majorIncidentSummary(record)
+		incidents[i]["child_incidents"] = children[FieldValue(record["sys_id"])]
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":   true,
+		"message":   fmt.Sprintf("Found %d %s major incidents", len(incidents), state),
+		"incidents": incidents,
+	}, page)), nil
+}
+
+// majorIncidentSummary returns the fields of a major incident read with sysparm_display_value=all
+func majorIncidentSummary(record map[string]interface{}) map[string]interface{} {
+	summary := map[string]interface{}{
+		"sys_id":               FieldValue(record["sys_id"]),
+		"number":               FieldValue(record["number"]),
+		"short_description":    FieldValue(record["short_description"]),
+		"state":                FieldDisplay(record["state"]),
+		"priority":             FieldDisplay(record["priority"]),
+		"major_incident_state": FieldValue(record["major_incident_state"]),
+		"assignment_group":     FieldDisplay(record["assignment_group"]),
+		"assigned_to":          FieldDisplay(record["assigned_to"]),
+		"cmdb_ci":              FieldDisplay(record["cmdb_ci"]),
+		"opened_at":            FieldDisplay(record["opened_at"]),
+	}
+	if promoted := FieldDisplay(record["promoted_on"]); promoted != "" {
+		summary["promoted_on"] = promoted
+		summary["promoted_by"] = FieldDisplay(record["promoted_by"])
+	}
+	return summary
+}
+
+// getIncidentRecord reads an incident by number or sys_id with sysparm_display_value=all
+func (r *Registry) getIncidentRecord(incidentID, fields string) (map[string]interface{}, error) {
+	sysID, err := r.resolveIncidentID(incidentID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.client.Get("/table/incident/"+sysID, map[string]string{
+		"sysparm_fields":                 fields,
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	record, ok := result["result"].(map[string]interface{})
+	if !ok || FieldValue(record["sys_id"]) == "" {
+		return nil, fmt.Errorf("incident %w: %s", ErrNotFound, incidentID)
+	}
+	return record, nil
+}
+
+func (r *Registry) promoteToMajorIncident(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	incidentID := GetStringArg(args, "incident_id", "")
+	reason := GetStringArg(args, "reason", "")
+	if incidentID == "" || reason == "" {
+		return JSONResult(NewErrorResponse("incident_id and reason are required", nil)), nil
+	}
+	state := GetStringArg(args, "state", "accepted")
+	if state != "accepted" && state != "proposed" {
+		return JSONResult(NewErrorResponse("state must be accepted or proposed", nil)), nil
+	}
+
+	incident, err := r.getIncidentRecord(incidentID, majorIncidentFields+",active")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get incident", err)), nil
+	}
+	number := FieldValue(incident["number"])
+	if FieldValue(incident["active"]) == "false" {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Incident %s is closed and can't be promoted", number), nil)), nil
+	}
+	if current := FieldValue(incident["major_incident_state"]); current == "accepted" || current == state {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Incident %s is already %s as a major incident", number, current), nil)), nil
+	}
+
+	// Communication tasks are created after the promotion; their group is resolved first
+	tasks := GetStringArrayArg(args, "communication_tasks")
+	groupID := ""
+	if group := GetStringArg(args, "communication_group", ""); group != "" && len(tasks) > 0 {
+		if groupID, err = r.resolveGroup(group); err != nil {
+			return JSONResult(NewErrorResponse("Failed to resolve communication_group", err)), nil
+		}
+	}
+
+	note := "Promoted to major incident: " + reason
+	if state == "proposed" {
+		note = "Proposed as a major incident: " + reason
+	}
+	sysID := FieldValue(incident["sys_id"])
+	result, err := r.client.Put("/table/incident/"+sysID, map[string]interface{}{
+		"major_incident_state": state,
+		"work_notes":           note,
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to promote incident", err)), nil
+	}
+	updated, _ := result["result"].(map[string]interface{})
+	if got := FieldValue(updated["major_incident_state"]); got != "" && got != state {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Incident %s was not promoted: its major incident state is %s (is Major Incident Management active?)", number, got), nil)), nil
+	}
+
+	created := []map[string]interface{}{}
+	var failed []string
+	for _, task := range tasks {
+		data := map[string]interface{}{
+			"incident":          sysID,
+			"short_description": fmt.Sprintf("Communication: %s", task),
+			"description":       fmt.Sprintf("Communication task for major incident %s: %s", number, FieldValue(incident["short_description"])),
+		}
+		if groupID != "" {
+			data["assignment_group"] = groupID
+		}
+		taskResult, err := r.client.Post("/table/incident_task", data)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", task, err))
+			continue
+		}
+		if taskData, ok := taskResult["result"].(map[string]interface{}); ok {
+			created = append(created, map[string]interface{}{
+				"sys_id":            FieldValue(taskData["sys_id"]),
+				"number":            FieldValue(taskData["number"]),
+				"short_description": data["short_description"],
+			})
+		}
+	}
+
+	response := map[string]interface{}{
+		"success":              true,
+		"message":              fmt.Sprintf("Incident %s %s as a major incident", number, state),
+		"incident_id":          sysID,
+		"incident_number":      number,
+		"major_incident_state": state,
+		"communication_tasks":  created,
+	}
+	if len(failed) > 0 {
+		response["failed_tasks"] = failed
+		response["message"] = fmt.Sprintf("Incident %s %s as a major incident, but %d communication tasks could not be created", number, state, len(failed))
+	}
+	return JSONResult(response), nil
+}
+
+func (r *Registry) escalateIncident(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	incidentID := GetStringArg(args, "incident_id", "")
+	reason := GetStringArg(args, "reason", "")
+	if incidentID == "" || reason == "" {
+		return JSONResult(NewErrorResponse("incident_id and reason are required", nil)), nil
+	}
+
+	incident, err := r.getIncidentRecord(incidentID, "sys_id,number,priority,impact,urgency,active")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get incident", err)), nil
+	}
+	number := FieldValue(incident["number"])
+	if FieldValue(incident["active"]) == "false" {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Incident %s is closed and can't be escalated", number), nil)), nil
+	}
+
+	current := FieldValue(incident["priority"])
+	target := GetStringArg(args, "priority", "")
+	if target == "" {
+		level, ok := CoerceNumber(current)
+		if !ok || level <= 1 {
+			target = "1"
+		} else {
+			target = fmt.Sprintf("%d", int(level)-1)
+		}
+	}
+	levels, ok := escalationImpactUrgency[target]
+	if !ok {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid priority: %s (use 1 to 4)", target), nil)), nil
+	}
+
+	data := map[string]interface{}{}
+	// Priority is only raised, never lowered
+	if current == "" || target < current {
+		data["impact"], data["urgency"] = levels[0], levels[1]
+	}
+	if err := r.setReferenceArgs(args, data, []string{"assigned_to"}, []string{"assignment_group"}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to resolve reference", err)), nil
+	}
+	if len(data) == 0 {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Incident %s is already priority %s; give assignment_group or assigned_to to escalate it further", number, FieldDisplay(incident["priority"])), nil)), nil
+	}
+	note := fmt.Sprintf("Escalated: %s", reason)
+	if _, raised := data["impact"]; raised {
+		note = fmt.Sprintf("Escalated to priority %s (%s): %s", target, priorityLabels[target], reason)
+	}
+	data["work_notes"] = note
+
+	result, err := r.client.Put("/table/incident/"+FieldValue(incident["sys_id"]), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to escalate incident", err)), nil
+	}
+	updated, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+
+	response := map[string]interface{}{
+		"success":           true,
+		"message":           fmt.Sprintf("Incident %s escalated", number),
+		"incident_id":       FieldValue(incident["sys_id"]),
+		"incident_number":   number,
+		"previous_priority": current,
+		"priority":          FieldValue(updated["priority"]),
+		"work_note":         note,
+	}
+	if got := FieldValue(updated["priority"]); got != "" && data["impact"] != nil && got != target {
+		withWarnings(response, fmt.Sprintf("The instance's priority lookup set priority %s instead of %s for impact %s and urgency %s", got, target, levels[0], levels[1]))
+	}
+	return JSONResult(response), nil
+}
+
+func (r *Registry) linkChildIncidents(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	parentID := GetStringArg(args, "parent_id", "")
+	childIDs := GetStringArrayArg(args, "child_ids")
+	if parentID == "" || len(childIDs) == 0 {
+		return JSONResult(NewErrorResponse("parent_id and child_ids are required", nil)), nil
+	}
+	if len(childIDs) > maxChildIncidents {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("At most %d child incidents can be linked per call", maxChildIncidents), nil)), nil
+	}
+
+	parent, err := r.getIncidentRecord(parentID, "sys_id,number")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get parent incident", err)), nil
+	}
+	parentSysID := FieldValue(parent["sys_id"])
+	parentNumber := FieldValue(parent["number"])
+
+	// Children given by number are looked up together
+	sysIDs, err := r.resolveRecordNumbers("incident", childIDs)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to look up child incidents", err)), nil
+	}
+
+	note := GetStringArg(args, "work_note", "")
+	if note == "" {
+		note = fmt.Sprintf("Linked as a child of %s", parentNumber)
+	}
+	var ids []string
+	var requests []servicenow.BatchRequest
+	var rejected []batchItemResult
+	for _, id := range childIDs {
+		sysID := sysIDs[id]
+		switch {
+		case sysID == "":
+			rejected = append(rejected, batchItemResult{ID: id, Error: "incident not found"})
+		case sysID == parentSysID:
+			rejected = append(rejected, batchItemResult{ID: id, Error: "the parent can't be its own child"})
+		default:
+			ids = append(ids, id)
+			requests = append(requests, servicenow.BatchRequest{
+				Method:   http.MethodPut,
+				Endpoint: "/table/incident/" + sysID,
+				Body:     map[string]interface{}{"parent_incident": parentSysID, "work_notes": note},
+			})
+		}
+	}
+
+	results := []batchItemResult{}
+	linked := 0
+	if len(requests) > 0 {
+		results, linked = r.runBatch(ids, requests)
+	}
+	results = append(results, rejected...)
+
+	message := fmt.Sprintf("Linked %d child incidents to %s", linked, parentNumber)
+	if linked < len(childIDs) {
+		message = fmt.Sprintf("Linked %d of %d child incidents to %s; see warnings for the failures", linked, len(childIDs), parentNumber)
+	}
+	return JSONResult(withWarnings(map[string]interface{}{
+		"success":       linked > 0,
+		"message":       message,
+		"parent_id":     parentSysID,
+		"parent_number": parentNumber,
+		"results":       results,
+	}, batchWarnings(results)...)), nil
+}
+
+func (r *Registry) resolveChildIncidents(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	parentID := GetStringArg(args, "parent_id", "")
+	if parentID == "" {
+		return JSONResult(NewErrorResponse("parent_id is required", nil)), nil
+	}
+	parent, err := r.getIncidentRecord(parentID, "sys_id,number,close_code,close_notes")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get parent incident", err)), nil
+	}
+	parentSysID := FieldValue(parent["sys_id"])
+	parentNumber := FieldValue(parent["number"])
+
+	// The resolution defaults to the parent's
+	code := GetStringArg(args, "resolution_code", FieldValue(parent["close_code"]))
+	notes := GetStringArg(args, "resolution_notes", FieldValue(parent["close_notes"]))
+	if code == "" || notes == "" {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s has no resolution to copy; give resolution_code and resolution_notes", parentNumber), nil)), nil
+	}
+
+	// Resolved (6), Closed (7), and Canceled (8) children are left alone
+	result, total, err := r.client.GetWithTotalCount("/table/incident", map[string]string{
+		"sysparm_query":  "parent_incident=" + parentSysID + "^stateNOT IN6,7,8^ORDERBYnumber",
+		"sysparm_fields": "sys_id,number",
+		"sysparm_limit":  fmt.Sprintf("%d", maxChildIncidents),
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list child incidents", err)), nil
+	}
+	children := GetResultList(result)
+	if len(children) == 0 {
+		return JSONResult(map[string]interface{}{
+			"success":       true,
+			"message":       fmt.Sprintf("%s has no open child incidents", parentNumber),
+			"parent_id":     parentSysID,
+			"parent_number": parentNumber,
+			"results":       []batchItemResult{},
+		}), nil
+	}
+
+	ids := make([]string, len(children))
+	requests := make([]servicenow.BatchRequest, len(children))
+	for i, child := range children {
+		ids[i] = FieldValue(child["number"])
+		requests[i] = servicenow.BatchRequest{
+			Method:   http.MethodPut,
+			Endpoint: "/table/incident/" + FieldValue(child["sys_id"]),
+			Body: map[string]interface{}{
+				"state":       "6", // Resolved
+				"close_code":  code,
+				"close_notes": notes,
+				"resolved_at": "now",
+			},
+		}
+	}
+	results, resolved := r.runBatch(ids, requests)
+
+	message := fmt.Sprintf("Resolved %d child incidents of %s", resolved, parentNumber)
+	if resolved < len(children) {
+		message = fmt.Sprintf("Resolved %d of %d child incidents of %s; see warnings for the failures", resolved, len(children), parentNumber)
+	}
+	response := map[string]interface{}{
+		"success":       resolved > 0,
+		"message":       message,
+		"parent_id":     parentSysID,
+		"parent_number": parentNumber,
+		"results":       results,
+	}
+	if remaining := total - len(children); remaining > 0 {
+		response["remaining"] = remaining
+		response["message"] = message + fmt.Sprintf(" (%d more are open; call again to resolve them)", remaining)
+	}
+	return JSONResult(withWarnings(response, batchWarnings(results)...)), nil
+}
@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestMajorIncidents tests listing major incidents with their child counts, promoting an incident with communication tasks, and escalating one priority level
+func TestMajorIncidents(t *testing.T) {
+	const (
+		incidentID = "11111111111111111111111111111111"
+		groupID    = "22222222222222222222222222222222"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+
+	var listQuery string
+	var updated map[string]interface{}
+	var tasks []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+			listQuery = r.URL.Query().Get("sysparm_query")
+			result = []interface{}{map[string]interface{}{
+				"sys_id": field(incidentID, incidentID), "number": field("INC0010001", "INC0010001"),
+				"priority": field("1", "1 - Critical"), "major_incident_state": field("accepted", "Accepted"),
+			}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/stats/incident":
+			result = []interface{}{map[string]interface{}{
+				"groupby_fields": []interface{}{map[string]interface{}{"field": "parent_incident", "value": incidentID}},
+				"stats":          map[string]interface{}{"count": "4"},
+			}}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident/"+incidentID:
+			result = map[string]interface{}{
+				"sys_id": field(incidentID, incidentID), "number": field("INC0010001", "INC0010001"),
+				"active": field("true", "true"), "priority": field("3", "3 - Moderate"),
+				"major_incident_state": field("", ""), "short_description": field("Email down", "Email down"),
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user_group":
+			result = []interface{}{map[string]interface{}{"sys_id": groupID}}
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/incident/"+incidentID:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			record := map[string]interface{}{"sys_id": incidentID, "priority": "2"}
+			if state, ok := updated["major_incident_state"]; ok {
+				record["major_incident_state"] = state
+			}
+			result = record
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/incident_task":
+			var task map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&task)
+			tasks = append(tasks, task)
+			result = map[string]interface{}{"sys_id": "t1", "number": "IT0010001"}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.listMajorIncidents(map[string]interface{}{})
+	if want := "major_incident_state=accepted^active=true^ORDERBYDESCpromoted_on^ORDERBYDESCopened_at"; listQuery != want {
+		t.Errorf("Expected major incident query %s, got %s", want, listQuery)
+	}
+	if !strings.Contains(result.Content[0].Text, `"child_incidents": 4`) {
+		t.Errorf("Expected 4 child incidents, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.promoteToMajorIncident(map[string]interface{}{
+		"incident_id": incidentID, "reason": "All users affected",
+		"communication_tasks": []interface{}{"Status page update"}, "communication_group": "Comms",
+	})
+	if !strings.Contains(result.Content[0].Text, "INC0010001 accepted as a major incident") {
+		t.Fatalf("Expected the incident to be promoted, got %s", result.Content[0].Text)
+	}
+	if updated["major_incident_state"] != "accepted" || updated["work_notes"] != "Promoted to major incident: All users affected" {
+		t.Errorf("Unexpected promotion payload: %+v", updated)
+	}
+	if len(tasks) != 1 || tasks[0]["incident"] != incidentID || tasks[0]["assignment_group"] != groupID ||
+		tasks[0]["short_description"] != "Communication: Status page update" {
+		t.Errorf("Unexpected communication tasks: %+v", tasks)
+	}
+
+	result, _ = registry.escalateIncident(map[string]interface{}{"incident_id": incidentID, "reason": "VIP affected"})
+	if updated["impact"] != "2" || updated["urgency"] != "1" || !strings.HasPrefix(updated["work_notes"].(string), "Escalated to priority 2") {
+		t.Errorf("Expected an escalation to priority 2, got payload %+v", updated)
+	}
+	if !strings.Contains(result.Content[0].Text, `"priority": "2"`) || strings.Contains(result.Content[0].Text, "warning") {
+		t.Errorf("Expected the escalated priority to be read back, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.escalateIncident(map[string]interface{}{"incident_id": incidentID, "reason": "Again", "priority": "4"})
+	if !strings.Contains(result.Content[0].Text, "already priority") {
+		t.Errorf("Expected a lower priority without reassignment to be refused, got %s", result.Content[0].Text)
+	}
+}
+
+// TestChildIncidents tests linking incidents to a parent, refusing unknown children and the parent itself, and resolving the open children with the parent's resolution
+func TestChildIncidents(t *testing.T) {
+	const (
+		parentID = "11111111111111111111111111111111"
+		childID  = "22222222222222222222222222222222"
+		otherID  = "33333333333333333333333333333333"
+	)
+
+	var mu sync.Mutex
+	var childQuery string
+	updates := map[string]map[string]interface{}{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch {
+		case r.URL.Path == "/api/now/v1/batch":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident/"+parentID:
+			result = map[string]interface{}{
+				"sys_id": parentID, "number": "INC0010001",
+				"close_code": "Solved (Permanently)", "close_notes": "Mail relay restarted",
+			}
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+			query := r.URL.Query().Get("sysparm_query")
+			if strings.HasPrefix(query, "numberIN") {
+				result = []interface{}{map[string]interface{}{"sys_id": childID, "number": "INC0010002"}}
+				break
+			}
+			childQuery = query
+			w.Header().Set("X-Total-Count", "2")
+			result = []interface{}{
+				map[string]interface{}{"sys_id": childID, "number": "INC0010002"},
+				map[string]interface{}{"sys_id": otherID, "number": "INC0010003"},
+			}
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/now/table/incident/"):
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			sysID := strings.TrimPrefix(r.URL.Path, "/api/now/table/incident/")
+			mu.Lock()
+			updates[sysID] = body
+			mu.Unlock()
+			result = map[string]interface{}{"sys_id": sysID}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.linkChildIncidents(map[string]interface{}{
+		"parent_id": parentID, "child_ids": []interface{}{"INC0010002", "INC0099999", parentID},
+	})
+	var response struct {
+		Results []batchItemResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(response.Results) != 3 || !response.Results[0].Success || response.Results[1].Error != "incident not found" ||
+		!strings.Contains(response.Results[2].Error, "its own child") {
+		t.Errorf("Expected one linked child, one unknown, and the parent refused, got %s", result.Content[0].Text)
+	}
+	if updates[childID]["parent_incident"] != parentID || updates[childID]["work_notes"] != "Linked as a child of INC0010001" {
+		t.Errorf("Unexpected link payload: %+v", updates[childID])
+	}
+	if len(updates) != 1 {
+		t.Errorf("Expected only the known child to be updated, got %+v", updates)
+	}
+
+	result, _ = registry.resolveChildIncidents(map[string]interface{}{"parent_id": parentID})
+	if want := "parent_incident=" + parentID + "^stateNOT IN6,7,8^ORDERBYnumber"; childQuery != want {
+		t.Errorf("Expected child query %s, got %s", want, childQuery)
+	}
+	if !strings.Contains(result.Content[0].Text, "Resolved 2 child incidents of INC0010001") {
+		t.Errorf("Expected both children to be resolved, got %s", result.Content[0].Text)
+	}
+	for _, id := range []string{childID, otherID} {
+		if updates[id]["state"] != "6" || updates[id]["close_code"] != "Solved (Permanently)" || updates[id]["close_notes"] != "Mail relay restarted" {
+			t.Errorf("Expected %s to get the parent's resolution, got %+v", id, updates[id])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @user_name and @[Full Name] mentions at the start of the text
+// or after whitespace or an opening parenthesis, so email addresses are left alone.
+// Mentions already in the instance format (@[sys_id:Name]) do not match.
+var mentionPattern = regexp.MustCompile(`(^|[\s(])@(\[[^\]:\r\n]+\]|[A-Za-z0-9_.\-]*[A-Za-z0-9_])`)
+
+// journalFields are the fields whose text is posted to the activity stream
+var journalFields = []string{"work_notes", "comments"}
+
+// expandMentions rewrites @user_name and @[Full Name] mentions in text to the
+// instance's mention format (@[sys_id:Full Name]), which notifies the mentioned
+// users when the note is posted. Mentions that don't identify exactly one active
+// user are left as typed and returned as unresolved.
+func (r *Registry) expandMentions(text string) (string, []string) {
+	matches := mentionPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	var unresolved []string
+	resolved := map[string]string{}
+	last := 0
+	for _, m := range matches {
+		// m[4]:m[5] is the mention without the @
+		token := text[m[4]:m[5]]
+		b.WriteString(text[last:m[4]])
+		last = m[5]
+
+		mention, ok := resolved[token]
+		if !ok {
+			mention = r.lookupMention(token)
+			resolved[token] = mention
+		}
+		if mention == "" {
+			unresolved = append(unresolved, "@"+token)
+			b.WriteString(token)
+			continue
+		}
+		b.WriteString(mention)
+	}
+	b.WriteString(text[last:])
+	return b.String(), unresolved
+}
+
+// lookupMention returns the instance mention format for a mention token, or "" when
+// the token doesn't identify exactly one active user
+func (r *Registry) lookupMention(token string) string {
+	query := fmt.Sprintf("user_name=%s^active=true", SanitizeQueryValue(token))
+	if strings.HasPrefix(token, "[") {
+		query = fmt.Sprintf("name=%s^active=true", SanitizeQueryValue(strings.Trim(token, "[]")))
+	}
+
+	result, err := r.client.Get("/table/sys_user", map[string]string{
+		"sysparm_query":  query,
+		"sysparm_fields": "sys_id,name",
+		"sysparm_limit":  "2",
+	})
+	if err != nil {
+		return ""
+	}
+	records := GetResultList(result)
+	if len(records) != 1 {
+		return ""
+	}
+	return fmt.Sprintf("[%s:%s]", FieldValue(records[0]["sys_id"]), FieldValue(records[0]["name"]))
+}
+
+// expandJournalMentions expands mentions in the work_notes and comments of a record
+// update in place and returns the mentions that could not be resolved
+func (r *Registry) expandJournalMentions(data map[string]interface{}) []string {
+	var unresolved []string
+	for _, field := range journalFields {
+		if text, ok := data[field].(string); ok {
+			expanded, missing := r.expandMentions(text)
+			data[field] = expanded
+			unresolved = append(unresolved, missing...)
+		}
+	}
+	return unresolved
+}
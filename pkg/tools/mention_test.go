@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExpandMentions tests that mentions are rewritten to the instance format and unknown users are reported
+func TestExpandMentions(t *testing.T) {
+	users := map[string]map[string]interface{}{
+		"user_name=abel.tuter^active=true": {"sys_id": "62826bf03710200044e0bfc8bcbe5df1", "name": "Abel Tuter"},
+		"name=Beth Anglin^active=true":     {"sys_id": "46d44a5dc0a8010e0000f3b8b3c1fe34", "name": "Beth Anglin"},
+	}
+
+	lookups := 0
+	var posted map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/now/table/sys_user":
+			lookups++
+			result := []interface{}{}
+			if user, ok := users[r.URL.Query().Get("sysparm_query")]; ok {
+				result = append(result, user)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/now/table/incident/"):
+			_ = json.NewDecoder(r.Body).Decode(&posted)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "inc1", "number": "INC0010001"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	text, unresolved := registry.expandMentions("@abel.tuter please check with @[Beth Anglin] and @nobody. Mail ops@example.com or ping @abel.tuter again; @[62826bf03710200044e0bfc8bcbe5df1:Abel Tuter] is done")
+	want := "@[62826bf03710200044e0bfc8bcbe5df1:Abel Tuter] please check with @[46d44a5dc0a8010e0000f3b8b3c1fe34:Beth Anglin] and @nobody. " +
+		"Mail ops@example.com or ping @[62826bf03710200044e0bfc8bcbe5df1:Abel Tuter] again; @[62826bf03710200044e0bfc8bcbe5df1:Abel Tuter] is done"
+	if text != want {
+		t.Errorf("Unexpected expansion:\n got %s\nwant %s", text, want)
+	}
+	if !reflect.DeepEqual(unresolved, []string{"@nobody"}) {
+		t.Errorf("Expected @nobody to be unresolved, got %v", unresolved)
+	}
+	if lookups != 3 {
+		t.Errorf("Expected each distinct mention to be looked up once, got %d lookups", lookups)
+	}
+
+	result, _ := registry.addIncidentComment(map[string]interface{}{
+		"incident_id":  "6816f79cc0a8016401c5a33be04be441",
+		"comment":      "@[Beth Anglin] can you approve?",
+		"is_work_note": true,
+	})
+	if posted["work_notes"] != "@[46d44a5dc0a8010e0000f3b8b3c1fe34:Beth Anglin] can you approve?" {
+		t.Errorf("Expected the work note to carry the expanded mention, got %v", posted["work_notes"])
+	}
+	if strings.Contains(result.Content[0].Text, "unresolved_mentions") {
+		t.Errorf("Expected no unresolved mentions, got %s", result.Content[0].Text)
+	}
+}
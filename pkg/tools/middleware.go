@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// ToolCall describes a tool call as seen by middleware
+type ToolCall struct {
+	Context context.Context
+	Tool    mcp.Tool
+	Args    map[string]interface{}
+	Start   time.Time
+
+	// Warnings are added to the warnings field of the result after the transformers run
+	Warnings []string
+}
+
+// Validator inspects (and may normalize) a tool call's arguments before its handler
+// runs. A non-nil error rejects the call with an "Invalid arguments" result.
+type Validator interface {
+	Validate(call *ToolCall) error
+}
+
+// Transformer rewrites a successful tool result after its handler runs
+type Transformer interface {
+	Transform(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult
+}
+
+// ValidatorFunc adapts a function to the Validator interface
+type ValidatorFunc func(call *ToolCall) error
+
+// Validate calls f(call)
+func (f ValidatorFunc) Validate(call *ToolCall) error {
+	return f(call)
+}
+
+// TransformerFunc adapts a function to the Transformer interface
+type TransformerFunc func(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult
+
+// Transform calls f(call, result)
+func (f TransformerFunc) Transform(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult {
+	return f(call, result)
+}
+
+// AddValidator appends a validator to the chain run before every tool call.
+// Validators run in the order added, after argument coercion and schema checks.
+func (r *Registry) AddValidator(v Validator) {
+	r.validators = append(r.validators, v)
+}
+
+// AddTransformer appends a transformer to the chain run after every successful
+// tool call. Transformers run in the order added, after usage metadata is attached.
+func (r *Registry) AddTransformer(t Transformer) {
+	r.transformers = append(r.transformers, t)
+}
+
+// callTool runs a tool call through the middleware chain around handler
+func (r *Registry) callTool(ctx context.Context, tool mcp.Tool, args map[string]interface{}, handler mcp.ToolHandlerWithContext) (*mcp.CallToolResult, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	ctx = servicenow.ContextWithUsage(ctx)
+	call := &ToolCall{Context: ctx, Tool: tool, Args: args, Start: time.Now()}
+
+	for _, v := range r.validators {
+		if err := v.Validate(call); err != nil {
+			return JSONResult(NewValidationErrorResponse("Invalid arguments", err)), nil
+		}
+	}
+
+	timeout := r.timeoutFor(ctx, tool.Name)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return JSONResult(NewErrorResponse("Tool call cancelled before it started", err)), nil
+	}
+
+	// Write calls collect the records they change for the session index, activity
+	// records, and verify=true read-backs
+	write := tool.Annotations == nil || !tool.Annotations.ReadOnlyHint
+	verify := write && GetBoolArg(call.Args, verifyArg, false)
+	var changes *changeRecorder
+	if write && (r.sessionIndex != nil || r.activityTable != "" || verify) {
+		ctx, changes = contextWithChangeRecorder(ctx)
+		changes.keepFields = verify
+	}
+
+	result, err := handler(ctx, call.Args)
+	if changes != nil && r.sessionIndex != nil {
+		if err := r.sessionIndex.record(ctx, tool.Name, changes); err != nil && r.logger != nil {
+			r.logger.Warn("Failed to update the session index: %v", err)
+		}
+	}
+	if write && r.activityTable != "" {
+		r.logActivity(ctx, call, result, err, changes)
+	}
+	if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s did not finish within %s; narrow the request or run it in the background with start_job", tool.Name, timeout), ctx.Err())), nil
+	}
+	if err != nil || result == nil {
+		return result, err
+	}
+	if verify && !result.IsError {
+		r.verifyUpdates(ctx, call, result, changes)
+	}
+
+	for _, t := range r.transformers {
+		result = t.Transform(call, result)
+	}
+	addWarnings(result, call.Warnings...)
+	return result, nil
+}
+
+// addWarnings adds warnings to the warnings field of a JSON result, the same field
+// withWarnings fills in handlers. Results that aren't a JSON object get them as text
+// content instead.
+func addWarnings(result *mcp.CallToolResult, warnings ...string) {
+	if len(warnings) == 0 {
+		return
+	}
+	var body map[string]interface{}
+	if len(result.Content) > 0 && result.Content[0].Type == "text" && json.Unmarshal([]byte(result.Content[0].Text), &body) == nil {
+		existing, _ := body["warnings"].([]interface{})
+		for _, warning := range warnings {
+			existing = append(existing, strings.TrimPrefix(warning, "Warning: "))
+		}
+		body["warnings"] = existing
+		result.Content[0].Text = JSONResult(body).Content[0].Text
+		return
+	}
+	for _, warning := range warnings {
+		result.Content = append(result.Content, mcp.ContentItem{Type: "text", Text: warning})
+	}
+}
+
+// coerceArgsValidator converts arguments to the types declared in the tool schema
+func coerceArgsValidator(call *ToolCall) error {
+	return coerceArgs(call.Tool.InputSchema, call.Args)
+}
+
+// schemaValidator checks arguments against the constraints declared in the tool schema
+func schemaValidator(call *ToolCall) error {
+	return validateArgs(call.Tool.InputSchema, call.Args)
+}
+
+// usageTransformer attaches ServiceNow API usage observed during the call to the result
+func (r *Registry) usageTransformer(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult {
+	r.attachUsage(call.Context, result)
+	return result
+}
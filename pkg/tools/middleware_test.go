@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestMiddlewareChain tests that validators and transformers run in order around every tool call
+func TestMiddlewareChain(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", true)
+	registry.EnableDiagnosticTools()
+
+	var order []string
+	registry.AddValidator(ValidatorFunc(func(call *ToolCall) error {
+		order = append(order, "validate:"+call.Tool.Name)
+		// Arguments are coerced to their schema types before custom validators run
+		if call.Args["message"] == "blocked" {
+			return errors.New("message is blocked")
+		}
+		return nil
+	}))
+	registry.AddTransformer(TransformerFunc(func(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult {
+		order = append(order, "first")
+		result.Content[0].Text = strings.ToUpper(result.Content[0].Text)
+		return result
+	}))
+	registry.AddTransformer(TransformerFunc(func(call *ToolCall, result *mcp.CallToolResult) *mcp.CallToolResult {
+		order = append(order, "second")
+		result.Content = append(result.Content, mcp.ContentItem{Type: "text", Text: "footer"})
+		return result
+	}))
+
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+	echo, _ := server.Handler("echo")
+
+	result, err := echo(context.Background(), map[string]interface{}{"message": float64(42)})
+	if err != nil || !strings.Contains(result.Content[0].Text, `"MESSAGE": "42"`) || result.Content[1].Text != "footer" {
+		t.Fatalf("Expected transformed echo of the coerced message, got %+v (%v)", result, err)
+	}
+	if strings.Join(order, ",") != "validate:echo,first,second" {
+		t.Errorf("Unexpected middleware order %v", order)
+	}
+
+	order = nil
+	result, _ = echo(context.Background(), map[string]interface{}{"message": "blocked"})
+	if !strings.Contains(result.Content[0].Text, "message is blocked") || len(order) != 1 {
+		t.Errorf("Expected the validator to reject the call before the handler and transformers, got %+v (%v)", result, order)
+	}
+}
+
+// TestToolTimeout tests that the per-tool timeout cancels the call's ServiceNow requests
+func TestToolTimeout(t *testing.T) {
+	cancelled := make(chan bool, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled <- true
+		case <-time.After(5 * time.Second):
+			cancelled <- false
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	registry.EnableDiagnosticTools()
+	if err := registry.SetToolTimeouts(time.Minute, []string{"get_incident=50ms", " sleep = 20ms "}); err != nil {
+		t.Fatalf("Expected valid timeouts, got %v", err)
+	}
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	getIncident, _ := server.Handler("get_incident")
+	start := time.Now()
+	result, _ := getIncident(context.Background(), map[string]interface{}{"incident_id": "INC0010001"})
+	if !strings.Contains(result.Content[0].Text, "get_incident did not finish within 50ms") || time.Since(start) > 2*time.Second {
+		t.Errorf("Expected the call to time out after 50ms, got %s after %s", result.Content[0].Text, time.Since(start))
+	}
+	if !<-cancelled {
+		t.Error("Expected the ServiceNow request to be cancelled")
+	}
+
+	sleep, _ := server.Handler("sleep")
+	result, _ = sleep(context.Background(), map[string]interface{}{"seconds": float64(5)})
+	if !strings.Contains(result.Content[0].Text, "sleep did not finish within 20ms") {
+		t.Errorf("Expected sleep to time out, got %s", result.Content[0].Text)
+	}
+
+	if err := registry.SetToolTimeouts(0, []string{"query_table"}); err == nil {
+		t.Error("Expected an entry without a duration to be rejected")
+	}
+}
+
+// TestUnknownArguments tests that unknown arguments are warned about, rejected in strict
+// mode, and matched to the closest valid argument
+func TestUnknownArguments(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", true)
+	registry.EnableDiagnosticTools()
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+	echo, _ := server.Handler("echo")
+
+	result, _ := echo(context.Background(), map[string]interface{}{"message": "hi", "mesage": "hi"})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"warnings"`) || !strings.Contains(result.Content[0].Text, `\"mesage\" (did you mean \"message\"?)`) {
+		t.Errorf("Expected the call to run with a warning suggesting message, got %+v", result)
+	}
+
+	if err := registry.SetUnknownArgumentMode(UnknownArgumentsStrict); err != nil {
+		t.Fatalf("SetUnknownArgumentMode failed: %v", err)
+	}
+	result, _ = echo(context.Background(), map[string]interface{}{"message": "hi", "verbose": true})
+	if !strings.Contains(result.Content[0].Text, "Invalid arguments") || !strings.Contains(result.Content[0].Text, "valid arguments: message") {
+		t.Errorf("Expected the call to be rejected with the valid arguments, got %s", result.Content[0].Text)
+	}
+
+	if err := registry.SetUnknownArgumentMode(UnknownArgumentsIgnore); err != nil {
+		t.Fatalf("SetUnknownArgumentMode failed: %v", err)
+	}
+	result, _ = echo(context.Background(), map[string]interface{}{"message": "hi", "verbose": true})
+	if len(result.Content) != 1 || strings.Contains(result.Content[0].Text, `"warnings"`) {
+		t.Errorf("Expected unknown arguments to be dropped silently, got %+v", result.Content)
+	}
+	if err := registry.SetUnknownArgumentMode("loose"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+
+	valid := []string{"assigned_to", "assignment_group", "incident_id", "limit", "short_description"}
+	for name, want := range map[string]string{"assignee": "assigned_to", "limt": "limit", "Short_Description": "short_description", "caller": ""} {
+		if got := closestArgument(name, valid); got != want {
+			t.Errorf("closestArgument(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestRecordLimits tests that configured default limits fill in missing limits and that limits above the cap are lowered
+func TestRecordLimits(t *testing.T) {
+	var limits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Path+"="+r.URL.Query().Get("sysparm_limit"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": []}`))
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	if err := registry.SetRecordLimits(20, []string{"list_incidents=5"}, []string{" list_problems = 10 "}); err != nil {
+		t.Fatalf("Expected valid limits, got %v", err)
+	}
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	call := func(name string, args map[string]interface{}) *mcp.CallToolResult {
+		handler, ok := server.Handler(name)
+		if !ok {
+			t.Fatalf("Expected %s to be registered", name)
+		}
+		result, _ := handler(context.Background(), args)
+		return result
+	}
+	call("list_incidents", map[string]interface{}{})
+	result := call("list_incidents", map[string]interface{}{"limit": float64(50)})
+	call("list_problems", map[string]interface{}{"limit": float64(15)})
+	call("list_stories", map[string]interface{}{})
+
+	want := []string{"/api/now/table/incident=5", "/api/now/table/incident=20", "/api/now/table/problem=10", "/api/now/table/rm_story=20"}
+	if strings.Join(limits, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected limits %v, got %v", want, limits)
+	}
+	if text := result.Content[0].Text; !strings.Contains(text, `"warnings"`) || !strings.Contains(text, "limit lowered from 50 to 20") {
+		t.Errorf("Expected a warning about the lowered limit, got %s", text)
+	}
+
+	if err := registry.SetRecordLimits(0, []string{"list_incidents=0"}, nil); err == nil {
+		t.Error("Expected a zero default limit to be rejected")
+	}
+}
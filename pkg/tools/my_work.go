@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// defaultMyWorkTables are the tables list_my_work reads: the common work tables for their
+// full records, then the base task table for work of any other class
+var defaultMyWorkTables = []string{"incident", "sc_task", "change_request", "change_task", "problem", "problem_task", "task"}
+
+// registerMyWorkTools registers the caller's work queue tools
+func (r *Registry) registerMyWorkTools(server *mcp.Server) int {
+	limitMin := float64(1)
+	limitMax := float64(200)
+
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "list_my_work",
+		Description: "List the open work assigned to you across task tables (incidents, catalog tasks, changes, change tasks, problems, problem tasks, and any other task), most recently updated first. Each record appears once, labeled with its class, even when it is read from both the base task table and its own table. Optionally include unassigned work in your groups.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"tables": {
+					Type:        "array",
+					Description: "Task tables to read (default: incident, sc_task, change_request, change_task, problem, problem_task, task). Include task to catch work of any other class.",
+					Items:       &mcp.Property{Type: "string"},
+				},
+				"include_groups": {
+					Type:        "boolean",
+					Description: "Also list unassigned work in your assignment groups",
+					Default:     false,
+				},
+				"active_only": {
+					Type:        "boolean",
+					Description: "Only return work that is still open",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List My Work",
+			ReadOnlyHint: true,
+		},
+	}, r.listMyWork)
+
+	return 1
+}
+
+func (r *Registry) listMyWork(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	tables := GetStringArrayArg(args, "tables")
+	if len(tables) == 0 {
+		tables = defaultMyWorkTables
+	}
+	for _, table := range tables {
+		if !tableNamePattern.MatchString(table) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+		}
+	}
+	limit := GetIntArg(args, "limit", 50)
+
+	userID, _, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify caller", err)), nil
+	}
+	r = r.forContext(ctx)
+
+	active := ""
+	if GetBoolArg(args, "active_only", true) {
+		active = "active=true^"
+	}
+	query := active + "assigned_to=" + userID
+	if GetBoolArg(args, "include_groups", false) {
+		memberships, err := r.client.Get("/table/sys_user_grmember", map[string]string{
+			"sysparm_query":  "user=" + userID,
+			"sysparm_fields": "group",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to get your groups", err)), nil
+		}
+		var groups []string
+		for _, membership := range GetResultList(memberships) {
+			if group := FieldValue(membership["group"]); group != "" {
+				groups = append(groups, group)
+			}
+		}
+		if len(groups) > 0 {
+			query += "^NQ" + active + "assigned_toISEMPTY^assignment_groupIN" + strings.Join(groups, ",")
+		}
+	}
+
+	var records taskRecordSet
+	for _, table := range tables {
+		result, err := r.client.Get("/table/"+table, map[string]string{
+			"sysparm_query":                  query + "^ORDERBYDESCsys_updated_on",
+			"sysparm_fields":                 "sys_id,sys_class_name,number,short_description,state,priority,assigned_to,assignment_group,sys_updated_on",
+			"sysparm_display_value":          "all",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to list your work in %s", table), err)), nil
+		}
+		for _, record := range GetResultList(result) {
+			records.add(table, record)
+		}
+	}
+
+	sort.SliceStable(records.records, func(i, j int) bool {
+		return FieldValue(records.records[i].fields["sys_updated_on"]) > FieldValue(records.records[j].fields["sys_updated_on"])
+	})
+	truncated := len(records.records) > limit
+	if truncated {
+		records.records = records.records[:limit]
+	}
+
+	work := []map[string]interface{}{}
+	byClass := map[string]int{}
+	for _, record := range records.records {
+		reason := "assigned to you"
+		if FieldValue(record.fields["assigned_to"]) != userID {
+			reason = "unassigned in your group"
+		}
+		work = append(work, map[string]interface{}{
+			"sys_id":            FieldValue(record.fields["sys_id"]),
+			"number":            FieldValue(record.fields["number"]),
+			"class":             record.class,
+			"class_label":       record.label,
+			"short_description": FieldValue(record.fields["short_description"]),
+			"state":             FieldDisplay(record.fields["state"]),
+			"priority":          FieldDisplay(record.fields["priority"]),
+			"assignment_group":  FieldDisplay(record.fields["assignment_group"]),
+			"updated_on":        FieldDisplay(record.fields["sys_updated_on"]),
+			"reason":            reason,
+		})
+		byClass[record.class]++
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":   true,
+		"message":   fmt.Sprintf("Found %d work items", len(work)),
+		"work":      work,
+		"by_class":  byClass,
+		"truncated": truncated,
+	}), nil
+}
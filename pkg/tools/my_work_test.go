@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// TestListMyWork tests that work read from both the base task table and its own table is listed once, in its own table's shape and labeled with its class
+func TestListMyWork(t *testing.T) {
+	const (
+		incidentID = "a0000000000000000000000000000001"
+		caseID     = "a0000000000000000000000000000002"
+	)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		var result []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/sys_user":
+			result = []interface{}{map[string]interface{}{"sys_id": "jane"}}
+		case "/api/now/table/sys_user_grmember":
+			result = []interface{}{map[string]interface{}{"group": "network"}}
+		case "/api/now/table/task":
+			if query != "active=true^assigned_to=jane^NQactive=true^assigned_toISEMPTY^assignment_groupINnetwork^ORDERBYDESCsys_updated_on" {
+				t.Errorf("Unexpected query %s", query)
+			}
+			result = []interface{}{
+				map[string]interface{}{
+					"sys_id": field(incidentID, incidentID), "sys_class_name": field("incident", "Incident"),
+					"number": field("INC0010001", "INC0010001"), "assigned_to": field("jane", "Jane"),
+					"sys_updated_on": field("2026-10-14 09:00:00", "2026-10-14 09:00:00"),
+				},
+				map[string]interface{}{
+					"sys_id": field(caseID, caseID), "sys_class_name": field("sn_hr_core_case", "HR Case"),
+					"number": field("HRC0001001", "HRC0001001"), "assigned_to": field("", ""),
+					"sys_updated_on": field("2026-10-15 08:00:00", "2026-10-15 08:00:00"),
+				},
+			}
+		case "/api/now/table/incident":
+			result = []interface{}{map[string]interface{}{
+				"sys_id": field(incidentID, incidentID), "sys_class_name": field("incident", "Incident"),
+				"number": field("INC0010001", "INC0010001"), "assigned_to": field("jane", "Jane"),
+				"priority": field("1", "1 - Critical"), "sys_updated_on": field("2026-10-14 09:00:00", "2026-10-14 09:00:00"),
+			}}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	ctx := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: "jane.doe", Password: "secret"})
+
+	result, _ := registry.listMyWork(ctx, map[string]interface{}{"tables": []interface{}{"task", "incident"}, "include_groups": true})
+	var response struct {
+		Success bool `json:"success"`
+		Work    []struct {
+			Number     string `json:"number"`
+			Class      string `json:"class"`
+			ClassLabel string `json:"class_label"`
+			Priority   string `json:"priority"`
+			Reason     string `json:"reason"`
+		} `json:"work"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Work) != 2 {
+		t.Fatalf("Expected two work items, got %s", result.Content[0].Text)
+	}
+	hrCase, incident := response.Work[0], response.Work[1]
+	if hrCase.Number != "HRC0001001" || hrCase.ClassLabel != "HR Case" || !strings.Contains(hrCase.Reason, "group") {
+		t.Errorf("Expected the group's HR case first, got %+v", hrCase)
+	}
+	if incident.Number != "INC0010001" || incident.Class != "incident" || incident.Priority != "1 - Critical" || incident.Reason != "assigned to you" {
+		t.Errorf("Expected the incident once, as read from incident, got %+v", incident)
+	}
+}
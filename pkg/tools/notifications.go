@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// sys_user.notification choice values
+const (
+	userNotificationDisabled = "1"
+	userNotificationEnabled  = "2"
+)
+
+// registerNotificationTools registers notification preference and device tools
+func (r *Registry) registerNotificationTools(server *mcp.Server) int {
+	count := 0
+
+	// Get Notification Settings
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_notification_settings",
+		Description: "Get a user's notification settings: the sys_user notification switch and email, notification devices (cmn_notif_device), and group email distribution. Lists likely reasons the user isn't receiving ticket emails.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"user_id": {
+					Type:        "string",
+					Description: "User sys_id, user_name (e.g., 'abel.tuter'), or email",
+				},
+			},
+			Required: []string{"user_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Notification Settings",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getNotificationSettings)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Update Notification Settings
+		r.registerTool(server, mcp.Tool{
+			Name:        "update_notification_settings",
+			Description: "Turn all notifications on or off for a user (sys_user notification field).",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"user_id": {
+						Type:        "string",
+						Description: "User sys_id, user_name (e.g., 'abel.tuter'), or email",
+					},
+					"notifications_enabled": {
+						Type:        "boolean",
+						Description: "true to send notifications to the user, false to stop them",
+					},
+				},
+				Required: []string{"user_id", "notifications_enabled"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:          "Update Notification Settings",
+				IdempotentHint: true,
+			},
+		}, (*Registry).updateNotificationSettings)
+		count++
+
+		// Update Notification Device
+		r.registerTool(server, mcp.Tool{
+			Name:        "update_notification_device",
+			Description: "Update a user's notification device (cmn_notif_device), e.g., reactivate it or correct its email address. Get device IDs from get_notification_settings.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"device_id": {
+						Type:        "string",
+						Description: "Notification device sys_id (e.g., 'a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6')",
+					},
+					"active": {
+						Type:        "boolean",
+						Description: "Whether notifications are sent to the device",
+					},
+					"email_address": {
+						Type:        "string",
+						Description: "Email address for email devices (e.g., 'abel.tuter@example.com')",
+					},
+					"phone_number": {
+						Type:        "string",
+						Description: "Phone number for SMS and voice devices (e.g., '+15555550100')",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Device name (e.g., 'Work phone')",
+					},
+				},
+				Required: []string{"device_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:          "Update Notification Device",
+				IdempotentHint: true,
+			},
+		}, (*Registry).updateNotificationDevice)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) getNotificationSettings(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	userID := GetStringArg(args, "user_id", "")
+	if userID == "" {
+		return JSONResult(NewErrorResponse("user_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveUser(userID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find user", err)), nil
+	}
+
+	userResult, err := r.client.Get(fmt.Sprintf("/table/sys_user/%s", sysID), map[string]string{
+		"sysparm_fields":        "sys_id,user_name,name,email,active,notification",
+		"sysparm_display_value": "false",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get user", err)), nil
+	}
+	user, _ := userResult["result"].(map[string]interface{})
+	if user == nil {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("User not found: %s", userID))), nil
+	}
+
+	deviceResult, err := r.client.Get("/table/cmn_notif_device", map[string]string{
+		"sysparm_query":         fmt.Sprintf("user=%s^ORDERBYorder", sysID),
+		"sysparm_fields":        "sys_id,name,type,email_address,phone_number,active,primary_email",
+		"sysparm_display_value": "false",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get notification devices", err)), nil
+	}
+
+	groupResult, err := r.client.Get("/table/sys_user_grmember", map[string]string{
+		"sysparm_query":         fmt.Sprintf("user=%s", sysID),
+		"sysparm_fields":        "group.sys_id,group.name,group.email,group.include_members",
+		"sysparm_display_value": "false",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get group memberships", err)), nil
+	}
+
+	email := FieldValue(user["email"])
+	enabled := FieldValue(user["notification"]) != userNotificationDisabled
+	var issues []string
+	if FieldValue(user["active"]) == "false" {
+		issues = append(issues, "The user is inactive; inactive users receive no notifications")
+	}
+	if !enabled {
+		issues = append(issues, "Notifications are turned off for the user (update_notification_settings with notifications_enabled=true)")
+	}
+	if email == "" {
+		issues = append(issues, "The user has no email address (set it with update_user)")
+	}
+
+	devices := []map[string]interface{}{}
+	activeEmail := false
+	for _, device := range GetResultList(deviceResult) {
+		active := FieldValue(device["active"]) == "true"
+		address := FieldValue(device["email_address"])
+		deviceType := FieldValue(device["type"])
+		if deviceType == "Email" && active && address != "" {
+			activeEmail = true
+		}
+		if FieldValue(device["primary_email"]) == "true" {
+			if !active {
+				issues = append(issues, fmt.Sprintf("The primary email device %q is inactive (update_notification_device with active=true)", FieldValue(device["name"])))
+			}
+			if email != "" && !strings.EqualFold(address, email) {
+				issues = append(issues, fmt.Sprintf("The primary email device sends to %s, not the user's email %s", address, email))
+			}
+		}
+		devices = append(devices, map[string]interface{}{
+			"device_id":     FieldValue(device["sys_id"]),
+			"name":          FieldValue(device["name"]),
+			"type":          deviceType,
+			"email_address": address,
+			"phone_number":  FieldValue(device["phone_number"]),
+			"active":        active,
+			"primary_email": FieldValue(device["primary_email"]) == "true",
+		})
+	}
+	if !activeEmail {
+		issues = append(issues, "The user has no active email device with an address")
+	}
+
+	groups := []map[string]interface{}{}
+	for _, member := range GetResultList(groupResult) {
+		includeMembers := FieldValue(member["group.include_members"]) == "true"
+		groupEmail := FieldValue(member["group.email"])
+		if !includeMembers {
+			issues = append(issues, fmt.Sprintf("Group %q doesn't send its notifications to members (include_members is off); they go to %s", FieldValue(member["group.name"]), groupDistribution(groupEmail)))
+		}
+		groups = append(groups, map[string]interface{}{
+			"group_id":        FieldValue(member["group.sys_id"]),
+			"name":            FieldValue(member["group.name"]),
+			"email":           groupEmail,
+			"include_members": includeMembers,
+		})
+	}
+
+	message := fmt.Sprintf("Notification settings for %s: %d possible delivery issues", FieldValue(user["name"]), len(issues))
+	if len(issues) == 0 {
+		message = fmt.Sprintf("Notification settings for %s look correct", FieldValue(user["name"]))
+	}
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": message,
+		"user": map[string]interface{}{
+			"user_id":               FieldValue(user["sys_id"]),
+			"user_name":             FieldValue(user["user_name"]),
+			"name":                  FieldValue(user["name"]),
+			"email":                 email,
+			"notifications_enabled": enabled,
+		},
+		"devices": devices,
+		"groups":  groups,
+		"issues":  issues,
+	}), nil
+}
+
+// groupDistribution describes where a group's notifications go when members are not included
+func groupDistribution(email string) string {
+	if email == "" {
+		return "nobody, since the group has no email"
+	}
+	return "the group email " + email
+}
+
+func (r *Registry) updateNotificationSettings(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	userID := GetStringArg(args, "user_id", "")
+	if userID == "" {
+		return JSONResult(NewErrorResponse("user_id is required", nil)), nil
+	}
+	if _, exists := args["notifications_enabled"]; !exists {
+		return JSONResult(NewErrorResponse("notifications_enabled is required", nil)), nil
+	}
+
+	sysID, err := r.resolveUser(userID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find user", err)), nil
+	}
+
+	enabled := GetBoolArg(args, "notifications_enabled", true)
+	value := userNotificationDisabled
+	if enabled {
+		value = userNotificationEnabled
+	}
+	if _, err := r.client.Put(fmt.Sprintf("/table/sys_user/%s", sysID), map[string]interface{}{"notification": value}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to update notification settings", err)), nil
+	}
+
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	return JSONResult(map[string]interface{}{
+		"success":               true,
+		"message":               fmt.Sprintf("Notifications turned %s for user %s", state, userID),
+		"user_id":               sysID,
+		"notifications_enabled": enabled,
+	}), nil
+}
+
+func (r *Registry) updateNotificationDevice(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	deviceID := GetStringArg(args, "device_id", "")
+	if deviceID == "" {
+		return JSONResult(NewErrorResponse("device_id is required", nil)), nil
+	}
+	if !IsSysID(deviceID) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid device_id: %s (use the device_id from get_notification_settings)", deviceID), nil)), nil
+	}
+
+	data := map[string]interface{}{}
+	for _, field := range []string{"email_address", "phone_number", "name"} {
+		if v := GetStringArg(args, field, ""); v != "" {
+			data[field] = v
+		}
+	}
+	if _, exists := args["active"]; exists {
+		data["active"] = GetBoolArg(args, "active", true)
+	}
+	if len(data) == 0 {
+		return JSONResult(NewErrorResponse("Provide at least one of active, email_address, phone_number, or name", nil)), nil
+	}
+
+	result, err := r.client.Put(fmt.Sprintf("/table/cmn_notif_device/%s", deviceID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to update notification device", err)), nil
+	}
+	device, _ := result["result"].(map[string]interface{})
+
+	return JSONResult(map[string]interface{}{
+		"success":   true,
+		"message":   fmt.Sprintf("Notification device %s updated", FieldValue(device["name"])),
+		"device_id": deviceID,
+		"updated":   data,
+	}), nil
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetNotificationSettings tests that delivery problems are reported for a user's settings, devices, and groups
+func TestGetNotificationSettings(t *testing.T) {
+	const userID = "11111111111111111111111111111111"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch r.URL.Path {
+		case "/api/now/table/sys_user":
+			result = []interface{}{map[string]interface{}{"sys_id": userID}}
+		case "/api/now/table/sys_user/" + userID:
+			result = map[string]interface{}{
+				"sys_id": userID, "user_name": "abel.tuter", "name": "Abel Tuter",
+				"email": "abel.tuter@example.com", "active": "true", "notification": "1",
+			}
+		case "/api/now/table/cmn_notif_device":
+			result = []interface{}{map[string]interface{}{
+				"sys_id": "d1", "name": "Primary email", "type": "Email",
+				"email_address": "old.address@example.com", "active": "false", "primary_email": "true",
+			}}
+		case "/api/now/table/sys_user_grmember":
+			result = []interface{}{
+				map[string]interface{}{"group.sys_id": "g1", "group.name": "Network", "group.email": "", "group.include_members": "false"},
+				map[string]interface{}{"group.sys_id": "g2", "group.name": "Service Desk", "group.email": "sd@example.com", "group.include_members": "true"},
+			}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.getNotificationSettings(map[string]interface{}{"user_id": "abel.tuter"})
+
+	var body struct {
+		Success bool     `json:"success"`
+		Issues  []string `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil || !body.Success {
+		t.Fatalf("Expected settings, got %s", result.Content[0].Text)
+	}
+
+	issues := strings.Join(body.Issues, "\n")
+	for _, want := range []string{
+		"Notifications are turned off",
+		`primary email device "Primary email" is inactive`,
+		"sends to old.address@example.com, not the user's email abel.tuter@example.com",
+		"no active email device",
+		`Group "Network" doesn't send its notifications to members`,
+	} {
+		if !strings.Contains(issues, want) {
+			t.Errorf("Expected issue %q, got:\n%s", want, issues)
+		}
+	}
+	if strings.Contains(issues, "Service Desk") {
+		t.Errorf("Expected no issue for a group that includes members, got:\n%s", issues)
+	}
+}
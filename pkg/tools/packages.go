@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// FullPackage exposes every tool except the requester self-service tools
+	FullPackage = "full"
+	// NonePackage exposes only the package meta tools
+	NonePackage = "none"
+)
+
+// toolPackage is a named subset of tools for one role
+type toolPackage struct {
+	description string
+	tools       []string
+}
+
+// toolPackages are the selectable tool packages besides FullPackage. Tools not
+// registered (e.g., write tools in read-only mode) are skipped.
+var toolPackages = map[string]toolPackage{
+	RequesterPackage: {
+		description: "Caller-scoped self-service tools for employee-facing assistants",
+		tools: []string{
+			"list_my_incidents", "get_my_incident", "get_my_request_status", "create_my_incident", "add_my_incident_comment",
+		},
+	},
+	"service_desk": {
+		description: "Incident handling, fulfillment tasks, and lookups for service desk agents",
+		tools: []string{
+			"list_my_work", "list_my_approvals", "respond_to_approval", "list_incidents", "get_incident", "get_incident_journal", "get_record_journal", "compute_priority", "create_incident", "update_incident",
+			"add_incident_comment", "resolve_incident", "close_incident", "attach_transcript", "suggest_routing", "triage_context",
+			"list_major_incidents", "promote_to_major_incident", "escalate_incident", "link_child_incidents", "resolve_child_incidents",
+			"get_incident_sla", "list_sla_breaches", "will_breach_soon", "list_sla_definitions", "list_assignment_rules",
+			"list_schedules", "compute_business_duration",
+			"list_catalogs", "list_catalog_items", "get_catalog_item", "create_request", "get_request_approval_report",
+			"list_catalog_tasks", "get_catalog_task", "update_catalog_task", "close_catalog_task",
+			"list_problems", "get_problem", "list_problem_tasks",
+			"list_knowledge_bases", "list_knowledge_articles", "get_knowledge_article",
+			"list_article_translations", "get_article_translation",
+			"list_users", "get_user", "list_groups", "get_ci_relationships",
+			"get_notification_settings", "update_notification_settings", "update_notification_device", "whoami",
+		},
+	},
+	"catalog_builder": {
+		description: "Service catalog design: categories, items, and variables",
+		tools: []string{
+			"list_catalogs", "list_catalog_items", "get_catalog_item", "list_catalog_categories", "list_catalog_item_variables",
+			"create_catalog_category", "update_catalog_category", "update_catalog_item", "create_catalog_item_variable", "delete_catalog_variable",
+			"move_catalog_items", "list_groups", "list_workflows", "get_workflow", "whoami",
+		},
+	},
+	"change_coordinator": {
+		description: "Change requests, change tasks, approvals, and impact analysis",
+		tools: []string{
+			"list_change_requests", "get_change_request", "get_change_approval_chain", "check_change_conflicts", "list_change_schedule", "create_change_request",
+			"update_change_request", "cancel_change", "calculate_change_risk", "add_change_task", "update_change_task", "close_change_task",
+			"submit_change_for_approval", "approve_change", "reject_change", "list_my_approvals", "respond_to_approval", "get_record_journal",
+			"list_incidents", "get_incident", "list_problems", "get_problem",
+			"list_users", "get_user", "list_groups", "get_ci_relationships", "whoami",
+		},
+	},
+	"knowledge_author": {
+		description: "Knowledge bases, categories, articles, translations, and access (user criteria)",
+		tools: []string{
+			"list_knowledge_bases", "list_knowledge_articles", "get_knowledge_article", "list_kb_categories",
+			"create_knowledge_base", "create_kb_category", "create_knowledge_article", "update_knowledge_article",
+			"publish_knowledge_article", "delete_kb_article", "list_article_translations", "get_article_translation", "create_article_translation",
+			"get_kb_access", "list_user_criteria", "create_user_criteria", "add_kb_user_criteria", "remove_kb_user_criteria",
+			"list_incidents", "get_incident", "list_problems", "get_problem", "whoami",
+		},
+	},
+	"platform_developer": {
+		description: "Workflows, flows, script includes, REST messages, update sets, and generic table queries",
+		tools: []string{
+			"list_workflows", "get_workflow", "create_workflow", "update_workflow", "delete_workflow",
+			"list_flows", "get_flow", "list_flow_executions",
+			"list_script_includes", "get_script_include", "create_script_include", "update_script_include", "delete_script_include",
+			"search_application_files",
+			"list_rest_messages", "get_rest_message", "create_rest_message", "create_rest_message_function",
+			"list_changesets", "get_changeset", "create_changeset", "update_changeset", "commit_changeset",
+			"list_deleted_records", "restore_deleted_record", "query_table", "batch_update", "start_job", "get_job_status", "fetch_job_result",
+			"list_database_views", "describe_database_view", "list_reports", "run_report", "execute_background_script", "clear_cache", "whoami",
+		},
+	},
+	"system_administrator": {
+		description: "Users, groups, CMDB relationships, analytics, and generic table queries",
+		tools: []string{
+			"list_users", "get_user", "list_groups", "create_user", "update_user", "create_group", "update_group",
+			"add_group_members", "remove_group_members", "get_notification_settings", "update_notification_settings",
+			"update_notification_device", "get_ci_relationships", "add_ci_relationship",
+			"list_changesets", "get_changeset", "list_pa_indicators", "list_pa_breakdowns", "get_pa_scores", "query_table",
+			"list_deleted_records", "restore_deleted_record", "start_job", "get_job_status", "fetch_job_result",
+			"list_assignment_rules", "list_sla_definitions", "batch_update", "list_reports", "run_report", "whoami",
+		},
+	},
+	"agile_management": {
+		description: "Stories, epics, scrum tasks, projects, story dependencies, and sprint planning",
+		tools: []string{
+			"list_stories", "list_epics", "list_scrum_tasks", "list_projects", "create_story", "update_story",
+			"create_epic", "update_epic", "create_scrum_task", "update_scrum_task", "create_project", "update_project",
+			"list_story_dependencies", "add_story_dependency", "remove_story_dependency", "move_stories_to_sprint", "rank_backlog", "list_my_approvals", "respond_to_approval", "list_users", "list_groups", "whoami",
+		},
+	},
+	NonePackage: {
+		description: "Only list_tool_packages and switch_tool_package, to start without tools and switch later",
+	},
+}
+
+// packageMetaTools are listed in every package. Diagnostic tools are included since
+// they are opt-in and never call ServiceNow.
+var packageMetaTools = map[string]bool{
+	"list_tool_packages":  true,
+	"switch_tool_package": true,
+	"echo":                true,
+	"sleep":               true,
+	"error_test":          true,
+}
+
+// toolPackageNames returns the selectable package names, FullPackage first
+func toolPackageNames() []string {
+	names := make([]string, 0, len(toolPackages))
+	for name := range toolPackages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{FullPackage}, names...)
+}
+
+// SetToolPackage selects the tool package exposed after RegisterAll: FullPackage
+// (the default), RequesterPackage for employee-facing self-service assistants, or
+// one of the role packages listed by list_tool_packages
+func (r *Registry) SetToolPackage(name string) error {
+	if name == "" {
+		name = FullPackage
+	}
+	if _, ok := toolPackages[name]; !ok && name != FullPackage {
+		return fmt.Errorf("unsupported tool package %q (supported: %s)", name, strings.Join(toolPackageNames(), ", "))
+	}
+	r.toolPackage.Store(name)
+	return nil
+}
+
+// ToolPackage returns the name of the active tool package
+func (r *Registry) ToolPackage() string {
+	if name, _ := r.toolPackage.Load().(string); name != "" {
+		return name
+	}
+	return FullPackage
+}
+
+// toolInPackage reports whether a tool is exposed by the active tool package
+func (r *Registry) toolInPackage(name string) bool {
+	current := r.ToolPackage()
+	if packageMetaTools[name] {
+		// The requester package is a fixed deployment choice, not a switchable view
+		return name != "switch_tool_package" || current != RequesterPackage
+	}
+	if current == FullPackage {
+		for _, tool := range toolPackages[RequesterPackage].tools {
+			if tool == name {
+				return false
+			}
+		}
+		return true
+	}
+	for _, tool := range toolPackages[current].tools {
+		if tool == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// defaultAutoPaginateMax bounds the records one auto_paginate call collects
+const defaultAutoPaginateMax = 1000
+
+// SetAutoPaginateMax sets the most records a list tool collects with auto_paginate
+// (MCP_AUTO_PAGINATE_MAX); max_records can only lower it
+func (r *Registry) SetAutoPaginateMax(max int) error {
+	if max <= 0 {
+		return fmt.Errorf("auto-paginate cap must be positive, got %d", max)
+	}
+	r.autoPaginateMax = max
+	return nil
+}
+
+// withAutoPaginate adds the auto_paginate and max_records arguments to the
+// properties of a list tool that reads through listRecords
+func withAutoPaginate(properties map[string]mcp.Property) map[string]mcp.Property {
+	maxRecordsMin := float64(1)
+	properties["auto_paginate"] = mcp.Property{
+		Type:        "boolean",
+		Description: "Follow the pages after the first (limit records per page) and return every match up to max_records, with the total count and the next_offset to continue from",
+		Default:     false,
+	}
+	properties["max_records"] = mcp.Property{
+		Type:        "integer",
+		Description: fmt.Sprintf("Most records returned with auto_paginate (default and cap: %d, or MCP_AUTO_PAGINATE_MAX)", defaultAutoPaginateMax),
+		Minimum:     &maxRecordsMin,
+	}
+	return properties
+}
+
+// listPage describes where the records of a list response sit among all the records
+// matching its query
+type listPage struct {
+	// TotalCount is -1 when the instance did not report X-Total-Count
+	TotalCount int
+	Limit      int
+	Offset     int
+	HasMore    bool
+	// paging summarizes an auto_paginate read
+	paging *servicenow.PagingResult
+}
+
+// newListPage describes a page of returned records read with limit and offset, of
+// total matches. Without a total, a full page is assumed to have more after it.
+func newListPage(total, limit, offset, returned int) *listPage {
+	page := &listPage{TotalCount: total, Limit: limit, Offset: offset}
+	if total >= 0 {
+		page.HasMore = offset+returned < total
+	} else {
+		page.HasMore = limit > 0 && returned >= limit
+	}
+	return page
+}
+
+// listRecords reads a page of a Table API list, or with the auto_paginate argument every
+// page up to max_records. The result has the records under "result" as from Get, and
+// page describes where they sit among all the matches.
+func (r *Registry) listRecords(endpoint string, params map[string]string, args map[string]interface{}) (map[string]interface{}, *listPage, error) {
+	limit, _ := strconv.Atoi(params["sysparm_limit"])
+	offset, _ := strconv.Atoi(params["sysparm_offset"])
+
+	if !GetBoolArg(args, "auto_paginate", false) {
+		result, total, err := r.client.GetWithTotalCount(endpoint, params)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result, newListPage(total, limit, offset, len(GetResultList(result))), nil
+	}
+
+	maxRecords := r.autoPaginateMax
+	if maxRecords <= 0 {
+		maxRecords = defaultAutoPaginateMax
+	}
+	if requested := GetIntArg(args, "max_records", maxRecords); requested > 0 && requested < maxRecords {
+		maxRecords = requested
+	}
+	perPage := limit
+	if perPage <= 0 || perPage > maxRecords {
+		perPage = maxRecords
+	}
+
+	records := []interface{}{}
+	paging, err := r.client.GetPages(endpoint, params, perPage, func(page []map[string]interface{}) error {
+		for _, record := range page {
+			if len(records) == maxRecords {
+				return servicenow.ErrStopPaging
+			}
+			records = append(records, record)
+		}
+		if len(records) == maxRecords {
+			return servicenow.ErrStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A page cut short at max_records continues from the first record left out
+	paging.Complete = paging.Complete && paging.Records == len(records)
+	paging.Records = len(records)
+	paging.NextOffset = offset + len(records)
+	page := &listPage{TotalCount: paging.TotalCount, Limit: maxRecords, Offset: offset, HasMore: !paging.Complete, paging: paging}
+	return map[string]interface{}{"result": records}, page, nil
+}
+
+// pageOfMatches returns the page at offset of records matched locally from a scan, and
+// where it sits among the matches. Their count is known only when the scan read every
+// record of its query.
+func pageOfMatches(matches []map[string]interface{}, scan *listPage, limit, offset int) ([]map[string]interface{}, *listPage) {
+	total := -1
+	if !scan.HasMore {
+		total = len(matches)
+	}
+	start := min(offset, len(matches))
+	end := min(offset+limit, len(matches))
+	return matches[start:end], newListPage(total, limit, offset, end-start)
+}
+
+// withPaging adds total_count, limit, offset, and has_more to a list response, and the
+// paging summary of an auto_paginate read
+func withPaging(response map[string]interface{}, page *listPage) map[string]interface{} {
+	if page == nil {
+		return response
+	}
+	response["total_count"] = page.TotalCount
+	response["limit"] = page.Limit
+	response["offset"] = page.Offset
+	response["has_more"] = page.HasMore
+	if page.paging != nil {
+		response["paging"] = page.paging
+	}
+	return response
+}
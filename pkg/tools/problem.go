@@ -0,0 +1,639 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// problemTaskClosedState is the Closed state of problem_task records
+const problemTaskClosedState = "157"
+
+// problemFields are the compact records returned by list_problems, problemDetailFields
+// those of get_problem, and problemTaskFields those of problem tasks
+const (
+	problemFields       = "sys_id,number,short_description,state,priority,known_error,assigned_to,assignment_group,sys_updated_on"
+	problemDetailFields = problemFields + ",description,impact,urgency,category,cmdb_ci,cause_notes,fix_notes,workaround,opened_at,resolved_at,sys_created_on"
+	problemTaskFields   = "sys_id,number,short_description,problem_task_type,state,assigned_to,cause_notes,close_notes"
+)
+
+// registerProblemTools registers problem management tools (problems, RCA fields, problem tasks)
+func (r *Registry) registerProblemTools(server *mcp.Server) int {
+	count := 0
+
+	// Helper for limit/offset constraints
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// List Problems
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_problems",
+		Description: "List problem records with optional filtering by state, known error, or text search.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(withAutoPaginate(map[string]mcp.Property{
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+				"state": {
+					Type:        "string",
+					Description: "Problem state: 101=New, 102=Assess, 103=Root Cause Analysis, 104=Fix in Progress, 106=Resolved, 107=Closed",
+					Enum:        []string{"101", "102", "103", "104", "106", "107"},
+				},
+				"known_error": {
+					Type:        "boolean",
+					Description: "Filter by known error flag",
+				},
+				"query": {
+					Type:        "string",
+					Description: "Search short description (e.g., 'VPN disconnects')",
+				},
+			})),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Problems",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listProblems)
+	count++
+
+	// Get Problem
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_problem",
+		Description: "Get a problem with its root cause analysis fields (cause notes, fix notes, workaround) and problem tasks.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"problem_id": {
+					Type:        "string",
+					Description: "Problem number (e.g., 'PRB0040001') or sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'). Accepts both formats.",
+				},
+			}),
+			Required: []string{"problem_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Problem",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getProblem)
+	count++
+
+	// List Problem Tasks
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_problem_tasks",
+		Description: "List problem tasks (RCA and general investigation tasks) for a problem.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"problem_id": {
+					Type:        "string",
+					Description: "Problem number (e.g., 'PRB0040001') or sys_id. Accepts both formats.",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+			Required: []string{"problem_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Problem Tasks",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listProblemTasks)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Update Problem RCA
+		r.registerTool(server, mcp.Tool{
+			Name:        "update_problem_rca",
+			Description: "Record root cause analysis on a problem: cause notes, fix notes, workaround, and known error flag. Optionally move the problem state.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"problem_id": {
+						Type:        "string",
+						Description: "Problem number (e.g., 'PRB0040001') or sys_id. Accepts both formats.",
+					},
+					"cause_notes": {
+						Type:        "string",
+						Description: "Root cause (e.g., 'Certificate on the VPN concentrator expired')",
+					},
+					"fix_notes": {
+						Type:        "string",
+						Description: "Permanent fix applied or planned (e.g., 'Automated certificate renewal')",
+					},
+					"workaround": {
+						Type:        "string",
+						Description: "Workaround users can apply until the fix is in place",
+					},
+					"known_error": {
+						Type:        "boolean",
+						Description: "Mark the problem as a known error",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Problem state: 102=Assess, 103=Root Cause Analysis, 104=Fix in Progress, 106=Resolved",
+						Enum:        []string{"102", "103", "104", "106"},
+					},
+					"work_notes": {
+						Type:        "string",
+						Description: "Internal work note to add",
+					},
+				},
+				Required: []string{"problem_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Update Problem RCA",
+			},
+		}, (*Registry).updateProblemRCA)
+		count++
+
+		// Communicate Workaround
+		r.registerTool(server, mcp.Tool{
+			Name:        "communicate_workaround",
+			Description: "Share a problem's workaround with its active linked incidents as work notes (or customer-visible comments), so service desk agents can apply it.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"problem_id": {
+						Type:        "string",
+						Description: "Problem number (e.g., 'PRB0040001') or sys_id. Accepts both formats.",
+					},
+					"workaround": {
+						Type:        "string",
+						Description: "Workaround to save on the problem before sharing. Defaults to the problem's current workaround.",
+					},
+					"customer_visible": {
+						Type:        "boolean",
+						Description: "If true, posts as a comment visible to callers instead of a work note",
+						Default:     false,
+					},
+				},
+				Required: []string{"problem_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Communicate Workaround",
+			},
+		}, (*Registry).communicateWorkaround)
+		count++
+
+		// Create Problem Task
+		r.registerTool(server, mcp.Tool{
+			Name:        "create_problem_task",
+			Description: "Create a problem task for root cause analysis or general investigation work on a problem.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"problem_id": {
+						Type:        "string",
+						Description: "Problem number (e.g., 'PRB0040001') or sys_id. Accepts both formats.",
+					},
+					"short_description": {
+						Type:        "string",
+						Description: "Brief description of the task (e.g., 'Review VPN concentrator logs')",
+						MaxLength:   shortDescriptionMaxLength,
+					},
+					"description": {
+						Type:        "string",
+						Description: "Detailed task instructions",
+					},
+					"task_type": {
+						Type:        "string",
+						Description: "Task type: 'rca' (root cause analysis) or 'general'",
+						Default:     "general",
+						Enum:        []string{"rca", "general"},
+					},
+					"assigned_to": {
+						Type:        "string",
+						Description: "User to assign the task to (sys_id or username, e.g., 'beth.anglin')",
+					},
+					"assignment_group": {
+						Type:        "string",
+						Description: "Group to assign the task to (sys_id or name)",
+					},
+				},
+				Required: []string{"problem_id", "short_description"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create Problem Task",
+			},
+		}, (*Registry).createProblemTask)
+		count++
+
+		// Close Problem Task
+		r.registerTool(server, mcp.Tool{
+			Name:        "close_problem_task",
+			Description: "Close a problem task with closing notes. For RCA tasks, include the cause found so it can be copied to the problem.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"task_id": {
+						Type:        "string",
+						Description: "Problem task number (e.g., 'PTASK0010001') or sys_id. Accepts both formats.",
+					},
+					"close_notes": {
+						Type:        "string",
+						Description: "Findings or outcome of the task",
+					},
+					"cause_notes": {
+						Type:        "string",
+						Description: "Root cause identified by this task (RCA tasks)",
+					},
+				},
+				Required: []string{"task_id", "close_notes"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Close Problem Task",
+			},
+		}, (*Registry).closeProblemTask)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listProblems(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	limit := GetIntArg(args, "limit", 20)
+	offset := GetIntArg(args, "offset", 0)
+
+	params := map[string]string{
+		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_fields":                 readFields(args, problemFields),
+	}
+
+	var filters []string
+	if v := GetStringArg(args, "state", ""); v != "" {
+		filters = append(filters, fmt.Sprintf("state=%s", SanitizeQueryValue(v)))
+	}
+	if _, ok := args["known_error"]; ok {
+		filters = append(filters, fmt.Sprintf("known_error=%t", GetBoolArg(args, "known_error", false)))
+	}
+	if v := GetStringArg(args, "query", ""); v != "" {
+		filters = append(filters, LikeFilter(v, "short_description"))
+	}
+	filters = append(filters, "ORDERBYDESCsys_updated_on")
+	params["sysparm_query"] = strings.Join(filters, "^")
+
+	result, page, err := r.listRecords("/table/problem", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list problems", err)), nil
+	}
+
+	problems := selectRecordFields(GetResultList(result), args)
+	r.labelStates("problem", problems...)
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":  true,
+		"message":  fmt.Sprintf("Found %d problems", len(problems)),
+		"problems": problems,
+	}, page)), nil
+}
+
+func (r *Registry) getProblem(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	problemID := GetStringArg(args, "problem_id", "")
+	if problemID == "" {
+		return JSONResult(NewErrorResponse("problem_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveProblemID(problemID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find problem", err)), nil
+	}
+
+	params := map[string]string{
+		"sysparm_fields":                 readFields(args, problemDetailFields),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+	}
+
+	result, err := r.client.Get(fmt.Sprintf("/table/problem/%s", sysID), params)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get problem", err)), nil
+	}
+
+	data, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Problem not found: %s", problemID))), nil
+	}
+
+	problem := selectFields(data, data, args)
+	r.labelStates("problem", problem)
+	response := map[string]interface{}{
+		"success": true,
+		"message": "Problem found",
+		"problem": problem,
+		"rca": map[string]interface{}{
+			"cause_notes": data["cause_notes"],
+			"fix_notes":   data["fix_notes"],
+			"workaround":  data["workaround"],
+			"known_error": data["known_error"],
+		},
+	}
+
+	tasks, err := r.getProblemTasks(sysID, 50)
+	if err == nil {
+		response["problem_tasks"] = tasks
+	}
+
+	return JSONResult(response), nil
+}
+
+func (r *Registry) listProblemTasks(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	problemID := GetStringArg(args, "problem_id", "")
+	if problemID == "" {
+		return JSONResult(NewErrorResponse("problem_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveProblemID(problemID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find problem", err)), nil
+	}
+
+	params := problemTaskParams(sysID, GetIntArg(args, "limit", 20), GetIntArg(args, "offset", 0))
+	params["sysparm_fields"] = readFields(args, problemTaskFields)
+	result, page, err := r.listRecords("/table/problem_task", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list problem tasks", err)), nil
+	}
+
+	tasks := selectRecordFields(GetResultList(result), args)
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d problem tasks", len(tasks)),
+		"tasks":   tasks,
+	}, page)), nil
+}
+
+// getProblemTasks returns the problem tasks of a problem
+func (r *Registry) getProblemTasks(problemSysID string, limit int) ([]map[string]interface{}, error) {
+	result, err := r.client.Get("/table/problem_task", problemTaskParams(problemSysID, limit, 0))
+	if err != nil {
+		return nil, err
+	}
+	return GetResultList(result), nil
+}
+
+// problemTaskParams are the Table API parameters reading a page of a problem's tasks
+func problemTaskParams(problemSysID string, limit, offset int) map[string]string {
+	return map[string]string{
+		"sysparm_query":                  fmt.Sprintf("problem=%s^ORDERBYnumber", problemSysID),
+		"sysparm_fields":                 problemTaskFields,
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+	}
+}
+
+func (r *Registry) updateProblemRCA(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	problemID := GetStringArg(args, "problem_id", "")
+	if problemID == "" {
+		return JSONResult(NewErrorResponse("problem_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveProblemID(problemID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find problem", err)), nil
+	}
+
+	data := map[string]interface{}{}
+	for _, field := range []string{"cause_notes", "fix_notes", "workaround", "state", "work_notes"} {
+		if v := GetStringArg(args, field, ""); v != "" {
+			data[field] = v
+		}
+	}
+	if _, ok := args["known_error"]; ok {
+		data["known_error"] = GetBoolArg(args, "known_error", false)
+	}
+
+	if len(data) == 0 {
+		return JSONResult(NewErrorResponse("At least one field to update is required", nil)), nil
+	}
+
+	unresolved := r.expandJournalMentions(data)
+
+	result, err := r.client.Put(fmt.Sprintf("/table/problem/%s", sysID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to update problem", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		response := map[string]interface{}{
+			"success":        true,
+			"message":        "Problem RCA updated successfully",
+			"problem_id":     resultData["sys_id"],
+			"problem_number": resultData["number"],
+		}
+		if len(unresolved) > 0 {
+			response["unresolved_mentions"] = unresolved
+		}
+		return JSONResult(response), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) communicateWorkaround(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	problemID := GetStringArg(args, "problem_id", "")
+	if problemID == "" {
+		return JSONResult(NewErrorResponse("problem_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveProblemID(problemID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find problem", err)), nil
+	}
+
+	workaround := GetStringArg(args, "workaround", "")
+	var number string
+	if workaround != "" {
+		result, err := r.client.Put(fmt.Sprintf("/table/problem/%s", sysID), map[string]interface{}{"workaround": workaround})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to save workaround", err)), nil
+		}
+		if data, ok := result["result"].(map[string]interface{}); ok {
+			number, _ = data["number"].(string)
+		}
+	} else {
+		result, err := r.client.Get(fmt.Sprintf("/table/problem/%s", sysID), map[string]string{
+			"sysparm_fields": "number,workaround",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to get problem", err)), nil
+		}
+		if data, ok := result["result"].(map[string]interface{}); ok {
+			number, _ = data["number"].(string)
+			workaround, _ = data["workaround"].(string)
+		}
+	}
+
+	if strings.TrimSpace(workaround) == "" {
+		return JSONResult(NewErrorResponse("The problem has no workaround to communicate; provide workaround", nil)), nil
+	}
+
+	incidents, err := r.client.Get("/table/incident", map[string]string{
+		"sysparm_query":  fmt.Sprintf("problem_id=%s^active=true", sysID),
+		"sysparm_fields": "sys_id,number",
+		"sysparm_limit":  "500",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find linked incidents", err)), nil
+	}
+
+	field := "work_notes"
+	if GetBoolArg(args, "customer_visible", false) {
+		field = "comments"
+	}
+	note := fmt.Sprintf("Workaround from %s:\n%s", number, workaround)
+
+	updated := []string{}
+	failed := []string{}
+	for _, incident := range GetResultList(incidents) {
+		incidentSysID, _ := incident["sys_id"].(string)
+		incidentNumber, _ := incident["number"].(string)
+		if _, err := r.client.Put(fmt.Sprintf("/table/incident/%s", incidentSysID), map[string]interface{}{field: note}); err != nil {
+			failed = append(failed, incidentNumber)
+			continue
+		}
+		updated = append(updated, incidentNumber)
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":            len(failed) == 0,
+		"message":            fmt.Sprintf("Workaround shared with %d of %d linked incidents", len(updated), len(updated)+len(failed)),
+		"problem_id":         sysID,
+		"updated_incidents":  updated,
+		"failed_incidents":   failed,
+		"communicated_field": field,
+	}), nil
+}
+
+func (r *Registry) createProblemTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	problemID := GetStringArg(args, "problem_id", "")
+	shortDesc := GetStringArg(args, "short_description", "")
+
+	if problemID == "" || shortDesc == "" {
+		return JSONResult(NewErrorResponse("problem_id and short_description are required", nil)), nil
+	}
+
+	sysID, err := r.resolveProblemID(problemID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find problem", err)), nil
+	}
+
+	data := map[string]interface{}{
+		"problem":           sysID,
+		"short_description": shortDesc,
+		"problem_task_type": GetStringArg(args, "task_type", "general"),
+	}
+	for _, field := range []string{"description", "assigned_to", "assignment_group"} {
+		if v := GetStringArg(args, field, ""); v != "" {
+			data[field] = v
+		}
+	}
+
+	result, err := r.client.Post("/table/problem_task", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create problem task", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     "Problem task created successfully",
+			"task_id":     resultData["sys_id"],
+			"task_number": resultData["number"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) closeProblemTask(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	taskID := GetStringArg(args, "task_id", "")
+	closeNotes := GetStringArg(args, "close_notes", "")
+
+	if taskID == "" || closeNotes == "" {
+		return JSONResult(NewErrorResponse("task_id and close_notes are required", nil)), nil
+	}
+
+	sysID, err := r.resolveProblemTaskID(taskID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find problem task", err)), nil
+	}
+
+	data := map[string]interface{}{
+		"state":       problemTaskClosedState,
+		"close_notes": closeNotes,
+	}
+	if v := GetStringArg(args, "cause_notes", ""); v != "" {
+		data["cause_notes"] = v
+	}
+
+	result, err := r.client.Put(fmt.Sprintf("/table/problem_task/%s", sysID), data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to close problem task", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":     true,
+			"message":     "Problem task closed successfully",
+			"task_id":     resultData["sys_id"],
+			"task_number": resultData["number"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+// resolveProblemID resolves a problem number to sys_id
+func (r *Registry) resolveProblemID(problemID string) (string, error) {
+	return r.resolveRecordNumber("problem", problemID, "problem")
+}
+
+// resolveProblemTaskID resolves a problem task number to sys_id
+func (r *Registry) resolveProblemTaskID(taskID string) (string, error) {
+	return r.resolveRecordNumber("problem_task", taskID, "problem task")
+}
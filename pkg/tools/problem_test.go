@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCommunicateWorkaround tests that a saved workaround is posted to every active linked incident
+func TestCommunicateWorkaround(t *testing.T) {
+	const problemSysID = "9d385017c611228701d22104cc95c371"
+
+	notes := map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/problem/"+problemSysID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": problemSysID, "number": "PRB0040001"}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+			if q := r.URL.Query().Get("sysparm_query"); q != "problem_id="+problemSysID+"^active=true" {
+				t.Errorf("Unexpected incident query %q", q)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+				map[string]interface{}{"sys_id": "inc1", "number": "INC0010001"},
+				map[string]interface{}{"sys_id": "inc2", "number": "INC0010002"},
+			}})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/now/table/incident/"):
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			notes[strings.TrimPrefix(r.URL.Path, "/api/now/table/incident/")], _ = body["work_notes"].(string)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.communicateWorkaround(map[string]interface{}{
+		"problem_id": problemSysID,
+		"workaround": "Reconnect using the backup gateway",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "shared with 2 of 2 linked incidents") {
+		t.Fatalf("Expected workaround shared with both incidents, got %+v", result)
+	}
+	for _, id := range []string{"inc1", "inc2"} {
+		if notes[id] != "Workaround from PRB0040001:\nReconnect using the backup gateway" {
+			t.Errorf("Expected workaround note on %s, got %q", id, notes[id])
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// itsmPrompt is a prebuilt workflow prompt filled with a record read from ServiceNow
+type itsmPrompt struct {
+	prompt mcp.Prompt
+	// record is the argument naming the record, and recordType how it is read and rendered
+	record     string
+	recordType recordResourceType
+	// instructions returns the task appended after the rendered record
+	instructions func(number string, args map[string]interface{}) string
+}
+
+// resolvedIncident renders an incident with its resolution, for knowledge articles
+var resolvedIncident = recordResourceType{
+	table:      "incident",
+	label:      "Incident",
+	fields:     []string{"state", "category", "subcategory", "cmdb_ci", "close_code", "resolved_at"},
+	longFields: []string{"description", "close_notes"},
+}
+
+// itsmPrompts are the prompts served by prompts/list and prompts/get
+var itsmPrompts = []itsmPrompt{
+	{
+		prompt: mcp.Prompt{
+			Name:        "incident-triage",
+			Description: "Triage an incident: check its priority, find the right group, and look for known fixes",
+			Arguments: []mcp.PromptArgument{
+				{Name: "incident", Description: "Incident number (e.g., 'INC0010001') or sys_id", Required: true},
+			},
+		},
+		record:     "incident",
+		recordType: recordResourceTypes["incident"],
+		instructions: func(number string, _ map[string]interface{}) string {
+			return fmt.Sprintf(`Triage %s:
+1. Check the impact and urgency against the description, and derive the priority with compute_priority. Explain any change you recommend.
+2. Use suggest_routing with the configuration item and category to find the assignment group, and compare it with the current group.
+3. Search list_knowledge_articles and list_problems for known errors or workarounds matching the symptoms.
+4. Summarize the recommended priority, group, and next step for the agent. Don't update the incident until the agent confirms.`, number)
+		},
+	},
+	{
+		prompt: mcp.Prompt{
+			Name:        "change-risk-assessment",
+			Description: "Assess the risk of a change request from its plans, configuration item, and schedule",
+			Arguments: []mcp.PromptArgument{
+				{Name: "change", Description: "Change request number (e.g., 'CHG0030001') or sys_id", Required: true},
+			},
+		},
+		record:     "change",
+		recordType: recordResourceTypes["change"],
+		instructions: func(number string, _ map[string]interface{}) string {
+			return fmt.Sprintf(`Assess the risk of %s:
+1. Review the implementation, backout, and test plans for missing steps, untested assumptions, and an unclear rollback point.
+2. Use get_ci_relationships on the configuration item to find the services and CIs that depend on it.
+3. Use list_change_requests to find other changes on the same configuration item or overlapping the planned window, and list_incidents for open incidents on it.
+4. Recommend a risk (High, Moderate, or Low) with the reasons, and the conditions under which the change should not proceed.`, number)
+		},
+	},
+	{
+		prompt: mcp.Prompt{
+			Name:        "kb-article-from-incident",
+			Description: "Draft a knowledge article from how a resolved incident was fixed",
+			Arguments: []mcp.PromptArgument{
+				{Name: "incident", Description: "Resolved incident number (e.g., 'INC0010001') or sys_id", Required: true},
+				{Name: "knowledge_base", Description: "Knowledge base to file the article in (e.g., 'IT')"},
+			},
+		},
+		record:     "incident",
+		recordType: resolvedIncident,
+		instructions: func(number string, args map[string]interface{}) string {
+			target := "the knowledge base the agent chooses (see list_knowledge_bases)"
+			if kb := GetStringArg(args, "knowledge_base", ""); kb != "" {
+				target = fmt.Sprintf("the %q knowledge base", kb)
+			}
+			return fmt.Sprintf(`Draft a knowledge article from the resolution of %s:
+1. Search list_knowledge_articles first; if an article already covers this fix, propose an update to it instead.
+2. Write for the next person who hits the problem: a title describing the symptom, then Symptoms, Cause, and Resolution sections in HTML. Leave out caller names and other personal data.
+3. Show the draft, then create it with create_knowledge_article in %s once the author approves.`, number, target)
+		},
+	},
+}
+
+// maxPromptChoices bounds the choices listed for a field of a creation prompt
+const maxPromptChoices = 40
+
+// creationField is a field a creation prompt asks the user for
+type creationField struct {
+	name  string
+	label string
+	hint  string
+	// required fields must be given to the create tool; the others are recommended
+	required bool
+	// choices lists the field's choices, read from the instance
+	choices bool
+}
+
+// creationPrompt walks the user through creating a record with a create tool, field by field
+type creationPrompt struct {
+	prompt mcp.Prompt
+	table  string
+	tool   string
+	fields []creationField
+	// notes are table-specific steps added to the instructions
+	notes string
+}
+
+// creationPrompts are the record creation prompts, listed unless in read-only mode
+var creationPrompts = []creationPrompt{
+	{
+		prompt: mcp.Prompt{
+			Name:        "file-incident",
+			Description: "Walk the user through filing a complete incident, with the instance's categories, impacts, and urgencies",
+			Arguments: []mcp.PromptArgument{
+				{Name: "summary", Description: "What the user has said about the issue so far"},
+			},
+		},
+		table: "incident",
+		tool:  "create_incident",
+		fields: []creationField{
+			{name: "short_description", label: "Short description", hint: fmt.Sprintf("One line naming the symptom, at most %d characters", shortDescriptionMaxLength), required: true},
+			{name: "caller_id", label: "Caller", hint: "Who is affected (user name or email)"},
+			{name: "description", label: "Description", hint: "What happened, error messages, when it started, and what was already tried"},
+			{name: "category", label: "Category", choices: true},
+			{name: "impact", label: "Impact", hint: "How much of the business is affected", choices: true},
+			{name: "urgency", label: "Urgency", hint: "How soon it must be fixed", choices: true},
+			{name: "assignment_group", label: "Assignment group", hint: "Group name; suggest_routing can propose one"},
+		},
+		notes: "Don't ask for the priority: it follows from impact and urgency (see compute_priority).",
+	},
+	{
+		prompt: mcp.Prompt{
+			Name:        "file-change-request",
+			Description: "Walk the user through raising a complete change request, with the instance's change types, categories, and risks",
+			Arguments: []mcp.PromptArgument{
+				{Name: "summary", Description: "What the user has said about the change so far"},
+			},
+		},
+		table: "change_request",
+		tool:  "create_change_request",
+		fields: []creationField{
+			{name: "short_description", label: "Short description", hint: fmt.Sprintf("One line naming the change, at most %d characters", shortDescriptionMaxLength), required: true},
+			{name: "type", label: "Type", hint: "Standard changes are pre-approved; emergency changes skip the normal lead time", required: true, choices: true},
+			{name: "description", label: "Description", hint: "The business justification and implementation plan"},
+			{name: "category", label: "Category", choices: true},
+			{name: "risk", label: "Risk", choices: true},
+			{name: "impact", label: "Impact", choices: true},
+			{name: "assignment_group", label: "Assignment group", hint: "Group carrying out the change"},
+			{name: "start_date", label: "Planned start", hint: "YYYY-MM-DD HH:MM:SS"},
+			{name: "end_date", label: "Planned end", hint: "YYYY-MM-DD HH:MM:SS"},
+		},
+		notes: "Check the planned window with list_change_schedule and check_change_conflicts before creating the change.",
+	},
+}
+
+// render writes the prompt's fields, with the choices read for each, and the steps to follow
+func (c creationPrompt) render(r *Registry, summary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# New %s\n\n", strings.ReplaceAll(c.table, "_", " "))
+	if summary != "" {
+		fmt.Fprintf(&b, "The user said:\n\n> %s\n\n", strings.ReplaceAll(summary, "\n", "\n> "))
+	}
+	for _, required := range []bool{true, false} {
+		if required {
+			b.WriteString("## Required fields\n\n")
+		} else {
+			b.WriteString("\n## Recommended fields\n\n")
+		}
+		for _, field := range c.fields {
+			if field.required != required {
+				continue
+			}
+			fmt.Fprintf(&b, "- **%s** (`%s`)", field.label, field.name)
+			if field.hint != "" {
+				fmt.Fprintf(&b, ": %s", field.hint)
+			}
+			if field.choices {
+				b.WriteString(promptChoices(r.fieldChoices(c.table, field.name)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&b, `
+Create the record with %s:
+1. Fill in what the user already said, and confirm it rather than asking again.
+2. Ask for the missing fields a few at a time, required fields first. Offer the listed choices and pass the value, not the label.
+3. %s
+4. Show the complete record and create it only once the user confirms. Report the new number.`, c.tool, c.notes)
+	return b.String()
+}
+
+// promptChoices formats a field's choices as " Choices: label (`value`), ..."
+func promptChoices(choices stateChoices) string {
+	if len(choices.order) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, min(len(choices.order), maxPromptChoices))
+	for _, value := range choices.order[:min(len(choices.order), maxPromptChoices)] {
+		parts = append(parts, fmt.Sprintf("%s (`%s`)", choices.labels[value], value))
+	}
+	text := " Choices: " + strings.Join(parts, ", ")
+	if more := len(choices.order) - maxPromptChoices; more > 0 {
+		text += fmt.Sprintf(", and %d more", more)
+	}
+	return text
+}
+
+// RegisterPrompts registers the ITSM workflow prompts with the server. None are
+// registered under RequesterPackage, since prompts read the record they name whoever
+// owns it. Call it after SetToolPackage.
+func (r *Registry) RegisterPrompts(server *mcp.Server) {
+	if r.ToolPackage() == RequesterPackage {
+		return
+	}
+	server.RegisterPromptProvider(promptProvider{registry: r})
+}
+
+// promptProvider serves itsmPrompts, filled with records read for the caller
+type promptProvider struct {
+	registry *Registry
+}
+
+// ListPrompts lists the ITSM workflow prompts, and the creation prompts unless in read-only mode
+func (p promptProvider) ListPrompts() []mcp.Prompt {
+	prompts := make([]mcp.Prompt, 0, len(itsmPrompts)+len(creationPrompts))
+	for _, prompt := range itsmPrompts {
+		prompts = append(prompts, prompt.prompt)
+	}
+	if !p.registry.readOnlyMode {
+		for _, prompt := range creationPrompts {
+			prompts = append(prompts, prompt.prompt)
+		}
+	}
+	return prompts
+}
+
+// GetPrompt fills a prompt with the configured credentials
+func (p promptProvider) GetPrompt(name string, arguments map[string]interface{}) (*mcp.GetPromptResult, error) {
+	return p.GetPromptWithContext(context.Background(), name, arguments)
+}
+
+// GetPromptWithContext fills a prompt with the record it names, or a creation prompt
+// with the choices of its fields, read with the credentials that came with the request
+func (p promptProvider) GetPromptWithContext(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.GetPromptResult, error) {
+	for _, prompt := range creationPrompts {
+		if prompt.prompt.Name != name || p.registry.readOnlyMode {
+			continue
+		}
+		text := prompt.render(p.registry.forContext(ctx), strings.TrimSpace(GetStringArg(arguments, "summary", "")))
+		return &mcp.GetPromptResult{
+			Description: prompt.prompt.Description,
+			Messages: []mcp.PromptMessage{{
+				Role:    "user",
+				Content: mcp.ContentItem{Type: "text", Text: text},
+			}},
+		}, nil
+	}
+
+	for _, prompt := range itsmPrompts {
+		if prompt.prompt.Name != name {
+			continue
+		}
+
+		id := strings.TrimSpace(GetStringArg(arguments, prompt.record, ""))
+		if id == "" {
+			return nil, fmt.Errorf("%w: %s is required", mcp.ErrInvalidPromptArguments, prompt.record)
+		}
+		record, err := p.registry.forContext(ctx).getResourceRecord(prompt.recordType, id)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, fmt.Errorf("%w: %s %s not found", mcp.ErrInvalidPromptArguments, prompt.record, id)
+		}
+
+		number := FieldDisplay(record["number"])
+		text := renderRecordMarkdown(prompt.recordType, record) + "\n" + prompt.instructions(number, arguments)
+		return &mcp.GetPromptResult{
+			Description: fmt.Sprintf("%s for %s", prompt.prompt.Name, number),
+			Messages: []mcp.PromptMessage{{
+				Role:    "user",
+				Content: mcp.ContentItem{Type: "text", Text: text},
+			}},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", mcp.ErrPromptNotFound, name)
+}
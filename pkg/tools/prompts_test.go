@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestITSMPrompts tests that prompts are filled with the record they name
+func TestITSMPrompts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/now/table/incident" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		result := []interface{}{}
+		if r.URL.Query().Get("sysparm_query") == "number=INC0010001" {
+			if !strings.Contains(r.URL.Query().Get("sysparm_fields"), "close_notes") {
+				t.Errorf("Expected the resolution to be read, got fields %s", r.URL.Query().Get("sysparm_fields"))
+			}
+			result = append(result, map[string]interface{}{
+				"number":            map[string]interface{}{"value": "INC0010001", "display_value": "INC0010001"},
+				"short_description": map[string]interface{}{"value": "VPN drops every hour", "display_value": "VPN drops every hour"},
+				"close_notes":       map[string]interface{}{"value": "Renewed the client certificate", "display_value": "Renewed the client certificate"},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	provider := promptProvider{registry: registry}
+	if prompts := provider.ListPrompts(); len(prompts) != len(itsmPrompts) || prompts[0].Name != "incident-triage" {
+		t.Errorf("Expected the ITSM prompts, got %+v", prompts)
+	}
+
+	result, err := provider.GetPromptWithContext(context.Background(), "kb-article-from-incident",
+		map[string]interface{}{"incident": "INC0010001", "knowledge_base": "IT"})
+	if err != nil {
+		t.Fatalf("Expected the prompt to be filled, got %v", err)
+	}
+	text := result.Messages[0].Content.Text
+	for _, want := range []string{"# Incident INC0010001: VPN drops every hour", "## Close notes\n\nRenewed the client certificate", `create_knowledge_article in the "IT" knowledge base`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	if _, err := provider.GetPromptWithContext(context.Background(), "incident-triage", map[string]interface{}{"incident": "INC0019999"}); !errors.Is(err, mcp.ErrInvalidPromptArguments) {
+		t.Errorf("Expected an unknown incident to be rejected, got %v", err)
+	}
+	if _, err := provider.GetPromptWithContext(context.Background(), "incident-triage", nil); !errors.Is(err, mcp.ErrInvalidPromptArguments) {
+		t.Errorf("Expected a missing incident to be rejected, got %v", err)
+	}
+	if _, err := provider.GetPromptWithContext(context.Background(), "missing", nil); !errors.Is(err, mcp.ErrPromptNotFound) {
+		t.Errorf("Expected an unknown prompt to be rejected, got %v", err)
+	}
+}
+
+// TestCreationPrompts tests that creation prompts list required fields before recommended ones with the instance's choices, and are hidden in read-only mode
+func TestCreationPrompts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/now/table/sys_choice" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		result := []interface{}{}
+		switch r.URL.Query().Get("sysparm_query") {
+		case "nameINincident,task^element=category^inactive=false^ORDERBYsequence":
+			result = append(result,
+				map[string]interface{}{"name": "incident", "value": "software", "label": "Software"},
+				map[string]interface{}{"name": "incident", "value": "hardware", "label": "Hardware"},
+			)
+		case "nameINincident,task^element=impact^inactive=false^ORDERBYsequence":
+			result = append(result, map[string]interface{}{"name": "task", "value": "1", "label": "1 - High"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	provider := promptProvider{registry: registry}
+	if prompts := provider.ListPrompts(); len(prompts) != len(itsmPrompts)+len(creationPrompts) {
+		t.Errorf("Expected the ITSM and creation prompts, got %+v", prompts)
+	}
+
+	result, err := provider.GetPromptWithContext(context.Background(), "file-incident", map[string]interface{}{"summary": "Laptop won't boot"})
+	if err != nil {
+		t.Fatalf("Expected the prompt to be filled, got %v", err)
+	}
+	text := result.Messages[0].Content.Text
+	for _, want := range []string{
+		"> Laptop won't boot",
+		"## Required fields\n\n- **Short description** (`short_description`)",
+		"- **Category** (`category`) Choices: Software (`software`), Hardware (`hardware`)\n",
+		"Choices: 1 - High (`1`)",
+		"create it only once the user confirms",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "## Recommended fields") < strings.Index(text, "short_description") {
+		t.Errorf("Expected required fields before recommended ones, got:\n%s", text)
+	}
+
+	readOnly, _ := newTestRegistry(t, ts.URL, true)
+	provider = promptProvider{registry: readOnly}
+	if len(provider.ListPrompts()) != len(itsmPrompts) {
+		t.Errorf("Expected no creation prompts in read-only mode")
+	}
+	if _, err := provider.GetPromptWithContext(context.Background(), "file-incident", nil); !errors.Is(err, mcp.ErrPromptNotFound) {
+		t.Errorf("Expected the creation prompt to be unavailable in read-only mode, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// maxQueueRecords bounds the incidents listed by a queue resource
+const maxQueueRecords = 100
+
+// queueFields are the incident fields a queue resource lists, in column order
+var queueFields = []string{"number", "priority", "state", "assigned_to", "sys_updated_on", "short_description"}
+
+// SetQueueResources sets the assignment groups (names or sys_ids) whose open incident
+// queues are listed by resources/list as servicenow://queue/<group>/open. Queues of
+// other groups can still be read.
+func (r *Registry) SetQueueResources(groups []string) {
+	selected := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if group = strings.TrimSpace(group); group != "" {
+			selected = append(selected, group)
+		}
+	}
+	r.queueGroups = selected
+}
+
+// queueURI returns the URI of the open incident queue of group
+func queueURI(group string) string {
+	return fmt.Sprintf("servicenow://queue/%s/open", url.PathEscape(group))
+}
+
+// parseQueueURI parses servicenow://queue/<group>/open[?format=json|markdown]
+func parseQueueURI(uri string) (group, format string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "servicenow" || u.Host != "queue" {
+		return "", "", fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+	group, ok := strings.CutSuffix(strings.TrimPrefix(u.Path, "/"), "/open")
+	if !ok || group == "" || strings.Contains(group, "/") {
+		return "", "", fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+	format = u.Query().Get("format")
+	switch format {
+	case "":
+		format = "markdown"
+	case "markdown", "json":
+	default:
+		return "", "", fmt.Errorf("unsupported format %q (use markdown or json)", format)
+	}
+	return group, format, nil
+}
+
+// queueProvider serves the open incidents of an assignment group as
+// servicenow://queue/<group>/open, regenerated on every read with an etag that changes
+// whenever the queue does
+type queueProvider struct {
+	registry *Registry
+}
+
+// ListResources lists the queues of the configured groups
+func (p queueProvider) ListResources() []mcp.Resource {
+	resources := []mcp.Resource{}
+	for _, group := range p.registry.queueGroups {
+		resources = append(resources, mcp.Resource{
+			URI:         queueURI(group),
+			Name:        group + " queue",
+			Description: fmt.Sprintf("Open incidents assigned to %s, with an etag to detect changes", group),
+			MimeType:    "text/markdown",
+		})
+	}
+	return resources
+}
+
+// ReadResource reads a queue with the configured credentials
+func (p queueProvider) ReadResource(uri string) (*mcp.ReadResourceResult, error) {
+	return p.ReadResourceWithContext(context.Background(), uri)
+}
+
+// ReadResourceWithContext reads a queue with the credentials that came with the request
+func (p queueProvider) ReadResourceWithContext(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	group, format, err := parseQueueURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	r := p.registry.forContext(ctx)
+	groupID, err := r.resolveGroup(group)
+	if err != nil {
+		return nil, err
+	}
+
+	result, total, err := r.client.GetWithTotalCount("/table/incident", map[string]string{
+		"sysparm_query":                  "active=true^assignment_group=" + groupID + "^ORDERBYpriority^ORDERBYopened_at",
+		"sysparm_fields":                 "sys_id," + strings.Join(queueFields, ","),
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", maxQueueRecords),
+	})
+	if err != nil {
+		return nil, err
+	}
+	incidents := GetResultList(result)
+	if total < len(incidents) {
+		total = len(incidents)
+	}
+	etag := queueETag(total, incidents)
+	meta := map[string]interface{}{"etag": etag}
+
+	if format == "json" {
+		rows := make([]map[string]interface{}, len(incidents))
+		for i, incident := range incidents {
+			row := map[string]interface{}{"sys_id": FieldValue(incident["sys_id"])}
+			for _, field := range queueFields {
+				row[field] = FieldDisplay(incident[field])
+			}
+			rows[i] = row
+		}
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"group":     group,
+			"etag":      etag,
+			"total":     total,
+			"incidents": rows,
+		}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []mcp.ResourceContent{{URI: uri, MimeType: "application/json", Text: string(data), Meta: meta}},
+		}, nil
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContent{{URI: uri, MimeType: "text/markdown", Text: renderQueueMarkdown(group, etag, total, incidents), Meta: meta}},
+	}, nil
+}
+
+// queueETag hashes the queue's size and each listed incident's sys_id and update time,
+// so it changes when an incident joins, leaves, or is updated
+func queueETag(total int, incidents []map[string]interface{}) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n", total)
+	for _, incident := range incidents {
+		fmt.Fprintf(h, "%s %s\n", FieldValue(incident["sys_id"]), FieldValue(incident["sys_updated_on"]))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// renderQueueMarkdown renders a queue as a title, its etag and size, and a table of incidents
+func renderQueueMarkdown(group, etag string, total int, incidents []map[string]interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Open incidents: %s\n\n", group)
+	fmt.Fprintf(&b, "- **ETag**: %s\n", etag)
+	if total > len(incidents) {
+		fmt.Fprintf(&b, "- **Open**: %d (showing the first %d)\n", total, len(incidents))
+	} else {
+		fmt.Fprintf(&b, "- **Open**: %d\n", total)
+	}
+	if len(incidents) == 0 {
+		b.WriteString("\nNo open incidents.\n")
+		return b.String()
+	}
+
+	b.WriteString("\n|")
+	for _, field := range queueFields {
+		fmt.Fprintf(&b, " %s |", fieldLabel(field))
+	}
+	b.WriteString("\n|" + strings.Repeat("---|", len(queueFields)) + "\n")
+	cell := strings.NewReplacer("|", `\|`, "\r", " ", "\n", " ")
+	for _, incident := range incidents {
+		b.WriteString("|")
+		for _, field := range queueFields {
+			fmt.Fprintf(&b, " %s |", cell.Replace(FieldDisplay(incident[field])))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
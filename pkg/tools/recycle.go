@@ -0,0 +1,315 @@
+package tools
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// deletedRecordsTable holds a copy of each deleted record of an audited table
+// (Deleted Records / recycle bin); undeleting re-inserts the copy
+const deletedRecordsTable = "sys_audit_delete"
+
+// defaultDeleteProtectedTables are the tables whose records can only be deleted
+// when the instance keeps deleted records
+var defaultDeleteProtectedTables = []string{"wf_workflow", "sys_script_include", "kb_knowledge"}
+
+// restoreSkippedFields are system fields ServiceNow sets on insert, which a restore doesn't copy
+var restoreSkippedFields = map[string]bool{
+	"sys_created_by": true, "sys_created_on": true, "sys_updated_by": true,
+	"sys_updated_on": true, "sys_mod_count": true, "sys_tags": true,
+}
+
+// recycleBin caches whether the instance exposes deleted records to this server
+type recycleBin struct {
+	once      sync.Once
+	available bool
+}
+
+// SetDeleteProtectedTables sets the tables whose records are only deleted when the
+// instance keeps a restorable copy. An empty list allows every delete.
+func (r *Registry) SetDeleteProtectedTables(tables []string) error {
+	protected := map[string]bool{}
+	for _, table := range tables {
+		table = strings.TrimSpace(table)
+		if table == "" {
+			continue
+		}
+		if !tableNamePattern.MatchString(table) {
+			return fmt.Errorf("invalid table name %q", table)
+		}
+		protected[table] = true
+	}
+	r.deleteProtected = protected
+	return nil
+}
+
+// recycleBinAvailable reports whether deleted records can be read (and so restored)
+func (r *Registry) recycleBinAvailable() bool {
+	r.recycleBin.once.Do(func() {
+		_, err := r.client.Get("/table/"+deletedRecordsTable, map[string]string{
+			"sysparm_fields": "sys_id",
+			"sysparm_limit":  "1",
+		})
+		r.recycleBin.available = err == nil
+	})
+	return r.recycleBin.available
+}
+
+// deleteRecord deletes a record. Records of delete-protected tables are refused when
+// the instance doesn't keep deleted records. The returned map describes whether the
+// deletion can be undone, for inclusion in the tool response.
+func (r *Registry) deleteRecord(table, sysID string) (map[string]interface{}, error) {
+	available := r.recycleBinAvailable()
+	if r.deleteProtected[table] && !available {
+		return nil, fmt.Errorf("%s is delete-protected and deleted records can't be restored on this instance (%s is not readable)", table, deletedRecordsTable)
+	}
+
+	if _, err := r.client.Delete(fmt.Sprintf("/table/%s/%s", table, sysID)); err != nil {
+		return nil, err
+	}
+
+	recovery := map[string]interface{}{"restorable": false}
+	if !available {
+		recovery["note"] = "Deleted records are not available on this instance; the deletion can't be undone"
+		return recovery, nil
+	}
+
+	// Only audited tables keep a copy of deleted records
+	result, err := r.client.Get("/table/"+deletedRecordsTable, map[string]string{
+		"sysparm_query":  fmt.Sprintf("tablename=%s^documentkey=%s^ORDERBYDESCsys_created_on", table, sysID),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if records := GetResultList(result); err == nil && len(records) > 0 {
+		recovery["restorable"] = true
+		recovery["delete_id"] = FieldValue(records[0]["sys_id"])
+		return recovery, nil
+	}
+	recovery["note"] = fmt.Sprintf("No deleted-record copy was kept (%s is not audited); the deletion can't be undone", table)
+	return recovery, nil
+}
+
+// registerRecycleBinTools registers the deleted record tools
+func (r *Registry) registerRecycleBinTools(server *mcp.Server) int {
+	count := 0
+
+	daysMin := float64(1)
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+
+	// List Deleted Records
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_deleted_records",
+		Description: "List recently deleted records that can be restored (Deleted Records / recycle bin), newest first. Only tables with auditing keep deleted records.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"table": {
+					Type:        "string",
+					Description: "Only records deleted from this table (e.g., 'wf_workflow')",
+				},
+				"days": {
+					Type:        "integer",
+					Description: "Only records deleted in the last N days",
+					Default:     7,
+					Minimum:     &daysMin,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Deleted Records",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listDeletedRecords)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Restore Deleted Record
+		r.registerTool(server, mcp.Tool{
+			Name:        "restore_deleted_record",
+			Description: "Restore a deleted record from its deleted-record copy, with its original sys_id. Records deleted along with it (cascade deletes) are not restored.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"delete_id": {
+						Type:        "string",
+						Description: "Deleted record entry sys_id from list_deleted_records or a delete response (e.g., 'a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6')",
+					},
+				},
+				Required: []string{"delete_id"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Restore Deleted Record",
+			},
+		}, (*Registry).restoreDeletedRecord)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listDeletedRecords(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	query := fmt.Sprintf("sys_created_on>=javascript:gs.daysAgoStart(%d)", GetIntArg(args, "days", 7))
+	if table := GetStringArg(args, "table", ""); table != "" {
+		if !tableNamePattern.MatchString(table) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+		}
+		query += "^tablename=" + table
+	}
+
+	result, page, err := r.listRecords("/table/"+deletedRecordsTable, map[string]string{
+		"sysparm_query":  query + "^ORDERBYDESCsys_created_on",
+		"sysparm_fields": readFields(args, "sys_id,tablename,documentkey,display_value,sys_created_on,sys_created_by"),
+		"sysparm_limit":  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset": fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list deleted records (the instance may not expose "+deletedRecordsTable+")", err)), nil
+	}
+
+	records := []map[string]interface{}{}
+	for _, entry := range GetResultList(result) {
+		records = append(records, selectFields(map[string]interface{}{
+			"delete_id":    FieldValue(entry["sys_id"]),
+			"table":        FieldValue(entry["tablename"]),
+			"record_id":    FieldValue(entry["documentkey"]),
+			"display_name": FieldValue(entry["display_value"]),
+			"deleted_at":   FieldValue(entry["sys_created_on"]),
+			"deleted_by":   FieldValue(entry["sys_created_by"]),
+		}, entry, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d deleted records", len(records)),
+		"records": records,
+	}, page)), nil
+}
+
+func (r *Registry) restoreDeletedRecord(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	deleteID := GetStringArg(args, "delete_id", "")
+	if deleteID == "" {
+		return JSONResult(NewErrorResponse("delete_id is required", nil)), nil
+	}
+	if !IsSysID(deleteID) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid delete_id: %s", deleteID), nil)), nil
+	}
+
+	result, err := r.client.Get(fmt.Sprintf("/table/%s/%s", deletedRecordsTable, deleteID), map[string]string{
+		"sysparm_fields": "tablename,documentkey,payload",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get deleted record", err)), nil
+	}
+	entry, _ := result["result"].(map[string]interface{})
+	table := FieldValue(entry["tablename"])
+	sysID := FieldValue(entry["documentkey"])
+	if !tableNamePattern.MatchString(table) || !IsSysID(sysID) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Deleted record entry %s has no restorable record", deleteID), nil)), nil
+	}
+
+	fields, err := parseDeletedPayload(FieldValue(entry["payload"]))
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read the deleted record copy", err)), nil
+	}
+
+	existing, err := r.client.Get("/table/"+table, map[string]string{
+		"sysparm_query":  "sys_id=" + sysID,
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to check %s", table), err)), nil
+	}
+	if len(GetResultList(existing)) > 0 {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Record %s already exists in %s; it was restored already", sysID, table), nil)), nil
+	}
+
+	data := map[string]interface{}{"sys_id": sysID}
+	for name, value := range fields {
+		if !restoreSkippedFields[name] {
+			data[name] = value
+		}
+	}
+	if _, err := r.client.Post("/table/"+table, data); err != nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to restore record into %s", table), err)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":   true,
+		"message":   fmt.Sprintf("Restored %s record %s", table, sysID),
+		"table":     table,
+		"record_id": sysID,
+	}), nil
+}
+
+// parseDeletedPayload extracts the field values from a deleted-record payload, an
+// XML document of the form <record_update><table action="DELETE"><field>value</field>...
+func parseDeletedPayload(payload string) (map[string]string, error) {
+	if strings.TrimSpace(payload) == "" {
+		return nil, errors.New("the deleted record entry has no payload")
+	}
+
+	fields := map[string]string{}
+	decoder := xml.NewDecoder(strings.NewReader(payload))
+	depth := 0
+	var field string
+	var value strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 3 {
+				field = t.Name.Local
+				value.Reset()
+			}
+		case xml.CharData:
+			if depth == 3 {
+				value.Write(t)
+			}
+		case xml.EndElement:
+			if depth == 3 {
+				fields[field] = value.String()
+			}
+			depth--
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("the payload holds no fields")
+	}
+	return fields, nil
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	deletedWorkflowID = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	deleteEntryID     = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// newRecycleBinServer fakes an instance that keeps deleted records when keepDeleted is set
+// and otherwise denies access to them. Deleted workflows are tracked in deleted, and
+// restored records are captured in restored.
+func newRecycleBinServer(t *testing.T, keepDeleted bool, deleted map[string]bool, restored *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/now/table/sys_audit_delete") && !keepDeleted:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"message":"User Not Authorized"}}`))
+			return
+		case r.URL.Path == "/api/now/table/sys_audit_delete":
+			result = []interface{}{map[string]interface{}{"sys_id": deleteEntryID}}
+		case r.URL.Path == "/api/now/table/sys_audit_delete/"+deleteEntryID:
+			result = map[string]interface{}{
+				"tablename":   "wf_workflow",
+				"documentkey": deletedWorkflowID,
+				"payload": `<?xml version="1.0" encoding="UTF-8"?><record_update table="wf_workflow"><wf_workflow action="DELETE">` +
+					`<name>Onboarding &amp; Access</name><description/><sys_id>` + deletedWorkflowID + `</sys_id>` +
+					`<sys_updated_on>2024-01-01 00:00:00</sys_updated_on></wf_workflow></record_update>`,
+			}
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/now/table/wf_workflow/"+deletedWorkflowID:
+			deleted[deletedWorkflowID] = true
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/wf_workflow":
+			result = []interface{}{}
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/wf_workflow":
+			_ = json.NewDecoder(r.Body).Decode(restored)
+			result = map[string]interface{}{"sys_id": deletedWorkflowID}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+}
+
+// TestDeleteProtection tests that protected tables are only deleted when the instance keeps deleted records
+func TestDeleteProtection(t *testing.T) {
+	deleted := map[string]bool{}
+	var restored map[string]interface{}
+
+	ts := newRecycleBinServer(t, false, deleted, &restored)
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.deleteWorkflow(map[string]interface{}{"workflow_id": deletedWorkflowID})
+	ts.Close()
+	if deleted[deletedWorkflowID] || !strings.Contains(result.Content[0].Text, "delete-protected") {
+		t.Fatalf("Expected the delete to be refused without deleted records, got %s", result.Content[0].Text)
+	}
+
+	ts = newRecycleBinServer(t, true, deleted, &restored)
+	defer ts.Close()
+	registry, _ = newTestRegistry(t, ts.URL, false)
+	result, _ = registry.deleteWorkflow(map[string]interface{}{"workflow_id": deletedWorkflowID})
+	if !deleted[deletedWorkflowID] || !strings.Contains(result.Content[0].Text, `"delete_id": "`+deleteEntryID+`"`) {
+		t.Fatalf("Expected the delete to report its restorable copy, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.restoreDeletedRecord(map[string]interface{}{"delete_id": deleteEntryID})
+	if !strings.Contains(result.Content[0].Text, `"success": true`) {
+		t.Fatalf("Expected the record to be restored, got %s", result.Content[0].Text)
+	}
+	if restored["sys_id"] != deletedWorkflowID || restored["name"] != "Onboarding & Access" || restored["description"] != "" {
+		t.Errorf("Expected the original sys_id and fields, got %v", restored)
+	}
+	if _, ok := restored["sys_updated_on"]; ok {
+		t.Errorf("Expected system fields to be left to the instance, got %v", restored)
+	}
+}
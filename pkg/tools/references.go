@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resolveUser resolves a user given as a sys_id, user_name, or email to a sys_user sys_id
+func (r *Registry) resolveUser(user string) (string, error) {
+	return r.resolveReference(cacheUsers, "sys_user", user, "user_name=%[1]s^ORemail=%[1]s", "user")
+}
+
+// resolveGroup resolves a group given as a sys_id or name to a sys_user_group sys_id
+func (r *Registry) resolveGroup(group string) (string, error) {
+	return r.resolveReference(cacheGroups, "sys_user_group", group, "name=%s", "group")
+}
+
+// resolveRecordNumber resolves a record of table given as a sys_id or number (e.g.,
+// INC0010001) to its sys_id. kind names the record in errors (e.g., "incident").
+func (r *Registry) resolveRecordNumber(table, number, kind string) (string, error) {
+	return r.resolveReference(cacheNumbers+table+":", table, number, "number=%s", kind)
+}
+
+// resolveReference looks up the sys_id of the record of table matching query (a format
+// taking the sanitized value), through the client's lookup cache under prefix and the
+// caller's credentials. sys_ids are returned as given; values matching several records
+// are refused as ambiguous.
+func (r *Registry) resolveReference(prefix, table, value, query, kind string) (string, error) {
+	value = strings.TrimSpace(value)
+	if IsSysID(value) {
+		return value, nil
+	}
+	key := r.cacheKey(prefix, strings.ToLower(value))
+	if sysID, ok := r.base.Cache().Get(key); ok {
+		return sysID.(string), nil
+	}
+
+	// A second match makes the value ambiguous
+	result, err := r.client.Get("/table/"+table, map[string]string{
+		"sysparm_query":  fmt.Sprintf(query, SanitizeQueryValue(value)),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "2",
+	})
+	if err != nil {
+		return "", err
+	}
+	records := GetResultList(result)
+	if len(records) == 0 || FieldValue(records[0]["sys_id"]) == "" {
+		return "", fmt.Errorf("%s %w: %s", kind, ErrNotFound, value)
+	}
+	sysID := FieldValue(records[0]["sys_id"])
+	if len(records) > 1 && FieldValue(records[1]["sys_id"]) != sysID {
+		return "", fmt.Errorf("%s %s %w; use its sys_id", kind, value, ErrAmbiguousID)
+	}
+	r.base.Cache().Set(key, sysID)
+	return sysID, nil
+}
+
+// resolveRecordNumbers resolves records of table given as sys_ids or numbers to their
+// sys_ids, looking the numbers up in one call. The result maps each id to its sys_id;
+// numbers matching no record are left out.
+func (r *Registry) resolveRecordNumbers(table string, ids []string) (map[string]string, error) {
+	sysIDs := make(map[string]string, len(ids))
+	var numbers []string
+	for _, id := range ids {
+		if IsSysID(id) {
+			sysIDs[id] = id
+		} else {
+			numbers = append(numbers, SanitizeQueryValue(strings.TrimSpace(id)))
+		}
+	}
+	if len(numbers) == 0 {
+		return sysIDs, nil
+	}
+
+	result, err := r.client.Get("/table/"+table, map[string]string{
+		"sysparm_query":  "numberIN" + strings.Join(numbers, ","),
+		"sysparm_fields": "sys_id,number",
+		"sysparm_limit":  fmt.Sprintf("%d", len(numbers)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	byNumber := map[string]string{}
+	for _, record := range GetResultList(result) {
+		byNumber[strings.ToUpper(FieldValue(record["number"]))] = FieldValue(record["sys_id"])
+	}
+	for _, id := range ids {
+		if sysID, ok := byNumber[strings.ToUpper(strings.TrimSpace(id))]; ok {
+			sysIDs[id] = sysID
+		}
+	}
+	return sysIDs, nil
+}
+
+// setReferenceArgs sets the user and group arguments given in args on a record's data,
+// resolved to sys_ids. The error names the argument that could not be resolved.
+func (r *Registry) setReferenceArgs(args, data map[string]interface{}, userFields, groupFields []string) error {
+	for _, field := range userFields {
+		if v := GetStringArg(args, field, ""); v != "" {
+			sysID, err := r.resolveUser(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			data[field] = sysID
+		}
+	}
+	for _, field := range groupFields {
+		if v := GetStringArg(args, field, ""); v != "" {
+			sysID, err := r.resolveGroup(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			data[field] = sysID
+		}
+	}
+	return nil
+}
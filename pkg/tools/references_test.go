@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// TestResolveReferences tests that users and groups given by email or name are sent as sys_ids, looked up once until the cache is cleared
+func TestResolveReferences(t *testing.T) {
+	const (
+		incidentID = "a0000000000000000000000000000001"
+		userID     = "b0000000000000000000000000000001"
+		groupID    = "c0000000000000000000000000000001"
+	)
+	lookups := map[string]int{}
+	var updated map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/now/table/sys_user":
+			lookups["sys_user"]++
+			if got := r.URL.Query().Get("sysparm_query"); got != "user_name=beth.anglin@example.com^ORemail=beth.anglin@example.com" {
+				_, _ = w.Write([]byte(`{"result": []}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + userID + `"}]}`))
+		case r.URL.Path == "/api/now/table/sys_user_group":
+			lookups["sys_user_group"]++
+			if got := r.URL.Query().Get("sysparm_query"); got != "name=Network" {
+				t.Errorf("Expected the group to be found by name, got %s", got)
+			}
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + groupID + `"}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/incident/"+incidentID:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			_, _ = w.Write([]byte(`{"result": {"sys_id": "` + incidentID + `", "number": "INC0010001"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	args := map[string]interface{}{"incident_id": incidentID, "assignment_group": "Network"}
+	for i := 0; i < 2; i++ {
+		args["assigned_to"] = []string{"beth.anglin@example.com", "Beth.Anglin@example.com"}[i]
+		result, _ := registry.updateIncident(args)
+		if !strings.Contains(result.Content[0].Text, `"success": true`) {
+			t.Fatalf("Expected the incident to be updated, got %s", result.Content[0].Text)
+		}
+		if updated["assigned_to"] != userID || updated["assignment_group"] != groupID {
+			t.Errorf("Expected the user and group sys_ids to be sent, got %+v", updated)
+		}
+	}
+	if lookups["sys_user"] != 1 || lookups["sys_user_group"] != 1 {
+		t.Errorf("Expected the user and group to be looked up once, got %v", lookups)
+	}
+
+	result, _ := registry.clearCache(map[string]interface{}{"scope": "users"})
+	if !strings.Contains(result.Content[0].Text, `"removed": 1`) {
+		t.Errorf("Expected the cached user to be cleared, got %s", result.Content[0].Text)
+	}
+	_, _ = registry.updateIncident(args)
+	if lookups["sys_user"] != 2 || lookups["sys_user_group"] != 1 {
+		t.Errorf("Expected only the user to be looked up again after clearing users, got %v", lookups)
+	}
+
+	result, _ = registry.updateIncident(map[string]interface{}{"incident_id": incidentID, "assigned_to": "nobody"})
+	if text := result.Content[0].Text; !strings.Contains(text, "assigned_to: user not found: nobody") {
+		t.Errorf("Expected an unknown user to be rejected, got %s", text)
+	}
+}
+
+// TestResolveReferencesPerCaller tests that lookups made with a caller's own credentials are cached for that caller only
+func TestResolveReferencesPerCaller(t *testing.T) {
+	lookups := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		user, _, _ := r.BasicAuth()
+		lookups[user]++
+		_, _ = w.Write([]byte(`{"result": [{"sys_id": "c0000000000000000000000000000001"}]}`))
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	as := func(user, password string) *Registry {
+		return registry.forContext(servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: user, Password: password}))
+	}
+	for _, caller := range []*Registry{as("alice", "a"), as("alice", "a"), as("bob", "b"), as("bob", "wrong"), registry.forContext(context.Background())} {
+		if _, err := caller.resolveGroup("Network"); err != nil {
+			t.Fatalf("resolveGroup failed: %v", err)
+		}
+	}
+	if lookups["alice"] != 1 || lookups["bob"] != 2 || len(lookups) != 3 {
+		t.Errorf("Expected one lookup per set of credentials, got %v", lookups)
+	}
+}
+
+// TestResolveReferencesAfterWrites tests that writes to a looked-up table, and deletes of numbered records, drop the cached lookups
+func TestResolveReferencesAfterWrites(t *testing.T) {
+	lookups := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			lookups[strings.TrimPrefix(r.URL.Path, "/api/now/table/")]++
+		}
+		_, _ = w.Write([]byte(`{"result": [{"sys_id": "c0000000000000000000000000000001"}]}`))
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	bound := registry.forContext(context.Background())
+	resolve := func() {
+		if _, err := bound.resolveGroup("Network"); err != nil {
+			t.Fatalf("resolveGroup failed: %v", err)
+		}
+		if _, err := bound.resolveRecordNumber("incident", "INC0010001", "incident"); err != nil {
+			t.Fatalf("resolveRecordNumber failed: %v", err)
+		}
+	}
+
+	resolve()
+	_, _ = bound.client.Put("/table/incident/c0000000000000000000000000000001", map[string]interface{}{"state": "2"})
+	resolve()
+	if lookups["sys_user_group"] != 1 || lookups["incident"] != 1 {
+		t.Fatalf("Expected an unrelated update to keep the cache, got %v", lookups)
+	}
+
+	_, _ = bound.client.Put("/table/sys_user_group/c0000000000000000000000000000001", map[string]interface{}{"name": "Networking"})
+	_, _ = bound.client.Delete("/table/incident/c0000000000000000000000000000001")
+	resolve()
+	if lookups["sys_user_group"] != 2 || lookups["incident"] != 2 {
+		t.Errorf("Expected the group and number lookups to be dropped, got %v", lookups)
+	}
+}
+
+// TestErrorCodes tests that error responses are classified: unknown and ambiguous references, instance status codes, and rejected arguments
+func TestErrorCodes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("sysparm_query") {
+		case "name=Network":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "c0000000000000000000000000000001"}, {"sys_id": "c0000000000000000000000000000002"}]}`))
+		case "number=INC0000404":
+			_, _ = w.Write([]byte(`{"result": []}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"message": "User Not Authorized"}}`))
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	for _, test := range []struct {
+		name string
+		args map[string]interface{}
+		code mcp.ToolErrorCode
+	}{
+		{"ambiguous group", map[string]interface{}{"incident_id": "a0000000000000000000000000000001", "assignment_group": "Network"}, mcp.ErrorAmbiguousID},
+		{"unknown incident", map[string]interface{}{"incident_id": "INC0000404", "state": "2"}, mcp.ErrorNotFound},
+		{"forbidden read", map[string]interface{}{"incident_id": "INC0010001", "state": "2"}, mcp.ErrorPermissionDenied},
+		{"missing argument", map[string]interface{}{"state": "2"}, mcp.ErrorValidationFailed},
+	} {
+		result, _ := registry.updateIncident(test.args)
+		var response ErrorResponse
+		if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+			t.Fatalf("%s: failed to parse result: %v", test.name, err)
+		}
+		if response.Success || response.Code != test.code {
+			t.Errorf("%s: expected %s, got %s", test.name, test.code, result.Content[0].Text)
+		}
+	}
+
+	if code := WriteBlockedResult().Meta["code"]; code != mcp.ErrorPermissionDenied {
+		t.Errorf("Expected blocked writes to be PERMISSION_DENIED, got %v", code)
+	}
+}
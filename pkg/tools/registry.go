@@ -1,68 +1,356 @@
 package tools
 
 import (
-	"github.com/elastiflow/go-mcp-servicenow/pkg/logging"
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
-	"github.com/elastiflow/go-mcp-servicenow/pkg/servicenow"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/logging"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
 )
 
+// deprecatedAliases maps retired tool names to their replacements. Old names keep
+// routing to the replacement tool so existing clients don't break when tools are renamed.
+var deprecatedAliases = map[string]string{}
+
 // Registry manages tool registration
 type Registry struct {
-	client       *servicenow.Client
+	client       serviceNowClient
+	base         *servicenow.Client
 	logger       *logging.Logger
 	readOnlyMode bool
+	diagnostics  bool
+	destructive  bool
+	httpMode     bool
+	toolPackage  *atomic.Value
+	digestTables []string
+	queueGroups  []string
+	resources    *recordResources
+	choices      *choiceCache
+	jobs         *jobStore
+	timeZone     *instanceTimeZone
+	validators   []Validator
+	transformers []Transformer
+
+	// Handling of arguments missing from the tool schema (MCP_UNKNOWN_ARGUMENTS)
+	unknownArguments string
+
+	// Most records collected by auto_paginate list calls (MCP_AUTO_PAGINATE_MAX, 0 for the default)
+	autoPaginateMax int
+
+	// Limit argument cap and per-tool defaults and maximums (MCP_MAX_LIMIT / MCP_DEFAULT_LIMITS / MCP_MAX_LIMITS)
+	maxLimit      int
+	defaultLimits map[string]int
+	maxLimits     map[string]int
+
+	// Result size limits (MCP_MAX_RESPONSE_BYTES / MCP_MAX_RESPONSE_SIZES)
+	maxResponseBytes        int
+	maxResponseBytesPerTool map[string]int
+
+	// Per-tool timeouts (MCP_TOOL_TIMEOUT / MCP_TOOL_TIMEOUTS)
+	toolTimeout  time.Duration
+	toolTimeouts map[string]time.Duration
+
+	// Records changed per session (MCP_SESSION_INDEX)
+	sessionIndex *sessionIndex
+
+	// Table receiving an activity record per write tool call (MCP_ACTIVITY_TABLE)
+	activityTable string
+
+	// Scripted REST API running background scripts (ALLOW_SCRIPT_EXECUTION, "" when disabled)
+	scriptAPI string
+
+	// Delete protection (MCP_DELETE_PROTECTED_TABLES)
+	recycleBin      *recycleBin
+	deleteProtected map[string]bool
+
+	// Tool selection (TOOLS_ENABLE / TOOLS_DISABLE)
+	enabledTools    map[string]bool
+	disabledTools   map[string]bool
+	knownTools      map[string]bool
+	module          string
+	registeredTools int
 }
 
 // NewRegistry creates a new tool registry
 func NewRegistry(client *servicenow.Client, logger *logging.Logger, readOnlyMode bool) *Registry {
-	return &Registry{
+	r := &Registry{
 		client:       client,
+		base:         client,
 		logger:       logger,
 		readOnlyMode: readOnlyMode,
+		toolPackage:  &atomic.Value{},
+		jobs:         newJobStore(),
+		timeZone:     &instanceTimeZone{},
+		resources:    &recordResources{},
+		choices:      &choiceCache{},
+		recycleBin:   &recycleBin{},
+		knownTools:   map[string]bool{},
+
+		maxResponseBytes: DefaultMaxResponseBytes,
+		unknownArguments: UnknownArgumentsWarn,
 	}
+	_ = r.SetDeleteProtectedTables(defaultDeleteProtectedTables)
+	r.AddValidator(ValidatorFunc(coerceArgsValidator))
+	r.AddValidator(ValidatorFunc(r.choiceArgsValidator))
+	r.AddValidator(ValidatorFunc(schemaValidator))
+	r.AddValidator(ValidatorFunc(r.limitsValidator))
+	r.AddValidator(ValidatorFunc(r.unknownArgumentsValidator))
+	r.AddValidator(ValidatorFunc(fieldsValidator))
+	r.AddTransformer(TransformerFunc(r.usageTransformer))
+	r.AddTransformer(TransformerFunc(r.responseSizeTransformer))
+	return r
 }
 
-// RegisterAll registers all tools with the MCP server
+// EnableDiagnosticTools registers the transport diagnostic tools (echo, sleep,
+// error_test) on the next RegisterAll call
+func (r *Registry) EnableDiagnosticTools() {
+	r.diagnostics = true
+}
+
+// SetHTTPMode tells the registry that calls arrive over HTTP from many callers, so
+// tools acting as the calling user need the caller's own ServiceNow credentials
+// rather than using the configured user
+func (r *Registry) SetHTTPMode(httpMode bool) {
+	r.httpMode = httpMode
+}
+
+// RegisterAll registers all tools with the MCP server and limits the tools it
+// exposes to the selected tool package. It returns the number of exposed tools.
 func (r *Registry) RegisterAll(server *mcp.Server) int {
 	count := 0
 
 	// Incident Management Tools (read-only always registered)
-	count += r.registerIncidentTools(server)
+	count += r.registerModule(server, "incidents", r.registerIncidentTools)
+
+	// Journal (Comments and Work Notes) Tools
+	count += r.registerModule(server, "journal", r.registerJournalTools)
+
+	// Routing Tools
+	count += r.registerModule(server, "routing", r.registerRoutingTools)
+
+	// Triage Tools
+	count += r.registerModule(server, "triage", r.registerTriageTools)
+
+	// Major Incident Tools
+	count += r.registerModule(server, "major_incidents", r.registerMajorIncidentTools)
+
+	// SLA Tools
+	count += r.registerModule(server, "slas", r.registerSLATools)
+
+	// Business Schedule Tools
+	count += r.registerModule(server, "schedules", r.registerScheduleTools)
 
 	// Catalog Tools
-	count += r.registerCatalogTools(server)
+	count += r.registerModule(server, "catalog", r.registerCatalogTools)
+
+	// Catalog Task Tools
+	count += r.registerModule(server, "catalog_tasks", r.registerCatalogTaskTools)
+
+	// Problem Management Tools
+	count += r.registerModule(server, "problems", r.registerProblemTools)
 
 	// Change Management Tools
-	count += r.registerChangeTools(server)
+	count += r.registerModule(server, "changes", r.registerChangeTools)
 
 	// Knowledge Base Tools
-	count += r.registerKnowledgeBaseTools(server)
+	count += r.registerModule(server, "knowledge", r.registerKnowledgeBaseTools)
+
+	// Knowledge Article Translation Tools
+	count += r.registerModule(server, "kb_translations", r.registerKBTranslationTools)
+
+	// Knowledge Base Access (User Criteria) Tools
+	count += r.registerModule(server, "kb_access", r.registerKBAccessTools)
 
 	// User Management Tools
-	count += r.registerUserTools(server)
+	count += r.registerModule(server, "users", r.registerUserTools)
+
+	// Notification Preference Tools
+	count += r.registerModule(server, "notifications", r.registerNotificationTools)
 
 	// Workflow Tools
-	count += r.registerWorkflowTools(server)
+	count += r.registerModule(server, "workflows", r.registerWorkflowTools)
+
+	// Flow Designer Tools
+	count += r.registerModule(server, "flows", r.registerFlowTools)
 
 	// Script Include Tools
-	count += r.registerScriptIncludeTools(server)
+	count += r.registerModule(server, "script_includes", r.registerScriptIncludeTools)
+
+	// Application File Tools
+	count += r.registerModule(server, "app_files", r.registerAppFileTools)
+
+	// Outbound REST Message Tools
+	count += r.registerModule(server, "rest_messages", r.registerRESTMessageTools)
 
 	// Changeset Tools
-	count += r.registerChangesetTools(server)
+	count += r.registerModule(server, "changesets", r.registerChangesetTools)
 
 	// Agile Tools (Story, Epic, Scrum Task, Project)
-	count += r.registerAgileTools(server)
+	count += r.registerModule(server, "agile", r.registerAgileTools)
+
+	// Story Dependency Tools
+	count += r.registerModule(server, "story_dependencies", r.registerStoryDependencyTools)
+
+	// Backlog Tools
+	count += r.registerModule(server, "backlog", r.registerBacklogTools)
+
+	// Performance Analytics Tools
+	count += r.registerModule(server, "analytics", r.registerAnalyticsTools)
+
+	// CMDB Relationship Tools
+	count += r.registerModule(server, "cmdb", r.registerCMDBTools)
+
+	// Deleted Record Tools
+	count += r.registerModule(server, "recycle_bin", r.registerRecycleBinTools)
+
+	// Generic Table Query Tool
+	count += r.registerModule(server, "table", r.registerTableTools)
+
+	// Database View and Report Tools
+	count += r.registerModule(server, "reports", r.registerReportTools)
+
+	// Batch Record Tools
+	count += r.registerModule(server, "batch", r.registerBatchTools)
 
-	// Meta tool: list_tool_packages
-	r.registerMetaTools(server)
-	count++
+	// Long-Running Job Tools
+	count += r.registerModule(server, "jobs", r.registerJobTools)
+
+	// Lookup Cache Tools
+	count += r.registerModule(server, "cache", r.registerCacheTools)
+
+	// My Work Tools
+	count += r.registerModule(server, "my_work", r.registerMyWorkTools)
+
+	// Approval Inbox Tools
+	count += r.registerModule(server, "approvals", r.registerApprovalTools)
+
+	// Requester Self-Service Tools (exposed only by the requester package)
+	count += r.registerModule(server, "requester", r.registerRequesterTools)
+
+	// Session Change Index Tools (opt-in)
+	if r.sessionIndex != nil {
+		count += r.registerModule(server, "session_changes", r.registerSessionChangeTools)
+	}
+
+	// Background Script Tool (opt-in, never in read-only mode)
+	if r.scriptAPI != "" {
+		count += r.registerModule(server, "scripts", r.registerScriptTools)
+	}
+
+	// Close, Cancel, and Delete Tools (opt-in, never in read-only mode)
+	if r.destructive {
+		count += r.registerModule(server, "destructive", r.registerDestructiveTools)
+	}
+
+	// Diagnostic Tools (opt-in, never call ServiceNow)
+	if r.diagnostics {
+		count += r.registerModule(server, "diagnostics", r.registerDiagnosticTools)
+	}
 
-	return count
+	// Meta tools: list_tool_packages, switch_tool_package
+	count += r.registerModule(server, "meta", r.registerMetaTools)
+
+	// Deprecated aliases (skipped when the replacement tool is not registered, e.g. in read-only mode)
+	aliases := make([]string, 0, len(deprecatedAliases))
+	for alias := range deprecatedAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if err := server.RegisterAlias(alias, deprecatedAliases[alias]); err == nil {
+			count++
+		}
+	}
+
+	if unknown := r.unknownToolSelections(); len(unknown) > 0 && r.logger != nil {
+		r.logger.Warn("Tool selection entries match no tool or module: %s", strings.Join(unknown, ", "))
+	}
+
+	server.SetToolFilter(r.toolInPackage)
+	exposed := len(server.ListTools())
+	if r.logger != nil {
+		r.logger.Debug("Registered %d tools, %d exposed by the %s package", count, exposed, r.ToolPackage())
+	}
+	return exposed
+}
+
+// rateLimitWarningRatio is the fraction of remaining ServiceNow API quota below which results carry a warning
+const rateLimitWarningRatio = 0.1
+
+// toolHandler handles a tool call on a registry bound to the call's request context
+type toolHandler func(r *Registry, args map[string]interface{}) (*mcp.CallToolResult, error)
+
+// registerTool registers a tool with the server, running its handler through the
+// middleware chain. The handler runs on a copy of the registry whose client
+// carries the request context (per-request credentials, impersonation).
+func (r *Registry) registerTool(server *mcp.Server, tool mcp.Tool, handler toolHandler) {
+	if !r.toolSelected(tool.Name) {
+		return
+	}
+	r.registeredTools++
+	tool = withVerify(withExamples(tool))
+	server.RegisterToolWithContext(tool, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.callTool(ctx, tool, args, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return handler(r.forContext(ctx), args)
+		})
+	})
+}
+
+// forContext returns a copy of the registry whose client issues requests with ctx
+func (r *Registry) forContext(ctx context.Context) *Registry {
+	bound := *r
+	bound.client = contextClient{Client: r.base, ctx: ctx}
+	return &bound
+}
+
+// registerToolWithContext registers a tool whose handler needs the request context
+// (e.g., the caller's ServiceNow credentials), with the same middleware chain as registerTool
+func (r *Registry) registerToolWithContext(server *mcp.Server, tool mcp.Tool, handler mcp.ToolHandlerWithContext) {
+	if !r.toolSelected(tool.Name) {
+		return
+	}
+	r.registeredTools++
+	tool = withVerify(withExamples(tool))
+	server.RegisterToolWithContext(tool, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.callTool(ctx, tool, args, handler)
+	})
+}
+
+// attachUsage adds ServiceNow API usage headers observed during a tool call to the
+// result metadata, and appends a warning when the remaining rate limit quota is low.
+// ctx is the call's context, which callTool set up to record usage.
+func (r *Registry) attachUsage(ctx context.Context, result *mcp.CallToolResult) {
+	usage, ok := servicenow.UsageFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if result.Meta == nil {
+		result.Meta = map[string]interface{}{}
+	}
+	result.Meta["servicenow_usage"] = usage
+
+	if usage.RateLimit.IsLow(rateLimitWarningRatio) {
+		warning := fmt.Sprintf("Warning: ServiceNow API rate limit is low (%d of %d requests remaining", usage.RateLimit.Remaining, usage.RateLimit.Limit)
+		if !usage.RateLimit.Reset.IsZero() {
+			warning += fmt.Sprintf(", resets at %s", usage.RateLimit.Reset.Format(time.RFC3339))
+		}
+		warning += "). Reduce request frequency to avoid the integration user being blocked."
+		addWarnings(result, warning)
+		if r.logger != nil {
+			r.logger.Warn("ServiceNow rate limit low: %d of %d remaining", usage.RateLimit.Remaining, usage.RateLimit.Limit)
+		}
+	}
 }
 
 // registerMetaTools registers metadata/introspection tools
-func (r *Registry) registerMetaTools(server *mcp.Server) {
-	server.RegisterTool(mcp.Tool{
+func (r *Registry) registerMetaTools(server *mcp.Server) int {
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_tool_packages",
 		Description: "Lists available tool packages and the currently loaded one.",
 		InputSchema: mcp.JSONSchema{
@@ -73,22 +361,78 @@ func (r *Registry) registerMetaTools(server *mcp.Server) {
 			Title:        "List Tool Packages",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		result := map[string]interface{}{
-			"current_package": "full",
-			"available_packages": []string{
-				"full",
-				"service_desk",
-				"catalog_builder",
-				"change_coordinator",
-				"knowledge_author",
-				"platform_developer",
-				"system_administrator",
-				"agile_management",
-				"none",
-			},
-			"message": "Currently loaded package: 'full'. Set MCP_TOOL_PACKAGE env var to switch.",
+	}, (*Registry).listToolPackages)
+
+	switchable := make([]string, 0, len(toolPackages))
+	for _, name := range toolPackageNames() {
+		if name != RequesterPackage {
+			switchable = append(switchable, name)
 		}
-		return JSONResult(result), nil
-	})
+	}
+	r.registerTool(server, mcp.Tool{
+		Name:        "switch_tool_package",
+		Description: "Switch the tools this server exposes to another tool package, for all clients. Re-list tools after switching.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"package": {
+					Type:        "string",
+					Description: "Package to switch to (see list_tool_packages)",
+					Enum:        switchable,
+				},
+			},
+			Required: []string{"package"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:          "Switch Tool Package",
+			IdempotentHint: true,
+		},
+	}, (*Registry).switchToolPackage)
+
+	return 2
+}
+
+func (r *Registry) listToolPackages(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	current := r.ToolPackage()
+	packages := []map[string]interface{}{{
+		"name":        FullPackage,
+		"description": "Every tool except the requester self-service tools",
+	}}
+	for _, name := range toolPackageNames()[1:] {
+		packages = append(packages, map[string]interface{}{
+			"name":        name,
+			"description": toolPackages[name].description,
+			"tools":       toolPackages[name].tools,
+		})
+	}
+	return JSONResult(map[string]interface{}{
+		"current_package":    current,
+		"available_packages": packages,
+		"message":            fmt.Sprintf("Currently loaded package: '%s'. Use switch_tool_package, or set MCP_TOOL_PACKAGE or -tool-package at startup, to change it.", current),
+	}), nil
+}
+
+func (r *Registry) switchToolPackage(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	name := GetStringArg(args, "package", "")
+	if name == "" {
+		return JSONResult(NewErrorResponse("package is required", nil)), nil
+	}
+
+	previous := r.ToolPackage()
+	if previous == RequesterPackage || name == RequesterPackage {
+		return JSONResult(NewErrorResponse("The requester package can only be selected at startup", nil)), nil
+	}
+	if err := r.SetToolPackage(name); err != nil {
+		return JSONResult(NewErrorResponse("Failed to switch tool package", err)), nil
+	}
+
+	if r.logger != nil {
+		r.logger.Info("Tool package switched from %s to %s", previous, name)
+	}
+	return JSONResult(map[string]interface{}{
+		"success":          true,
+		"message":          fmt.Sprintf("Switched tool package from '%s' to '%s'. Re-list tools to see the available tools.", previous, name),
+		"current_package":  name,
+		"previous_package": previous,
+	}), nil
 }
@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// newTestRegistry creates a registry backed by a client for the given instance URL
+func newTestRegistry(t *testing.T, instanceURL string, readOnly bool) (*Registry, *mcp.Server) {
+	t.Helper()
+
+	client, err := servicenow.NewClient(&servicenow.Config{
+		InstanceURL: instanceURL,
+		Timeout:     5,
+		Auth: servicenow.AuthConfig{
+			Type:  servicenow.AuthTypeBasic,
+			Basic: &servicenow.BasicAuthConfig{Username: "test", Password: "test"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry := NewRegistry(client, nil, readOnly)
+	registry.RegisterAll(server)
+	return registry, server
+}
+
+// TestToolSchemasGolden asserts the full tools/list output against golden files.
+// Run `make golden` (go test ./pkg/tools -run TestToolSchemasGolden -update) to regenerate.
+func TestToolSchemasGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		golden   string
+	}{
+		{name: "full", readOnly: false, golden: "tools_full.golden.json"},
+		{name: "read_only", readOnly: true, golden: "tools_read_only.golden.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, server := newTestRegistry(t, "https://example.service-now.com", tt.readOnly)
+
+			got, err := json.MarshalIndent(mcp.ListToolsResult{Tools: server.ListTools()}, "", "  ")
+			if err != nil {
+				t.Fatalf("Failed to marshal tools: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run `make golden` to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("tools/list output differs from %s; if the change is intentional, run `make golden`", path)
+			}
+		})
+	}
+}
+
+// TestToolPackages tests that packages limit the exposed tools and can be switched at runtime
+func TestToolPackages(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", false)
+	if err := registry.SetToolPackage("change_coordinator"); err != nil {
+		t.Fatalf("Expected change_coordinator to be accepted, got %v", err)
+	}
+
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	count := registry.RegisterAll(server)
+
+	names := map[string]bool{}
+	for _, tool := range server.ListTools() {
+		names[tool.Name] = true
+	}
+	if count != len(names) || !names["approve_change"] || !names["switch_tool_package"] || names["create_incident"] || names["list_my_incidents"] {
+		t.Fatalf("Unexpected change_coordinator tools (%d): %v", count, names)
+	}
+
+	result, _ := registry.switchToolPackage(map[string]interface{}{"package": NonePackage})
+	if result.IsError || registry.ToolPackage() != NonePackage {
+		t.Fatalf("Expected switch to none, got %+v", result)
+	}
+	if tools := server.ListTools(); len(tools) != 2 {
+		t.Errorf("Expected only the package tools after switching to none, got %d tools", len(tools))
+	}
+
+	for _, name := range []string{RequesterPackage, "field_services"} {
+		result, _ := registry.switchToolPackage(map[string]interface{}{"package": name})
+		if !strings.Contains(result.Content[0].Text, `"success": false`) || registry.ToolPackage() != NonePackage {
+			t.Errorf("Expected switch to %s to be rejected, got %s", name, result.Content[0].Text)
+		}
+	}
+
+	result, _ = registry.switchToolPackage(map[string]interface{}{"package": FullPackage})
+	if result.IsError || len(server.ListTools()) <= count {
+		t.Errorf("Expected full package to expose more tools than change_coordinator, got %+v", result)
+	}
+}
+
+// TestDiagnosticTools tests that diagnostic tools are opt-in and behave as documented
+func TestDiagnosticTools(t *testing.T) {
+	_, server := newTestRegistry(t, "https://example.service-now.com", true)
+	for _, tool := range server.ListTools() {
+		if tool.Name == "echo" {
+			t.Fatal("Expected diagnostic tools to be disabled by default")
+		}
+	}
+
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", true)
+	registry.EnableDiagnosticTools()
+	server = mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	names := map[string]bool{}
+	for _, tool := range server.ListTools() {
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"echo", "sleep", "error_test"} {
+		if !names[name] {
+			t.Errorf("Expected diagnostic tool %s to be registered", name)
+		}
+	}
+
+	result, _ := registry.diagnosticEcho(map[string]interface{}{"message": "ping"})
+	if result.IsError || !bytes.Contains([]byte(result.Content[0].Text), []byte(`"message": "ping"`)) {
+		t.Errorf("Expected echo of message, got %+v", result)
+	}
+
+	result, _ = registry.diagnosticSleep(context.Background(), map[string]interface{}{"seconds": float64(0)})
+	if result.IsError {
+		t.Errorf("Expected sleep to succeed, got %+v", result)
+	}
+
+	result, _ = registry.diagnosticError(map[string]interface{}{"message": "boom"})
+	if !result.IsError || result.Content[0].Text != "boom" {
+		t.Errorf("Expected error result, got %+v", result)
+	}
+	if _, err := registry.diagnosticError(map[string]interface{}{"mode": "handler"}); err == nil {
+		t.Error("Expected handler error")
+	}
+}
+
+// TestToolSelection tests that disabled tools and modules are never registered
+func TestToolSelection(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", false)
+	registry.SetToolSelection(nil, []string{"delete_workflow", " create_user", "script_includes"})
+
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+	if _, ok := server.Handler("delete_workflow"); ok {
+		t.Error("Expected delete_workflow not to be registered")
+	}
+	names := map[string]bool{}
+	for _, tool := range server.ListTools() {
+		names[tool.Name] = true
+	}
+	if names["create_user"] || names["list_script_includes"] || !names["list_workflows"] || !names["update_user"] {
+		t.Errorf("Unexpected tools after disabling: %v", names)
+	}
+
+	registry, _ = newTestRegistry(t, "https://example.service-now.com", false)
+	registry.SetToolSelection([]string{"incidents", "list_users", "unknown_tool"}, []string{"resolve_incident"})
+	server = mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	names = map[string]bool{}
+	for _, tool := range server.ListTools() {
+		names[tool.Name] = true
+	}
+	if len(names) != 8 || !names["create_incident"] || !names["list_users"] || names["resolve_incident"] || names["get_user"] {
+		t.Errorf("Unexpected tools after enabling: %v", names)
+	}
+	if unknown := registry.unknownToolSelections(); len(unknown) != 1 || unknown[0] != "unknown_tool" {
+		t.Errorf("Expected unknown_tool to be reported, got %v", unknown)
+	}
+}
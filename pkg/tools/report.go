@@ -0,0 +1,448 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// reportAggregates maps report aggregations (sys_report.aggregate) to the Aggregate API
+// parameter and result key computing them over the report's sum field
+var reportAggregates = map[string]struct{ param, key string }{
+	"SUM": {"sysparm_sum_fields", "sum"},
+	"AVG": {"sysparm_avg_fields", "avg"},
+	"MIN": {"sysparm_min_fields", "min"},
+	"MAX": {"sysparm_max_fields", "max"},
+}
+
+// registerReportTools registers the database view and report tools
+func (r *Registry) registerReportTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// List Database Views (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_database_views",
+		Description: "List database views: pre-joined tables defined on the instance that query_table can query by name like any table.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"query": {
+					Type:        "string",
+					Description: "Search text in the view name or label",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Database Views",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listDatabaseViews)
+	count++
+
+	// Describe Database View (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "describe_database_view",
+		Description: "Describe a database view: the tables it joins, their join conditions, and the column names to query, filter, and sort it with in query_table (each table's fields prefixed with its variable prefix, e.g., 'inc_number').",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"view": {
+					Type:        "string",
+					Description: "Database view name (e.g., 'incident_sla')",
+				},
+			},
+			Required: []string{"view"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Describe Database View",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).describeDatabaseView)
+	count++
+
+	// List Reports (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_reports",
+		Description: "List saved platform reports (sys_report) the caller can read, with the table, type, and grouping each one reports on. Run one with run_report.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"query": {
+					Type:        "string",
+					Description: "Search text in the report title or description",
+				},
+				"table": {
+					Type:        "string",
+					Description: "Only reports on this table or database view (e.g., 'incident')",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Reports",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listReports)
+	count++
+
+	// Run Report (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "run_report",
+		Description: "Run a saved report with its own table, filter, and grouping. List reports return their columns for the matching records; other report types (bar, pie, single score, ...) return the report's aggregate (count, sum, average, minimum, or maximum), per group when the report is grouped. With chart, aggregate reports also return a PNG bar chart of the results.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withChart(map[string]mcp.Property{
+				"report_id": {
+					Type:        "string",
+					Description: "Report sys_id or exact title",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max records (list reports) or groups",
+					Default:     100,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset (list reports)",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+			Required: []string{"report_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Run Report",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).runReport)
+	count++
+
+	return count
+}
+
+func (r *Registry) listDatabaseViews(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := map[string]string{
+		"sysparm_fields":                 "sys_id,name,label,sys_scope",
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+	query := "ORDERBYname"
+	if text := GetStringArg(args, "query", ""); text != "" {
+		query = LikeFilter(text, "name", "label") + "^" + query
+	}
+	params["sysparm_query"] = query
+
+	result, page, err := r.listRecords("/table/sys_db_view", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list database views", err)), nil
+	}
+
+	views := GetResultList(result)
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d database views", len(views)),
+		"views":   views,
+	}, page)), nil
+}
+
+func (r *Registry) describeDatabaseView(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	name := GetStringArg(args, "view", "")
+	if name == "" {
+		return JSONResult(NewErrorResponse("view is required", nil)), nil
+	}
+	if !tableNamePattern.MatchString(name) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid view name: %s", name), nil)), nil
+	}
+
+	result, err := r.client.Get("/table/sys_db_view", map[string]string{
+		"sysparm_query":  "name=" + name,
+		"sysparm_fields": "sys_id,name,label",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get database view", err)), nil
+	}
+	views := GetResultList(result)
+	if len(views) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Database view not found: %s", name))), nil
+	}
+	view := views[0]
+
+	result, err = r.client.Get("/table/sys_db_view_table", map[string]string{
+		"sysparm_query":  fmt.Sprintf("view=%s^ORDERBYorder", FieldValue(view["sys_id"])),
+		"sysparm_fields": "sys_id,table,variable_prefix,where_clause,left_join,order",
+		"sysparm_limit":  "50",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read the view's tables", err)), nil
+	}
+	viewTables := GetResultList(result)
+
+	// Tables without listed fields contribute all their fields
+	columns := map[string][]string{}
+	if len(viewTables) > 0 {
+		ids := make([]string, len(viewTables))
+		for i, table := range viewTables {
+			ids[i] = FieldValue(table["sys_id"])
+		}
+		result, err = r.client.Get("/table/sys_db_view_table_field", map[string]string{
+			"sysparm_query":  fmt.Sprintf("view_tableIN%s^ORDERBYfield", strings.Join(ids, ",")),
+			"sysparm_fields": "view_table,field",
+			"sysparm_limit":  "1000",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to read the view's fields", err)), nil
+		}
+		for _, field := range GetResultList(result) {
+			id := FieldValue(field["view_table"])
+			columns[id] = append(columns[id], FieldValue(field["field"]))
+		}
+	}
+
+	tables := []map[string]interface{}{}
+	for _, table := range viewTables {
+		prefix := FieldValue(table["variable_prefix"])
+		entry := map[string]interface{}{
+			"table":     FieldValue(table["table"]),
+			"prefix":    prefix,
+			"join":      FieldValue(table["where_clause"]),
+			"left_join": FieldValue(table["left_join"]) == "true",
+		}
+		if fields := columns[FieldValue(table["sys_id"])]; len(fields) > 0 {
+			prefixed := make([]string, len(fields))
+			for i, field := range fields {
+				prefixed[i] = prefix + "_" + field
+			}
+			entry["columns"] = prefixed
+		} else {
+			entry["columns"] = prefix + "_<field> for every field of " + FieldValue(table["table"])
+		}
+		tables = append(tables, entry)
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Database view %s joins %d tables; query it with query_table using table '%s'", name, len(tables), name),
+		"view":    name,
+		"label":   FieldValue(view["label"]),
+		"tables":  tables,
+	}), nil
+}
+
+func (r *Registry) listReports(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := map[string]string{
+		"sysparm_fields":                 "sys_id,title,description,table,type,field,aggregate,sumfield,sys_updated_on",
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+
+	var filters []string
+	if text := GetStringArg(args, "query", ""); text != "" {
+		filters = append(filters, LikeFilter(text, "title", "description"))
+	}
+	if table := GetStringArg(args, "table", ""); table != "" {
+		if !tableNamePattern.MatchString(table) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+		}
+		filters = append(filters, "table="+table)
+	}
+	params["sysparm_query"] = strings.Join(append(filters, "ORDERBYtitle"), "^")
+
+	result, page, err := r.listRecords("/table/sys_report", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list reports", err)), nil
+	}
+
+	reports := GetResultList(result)
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d reports", len(reports)),
+		"reports": reports,
+	}, page)), nil
+}
+
+func (r *Registry) runReport(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	reportID := GetStringArg(args, "report_id", "")
+	if reportID == "" {
+		return JSONResult(NewErrorResponse("report_id is required", nil)), nil
+	}
+	limit := GetIntArg(args, "limit", 100)
+
+	idField := "title"
+	if IsSysID(reportID) {
+		idField = "sys_id"
+	}
+	result, err := r.client.Get("/table/sys_report", map[string]string{
+		"sysparm_query":  fmt.Sprintf("%s=%s", idField, SanitizeQueryValue(reportID)),
+		"sysparm_fields": "sys_id,title,table,type,filter,field,aggregate,sumfield,field_list,orderby_list",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get report", err)), nil
+	}
+	reports := GetResultList(result)
+	if len(reports) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Report not found: %s", reportID))), nil
+	}
+	report := reports[0]
+	table := FieldValue(report["table"])
+	if !tableNamePattern.MatchString(table) {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Report %s has no runnable table (%q)", FieldValue(report["title"]), table), nil)), nil
+	}
+	filter := FieldValue(report["filter"])
+	groupBy := FieldValue(report["field"])
+
+	summary := map[string]interface{}{
+		"sys_id": FieldValue(report["sys_id"]),
+		"title":  FieldValue(report["title"]),
+		"table":  table,
+		"type":   FieldValue(report["type"]),
+		"filter": filter,
+	}
+
+	// List reports return their columns for the matching records
+	if FieldValue(report["type"]) == "list" {
+		params := map[string]string{
+			"sysparm_display_value":          "true",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  fmt.Sprintf("%d", limit),
+			"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		}
+		query := filter
+		if order := FieldValue(report["orderby_list"]); order != "" && !strings.Contains(filter, "ORDERBY") {
+			query = strings.Trim(query+"^ORDERBY"+strings.ReplaceAll(order, ",", "^ORDERBY"), "^")
+		}
+		if query != "" {
+			params["sysparm_query"] = query
+		}
+		if columns := FieldValue(report["field_list"]); columns != "" {
+			params["sysparm_fields"] = columns
+		}
+
+		result, page, err := r.listRecords(fmt.Sprintf("/table/%s", table), params, args)
+		if err != nil {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to run report on %s", table), err)), nil
+		}
+		records := GetResultList(result)
+		return JSONResult(withPaging(map[string]interface{}{
+			"success": true,
+			"message": fmt.Sprintf("Report %s returned %d records", summary["title"], len(records)),
+			"report":  summary,
+			"records": records,
+		}, page)), nil
+	}
+
+	// Other report types chart an aggregate of the matching records, per group when grouped
+	params := map[string]string{"sysparm_display_value": "all"}
+	if filter != "" {
+		params["sysparm_query"] = filter
+	}
+	aggregate := strings.ToUpper(FieldValue(report["aggregate"]))
+	sumField := FieldValue(report["sumfield"])
+	measure, ok := reportAggregates[aggregate]
+	if ok && fieldNamePattern.MatchString(sumField) {
+		params[measure.param] = sumField
+	} else {
+		// Counts, and aggregations the Aggregate API has no parameter for (e.g., COUNT_DISTINCT)
+		if aggregate != "" && aggregate != "COUNT" {
+			summary["note"] = fmt.Sprintf("%s is not supported by the Aggregate API; records are counted instead", aggregate)
+		}
+		aggregate, measure = "COUNT", struct{ param, key string }{"sysparm_count", "count"}
+		params["sysparm_count"] = "true"
+	}
+	summary["aggregate"] = aggregate
+	if groupBy != "" {
+		params["sysparm_group_by"] = groupBy
+		summary["group_by"] = groupBy
+	}
+
+	result, err = r.client.Get(fmt.Sprintf("/stats/%s", table), params)
+	if err != nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to run report on %s", table), err)), nil
+	}
+
+	// Grouped results are a list of groups; ungrouped ones a single stats object
+	var groups []map[string]interface{}
+	if body, ok := result["result"].(map[string]interface{}); ok {
+		groups = []map[string]interface{}{body}
+	} else {
+		groups = GetResultList(result)
+	}
+
+	rows := []map[string]interface{}{}
+	var points []chartPoint
+	for _, group := range groups {
+		row := map[string]interface{}{"value": reportAggregateValue(group, measure.key, sumField)}
+		if fields, ok := group["groupby_fields"].([]interface{}); ok && len(fields) > 0 {
+			if field, ok := fields[0].(map[string]interface{}); ok {
+				row["group"] = field["value"]
+				if display, ok := field["display_value"]; ok {
+					row["group"] = display
+				}
+			}
+		}
+		rows = append(rows, row)
+		if value, ok := chartNumber(row["value"]); ok && len(rows) <= limit {
+			label, _ := row["group"].(string)
+			points = append(points, chartPoint{label: label, value: value})
+		}
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		summary["truncated_groups"] = true
+	}
+
+	return withChartImage(JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Report %s returned %d results", summary["title"], len(rows)),
+		"report":  summary,
+		"results": rows,
+	}), args, barChart, fmt.Sprintf("%s (%s)", summary["title"], aggregate), points), nil
+}
+
+// reportAggregateValue returns the aggregate of one Aggregate API result: the count, or
+// the sum, average, minimum, or maximum of the sum field
+func reportAggregateValue(group map[string]interface{}, key, field string) interface{} {
+	stats, _ := group["stats"].(map[string]interface{})
+	if key == "count" {
+		return stats["count"]
+	}
+	values, _ := stats[key].(map[string]interface{})
+	return values[field]
+}
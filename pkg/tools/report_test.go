@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRunReport tests that list reports read their columns and other reports aggregate per group, charted when asked
+func TestRunReport(t *testing.T) {
+	const (
+		listReportID  = "a0000000000000000000000000000001"
+		chartReportID = "a0000000000000000000000000000002"
+	)
+	reports := map[string]map[string]interface{}{
+		listReportID: {"sys_id": listReportID, "title": "Open P1s", "table": "incident", "type": "list",
+			"filter": "active=true^priority=1", "field_list": "number,short_description", "orderby_list": "number"},
+		chartReportID: {"sys_id": chartReportID, "title": "Open by group", "table": "incident", "type": "bar",
+			"filter": "active=true", "field": "assignment_group", "aggregate": "COUNT_DISTINCT"},
+	}
+
+	var tableParams, statsParams map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := map[string]string{}
+		for key := range r.URL.Query() {
+			params[key] = r.URL.Query().Get(key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_report":
+			id := strings.TrimPrefix(params["sysparm_query"], "sys_id=")
+			records := []interface{}{}
+			if report, ok := reports[id]; ok {
+				records = append(records, report)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+		case "/api/now/table/incident":
+			tableParams = params
+			_, _ = w.Write([]byte(`{"result": [{"number": "INC0010001", "short_description": "Email down"}]}`))
+		case "/api/now/stats/incident":
+			statsParams = params
+			_, _ = w.Write([]byte(`{"result": [
+				{"groupby_fields": [{"field": "assignment_group", "value": "g1", "display_value": "Service Desk"}], "stats": {"count": "4"}},
+				{"groupby_fields": [{"field": "assignment_group", "value": "g2", "display_value": "Network"}], "stats": {"count": "2"}}
+			]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.runReport(map[string]interface{}{"report_id": listReportID})
+	if !strings.Contains(result.Content[0].Text, "INC0010001") {
+		t.Fatalf("Expected the list report's records, got %s", result.Content[0].Text)
+	}
+	if tableParams["sysparm_query"] != "active=true^priority=1^ORDERBYnumber" || tableParams["sysparm_fields"] != "number,short_description" {
+		t.Errorf("Expected the report's filter, order, and columns, got %+v", tableParams)
+	}
+
+	result, _ = registry.runReport(map[string]interface{}{"report_id": chartReportID})
+	var response struct {
+		Report  map[string]interface{}   `json:"report"`
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if statsParams["sysparm_group_by"] != "assignment_group" || statsParams["sysparm_count"] != "true" || statsParams["sysparm_query"] != "active=true" {
+		t.Errorf("Expected a grouped count with the report's filter, got %+v", statsParams)
+	}
+	if len(response.Results) != 2 || response.Results[0]["group"] != "Service Desk" || response.Results[0]["value"] != "4" {
+		t.Errorf("Expected counts per group, got %+v", response.Results)
+	}
+	if response.Report["note"] == nil {
+		t.Errorf("Expected a note that COUNT_DISTINCT fell back to a count, got %+v", response.Report)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("Expected no chart unless asked for, got %d content items", len(result.Content))
+	}
+
+	result, _ = registry.runReport(map[string]interface{}{"report_id": chartReportID, "chart": true})
+	if len(result.Content) != 2 || result.Content[1].Type != "image" || result.Content[1].MimeType != "image/png" {
+		t.Fatalf("Expected the results and a PNG chart, got %+v", result.Content)
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Content[1].Data)
+	if err != nil {
+		t.Fatalf("Failed to decode chart: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to decode chart PNG: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != chartWidth || size.Y != chartHeight {
+		t.Errorf("Expected a %dx%d chart, got %v", chartWidth, chartHeight, size)
+	}
+
+	result, _ = registry.runReport(map[string]interface{}{"report_id": "a0000000000000000000000000000009"})
+	if !strings.Contains(result.Content[0].Text, "Report not found") {
+		t.Errorf("Expected an unknown report to be reported, got %s", result.Content[0].Text)
+	}
+}
+
+// TestDescribeDatabaseView tests that a view's columns carry each table's variable prefix
+func TestDescribeDatabaseView(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_db_view":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "v1", "name": "incident_sla", "label": "Incident SLA"}]}`))
+		case "/api/now/table/sys_db_view_table":
+			_, _ = w.Write([]byte(`{"result": [
+				{"sys_id": "t1", "table": "incident", "variable_prefix": "inc", "where_clause": "", "left_join": "false"},
+				{"sys_id": "t2", "table": "task_sla", "variable_prefix": "taskslatable", "where_clause": "taskslatable_task = inc_sys_id", "left_join": "true"}
+			]}`))
+		case "/api/now/table/sys_db_view_table_field":
+			_, _ = w.Write([]byte(`{"result": [{"view_table": "t1", "field": "number"}, {"view_table": "t1", "field": "priority"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.describeDatabaseView(map[string]interface{}{"view": "incident_sla"})
+	var response struct {
+		Tables []map[string]interface{} `json:"tables"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(response.Tables) != 2 {
+		t.Fatalf("Expected two joined tables, got %+v", response.Tables)
+	}
+	if columns, _ := response.Tables[0]["columns"].([]interface{}); len(columns) != 2 || columns[0] != "inc_number" {
+		t.Errorf("Expected the listed fields with the inc prefix, got %+v", response.Tables[0])
+	}
+	if response.Tables[1]["join"] != "taskslatable_task = inc_sys_id" || response.Tables[1]["left_join"] != true {
+		t.Errorf("Expected the join condition, got %+v", response.Tables[1])
+	}
+
+	result, _ = registry.describeDatabaseView(map[string]interface{}{"view": "incident^name=x"})
+	if !strings.Contains(result.Content[0].Text, "Invalid view name") {
+		t.Errorf("Expected an invalid view name to be rejected, got %s", result.Content[0].Text)
+	}
+}
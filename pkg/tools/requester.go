@@ -0,0 +1,424 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// RequesterPackage is the tool package exposing only caller-safe self-service tools
+const RequesterPackage = "requester"
+
+// registerRequesterTools registers the self-service tools of the requester package.
+// Every handler resolves the calling user and only reads or writes records that
+// user raised, and calls ServiceNow with the caller's own credentials when the
+// request carried them.
+func (r *Registry) registerRequesterTools(server *mcp.Server) int {
+	count := 0
+
+	// Helper for limit/offset constraints
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+
+	// List My Incidents
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "list_my_incidents",
+		Description: "List incidents you raised, newest first.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"active_only": {
+					Type:        "boolean",
+					Description: "Only return incidents that are still open",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     10,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List My Incidents",
+			ReadOnlyHint: true,
+		},
+	}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.listMyIncidents(ctx, args)
+	})
+	count++
+
+	// Get My Incident
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "get_my_incident",
+		Description: "Get the status and customer-visible comments of an incident you raised.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"incident_id": {
+					Type:        "string",
+					Description: "Incident number (e.g., 'INC0010001') or sys_id",
+				},
+			},
+			Required: []string{"incident_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get My Incident",
+			ReadOnlyHint: true,
+		},
+	}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.getMyIncident(ctx, args)
+	})
+	count++
+
+	// Get My Request Status
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "get_my_request_status",
+		Description: "Check the status of service requests you raised or that were raised for you. Omit request_id to list your open requests.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"request_id": {
+					Type:        "string",
+					Description: "Request number (e.g., 'REQ0010001') or sys_id",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get My Request Status",
+			ReadOnlyHint: true,
+		},
+	}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.getMyRequestStatus(ctx, args)
+	})
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Create My Incident
+		r.registerToolWithContext(server, mcp.Tool{
+			Name:        "create_my_incident",
+			Description: "Report an issue. The incident is raised with you as the caller.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"short_description": {
+						Type:        "string",
+						Description: "Brief summary of the issue (e.g., 'Cannot connect to VPN')",
+						MaxLength:   shortDescriptionMaxLength,
+					},
+					"description": {
+						Type:        "string",
+						Description: "Details: what happened, when, and any error messages",
+					},
+					"urgency": {
+						Type:        "string",
+						Description: "How urgent it is for you: 1=High, 2=Medium, 3=Low",
+						Default:     "3",
+						Enum:        []string{"1", "2", "3"},
+					},
+				},
+				Required: []string{"short_description"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create My Incident",
+			},
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).createMyIncident(ctx, args)
+		})
+		count++
+
+		// Add My Incident Comment
+		r.registerToolWithContext(server, mcp.Tool{
+			Name:        "add_my_incident_comment",
+			Description: "Add a comment to an incident you raised. Comments are visible to the support team.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"incident_id": {
+						Type:        "string",
+						Description: "Incident number (e.g., 'INC0010001') or sys_id",
+					},
+					"comment": {
+						Type:        "string",
+						Description: "Comment text",
+					},
+				},
+				Required: []string{"incident_id", "comment"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Add My Incident Comment",
+			},
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).addMyIncidentComment(ctx, args)
+		})
+		count++
+	}
+
+	return count
+}
+
+// requesterIdentity returns the sys_id and user name of the calling user: the
+// ServiceNow user whose credentials came with the request, or in stdio mode the
+// configured basic auth user. HTTP callers without their own credentials, and API key
+// and OAuth identities, which cannot be tied to a user, are rejected rather than
+// falling back to the integration account.
+func (r *Registry) requesterIdentity(ctx context.Context) (string, string, error) {
+	userName := ""
+	if creds := servicenow.CredentialsFromContext(ctx); creds != nil {
+		userName = creds.Username
+	} else if cfg := r.client.Config(); !r.httpMode && cfg != nil && cfg.Auth.Type == servicenow.AuthTypeBasic && cfg.Auth.Basic != nil {
+		userName = cfg.Auth.Basic.Username
+	}
+	if userName == "" {
+		return "", "", fmt.Errorf("%w: requester tools need the caller's ServiceNow username (send %s and %s headers)",
+			ErrUnknownCaller, servicenow.HeaderUsername, servicenow.HeaderPassword)
+	}
+
+	result, err := r.client.GetWithContext(ctx, "/table/sys_user", map[string]string{
+		"sysparm_query":  fmt.Sprintf("user_name=%s", SanitizeQueryValue(userName)),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if records := GetResultList(result); len(records) > 0 {
+		if sysID, ok := records[0]["sys_id"].(string); ok {
+			return sysID, userName, nil
+		}
+	}
+	return "", "", fmt.Errorf("user %w: %s", ErrNotFound, userName)
+}
+
+// findMyRecord returns a record from table matching recordID (number or sys_id) only
+// if ownerField holds the caller's sys_id. Records owned by others are reported as
+// not found so their existence is not revealed.
+func (r *Registry) findMyRecord(ctx context.Context, table, recordID, ownerQuery, fields string) (map[string]interface{}, error) {
+	idField := "number"
+	if IsSysID(recordID) {
+		idField = "sys_id"
+	}
+
+	result, err := r.client.GetWithContext(ctx, fmt.Sprintf("/table/%s", table), map[string]string{
+		"sysparm_query":                  fmt.Sprintf("%s=%s^%s", idField, SanitizeQueryValue(recordID), ownerQuery),
+		"sysparm_fields":                 fields,
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if records := GetResultList(result); len(records) > 0 {
+		return records[0], nil
+	}
+	return nil, fmt.Errorf("%w among your records: %s", ErrNotFound, recordID)
+}
+
+func (r *Registry) listMyIncidents(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	userID, _, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify caller", err)), nil
+	}
+
+	query := fmt.Sprintf("caller_id=%s", userID)
+	if GetBoolArg(args, "active_only", true) {
+		query += "^active=true"
+	}
+
+	result, page, err := r.forContext(ctx).listRecords("/table/incident", map[string]string{
+		"sysparm_query":                  query + "^ORDERBYDESCsys_created_on",
+		"sysparm_fields":                 "number,short_description,state,urgency,sys_created_on,sys_updated_on",
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 10)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list your incidents", err)), nil
+	}
+
+	incidents := GetResultList(result)
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":   true,
+		"message":   fmt.Sprintf("Found %d incidents", len(incidents)),
+		"incidents": incidents,
+	}, page)), nil
+}
+
+func (r *Registry) getMyIncident(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	incidentID := GetStringArg(args, "incident_id", "")
+	if incidentID == "" {
+		return JSONResult(NewErrorResponse("incident_id is required", nil)), nil
+	}
+
+	userID, _, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify caller", err)), nil
+	}
+
+	incident, err := r.findMyRecord(ctx, "incident", incidentID, fmt.Sprintf("caller_id=%s", userID),
+		"sys_id,number,short_description,description,state,urgency,assignment_group,sys_created_on,sys_updated_on,resolved_at,close_notes")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Incident not found", err)), nil
+	}
+
+	response := map[string]interface{}{
+		"success":  true,
+		"message":  "Incident found",
+		"incident": incident,
+	}
+
+	// Customer-visible comments only; work notes stay internal
+	comments, err := r.client.GetWithContext(ctx, "/table/sys_journal_field", map[string]string{
+		"sysparm_query":  fmt.Sprintf("element_id=%s^element=comments^ORDERBYDESCsys_created_on", incident["sys_id"]),
+		"sysparm_fields": "value,sys_created_by,sys_created_on",
+		"sysparm_limit":  "20",
+	})
+	if err == nil {
+		response["comments"] = GetResultList(comments)
+	}
+
+	return JSONResult(response), nil
+}
+
+func (r *Registry) getMyRequestStatus(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	userID, _, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify caller", err)), nil
+	}
+
+	ownerQuery := fmt.Sprintf("requested_for=%[1]s^ORopened_by=%[1]s", userID)
+	fields := "sys_id,number,short_description,request_state,approval,stage,opened_at,due_date"
+
+	requestID := GetStringArg(args, "request_id", "")
+	if requestID == "" {
+		result, err := r.client.GetWithContext(ctx, "/table/sc_request", map[string]string{
+			"sysparm_query":                  ownerQuery + "^active=true^ORDERBYDESCopened_at",
+			"sysparm_fields":                 fields,
+			"sysparm_display_value":          "true",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  "20",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to list your requests", err)), nil
+		}
+		requests := GetResultList(result)
+		return JSONResult(map[string]interface{}{
+			"success":  true,
+			"message":  fmt.Sprintf("Found %d open requests", len(requests)),
+			"requests": requests,
+		}), nil
+	}
+
+	request, err := r.findMyRecord(ctx, "sc_request", requestID, ownerQuery, fields)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Request not found", err)), nil
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"message": "Request found",
+		"request": request,
+	}
+
+	items, err := r.client.GetWithContext(ctx, "/table/sc_req_item", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("request=%s", request["sys_id"]),
+		"sysparm_fields":                 "number,cat_item,stage,state,due_date",
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err == nil {
+		response["items"] = GetResultList(items)
+	}
+
+	return JSONResult(response), nil
+}
+
+func (r *Registry) createMyIncident(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	shortDesc := GetStringArg(args, "short_description", "")
+	if shortDesc == "" {
+		return JSONResult(NewErrorResponse("short_description is required", nil)), nil
+	}
+
+	userID, _, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify caller", err)), nil
+	}
+
+	data := map[string]interface{}{
+		"short_description": shortDesc,
+		"caller_id":         userID,
+		"opened_by":         userID,
+		"urgency":           GetStringArg(args, "urgency", "3"),
+		"contact_type":      "self-service",
+	}
+	if v := GetStringArg(args, "description", ""); v != "" {
+		data["description"] = v
+	}
+
+	result, err := r.client.PostWithContext(ctx, "/table/incident", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create incident", err)), nil
+	}
+
+	if resultData, ok := result["result"].(map[string]interface{}); ok {
+		return JSONResult(map[string]interface{}{
+			"success":         true,
+			"message":         "Incident created successfully",
+			"incident_number": resultData["number"],
+		}), nil
+	}
+
+	return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+}
+
+func (r *Registry) addMyIncidentComment(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	incidentID := GetStringArg(args, "incident_id", "")
+	comment := GetStringArg(args, "comment", "")
+	if incidentID == "" || comment == "" {
+		return JSONResult(NewErrorResponse("incident_id and comment are required", nil)), nil
+	}
+
+	userID, _, err := r.requesterIdentity(ctx)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify caller", err)), nil
+	}
+
+	incident, err := r.findMyRecord(ctx, "incident", incidentID, fmt.Sprintf("caller_id=%s", userID), "sys_id,number")
+	if err != nil {
+		return JSONResult(NewErrorResponse("Incident not found", err)), nil
+	}
+
+	if _, err := r.client.PutWithContext(ctx, fmt.Sprintf("/table/incident/%s", incident["sys_id"]), map[string]interface{}{
+		"comments": comment,
+	}); err != nil {
+		return JSONResult(NewErrorResponse("Failed to add comment", err)), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":         true,
+		"message":         "Comment added successfully",
+		"incident_number": incident["number"],
+	}), nil
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// TestRequesterPackage tests that the requester package registers only self-service tools
+func TestRequesterPackage(t *testing.T) {
+	registry, _ := newTestRegistry(t, "https://example.service-now.com", false)
+	if err := registry.SetToolPackage("field_services"); err == nil {
+		t.Error("Expected unsupported package to be rejected")
+	}
+	if err := registry.SetToolPackage(RequesterPackage); err != nil {
+		t.Fatalf("Expected requester package to be accepted, got %v", err)
+	}
+
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	var names []string
+	for _, tool := range server.ListTools() {
+		names = append(names, tool.Name)
+	}
+	want := "list_my_incidents,get_my_incident,get_my_request_status,create_my_incident,add_my_incident_comment,list_tool_packages"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("Expected tools %s, got %s", want, got)
+	}
+}
+
+// TestRequesterOwnership tests that requester tools act as the calling user and hide other users' records
+func TestRequesterOwnership(t *testing.T) {
+	users := map[string]string{"jane.doe": "jane", "test": "integration"}
+
+	var incidentQuery, authUser string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		authUser, _, _ = r.BasicAuth()
+		switch r.URL.Path {
+		case "/api/now/table/sys_user":
+			name := strings.TrimPrefix(r.URL.Query().Get("sysparm_query"), "user_name=")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": users[name]}}})
+		case "/api/now/table/incident":
+			incidentQuery = r.URL.Query().Get("sysparm_query")
+			// Only jane's incident exists
+			result := []interface{}{}
+			if strings.Contains(incidentQuery, "caller_id=jane") {
+				result = append(result, map[string]interface{}{"sys_id": "inc1", "number": "INC0010001"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+		case "/api/now/table/sys_journal_field":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	jane := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: "jane.doe", Password: "secret"})
+	result, _ := registry.getMyIncident(jane, map[string]interface{}{"incident_id": "INC0010001"})
+	if !strings.Contains(result.Content[0].Text, `"success": true`) {
+		t.Fatalf("Expected jane to see her incident, got %s", result.Content[0].Text)
+	}
+	if incidentQuery != "number=INC0010001^caller_id=jane" || authUser != "jane.doe" {
+		t.Errorf("Expected caller-scoped query with jane's credentials, got %q as %q", incidentQuery, authUser)
+	}
+
+	// Without request credentials the configured user is the caller
+	result, _ = registry.getMyIncident(context.Background(), map[string]interface{}{"incident_id": "INC0010001"})
+	if !strings.Contains(result.Content[0].Text, "Incident not found") {
+		t.Errorf("Expected another user's incident to be hidden, got %s", result.Content[0].Text)
+	}
+
+	apiKey := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{APIKey: "key"})
+	result, _ = registry.listMyIncidents(apiKey, map[string]interface{}{})
+	if !strings.Contains(result.Content[0].Text, "Failed to identify caller") {
+		t.Errorf("Expected API key callers to be rejected, got %s", result.Content[0].Text)
+	}
+
+	// In HTTP mode a caller without credentials is not the configured user
+	registry.SetHTTPMode(true)
+	authUser = ""
+	result, _ = registry.getMyIncident(context.Background(), map[string]interface{}{"incident_id": "INC0010001"})
+	if !strings.Contains(result.Content[0].Text, "Failed to identify caller") || !strings.Contains(result.Content[0].Text, `"code": "PERMISSION_DENIED"`) {
+		t.Errorf("Expected HTTP callers without credentials to be rejected, got %s", result.Content[0].Text)
+	}
+	if authUser != "" {
+		t.Errorf("Expected no request to the instance, got one as %q", authUser)
+	}
+	result, _ = registry.getMyIncident(jane, map[string]interface{}{"incident_id": "INC0010001"})
+	if !strings.Contains(result.Content[0].Text, `"success": true`) {
+		t.Errorf("Expected HTTP callers with credentials to be served, got %s", result.Content[0].Text)
+	}
+}
+
+// TestRequesterWritesIndexed tests that records the requester write tools change are indexed under the session
+func TestRequesterWritesIndexed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_user":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": "jane"}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": "inc2", "number": "INC0010002"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/incident":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "inc1", "number": "INC0010001"}})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/incident/inc2":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "inc2", "number": "INC0010002"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	if err := registry.SetSessionIndex(filepath.Join(t.TempDir(), "index.jsonl")); err != nil {
+		t.Fatalf("Failed to enable the session index: %v", err)
+	}
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	create, _ := server.Handler("create_my_incident")
+	if result, _ := create(context.Background(), map[string]interface{}{"short_description": "VPN down"}); result.IsError || !strings.Contains(result.Content[0].Text, `"success": true`) {
+		t.Fatalf("Expected the incident to be created, got %+v", result)
+	}
+	comment, _ := server.Handler("add_my_incident_comment")
+	if result, _ := comment(context.Background(), map[string]interface{}{"incident_id": "INC0010002", "comment": "Still down"}); result.IsError || !strings.Contains(result.Content[0].Text, `"success": true`) {
+		t.Fatalf("Expected the comment to be added, got %+v", result)
+	}
+
+	listChanges, _ := server.Handler("list_session_changes")
+	result, _ := listChanges(context.Background(), map[string]interface{}{})
+	var body struct {
+		Changes []recordChange `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(body.Changes) != 2 || body.Changes[0].SysID != "inc1" || body.Changes[0].Tool != "create_my_incident" ||
+		body.Changes[1].SysID != "inc2" || body.Changes[1].Tool != "add_my_incident_comment" {
+		t.Errorf("Expected both incidents in the session index, got %+v", body.Changes)
+	}
+}
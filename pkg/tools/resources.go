@@ -0,0 +1,307 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// recordResourceType describes the records served under servicenow://<kind>/<number>
+type recordResourceType struct {
+	table string
+	label string
+	// fields are rendered as a list, in order; longFields as sections after it
+	fields     []string
+	longFields []string
+}
+
+// recordResourceTypes are the record kinds exposed as resources, by URI host
+var recordResourceTypes = map[string]recordResourceType{
+	"incident": {
+		table:      "incident",
+		label:      "Incident",
+		fields:     []string{"state", "priority", "impact", "urgency", "category", "subcategory", "caller_id", "assignment_group", "assigned_to", "cmdb_ci", "opened_at", "sys_updated_on"},
+		longFields: []string{"description", "close_notes"},
+	},
+	"change": {
+		table:      "change_request",
+		label:      "Change request",
+		fields:     []string{"type", "state", "risk", "impact", "priority", "assignment_group", "assigned_to", "cmdb_ci", "start_date", "end_date", "sys_updated_on"},
+		longFields: []string{"description", "justification", "implementation_plan", "backout_plan", "test_plan"},
+	},
+	"problem": {
+		table:      "problem",
+		label:      "Problem",
+		fields:     []string{"state", "priority", "assignment_group", "assigned_to", "cmdb_ci", "opened_at", "sys_updated_on"},
+		longFields: []string{"description", "cause_notes", "workaround", "fix_notes"},
+	},
+	"kb": {
+		table:      "kb_knowledge",
+		label:      "Knowledge article",
+		fields:     []string{"workflow_state", "kb_knowledge_base", "kb_category", "language", "author", "published", "valid_to", "sys_updated_on"},
+		longFields: []string{"text"},
+	},
+}
+
+// maxRecentResources bounds the recently read records listed by resources/list
+const maxRecentResources = 20
+
+// recordResource identifies a record resource parsed from its URI
+type recordResource struct {
+	kind   string
+	id     string
+	format string
+}
+
+// parseRecordURI parses servicenow://<kind>/<number or sys_id>[?format=json|markdown]
+func parseRecordURI(uri string) (recordResource, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "servicenow" {
+		return recordResource{}, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+	if _, ok := recordResourceTypes[u.Host]; !ok {
+		return recordResource{}, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+	id := strings.TrimPrefix(u.Path, "/")
+	if id == "" || strings.Contains(id, "/") {
+		return recordResource{}, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+	format := u.Query().Get("format")
+	switch format {
+	case "":
+		format = "markdown"
+	case "markdown", "json":
+	default:
+		return recordResource{}, fmt.Errorf("unsupported format %q (use markdown or json)", format)
+	}
+	return recordResource{kind: u.Host, id: id, format: format}, nil
+}
+
+// uri returns the canonical URI of the record, without a format
+func (res recordResource) uri() string {
+	return fmt.Sprintf("servicenow://%s/%s", res.kind, res.id)
+}
+
+// SetPinnedResources sets the record URIs (e.g., servicenow://kb/KB0010001) always
+// listed by resources/list
+func (r *Registry) SetPinnedResources(uris []string) error {
+	pinned := make([]string, 0, len(uris))
+	for _, uri := range uris {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			continue
+		}
+		res, err := parseRecordURI(uri)
+		if err != nil {
+			return fmt.Errorf("invalid record resource %q", uri)
+		}
+		pinned = append(pinned, res.uri())
+	}
+	r.resources.mu.Lock()
+	defer r.resources.mu.Unlock()
+	r.resources.pinned = pinned
+	return nil
+}
+
+// recordResources tracks the pinned and recently read record resources
+type recordResources struct {
+	mu     sync.Mutex
+	pinned []string
+	// recent holds record URIs, most recently read first
+	recent []string
+}
+
+// touch moves uri to the front of the recently read records
+func (rr *recordResources) touch(uri string) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	recent := []string{uri}
+	for _, existing := range rr.recent {
+		if existing != uri && len(recent) < maxRecentResources {
+			recent = append(recent, existing)
+		}
+	}
+	rr.recent = recent
+}
+
+// RegisterResources registers the registry's MCP resources with the server. None are
+// registered under RequesterPackage: resources read any record the configured
+// credentials can see, not only the caller's own, so they would bypass the requester
+// tools' scoping. Call it after SetToolPackage.
+func (r *Registry) RegisterResources(server *mcp.Server) {
+	if r.ToolPackage() == RequesterPackage {
+		return
+	}
+	server.RegisterResourceProvider(resourceProviders{
+		digestProvider{registry: r},
+		recordProvider{registry: r},
+		queueProvider{registry: r},
+	})
+}
+
+// resourceProviders serves the resources of several providers; reads go to the
+// first provider that knows the URI
+type resourceProviders []mcp.ContextResourceProvider
+
+// ListResources lists the resources of every provider
+func (providers resourceProviders) ListResources() []mcp.Resource {
+	resources := []mcp.Resource{}
+	for _, provider := range providers {
+		resources = append(resources, provider.ListResources()...)
+	}
+	return resources
+}
+
+// ReadResource reads a resource without request context
+func (providers resourceProviders) ReadResource(uri string) (*mcp.ReadResourceResult, error) {
+	return providers.ReadResourceWithContext(context.Background(), uri)
+}
+
+// ReadResourceWithContext reads a resource from the provider that serves uri
+func (providers resourceProviders) ReadResourceWithContext(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	for _, provider := range providers {
+		result, err := provider.ReadResourceWithContext(ctx, uri)
+		if !errors.Is(err, mcp.ErrResourceNotFound) {
+			return result, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+}
+
+// recordProvider serves incidents, changes, problems, and knowledge articles as
+// servicenow://<kind>/<number> resources
+type recordProvider struct {
+	registry *Registry
+}
+
+// ListResources lists the pinned records, then the recently read ones. Only URIs are
+// kept, so the list doesn't reveal record contents to other callers.
+func (p recordProvider) ListResources() []mcp.Resource {
+	rr := p.registry.resources
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	resources := []mcp.Resource{}
+	listed := map[string]bool{}
+	add := func(uri, description string) {
+		if listed[uri] {
+			return
+		}
+		listed[uri] = true
+		res, _ := parseRecordURI(uri)
+		resources = append(resources, mcp.Resource{
+			URI:         uri,
+			Name:        res.id,
+			Description: fmt.Sprintf("%s %s (%s)", recordResourceTypes[res.kind].label, res.id, description),
+			MimeType:    "text/markdown",
+		})
+	}
+	for _, uri := range rr.pinned {
+		add(uri, "pinned")
+	}
+	for _, uri := range rr.recent {
+		add(uri, "recently read")
+	}
+	return resources
+}
+
+// ReadResource reads a record with the configured credentials
+func (p recordProvider) ReadResource(uri string) (*mcp.ReadResourceResult, error) {
+	return p.ReadResourceWithContext(context.Background(), uri)
+}
+
+// ReadResourceWithContext reads a record with the credentials that came with the request
+func (p recordProvider) ReadResourceWithContext(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	res, err := parseRecordURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	resourceType := recordResourceTypes[res.kind]
+
+	record, err := p.registry.forContext(ctx).getResourceRecord(resourceType, res.id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%w: %s", mcp.ErrResourceNotFound, uri)
+	}
+	p.registry.resources.touch(res.uri())
+
+	if res.format == "json" {
+		data, err := json.MarshalIndent(record, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{
+			Contents: []mcp.ResourceContent{{URI: uri, MimeType: "application/json", Text: string(data)}},
+		}, nil
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContent{{URI: uri, MimeType: "text/markdown", Text: renderRecordMarkdown(resourceType, record)}},
+	}, nil
+}
+
+// getResourceRecord returns a record by number or sys_id, or nil if there is none
+func (r *Registry) getResourceRecord(resourceType recordResourceType, id string) (map[string]interface{}, error) {
+	query := fmt.Sprintf("number=%s", SanitizeQueryValue(id))
+	if IsSysID(id) {
+		query = fmt.Sprintf("sys_id=%s", id)
+	}
+	fields := append([]string{"sys_id", "number", "short_description"}, resourceType.fields...)
+	result, err := r.client.Get(fmt.Sprintf("/table/%s", resourceType.table), map[string]string{
+		"sysparm_query":                  query,
+		"sysparm_fields":                 strings.Join(append(fields, resourceType.longFields...), ","),
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+// renderRecordMarkdown renders a record as a title, a field list, and a section per long text field
+func renderRecordMarkdown(resourceType recordResourceType, record map[string]interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s %s: %s\n\n", resourceType.label, FieldDisplay(record["number"]), FieldDisplay(record["short_description"]))
+	for _, field := range resourceType.fields {
+		if value := FieldDisplay(record[field]); value != "" {
+			fmt.Fprintf(&b, "- **%s**: %s\n", fieldLabel(field), value)
+		}
+	}
+	for _, field := range resourceType.longFields {
+		if value := FieldDisplay(record[field]); value != "" {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", fieldLabel(field), value)
+		}
+	}
+	return b.String()
+}
+
+// fieldLabels are the headings of fields whose names don't read well
+var fieldLabels = map[string]string{
+	"caller_id":         "Caller",
+	"cmdb_ci":           "Configuration item",
+	"kb_knowledge_base": "Knowledge base",
+	"kb_category":       "Category",
+	"sys_updated_on":    "Updated",
+}
+
+// fieldLabel turns a field name into a heading (e.g., "assignment_group" is "Assignment group")
+func fieldLabel(field string) string {
+	if label, ok := fieldLabels[field]; ok {
+		return label
+	}
+	label := strings.ReplaceAll(field, "_", " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
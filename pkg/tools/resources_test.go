@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestRecordResources tests reading records as markdown or JSON and listing pinned and recently read records
+func TestRecordResources(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/now/table/incident" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		result := []interface{}{}
+		if r.URL.Query().Get("sysparm_query") == "number=INC0010001" {
+			result = append(result, map[string]interface{}{
+				"number":            map[string]interface{}{"value": "INC0010001", "display_value": "INC0010001"},
+				"short_description": map[string]interface{}{"value": "Email down", "display_value": "Email down"},
+				"priority":          map[string]interface{}{"value": "2", "display_value": "2 - High"},
+				"assignment_group":  map[string]interface{}{"value": "g1", "display_value": "Service Desk"},
+				"description":       map[string]interface{}{"value": "Outlook cannot connect", "display_value": "Outlook cannot connect"},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	if err := registry.SetPinnedResources([]string{"servicenow://kb/KB0010001", " "}); err != nil {
+		t.Fatalf("Expected pinned resources to be accepted, got %v", err)
+	}
+	if err := registry.SetPinnedResources([]string{"servicenow://user/admin"}); err == nil {
+		t.Error("Expected an unsupported record kind to be rejected")
+	}
+	_ = registry.SetPinnedResources([]string{"servicenow://kb/KB0010001"})
+
+	providers := resourceProviders{digestProvider{registry: registry}, recordProvider{registry: registry}}
+	ctx := context.Background()
+
+	result, err := providers.ReadResourceWithContext(ctx, "servicenow://incident/INC0010001")
+	if err != nil {
+		t.Fatalf("Expected the incident to be read, got %v", err)
+	}
+	text := result.Contents[0].Text
+	for _, want := range []string{"# Incident INC0010001: Email down", "- **Priority**: 2 - High", "- **Assignment group**: Service Desk", "## Description\n\nOutlook cannot connect"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	result, err = providers.ReadResourceWithContext(ctx, "servicenow://incident/INC0010001?format=json")
+	if err != nil || result.Contents[0].MimeType != "application/json" || !strings.Contains(result.Contents[0].Text, `"display_value": "Email down"`) {
+		t.Errorf("Expected the record as JSON, got %+v (%v)", result, err)
+	}
+
+	for _, uri := range []string{"servicenow://incident/INC0019999", "servicenow://user/admin", "https://example.com/INC0010001"} {
+		if _, err := providers.ReadResourceWithContext(ctx, uri); !errors.Is(err, mcp.ErrResourceNotFound) {
+			t.Errorf("Expected %s to be not found, got %v", uri, err)
+		}
+	}
+
+	var uris []string
+	for _, resource := range providers.ListResources() {
+		uris = append(uris, resource.URI)
+	}
+	want := []string{DailyDigestURI, "servicenow://kb/KB0010001", "servicenow://incident/INC0010001"}
+	if strings.Join(uris, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected resources %v, got %v", want, uris)
+	}
+}
+
+// TestQueueResource tests that a group's open incident queue is listed for configured groups and carries an etag that changes with the queue
+func TestQueueResource(t *testing.T) {
+	updated := "2026-10-15 08:00:00"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/sys_user_group":
+			if r.URL.Query().Get("sysparm_query") != "name=Network Ops" {
+				t.Errorf("Unexpected group query %s", r.URL.Query().Get("sysparm_query"))
+			}
+			result = []interface{}{map[string]interface{}{"sys_id": "g1"}}
+		case "/api/now/table/incident":
+			if got := r.URL.Query().Get("sysparm_query"); got != "active=true^assignment_group=g1^ORDERBYpriority^ORDERBYopened_at" {
+				t.Errorf("Unexpected queue query %s", got)
+			}
+			result = []interface{}{map[string]interface{}{
+				"sys_id":            map[string]interface{}{"value": "i1", "display_value": "i1"},
+				"number":            map[string]interface{}{"value": "INC0010001", "display_value": "INC0010001"},
+				"short_description": map[string]interface{}{"value": "Router | down", "display_value": "Router | down"},
+				"sys_updated_on":    map[string]interface{}{"value": updated, "display_value": updated},
+			}}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	registry.SetQueueResources([]string{"Network Ops", " "})
+	providers := resourceProviders{recordProvider{registry: registry}, queueProvider{registry: registry}}
+
+	resources := providers.ListResources()
+	if len(resources) != 1 || resources[0].URI != "servicenow://queue/Network%20Ops/open" {
+		t.Fatalf("Expected the configured queue to be listed, got %+v", resources)
+	}
+
+	result, err := providers.ReadResourceWithContext(context.Background(), resources[0].URI)
+	if err != nil {
+		t.Fatalf("Expected the queue to be read, got %v", err)
+	}
+	content := result.Contents[0]
+	etag, _ := content.Meta["etag"].(string)
+	for _, want := range []string{"# Open incidents: Network Ops", "- **ETag**: " + etag, "- **Open**: 1", `| INC0010001 |`, `Router \| down`} {
+		if etag == "" || !strings.Contains(content.Text, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, content.Text)
+		}
+	}
+
+	result, _ = providers.ReadResourceWithContext(context.Background(), resources[0].URI)
+	if result.Contents[0].Meta["etag"] != etag {
+		t.Error("Expected an unchanged queue to keep its etag")
+	}
+	updated = "2026-10-15 09:00:00"
+	result, _ = providers.ReadResourceWithContext(context.Background(), resources[0].URI+"?format=json")
+	if result.Contents[0].Meta["etag"] == etag || result.Contents[0].MimeType != "application/json" {
+		t.Errorf("Expected an updated incident to change the etag, got %+v", result.Contents[0])
+	}
+
+	if _, err := providers.ReadResourceWithContext(context.Background(), "servicenow://queue/Network%20Ops/closed"); !errors.Is(err, mcp.ErrResourceNotFound) {
+		t.Errorf("Expected an unknown queue view to be not found, got %v", err)
+	}
+}
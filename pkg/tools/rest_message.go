@@ -0,0 +1,561 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// restAuthProfileTables are the tables holding the auth profiles each REST authentication type references
+var restAuthProfileTables = map[string]struct{ field, table string }{
+	"basic":  {"basic_auth_profile", "sys_auth_profile_basic"},
+	"oauth2": {"oauth2_profile", "oauth_entity_profile"},
+}
+
+// restHTTPMethods are the HTTP methods of REST message functions
+var restHTTPMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// secretHeaderWords mark header names whose values are credentials
+var secretHeaderWords = []string{"auth", "token", "key", "secret", "password", "cookie"}
+
+// restMessageFields are the fields of the message returned by get_rest_message
+const restMessageFields = "sys_id,name,description,rest_endpoint,authentication_type,basic_auth_profile,oauth2_profile,use_mutual_auth,access,sys_scope,sys_updated_on"
+
+// restFunctionFields are the fields of each function returned by get_rest_message
+const restFunctionFields = "sys_id,function_name,http_method,rest_endpoint,authentication_type,basic_auth_profile,oauth2_profile,content,use_mid_server"
+
+// registerRESTMessageTools registers the outbound REST message tools
+func (r *Registry) registerRESTMessageTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// List REST Messages
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_rest_messages",
+		Description: "List outbound REST messages (sys_rest_message): the integrations the instance calls, with their endpoint and authentication type.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"query": {
+					Type:        "string",
+					Description: "Search text in the message name or endpoint",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List REST Messages",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listRESTMessages)
+	count++
+
+	// Get REST Message
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_rest_message",
+		Description: "Get an outbound REST message with its HTTP methods (sys_rest_message_fn): endpoint, HTTP method, authentication and auth profile, headers, query parameters, variable substitutions, and request body. Credential header values are masked.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"message_id": {
+					Type:        "string",
+					Description: "REST message sys_id or name",
+				},
+			},
+			Required: []string{"message_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get REST Message",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getRESTMessage)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Create REST Message
+		r.registerTool(server, mcp.Tool{
+			Name:        "create_rest_message",
+			Description: "Create an outbound REST message: the base endpoint, authentication, and headers shared by its HTTP methods. Add methods with create_rest_message_function.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"name": {
+						Type:        "string",
+						Description: "REST message name",
+					},
+					"endpoint": {
+						Type:        "string",
+						Description: "Base endpoint URL; may contain ${variables} (e.g., 'https://api.example.com/v1')",
+					},
+					"description": {
+						Type:        "string",
+						Description: "Description",
+					},
+					"authentication_type": {
+						Type:        "string",
+						Description: "Authentication used by the message's methods",
+						Default:     "no_authentication",
+						Enum:        []string{"no_authentication", "basic", "oauth2"},
+					},
+					"auth_profile": {
+						Type:        "string",
+						Description: "Basic auth profile (sys_auth_profile_basic) or OAuth profile (oauth_entity_profile) sys_id or name, for the basic and oauth2 authentication types",
+					},
+					"headers": {
+						Type:        "object",
+						Description: "HTTP headers sent by every method (e.g., {\"Accept\": \"application/json\"})",
+					},
+				},
+				Required: []string{"name", "endpoint"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create REST Message",
+			},
+		}, (*Registry).createRESTMessage)
+		count++
+
+		// Create REST Message Function
+		r.registerTool(server, mcp.Tool{
+			Name:        "create_rest_message_function",
+			Description: "Add an HTTP method (sys_rest_message_fn) to a REST message, with its HTTP verb, endpoint, authentication, headers, query parameters, and request body.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"message_id": {
+						Type:        "string",
+						Description: "REST message sys_id or name",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Method name (e.g., 'Create ticket')",
+					},
+					"http_method": {
+						Type:        "string",
+						Description: "HTTP method",
+						Enum:        restHTTPMethods,
+					},
+					"endpoint": {
+						Type:        "string",
+						Description: "Endpoint URL; may contain ${variables} (default: the message's endpoint)",
+					},
+					"authentication_type": {
+						Type:        "string",
+						Description: "Authentication, or inherit_from_parent to use the message's",
+						Default:     "inherit_from_parent",
+						Enum:        []string{"inherit_from_parent", "no_authentication", "basic", "oauth2"},
+					},
+					"auth_profile": {
+						Type:        "string",
+						Description: "Basic auth or OAuth profile sys_id or name, for the basic and oauth2 authentication types",
+					},
+					"content": {
+						Type:        "string",
+						Description: "Request body; may contain ${variables} (e.g., '{\"summary\": \"${summary}\"}')",
+					},
+					"headers": {
+						Type:        "object",
+						Description: "HTTP headers sent by this method (e.g., {\"Content-Type\": \"application/json\"})",
+					},
+					"query_parameters": {
+						Type:        "object",
+						Description: "HTTP query parameters (e.g., {\"limit\": \"10\"})",
+					},
+				},
+				Required: []string{"message_id", "name", "http_method"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Create REST Message Function",
+			},
+		}, (*Registry).createRESTMessageFunction)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listRESTMessages(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	params := map[string]string{
+		"sysparm_fields":                 readFields(args, "sys_id,name,description,rest_endpoint,authentication_type"),
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}
+	query := "ORDERBYname"
+	if text := GetStringArg(args, "query", ""); text != "" {
+		query = LikeFilter(text, "name", "rest_endpoint") + "^" + query
+	}
+	params["sysparm_query"] = query
+
+	result, page, err := r.listRecords("/table/sys_rest_message", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list REST messages", err)), nil
+	}
+
+	messages := []map[string]interface{}{}
+	for _, data := range GetResultList(result) {
+		messages = append(messages, selectFields(map[string]interface{}{
+			"sys_id":              data["sys_id"],
+			"name":                data["name"],
+			"description":         data["description"],
+			"endpoint":            data["rest_endpoint"],
+			"authentication_type": data["authentication_type"],
+		}, data, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":       true,
+		"message":       fmt.Sprintf("Found %d REST messages", len(messages)),
+		"rest_messages": messages,
+	}, page)), nil
+}
+
+func (r *Registry) getRESTMessage(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	messageID := GetStringArg(args, "message_id", "")
+	if messageID == "" {
+		return JSONResult(NewErrorResponse("message_id is required", nil)), nil
+	}
+
+	idField := "name"
+	if IsSysID(messageID) {
+		idField = "sys_id"
+	}
+	result, err := r.client.Get("/table/sys_rest_message", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("%s=%s", idField, SanitizeQueryValue(messageID)),
+		"sysparm_fields":                 restMessageFields,
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get REST message", err)), nil
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("REST message not found: %s", messageID))), nil
+	}
+	data := records[0]
+	sysID := FieldValue(data["sys_id"])
+
+	message := map[string]interface{}{
+		"sys_id":              sysID,
+		"name":                FieldValue(data["name"]),
+		"description":         FieldValue(data["description"]),
+		"endpoint":            FieldValue(data["rest_endpoint"]),
+		"authentication_type": FieldValue(data["authentication_type"]),
+		"use_mutual_auth":     FieldValue(data["use_mutual_auth"]) == "true",
+		"access":              FieldDisplay(data["access"]),
+		"scope":               FieldDisplay(data["sys_scope"]),
+		"updated_on":          FieldDisplay(data["sys_updated_on"]),
+	}
+	if profile := restAuthProfile(data); profile != nil {
+		message["auth_profile"] = profile
+	}
+
+	// Details failing to load are reported under warnings, so the message is still returned
+	var warnings []string
+	related := func(name, table, query, fields string) []map[string]interface{} {
+		result, err := r.client.Get("/table/"+table, map[string]string{
+			"sysparm_query":                  query,
+			"sysparm_fields":                 fields,
+			"sysparm_display_value":          "all",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  "500",
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s not read: %v", name, err))
+			return nil
+		}
+		return GetResultList(result)
+	}
+
+	message["headers"] = restNameValues(related("Headers", "sys_rest_message_headers",
+		fmt.Sprintf("rest_message=%s^ORDERBYname", sysID), "name,value"), true)
+
+	functionRecords := related("HTTP methods", "sys_rest_message_fn",
+		fmt.Sprintf("rest_message=%s^ORDERBYfunction_name", sysID), restFunctionFields)
+	functionIDs := make([]string, 0, len(functionRecords))
+	for _, fn := range functionRecords {
+		functionIDs = append(functionIDs, FieldValue(fn["sys_id"]))
+	}
+
+	// Headers, query parameters, and variables of all methods, keyed by method sys_id
+	byFunction := func(records []map[string]interface{}) map[string][]map[string]interface{} {
+		grouped := map[string][]map[string]interface{}{}
+		for _, record := range records {
+			id := FieldValue(record["rest_message_function"])
+			grouped[id] = append(grouped[id], record)
+		}
+		return grouped
+	}
+	var headers, parameters, variables map[string][]map[string]interface{}
+	if len(functionIDs) > 0 {
+		ids := strings.Join(functionIDs, ",")
+		headers = byFunction(related("Method headers", "sys_rest_message_fn_headers",
+			fmt.Sprintf("rest_message_functionIN%s^ORDERBYname", ids), "rest_message_function,name,value"))
+		parameters = byFunction(related("Query parameters", "sys_rest_message_fn_parameters",
+			fmt.Sprintf("rest_message_functionIN%s^ORDERBYorder", ids), "rest_message_function,name,value"))
+		variables = byFunction(related("Variable substitutions", "sys_rest_message_fn_param_defs",
+			fmt.Sprintf("rest_message_functionIN%s^ORDERBYname", ids), "rest_message_function,name,value"))
+	}
+
+	functions := []map[string]interface{}{}
+	for _, fn := range functionRecords {
+		id := FieldValue(fn["sys_id"])
+		function := map[string]interface{}{
+			"sys_id":              id,
+			"name":                FieldValue(fn["function_name"]),
+			"http_method":         strings.ToUpper(FieldValue(fn["http_method"])),
+			"endpoint":            FieldValue(fn["rest_endpoint"]),
+			"authentication_type": FieldValue(fn["authentication_type"]),
+			"use_mid_server":      FieldValue(fn["use_mid_server"]) == "true",
+			"headers":             restNameValues(headers[id], true),
+			"query_parameters":    restNameValues(parameters[id], false),
+			"variables":           restNameValues(variables[id], false),
+		}
+		if profile := restAuthProfile(fn); profile != nil {
+			function["auth_profile"] = profile
+		}
+		if content := FieldValue(fn["content"]); content != "" {
+			function["content"] = content
+		}
+		functions = append(functions, function)
+	}
+	message["functions"] = functions
+
+	response := map[string]interface{}{
+		"success":      true,
+		"message":      fmt.Sprintf("REST message %s has %d HTTP methods", message["name"], len(functions)),
+		"rest_message": message,
+	}
+	withWarnings(response, warnings...)
+	return JSONResult(response), nil
+}
+
+// restAuthProfile returns the auth profile a REST message or method record references
+// for its authentication type, or nil when it uses none
+func restAuthProfile(record map[string]interface{}) map[string]interface{} {
+	profile, ok := restAuthProfileTables[FieldValue(record["authentication_type"])]
+	if !ok || FieldValue(record[profile.field]) == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		"table":  profile.table,
+		"sys_id": FieldValue(record[profile.field]),
+		"name":   FieldDisplay(record[profile.field]),
+	}
+}
+
+// restNameValues returns the name and value of header, parameter, or variable records,
+// masking the values of credential headers when masking is set
+func restNameValues(records []map[string]interface{}, masking bool) []map[string]interface{} {
+	values := []map[string]interface{}{}
+	for _, record := range records {
+		name, value := FieldValue(record["name"]), FieldValue(record["value"])
+		if masking && isSecretHeader(name) && value != "" && !strings.Contains(value, "${") {
+			value = "(masked)"
+		}
+		values = append(values, map[string]interface{}{"name": name, "value": value})
+	}
+	return values
+}
+
+// isSecretHeader reports whether a header's value is likely a credential (e.g., Authorization, X-API-Key)
+func isSecretHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretHeaderWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Registry) createRESTMessage(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	name := GetStringArg(args, "name", "")
+	endpoint := GetStringArg(args, "endpoint", "")
+	if name == "" || endpoint == "" {
+		return JSONResult(NewErrorResponse("name and endpoint are required", nil)), nil
+	}
+
+	data := map[string]interface{}{
+		"name":          name,
+		"rest_endpoint": endpoint,
+	}
+	if v := GetStringArg(args, "description", ""); v != "" {
+		data["description"] = v
+	}
+	if err := r.setRESTAuthentication(data, args, "no_authentication"); err != nil {
+		return JSONResult(NewValidationErrorResponse("Invalid authentication", err)), nil
+	}
+
+	result, err := r.client.Post("/table/sys_rest_message", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create REST message", err)), nil
+	}
+	resultData, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+	sysID := FieldValue(resultData["sys_id"])
+
+	response := map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("REST message %s created; add HTTP methods with create_rest_message_function", name),
+		"message_id": sysID,
+	}
+	if failed := r.createRESTNameValues("sys_rest_message_headers", "rest_message", sysID, GetMapArg(args, "headers")); len(failed) > 0 {
+		response["failed_headers"] = failed
+	}
+	return JSONResult(response), nil
+}
+
+func (r *Registry) createRESTMessageFunction(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	messageID := GetStringArg(args, "message_id", "")
+	name := GetStringArg(args, "name", "")
+	method := strings.ToLower(GetStringArg(args, "http_method", ""))
+	if messageID == "" || name == "" || method == "" {
+		return JSONResult(NewErrorResponse("message_id, name, and http_method are required", nil)), nil
+	}
+
+	// The message, for its sys_id and default endpoint
+	idField := "name"
+	if IsSysID(messageID) {
+		idField = "sys_id"
+	}
+	result, err := r.client.Get("/table/sys_rest_message", map[string]string{
+		"sysparm_query":  fmt.Sprintf("%s=%s", idField, SanitizeQueryValue(messageID)),
+		"sysparm_fields": "sys_id,name,rest_endpoint",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find REST message", err)), nil
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("REST message not found: %s", messageID))), nil
+	}
+	messageSysID := FieldValue(records[0]["sys_id"])
+
+	data := map[string]interface{}{
+		"rest_message":  messageSysID,
+		"function_name": name,
+		"http_method":   method,
+		"rest_endpoint": GetStringArg(args, "endpoint", FieldValue(records[0]["rest_endpoint"])),
+	}
+	if v := GetStringArg(args, "content", ""); v != "" {
+		data["content"] = v
+	}
+	if err := r.setRESTAuthentication(data, args, "inherit_from_parent"); err != nil {
+		return JSONResult(NewValidationErrorResponse("Invalid authentication", err)), nil
+	}
+
+	result, err = r.client.Post("/table/sys_rest_message_fn", data)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to create REST message function", err)), nil
+	}
+	resultData, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+	sysID := FieldValue(resultData["sys_id"])
+
+	response := map[string]interface{}{
+		"success":     true,
+		"message":     fmt.Sprintf("%s method %s added to REST message %s", strings.ToUpper(method), name, FieldValue(records[0]["name"])),
+		"function_id": sysID,
+		"message_id":  messageSysID,
+	}
+	if failed := r.createRESTNameValues("sys_rest_message_fn_headers", "rest_message_function", sysID, GetMapArg(args, "headers")); len(failed) > 0 {
+		response["failed_headers"] = failed
+	}
+	if failed := r.createRESTNameValues("sys_rest_message_fn_parameters", "rest_message_function", sysID, GetMapArg(args, "query_parameters")); len(failed) > 0 {
+		response["failed_query_parameters"] = failed
+	}
+	return JSONResult(response), nil
+}
+
+// setRESTAuthentication sets the authentication type of a REST message or method record
+// and, for basic and OAuth authentication, its auth profile resolved by sys_id or name
+func (r *Registry) setRESTAuthentication(data, args map[string]interface{}, defaultType string) error {
+	authType := GetStringArg(args, "authentication_type", defaultType)
+	data["authentication_type"] = authType
+
+	profile, needsProfile := restAuthProfileTables[authType]
+	profileID := GetStringArg(args, "auth_profile", "")
+	if !needsProfile {
+		if profileID != "" {
+			return fmt.Errorf("auth_profile applies only to the basic and oauth2 authentication types")
+		}
+		return nil
+	}
+	if profileID == "" {
+		return fmt.Errorf("auth_profile is required for %s authentication", authType)
+	}
+	if !IsSysID(profileID) {
+		result, err := r.client.Get("/table/"+profile.table, map[string]string{
+			"sysparm_query":  "name=" + SanitizeQueryValue(profileID),
+			"sysparm_fields": "sys_id",
+			"sysparm_limit":  "1",
+		})
+		if err != nil {
+			return err
+		}
+		records := GetResultList(result)
+		if len(records) == 0 {
+			return fmt.Errorf("%s profile %w: %s", profile.table, ErrNotFound, profileID)
+		}
+		profileID = FieldValue(records[0]["sys_id"])
+	}
+	data[profile.field] = profileID
+	return nil
+}
+
+// createRESTNameValues creates the header or query parameter records of a REST message
+// or method, in name order, and returns the names that failed with their errors
+func (r *Registry) createRESTNameValues(table, parentField, parentID string, values map[string]interface{}) map[string]string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failed := map[string]string{}
+	for _, name := range names {
+		_, err := r.client.Post("/table/"+table, map[string]interface{}{
+			parentField: parentID,
+			"name":      name,
+			"value":     fmt.Sprint(values[name]),
+		})
+		if err != nil {
+			failed[name] = err.Error()
+		}
+	}
+	return failed
+}
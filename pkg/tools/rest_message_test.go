@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetRESTMessage tests that a message is returned with its methods, auth profiles, and masked credential headers
+func TestGetRESTMessage(t *testing.T) {
+	const messageID = "a0000000000000000000000000000001"
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var records []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/sys_rest_message":
+			records = []interface{}{map[string]interface{}{
+				"sys_id": field(messageID, messageID), "name": field("Jira", "Jira"), "rest_endpoint": field("https://jira.example.com/rest/api/2", ""),
+				"authentication_type": field("basic", "Basic"), "basic_auth_profile": field("p1", "Jira integration"),
+			}}
+		case "/api/now/table/sys_rest_message_headers":
+			records = []interface{}{
+				map[string]interface{}{"name": field("Accept", "Accept"), "value": field("application/json", "")},
+				map[string]interface{}{"name": field("X-API-Key", "X-API-Key"), "value": field("s3cr3t", "")},
+			}
+		case "/api/now/table/sys_rest_message_fn":
+			records = []interface{}{map[string]interface{}{
+				"sys_id": field("f1", "f1"), "function_name": field("Create issue", ""), "http_method": field("post", "POST"),
+				"rest_endpoint": field("https://jira.example.com/rest/api/2/issue", ""), "authentication_type": field("inherit_from_parent", ""),
+				"content": field(`{"summary": "${summary}"}`, ""),
+			}}
+		case "/api/now/table/sys_rest_message_fn_headers":
+			records = []interface{}{map[string]interface{}{"rest_message_function": field("f1", "f1"), "name": field("Authorization", ""), "value": field("Bearer ${token}", "")}}
+		case "/api/now/table/sys_rest_message_fn_parameters", "/api/now/table/sys_rest_message_fn_param_defs":
+			records = []interface{}{map[string]interface{}{"rest_message_function": field("f1", "f1"), "name": field("summary", ""), "value": field("", "")}}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.getRESTMessage(map[string]interface{}{"message_id": "Jira"})
+	text := result.Content[0].Text
+	if strings.Contains(text, "s3cr3t") || !strings.Contains(text, "(masked)") {
+		t.Errorf("Expected the API key header to be masked, got %s", text)
+	}
+	var response struct {
+		RESTMessage struct {
+			AuthProfile map[string]interface{}   `json:"auth_profile"`
+			Functions   []map[string]interface{} `json:"functions"`
+		} `json:"rest_message"`
+	}
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if response.RESTMessage.AuthProfile["table"] != "sys_auth_profile_basic" || response.RESTMessage.AuthProfile["name"] != "Jira integration" {
+		t.Errorf("Expected the basic auth profile reference, got %+v", response.RESTMessage.AuthProfile)
+	}
+	if len(response.RESTMessage.Functions) != 1 {
+		t.Fatalf("Expected one HTTP method, got %+v", response.RESTMessage.Functions)
+	}
+	fn := response.RESTMessage.Functions[0]
+	headers, _ := fn["headers"].([]interface{})
+	if fn["http_method"] != "POST" || len(headers) != 1 || headers[0].(map[string]interface{})["value"] != "Bearer ${token}" {
+		t.Errorf("Expected the POST method with its variable Authorization header unmasked, got %+v", fn)
+	}
+}
+
+// TestCreateRESTMessageFunction tests that a method inherits the message endpoint and gets its headers
+func TestCreateRESTMessageFunction(t *testing.T) {
+	const messageID = "a0000000000000000000000000000001"
+	var function map[string]interface{}
+	var headers []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/sys_rest_message":
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + messageID + `", "name": "Jira", "rest_endpoint": "https://jira.example.com/rest/api/2"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/sys_rest_message_fn":
+			_ = json.NewDecoder(r.Body).Decode(&function)
+			_, _ = w.Write([]byte(`{"result": {"sys_id": "f1"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/sys_rest_message_fn_headers":
+			var header map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&header)
+			headers = append(headers, header["name"].(string)+"="+header["value"].(string))
+			_, _ = w.Write([]byte(`{"result": {"sys_id": "h1"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+
+	result, _ := registry.createRESTMessageFunction(map[string]interface{}{
+		"message_id":  "Jira",
+		"name":        "Get issue",
+		"http_method": "GET",
+		"headers":     map[string]interface{}{"Accept": "application/json"},
+	})
+	if !strings.Contains(result.Content[0].Text, `"function_id": "f1"`) {
+		t.Fatalf("Expected the method to be created, got %s", result.Content[0].Text)
+	}
+	if function["rest_message"] != messageID || function["http_method"] != "get" || function["rest_endpoint"] != "https://jira.example.com/rest/api/2" || function["authentication_type"] != "inherit_from_parent" {
+		t.Errorf("Expected a GET method inheriting the message endpoint and authentication, got %+v", function)
+	}
+	if len(headers) != 1 || headers[0] != "Accept=application/json" {
+		t.Errorf("Expected the Accept header to be created, got %v", headers)
+	}
+
+	result, _ = registry.createRESTMessageFunction(map[string]interface{}{
+		"message_id": messageID, "name": "Get issue", "http_method": "get", "authentication_type": "basic",
+	})
+	if !strings.Contains(result.Content[0].Text, "auth_profile is required") {
+		t.Errorf("Expected basic authentication without a profile to be rejected, got %s", result.Content[0].Text)
+	}
+}
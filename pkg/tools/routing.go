@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// registerRoutingTools registers assignment routing tools
+func (r *Registry) registerRoutingTools(server *mcp.Server) int {
+	count := 0
+
+	r.registerTool(server, mcp.Tool{
+		Name:        "suggest_routing",
+		Description: "Suggest the assignment group for an incident based on the instance's assignment data lookups, assignment rules, and the CI/service support group. Use before create_incident to assign correctly.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"ci_or_service": {
+					Type:        "string",
+					Description: "Configuration item or business service name (e.g., 'email-server-01', 'Email') or sys_id",
+				},
+				"category": {
+					Type:        "string",
+					Description: "Incident category (e.g., 'Hardware', 'Software', 'Network')",
+				},
+				"subcategory": {
+					Type:        "string",
+					Description: "Incident subcategory (e.g., 'email')",
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Suggest Routing",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).suggestRouting)
+	count++
+
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+
+	// List Assignment Rules
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_assignment_rules",
+		Description: "List assignment rules (sysrule_assignment) in the order they are evaluated, with their conditions and the group or user they assign. Filter by category to see which rules can route those tickets.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"table": {
+					Type:        "string",
+					Description: "Only rules for this table (e.g., 'incident', 'sc_req_item')",
+				},
+				"group": {
+					Type:        "string",
+					Description: "Only rules assigning this group (name or sys_id, e.g., 'Service Desk')",
+				},
+				"category": {
+					Type:        "string",
+					Description: "Only rules whose condition allows this category (e.g., 'hardware'); rules without a category condition are included",
+				},
+				"query": {
+					Type:        "string",
+					Description: "Text search in the rule name (e.g., 'network')",
+				},
+				"active_only": {
+					Type:        "boolean",
+					Description: "Only active rules",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Assignment Rules",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listAssignmentRules)
+	count++
+
+	return count
+}
+
+// routingCandidate is a possible assignment group with the rule that produced it
+type routingCandidate struct {
+	GroupID    string `json:"group_id"`
+	GroupName  string `json:"group_name"`
+	AssignedTo string `json:"assigned_to,omitempty"`
+	Source     string `json:"source"`
+	Rule       string `json:"rule,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+func (r *Registry) suggestRouting(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	ciOrService := GetStringArg(args, "ci_or_service", "")
+	category := GetStringArg(args, "category", "")
+	subcategory := GetStringArg(args, "subcategory", "")
+
+	if ciOrService == "" && category == "" {
+		return JSONResult(NewErrorResponse("ci_or_service or category is required", nil)), nil
+	}
+
+	candidates := []routingCandidate{}
+
+	// Resolve the CI or service
+	var ci map[string]interface{}
+	if ciOrService != "" {
+		query := fmt.Sprintf("name=%s", SanitizeQueryValue(ciOrService))
+		if IsSysID(ciOrService) {
+			query = fmt.Sprintf("sys_id=%s", ciOrService)
+		}
+		result, err := r.client.Get("/table/cmdb_ci", map[string]string{
+			"sysparm_query":         query,
+			"sysparm_fields":        "sys_id,name,sys_class_name,support_group,assignment_group",
+			"sysparm_display_value": "all",
+			"sysparm_limit":         "1",
+		})
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to look up configuration item", err)), nil
+		}
+		if records := GetResultList(result); len(records) > 0 {
+			ci = records[0]
+		}
+	}
+	ciSysID := FieldValue(ci["sys_id"])
+
+	// 1. Assignment data lookup rules (dl_u_assignment), most specific first
+	var lookupFilters []string
+	lookupFilters = append(lookupFilters, "active=true")
+	if category != "" {
+		lookupFilters = append(lookupFilters, fmt.Sprintf("category=%s^ORcategoryISEMPTY", SanitizeQueryValue(category)))
+	}
+	if subcategory != "" {
+		lookupFilters = append(lookupFilters, fmt.Sprintf("subcategory=%s^ORsubcategoryISEMPTY", SanitizeQueryValue(subcategory)))
+	}
+	if ciSysID != "" {
+		lookupFilters = append(lookupFilters, fmt.Sprintf("configuration_item=%s^ORconfiguration_itemISEMPTY", ciSysID))
+	}
+	lookupResult, err := r.client.Get("/table/dl_u_assignment", map[string]string{
+		"sysparm_query":         strings.Join(lookupFilters, "^") + "^ORDERBYorder",
+		"sysparm_fields":        "sys_id,category,subcategory,configuration_item,assignment_group,assigned_to,order",
+		"sysparm_display_value": "all",
+		"sysparm_limit":         "20",
+	})
+	if err == nil {
+		for _, rule := range GetResultList(lookupResult) {
+			if FieldValue(rule["assignment_group"]) == "" {
+				continue
+			}
+			candidates = append(candidates, routingCandidate{
+				GroupID:    FieldValue(rule["assignment_group"]),
+				GroupName:  FieldDisplay(rule["assignment_group"]),
+				AssignedTo: FieldDisplay(rule["assigned_to"]),
+				Source:     "assignment_data_lookup",
+				Rule:       FieldValue(rule["sys_id"]),
+				Reason: fmt.Sprintf("Data lookup match (category=%s, subcategory=%s, ci=%s, order=%s)",
+					FieldDisplay(rule["category"]), FieldDisplay(rule["subcategory"]),
+					FieldDisplay(rule["configuration_item"]), FieldDisplay(rule["order"])),
+			})
+		}
+	}
+
+	// 2. Assignment rules (sysrule_assignment) whose condition references the category or CI
+	rulesResult, err := r.client.Get("/table/sysrule_assignment", map[string]string{
+		"sysparm_query":         "active=true^table=incident^ORDERBYorder",
+		"sysparm_fields":        "sys_id,name,condition,group,user,order",
+		"sysparm_display_value": "all",
+		"sysparm_limit":         "100",
+	})
+	if err == nil {
+		for _, rule := range GetResultList(rulesResult) {
+			condition := FieldValue(rule["condition"])
+			if FieldValue(rule["group"]) == "" || !conditionMatches(condition, category, subcategory, ciSysID) {
+				continue
+			}
+			candidates = append(candidates, routingCandidate{
+				GroupID:    FieldValue(rule["group"]),
+				GroupName:  FieldDisplay(rule["group"]),
+				AssignedTo: FieldDisplay(rule["user"]),
+				Source:     "assignment_rule",
+				Rule:       FieldDisplay(rule["name"]),
+				Reason:     fmt.Sprintf("Assignment rule condition: %s", condition),
+			})
+		}
+	}
+
+	// 3. CI/service support group
+	if ci != nil {
+		for _, field := range []string{"support_group", "assignment_group"} {
+			if groupID := FieldValue(ci[field]); groupID != "" {
+				candidates = append(candidates, routingCandidate{
+					GroupID:   groupID,
+					GroupName: FieldDisplay(ci[field]),
+					Source:    "ci_" + field,
+					Reason:    fmt.Sprintf("%s of %s (%s)", field, FieldDisplay(ci["name"]), FieldDisplay(ci["sys_class_name"])),
+				})
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return JSONResult(map[string]interface{}{
+			"success":    false,
+			"message":    "No routing rule matched; assign manually or pick a group with list_groups",
+			"ci_found":   ci != nil,
+			"candidates": candidates,
+		}), nil
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":            true,
+		"message":            fmt.Sprintf("Suggested assignment group: %s (%s)", candidates[0].GroupName, candidates[0].Source),
+		"suggested_group":    candidates[0],
+		"candidates":         candidates,
+		"ci_found":           ci != nil,
+		"configuration_item": FieldDisplay(ci["name"]),
+	}), nil
+}
+
+// conditionMatches reports whether an encoded assignment rule condition references
+// the given category, subcategory, or CI, compared as they were sent in the lookup
+// query. Conditions on other fields are not evaluated.
+func conditionMatches(condition, category, subcategory, ciSysID string) bool {
+	if condition == "" {
+		return false
+	}
+	category, subcategory = SanitizeQueryValue(category), SanitizeQueryValue(subcategory)
+	for _, term := range strings.FieldsFunc(condition, func(c rune) bool { return c == '^' }) {
+		term = strings.TrimPrefix(term, "OR")
+		switch {
+		case category != "" && strings.EqualFold(term, "category="+category):
+			return true
+		case subcategory != "" && strings.EqualFold(term, "subcategory="+subcategory):
+			return true
+		case ciSysID != "" && term == "cmdb_ci="+ciSysID:
+			return true
+		}
+	}
+	return false
+}
+
+// conditionScanLimit is the number of rules or definitions read when their conditions
+// are matched locally
+const conditionScanLimit = 500
+
+// conditionValues returns the values an encoded condition compares field against with
+// = or IN, and whether the condition constrains the field at all. OR and NQ groupings
+// are not evaluated, so the result describes which values the condition mentions.
+func conditionValues(condition, field string) ([]string, bool) {
+	var values []string
+	constrained := false
+	for _, term := range strings.FieldsFunc(condition, func(c rune) bool { return c == '^' }) {
+		term = strings.TrimPrefix(strings.TrimPrefix(term, "NQ"), "OR")
+		switch {
+		case strings.HasPrefix(term, field+"="):
+			values = append(values, strings.TrimPrefix(term, field+"="))
+			constrained = true
+		case strings.HasPrefix(term, field+"IN"):
+			values = append(values, strings.Split(strings.TrimPrefix(term, field+"IN"), ",")...)
+			constrained = true
+		case strings.HasPrefix(term, field) && !strings.HasPrefix(term, field+"."):
+			// Other operators (!=, LIKE, ISEMPTY, ...) constrain the field to unknown values
+			constrained = true
+		}
+	}
+	return values, constrained
+}
+
+// conditionAllows reports whether an encoded condition either doesn't constrain field
+// or mentions value for it (case-insensitively)
+func conditionAllows(condition, field, value string) bool {
+	values, constrained := conditionValues(condition, field)
+	if !constrained {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Registry) listAssignmentRules(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	var filters []string
+	if GetBoolArg(args, "active_only", true) {
+		filters = append(filters, "active=true")
+	}
+	if table := GetStringArg(args, "table", ""); table != "" {
+		if !tableNamePattern.MatchString(table) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+		}
+		filters = append(filters, "table="+table)
+	}
+	if group := GetStringArg(args, "group", ""); group != "" {
+		if IsSysID(group) {
+			filters = append(filters, "group="+group)
+		} else {
+			filters = append(filters, "group.name="+SanitizeQueryValue(group))
+		}
+	}
+	if query := GetStringArg(args, "query", ""); query != "" {
+		filters = append(filters, LikeFilter(query, "name"))
+	}
+	filters = append(filters, "ORDERBYorder")
+
+	category := GetStringArg(args, "category", "")
+	limit := GetIntArg(args, "limit", 50)
+	offset := GetIntArg(args, "offset", 0)
+	params := map[string]string{
+		"sysparm_query":                  strings.Join(filters, "^"),
+		"sysparm_fields":                 readFields(args, "sys_id,name,table,condition,group,user,order,active"),
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+	}
+	if category != "" {
+		// Conditions are matched here, so read enough rules to page through the matches
+		params["sysparm_limit"] = fmt.Sprintf("%d", conditionScanLimit)
+		params["sysparm_offset"] = "0"
+	}
+
+	result, page, err := r.listRecords("/table/sysrule_assignment", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list assignment rules", err)), nil
+	}
+
+	rules := []map[string]interface{}{}
+	for _, rule := range GetResultList(result) {
+		condition := FieldValue(rule["condition"])
+		if category != "" && !conditionAllows(condition, "category", category) {
+			continue
+		}
+		rules = append(rules, selectFields(map[string]interface{}{
+			"sys_id":    FieldValue(rule["sys_id"]),
+			"name":      FieldDisplay(rule["name"]),
+			"table":     FieldValue(rule["table"]),
+			"order":     FieldValue(rule["order"]),
+			"active":    FieldValue(rule["active"]) == "true",
+			"condition": condition,
+			"group":     FieldDisplay(rule["group"]),
+			"group_id":  FieldValue(rule["group"]),
+			"user":      FieldDisplay(rule["user"]),
+		}, rule, args))
+	}
+	if category != "" {
+		rules, page = pageOfMatches(rules, page, limit, offset)
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d assignment rules", len(rules)),
+		"rules":   rules,
+	}, page)), nil
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSuggestRouting tests that the assignment group is chosen from data lookups, then assignment rules, then the CI's support group
+func TestSuggestRouting(t *testing.T) {
+	const ciSysID = "6816f79cc0a8016401c5a33be04be441"
+
+	ref := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+	lookupFails := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("sysparm_query")
+		records := []interface{}{}
+		switch r.URL.Path {
+		case "/api/now/table/cmdb_ci":
+			if query == "name=email-server-01" {
+				records = append(records, map[string]interface{}{
+					"sys_id":         ref(ciSysID, ciSysID),
+					"name":           ref("email-server-01", "email-server-01"),
+					"sys_class_name": ref("cmdb_ci_server", "Server"),
+					"support_group":  ref("grp_email", "Email Support"),
+				})
+			}
+		case "/api/now/table/dl_u_assignment":
+			if lookupFails {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if strings.Contains(query, "category=network^") {
+				records = append(records, map[string]interface{}{
+					"sys_id":           ref("dl1", "dl1"),
+					"category":         ref("network", "Network"),
+					"assignment_group": ref("grp_network", "Network Team"),
+				})
+			}
+		case "/api/now/table/sysrule_assignment":
+			records = append(records,
+				map[string]interface{}{"name": ref("Software", "Software"), "condition": ref("category=software^EQ", ""), "group": ref("grp_software", "Software Team")},
+				map[string]interface{}{"name": ref("Network fallback", "Network fallback"), "condition": ref("category=network^EQ", ""), "group": ref("grp_noc", "NOC")},
+				map[string]interface{}{"name": ref("No group", "No group"), "condition": ref("category=hardware^EQ", ""), "group": ref("", "")},
+			)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		lookupFails bool
+		success     bool
+		group       string
+		source      string
+		message     string
+	}{
+		{"data lookup first", map[string]interface{}{"category": "network"}, false, true, "Network Team", "assignment_data_lookup", ""},
+		{"assignment rule", map[string]interface{}{"category": "Software"}, false, true, "Software Team", "assignment_rule", ""},
+		{"rule when the data lookup fails", map[string]interface{}{"category": "network"}, true, true, "NOC", "assignment_rule", ""},
+		{"CI support group fallback", map[string]interface{}{"ci_or_service": "email-server-01", "category": "hardware"}, false, true, "Email Support", "ci_support_group", ""},
+		{"no match", map[string]interface{}{"category": "facilities"}, false, false, "", "", "No routing rule matched"},
+		{"unknown CI", map[string]interface{}{"ci_or_service": "missing-server"}, false, false, "", "", "No routing rule matched"},
+		{"missing arguments", map[string]interface{}{"subcategory": "email"}, false, false, "", "", "ci_or_service or category is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupFails = tt.lookupFails
+			result, _ := registry.suggestRouting(tt.args)
+			var response struct {
+				Success        bool              `json:"success"`
+				Message        string            `json:"message"`
+				SuggestedGroup *routingCandidate `json:"suggested_group"`
+			}
+			if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+				t.Fatalf("Failed to parse result: %v", err)
+			}
+			if response.Success != tt.success || !strings.Contains(response.Message, tt.message) {
+				t.Fatalf("Expected success=%v with %q, got %s", tt.success, tt.message, result.Content[0].Text)
+			}
+			if !tt.success {
+				if response.SuggestedGroup != nil {
+					t.Errorf("Expected no suggested group, got %+v", response.SuggestedGroup)
+				}
+				return
+			}
+			if response.SuggestedGroup == nil || response.SuggestedGroup.GroupName != tt.group || response.SuggestedGroup.Source != tt.source {
+				t.Errorf("Expected %s from %s, got %+v", tt.group, tt.source, response.SuggestedGroup)
+			}
+		})
+	}
+}
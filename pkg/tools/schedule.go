@@ -0,0 +1,525 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	// Embedded zone data, since the runtime image has no tz database
+	_ "time/tzdata"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// systemTimeZoneProperty holds the instance's default time zone
+const systemTimeZoneProperty = "glide.sys.default.tz"
+
+// maxScheduleRange bounds business time calculations, which walk the schedule day by day
+const maxScheduleRange = 366 * 24 * time.Hour
+
+// Layouts of schedule span values (glide_schedule_date_time), in the schedule's time zone
+var scheduleDateTimeLayouts = []string{"20060102T150405", "20060102T150405Z", "20060102", dateTimeLayout, dateLayout}
+
+// instanceTimeZone caches the instance's system time zone
+type instanceTimeZone struct {
+	once     sync.Once
+	name     string
+	location *time.Location
+}
+
+// instanceLocation returns the instance's system time zone (glide.sys.default.tz),
+// falling back to UTC when the property is unset or unreadable
+func (r *Registry) instanceLocation() (string, *time.Location) {
+	r.timeZone.once.Do(func() {
+		r.timeZone.name, r.timeZone.location = "UTC", time.UTC
+		result, err := r.client.Get("/table/sys_properties", map[string]string{
+			"sysparm_query":  "name=" + systemTimeZoneProperty,
+			"sysparm_fields": "value",
+			"sysparm_limit":  "1",
+		})
+		if err != nil {
+			return
+		}
+		if records := GetResultList(result); len(records) > 0 {
+			name := FieldValue(records[0]["value"])
+			if location, err := time.LoadLocation(name); err == nil && name != "" {
+				r.timeZone.name, r.timeZone.location = name, location
+			}
+		}
+	})
+	return r.timeZone.name, r.timeZone.location
+}
+
+// interval is a half-open time range [start, end)
+type interval struct {
+	start, end time.Time
+}
+
+// scheduleSpan is a schedule entry (cmn_schedule_span), in its schedule's time zone
+type scheduleSpan struct {
+	start, end time.Time
+	repeat     string
+	daysOfWeek string
+	until      time.Time
+	exclude    bool
+}
+
+// businessSchedule is a schedule (cmn_schedule) with its own spans and those of its
+// child schedules (cmn_other_schedule), e.g., an excluded holiday schedule
+type businessSchedule struct {
+	id       string
+	name     string
+	timeZone string
+	location *time.Location
+	spans    []scheduleSpan
+}
+
+// loadSchedule reads a schedule and its spans. Schedules without a time zone (floating)
+// are evaluated in the instance's system time zone.
+func (r *Registry) loadSchedule(sysID string) (*businessSchedule, error) {
+	result, err := r.client.Get("/table/cmn_schedule/"+sysID, map[string]string{
+		"sysparm_fields": "sys_id,name,time_zone",
+	})
+	if err != nil {
+		return nil, err
+	}
+	record, _ := result["result"].(map[string]interface{})
+	if record == nil {
+		return nil, fmt.Errorf("schedule %w: %s", ErrNotFound, sysID)
+	}
+
+	schedule := &businessSchedule{id: sysID, name: FieldValue(record["name"]), timeZone: FieldValue(record["time_zone"])}
+	location, err := time.LoadLocation(schedule.timeZone)
+	if schedule.timeZone == "" || err != nil {
+		schedule.timeZone, location = r.instanceLocation()
+	}
+	schedule.location = location
+
+	// Spans of excluded child schedules (e.g., holidays) are excluded from this schedule
+	excluded := map[string]bool{}
+	ids := []string{sysID}
+	children, err := r.client.Get("/table/cmn_other_schedule", map[string]string{
+		"sysparm_query":  "schedule=" + sysID,
+		"sysparm_fields": "child_schedule,type",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range GetResultList(children) {
+		childID := FieldValue(child["child_schedule"])
+		if !IsSysID(childID) {
+			continue
+		}
+		ids = append(ids, childID)
+		excluded[childID] = FieldValue(child["type"]) == "exclude"
+	}
+
+	spans, err := r.client.Get("/table/cmn_schedule_span", map[string]string{
+		"sysparm_query":  "scheduleIN" + strings.Join(ids, ","),
+		"sysparm_fields": "schedule,type,all_day,start_date_time,end_date_time,repeat_type,days_of_week,repeat_until",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range GetResultList(spans) {
+		start, startOK := parseScheduleTime(FieldValue(record["start_date_time"]), location)
+		end, endOK := parseScheduleTime(FieldValue(record["end_date_time"]), location)
+		if !startOK || !endOK {
+			continue
+		}
+		if FieldValue(record["all_day"]) == "true" {
+			start = startOfDay(start)
+			end = startOfDay(end).AddDate(0, 0, 1)
+		}
+		if !end.After(start) {
+			continue
+		}
+		span := scheduleSpan{
+			start:      start,
+			end:        end,
+			repeat:     FieldValue(record["repeat_type"]),
+			daysOfWeek: FieldValue(record["days_of_week"]),
+			exclude:    FieldValue(record["type"]) == "exclude" || excluded[FieldValue(record["schedule"])],
+		}
+		if until, ok := parseScheduleTime(FieldValue(record["repeat_until"]), location); ok {
+			span.until = startOfDay(until).AddDate(0, 0, 1)
+		}
+		schedule.spans = append(schedule.spans, span)
+	}
+	return schedule, nil
+}
+
+// parseScheduleTime parses a schedule span value in the schedule's time zone
+func parseScheduleTime(value string, location *time.Location) (time.Time, bool) {
+	for _, layout := range scheduleDateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// startOfDay returns midnight of t's day in t's time zone
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// occursOn reports whether a repeating span has an occurrence starting on day
+func (s scheduleSpan) occursOn(day time.Time) bool {
+	if day.Before(startOfDay(s.start)) || (!s.until.IsZero() && !day.Before(s.until)) {
+		return false
+	}
+	weekday := scheduleWeekday(day)
+	switch s.repeat {
+	case "daily":
+		return true
+	case "weekdays":
+		return weekday <= 5
+	case "weekends":
+		return weekday >= 6
+	case "weekly":
+		days := s.daysOfWeek
+		if days == "" {
+			days = fmt.Sprint(scheduleWeekday(s.start))
+		}
+		return strings.Contains(days, fmt.Sprint(weekday))
+	case "monthly":
+		return day.Day() == s.start.Day()
+	case "yearly":
+		return day.Month() == s.start.Month() && day.Day() == s.start.Day()
+	}
+	return false
+}
+
+// scheduleWeekday numbers days of the week the way schedules do, from Monday (1) to Sunday (7)
+func scheduleWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// occurrences returns the span's occurrences overlapping [from, to)
+func (s scheduleSpan) occurrences(from, to time.Time) []interval {
+	switch s.repeat {
+	case "daily", "weekdays", "weekends", "weekly", "monthly", "yearly":
+	default:
+		return []interval{{s.start, s.end}}
+	}
+
+	var result []interval
+	length := s.end.Sub(s.start)
+	hour, min, sec := s.start.Clock()
+	// Start a day early for occurrences that run past midnight into the range
+	for day := startOfDay(from.In(s.start.Location())).AddDate(0, 0, -1); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !s.occursOn(day) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), hour, min, sec, 0, day.Location())
+		result = append(result, interval{start, start.Add(length)})
+	}
+	return result
+}
+
+// workingIntervals returns the schedule's working time within [from, to): its included
+// spans, merged, minus its excluded spans
+func (s *businessSchedule) workingIntervals(from, to time.Time) []interval {
+	var included, excluded []interval
+	for _, span := range s.spans {
+		for _, occurrence := range span.occurrences(from, to) {
+			if occurrence.start.Before(from) {
+				occurrence.start = from
+			}
+			if occurrence.end.After(to) {
+				occurrence.end = to
+			}
+			if !occurrence.end.After(occurrence.start) {
+				continue
+			}
+			if span.exclude {
+				excluded = append(excluded, occurrence)
+			} else {
+				included = append(included, occurrence)
+			}
+		}
+	}
+	return subtractIntervals(mergeIntervals(included), mergeIntervals(excluded))
+}
+
+// mergeIntervals sorts intervals and joins overlapping ones
+func mergeIntervals(intervals []interval) []interval {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+	var merged []interval
+	for _, next := range intervals {
+		if n := len(merged); n > 0 && !next.start.After(merged[n-1].end) {
+			if next.end.After(merged[n-1].end) {
+				merged[n-1].end = next.end
+			}
+			continue
+		}
+		merged = append(merged, next)
+	}
+	return merged
+}
+
+// subtractIntervals removes the merged intervals in remove from the merged intervals in from
+func subtractIntervals(from, remove []interval) []interval {
+	var result []interval
+	for _, current := range from {
+		for _, cut := range remove {
+			if !cut.end.After(current.start) || !cut.start.Before(current.end) {
+				continue
+			}
+			if cut.start.After(current.start) {
+				result = append(result, interval{current.start, cut.start})
+			}
+			current.start = cut.end
+		}
+		if current.end.After(current.start) {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
+// duration returns the working time between from and to
+func (s *businessSchedule) duration(from, to time.Time) time.Duration {
+	var total time.Duration
+	for _, working := range s.workingIntervals(from, to) {
+		total += working.end.Sub(working.start)
+	}
+	return total
+}
+
+// add returns the time at which d of working time has elapsed after from, searching
+// up to maxScheduleRange ahead
+func (s *businessSchedule) add(from time.Time, d time.Duration) (time.Time, bool) {
+	remaining := d
+	for windowStart := from; windowStart.Sub(from) < maxScheduleRange; windowStart = windowStart.AddDate(0, 0, 7) {
+		for _, working := range s.workingIntervals(windowStart, windowStart.AddDate(0, 0, 7)) {
+			if length := working.end.Sub(working.start); length < remaining {
+				remaining -= length
+				continue
+			}
+			return working.start.Add(remaining), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// registerScheduleTools registers business schedule tools
+func (r *Registry) registerScheduleTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+	hoursMin := float64(0)
+
+	// List Schedules
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_schedules",
+		Description: "List business schedules (cmn_schedule), e.g., '8-5 weekdays', with their time zones, and the instance's system time zone.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"name": {
+					Type:        "string",
+					Description: "Schedule name contains (e.g., '8-5')",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Schedules",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listSchedules)
+	count++
+
+	// Compute Business Duration
+	r.registerTool(server, mcp.Tool{
+		Name:        "compute_business_duration",
+		Description: "Compute business time on a schedule: the working time between start and end, or the time by which a number of business hours will have elapsed after start (e.g., 'respond within 4 business hours'). Excluded spans and holiday schedules are left out.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"schedule_id": {
+					Type:        "string",
+					Description: "Schedule sys_id or exact name (e.g., '8-5 weekdays excluding holidays'); see list_schedules",
+				},
+				"start": {
+					Type:        "string",
+					Description: "Start date/time in UTC (format: YYYY-MM-DD HH:MM:SS). Defaults to now.",
+					Format:      "date-time",
+				},
+				"end": {
+					Type:        "string",
+					Description: "End date/time in UTC (format: YYYY-MM-DD HH:MM:SS). Provide end or business_hours.",
+					Format:      "date-time",
+				},
+				"business_hours": {
+					Type:        "number",
+					Description: "Business hours to add to start (e.g., 4); returns when they will have elapsed",
+					Minimum:     &hoursMin,
+				},
+			},
+			Required: []string{"schedule_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Compute Business Duration",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).computeBusinessDuration)
+	count++
+
+	return count
+}
+
+func (r *Registry) listSchedules(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	query := "ORDERBYname"
+	if name := GetStringArg(args, "name", ""); name != "" {
+		query = LikeFilter(name, "name") + "^" + query
+	}
+
+	result, page, err := r.listRecords("/table/cmn_schedule", map[string]string{
+		"sysparm_query":  query,
+		"sysparm_fields": readFields(args, "sys_id,name,time_zone,type,description"),
+		"sysparm_limit":  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset": fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list schedules", err)), nil
+	}
+
+	instanceZone, _ := r.instanceLocation()
+	schedules := []map[string]interface{}{}
+	for _, record := range GetResultList(result) {
+		timeZone := FieldValue(record["time_zone"])
+		if timeZone == "" {
+			timeZone = "floating (" + instanceZone + ")"
+		}
+		schedules = append(schedules, selectFields(map[string]interface{}{
+			"schedule_id": FieldValue(record["sys_id"]),
+			"name":        FieldValue(record["name"]),
+			"time_zone":   timeZone,
+			"type":        FieldValue(record["type"]),
+			"description": FieldValue(record["description"]),
+		}, record, args))
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":            true,
+		"message":            fmt.Sprintf("Found %d schedules", len(schedules)),
+		"instance_time_zone": instanceZone,
+		"schedules":          schedules,
+	}, page)), nil
+}
+
+func (r *Registry) computeBusinessDuration(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	scheduleID := GetStringArg(args, "schedule_id", "")
+	if scheduleID == "" {
+		return JSONResult(NewErrorResponse("schedule_id is required", nil)), nil
+	}
+	endArg := GetStringArg(args, "end", "")
+	_, hasHours := args["business_hours"]
+	if (endArg == "") == !hasHours {
+		return JSONResult(NewErrorResponse("Provide exactly one of end or business_hours", nil)), nil
+	}
+
+	start := time.Now().UTC().Truncate(time.Second)
+	if v := GetStringArg(args, "start", ""); v != "" {
+		parsed, err := time.Parse(dateTimeLayout, v)
+		if err != nil {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid start: %s (use YYYY-MM-DD HH:MM:SS)", v), nil)), nil
+		}
+		start = parsed
+	}
+
+	sysID, err := r.resolveScheduleID(scheduleID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find schedule", err)), nil
+	}
+	schedule, err := r.loadSchedule(sysID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to load schedule", err)), nil
+	}
+
+	response := map[string]interface{}{
+		"success":     true,
+		"schedule_id": schedule.id,
+		"schedule":    schedule.name,
+		"time_zone":   schedule.timeZone,
+		"start":       start.Format(dateTimeLayout),
+		"start_local": start.In(schedule.location).Format(dateTimeLayout),
+	}
+
+	if hasHours {
+		hours, _ := CoerceNumber(args["business_hours"])
+		due, ok := schedule.add(start, time.Duration(hours*float64(time.Hour)))
+		if !ok {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Schedule %s has fewer than %v working hours in the year after start", schedule.name, hours), nil)), nil
+		}
+		response["message"] = fmt.Sprintf("%v business hours after %s on %s ends at %s (%s)", hours, start.Format(dateTimeLayout), schedule.name, due.In(schedule.location).Format(dateTimeLayout), schedule.timeZone)
+		response["business_hours"] = hours
+		response["end"] = due.UTC().Format(dateTimeLayout)
+		response["end_local"] = due.In(schedule.location).Format(dateTimeLayout)
+		response["wall_clock_minutes"] = int(due.Sub(start).Minutes())
+		return JSONResult(response), nil
+	}
+
+	end, err := time.Parse(dateTimeLayout, endArg)
+	if err != nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid end: %s (use YYYY-MM-DD HH:MM:SS)", endArg), nil)), nil
+	}
+	if end.Before(start) {
+		return JSONResult(NewErrorResponse("end must not be before start", nil)), nil
+	}
+	if end.Sub(start) > maxScheduleRange {
+		return JSONResult(NewErrorResponse("start and end must be at most a year apart", nil)), nil
+	}
+
+	business := schedule.duration(start, end)
+	response["message"] = fmt.Sprintf("%.2f business hours on %s between %s and %s", business.Hours(), schedule.name, start.Format(dateTimeLayout), endArg)
+	response["end"] = endArg
+	response["end_local"] = end.In(schedule.location).Format(dateTimeLayout)
+	response["business_minutes"] = int(business.Minutes())
+	response["business_hours"] = math.Round(business.Hours()*100) / 100
+	response["wall_clock_minutes"] = int(end.Sub(start).Minutes())
+	return JSONResult(response), nil
+}
+
+// resolveScheduleID resolves a schedule name to sys_id
+func (r *Registry) resolveScheduleID(scheduleID string) (string, error) {
+	if IsSysID(scheduleID) {
+		return scheduleID, nil
+	}
+
+	result, err := r.client.Get("/table/cmn_schedule", map[string]string{
+		"sysparm_query":  "name=" + SanitizeQueryValue(scheduleID),
+		"sysparm_fields": "sys_id",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return "", err
+	}
+	if records := GetResultList(result); len(records) > 0 {
+		return FieldValue(records[0]["sys_id"]), nil
+	}
+	return "", fmt.Errorf("schedule %w: %s", ErrNotFound, scheduleID)
+}
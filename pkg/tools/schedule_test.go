@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestComputeBusinessDuration tests business time on a weekday schedule with an excluded holiday schedule
+func TestComputeBusinessDuration(t *testing.T) {
+	const (
+		scheduleID = "11111111111111111111111111111111"
+		holidaysID = "22222222222222222222222222222222"
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var result interface{}
+		switch r.URL.Path {
+		case "/api/now/table/cmn_schedule":
+			result = []interface{}{map[string]interface{}{"sys_id": scheduleID}}
+		case "/api/now/table/cmn_schedule/" + scheduleID:
+			result = map[string]interface{}{"sys_id": scheduleID, "name": "8-5 weekdays excluding holidays", "time_zone": "America/New_York"}
+		case "/api/now/table/cmn_other_schedule":
+			result = []interface{}{map[string]interface{}{"child_schedule": holidaysID, "type": "exclude"}}
+		case "/api/now/table/cmn_schedule_span":
+			if q := r.URL.Query().Get("sysparm_query"); q != "scheduleIN"+scheduleID+","+holidaysID {
+				t.Errorf("Expected the spans of the schedule and its holidays, got %s", q)
+			}
+			result = []interface{}{
+				map[string]interface{}{
+					"schedule": scheduleID, "start_date_time": "20240101T080000", "end_date_time": "20240101T170000",
+					"repeat_type": "weekly", "days_of_week": "12345",
+				},
+				map[string]interface{}{
+					"schedule": holidaysID, "start_date_time": "20241223", "end_date_time": "20241223", "all_day": "true",
+				},
+			}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	// Friday 15:00 to Tuesday 15:00 New York time: 2 hours Friday, Monday is a holiday, 7 hours Tuesday
+	result, _ := registry.computeBusinessDuration(map[string]interface{}{
+		"schedule_id": "8-5 weekdays excluding holidays",
+		"start":       "2024-12-20 20:00:00",
+		"end":         "2024-12-24 20:00:00",
+	})
+	var body struct {
+		Success         bool    `json:"success"`
+		BusinessMinutes int     `json:"business_minutes"`
+		BusinessHours   float64 `json:"business_hours"`
+		End             string  `json:"end"`
+		EndLocal        string  `json:"end_local"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil || !body.Success || body.BusinessMinutes != 540 {
+		t.Fatalf("Expected 540 business minutes, got %s", result.Content[0].Text)
+	}
+
+	// 4 business hours from Friday 15:00: 2 hours Friday, then 2 hours Tuesday morning
+	result, _ = registry.computeBusinessDuration(map[string]interface{}{
+		"schedule_id":    scheduleID,
+		"start":          "2024-12-20 20:00:00",
+		"business_hours": float64(4),
+	})
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil || body.End != "2024-12-24 15:00:00" || body.EndLocal != "2024-12-24 10:00:00" {
+		t.Fatalf("Expected the 4 business hours to end Tuesday 10:00 New York time, got %s", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// maxScriptLength bounds the size of a background script
+const maxScriptLength = 100000
+
+// EnableScriptExecution registers execute_background_script on the next RegisterAll
+// call (never in read-only mode). Scripts run through the scripted REST API at path
+// (e.g., /api/acme/mcp_script/run), which must be installed on the instance.
+func (r *Registry) EnableScriptExecution(path string) error {
+	if !strings.HasPrefix(path, "/api/") || strings.Contains(path, "..") || strings.ContainsAny(path, "?# ") {
+		return fmt.Errorf("invalid scripted REST API path %q (expected e.g. /api/acme/mcp_script/run)", path)
+	}
+	r.scriptAPI = path
+	return nil
+}
+
+// registerScriptTools registers the background script tool. It is only registered
+// when script execution is enabled (ALLOW_SCRIPT_EXECUTION=true) outside read-only mode.
+func (r *Registry) registerScriptTools(server *mcp.Server) int {
+	count := 0
+
+	if !r.readOnlyMode {
+		// Execute Background Script
+		r.registerToolWithContext(server, mcp.Tool{
+			Name:        "execute_background_script",
+			Description: "Run a server-side JavaScript background script on the instance and return its output, log messages, and execution time. Scripts run with the rights of the calling account and can change or delete any data it can reach; prefer the dedicated tools where one exists. Call log('message') to capture messages; the value of the last expression is returned as output.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"script": {
+						Type:        "string",
+						Description: "Server-side JavaScript (e.g., \"var gr = new GlideRecord('incident'); gr.addActiveQuery(); gr.query(); log(gr.getRowCount());\")",
+					},
+				},
+				Required: []string{"script"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Execute Background Script",
+				DestructiveHint: true,
+			},
+		}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			return r.forContext(ctx).executeBackgroundScript(ctx, args)
+		})
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) executeBackgroundScript(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	script := GetStringArg(args, "script", "")
+	if strings.TrimSpace(script) == "" {
+		return JSONResult(NewErrorResponse("script is required", nil)), nil
+	}
+	if len(script) > maxScriptLength {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("script is %d bytes, more than the limit of %d", len(script), maxScriptLength), nil)), nil
+	}
+
+	if r.logger != nil {
+		r.logger.Audit("Background script run by %s via %s (%d bytes)", mcp.CallerIdentity(ctx), r.scriptAPI, len(script))
+	}
+
+	start := time.Now()
+	result, err := r.base.InstanceRequestWithContext(ctx, "POST", r.scriptAPI, map[string]interface{}{"script": script})
+	elapsed := time.Since(start)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to run background script", err)), nil
+	}
+
+	body, _ := result["result"].(map[string]interface{})
+	if body == nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Unexpected response from %s (is the scripted REST API installed?)", r.scriptAPI), nil)), nil
+	}
+
+	logs := []string{}
+	if entries, ok := body["logs"].([]interface{}); ok {
+		for _, entry := range entries {
+			logs = append(logs, fmt.Sprint(entry))
+		}
+	}
+	// The instance's own timing excludes the network round trip
+	executionMs := elapsed.Milliseconds()
+	if ms, ok := body["execution_time_ms"].(float64); ok {
+		executionMs = int64(ms)
+	}
+
+	response := map[string]interface{}{
+		"success":           true,
+		"message":           fmt.Sprintf("Script ran in %d ms", executionMs),
+		"output":            body["output"],
+		"logs":              logs,
+		"execution_time_ms": executionMs,
+	}
+	if scriptErr := FieldDisplay(body["error"]); scriptErr != "" {
+		response["success"] = false
+		response["message"] = "Script failed: " + scriptErr
+		response["error"] = scriptErr
+	}
+	return JSONResult(response), nil
+}
@@ -4,24 +4,25 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
 )
 
 // registerScriptIncludeTools registers all script include tools
 func (r *Registry) registerScriptIncludeTools(server *mcp.Server) int {
 	count := 0
 
-	// Helper for limit constraints
+	// Helper for limit/offset constraints
 	limitMin := float64(1)
 	limitMax := float64(1000)
+	offsetMin := float64(0)
 
 	// List Script Includes
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_script_includes",
 		Description: "List script includes with optional filtering. Script includes are reusable server-side JavaScript functions.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of script includes to return (default: 50)",
@@ -29,6 +30,12 @@ func (r *Registry) registerScriptIncludeTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"active": {
 					Type:        "boolean",
 					Description: "Filter by active status (true = only active, false = only inactive)",
@@ -37,44 +44,40 @@ func (r *Registry) registerScriptIncludeTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Search query (searches name and API name)",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Script Includes",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listScriptIncludes(args)
-	})
+	}, (*Registry).listScriptIncludes)
 	count++
 
 	// Get Script Include
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_script_include",
 		Description: "Get detailed information about a script include including the full script code.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"script_id": {
 					Type:        "string",
 					Description: "Script include sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6') or name. Accepts both formats.",
 				},
-			},
+			}),
 			Required: []string{"script_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Script Include",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getScriptInclude(args)
-	})
+	}, (*Registry).getScriptInclude)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Script Include
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_script_include",
 			Description: "Create a new script include. Script includes are reusable server-side JavaScript functions.",
 			InputSchema: mcp.JSONSchema{
@@ -106,13 +109,11 @@ func (r *Registry) registerScriptIncludeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Script Include",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createScriptInclude(args)
-		})
+		}, (*Registry).createScriptInclude)
 		count++
 
 		// Update Script Include
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_script_include",
 			Description: "Update an existing script include. At least one field besides script_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -144,13 +145,11 @@ func (r *Registry) registerScriptIncludeTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Script Include",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateScriptInclude(args)
-		})
+		}, (*Registry).updateScriptInclude)
 		count++
 
 		// Delete Script Include
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "delete_script_include",
 			Description: "Permanently delete a script include. This action cannot be undone.",
 			InputSchema: mcp.JSONSchema{
@@ -167,9 +166,7 @@ func (r *Registry) registerScriptIncludeTools(server *mcp.Server) int {
 				Title:           "Delete Script Include",
 				DestructiveHint: true,
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.deleteScriptInclude(args)
-		})
+		}, (*Registry).deleteScriptInclude)
 		count++
 	}
 
@@ -182,27 +179,25 @@ func (r *Registry) listScriptIncludes(args map[string]interface{}) (*mcp.CallToo
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,name,api_name,description,active,client_callable"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
-	if active, exists := args["active"]; exists {
-		if active.(bool) {
-			filters = append(filters, "active=true")
-		} else {
-			filters = append(filters, "active=false")
-		}
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
 	}
 	if query != "" {
-		filters = append(filters, fmt.Sprintf("nameLIKE%s^ORapi_nameLIKE%s", query, query))
+		filters = append(filters, LikeFilter(query, "name", "api_name"))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/sys_script_include", params)
+	result, page, err := r.listRecords("/table/sys_script_include", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list script includes", err)), nil
 	}
@@ -211,25 +206,28 @@ func (r *Registry) listScriptIncludes(args map[string]interface{}) (*mcp.CallToo
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				scripts = append(scripts, map[string]interface{}{
+				scripts = append(scripts, selectFields(map[string]interface{}{
 					"sys_id":          data["sys_id"],
 					"name":            data["name"],
 					"api_name":        data["api_name"],
 					"description":     data["description"],
 					"active":          data["active"],
 					"client_callable": data["client_callable"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":         true,
 		"message":         fmt.Sprintf("Found %d script includes", len(scripts)),
 		"script_includes": scripts,
-	}), nil
+	}, page)), nil
 }
 
+// scriptIncludeFields are the fields of the record returned by get_script_include
+const scriptIncludeFields = "sys_id,name,api_name,description,script,active,client_callable,access,sys_scope,sys_updated_on"
+
 func (r *Registry) getScriptInclude(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	scriptID := GetStringArg(args, "script_id", "")
 	if scriptID == "" {
@@ -242,14 +240,16 @@ func (r *Registry) getScriptInclude(args map[string]interface{}) (*mcp.CallToolR
 	if IsSysID(scriptID) {
 		endpoint = fmt.Sprintf("/table/sys_script_include/%s", scriptID)
 		params = map[string]string{
+			"sysparm_fields":                 readFields(args, scriptIncludeFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
 	} else {
 		endpoint = "/table/sys_script_include"
 		params = map[string]string{
-			"sysparm_query":                  fmt.Sprintf("name=%s^ORapi_name=%s", scriptID, scriptID),
+			"sysparm_query":                  fmt.Sprintf("name=%s^ORapi_name=%s", SanitizeQueryValue(scriptID), SanitizeQueryValue(scriptID)),
 			"sysparm_limit":                  "1",
+			"sysparm_fields":                 readFields(args, scriptIncludeFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
@@ -270,16 +270,13 @@ func (r *Registry) getScriptInclude(args map[string]interface{}) (*mcp.CallToolR
 	}
 
 	if scriptData == nil {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": fmt.Sprintf("Script include not found: %s", scriptID),
-		}), nil
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Script include not found: %s", scriptID))), nil
 	}
 
 	return JSONResult(map[string]interface{}{
 		"success":        true,
 		"message":        "Script include found",
-		"script_include": scriptData,
+		"script_include": selectFields(scriptData, scriptData, args),
 	}), nil
 }
 
@@ -376,13 +373,14 @@ func (r *Registry) deleteScriptInclude(args map[string]interface{}) (*mcp.CallTo
 		return JSONResult(NewErrorResponse("script_id is required", nil)), nil
 	}
 
-	_, err := r.client.Delete(fmt.Sprintf("/table/sys_script_include/%s", scriptID))
+	recovery, err := r.deleteRecord("sys_script_include", scriptID)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to delete script include", err)), nil
 	}
 
 	return JSONResult(map[string]interface{}{
-		"success": true,
-		"message": "Script include deleted successfully",
+		"success":  true,
+		"message":  "Script include deleted successfully",
+		"recovery": recovery,
 	}), nil
 }
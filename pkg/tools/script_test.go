@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestExecuteBackgroundScript tests that the script tool is opt-in, never registered read-only, and returns the scripted REST API's output
+func TestExecuteBackgroundScript(t *testing.T) {
+	var script string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/acme/mcp_script/run" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		script, _ = body["script"].(string)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result": {"output": "42", "logs": ["active incidents: 42"], "execution_time_ms": 17}}`))
+	}))
+	defer ts.Close()
+
+	registered := func(registry *Registry) bool {
+		server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+		registry.RegisterAll(server)
+		for _, tool := range server.ListTools() {
+			if tool.Name == "execute_background_script" {
+				return true
+			}
+		}
+		return false
+	}
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	if registered(registry) {
+		t.Error("Expected execute_background_script to be unregistered unless enabled")
+	}
+	if err := registry.EnableScriptExecution("/api/acme/../now/table"); err == nil {
+		t.Error("Expected a path escaping the API to be rejected")
+	}
+	if err := registry.EnableScriptExecution("/api/acme/mcp_script/run"); err != nil {
+		t.Fatalf("Failed to enable script execution: %v", err)
+	}
+	if !registered(registry) {
+		t.Error("Expected execute_background_script to be registered once enabled")
+	}
+
+	readOnly, _ := newTestRegistry(t, ts.URL, true)
+	_ = readOnly.EnableScriptExecution("/api/acme/mcp_script/run")
+	if registered(readOnly) {
+		t.Error("Expected execute_background_script to be unregistered in read-only mode")
+	}
+
+	result, _ := registry.executeBackgroundScript(context.Background(), map[string]interface{}{"script": "gs.info('hi'); 42"})
+	var response struct {
+		Success         bool     `json:"success"`
+		Output          string   `json:"output"`
+		Logs            []string `json:"logs"`
+		ExecutionTimeMs int      `json:"execution_time_ms"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if script != "gs.info('hi'); 42" {
+		t.Errorf("Expected the script to be sent, got %q", script)
+	}
+	if !response.Success || response.Output != "42" || len(response.Logs) != 1 || response.ExecutionTimeMs != 17 {
+		t.Errorf("Expected the output, logs, and instance execution time, got %s", result.Content[0].Text)
+	}
+}
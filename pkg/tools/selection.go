@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// SetToolSelection limits the tools RegisterAll registers. Entries are tool names
+// (e.g., delete_workflow) or module names (e.g., workflows). When enable is
+// non-empty only the listed tools and modules are registered; disable always wins.
+// Tools that are not registered never appear in tools/list, whatever the package.
+func (r *Registry) SetToolSelection(enable, disable []string) {
+	r.enabledTools = selectionSet(enable)
+	r.disabledTools = selectionSet(disable)
+}
+
+// selectionSet converts a list of tool or module names to a set, ignoring blanks
+func selectionSet(names []string) map[string]bool {
+	set := map[string]bool{}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// registerModule registers a module's tools, tagging them with the module name so
+// the tool selection can match it. It returns the number of tools registered.
+func (r *Registry) registerModule(server *mcp.Server, module string, register func(*mcp.Server) int) int {
+	r.knownTools[module] = true
+	before := r.registeredTools
+	r.module = module
+	register(server)
+	r.module = ""
+	return r.registeredTools - before
+}
+
+// toolSelected reports whether the tool selection allows a tool of the module being registered
+func (r *Registry) toolSelected(name string) bool {
+	r.knownTools[name] = true
+	if r.disabledTools[name] || r.disabledTools[r.module] {
+		return false
+	}
+	return len(r.enabledTools) == 0 || r.enabledTools[name] || r.enabledTools[r.module]
+}
+
+// unknownToolSelections returns the selection entries that match no tool or module
+func (r *Registry) unknownToolSelections() []string {
+	var unknown []string
+	for _, set := range []map[string]bool{r.enabledTools, r.disabledTools} {
+		for name := range set {
+			if !r.knownTools[name] {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// Actions recorded in the session index
+const (
+	changeCreated = "created"
+	changeUpdated = "updated"
+	changeDeleted = "deleted"
+)
+
+// recordChange is a ServiceNow record created, updated, or deleted by a tool call
+type recordChange struct {
+	Time      time.Time   `json:"time"`
+	SessionID string      `json:"session_id"`
+	Tool      string      `json:"tool"`
+	RequestID interface{} `json:"request_id,omitempty"`
+	Action    string      `json:"action"`
+	Table     string      `json:"table"`
+	SysID     string      `json:"sys_id"`
+	Number    string      `json:"number,omitempty"`
+
+	// fields are the values sent with an update, kept when the call asked to verify them
+	fields map[string]interface{}
+}
+
+// changeRecorder collects the record changes made by the client during one tool call
+type changeRecorder struct {
+	mu      sync.Mutex
+	changes []recordChange
+	// keepFields keeps the values sent with updates for verify=true calls
+	keepFields bool
+}
+
+type changeRecorderKey struct{}
+
+// contextWithChangeRecorder returns a context whose client writes are collected by the returned recorder
+func contextWithChangeRecorder(ctx context.Context) (context.Context, *changeRecorder) {
+	recorder := &changeRecorder{}
+	return context.WithValue(ctx, changeRecorderKey{}, recorder), recorder
+}
+
+// recordWrite notes a successful write of body to a Table API endpoint (/table/<table>[/<sys_id>])
+// on the context's change recorder, if it has one
+func recordWrite(ctx context.Context, method, endpoint string, body interface{}, result map[string]interface{}) {
+	recorder, ok := ctx.Value(changeRecorderKey{}).(*changeRecorder)
+	if !ok {
+		return
+	}
+	path, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/table/"), "?")
+	if path == endpoint {
+		return
+	}
+	table, sysID, _ := strings.Cut(path, "/")
+
+	change := recordChange{Table: table, SysID: sysID}
+	switch method {
+	case http.MethodPost:
+		change.Action = changeCreated
+	case http.MethodPut, http.MethodPatch:
+		change.Action = changeUpdated
+		if recorder.keepFields {
+			change.fields = requestFields(body)
+		}
+	case http.MethodDelete:
+		change.Action = changeDeleted
+	default:
+		return
+	}
+	if record, ok := result["result"].(map[string]interface{}); ok {
+		if change.SysID == "" {
+			change.SysID = FieldValue(record["sys_id"])
+		}
+		change.Number = FieldValue(record["number"])
+	}
+	recorder.add(change)
+}
+
+// requestFields returns the fields of a request body as a map
+func requestFields(body interface{}) map[string]interface{} {
+	if fields, ok := body.(map[string]interface{}); ok {
+		return fields
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// recordUpload notes an attachment uploaded to a record on the context's change recorder
+func recordUpload(ctx context.Context, result map[string]interface{}) {
+	recorder, ok := ctx.Value(changeRecorderKey{}).(*changeRecorder)
+	if !ok {
+		return
+	}
+	if record, ok := result["result"].(map[string]interface{}); ok {
+		recorder.add(recordChange{Action: changeCreated, Table: "sys_attachment", SysID: FieldValue(record["sys_id"])})
+	}
+}
+
+// add appends a change made during the call
+func (c *changeRecorder) add(change recordChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, change)
+}
+
+// sessionIndex is an append-only JSON Lines file mapping MCP sessions to the
+// records their tool calls created, updated, or deleted
+type sessionIndex struct {
+	mu   sync.Mutex
+	path string
+	// stdioSession identifies this process's session when calls arrive outside an HTTP session
+	stdioSession string
+}
+
+// SetSessionIndex enables the session change index stored at path, and the
+// list_session_changes tool. Call it before RegisterAll.
+func (r *Registry) SetSessionIndex(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	r.sessionIndex = &sessionIndex{
+		path:         path,
+		stdioSession: fmt.Sprintf("stdio-%s-%d", time.Now().UTC().Format("20060102T150405"), os.Getpid()),
+	}
+	return nil
+}
+
+// sessionID returns the ID changes made with ctx are filed under
+func (ix *sessionIndex) sessionID(ctx context.Context) string {
+	if id := mcp.SessionIDFromContext(ctx); id != "" {
+		return id
+	}
+	return ix.stdioSession
+}
+
+// record appends the changes collected during a tool call
+func (ix *sessionIndex) record(ctx context.Context, tool string, recorder *changeRecorder) error {
+	recorder.mu.Lock()
+	changes := recorder.changes
+	recorder.mu.Unlock()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var lines []byte
+	now := time.Now().UTC()
+	for _, change := range changes {
+		change.Time, change.SessionID, change.Tool = now, ix.sessionID(ctx), tool
+		change.RequestID = mcp.RequestIDFromContext(ctx)
+		line, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	f, err := os.OpenFile(ix.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(lines); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// list returns the most recent changes (up to limit) of a session, oldest first,
+// optionally limited to one table
+func (ix *sessionIndex) list(sessionID, table string, limit int) ([]recordChange, error) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	f, err := os.Open(ix.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	changes := []recordChange{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var change recordChange
+		if json.Unmarshal(scanner.Bytes(), &change) != nil {
+			continue
+		}
+		if change.SessionID != sessionID || (table != "" && change.Table != table) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(changes) > limit {
+		changes = changes[len(changes)-limit:]
+	}
+	return changes, nil
+}
+
+// registerSessionChangeTools registers the session change index tool
+func (r *Registry) registerSessionChangeTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(1000)
+
+	// List Session Changes
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "list_session_changes",
+		Description: "List the ServiceNow records created, updated, or deleted by tool calls in an MCP session, for auditing what a session changed. Defaults to the current session.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"session_id": {
+					Type:        "string",
+					Description: "Session ID to list (default: the current session). HTTP sessions use the Mcp-Session-Id value; stdio sessions look like 'stdio-20241215T143000-4242'.",
+				},
+				"table": {
+					Type:        "string",
+					Description: "Only changes to records of this table (e.g., 'incident')",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max changes to return; the most recent are kept",
+					Default:     100,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Session Changes",
+			ReadOnlyHint: true,
+		},
+	}, r.listSessionChanges)
+	count++
+
+	return count
+}
+
+func (r *Registry) listSessionChanges(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	current := r.sessionIndex.sessionID(ctx)
+	sessionID := GetStringArg(args, "session_id", "")
+	if sessionID == "" {
+		sessionID = current
+	}
+
+	changes, err := r.sessionIndex.list(sessionID, GetStringArg(args, "table", ""), GetIntArg(args, "limit", 100))
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to read the session index", err)), nil
+	}
+
+	counts := map[string]int{changeCreated: 0, changeUpdated: 0, changeDeleted: 0}
+	for _, change := range changes {
+		counts[change.Action]++
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success":         true,
+		"message":         fmt.Sprintf("Found %d record changes in session %s", len(changes), sessionID),
+		"session_id":      sessionID,
+		"current_session": sessionID == current,
+		"counts":          counts,
+		"changes":         changes,
+	}), nil
+}
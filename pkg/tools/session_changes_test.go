@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// TestSessionChanges tests that records written by tool calls are indexed under the session
+func TestSessionChanges(t *testing.T) {
+	const (
+		dependentID    = "11111111111111111111111111111111"
+		prerequisiteID = "22222222222222222222222222222222"
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/m2m_story_dependencies":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/m2m_story_dependencies":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "dep1"}})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/rm_story/"+dependentID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": dependentID, "number": "STRY0010001"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	if err := registry.SetSessionIndex(filepath.Join(t.TempDir(), "sessions", "index.jsonl")); err != nil {
+		t.Fatalf("Failed to enable the session index: %v", err)
+	}
+	server := mcp.NewServer("test-servicenow-mcp", "1.0.0-test")
+	registry.RegisterAll(server)
+
+	addDependency, _ := server.Handler("add_story_dependency")
+	result, _ := addDependency(context.Background(), map[string]interface{}{"story_id": dependentID, "blocked_by": prerequisiteID})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"success": true`) {
+		t.Fatalf("Expected the dependency to be added, got %+v", result)
+	}
+
+	listChanges, ok := server.Handler("list_session_changes")
+	if !ok {
+		t.Fatal("Expected list_session_changes to be registered when the index is enabled")
+	}
+	result, _ = listChanges(context.Background(), map[string]interface{}{})
+	var body struct {
+		SessionID string         `json:"session_id"`
+		Counts    map[string]int `json:"counts"`
+		Changes   []recordChange `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !strings.HasPrefix(body.SessionID, "stdio-") || len(body.Changes) != 2 || body.Counts[changeCreated] != 1 || body.Counts[changeUpdated] != 1 {
+		t.Fatalf("Expected one created and one updated record in the stdio session, got %+v", body)
+	}
+	created, updated := body.Changes[0], body.Changes[1]
+	if created.Table != storyDependencyTable || created.SysID != "dep1" || created.Tool != "add_story_dependency" {
+		t.Errorf("Unexpected created record %+v", created)
+	}
+	if updated.Table != "rm_story" || updated.SysID != dependentID || updated.Number != "STRY0010001" {
+		t.Errorf("Unexpected updated record %+v", updated)
+	}
+
+	result, _ = listChanges(context.Background(), map[string]interface{}{"table": "rm_story", "limit": float64(5)})
+	if !strings.Contains(result.Content[0].Text, `"updated": 1`) || strings.Contains(result.Content[0].Text, storyDependencyTable) {
+		t.Errorf("Expected only the rm_story change, got %s", result.Content[0].Text)
+	}
+	result, _ = listChanges(context.Background(), map[string]interface{}{"session_id": "another-session"})
+	if !strings.Contains(result.Content[0].Text, `"changes": []`) {
+		t.Errorf("Expected no changes for another session, got %s", result.Content[0].Text)
+	}
+}
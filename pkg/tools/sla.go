@@ -0,0 +1,550 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// taskSLAFields are the task_sla fields read by the SLA tools
+const taskSLAFields = "sys_id,task,task.number,task.sys_class_name,task.priority,task.short_description,sla,stage,has_breached," +
+	"start_time,planned_end_time,percentage,business_percentage,time_left,business_time_left," +
+	"schedule,pause_duration,business_pause_duration"
+
+// taskSLA is the state of one SLA attached to a task
+type taskSLA struct {
+	SLAID               string  `json:"sla_id"`
+	TaskID              string  `json:"task_id"`
+	Task                string  `json:"task"`
+	TaskTable           string  `json:"task_table,omitempty"`
+	Priority            string  `json:"priority,omitempty"`
+	ShortDescription    string  `json:"short_description,omitempty"`
+	Definition          string  `json:"definition"`
+	Stage               string  `json:"stage"`
+	Breached            bool    `json:"breached"`
+	StartTime           string  `json:"start_time,omitempty"`
+	BreachTime          string  `json:"breach_time,omitempty"`
+	PercentElapsed      float64 `json:"percent_elapsed"`
+	BusinessPercent     float64 `json:"business_percent_elapsed"`
+	RemainingMinutes    int     `json:"remaining_minutes"`
+	TimeLeft            string  `json:"time_left,omitempty"`
+	Schedule            string  `json:"schedule,omitempty"`
+	BusinessMinutesLeft *int    `json:"business_minutes_left,omitempty"` // working time on the schedule until breach
+
+	scheduleID    string
+	pause         time.Duration
+	businessPause time.Duration
+}
+
+// newTaskSLA builds the SLA state from a task_sla record read with sysparm_display_value=all.
+// For running SLAs the elapsed percentage and remaining time are computed from the start
+// and breach times less time spent paused, since the recorded percentages are only refreshed
+// periodically by the SLA timer job; otherwise the recorded values are used.
+func newTaskSLA(record map[string]interface{}, now time.Time) taskSLA {
+	sla := taskSLA{
+		SLAID:            FieldValue(record["sys_id"]),
+		TaskID:           FieldValue(record["task"]),
+		Task:             FieldDisplay(record["task.number"]),
+		TaskTable:        FieldValue(record["task.sys_class_name"]),
+		Priority:         FieldDisplay(record["task.priority"]),
+		ShortDescription: FieldDisplay(record["task.short_description"]),
+		Definition:       FieldDisplay(record["sla"]),
+		Stage:            FieldValue(record["stage"]),
+		Breached:         FieldValue(record["has_breached"]) == "true",
+		StartTime:        FieldValue(record["start_time"]),
+		BreachTime:       FieldValue(record["planned_end_time"]),
+		PercentElapsed:   parsePercent(FieldValue(record["percentage"])),
+		BusinessPercent:  parsePercent(FieldValue(record["business_percentage"])),
+		TimeLeft:         FieldDisplay(record["business_time_left"]),
+		Schedule:         FieldDisplay(record["schedule"]),
+		scheduleID:       FieldValue(record["schedule"]),
+	}
+	sla.pause, _ = parseServiceNowDuration(FieldValue(record["pause_duration"]))
+	sla.businessPause, _ = parseServiceNowDuration(FieldValue(record["business_pause_duration"]))
+	if left, ok := parseServiceNowDuration(FieldValue(record["business_time_left"])); ok {
+		sla.RemainingMinutes = int(left.Minutes())
+	}
+
+	start, startErr := time.Parse(dateTimeLayout, sla.StartTime)
+	breach, breachErr := time.Parse(dateTimeLayout, sla.BreachTime)
+	if sla.Stage == "in_progress" && startErr == nil && breachErr == nil && breach.After(start) {
+		sla.PercentElapsed = roundPercent(float64(now.Sub(start)-sla.pause) / float64(breach.Sub(start)-sla.pause) * 100)
+		sla.RemainingMinutes = int(math.Floor(breach.Sub(now).Minutes()))
+	}
+	return sla
+}
+
+// applySLASchedules computes the business percentage elapsed and business time left of
+// running SLAs from their schedules, loading each schedule once. SLAs whose schedule
+// can't be read keep the recorded business percentage.
+func (r *Registry) applySLASchedules(slas []taskSLA, now time.Time) {
+	schedules := map[string]*businessSchedule{}
+	for i := range slas {
+		sla := &slas[i]
+		if sla.Stage != "in_progress" || !IsSysID(sla.scheduleID) {
+			continue
+		}
+		start, startErr := time.Parse(dateTimeLayout, sla.StartTime)
+		breach, breachErr := time.Parse(dateTimeLayout, sla.BreachTime)
+		if startErr != nil || breachErr != nil || !breach.After(start) || breach.Sub(start) > maxScheduleRange {
+			continue
+		}
+
+		schedule, loaded := schedules[sla.scheduleID]
+		if !loaded {
+			schedule, _ = r.loadSchedule(sla.scheduleID)
+			schedules[sla.scheduleID] = schedule
+		}
+		if schedule == nil {
+			continue
+		}
+
+		total := schedule.duration(start, breach) - sla.businessPause
+		if total <= 0 {
+			continue
+		}
+		sla.BusinessPercent = roundPercent(float64(schedule.duration(start, now)-sla.businessPause) / float64(total) * 100)
+		left := schedule.duration(now, breach)
+		if now.After(breach) {
+			left = -schedule.duration(breach, now)
+		}
+		minutes := int(left.Minutes())
+		sla.BusinessMinutesLeft = &minutes
+	}
+}
+
+// parsePercent parses a recorded SLA percentage, rounded to one decimal place
+func parsePercent(value string) float64 {
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return roundPercent(percent)
+}
+
+// roundPercent rounds a percentage to one decimal place
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*10) / 10
+}
+
+// parseServiceNowDuration parses a raw duration value, stored as a date/time offset
+// from the epoch (e.g., "1970-01-01 02:30:00" is two and a half hours)
+func parseServiceNowDuration(value string) (time.Duration, bool) {
+	t, err := time.Parse(dateTimeLayout, value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Sub(time.Unix(0, 0).UTC()), true
+}
+
+// slaScanLimit is the number of running SLAs, nearest breach first, will_breach_soon inspects
+const slaScanLimit = 500
+
+// remainingPercent returns the share of an SLA's time left before breach, from its
+// schedule when one applies and otherwise from wall-clock time
+func (sla taskSLA) remainingPercent() float64 {
+	elapsed := sla.PercentElapsed
+	if sla.BusinessMinutesLeft != nil {
+		elapsed = sla.BusinessPercent
+	}
+	return math.Max(0, roundPercent(100-elapsed))
+}
+
+// registerSLATools registers task SLA tools
+func (r *Registry) registerSLATools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(100)
+	offsetMin := float64(0)
+	percentMin := float64(1)
+	percentMax := float64(100)
+
+	// Get Incident SLA
+	r.registerTool(server, mcp.Tool{
+		Name:        "get_incident_sla",
+		Description: "Get the SLAs attached to an incident with their stage, breach status, percentage of time elapsed, and time remaining before breach.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"incident_id": {
+					Type:        "string",
+					Description: "Incident number (e.g., 'INC0010001') or sys_id",
+				},
+			},
+			Required: []string{"incident_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Get Incident SLA",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).getIncidentSLA)
+	count++
+
+	// List SLA Breaches
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_sla_breaches",
+		Description: "List breached incident SLAs, most recent breach first, optionally for one priority (e.g., priority '1' for P1 incidents).",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"priority": {
+					Type:        "string",
+					Description: "Only incidents with this priority (1=Critical, 2=High, 3=Moderate, 4=Low, 5=Planning)",
+					Enum:        []string{"1", "2", "3", "4", "5"},
+				},
+				"active_only": {
+					Type:        "boolean",
+					Description: "Only incidents that are still open",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List SLA Breaches",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listSLABreaches)
+	count++
+
+	// Will Breach Soon
+	r.registerTool(server, mcp.Tool{
+		Name:        "will_breach_soon",
+		Description: "List running task SLAs that have not breached yet but have less than a given percentage of their time remaining, nearest breach first. The proactive counterpart to list_sla_breaches.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"remaining_percent": {
+					Type:        "number",
+					Description: "Include SLAs with less than this percentage of their time remaining",
+					Default:     25,
+					Minimum:     &percentMin,
+					Maximum:     &percentMax,
+				},
+				"task_table": {
+					Type:        "string",
+					Description: "Only SLAs on tasks of this table (e.g., 'incident', 'sc_req_item')",
+				},
+				"priority": {
+					Type:        "string",
+					Description: "Only tasks with this priority (1=Critical, 2=High, 3=Moderate, 4=Low, 5=Planning)",
+					Enum:        []string{"1", "2", "3", "4", "5"},
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+			},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Will Breach Soon",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).willBreachSoon)
+	count++
+
+	// List SLA Definitions
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_sla_definitions",
+		Description: "List SLA definitions (contract_sla) with their targets, durations, schedules, and start/pause/stop conditions. Filter by priority and category to see which SLAs apply to such tickets.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withFieldSelection(map[string]mcp.Property{
+				"table": {
+					Type:        "string",
+					Description: "Only definitions for this task table (e.g., 'incident', 'sc_req_item')",
+				},
+				"type": {
+					Type:        "string",
+					Description: "Only definitions of this type",
+					Enum:        []string{"SLA", "OLA", "Underpinning contract"},
+				},
+				"priority": {
+					Type:        "string",
+					Description: "Only definitions whose start condition allows this priority (1-5); definitions without a priority condition are included",
+					Enum:        []string{"1", "2", "3", "4", "5"},
+				},
+				"category": {
+					Type:        "string",
+					Description: "Only definitions whose start condition allows this category (e.g., 'hardware'); definitions without a category condition are included",
+				},
+				"query": {
+					Type:        "string",
+					Description: "Text search in the definition name (e.g., 'resolution')",
+				},
+				"active_only": {
+					Type:        "boolean",
+					Description: "Only active definitions",
+					Default:     true,
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     50,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			}),
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List SLA Definitions",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listSLADefinitions)
+	count++
+
+	return count
+}
+
+func (r *Registry) getIncidentSLA(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	incidentID := GetStringArg(args, "incident_id", "")
+	if incidentID == "" {
+		return JSONResult(NewErrorResponse("incident_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveIncidentID(incidentID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find incident", err)), nil
+	}
+
+	result, err := r.client.Get("/table/task_sla", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("task=%s^ORDERBYplanned_end_time", sysID),
+		"sysparm_fields":                 taskSLAFields,
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get incident SLAs", err)), nil
+	}
+
+	now := time.Now().UTC()
+	slas := []taskSLA{}
+	breached := 0
+	for _, record := range GetResultList(result) {
+		sla := newTaskSLA(record, now)
+		if sla.Breached {
+			breached++
+		}
+		slas = append(slas, sla)
+	}
+	r.applySLASchedules(slas, now)
+
+	message := fmt.Sprintf("Incident %s has %d SLAs, %d breached", incidentID, len(slas), breached)
+	if len(slas) == 0 {
+		message = fmt.Sprintf("Incident %s has no SLAs attached", incidentID)
+	}
+	return JSONResult(map[string]interface{}{
+		"success":  true,
+		"message":  message,
+		"slas":     slas,
+		"breached": breached,
+	}), nil
+}
+
+func (r *Registry) listSLABreaches(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	query := "has_breached=true^task.sys_class_name=incident"
+	if GetBoolArg(args, "active_only", true) {
+		query += "^task.active=true"
+	}
+	priority := GetStringArg(args, "priority", "")
+	if priority != "" {
+		if _, ok := priorityLabels[priority]; !ok {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid priority: %s (use 1-5)", priority), nil)), nil
+		}
+		query += "^task.priority=" + priority
+	}
+
+	result, page, err := r.listRecords("/table/task_sla", map[string]string{
+		"sysparm_query":                  query + "^ORDERBYDESCplanned_end_time",
+		"sysparm_fields":                 taskSLAFields,
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", GetIntArg(args, "limit", 20)),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+	}, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list SLA breaches", err)), nil
+	}
+
+	now := time.Now().UTC()
+	slas := []taskSLA{}
+	for _, record := range GetResultList(result) {
+		slas = append(slas, newTaskSLA(record, now))
+	}
+	r.applySLASchedules(slas, now)
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d breached incident SLAs", len(slas)),
+		"slas":    slas,
+	}, page)), nil
+}
+
+func (r *Registry) willBreachSoon(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	query := "stage=in_progress^has_breached=false"
+	if table := GetStringArg(args, "task_table", ""); table != "" {
+		if !tableNamePattern.MatchString(table) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+		}
+		query += "^task.sys_class_name=" + table
+	}
+	if priority := GetStringArg(args, "priority", ""); priority != "" {
+		if _, ok := priorityLabels[priority]; !ok {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid priority: %s (use 1-5)", priority), nil)), nil
+		}
+		query += "^task.priority=" + priority
+	}
+
+	// The recorded percentages lag behind the SLA timer job, so the running SLAs nearest
+	// to breach are read and their remaining time computed here
+	result, err := r.client.Get("/table/task_sla", map[string]string{
+		"sysparm_query":                  query + "^ORDERBYplanned_end_time",
+		"sysparm_fields":                 taskSLAFields,
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", slaScanLimit),
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list running SLAs", err)), nil
+	}
+
+	now := time.Now().UTC()
+	running := []taskSLA{}
+	for _, record := range GetResultList(result) {
+		running = append(running, newTaskSLA(record, now))
+	}
+	r.applySLASchedules(running, now)
+
+	threshold := float64(25)
+	if v, ok := CoerceNumber(args["remaining_percent"]); ok {
+		threshold = v
+	}
+	limit := GetIntArg(args, "limit", 20)
+	slas := []taskSLA{}
+	for _, sla := range running {
+		if len(slas) >= limit {
+			break
+		}
+		if sla.remainingPercent() < threshold {
+			slas = append(slas, sla)
+		}
+	}
+
+	return JSONResult(map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d running SLAs with less than %v%% of their time remaining", len(slas), threshold),
+		"slas":    slas,
+	}), nil
+}
+
+func (r *Registry) listSLADefinitions(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	var filters []string
+	if GetBoolArg(args, "active_only", true) {
+		filters = append(filters, "active=true")
+	}
+	if table := GetStringArg(args, "table", ""); table != "" {
+		if !tableNamePattern.MatchString(table) {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)), nil
+		}
+		filters = append(filters, "collection="+table)
+	}
+	if slaType := GetStringArg(args, "type", ""); slaType != "" {
+		filters = append(filters, "type="+SanitizeQueryValue(slaType))
+	}
+	if query := GetStringArg(args, "query", ""); query != "" {
+		filters = append(filters, LikeFilter(query, "name"))
+	}
+	filters = append(filters, "ORDERBYname")
+
+	priority := GetStringArg(args, "priority", "")
+	if priority != "" {
+		if _, ok := priorityLabels[priority]; !ok {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("Invalid priority: %s (use 1-5)", priority), nil)), nil
+		}
+	}
+	category := GetStringArg(args, "category", "")
+	limit := GetIntArg(args, "limit", 50)
+	offset := GetIntArg(args, "offset", 0)
+	matchLocally := priority != "" || category != ""
+	params := map[string]string{
+		"sysparm_query":                  strings.Join(filters, "^"),
+		"sysparm_fields":                 readFields(args, "sys_id,name,type,collection,target,duration,schedule,start_condition,pause_condition,stop_condition,active"),
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+	}
+	if matchLocally {
+		// Start conditions are matched here, so read enough definitions to page through the matches
+		params["sysparm_limit"] = fmt.Sprintf("%d", conditionScanLimit)
+		params["sysparm_offset"] = "0"
+	}
+
+	result, page, err := r.listRecords("/table/contract_sla", params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to list SLA definitions", err)), nil
+	}
+
+	definitions := []map[string]interface{}{}
+	for _, record := range GetResultList(result) {
+		start := FieldValue(record["start_condition"])
+		if priority != "" && !conditionAllows(start, "priority", priority) {
+			continue
+		}
+		if category != "" && !conditionAllows(start, "category", category) {
+			continue
+		}
+		definition := map[string]interface{}{
+			"sys_id":          FieldValue(record["sys_id"]),
+			"name":            FieldDisplay(record["name"]),
+			"type":            FieldValue(record["type"]),
+			"table":           FieldValue(record["collection"]),
+			"target":          FieldValue(record["target"]),
+			"duration":        FieldDisplay(record["duration"]),
+			"schedule":        FieldDisplay(record["schedule"]),
+			"active":          FieldValue(record["active"]) == "true",
+			"start_condition": start,
+			"pause_condition": FieldValue(record["pause_condition"]),
+			"stop_condition":  FieldValue(record["stop_condition"]),
+		}
+		if duration, ok := parseServiceNowDuration(FieldValue(record["duration"])); ok {
+			definition["duration_minutes"] = duration.Minutes()
+		}
+		definitions = append(definitions, selectFields(definition, record, args))
+	}
+	if matchLocally {
+		definitions, page = pageOfMatches(definitions, page, limit, offset)
+	}
+
+	return JSONResult(withPaging(map[string]interface{}{
+		"success":     true,
+		"message":     fmt.Sprintf("Found %d SLA definitions", len(definitions)),
+		"definitions": definitions,
+	}, page)), nil
+}
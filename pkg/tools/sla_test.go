@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestNewTaskSLA tests the elapsed percentage and remaining time of running and stopped SLAs
+func TestNewTaskSLA(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+
+	running := newTaskSLA(map[string]interface{}{
+		"task.number":        field("INC0010001", "INC0010001"),
+		"sla":                field("s1", "P1 resolution (4 hour)"),
+		"stage":              field("in_progress", "In progress"),
+		"has_breached":       field("false", "false"),
+		"start_time":         field("2024-03-01 09:00:00", "2024-03-01 01:00:00"),
+		"planned_end_time":   field("2024-03-01 13:00:00", "2024-03-01 05:00:00"),
+		"percentage":         field("50", "50"),
+		"business_time_left": field("1970-01-01 02:00:00", "2 Hours"),
+	}, now)
+	if running.PercentElapsed != 75 || running.RemainingMinutes != 60 {
+		t.Errorf("Expected 75%% elapsed with 60 minutes left, got %v%% and %d", running.PercentElapsed, running.RemainingMinutes)
+	}
+	if running.Definition != "P1 resolution (4 hour)" || running.Task != "INC0010001" {
+		t.Errorf("Expected display values for the SLA and task, got %+v", running)
+	}
+
+	overdue := newTaskSLA(map[string]interface{}{
+		"stage":            "in_progress",
+		"has_breached":     "true",
+		"start_time":       "2024-03-01 10:00:00",
+		"planned_end_time": "2024-03-01 11:30:00",
+	}, now)
+	if !overdue.Breached || overdue.PercentElapsed != 133.3 || overdue.RemainingMinutes != -30 {
+		t.Errorf("Expected a breach 30 minutes ago at 133.3%%, got %+v", overdue)
+	}
+
+	paused := newTaskSLA(map[string]interface{}{
+		"stage":              "paused",
+		"start_time":         "2024-03-01 09:00:00",
+		"planned_end_time":   "2024-03-01 13:00:00",
+		"percentage":         "42.25",
+		"business_time_left": "1970-01-01 01:15:00",
+	}, now)
+	if paused.PercentElapsed != 42.3 || paused.RemainingMinutes != 75 {
+		t.Errorf("Expected the recorded values for a paused SLA, got %+v", paused)
+	}
+}
+
+// TestWillBreachSoon tests that only running SLAs under the remaining-time threshold are listed
+func TestWillBreachSoon(t *testing.T) {
+	now := time.Now().UTC()
+	running := func(number string, elapsed, left time.Duration) map[string]interface{} {
+		return map[string]interface{}{
+			"task.number":      number,
+			"stage":            "in_progress",
+			"has_breached":     "false",
+			"start_time":       now.Add(-elapsed).Format(dateTimeLayout),
+			"planned_end_time": now.Add(left).Format(dateTimeLayout),
+			"percentage":       "0",
+		}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/now/table/task_sla" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		want := "stage=in_progress^has_breached=false^task.sys_class_name=incident^ORDERBYplanned_end_time"
+		if q := r.URL.Query().Get("sysparm_query"); q != want {
+			t.Errorf("Expected query %s, got %s", want, q)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+			running("INC0010001", 90*time.Minute, 10*time.Minute),
+			running("INC0010002", 10*time.Minute, 90*time.Minute),
+		}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.willBreachSoon(map[string]interface{}{"task_table": "incident", "remaining_percent": float64(20)})
+
+	var body struct {
+		SLAs []taskSLA `json:"slas"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil || len(body.SLAs) != 1 || body.SLAs[0].Task != "INC0010001" {
+		t.Fatalf("Expected only the SLA with 10%% remaining, got %s", result.Content[0].Text)
+	}
+}
+
+// TestListSLADefinitions tests that definitions are matched on the priority and category in their start condition
+func TestListSLADefinitions(t *testing.T) {
+	definition := func(name, start string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":            name,
+			"collection":      "incident",
+			"duration":        "1970-01-01 08:00:00",
+			"start_condition": start,
+			"active":          "true",
+		}
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/now/table/contract_sla" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		want := "active=true^collection=incident^ORDERBYname"
+		if q := r.URL.Query().Get("sysparm_query"); q != want {
+			t.Errorf("Expected query %s, got %s", want, q)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{
+			definition("P1 resolution", "active=true^priority=1"),
+			definition("P2 hardware resolution", "active=true^priorityIN1,2^category=hardware"),
+			definition("P2 software resolution", "active=true^priority=2^category=software"),
+			definition("Response", "active=true"),
+		}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.listSLADefinitions(map[string]interface{}{"table": "incident", "priority": "2", "category": "Hardware"})
+
+	var body struct {
+		Definitions []struct {
+			Name            string  `json:"name"`
+			DurationMinutes float64 `json:"duration_minutes"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &body); err != nil || len(body.Definitions) != 2 {
+		t.Fatalf("Expected the hardware and unconstrained definitions, got %s", result.Content[0].Text)
+	}
+	if body.Definitions[0].Name != "P2 hardware resolution" || body.Definitions[1].Name != "Response" {
+		t.Errorf("Unexpected definitions: %+v", body.Definitions)
+	}
+	if body.Definitions[0].DurationMinutes != 480 {
+		t.Errorf("Expected an 8 hour duration, got %v minutes", body.Definitions[0].DurationMinutes)
+	}
+}
@@ -0,0 +1,315 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// storyDependencyTable links a dependent story to the prerequisite story it is blocked by
+const storyDependencyTable = "m2m_story_dependencies"
+
+// registerStoryDependencyTools registers tools for story dependencies (blocked-by links between stories)
+func (r *Registry) registerStoryDependencyTools(server *mcp.Server) int {
+	count := 0
+
+	// List Story Dependencies
+	r.registerTool(server, mcp.Tool{
+		Name:        "list_story_dependencies",
+		Description: "List the stories a story is blocked by and the stories it is blocking.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"story_id": {
+					Type:        "string",
+					Description: "Story number (e.g., 'STRY0010001') or sys_id. Accepts both formats.",
+				},
+				"direction": {
+					Type:        "string",
+					Description: "Which links to return: 'blocked_by' (prerequisites), 'blocking' (dependents), or 'all'",
+					Default:     "all",
+					Enum:        []string{"blocked_by", "blocking", "all"},
+				},
+			},
+			Required: []string{"story_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "List Story Dependencies",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).listStoryDependencies)
+	count++
+
+	// Write operations
+	if !r.readOnlyMode {
+		// Add Story Dependency
+		r.registerTool(server, mcp.Tool{
+			Name:        "add_story_dependency",
+			Description: "Record that a story is blocked by another story. By default the dependent story is also flagged as blocked.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"story_id": {
+						Type:        "string",
+						Description: "The dependent (blocked) story number or sys_id (e.g., 'STRY0010001')",
+					},
+					"blocked_by": {
+						Type:        "string",
+						Description: "The prerequisite story number or sys_id that must be done first (e.g., 'STRY0010002')",
+					},
+					"mark_blocked": {
+						Type:        "boolean",
+						Description: "Also set blocked=true on the dependent story",
+						Default:     true,
+					},
+					"blocked_reason": {
+						Type:        "string",
+						Description: "Reason shown on the blocked story. Defaults to 'Blocked by <prerequisite number>'.",
+					},
+				},
+				Required: []string{"story_id", "blocked_by"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title: "Add Story Dependency",
+			},
+		}, (*Registry).addStoryDependency)
+		count++
+
+		// Remove Story Dependency
+		r.registerTool(server, mcp.Tool{
+			Name:        "remove_story_dependency",
+			Description: "Remove a blocked-by link between two stories. Optionally clears the blocked flag when no prerequisites remain.",
+			InputSchema: mcp.JSONSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"story_id": {
+						Type:        "string",
+						Description: "The dependent (blocked) story number or sys_id",
+					},
+					"blocked_by": {
+						Type:        "string",
+						Description: "The prerequisite story number or sys_id",
+					},
+					"unblock": {
+						Type:        "boolean",
+						Description: "Set blocked=false on the dependent story if it has no remaining prerequisites",
+						Default:     true,
+					},
+				},
+				Required: []string{"story_id", "blocked_by"},
+			},
+			Annotations: &mcp.ToolAnnotation{
+				Title:           "Remove Story Dependency",
+				DestructiveHint: true,
+			},
+		}, (*Registry).removeStoryDependency)
+		count++
+	}
+
+	return count
+}
+
+func (r *Registry) listStoryDependencies(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	storyID := GetStringArg(args, "story_id", "")
+	if storyID == "" {
+		return JSONResult(NewErrorResponse("story_id is required", nil)), nil
+	}
+
+	sysID, err := r.resolveStoryID(storyID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find story", err)), nil
+	}
+
+	direction := GetStringArg(args, "direction", "all")
+	response := map[string]interface{}{
+		"success":  true,
+		"story_id": sysID,
+	}
+
+	total := 0
+	if direction == "blocked_by" || direction == "all" {
+		links, err := r.getStoryDependencies(fmt.Sprintf("dependent_story=%s", sysID), "prerequisite_story")
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to list story dependencies", err)), nil
+		}
+		response["blocked_by"] = links
+		total += len(links)
+	}
+	if direction == "blocking" || direction == "all" {
+		links, err := r.getStoryDependencies(fmt.Sprintf("prerequisite_story=%s", sysID), "dependent_story")
+		if err != nil {
+			return JSONResult(NewErrorResponse("Failed to list story dependencies", err)), nil
+		}
+		response["blocking"] = links
+		total += len(links)
+	}
+
+	response["message"] = fmt.Sprintf("Found %d story dependencies", total)
+	return JSONResult(response), nil
+}
+
+// getStoryDependencies returns the stories on the other side of the dependency links matching query
+func (r *Registry) getStoryDependencies(query, otherField string) ([]map[string]interface{}, error) {
+	result, err := r.client.Get(fmt.Sprintf("/table/%s", storyDependencyTable), map[string]string{
+		"sysparm_query":  query,
+		"sysparm_fields": fmt.Sprintf("sys_id,%[1]s,%[1]s.number,%[1]s.short_description,%[1]s.state", otherField),
+		"sysparm_limit":  "100",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links := []map[string]interface{}{}
+	for _, record := range GetResultList(result) {
+		links = append(links, map[string]interface{}{
+			"dependency_id":     record["sys_id"],
+			"story_id":          FieldValue(record[otherField]),
+			"number":            record[otherField+".number"],
+			"short_description": record[otherField+".short_description"],
+			"state":             record[otherField+".state"],
+		})
+	}
+	return links, nil
+}
+
+func (r *Registry) addStoryDependency(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	storyID := GetStringArg(args, "story_id", "")
+	blockedBy := GetStringArg(args, "blocked_by", "")
+	if storyID == "" || blockedBy == "" {
+		return JSONResult(NewErrorResponse("story_id and blocked_by are required", nil)), nil
+	}
+
+	dependentSysID, err := r.resolveStoryID(storyID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find story", err)), nil
+	}
+	prerequisiteSysID, err := r.resolveStoryID(blockedBy)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find blocking story", err)), nil
+	}
+	if dependentSysID == prerequisiteSysID {
+		return JSONResult(NewErrorResponse("A story cannot be blocked by itself", nil)), nil
+	}
+
+	// Avoid duplicate links and direct cycles
+	existing, err := r.client.Get(fmt.Sprintf("/table/%s", storyDependencyTable), map[string]string{
+		"sysparm_query": fmt.Sprintf("dependent_story=%[1]s^prerequisite_story=%[2]s^NQdependent_story=%[2]s^prerequisite_story=%[1]s",
+			dependentSysID, prerequisiteSysID),
+		"sysparm_fields": "sys_id,dependent_story",
+		"sysparm_limit":  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to check existing dependencies", err)), nil
+	}
+	if records := GetResultList(existing); len(records) > 0 {
+		if FieldValue(records[0]["dependent_story"]) == dependentSysID {
+			return JSONResult(NewErrorResponse(fmt.Sprintf("%s is already blocked by %s", storyID, blockedBy), nil)), nil
+		}
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s is already blocked by %s; adding this link would create a cycle", blockedBy, storyID), nil)), nil
+	}
+
+	result, err := r.client.Post(fmt.Sprintf("/table/%s", storyDependencyTable), map[string]interface{}{
+		"dependent_story":    dependentSysID,
+		"prerequisite_story": prerequisiteSysID,
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to add story dependency", err)), nil
+	}
+
+	resultData, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return JSONResult(NewErrorResponse("Unexpected response from ServiceNow", nil)), nil
+	}
+
+	response := map[string]interface{}{
+		"success":       true,
+		"message":       fmt.Sprintf("%s is now blocked by %s", storyID, blockedBy),
+		"dependency_id": resultData["sys_id"],
+	}
+
+	if GetBoolArg(args, "mark_blocked", true) {
+		reason := GetStringArg(args, "blocked_reason", fmt.Sprintf("Blocked by %s", blockedBy))
+		if _, err := r.client.Put(fmt.Sprintf("/table/rm_story/%s", dependentSysID), map[string]interface{}{
+			"blocked":        true,
+			"blocked_reason": reason,
+		}); err != nil {
+			withWarnings(response, fmt.Sprintf("Dependency added but failed to flag the story as blocked: %v", err))
+		}
+	}
+
+	return JSONResult(response), nil
+}
+
+func (r *Registry) removeStoryDependency(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if r.readOnlyMode {
+		return WriteBlockedResult(), nil
+	}
+
+	storyID := GetStringArg(args, "story_id", "")
+	blockedBy := GetStringArg(args, "blocked_by", "")
+	if storyID == "" || blockedBy == "" {
+		return JSONResult(NewErrorResponse("story_id and blocked_by are required", nil)), nil
+	}
+
+	dependentSysID, err := r.resolveStoryID(storyID)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find story", err)), nil
+	}
+	prerequisiteSysID, err := r.resolveStoryID(blockedBy)
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find blocking story", err)), nil
+	}
+
+	existing, err := r.client.Get(fmt.Sprintf("/table/%s", storyDependencyTable), map[string]string{
+		"sysparm_query":  fmt.Sprintf("dependent_story=%s^prerequisite_story=%s", dependentSysID, prerequisiteSysID),
+		"sysparm_fields": "sys_id",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to find story dependency", err)), nil
+	}
+	links := GetResultList(existing)
+	if len(links) == 0 {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("%s is not blocked by %s", storyID, blockedBy), nil)), nil
+	}
+
+	for _, link := range links {
+		if _, err := r.client.Delete(fmt.Sprintf("/table/%s/%s", storyDependencyTable, link["sys_id"])); err != nil {
+			return JSONResult(NewErrorResponse("Failed to remove story dependency", err)), nil
+		}
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%s is no longer blocked by %s", storyID, blockedBy),
+	}
+
+	if GetBoolArg(args, "unblock", true) {
+		remaining, err := r.getStoryDependencies(fmt.Sprintf("dependent_story=%s", dependentSysID), "prerequisite_story")
+		switch {
+		case err != nil:
+			withWarnings(response, fmt.Sprintf("Dependency removed but failed to check remaining prerequisites: %v", err))
+		case len(remaining) > 0:
+			response["remaining_blocked_by"] = remaining
+		default:
+			if _, err := r.client.Put(fmt.Sprintf("/table/rm_story/%s", dependentSysID), map[string]interface{}{
+				"blocked":        false,
+				"blocked_reason": "",
+			}); err != nil {
+				withWarnings(response, fmt.Sprintf("Dependency removed but failed to clear the blocked flag: %v", err))
+			} else {
+				response["unblocked"] = true
+			}
+		}
+	}
+
+	return JSONResult(response), nil
+}
+
+// resolveStoryID resolves a story number to sys_id
+func (r *Registry) resolveStoryID(storyID string) (string, error) {
+	return r.resolveRecordNumber("rm_story", storyID, "story")
+}
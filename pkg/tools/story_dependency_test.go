@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestAddStoryDependency tests that a blocked-by link is created and the dependent story flagged as blocked
+func TestAddStoryDependency(t *testing.T) {
+	stories := map[string]string{"STRY0010001": "story1", "STRY0010002": "story2"}
+
+	var link, flagged map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/rm_story":
+			number := strings.TrimPrefix(r.URL.Query().Get("sysparm_query"), "number=")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"sys_id": stories[number]}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/m2m_story_dependencies":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/m2m_story_dependencies":
+			_ = json.NewDecoder(r.Body).Decode(&link)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "dep1"}})
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/rm_story/story1":
+			_ = json.NewDecoder(r.Body).Decode(&flagged)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": "story1"}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.addStoryDependency(map[string]interface{}{
+		"story_id":   "STRY0010001",
+		"blocked_by": "STRY0010002",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"dependency_id": "dep1"`) {
+		t.Fatalf("Expected dependency result, got %+v", result)
+	}
+	if link["dependent_story"] != "story1" || link["prerequisite_story"] != "story2" {
+		t.Errorf("Expected story1 blocked by story2, got %v", link)
+	}
+	if flagged["blocked"] != true || flagged["blocked_reason"] != "Blocked by STRY0010002" {
+		t.Errorf("Expected story flagged as blocked, got %v", flagged)
+	}
+
+	result, _ = registry.addStoryDependency(map[string]interface{}{
+		"story_id":   "STRY0010001",
+		"blocked_by": "STRY0010001",
+	})
+	if !strings.Contains(result.Content[0].Text, "cannot be blocked by itself") {
+		t.Errorf("Expected self-dependency to be rejected, got %s", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+var (
+	// tableNamePattern matches ServiceNow table names (e.g., incident, u_custom_table, x_acme_app_item)
+	tableNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+	// fieldNamePattern matches field names, including dot-walked references (e.g., caller_id.email)
+	fieldNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+)
+
+// filterOperators maps the structured filter operators to ServiceNow encoded query operators
+var filterOperators = map[string]string{
+	"equals":           "=",
+	"not_equals":       "!=",
+	"greater_than":     ">",
+	"greater_or_equal": ">=",
+	"less_than":        "<",
+	"less_or_equal":    "<=",
+	"contains":         "LIKE",
+	"not_contains":     "NOT LIKE",
+	"starts_with":      "STARTSWITH",
+	"ends_with":        "ENDSWITH",
+	"in":               "IN",
+	"not_in":           "NOT IN",
+	"is_empty":         "ISEMPTY",
+	"is_not_empty":     "ISNOTEMPTY",
+}
+
+// filterOperatorNames lists the structured filter operators in schema order
+var filterOperatorNames = []string{
+	"equals", "not_equals", "greater_than", "greater_or_equal", "less_than", "less_or_equal",
+	"contains", "not_contains", "starts_with", "ends_with", "in", "not_in", "is_empty", "is_not_empty",
+}
+
+// registerTableTools registers the generic table query tool
+func (r *Registry) registerTableTools(server *mcp.Server) int {
+	count := 0
+
+	// Helper for limit/offset constraints
+	limitMin := float64(1)
+	limitMax := float64(1000)
+	offsetMin := float64(0)
+
+	// Query Table
+	r.registerTool(server, mcp.Tool{
+		Name:        "query_table",
+		Description: "Query any ServiceNow table or database view not covered by a dedicated tool. Filter with structured 'filters' (preferred) or a raw encoded 'query'; both are ANDed when given.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: withArchived(withAutoPaginate(map[string]mcp.Property{
+				"table": {
+					Type:        "string",
+					Description: "Table or database view name (e.g., 'cmdb_ci_server', 'sys_user_role', 'u_custom_table', 'incident_sla')",
+				},
+				"filters": {
+					Type:        "array",
+					Description: "Conditions ANDed together, compiled into an encoded query (e.g., [{\"field\": \"active\", \"operator\": \"equals\", \"value\": \"true\"}])",
+					Items: &mcp.Property{
+						Type: "object",
+						Properties: map[string]mcp.Property{
+							"field": {
+								Type:        "string",
+								Description: "Field name; dot-walking is allowed (e.g., 'assigned_to.email')",
+							},
+							"operator": {
+								Type:        "string",
+								Description: "Comparison operator",
+								Enum:        filterOperatorNames,
+							},
+							"value": {
+								Type:        "string",
+								Description: "Value to compare; comma-separated for 'in'/'not_in', omitted for 'is_empty'/'is_not_empty'",
+							},
+						},
+					},
+				},
+				"query": {
+					Type:        "string",
+					Description: "Raw encoded query (e.g., 'active=true^priority=1')",
+				},
+				"fields": {
+					Type:        "array",
+					Description: "Fields to return (default: all fields)",
+					Items:       &mcp.Property{Type: "string"},
+				},
+				"order_by": {
+					Type:        "string",
+					Description: "Field to sort by (e.g., 'sys_created_on')",
+				},
+				"order_direction": {
+					Type:        "string",
+					Description: "Sort direction",
+					Default:     "desc",
+					Enum:        []string{"asc", "desc"},
+				},
+				"display_value": {
+					Type:        "string",
+					Description: "Return raw values ('false'), display values ('true'), or both ('all')",
+					Default:     "true",
+					Enum:        []string{"true", "false", "all"},
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Max results",
+					Default:     20,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
+			})),
+			Required: []string{"table"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Query Table",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).queryTable)
+	count++
+
+	return count
+}
+
+func (r *Registry) queryTable(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	table, params, errResp := tableQueryParams(args)
+	if errResp != nil {
+		return JSONResult(errResp), nil
+	}
+	params["sysparm_limit"] = fmt.Sprintf("%d", GetIntArg(args, "limit", 20))
+	params["sysparm_offset"] = fmt.Sprintf("%d", GetIntArg(args, "offset", 0))
+	if errResp := archiveArgsError(args); errResp != nil {
+		return JSONResult(errResp), nil
+	}
+
+	result, page, warning, err := r.listRecordsWithArchive(table, params, args)
+	if err != nil {
+		return JSONResult(NewErrorResponse(fmt.Sprintf("Failed to query table %s", table), err)), nil
+	}
+
+	records := GetResultList(result)
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Found %d records in %s", len(records), table),
+		"table":   table,
+		"query":   params["sysparm_query"],
+		"records": records,
+	}
+	if warning != "" {
+		withWarnings(response, warning)
+	}
+	return JSONResult(withPaging(response, page)), nil
+}
+
+// tableQueryParams validates the table, filters, query, order_by, fields, and
+// display_value arguments shared by query_table and table exports, and returns the
+// table name with the Table API parameters (without limit and offset)
+func tableQueryParams(args map[string]interface{}) (string, map[string]string, *ErrorResponse) {
+	table := GetStringArg(args, "table", "")
+	if table == "" {
+		return "", nil, NewErrorResponse("table is required", nil)
+	}
+	if !tableNamePattern.MatchString(table) {
+		return "", nil, NewErrorResponse(fmt.Sprintf("Invalid table name: %s", table), nil)
+	}
+
+	var terms []string
+	if filters, ok := args["filters"].([]interface{}); ok && len(filters) > 0 {
+		compiled, err := BuildEncodedQuery(filters)
+		if err != nil {
+			return "", nil, NewValidationErrorResponse("Invalid filters", err)
+		}
+		terms = append(terms, compiled)
+	}
+	if query := GetStringArg(args, "query", ""); query != "" {
+		terms = append(terms, query)
+	}
+
+	if orderBy := GetStringArg(args, "order_by", ""); orderBy != "" {
+		if !fieldNamePattern.MatchString(orderBy) {
+			return "", nil, NewErrorResponse(fmt.Sprintf("Invalid order_by field: %s", orderBy), nil)
+		}
+		if GetStringArg(args, "order_direction", "desc") == "asc" {
+			terms = append(terms, "ORDERBY"+orderBy)
+		} else {
+			terms = append(terms, "ORDERBYDESC"+orderBy)
+		}
+	}
+
+	params := map[string]string{
+		"sysparm_display_value":          GetStringArg(args, "display_value", "true"),
+		"sysparm_exclude_reference_link": "true",
+	}
+	if len(terms) > 0 {
+		params["sysparm_query"] = strings.Join(terms, "^")
+	}
+	if fields := GetStringArrayArg(args, "fields"); len(fields) > 0 {
+		for _, field := range fields {
+			if !fieldNamePattern.MatchString(field) {
+				return "", nil, NewErrorResponse(fmt.Sprintf("Invalid field name: %s", field), nil)
+			}
+		}
+		params["sysparm_fields"] = strings.Join(fields, ",")
+	}
+	return table, params, nil
+}
+
+// BuildEncodedQuery compiles structured filters ({field, operator, value} objects) into
+// an encoded query with the conditions ANDed. Values are sanitized so they cannot
+// inject additional query terms.
+func BuildEncodedQuery(filters []interface{}) (string, error) {
+	terms := make([]string, 0, len(filters))
+	for i, item := range filters {
+		filter, ok := item.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("filter %d must be an object with field, operator, and value", i+1)
+		}
+
+		field := GetStringArg(filter, "field", "")
+		if !fieldNamePattern.MatchString(field) {
+			return "", fmt.Errorf("filter %d: invalid field name %q", i+1, field)
+		}
+
+		name := GetStringArg(filter, "operator", "equals")
+		operator, ok := filterOperators[name]
+		if !ok {
+			return "", fmt.Errorf("filter %d: unknown operator %q (use one of %s)", i+1, name, strings.Join(filterOperatorNames, ", "))
+		}
+
+		if name == "is_empty" || name == "is_not_empty" {
+			terms = append(terms, field+operator)
+			continue
+		}
+
+		var value string
+		switch v := filter["value"].(type) {
+		case string:
+			value = v
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, part := range v {
+				parts = append(parts, fmt.Sprint(part))
+			}
+			value = strings.Join(parts, ",")
+		case nil:
+			return "", fmt.Errorf("filter %d: value is required for operator %q", i+1, name)
+		default:
+			value = fmt.Sprint(v)
+		}
+
+		terms = append(terms, field+operator+SanitizeQueryValue(value))
+	}
+	return strings.Join(terms, "^"), nil
+}
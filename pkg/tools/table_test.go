@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestBuildEncodedQuery tests that structured filters compile into encoded query syntax
+func TestBuildEncodedQuery(t *testing.T) {
+	query, err := BuildEncodedQuery([]interface{}{
+		map[string]interface{}{"field": "active", "operator": "equals", "value": "true"},
+		map[string]interface{}{"field": "assigned_to.email", "operator": "ends_with", "value": "@example.com"},
+		map[string]interface{}{"field": "priority", "operator": "in", "value": []interface{}{"1", float64(2)}},
+		map[string]interface{}{"field": "short_description", "operator": "contains", "value": "vpn^NQactive=false"},
+		map[string]interface{}{"field": "resolved_at", "operator": "is_empty"},
+	})
+	if err != nil {
+		t.Fatalf("Expected filters to compile, got %v", err)
+	}
+	want := "active=true^assigned_to.emailENDSWITH@example.com^priorityIN1,2^short_descriptionLIKEvpnNQactive=false^resolved_atISEMPTY"
+	if query != want {
+		t.Errorf("Expected %q, got %q", want, query)
+	}
+
+	for _, bad := range []interface{}{
+		"active=true",
+		map[string]interface{}{"field": "active^ORDERBYname", "operator": "equals", "value": "true"},
+		map[string]interface{}{"field": "active", "operator": "matches", "value": "true"},
+		map[string]interface{}{"field": "active", "operator": "equals"},
+	} {
+		if _, err := BuildEncodedQuery([]interface{}{bad}); err == nil {
+			t.Errorf("Expected error for filter %v", bad)
+		}
+	}
+}
+
+// TestQueryTable tests that query_table combines filters, raw query, and ordering
+func TestQueryTable(t *testing.T) {
+	var params map[string][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/now/table/cmdb_ci_server" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		params = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": []interface{}{map[string]interface{}{"name": "web01"}}})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+	result, _ := registry.queryTable(map[string]interface{}{
+		"table":           "cmdb_ci_server",
+		"filters":         []interface{}{map[string]interface{}{"field": "os", "operator": "starts_with", "value": "Linux"}},
+		"query":           "install_status=1",
+		"fields":          []interface{}{"name", "os"},
+		"order_by":        "name",
+		"order_direction": "asc",
+	})
+	if result.IsError || !strings.Contains(result.Content[0].Text, `"name": "web01"`) {
+		t.Fatalf("Expected records, got %+v", result)
+	}
+	if got := params["sysparm_query"][0]; got != "osSTARTSWITHLinux^install_status=1^ORDERBYname" {
+		t.Errorf("Unexpected sysparm_query %q", got)
+	}
+	if got := params["sysparm_fields"][0]; got != "name,os" {
+		t.Errorf("Unexpected sysparm_fields %q", got)
+	}
+
+	result, _ = registry.queryTable(map[string]interface{}{"table": "incident/../sys_user"})
+	if !strings.Contains(result.Content[0].Text, "Invalid table name") {
+		t.Errorf("Expected invalid table name error, got %s", result.Content[0].Text)
+	}
+}
+
+// TestQueryTableAutoPaginate tests that auto_paginate follows pages up to max_records
+func TestQueryTableAutoPaginate(t *testing.T) {
+	const total = 25
+
+	var offsets []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/now/table/cmdb_ci_server" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("sysparm_limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+		offsets = append(offsets, r.URL.Query().Get("sysparm_offset"))
+
+		records := []map[string]interface{}{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			records = append(records, map[string]interface{}{"sys_id": strconv.Itoa(i)})
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.queryTable(map[string]interface{}{"table": "cmdb_ci_server", "limit": 10, "auto_paginate": true, "max_records": 15})
+	var response struct {
+		Records []map[string]interface{} `json:"records"`
+		Paging  map[string]interface{}   `json:"paging"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(response.Records) != 15 || strings.Join(offsets, ",") != "0,10" {
+		t.Errorf("Expected 15 records from offsets 0,10, got %d from %v", len(response.Records), offsets)
+	}
+	if response.Paging["total_count"] != float64(total) || response.Paging["next_offset"] != float64(15) || response.Paging["complete"] != false {
+		t.Errorf("Unexpected paging summary: %+v", response.Paging)
+	}
+
+	offsets = nil
+	result, _ = registry.queryTable(map[string]interface{}{"table": "cmdb_ci_server", "limit": 10})
+	if strings.Contains(result.Content[0].Text, `"paging"`) || strings.Join(offsets, ",") != "0" {
+		t.Errorf("Expected a single page without paging summary, got %v: %s", offsets, result.Content[0].Text)
+	}
+}
+
+// TestQueryTablePagingMetadata tests that list results report total_count, limit, offset, and has_more
+func TestQueryTablePagingMetadata(t *testing.T) {
+	withTotal := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/now/table/cmdb_ci_server" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		records := []map[string]interface{}{}
+		for i := 0; i < 10; i++ {
+			records = append(records, map[string]interface{}{"sys_id": strconv.Itoa(i)})
+		}
+		if withTotal {
+			w.Header().Set("X-Total-Count", "25")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	tests := []struct {
+		name      string
+		withTotal bool
+		offset    int
+		total     float64
+		hasMore   bool
+	}{
+		{"middle page", true, 10, 25, true},
+		{"last page", true, 20, 25, false},
+		{"total not reported", false, 10, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withTotal = tt.withTotal
+			result, _ := registry.queryTable(map[string]interface{}{"table": "cmdb_ci_server", "limit": 10, "offset": tt.offset})
+			var response map[string]interface{}
+			if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+				t.Fatalf("Failed to parse result: %v", err)
+			}
+			if response["total_count"] != tt.total || response["limit"] != float64(10) ||
+				response["offset"] != float64(tt.offset) || response["has_more"] != tt.hasMore {
+				t.Errorf("Unexpected paging fields: total_count=%v limit=%v offset=%v has_more=%v",
+					response["total_count"], response["limit"], response["offset"], response["has_more"])
+			}
+		})
+	}
+}
+
+// TestQueryTableIncludeArchived tests that include_archived pages through the live records and then the archive table, and that a missing archive is reported rather than failing the query
+func TestQueryTableIncludeArchived(t *testing.T) {
+	archiveExists := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var prefix string
+		var total int
+		switch {
+		case r.URL.Path == "/api/now/table/incident":
+			prefix, total = "live", 3
+		case r.URL.Path == "/api/now/table/ar_incident" && archiveExists:
+			prefix, total = "archived", 4
+		case r.URL.Path == "/api/now/table/ar_incident":
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"message": "Invalid table ar_incident"}})
+			return
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("sysparm_offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("sysparm_limit"))
+		records := []map[string]interface{}{}
+		for i := offset; i < total && i < offset+limit; i++ {
+			records = append(records, map[string]interface{}{"sys_id": prefix + strconv.Itoa(i)})
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	tests := []struct {
+		name    string
+		offset  int
+		limit   int
+		want    string
+		total   float64
+		hasMore bool
+	}{
+		{"live records only", 0, 2, "live0,live1", 7, true},
+		{"live then archived", 2, 3, "live2,archived0,archived1", 7, true},
+		{"archived only", 5, 3, "archived2,archived3", 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := registry.queryTable(map[string]interface{}{"table": "incident", "include_archived": true, "limit": tt.limit, "offset": tt.offset})
+			var response struct {
+				Records    []map[string]interface{} `json:"records"`
+				TotalCount float64                  `json:"total_count"`
+				HasMore    bool                     `json:"has_more"`
+			}
+			if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+				t.Fatalf("Failed to parse result: %v", err)
+			}
+			var got []string
+			for _, record := range response.Records {
+				if archived := record["archived"] == true; archived != strings.HasPrefix(record["sys_id"].(string), "archived") {
+					t.Errorf("Record %v has the wrong archived mark", record["sys_id"])
+				}
+				got = append(got, record["sys_id"].(string))
+			}
+			if strings.Join(got, ",") != tt.want || response.TotalCount != tt.total || response.HasMore != tt.hasMore {
+				t.Errorf("Expected %s (total %v, has_more %v), got %s", tt.want, tt.total, tt.hasMore, result.Content[0].Text)
+			}
+		})
+	}
+
+	archiveExists = false
+	result, _ := registry.queryTable(map[string]interface{}{"table": "incident", "include_archived": true, "limit": 5})
+	if !strings.Contains(result.Content[0].Text, `"warnings"`) || !strings.Contains(result.Content[0].Text, `"total_count": 3`) {
+		t.Errorf("Expected the live records with an archive warning, got %s", result.Content[0].Text)
+	}
+
+	result, _ = registry.queryTable(map[string]interface{}{"table": "incident", "include_archived": true, "auto_paginate": true})
+	if !strings.Contains(result.Content[0].Text, "can't be combined with auto_paginate") {
+		t.Errorf("Expected include_archived with auto_paginate to be refused, got %s", result.Content[0].Text)
+	}
+}
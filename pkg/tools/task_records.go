@@ -0,0 +1,63 @@
+package tools
+
+// taskRecord is a record collected in a taskRecordSet, with the table it was read from
+// and its class (sys_class_name), e.g., a record read from task of class incident
+type taskRecord struct {
+	table  string
+	class  string
+	label  string
+	fields map[string]interface{}
+}
+
+// taskRecordSet collects the records of views read from several task tables, such as
+// the base task table and tables extending it, keeping one record per sys_id. A record
+// read from its own class's table replaces the same record read from a base table, whose
+// shape lacks the class's fields. Records are kept in the order first added.
+type taskRecordSet struct {
+	records []taskRecord
+	index   map[string]int
+}
+
+// add adds a record read from table, reporting whether it was new. Records without a
+// sys_id are always added. The class comes from the record's sys_class_name (read with
+// sysparm_display_value=all for its label), or is table when the field was not read.
+func (s *taskRecordSet) add(table string, record map[string]interface{}) bool {
+	class := FieldValue(record["sys_class_name"])
+	if class == "" {
+		class = table
+	}
+	label := FieldDisplay(record["sys_class_name"])
+	if label == "" {
+		label = class
+	}
+	added := taskRecord{table: table, class: class, label: label, fields: record}
+
+	sysID := FieldValue(record["sys_id"])
+	if s.index == nil {
+		s.index = map[string]int{}
+	}
+	if i, ok := s.index[sysID]; ok && sysID != "" {
+		if existing := s.records[i]; existing.table != existing.class && table == class {
+			s.records[i] = added
+		}
+		return false
+	}
+	if sysID != "" {
+		s.index[sysID] = len(s.records)
+	}
+	s.records = append(s.records, added)
+	return true
+}
+
+// byClass groups the records by class, classes in the order first seen
+func (s *taskRecordSet) byClass() ([]string, map[string][]taskRecord) {
+	var classes []string
+	groups := map[string][]taskRecord{}
+	for _, record := range s.records {
+		if _, ok := groups[record.class]; !ok {
+			classes = append(classes, record.class)
+		}
+		groups[record.class] = append(groups[record.class], record)
+	}
+	return classes, groups
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// noToolTimeoutKey marks a context whose tool calls run without the per-tool timeout
+// (background jobs, which exist to outlast it)
+type noToolTimeoutKey struct{}
+
+// withoutToolTimeout returns a context whose tool calls are not limited by the per-tool timeout
+func withoutToolTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noToolTimeoutKey{}, true)
+}
+
+// SetToolTimeouts limits how long a tool call may run before its ServiceNow requests are
+// cancelled. defaultTimeout applies to every tool (0 means no limit); overrides are
+// "tool=duration" entries (e.g., "query_table=2m") for individual tools.
+func (r *Registry) SetToolTimeouts(defaultTimeout time.Duration, overrides []string) error {
+	if defaultTimeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", defaultTimeout)
+	}
+	perTool := map[string]time.Duration{}
+	for _, entry := range overrides {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid tool timeout %q (use tool=duration, e.g., query_table=2m)", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("invalid duration for %s: %q", strings.TrimSpace(name), value)
+		}
+		perTool[strings.TrimSpace(name)] = timeout
+	}
+	r.toolTimeout, r.toolTimeouts = defaultTimeout, perTool
+	return nil
+}
+
+// timeoutFor returns the time limit for a call of the named tool (0 means no limit)
+func (r *Registry) timeoutFor(ctx context.Context, name string) time.Duration {
+	if ctx.Value(noToolTimeoutKey{}) != nil {
+		return 0
+	}
+	if timeout, ok := r.toolTimeouts[name]; ok {
+		return timeout
+	}
+	return r.toolTimeout
+}
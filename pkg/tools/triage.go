@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// maxTriageRelatedCIs bounds the CIs related to the incident's CI that recent changes are searched on
+const maxTriageRelatedCIs = 50
+
+// registerTriageTools registers the incident triage tools
+func (r *Registry) registerTriageTools(server *mcp.Server) int {
+	count := 0
+
+	limitMin := float64(1)
+	limitMax := float64(20)
+	daysMin := float64(1)
+	daysMax := float64(90)
+
+	// Triage Context (read-only)
+	r.registerTool(server, mcp.Tool{
+		Name:        "triage_context",
+		Description: "Gather the triage picture for an incident in one call: similar open incidents, candidate known-error problems, matching published knowledge articles, and recent changes on the incident's CI and the CIs related to it. Matches use the incident's short description (keyword search) and configuration item.",
+		InputSchema: mcp.JSONSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"incident_id": {
+					Type:        "string",
+					Description: "Incident number (e.g., 'INC0010001') or sys_id",
+				},
+				"limit": {
+					Type:        "integer",
+					Description: "Maximum records per section",
+					Default:     5,
+					Minimum:     &limitMin,
+					Maximum:     &limitMax,
+				},
+				"change_days": {
+					Type:        "integer",
+					Description: "How many days back to look for changes on the related CIs",
+					Default:     14,
+					Minimum:     &daysMin,
+					Maximum:     &daysMax,
+				},
+			},
+			Required: []string{"incident_id"},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Triage Context",
+			ReadOnlyHint: true,
+		},
+	}, (*Registry).triageContext)
+	count++
+
+	return count
+}
+
+func (r *Registry) triageContext(args map[string]interface{}) (*mcp.CallToolResult, error) {
+	incidentID := GetStringArg(args, "incident_id", "")
+	if incidentID == "" {
+		return JSONResult(NewErrorResponse("incident_id is required", nil)), nil
+	}
+	limit := GetIntArg(args, "limit", 5)
+	changeDays := GetIntArg(args, "change_days", 14)
+
+	idField := "number"
+	if IsSysID(incidentID) {
+		idField = "sys_id"
+	}
+	result, err := r.client.Get("/table/incident", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("%s=%s", idField, SanitizeQueryValue(incidentID)),
+		"sysparm_fields":                 "sys_id,number,short_description,state,priority,category,subcategory,cmdb_ci,problem_id",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to get incident", err)), nil
+	}
+	records := GetResultList(result)
+	if len(records) == 0 {
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Incident not found: %s", incidentID))), nil
+	}
+	incident := records[0]
+	sysID := FieldValue(incident["sys_id"])
+	number := FieldDisplay(incident["number"])
+	text := strings.TrimSpace(SanitizeQueryValue(FieldValue(incident["short_description"])))
+	ciID := FieldValue(incident["cmdb_ci"])
+
+	// Sections failing to load are reported under errors, so the others are still returned
+	sectionErrors := map[string]string{}
+	search := func(section, table, fields string, alternatives []string, order string) []map[string]interface{} {
+		if len(alternatives) == 0 {
+			return []map[string]interface{}{}
+		}
+		query := strings.Join(alternatives, "^NQ")
+		if order != "" {
+			query += "^" + order
+		}
+		result, err := r.client.Get(fmt.Sprintf("/table/%s", table), map[string]string{
+			"sysparm_query":                  query,
+			"sysparm_fields":                 fields,
+			"sysparm_display_value":          "true",
+			"sysparm_exclude_reference_link": "true",
+			"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		})
+		if err != nil {
+			sectionErrors[section] = err.Error()
+			return []map[string]interface{}{}
+		}
+		return GetResultList(result)
+	}
+
+	// The incident's CI and the CIs one relationship away from it
+	relatedCIs := []string{}
+	if ciID != "" {
+		relatedCIs = append(relatedCIs, ciID)
+		result, err := r.client.Get("/table/cmdb_rel_ci", map[string]string{
+			"sysparm_query":  fmt.Sprintf("parent=%[1]s^ORchild=%[1]s", ciID),
+			"sysparm_fields": "parent,child",
+			"sysparm_limit":  fmt.Sprintf("%d", maxTriageRelatedCIs),
+		})
+		if err != nil {
+			sectionErrors["related_cis"] = err.Error()
+		}
+		seen := map[string]bool{ciID: true}
+		for _, rel := range GetResultList(result) {
+			for _, id := range []string{FieldValue(rel["parent"]), FieldValue(rel["child"])} {
+				if IsSysID(id) && !seen[id] {
+					seen[id] = true
+					relatedCIs = append(relatedCIs, id)
+				}
+			}
+		}
+	}
+	ciList := strings.Join(relatedCIs, ",")
+
+	// Similar open incidents: same keywords or same CI
+	var similar []string
+	if text != "" {
+		similar = append(similar, fmt.Sprintf("active=true^sys_id!=%s^123TEXTQUERY321=%s", sysID, text))
+	}
+	if ciID != "" {
+		similar = append(similar, fmt.Sprintf("active=true^sys_id!=%s^cmdb_ci=%s", sysID, ciID))
+	}
+	similarIncidents := search("similar_incidents", "incident",
+		"sys_id,number,short_description,state,priority,cmdb_ci,assignment_group,problem_id,sys_updated_on", similar, "")
+	r.labelStates("incident", similarIncidents...)
+
+	// Known errors: the incident's problem, and known-error problems with the same keywords or on a related CI
+	var knownErrors []string
+	if problemID := FieldValue(incident["problem_id"]); problemID != "" {
+		knownErrors = append(knownErrors, fmt.Sprintf("sys_id=%s", problemID))
+	}
+	if text != "" {
+		knownErrors = append(knownErrors, fmt.Sprintf("known_error=true^123TEXTQUERY321=%s", text))
+	}
+	if ciList != "" {
+		knownErrors = append(knownErrors, fmt.Sprintf("known_error=true^cmdb_ciIN%s", ciList))
+	}
+	problems := search("known_error_problems", "problem",
+		"sys_id,number,short_description,state,known_error,cmdb_ci,workaround,fix_notes", knownErrors, "")
+	r.labelStates("problem", problems...)
+
+	// Published knowledge articles with the same keywords
+	var articleQuery []string
+	if text != "" {
+		articleQuery = append(articleQuery, fmt.Sprintf("workflow_state=published^123TEXTQUERY321=%s", text))
+	}
+	articles := search("kb_articles", "kb_knowledge", "sys_id,number,short_description,kb_knowledge_base,sys_view_count", articleQuery, "")
+
+	// Changes on the related CIs that started or ended in the last change_days days
+	var changeQuery []string
+	if ciList != "" {
+		changeQuery = append(changeQuery, fmt.Sprintf("cmdb_ciIN%s^start_date>=javascript:gs.daysAgoStart(%[2]d)^ORend_date>=javascript:gs.daysAgoStart(%[2]d)", ciList, changeDays))
+	}
+	changes := search("recent_changes", "change_request",
+		"sys_id,number,short_description,type,state,cmdb_ci,start_date,end_date,close_code", changeQuery, "ORDERBYDESCstart_date")
+	r.labelStates("change_request", changes...)
+
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Triage context for %s: %d similar incidents, %d known-error problems, %d knowledge articles, %d recent changes",
+			number, len(similarIncidents), len(problems), len(articles), len(changes)),
+		"incident": map[string]interface{}{
+			"sys_id":            sysID,
+			"number":            number,
+			"short_description": FieldDisplay(incident["short_description"]),
+			"priority":          FieldDisplay(incident["priority"]),
+			"category":          FieldDisplay(incident["category"]),
+			"cmdb_ci":           FieldDisplay(incident["cmdb_ci"]),
+		},
+		"related_ci_count":     len(relatedCIs),
+		"similar_incidents":    similarIncidents,
+		"known_error_problems": problems,
+		"kb_articles":          articles,
+		"recent_changes":       changes,
+	}
+	if len(sectionErrors) > 0 {
+		response["errors"] = sectionErrors
+	}
+	return JSONResult(response), nil
+}
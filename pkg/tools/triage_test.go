@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestTriageContext tests that triage_context searches each section by keywords and related CIs
+func TestTriageContext(t *testing.T) {
+	const (
+		incidentID = "1c741bd70b2322007518478d83673af3"
+		ciID       = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		relatedID  = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	queries := map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		var records []interface{}
+		switch r.URL.Path {
+		case "/api/now/table/incident":
+			if query == "number=INC0010001" {
+				records = []interface{}{map[string]interface{}{
+					"sys_id":            map[string]interface{}{"value": incidentID, "display_value": incidentID},
+					"number":            map[string]interface{}{"value": "INC0010001", "display_value": "INC0010001"},
+					"short_description": map[string]interface{}{"value": "VPN drops every hour", "display_value": "VPN drops every hour"},
+					"cmdb_ci":           map[string]interface{}{"value": ciID, "display_value": "vpn-gw-01"},
+					"problem_id":        map[string]interface{}{"value": "", "display_value": ""},
+				}}
+			} else {
+				queries["incident"] = query
+				records = []interface{}{map[string]interface{}{"number": "INC0010002", "state": "In Progress"}}
+			}
+		case "/api/now/table/cmdb_rel_ci":
+			records = []interface{}{map[string]interface{}{"parent": ciID, "child": relatedID}}
+		case "/api/now/table/problem", "/api/now/table/kb_knowledge", "/api/now/table/change_request":
+			queries[strings.TrimPrefix(r.URL.Path, "/api/now/table/")] = query
+			records = []interface{}{}
+		case "/api/now/table/sys_choice":
+			records = []interface{}{}
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": records})
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, true)
+
+	result, _ := registry.triageContext(map[string]interface{}{"incident_id": "INC0010001", "change_days": 7})
+	if result.IsError || !strings.Contains(result.Content[0].Text, "1 similar incidents, 0 known-error problems, 0 knowledge articles, 0 recent changes") {
+		t.Fatalf("Unexpected result: %s", result.Content[0].Text)
+	}
+
+	want := map[string]string{
+		"incident":       "active=true^sys_id!=" + incidentID + "^123TEXTQUERY321=VPN drops every hour^NQactive=true^sys_id!=" + incidentID + "^cmdb_ci=" + ciID,
+		"problem":        "known_error=true^123TEXTQUERY321=VPN drops every hour^NQknown_error=true^cmdb_ciIN" + ciID + "," + relatedID,
+		"kb_knowledge":   "workflow_state=published^123TEXTQUERY321=VPN drops every hour",
+		"change_request": "cmdb_ciIN" + ciID + "," + relatedID + "^start_date>=javascript:gs.daysAgoStart(7)^ORend_date>=javascript:gs.daysAgoStart(7)^ORDERBYDESCstart_date",
+	}
+	for table, query := range want {
+		if queries[table] != query {
+			t.Errorf("Expected %s query %q, got %q", table, query, queries[table])
+		}
+	}
+}
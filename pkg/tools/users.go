@@ -1,10 +1,13 @@
 package tools
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
 )
 
 // registerUserTools registers all user management tools
@@ -17,12 +20,12 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 	offsetMin := float64(0)
 
 	// List Users
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_users",
 		Description: "List users with optional filtering by active status, department, or search query.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of users to return (default: 50)",
@@ -46,49 +49,45 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 				},
 				"query": {
 					Type:        "string",
-					Description: "Search query (searches name, email, and username). For advanced filtering, use ServiceNow encoded query syntax (^ for AND, | for OR)",
+					Description: "Text search in name, email, and username (e.g., 'smith')",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Users",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listUsers(args)
-	})
+	}, (*Registry).listUsers)
 	count++
 
 	// Get User
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_user",
 		Description: "Get detailed information about a specific user including profile, department, and manager.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"user_id": {
 					Type:        "string",
 					Description: "User sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6'), username, or email. Accepts all three formats.",
 				},
-			},
+			}),
 			Required: []string{"user_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get User",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getUser(args)
-	})
+	}, (*Registry).getUser)
 	count++
 
 	// List Groups
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_groups",
 		Description: "List groups with optional filtering by active status or name search.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of groups to return (default: 50)",
@@ -96,6 +95,12 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"active": {
 					Type:        "boolean",
 					Description: "Filter by active status (true = only active groups, false = only inactive)",
@@ -104,21 +109,36 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Search query for group name",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Groups",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listGroups(args)
+	}, (*Registry).listGroups)
+	count++
+
+	// Who Am I
+	r.registerToolWithContext(server, mcp.Tool{
+		Name:        "whoami",
+		Description: "Identify the ServiceNow user this server acts as for the current call (user record, roles, and groups) and the MCP caller identity. Use it to resolve 'me' before assigning work to yourself or acting on your approvals.",
+		InputSchema: mcp.JSONSchema{
+			Type:       "object",
+			Properties: map[string]mcp.Property{},
+		},
+		Annotations: &mcp.ToolAnnotation{
+			Title:        "Who Am I",
+			ReadOnlyHint: true,
+		},
+	}, func(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		return r.forContext(ctx).whoami(ctx, args)
 	})
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create User
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_user",
 			Description: "Create a new user account. Returns the new user sys_id upon successful creation.",
 			InputSchema: mcp.JSONSchema{
@@ -158,13 +178,11 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create User",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createUser(args)
-		})
+		}, (*Registry).createUser)
 		count++
 
 		// Update User
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_user",
 			Description: "Update an existing user. At least one field besides user_id must be provided to make changes.",
 			InputSchema: mcp.JSONSchema{
@@ -208,13 +226,11 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update User",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateUser(args)
-		})
+		}, (*Registry).updateUser)
 		count++
 
 		// Create Group
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_group",
 			Description: "Create a new group. Groups are used for assignment and permissions.",
 			InputSchema: mcp.JSONSchema{
@@ -242,13 +258,11 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Group",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createGroup(args)
-		})
+		}, (*Registry).createGroup)
 		count++
 
 		// Update Group
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_group",
 			Description: "Update an existing group. At least one field besides group_id must be provided to make changes.",
 			InputSchema: mcp.JSONSchema{
@@ -280,13 +294,11 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Group",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateGroup(args)
-		})
+		}, (*Registry).updateGroup)
 		count++
 
 		// Add Group Members
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "add_group_members",
 			Description: "Add one or more users to a group.",
 			InputSchema: mcp.JSONSchema{
@@ -307,13 +319,11 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Add Group Members",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.addGroupMembers(args)
-		})
+		}, (*Registry).addGroupMembers)
 		count++
 
 		// Remove Group Members
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "remove_group_members",
 			Description: "Remove one or more users from a group.",
 			InputSchema: mcp.JSONSchema{
@@ -334,9 +344,7 @@ func (r *Registry) registerUserTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Remove Group Members",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.removeGroupMembers(args)
-		})
+		}, (*Registry).removeGroupMembers)
 		count++
 	}
 
@@ -352,30 +360,27 @@ func (r *Registry) listUsers(args map[string]interface{}) (*mcp.CallToolResult,
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
 		"sysparm_offset":                 fmt.Sprintf("%d", offset),
+		"sysparm_fields":                 readFields(args, "sys_id,user_name,first_name,last_name,email,title,department,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
-	if active, exists := args["active"]; exists {
-		if active.(bool) {
-			filters = append(filters, "active=true")
-		} else {
-			filters = append(filters, "active=false")
-		}
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
 	}
 	if department != "" {
-		filters = append(filters, fmt.Sprintf("department=%s", department))
+		filters = append(filters, fmt.Sprintf("department=%s", SanitizeQueryValue(department)))
 	}
 	if query != "" {
-		filters = append(filters, fmt.Sprintf("nameLIKE%s^ORemailLIKE%s^ORuser_nameLIKE%s", query, query, query))
+		filters = append(filters, LikeFilter(query, "name", "email", "user_name"))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/sys_user", params)
+	result, page, err := r.listRecords("/table/sys_user", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list users", err)), nil
 	}
@@ -384,7 +389,7 @@ func (r *Registry) listUsers(args map[string]interface{}) (*mcp.CallToolResult,
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				users = append(users, map[string]interface{}{
+				users = append(users, selectFields(map[string]interface{}{
 					"sys_id":     data["sys_id"],
 					"user_name":  data["user_name"],
 					"first_name": data["first_name"],
@@ -393,18 +398,21 @@ func (r *Registry) listUsers(args map[string]interface{}) (*mcp.CallToolResult,
 					"title":      data["title"],
 					"department": data["department"],
 					"active":     data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Found %d users", len(users)),
 		"users":   users,
-	}), nil
+	}, page)), nil
 }
 
+// userFields are the fields of the record returned by get_user
+const userFields = "sys_id,user_name,first_name,last_name,name,email,phone,mobile_phone,title,department,company,location,manager,active,locked_out,last_login_time,time_zone"
+
 func (r *Registry) getUser(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	userID := GetStringArg(args, "user_id", "")
 	if userID == "" {
@@ -417,14 +425,16 @@ func (r *Registry) getUser(args map[string]interface{}) (*mcp.CallToolResult, er
 	if IsSysID(userID) {
 		endpoint = fmt.Sprintf("/table/sys_user/%s", userID)
 		params = map[string]string{
+			"sysparm_fields":                 readFields(args, userFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
 	} else {
 		endpoint = "/table/sys_user"
 		params = map[string]string{
-			"sysparm_query":                  fmt.Sprintf("user_name=%s^ORemail=%s", userID, userID),
+			"sysparm_query":                  fmt.Sprintf("user_name=%s^ORemail=%s", SanitizeQueryValue(userID), SanitizeQueryValue(userID)),
 			"sysparm_limit":                  "1",
+			"sysparm_fields":                 readFields(args, userFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
@@ -445,16 +455,13 @@ func (r *Registry) getUser(args map[string]interface{}) (*mcp.CallToolResult, er
 	}
 
 	if userData == nil {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": fmt.Sprintf("User not found: %s", userID),
-		}), nil
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("User not found: %s", userID))), nil
 	}
 
 	return JSONResult(map[string]interface{}{
 		"success": true,
 		"message": "User found",
-		"user":    userData,
+		"user":    selectFields(userData, userData, args),
 	}), nil
 }
 
@@ -464,27 +471,25 @@ func (r *Registry) listGroups(args map[string]interface{}) (*mcp.CallToolResult,
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,name,description,manager,email,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
-	if active, exists := args["active"]; exists {
-		if active.(bool) {
-			filters = append(filters, "active=true")
-		} else {
-			filters = append(filters, "active=false")
-		}
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
 	}
 	if query != "" {
-		filters = append(filters, fmt.Sprintf("nameLIKE%s", query))
+		filters = append(filters, LikeFilter(query, "name"))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/sys_user_group", params)
+	result, page, err := r.listRecords("/table/sys_user_group", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list groups", err)), nil
 	}
@@ -493,23 +498,109 @@ func (r *Registry) listGroups(args map[string]interface{}) (*mcp.CallToolResult,
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				groups = append(groups, map[string]interface{}{
+				groups = append(groups, selectFields(map[string]interface{}{
 					"sys_id":      data["sys_id"],
 					"name":        data["name"],
 					"description": data["description"],
 					"manager":     data["manager"],
 					"email":       data["email"],
 					"active":      data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Found %d groups", len(groups)),
 		"groups":  groups,
-	}), nil
+	}, page)), nil
+}
+
+func (r *Registry) whoami(ctx context.Context, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	// The session user of the call's credentials, whichever auth type they use
+	result, err := r.client.Get("/table/sys_user", map[string]string{
+		"sysparm_query":                  "sys_id=javascript:gs.getUserID()",
+		"sysparm_fields":                 userFields,
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "1",
+	})
+	if err != nil {
+		return JSONResult(NewErrorResponse("Failed to identify the ServiceNow user", err)), nil
+	}
+	users := GetResultList(result)
+	if len(users) == 0 {
+		return JSONResult(NewErrorResponse("The authenticated user has no readable sys_user record", nil)), nil
+	}
+	user := users[0]
+	userID := FieldValue(user["sys_id"])
+
+	// Roles and groups failing to load are reported under warnings, so the user is still returned
+	var warnings []string
+	roles := []string{}
+	result, err = r.client.Get("/table/sys_user_has_role", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("user=%s^state=active^ORDERBYrole.name", userID),
+		"sysparm_fields":                 "role",
+		"sysparm_display_value":          "true",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "500",
+	})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Roles not read: %v", err))
+	}
+	seen := map[string]bool{}
+	for _, record := range GetResultList(result) {
+		if role := FieldDisplay(record["role"]); role != "" && !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	groups := []map[string]interface{}{}
+	result, err = r.client.Get("/table/sys_user_grmember", map[string]string{
+		"sysparm_query":                  fmt.Sprintf("user=%s^ORDERBYgroup.name", userID),
+		"sysparm_fields":                 "group",
+		"sysparm_display_value":          "all",
+		"sysparm_exclude_reference_link": "true",
+		"sysparm_limit":                  "500",
+	})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Groups not read: %v", err))
+	}
+	for _, record := range GetResultList(result) {
+		groups = append(groups, map[string]interface{}{
+			"sys_id": FieldValue(record["group"]),
+			"name":   FieldDisplay(record["group"]),
+		})
+	}
+
+	// Who the call came from, and whose ServiceNow credentials it uses
+	caller := map[string]interface{}{"identity": mcp.CallerIdentity(ctx)}
+	if sessionID := mcp.SessionIDFromContext(ctx); sessionID != "" {
+		caller["session_id"] = sessionID
+	}
+	caller["credentials"] = "server"
+	if servicenow.CredentialsFromContext(ctx) != nil {
+		caller["credentials"] = "request headers"
+	}
+	if cfg := r.base.Config(); cfg != nil {
+		caller["auth_type"] = string(cfg.Auth.Type)
+	}
+	if impersonated := r.base.ImpersonatedUser(ctx); impersonated != "" {
+		caller["writes_as"] = impersonated
+	}
+
+	response := map[string]interface{}{
+		"success":    true,
+		"message":    fmt.Sprintf("Authenticated as %s (%s) with %d roles in %d groups", FieldDisplay(user["name"]), FieldDisplay(user["user_name"]), len(roles), len(groups)),
+		"user":       user,
+		"roles":      roles,
+		"groups":     groups,
+		"mcp_caller": caller,
+	}
+	withWarnings(response, warnings...)
+	return JSONResult(response), nil
 }
 
 func (r *Registry) createUser(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -702,34 +793,29 @@ func (r *Registry) addGroupMembers(args map[string]interface{}) (*mcp.CallToolRe
 		return JSONResult(NewErrorResponse("group_id and user_ids are required", nil)), nil
 	}
 
-	addedCount := 0
-	var lastErr error
-
+	requests := make([]servicenow.BatchRequest, 0, len(userIDs))
 	for _, userID := range userIDs {
-		data := map[string]interface{}{
-			"group": groupID,
-			"user":  userID,
-		}
-
-		_, err := r.client.Post("/table/sys_user_grmember", data)
-		if err != nil {
-			lastErr = err
-		} else {
-			addedCount++
-		}
+		requests = append(requests, servicenow.BatchRequest{
+			Method:   http.MethodPost,
+			Endpoint: "/table/sys_user_grmember",
+			Body:     map[string]interface{}{"group": groupID, "user": userID},
+		})
 	}
+	results, addedCount := r.runBatch(userIDs, requests)
 
 	if addedCount == len(userIDs) {
 		return JSONResult(map[string]interface{}{
 			"success": true,
 			"message": fmt.Sprintf("Successfully added %d members to group", addedCount),
+			"results": results,
 		}), nil
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withWarnings(map[string]interface{}{
 		"success": addedCount > 0,
-		"message": fmt.Sprintf("Added %d of %d members. Last error: %v", addedCount, len(userIDs), lastErr),
-	}), nil
+		"message": fmt.Sprintf("Added %d of %d members; see warnings for the failures", addedCount, len(userIDs)),
+		"results": results,
+	}, batchWarnings(results)...)), nil
 }
 
 func (r *Registry) removeGroupMembers(args map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -744,33 +830,37 @@ func (r *Registry) removeGroupMembers(args map[string]interface{}) (*mcp.CallToo
 		return JSONResult(NewErrorResponse("group_id and user_ids are required", nil)), nil
 	}
 
-	removedCount := 0
-	var lastErr error
-
-	for _, userID := range userIDs {
-		// Find the membership record
-		params := map[string]string{
-			"sysparm_query": fmt.Sprintf("group=%s^user=%s", groupID, userID),
-			"sysparm_limit": "1",
-		}
-
-		result, err := r.client.Get("/table/sys_user_grmember", params)
+	// Each removal is a lookup and a delete, so users are removed in parallel
+	results := make([]batchItemResult, len(userIDs))
+	forEachParallel(len(userIDs), func(i int) {
+		results[i].ID = userIDs[i]
+		result, err := r.client.Get("/table/sys_user_grmember", map[string]string{
+			"sysparm_query":  fmt.Sprintf("group=%s^user=%s", SanitizeQueryValue(groupID), SanitizeQueryValue(userIDs[i])),
+			"sysparm_fields": "sys_id",
+			"sysparm_limit":  "1",
+		})
 		if err != nil {
-			lastErr = err
-			continue
+			results[i].Error = err.Error()
+			return
+		}
+		records := GetResultList(result)
+		if len(records) == 0 {
+			results[i].Error = "not a member of the group"
+			return
 		}
+		memberID := FieldValue(records[0]["sys_id"])
+		if _, err := r.client.Delete(fmt.Sprintf("/table/sys_user_grmember/%s", memberID)); err != nil {
+			results[i].Error = err.Error()
+			return
+		}
+		results[i].SysID = memberID
+		results[i].Success = true
+	})
 
-		if resultList, ok := result["result"].([]interface{}); ok && len(resultList) > 0 {
-			if memberData, ok := resultList[0].(map[string]interface{}); ok {
-				if memberID, ok := memberData["sys_id"].(string); ok {
-					_, err := r.client.Delete(fmt.Sprintf("/table/sys_user_grmember/%s", memberID))
-					if err != nil {
-						lastErr = err
-					} else {
-						removedCount++
-					}
-				}
-			}
+	removedCount := 0
+	for _, result := range results {
+		if result.Success {
+			removedCount++
 		}
 	}
 
@@ -778,11 +868,13 @@ func (r *Registry) removeGroupMembers(args map[string]interface{}) (*mcp.CallToo
 		return JSONResult(map[string]interface{}{
 			"success": true,
 			"message": fmt.Sprintf("Successfully removed %d members from group", removedCount),
+			"results": results,
 		}), nil
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withWarnings(map[string]interface{}{
 		"success": removedCount > 0,
-		"message": fmt.Sprintf("Removed %d of %d members. Last error: %v", removedCount, len(userIDs), lastErr),
-	}), nil
+		"message": fmt.Sprintf("Removed %d of %d members; see warnings for the failures", removedCount, len(userIDs)),
+		"results": results,
+	}, batchWarnings(results)...)), nil
 }
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/servicenow"
+)
+
+// TestWhoami tests that whoami returns the session user with roles, groups, and the caller identity
+func TestWhoami(t *testing.T) {
+	const userID = "11111111111111111111111111111111"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("sysparm_query")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/now/table/sys_user":
+			if query != "sys_id=javascript:gs.getUserID()" {
+				t.Errorf("Expected the session user to be read, got %q", query)
+			}
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "` + userID + `", "user_name": "abel.tuter", "name": "Abel Tuter"}]}`))
+		case "/api/now/table/sys_user_has_role":
+			_, _ = w.Write([]byte(`{"result": [{"role": "itil"}, {"role": "itil"}, {"role": "approver_user"}]}`))
+		case "/api/now/table/sys_user_grmember":
+			if !strings.HasPrefix(query, "user="+userID) {
+				t.Errorf("Expected the user's groups to be read, got %q", query)
+			}
+			_, _ = w.Write([]byte(`{"result": [{"group": {"value": "g1", "display_value": "Service Desk"}}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	_, server := newTestRegistry(t, ts.URL, true)
+	whoami, _ := server.Handler("whoami")
+
+	ctx := servicenow.ContextWithCredentials(context.Background(), &servicenow.ContextCredentials{Username: "abel.tuter", Password: "secret"})
+	result, _ := whoami(ctx, map[string]interface{}{})
+	var response struct {
+		User      map[string]interface{}   `json:"user"`
+		Roles     []string                 `json:"roles"`
+		Groups    []map[string]interface{} `json:"groups"`
+		MCPCaller map[string]interface{}   `json:"mcp_caller"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if response.User["user_name"] != "abel.tuter" || len(response.Roles) != 2 || len(response.Groups) != 1 || response.Groups[0]["name"] != "Service Desk" {
+		t.Errorf("Expected the user with 2 roles and 1 group, got %+v", response)
+	}
+	if response.MCPCaller["identity"] != "user abel.tuter" || response.MCPCaller["credentials"] != "request headers" {
+		t.Errorf("Expected the header user as the caller, got %+v", response.MCPCaller)
+	}
+}
+
+// TestRemoveGroupMembersWarnings tests that each member not removed is reported as a warning naming the user and the reason
+func TestRemoveGroupMembersWarnings(t *testing.T) {
+	const groupID = "11111111111111111111111111111111"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query().Get("sysparm_query")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(query, "user=u1"):
+			_, _ = w.Write([]byte(`{"result": [{"sys_id": "m1"}]}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"result": []}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/now/table/sys_user_grmember/m1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	registry, _ := newTestRegistry(t, ts.URL, false)
+	result, _ := registry.removeGroupMembers(map[string]interface{}{"group_id": groupID, "user_ids": []interface{}{"u1", "u2", "u3"}})
+	var response struct {
+		Success  bool     `json:"success"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Warnings) != 2 || response.Warnings[0] != "u2: not a member of the group" || response.Warnings[1] != "u3: not a member of the group" {
+		t.Errorf("Expected a warning for each member not removed, got %s", result.Content[0].Text)
+	}
+}
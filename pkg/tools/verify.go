@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
+)
+
+// verifyArg is the argument that asks an update tool to read its records back
+const verifyArg = "verify"
+
+// unverifiableFields are journal fields, which read back as the whole journal rather than the entry written
+var unverifiableFields = map[string]bool{"work_notes": true, "comments": true}
+
+// withVerify adds the verify argument to update tools
+func withVerify(tool mcp.Tool) mcp.Tool {
+	if !strings.HasPrefix(tool.Name, "update_") {
+		return tool
+	}
+	properties := make(map[string]mcp.Property, len(tool.InputSchema.Properties)+1)
+	for name, prop := range tool.InputSchema.Properties {
+		properties[name] = prop
+	}
+	properties[verifyArg] = mcp.Property{
+		Type:        "boolean",
+		Description: "Read the record back after the update and report fields that were not applied, e.g., because a business rule rejected or overrode them (default: false)",
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// fieldMismatch is a requested field whose value after the update differs from the one sent
+type fieldMismatch struct {
+	Field     string `json:"field"`
+	Requested string `json:"requested"`
+	Actual    string `json:"actual"`
+}
+
+// recordVerification compares the fields sent to a record with the record as read back
+type recordVerification struct {
+	Table      string          `json:"table"`
+	SysID      string          `json:"sys_id"`
+	Number     string          `json:"number,omitempty"`
+	Applied    []string        `json:"applied"`
+	NotApplied []fieldMismatch `json:"not_applied"`
+	NotChecked []string        `json:"not_checked,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// verifyUpdates reads back the records updated during a verify=true call, adds a
+// "verification" section to the result, and warns about fields that were not applied
+func (r *Registry) verifyUpdates(ctx context.Context, call *ToolCall, result *mcp.CallToolResult, changes *changeRecorder) {
+	// Merge repeated updates of a record, keeping the last value sent for each field
+	var updates []recordChange
+	index := map[string]int{}
+	changes.mu.Lock()
+	for _, change := range changes.changes {
+		if change.Action != changeUpdated || change.SysID == "" || len(change.fields) == 0 {
+			continue
+		}
+		key := change.Table + "/" + change.SysID
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(updates)
+			updates = append(updates, recordChange{Table: change.Table, SysID: change.SysID, Number: change.Number, fields: map[string]interface{}{}})
+			i = len(updates) - 1
+		}
+		for name, value := range change.fields {
+			updates[i].fields[name] = value
+		}
+		if change.Number != "" {
+			updates[i].Number = change.Number
+		}
+	}
+	changes.mu.Unlock()
+
+	verification := map[string]interface{}{}
+	records := make([]recordVerification, 0, len(updates))
+	client := r.forContext(ctx).client
+	for _, update := range updates {
+		records = append(records, verifyRecord(client, update))
+	}
+	verification["records"] = records
+	if len(records) == 0 {
+		verification["message"] = "No record updates to verify"
+	}
+
+	for _, record := range records {
+		name := record.Number
+		if name == "" {
+			name = record.Table + " " + record.SysID
+		}
+		if record.Error != "" {
+			call.Warnings = append(call.Warnings, fmt.Sprintf("Warning: %s could not be read back to verify the update: %s", name, record.Error))
+			continue
+		}
+		if len(record.NotApplied) == 0 {
+			continue
+		}
+		fields := make([]string, 0, len(record.NotApplied))
+		for _, mismatch := range record.NotApplied {
+			fields = append(fields, fmt.Sprintf("%s (sent %q, is %q)", mismatch.Field, mismatch.Requested, mismatch.Actual))
+		}
+		call.Warnings = append(call.Warnings, fmt.Sprintf("Warning: %s was saved, but %d requested field(s) were not applied, likely rejected or overridden by a business rule: %s",
+			name, len(fields), strings.Join(fields, ", ")))
+	}
+
+	if len(result.Content) == 0 {
+		return
+	}
+	var body map[string]interface{}
+	if json.Unmarshal([]byte(result.Content[0].Text), &body) != nil {
+		return
+	}
+	body["verification"] = verification
+	result.Content[0] = JSONResult(body).Content[0]
+}
+
+// verifyRecord reads a record back and sorts the fields sent to it by whether they hold the value sent
+func verifyRecord(client serviceNowClient, update recordChange) recordVerification {
+	verification := recordVerification{Table: update.Table, SysID: update.SysID, Number: update.Number, Applied: []string{}, NotApplied: []fieldMismatch{}}
+
+	names := make([]string, 0, len(update.fields))
+	for name := range update.fields {
+		if unverifiableFields[name] {
+			verification.NotChecked = append(verification.NotChecked, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.Strings(verification.NotChecked)
+	if len(names) == 0 {
+		return verification
+	}
+
+	result, err := client.Get(fmt.Sprintf("/table/%s/%s", update.Table, update.SysID), map[string]string{
+		"sysparm_fields":        strings.Join(append([]string{"number"}, names...), ","),
+		"sysparm_display_value": "all",
+	})
+	if err != nil {
+		verification.Error = err.Error()
+		return verification
+	}
+	record, _ := result["result"].(map[string]interface{})
+	if verification.Number == "" {
+		verification.Number = FieldDisplay(record["number"])
+	}
+
+	for _, name := range names {
+		requested := fmt.Sprint(update.fields[name])
+		if update.fields[name] == nil {
+			requested = ""
+		}
+		value, display := FieldValue(record[name]), FieldDisplay(record[name])
+		if sameFieldValue(requested, value) || sameFieldValue(requested, display) {
+			verification.Applied = append(verification.Applied, name)
+			continue
+		}
+		actual := display
+		if actual == "" {
+			actual = value
+		} else if value != display && value != "" {
+			actual = fmt.Sprintf("%s (%s)", value, display)
+		}
+		verification.NotApplied = append(verification.NotApplied, fieldMismatch{Field: name, Requested: requested, Actual: actual})
+	}
+	return verification
+}
+
+// sameFieldValue reports whether a value read back matches the value sent, ignoring case and surrounding space
+func sameFieldValue(requested, actual string) bool {
+	return strings.EqualFold(strings.TrimSpace(requested), strings.TrimSpace(actual))
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestVerifyUpdate tests that verify=true reads an updated record back and reports the fields a business rule overrode
+func TestVerifyUpdate(t *testing.T) {
+	const sysID = "a0000000000000000000000000000001"
+	field := func(value, display string) map[string]interface{} {
+		return map[string]interface{}{"value": value, "display_value": display}
+	}
+	readBack := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/now/table/incident/"+sysID:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{"sys_id": sysID, "number": "INC0010001"}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident/"+sysID:
+			readBack = true
+			if got := r.URL.Query().Get("sysparm_fields"); got != "number,description,short_description" {
+				t.Errorf("Expected the requested fields to be read back, got %s", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{
+				"number":            field("INC0010001", "INC0010001"),
+				"short_description": field("Printer offline", "Printer offline"),
+				"description":       field("Set by business rule", "Set by business rule"),
+			}})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	_, server := newTestRegistry(t, ts.URL, false)
+	updateIncident, _ := server.Handler("update_incident")
+
+	args := map[string]interface{}{"incident_id": sysID, "short_description": "Printer offline", "description": "Paper jam", "work_notes": "Checked the tray"}
+	result, _ := updateIncident(context.Background(), args)
+	if readBack || strings.Contains(result.Content[0].Text, "verification") {
+		t.Fatalf("Expected no read-back without verify, got %s", result.Content[0].Text)
+	}
+
+	args["verify"] = true
+	result, _ = updateIncident(context.Background(), args)
+	var response struct {
+		Success      bool `json:"success"`
+		Verification struct {
+			Records []recordVerification `json:"records"`
+		} `json:"verification"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if !response.Success || len(response.Verification.Records) != 1 {
+		t.Fatalf("Expected one verified record, got %s", result.Content[0].Text)
+	}
+	record := response.Verification.Records[0]
+	if len(record.Applied) != 1 || record.Applied[0] != "short_description" {
+		t.Errorf("Expected short_description to be applied, got %+v", record.Applied)
+	}
+	if len(record.NotApplied) != 1 || record.NotApplied[0].Field != "description" || record.NotApplied[0].Actual != "Set by business rule" {
+		t.Errorf("Expected description to be reported as overridden, got %+v", record.NotApplied)
+	}
+	if len(record.NotChecked) != 1 || record.NotChecked[0] != "work_notes" {
+		t.Errorf("Expected work notes to be left unchecked, got %+v", record.NotChecked)
+	}
+	if len(response.Warnings) != 1 || !strings.HasPrefix(response.Warnings[0], "INC0010001 was saved, but 1 requested field(s) were not applied") {
+		t.Errorf("Expected a warning about the overridden field, got %+v", response.Warnings)
+	}
+}
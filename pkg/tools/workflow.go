@@ -4,24 +4,25 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/elastiflow/go-mcp-servicenow/pkg/mcp"
+	"github.com/JeremyProffitt/go-mcp-servicenow/pkg/mcp"
 )
 
 // registerWorkflowTools registers all workflow management tools
 func (r *Registry) registerWorkflowTools(server *mcp.Server) int {
 	count := 0
 
-	// Helper for limit constraints
+	// Helper for limit/offset constraints
 	limitMin := float64(1)
 	limitMax := float64(1000)
+	offsetMin := float64(0)
 
 	// List Workflows
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "list_workflows",
 		Description: "List workflows with optional filtering by active status or table. Workflows automate business processes.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"limit": {
 					Type:        "number",
 					Description: "Maximum number of workflows to return (default: 50)",
@@ -29,6 +30,12 @@ func (r *Registry) registerWorkflowTools(server *mcp.Server) int {
 					Minimum:     &limitMin,
 					Maximum:     &limitMax,
 				},
+				"offset": {
+					Type:        "integer",
+					Description: "Pagination offset",
+					Default:     0,
+					Minimum:     &offsetMin,
+				},
 				"active": {
 					Type:        "boolean",
 					Description: "Filter by active status (true = only active workflows, false = only inactive)",
@@ -37,44 +44,40 @@ func (r *Registry) registerWorkflowTools(server *mcp.Server) int {
 					Type:        "string",
 					Description: "Filter by table name (e.g., 'incident', 'change_request', 'sc_req_item')",
 				},
-			},
+			}),
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "List Workflows",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.listWorkflows(args)
-	})
+	}, (*Registry).listWorkflows)
 	count++
 
 	// Get Workflow
-	server.RegisterTool(mcp.Tool{
+	r.registerTool(server, mcp.Tool{
 		Name:        "get_workflow",
 		Description: "Get detailed information about a specific workflow including configuration and activities.",
 		InputSchema: mcp.JSONSchema{
 			Type: "object",
-			Properties: map[string]mcp.Property{
+			Properties: withFieldSelection(map[string]mcp.Property{
 				"workflow_id": {
 					Type:        "string",
 					Description: "Workflow sys_id (e.g., 'a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6') or name. Accepts both formats.",
 				},
-			},
+			}),
 			Required: []string{"workflow_id"},
 		},
 		Annotations: &mcp.ToolAnnotation{
 			Title:        "Get Workflow",
 			ReadOnlyHint: true,
 		},
-	}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-		return r.getWorkflow(args)
-	})
+	}, (*Registry).getWorkflow)
 	count++
 
 	// Write operations
 	if !r.readOnlyMode {
 		// Create Workflow
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "create_workflow",
 			Description: "Create a new workflow definition. The workflow is created inactive by default.",
 			InputSchema: mcp.JSONSchema{
@@ -98,13 +101,11 @@ func (r *Registry) registerWorkflowTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Create Workflow",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.createWorkflow(args)
-		})
+		}, (*Registry).createWorkflow)
 		count++
 
 		// Update Workflow
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "update_workflow",
 			Description: "Update an existing workflow. At least one field besides workflow_id must be provided.",
 			InputSchema: mcp.JSONSchema{
@@ -132,13 +133,11 @@ func (r *Registry) registerWorkflowTools(server *mcp.Server) int {
 			Annotations: &mcp.ToolAnnotation{
 				Title: "Update Workflow",
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.updateWorkflow(args)
-		})
+		}, (*Registry).updateWorkflow)
 		count++
 
 		// Delete Workflow
-		server.RegisterTool(mcp.Tool{
+		r.registerTool(server, mcp.Tool{
 			Name:        "delete_workflow",
 			Description: "Permanently delete a workflow. This action cannot be undone.",
 			InputSchema: mcp.JSONSchema{
@@ -155,9 +154,7 @@ func (r *Registry) registerWorkflowTools(server *mcp.Server) int {
 				Title:           "Delete Workflow",
 				DestructiveHint: true,
 			},
-		}, func(args map[string]interface{}) (*mcp.CallToolResult, error) {
-			return r.deleteWorkflow(args)
-		})
+		}, (*Registry).deleteWorkflow)
 		count++
 	}
 
@@ -170,27 +167,25 @@ func (r *Registry) listWorkflows(args map[string]interface{}) (*mcp.CallToolResu
 
 	params := map[string]string{
 		"sysparm_limit":                  fmt.Sprintf("%d", limit),
+		"sysparm_offset":                 fmt.Sprintf("%d", GetIntArg(args, "offset", 0)),
+		"sysparm_fields":                 readFields(args, "sys_id,name,table,description,active"),
 		"sysparm_display_value":          "true",
 		"sysparm_exclude_reference_link": "true",
 	}
 
 	var filters []string
-	if active, exists := args["active"]; exists {
-		if active.(bool) {
-			filters = append(filters, "active=true")
-		} else {
-			filters = append(filters, "active=false")
-		}
+	if _, exists := args["active"]; exists {
+		filters = append(filters, fmt.Sprintf("active=%t", GetBoolArg(args, "active", false)))
 	}
 	if table != "" {
-		filters = append(filters, fmt.Sprintf("table=%s", table))
+		filters = append(filters, fmt.Sprintf("table=%s", SanitizeQueryValue(table)))
 	}
 
 	if len(filters) > 0 {
 		params["sysparm_query"] = strings.Join(filters, "^")
 	}
 
-	result, err := r.client.Get("/table/wf_workflow", params)
+	result, page, err := r.listRecords("/table/wf_workflow", params, args)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to list workflows", err)), nil
 	}
@@ -199,24 +194,27 @@ func (r *Registry) listWorkflows(args map[string]interface{}) (*mcp.CallToolResu
 	if resultList, ok := result["result"].([]interface{}); ok {
 		for _, item := range resultList {
 			if data, ok := item.(map[string]interface{}); ok {
-				workflows = append(workflows, map[string]interface{}{
+				workflows = append(workflows, selectFields(map[string]interface{}{
 					"sys_id":      data["sys_id"],
 					"name":        data["name"],
 					"table":       data["table"],
 					"description": data["description"],
 					"active":      data["active"],
-				})
+				}, data, args))
 			}
 		}
 	}
 
-	return JSONResult(map[string]interface{}{
+	return JSONResult(withPaging(map[string]interface{}{
 		"success":   true,
 		"message":   fmt.Sprintf("Found %d workflows", len(workflows)),
 		"workflows": workflows,
-	}), nil
+	}, page)), nil
 }
 
+// workflowFields are the fields of the record returned by get_workflow
+const workflowFields = "sys_id,name,table,description,active,template,access,sys_created_by,sys_updated_on"
+
 func (r *Registry) getWorkflow(args map[string]interface{}) (*mcp.CallToolResult, error) {
 	workflowID := GetStringArg(args, "workflow_id", "")
 	if workflowID == "" {
@@ -229,14 +227,16 @@ func (r *Registry) getWorkflow(args map[string]interface{}) (*mcp.CallToolResult
 	if IsSysID(workflowID) {
 		endpoint = fmt.Sprintf("/table/wf_workflow/%s", workflowID)
 		params = map[string]string{
+			"sysparm_fields":                 readFields(args, workflowFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
 	} else {
 		endpoint = "/table/wf_workflow"
 		params = map[string]string{
-			"sysparm_query":                  fmt.Sprintf("name=%s", workflowID),
+			"sysparm_query":                  fmt.Sprintf("name=%s", SanitizeQueryValue(workflowID)),
 			"sysparm_limit":                  "1",
+			"sysparm_fields":                 readFields(args, workflowFields),
 			"sysparm_display_value":          "true",
 			"sysparm_exclude_reference_link": "true",
 		}
@@ -257,16 +257,13 @@ func (r *Registry) getWorkflow(args map[string]interface{}) (*mcp.CallToolResult
 	}
 
 	if workflowData == nil {
-		return JSONResult(map[string]interface{}{
-			"success": false,
-			"message": fmt.Sprintf("Workflow not found: %s", workflowID),
-		}), nil
+		return JSONResult(NewNotFoundResponse(fmt.Sprintf("Workflow not found: %s", workflowID))), nil
 	}
 
 	return JSONResult(map[string]interface{}{
 		"success":  true,
 		"message":  "Workflow found",
-		"workflow": workflowData,
+		"workflow": selectFields(workflowData, workflowData, args),
 	}), nil
 }
 
@@ -355,13 +352,14 @@ func (r *Registry) deleteWorkflow(args map[string]interface{}) (*mcp.CallToolRes
 		return JSONResult(NewErrorResponse("workflow_id is required", nil)), nil
 	}
 
-	_, err := r.client.Delete(fmt.Sprintf("/table/wf_workflow/%s", workflowID))
+	recovery, err := r.deleteRecord("wf_workflow", workflowID)
 	if err != nil {
 		return JSONResult(NewErrorResponse("Failed to delete workflow", err)), nil
 	}
 
 	return JSONResult(map[string]interface{}{
-		"success": true,
-		"message": "Workflow deleted successfully",
+		"success":  true,
+		"message":  "Workflow deleted successfully",
+		"recovery": recovery,
 	}), nil
 }